@@ -0,0 +1,470 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/virtwork/internal/audit"
+	"github.com/opdev/virtwork/internal/config"
+)
+
+var _ = Describe("audit schema command", func() {
+	schema := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"audit", "schema"}, args...))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	It("should print the SQLite DDL by default", func() {
+		out, err := schema()
+		Expect(err).NotTo(HaveOccurred())
+		for _, table := range []string{"audit_log", "workload_details", "vm_details", "resource_details", "events"} {
+			Expect(out).To(ContainSubstring("CREATE TABLE IF NOT EXISTS " + table + " ("))
+		}
+		Expect(out).To(ContainSubstring("AUTOINCREMENT"))
+	})
+
+	It("should print the PostgreSQL DDL when an audit DSN is configured", func() {
+		out, err := schema("--audit-dsn", "postgres://virtwork@db.example.com/virtwork")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("CREATE TABLE IF NOT EXISTS audit_log ("))
+		Expect(out).To(ContainSubstring("SERIAL PRIMARY KEY"))
+		Expect(out).NotTo(ContainSubstring("AUTOINCREMENT"))
+	})
+
+	It("should print the dialect selected with --dialect", func() {
+		out, err := schema("--dialect", "postgres")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`linked_run_ids\s+JSONB`))
+	})
+
+	It("should reject an unknown dialect", func() {
+		_, err := schema("--dialect", "mysql")
+		Expect(err).To(MatchError(ContainSubstring(`unknown schema dialect "mysql"`)))
+	})
+})
+
+// syncBuffer is a bytes.Buffer safe to read while tailEvents writes to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+var _ = Describe("tailEvents", func() {
+	var (
+		ctx     context.Context
+		auditor *audit.SQLiteAuditor
+		execID  int64
+		runID   string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		auditor, err = audit.NewSQLiteAuditor(filepath.Join(GinkgoT().TempDir(), "audit.db"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(auditor.Close)
+		execID, runID, err = auditor.StartExecution(ctx, "run", &config.Config{Namespace: "test-ns"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should print the run's events and stop without --follow", func() {
+		Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{EventType: "execution_started", Message: "Planned 1 VMs"})).To(Succeed())
+		Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{EventType: "execution_failed", ErrorDetail: "boom"})).To(Succeed())
+
+		var out bytes.Buffer
+		Expect(tailEvents(ctx, auditor, runID, false, time.Millisecond, &out)).To(Succeed())
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(2))
+		Expect(string(lines[0])).To(MatchRegexp(`^\S+  execution_started\s+-\s+Planned 1 VMs$`))
+		Expect(string(lines[1])).To(HaveSuffix("(boom)"))
+	})
+
+	It("should say when the run has no events", func() {
+		var out bytes.Buffer
+		Expect(tailEvents(ctx, auditor, "no-such-run", false, time.Millisecond, &out)).To(Succeed())
+		Expect(out.String()).To(Equal("No events found for run no-such-run\n"))
+	})
+
+	It("should print new events once with --follow until cancelled", func() {
+		Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{EventType: "execution_started"})).To(Succeed())
+
+		followCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		out := &syncBuffer{}
+		done := make(chan error, 1)
+		go func() {
+			done <- tailEvents(followCtx, auditor, runID, true, 10*time.Millisecond, out)
+		}()
+
+		Eventually(out.String).Should(ContainSubstring("execution_started"))
+		Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{EventType: "vm_created", Message: "second"})).To(Succeed())
+		Eventually(out.String).Should(ContainSubstring("second"))
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(bytes.Count([]byte(out.String()), []byte("execution_started"))).To(Equal(1))
+	})
+})
+
+// failingAuditor fails to start executions, as a locked or unreachable audit
+// database would.
+type failingAuditor struct {
+	audit.NoOpAuditor
+}
+
+func (failingAuditor) StartExecution(_ context.Context, _ string, _ *config.Config) (int64, string, error) {
+	return 0, "", errors.New("database is locked")
+}
+
+var _ = Describe("startExecution", func() {
+	It("should continue unaudited with a generated run ID when the audit fails", func() {
+		var stderr bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetErr(&stderr)
+
+		auditor, execID, runID := startExecution(context.Background(), cmd, failingAuditor{}, "run", &config.Config{})
+		Expect(auditor).To(Equal(audit.NoOpAuditor{}))
+		Expect(execID).To(BeZero())
+		Expect(uuid.Validate(runID)).To(Succeed())
+		Expect(stderr.String()).To(ContainSubstring("Warning: starting audit execution: database is locked; continuing without auditing"))
+	})
+
+	It("should keep a working auditor", func() {
+		a, err := audit.NewSQLiteAuditor(filepath.Join(GinkgoT().TempDir(), "audit.db"))
+		Expect(err).NotTo(HaveOccurred())
+		defer a.Close()
+
+		auditor, execID, runID := startExecution(context.Background(), &cobra.Command{}, a, "run", &config.Config{})
+		Expect(auditor).To(BeIdenticalTo(a))
+		Expect(execID).NotTo(BeZero())
+		Expect(runID).NotTo(BeEmpty())
+	})
+})
+
+var _ = Describe("run audit totals", func() {
+	It("should record the planned VM and workload counts", func() {
+		dbPath := filepath.Join(GinkgoT().TempDir(), "audit.db")
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"run", "--dry-run", "--output", "json", "--audit-db", dbPath,
+			"--workloads", "cpu,network", "--vm-count", "2"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		a, err := audit.NewSQLiteAuditor(dbPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer a.Close()
+
+		// Each network server is paired with a client, doubling its VMs.
+		var vms, workloads int
+		Expect(a.DB().QueryRow(
+			`SELECT total_vm_count, total_workload_count FROM audit_log WHERE command = 'dry-run'`,
+		).Scan(&vms, &workloads)).To(Succeed())
+		Expect(vms).To(Equal(6))
+		Expect(workloads).To(Equal(2))
+	})
+})
+
+var _ = Describe("cleanup audit", func() {
+	// cleanupStatus runs cleanup with args against a fresh audit database and
+	// returns the status and error summary of the cleanup it recorded.
+	cleanupStatus := func(args ...string) (string, string) {
+		dbPath := filepath.Join(GinkgoT().TempDir(), "audit.db")
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"cleanup", "--audit-db", dbPath}, args...))
+		Expect(rootCmd.Execute()).NotTo(Succeed())
+
+		a, err := audit.NewSQLiteAuditor(dbPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer a.Close()
+		var status, summary string
+		Expect(a.DB().QueryRow(
+			`SELECT status, COALESCE(error_summary, '') FROM audit_log WHERE command = 'cleanup'`,
+		).Scan(&status, &summary)).To(Succeed())
+		return status, summary
+	}
+
+	It("should record a rejected cleanup-concurrency as failed", func() {
+		status, summary := cleanupStatus("--cleanup-concurrency", "0")
+		Expect(status).To(Equal("failed"))
+		Expect(summary).To(ContainSubstring("cleanup-concurrency must be at least 1"))
+	})
+
+	It("should record rejected orphan flags as failed", func() {
+		status, summary := cleanupStatus("--delete-orphans")
+		Expect(status).To(Equal("failed"))
+		Expect(summary).To(ContainSubstring("--delete-orphans requires --report-orphans"))
+
+		status, _ = cleanupStatus("--report-orphans", "--run-id", "run-a")
+		Expect(status).To(Equal("failed"))
+	})
+
+	It("should record a report-orphans run that cannot connect as failed", func() {
+		kubeconfig := filepath.Join(GinkgoT().TempDir(), "missing-kubeconfig")
+		status, summary := cleanupStatus("--report-orphans", "--kubeconfig", kubeconfig)
+		Expect(status).To(Equal("failed"))
+		Expect(summary).To(ContainSubstring("connecting to cluster"))
+	})
+
+	It("should record rejected verify flags as failed", func() {
+		status, summary := cleanupStatus("--verify-timeout", "-1s")
+		Expect(status).To(Equal("failed"))
+		Expect(summary).To(ContainSubstring("verify-timeout must not be negative"))
+
+		status, _ = cleanupStatus("--verify-cleanup-complete", "--report-orphans")
+		Expect(status).To(Equal("failed"))
+	})
+
+	It("should record a conflicting dry run as failed", func() {
+		status, summary := cleanupStatus("--dry-run", "--verify-cleanup-complete")
+		Expect(status).To(Equal("failed"))
+		Expect(summary).To(ContainSubstring("--dry-run cannot be combined"))
+	})
+
+	It("should record rejected older-than flags as failed", func() {
+		status, summary := cleanupStatus("--older-than", "-1h")
+		Expect(status).To(Equal("failed"))
+		Expect(summary).To(ContainSubstring("older-than must not be negative"))
+
+		status, _ = cleanupStatus("--older-than", "1h", "--delete-namespace")
+		Expect(status).To(Equal("failed"))
+	})
+
+	It("should record a dry run that cannot connect as failed", func() {
+		kubeconfig := filepath.Join(GinkgoT().TempDir(), "missing-kubeconfig")
+		status, summary := cleanupStatus("--dry-run", "--kubeconfig", kubeconfig)
+		Expect(status).To(Equal("failed"))
+		Expect(summary).To(ContainSubstring("connecting to cluster"))
+	})
+})
+
+var _ = Describe("audit export command", func() {
+	var (
+		dir    string
+		dbPath string
+	)
+
+	execute := func(args ...string) (string, error) {
+		rootCmd := newRootCmd()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(args)
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	readCSV := func(path string) [][]string {
+		f, err := os.Open(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+		records, err := csv.NewReader(f).ReadAll()
+		Expect(err).NotTo(HaveOccurred())
+		return records
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		dbPath = filepath.Join(dir, "audit.db")
+		a, err := audit.NewSQLiteAuditor(dbPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer a.Close()
+
+		ctx := context.Background()
+		for i := range 2 {
+			execID, _, err := a.StartExecution(ctx, "run", &config.Config{Namespace: "virtwork"})
+			Expect(err).NotTo(HaveOccurred())
+			wlID, err := a.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+				WorkloadType: "cpu", Enabled: true, VMCount: 2, CPUCores: 1, Memory: "1Gi",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			for j := range 2 {
+				_, err := a.RecordVM(ctx, execID, wlID, audit.VMRecord{
+					VMName: fmt.Sprintf("virtwork-cpu-%d-%d", i, j), Namespace: "virtwork",
+					Component: "cpu", CPUCores: 1, Memory: "1Gi", ContainerDiskImage: "img",
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+	})
+
+	It("should write audit_log with a header to --out", func() {
+		out := filepath.Join(dir, "runs.csv")
+		_, err := execute("audit", "export", "--audit-db", dbPath, "--out", out)
+		Expect(err).NotTo(HaveOccurred())
+
+		records := readCSV(out)
+		Expect(records).To(HaveLen(3))
+		Expect(records[0]).To(ContainElements("run_id", "command", "started_at"))
+		Expect(filepath.Join(dir, "runs-vms.csv")).NotTo(BeAnExistingFile())
+	})
+
+	It("should write to stdout without --out", func() {
+		stdout, err := execute("audit", "export", "--audit-db", dbPath)
+		Expect(err).NotTo(HaveOccurred())
+		records, err := csv.NewReader(bytes.NewBufferString(stdout)).ReadAll()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(3))
+	})
+
+	It("should write vm_details next to --out with --include-vms", func() {
+		out := filepath.Join(dir, "runs.csv")
+		_, err := execute("audit", "export", "--audit-db", dbPath, "--out", out, "--include-vms")
+		Expect(err).NotTo(HaveOccurred())
+
+		records := readCSV(filepath.Join(dir, "runs-vms.csv"))
+		Expect(records).To(HaveLen(5))
+		Expect(records[0]).To(ContainElements("audit_id", "vm_name"))
+	})
+
+	It("should filter executions with --since", func() {
+		stdout, err := execute("audit", "export", "--audit-db", dbPath, "--since", "2999-01-01")
+		Expect(err).NotTo(HaveOccurred())
+		records, err := csv.NewReader(bytes.NewBufferString(stdout)).ReadAll()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+	})
+
+	It("should reject invalid flags", func() {
+		_, err := execute("audit", "export", "--audit-db", dbPath, "--format", "json")
+		Expect(err).To(MatchError(ContainSubstring(`invalid --format "json"`)))
+
+		_, err = execute("audit", "export", "--audit-db", dbPath, "--include-vms")
+		Expect(err).To(MatchError(ContainSubstring("requires --out")))
+
+		_, err = execute("audit", "export", "--audit-db", dbPath, "--since", "yesterday")
+		Expect(err).To(MatchError(ContainSubstring(`invalid --since "yesterday"`)))
+	})
+})
+
+var _ = Describe("parseSince", func() {
+	It("should normalize dates and times to UTC RFC 3339", func() {
+		Expect(parseSince("")).To(BeEmpty())
+		Expect(parseSince("2026-03-01")).To(Equal("2026-03-01T00:00:00Z"))
+		Expect(parseSince("2026-03-01T12:00:00+02:00")).To(Equal("2026-03-01T10:00:00Z"))
+	})
+
+	It("should resolve an age in days or a duration before now", func() {
+		for s, age := range map[string]time.Duration{"7d": 7 * 24 * time.Hour, "12h": 12 * time.Hour} {
+			since, err := parseSince(s)
+			Expect(err).NotTo(HaveOccurred())
+			t, err := time.Parse(time.RFC3339, since)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(BeTemporally("~", time.Now().Add(-age), 2*time.Second))
+		}
+	})
+
+	It("should reject malformed and non-positive ages", func() {
+		for _, s := range []string{"0d", "-1d", "xd", "-12h", "yesterday"} {
+			_, err := parseSince(s)
+			Expect(err).To(MatchError(ContainSubstring("invalid --since")), s)
+		}
+	})
+})
+
+var _ = Describe("audit stats command", func() {
+	var dbPath string
+
+	execute := func(args ...string) (string, error) {
+		rootCmd := newRootCmd()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"audit", "stats", "--audit-db", dbPath}, args...))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	BeforeEach(func() {
+		dbPath = filepath.Join(GinkgoT().TempDir(), "audit.db")
+		a, err := audit.NewSQLiteAuditor(dbPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer a.Close()
+
+		ctx := context.Background()
+		for i, status := range []string{"success", "failed"} {
+			execID, _, err := a.StartExecution(ctx, "run", &config.Config{Namespace: "virtwork"})
+			Expect(err).NotTo(HaveOccurred())
+			wlID, err := a.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+				WorkloadType: "cpu", Enabled: true, VMCount: 1, CPUCores: 1, Memory: "1Gi",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = a.RecordVM(ctx, execID, wlID, audit.VMRecord{
+				VMName: fmt.Sprintf("virtwork-cpu-%d", i), Namespace: "virtwork",
+				Component: "cpu", CPUCores: 1, Memory: "1Gi", ContainerDiskImage: "img",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a.CompleteExecution(ctx, execID, status, "")).To(Succeed())
+		}
+	})
+
+	It("should print the totals and a workload table", func() {
+		out, err := execute()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`Executions:\s+2 \(failed 1, success 1\)`))
+		Expect(out).To(MatchRegexp(`VMs created:\s+2`))
+		Expect(out).To(MatchRegexp(`WORKLOAD\s+EXECUTIONS\s+SUCCEEDED\s+FAILED\s+VMS`))
+		Expect(out).To(MatchRegexp(`cpu\s+2\s+1\s+1\s+2`))
+	})
+
+	It("should print JSON with --format json", func() {
+		out, err := execute("--format", "json", "--since", "7d")
+		Expect(err).NotTo(HaveOccurred())
+		var stats audit.Stats
+		Expect(json.Unmarshal([]byte(out), &stats)).To(Succeed())
+		Expect(stats.Executions).To(Equal(2))
+		Expect(stats.ByStatus).To(HaveKeyWithValue("failed", 1))
+		Expect(stats.Workloads).To(HaveLen(1))
+	})
+
+	It("should print zeroed stats without an audit database", func() {
+		dbPath = filepath.Join(GinkgoT().TempDir(), "missing.db")
+		out, err := execute()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`Executions:\s+0\n`))
+		Expect(dbPath).NotTo(BeAnExistingFile())
+	})
+
+	It("should reject invalid flags", func() {
+		_, err := execute("--format", "csv")
+		Expect(err).To(MatchError(ContainSubstring(`invalid --format "csv"`)))
+
+		_, err = execute("--since", "last week")
+		Expect(err).To(MatchError(ContainSubstring(`invalid --since "last week"`)))
+	})
+})
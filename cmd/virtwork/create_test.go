@@ -0,0 +1,119 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+var _ = Describe("createVMs", func() {
+	const namespace = "test-ns"
+	ctx := context.Background()
+
+	plans := func(n int) []vmPlan {
+		var out []vmPlan
+		for i := range n {
+			name := fmt.Sprintf("virtwork-cpu-%d", i)
+			out = append(out, vmPlan{
+				vmName:    name,
+				component: "cpu",
+				vmSpec: &vm.VMSpecOpts{
+					Name:               name,
+					Namespace:          namespace,
+					ContainerDiskImage: "test-image",
+					CloudInitUserdata:  "#cloud-config\n",
+					CPUCores:           1,
+					Memory:             "1Gi",
+				},
+			})
+		}
+		return out
+	}
+
+	// trackInFlight returns a client whose VM creates take a moment, and a
+	// function reporting the most creates that were ever in flight at once.
+	trackInFlight := func(fail error) (client.Client, func() int) {
+		var mu sync.Mutex
+		var inFlight, peak int
+		c := fake.NewClientBuilder().
+			WithScheme(cluster.NewScheme()).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					mu.Lock()
+					inFlight++
+					peak = max(peak, inFlight)
+					mu.Unlock()
+					time.Sleep(10 * time.Millisecond)
+					mu.Lock()
+					inFlight--
+					mu.Unlock()
+					if fail != nil {
+						return fail
+					}
+					return cl.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+		return c, func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return peak
+		}
+	}
+
+	It("should keep at most create-concurrency creates in flight", func() {
+		c, peak := trackInFlight(nil)
+		cfg := &config.Config{Namespace: namespace, CreateConcurrency: 3, CreateBackoff: time.Millisecond}
+
+		var mu sync.Mutex
+		var created []string
+		err := createVMs(ctx, c, cfg, plans(20), false, func(p vmPlan, _ bool, err error) {
+			Expect(err).NotTo(HaveOccurred())
+			mu.Lock()
+			defer mu.Unlock()
+			created = append(created, p.vmName)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(HaveLen(20))
+		Expect(peak()).To(BeNumerically(">", 1))
+		Expect(peak()).To(BeNumerically("<=", 3))
+
+		var list kubevirtv1.VirtualMachineList
+		Expect(c.List(ctx, &list, client.InNamespace(namespace))).To(Succeed())
+		Expect(list.Items).To(HaveLen(20))
+	})
+
+	It("should create all plans at once when the limit covers them", func() {
+		c, peak := trackInFlight(nil)
+		cfg := &config.Config{Namespace: namespace, CreateConcurrency: 10, CreateBackoff: time.Millisecond}
+
+		Expect(createVMs(ctx, c, cfg, plans(4), false, func(vmPlan, bool, error) {})).To(Succeed())
+		Expect(peak()).To(Equal(4))
+	})
+
+	It("should stop starting creates after the first failure", func() {
+		c, _ := trackInFlight(errors.New("admission denied"))
+		cfg := &config.Config{Namespace: namespace, CreateConcurrency: 1, CreateBackoff: time.Millisecond}
+
+		attempts := 0
+		err := createVMs(ctx, c, cfg, plans(5), false, func(vmPlan, bool, error) { attempts++ })
+		Expect(err).To(MatchError(ContainSubstring("admission denied")))
+		Expect(attempts).To(Equal(1))
+	})
+})
@@ -0,0 +1,65 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("describe command", func() {
+	describe := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"describe"}, args...))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	It("should print the database workload's setup script and data disk", func() {
+		out, err := describe("--workload", "database")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Workload:  database\n"))
+		Expect(out).To(ContainSubstring("# Data volume templates\n"))
+		Expect(out).To(ContainSubstring("name: virtwork-database-data"))
+		Expect(out).To(ContainSubstring("# Service: none\n"))
+		Expect(out).To(ContainSubstring("# Cloud-init userdata\n#cloud-config\n"))
+		Expect(out).To(ContainSubstring("path: /usr/local/bin/virtwork-db-setup.sh"))
+		Expect(out).To(ContainSubstring("pgbench -i"))
+	})
+
+	It("should print only the requested role of a multi-VM workload", func() {
+		out, err := describe("--workload", "network", "--role", "server")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("# Cloud-init userdata (role server)\n"))
+		Expect(out).NotTo(ContainSubstring("role client"))
+		Expect(out).To(ContainSubstring("name: virtwork-iperf3-server"))
+	})
+
+	It("should print every role when none is given", func() {
+		out, err := describe("--workload", "network")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("# Cloud-init userdata (role server)\n"))
+		Expect(out).To(ContainSubstring("# Cloud-init userdata (role client)\n"))
+	})
+
+	It("should reject an unknown role", func() {
+		_, err := describe("--workload", "network", "--role", "proxy")
+		Expect(err).To(MatchError(`workload "network" has no role "proxy"; roles: server, client`))
+	})
+
+	It("should reject a role for a single-VM workload", func() {
+		_, err := describe("--workload", "cpu", "--role", "server")
+		Expect(err).To(MatchError(ContainSubstring("--role only applies to multi-VM workloads")))
+	})
+
+	It("should require --workload", func() {
+		_, err := describe()
+		Expect(err).To(MatchError(ContainSubstring(`required flag(s) "workload" not set`)))
+	})
+})
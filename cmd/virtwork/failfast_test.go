@@ -0,0 +1,58 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/vm"
+	"github.com/opdev/virtwork/internal/wait"
+)
+
+var _ = Describe("run --fail-fast", func() {
+	plan := func(name, namespace string) vmPlan {
+		return vmPlan{vmName: name, vmSpec: &vm.VMSpecOpts{Name: name, Namespace: namespace}}
+	}
+	vmi := func(name, namespace string, phase kubevirtv1.VirtualMachineInstancePhase) *kubevirtv1.VirtualMachineInstance {
+		return &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: phase},
+		}
+	}
+
+	It("should stop waiting in every namespace once one VM fails", func() {
+		c := fake.NewClientBuilder().WithScheme(cluster.NewScheme()).WithObjects(
+			vmi("virtwork-cpu-0", "virtwork-cpu", kubevirtv1.Failed),
+			vmi("virtwork-memory-0", "virtwork-memory", kubevirtv1.Pending),
+		).Build()
+		cfg := &config.Config{ReadinessLevel: constants.ReadinessPhase}
+		plans := []vmPlan{plan("virtwork-cpu-0", "virtwork-cpu"), plan("virtwork-memory-0", "virtwork-memory")}
+
+		start := time.Now()
+		results := waitForPlans(context.Background(), c, cfg, plans, 10*time.Second, 10*time.Millisecond, true, nil, nil)
+		Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+		Expect(results["virtwork-cpu-0"].Err).To(MatchError(wait.ErrVMFailed))
+		Expect(results["virtwork-memory-0"].Err).To(MatchError(context.Canceled))
+	})
+
+	It("should reject --fail-fast with --recreate-failed", func() {
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"run", "--no-audit", "--fail-fast", "--recreate-failed"})
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring("cannot be combined with --recreate-failed")))
+	})
+})
@@ -0,0 +1,81 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/audit"
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/hook"
+)
+
+var _ = Describe("runOnReadyHook", func() {
+	const namespace = "test-ns"
+	ctx := context.Background()
+
+	var (
+		runCmd           *cobra.Command
+		c                client.Client
+		progress, stderr bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		runCmd, _, _ = newRootCmd().Find([]string{"run"})
+		progress.Reset()
+		stderr.Reset()
+		runCmd.SetErr(&stderr)
+		c = fake.NewClientBuilder().WithScheme(cluster.NewScheme()).WithObjects(
+			&kubevirtv1.VirtualMachineInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "virtwork-cpu-0", Namespace: namespace},
+				Status: kubevirtv1.VirtualMachineInstanceStatus{
+					Interfaces: []kubevirtv1.VirtualMachineInstanceNetworkInterface{{Name: "default", IP: "10.128.0.11"}},
+				},
+			},
+		).Build()
+	})
+
+	run := func(command string, vmNames ...string) error {
+		return runOnReadyHook(ctx, runCmd, c, audit.NoOpAuditor{}, 0, command,
+			hook.Context{RunID: "run-a", Namespace: namespace}, vmNames, nil, textLogger(&progress), &progress)
+	}
+
+	It("should run the command with the run's IDs and VM IPs", func() {
+		Expect(run(`echo "$VIRTWORK_RUN_ID $VIRTWORK_NAMESPACE $VIRTWORK_VM_IPS"`, "virtwork-cpu-0")).To(Succeed())
+		Expect(progress.String()).To(ContainSubstring("run-a test-ns virtwork-cpu-0=10.128.0.11\n"))
+	})
+
+	It("should pass an empty IP for a VM whose VMI cannot be read", func() {
+		Expect(run(`echo "$VIRTWORK_VM_IPS"`, "virtwork-cpu-0", "virtwork-cpu-1")).To(Succeed())
+		Expect(progress.String()).To(ContainSubstring("virtwork-cpu-0=10.128.0.11,virtwork-cpu-1=\n"))
+		Expect(stderr.String()).To(ContainSubstring("Warning: looking up the IP of VM virtwork-cpu-1"))
+	})
+
+	It("should fail the run when the command fails", func() {
+		Expect(run("exit 1", "virtwork-cpu-0")).To(MatchError(ContainSubstring("on-ready hook")))
+	})
+
+	It("should reject --on-ready-exec without the readiness wait", func() {
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"run", "--no-audit", "--no-wait", "--on-ready-exec", "true"})
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring("cannot be combined with --no-wait or --start-stopped")))
+	})
+
+	It("should only warn with --on-ready-exec-allow-failure", func() {
+		Expect(runCmd.Flags().Set("on-ready-exec-allow-failure", "true")).To(Succeed())
+		Expect(run("exit 1", "virtwork-cpu-0")).To(Succeed())
+		Expect(stderr.String()).To(ContainSubstring("Warning: on-ready hook"))
+	})
+})
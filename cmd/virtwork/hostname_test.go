@@ -0,0 +1,42 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+var _ = Describe("singleVMSpec", func() {
+	hostname := func(userdata string) interface{} {
+		var parsed map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(userdata), &parsed)).To(Succeed())
+		return parsed["hostname"]
+	}
+
+	It("should give each VM of a workload its own hostname", func() {
+		cfg := &config.Config{Namespace: "virtwork"}
+		w, err := workloads.DefaultRegistry().Get("cpu", config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  2,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		userdata, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		first := singleVMSpec(cfg, w, "cpu", "virtwork-cpu-0", "run-1", userdata)
+		second := singleVMSpec(cfg, w, "cpu", "virtwork-cpu-1", "run-1", userdata)
+
+		Expect(hostname(first.CloudInitUserdata)).To(Equal("virtwork-cpu-0"))
+		Expect(hostname(second.CloudInitUserdata)).To(Equal("virtwork-cpu-1"))
+		Expect(first.CloudInitUserdata).To(HavePrefix("#cloud-config\n"))
+		Expect(first.CloudInitUserdata).To(ContainSubstring("stress-ng"))
+	})
+})
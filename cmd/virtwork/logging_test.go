@@ -0,0 +1,32 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/logging"
+)
+
+// textLogger returns a logger writing plain progress lines to w.
+func textLogger(w io.Writer) *slog.Logger {
+	logger, err := logging.New(w, logging.FormatText, false)
+	Expect(err).NotTo(HaveOccurred())
+	return logger
+}
+
+var _ = Describe("run --log-format", func() {
+	It("should reject an unknown format", func() {
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"run", "--dry-run", "--no-audit", "--log-format", "xml"})
+		Expect(rootCmd.Execute()).To(MatchError(`invalid log format "xml": must be text or json`))
+	})
+})
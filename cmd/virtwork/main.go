@@ -5,21 +5,48 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/api/resource"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	sigyaml "sigs.k8s.io/yaml"
 
 	"github.com/opdev/virtwork/internal/audit"
 	"github.com/opdev/virtwork/internal/cleanup"
+	"github.com/opdev/virtwork/internal/cloudinit"
 	"github.com/opdev/virtwork/internal/cluster"
 	"github.com/opdev/virtwork/internal/config"
 	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/discover"
+	"github.com/opdev/virtwork/internal/hook"
+	"github.com/opdev/virtwork/internal/logging"
+	"github.com/opdev/virtwork/internal/metrics"
+	"github.com/opdev/virtwork/internal/migrate"
+	"github.com/opdev/virtwork/internal/progressbar"
 	"github.com/opdev/virtwork/internal/resources"
+	"github.com/opdev/virtwork/internal/scale"
+	"github.com/opdev/virtwork/internal/status"
+	"github.com/opdev/virtwork/internal/table"
 	"github.com/opdev/virtwork/internal/vm"
 	"github.com/opdev/virtwork/internal/wait"
 	"github.com/opdev/virtwork/internal/workloads"
@@ -39,18 +66,28 @@ func newRootCmd() *cobra.Command {
 Virtualization installed) and runs continuous workloads inside them to produce
 realistic CPU, memory, database, network, and disk I/O metrics.`,
 		SilenceUsage: true,
+		RunE:         rootE,
 	}
 
 	pf := rootCmd.PersistentFlags()
 	pf.String("namespace", "", "Kubernetes namespace for VMs")
 	pf.String("kubeconfig", "", "Path to kubeconfig file")
+	pf.String("context", "", "Kubeconfig context to use (alias: --kube-context)")
 	pf.String("config", "", "Path to YAML config file")
 	pf.Bool("verbose", false, "Enable verbose output")
+	pf.String("output", "table", "Output format of the run summary and dry-run listing (table, json)")
+	pf.String("log-format", "text", "Format of run progress logs (text, json); json logs go to stderr")
 	pf.Bool("audit", true, "Enable audit logging to SQLite")
 	pf.Bool("no-audit", false, "Disable audit logging")
 	pf.String("audit-db", "", "Path to audit database file")
+	pf.String("audit-dsn", "", "PostgreSQL DSN for a shared audit database (overrides --audit-db)")
+	pf.Bool("audit-db-per-context", false, "Keep a separate audit database per kubeconfig context under ~/.virtwork")
 
-	rootCmd.AddCommand(newRunCmd(), newCleanupCmd())
+	rootCmd.SetGlobalNormalizationFunc(config.NormalizeFlagName)
+
+	rootCmd.Flags().Bool("list-contexts", false, "List the contexts in the kubeconfig and exit")
+
+	rootCmd.AddCommand(newRunCmd(), newCleanupCmd(), newStatusCmd(), newListCmd(), newScaleCmd(), newMigrateCmd(), newStartCmd(), newDescribeCmd(), newPreflightCmd(), newAuditCmd())
 	return rootCmd
 }
 
@@ -65,19 +102,83 @@ via systemd.`,
 	}
 
 	f := cmd.Flags()
-	f.StringSlice("workloads", workloads.AllWorkloadNames, "Workloads to deploy (comma-separated)")
+	f.StringSlice("workloads", workloads.AllWorkloadNames, "Workloads to deploy (comma-separated, or all)")
 	f.Int("vm-count", 1, "Number of VMs per workload")
 	f.Int("cpu-cores", 0, "CPU cores per VM")
 	f.String("memory", "", "Memory per VM (e.g., 2Gi)")
 	f.String("disk-size", "", "Data disk size")
 	f.String("container-disk-image", "", "Container disk image for VMs")
+	f.String("image-pull-secret", "", "Secret in the namespace used to pull container disk images from a private registry")
 	f.Bool("dry-run", false, "Print specs without creating resources")
+	f.Bool("no-redact", false, "Show passwords and SSH keys in dry-run output")
 	f.Bool("no-wait", false, "Skip waiting for VM readiness")
 	f.Int("timeout", 0, "Readiness timeout in seconds")
+	f.String("readiness-level", "", "When a VM counts as ready: phase (default), agent, or ready")
 	f.String("ssh-user", "", "SSH user for VMs")
 	f.String("ssh-password", "", "SSH password for VMs")
 	f.StringSlice("ssh-key", nil, "SSH authorized key (repeatable)")
 	f.StringSlice("ssh-key-file", nil, "SSH key file path (repeatable)")
+	f.Duration("warmup", 0, "Warmup period before each workload's measured loop (e.g., 5m)")
+	f.Duration("stagger", 0, "Spread workload start times across each workload's VMs over this window (e.g., 2m)")
+	f.Duration("deadline", 0, "Abort the run if connecting, creating, and waiting take longer than this (e.g., 30m; 0 = no deadline)")
+	f.Bool("skip-connect-check", false, "Skip pinging the API server before creating resources")
+	f.Bool("skip-preflight", false, "Skip checking that the KubeVirt and CDI CRDs are installed before creating resources")
+	f.Bool("check-quota", false, "Fail before creating resources if the planned VM requests exceed a ResourceQuota in the namespace")
+	f.Int("create-concurrency", constants.DefaultCreateConcurrency, "Maximum VM creates in flight at once")
+	f.Int("create-retries", constants.DefaultCreateRetries, "Retries of a VM create after transient API errors")
+	f.Duration("create-backoff", constants.DefaultCreateBackoff, "Wait before the first VM create retry, doubling per retry up to 30s")
+	f.String("template-values", "", "YAML file of values substituted into custom workload templates")
+	f.String("seed-sql", "", "SQL file applied to the database workload before benchmarking")
+	f.String("clock-timezone", "", "Guest clock: UTC or an IANA timezone (e.g., America/New_York)")
+	f.StringToString("timers", nil, "Guest timers to enable or disable (e.g., hpet=false,kvm=true)")
+	f.Bool("compress-cloud-init", false, "Gzip cloud-init userdata to reduce its size")
+	f.Bool("workload-probes", false, "Gate disk, database, fs, network, redis, and web VM readiness on the workload service running")
+	f.Int("clients-per-server", 1, "Network workload client VMs per iperf3 server")
+	f.String("cpu-model", "", "Guest CPU model (e.g., host-passthrough)")
+	f.Bool("dedicated-cpu", false, "Pin each vCPU to a dedicated host CPU")
+	f.Int("cpu-sockets", 0, "Guest CPU sockets (0 uses the KubeVirt default)")
+	f.Int("cpu-threads", 0, "Guest CPU threads per core (0 uses the KubeVirt default)")
+	f.String("hugepages", "", "Back guest memory with hugepages of this size: 2Mi or 1Gi")
+	f.String("memory-limit", "", "Memory limit per VM (e.g., 4Gi); at least --memory")
+	f.String("cpu-limit", "", "CPU limit per VM (e.g., 2 or 1500m)")
+	f.Bool("overcommit-guest-overhead", false, "Leave the virt-launcher memory overhead out of each VM's memory request")
+	f.Bool("read-only-root", false, "Mount each guest's root filesystem read-only under an ephemeral overlay")
+	f.Bool("network-policy", false, "Allow ingress to network workload servers only from their clients")
+	f.Bool("namespace-per-workload", false, "Deploy each workload into its own namespace, <namespace>-<workload>")
+	f.String("cloud-init-overlay", "", "Cloud-config YAML file merged into every workload's cloud-init")
+	f.Bool("overlay-override", false, "Let --cloud-init-overlay values replace the workload's where both set one")
+	f.String("custom-userdata", "", "Cloud-config or script file run by the \"custom\" workload")
+	f.StringToString("node-selector", nil, "Node label the VMs must be scheduled on (key=value, repeatable)")
+	f.StringArray("toleration", nil, "Taint the VMs tolerate: key[=value][:effect] (repeatable)")
+	f.StringArray("gpu", nil, "GPU device resource to pass through to each VM, e.g. nvidia.com/GA102GL_A10 (repeatable)")
+	f.StringArray("multus-network", nil, "NetworkAttachmentDefinition ([namespace/]name) each VM gets a second NIC on (repeatable)")
+	f.String("affinity-from-file", "", "YAML file holding a Kubernetes affinity for the VMs")
+	f.String("anti-affinity-weight", "", "Keep each workload's VMs on separate nodes: required, preferred, or a preferred weight 1-100")
+	f.Lookup("anti-affinity-weight").NoOptDefVal = "preferred"
+	f.String("data-source-url", "", "Registry image to populate disk and database data disks from (e.g., docker://quay.io/org/img)")
+	f.String("data-source-pvc", "", "PVC ([namespace/]name) to clone disk and database data disks from")
+	f.String("data-fs", "", "Share a PVC with disk and database VMs over virtiofs instead of a data disk: pvc=<name>")
+	f.String("storage-class", "", "StorageClass for disk, database, and fs data disks (default: cluster default)")
+	f.String("access-mode", "", "Access mode for data disks: ReadWriteOnce, ReadWriteMany, ReadOnlyMany, or ReadWriteOncePod")
+	f.String("syslog-server", "", "Forward guest logs to this syslog server over TCP (host:port)")
+	f.String("block-size", "", "Data disk block size: logical=N,physical=N or match-volume")
+	f.String("metrics-file", "", "Write Prometheus text-format metrics for the run to this file")
+	f.String("ready-report-file", "", "Write a JSON report of each VM's readiness outcome to this file")
+	f.Bool("recreate-failed", false, "Delete and recreate, once, each VM whose VMI fails before becoming ready")
+	f.Bool("fail-fast", false, "Stop waiting for the other VMs as soon as one fails its readiness wait")
+	f.Bool("verify-ssh", false, "After the readiness wait, check that each VM's SSH port answers")
+	f.String("on-ready-exec", "", "Shell command to run locally once all VMs are ready; gets VIRTWORK_RUN_ID, VIRTWORK_NAMESPACE, and VIRTWORK_VM_IPS")
+	f.Bool("on-ready-exec-allow-failure", false, "Only warn, rather than fail the run, when the --on-ready-exec command fails")
+	f.Bool("progress", false, "Show a single updating line of VM creation and readiness progress on a terminal")
+	f.String("reuse-run-id", "", "Re-run into an existing run (UUID), creating only its missing VMs")
+	f.Bool("update", false, "Update the memory, CPU, resources, and labels of VMs that already exist instead of leaving them")
+	f.Bool("label-run-with-git", false, "Annotate created resources with the git SHA, branch, and build URL detected from CI env vars")
+	f.String("git-sha", "", "Git SHA to annotate created resources with (overrides the detected one)")
+	f.String("git-branch", "", "Git branch to annotate created resources with (overrides the detected one)")
+	f.String("ci-url", "", "CI build URL to annotate created resources with (overrides the detected one)")
+	f.Bool("start-stopped", false, "Create the VMs stopped; boot them later with \"virtwork start\"")
+	f.String("config-dump", "", "Print the resolved configuration (yaml or json) and exit")
+	f.Lookup("config-dump").NoOptDefVal = "yaml"
 
 	return cmd
 }
@@ -86,15 +187,266 @@ func newCleanupCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "cleanup",
 		Short: "Delete all managed resources",
-		Long:  `Delete all VMs, services, secrets, and optionally the namespace created by virtwork.`,
-		RunE:  cleanupE,
+		Long: `Delete all VMs, services, secrets, and optionally the namespace created by virtwork.
+With --dry-run, list what would be deleted instead.`,
+		RunE: cleanupE,
 	}
 
 	cmd.Flags().Bool("delete-namespace", false, "Also delete the namespace")
 	cmd.Flags().String("run-id", "", "Only delete resources from this specific run (UUID)")
+	cmd.Flags().Int64("grace-period", -1, "Seconds to wait before force-deleting VMs and secrets (-1 uses the server default)")
+	cmd.Flags().String("propagation", "", "Deletion propagation for VMs and secrets: Foreground, Background, or Orphan")
+	cmd.Flags().Bool("force", false, "Force-delete VMs and secrets: no grace period and background propagation")
+	cmd.Flags().Int("cleanup-concurrency", constants.DefaultCleanupConcurrency, "Maximum deletions of each resource kind in flight at once")
+	cmd.Flags().StringToString("selector", nil, "Only delete resources that also carry this label: key=value (repeatable)")
+	cmd.Flags().Duration("older-than", 0, "Only delete resources created longer ago than this (e.g., 24h; 0 = any age)")
+	cmd.Flags().Bool("report-orphans", false, "Report cloud-init secrets without a VM and services without a backing VM instead of cleaning up")
+	cmd.Flags().Bool("delete-orphans", false, "Delete the orphans found by --report-orphans")
+	cmd.Flags().Bool("verify-cleanup-complete", false, "Exit non-zero if any managed resource still exists after deletion")
+	cmd.Flags().Duration("verify-timeout", constants.DefaultVerifyCleanupTimeout, "How long --verify-cleanup-complete waits for deleted resources to disappear")
+	cmd.Flags().Bool("dry-run", false, "List the resources that would be deleted without deleting anything")
+	cmd.Flags().Bool("namespace-per-workload", false, "Clean up the <namespace>-<workload> namespaces of runs made with --namespace-per-workload")
+	return cmd
+}
+
+func newStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the current phase of managed VMs",
+		Long: `List the VMs created by virtwork with their workload, role, and current
+phase. When auditing is enabled, the recorded creation and readiness times are
+shown as well. Exits non-zero if any VM is in the Failed phase.`,
+		RunE: statusE,
+	}
+
+	cmd.Flags().String("run-id", "", "Only show VMs from this specific run (UUID)")
+	cmd.Flags().StringSlice("columns", nil, "Columns to show, comma-separated: name, component, role, phase, vm-ready, agent, node, message, created, ready")
+	cmd.Flags().Bool("namespace-per-workload", false, "Show the VMs in the <namespace>-<workload> namespaces of runs made with --namespace-per-workload")
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the runs deployed on the cluster",
+		Long: `List the runs that have managed VMs, services, or secrets on the cluster,
+grouped by their virtwork/run-id label, with each run's VM count, workloads,
+and creation time. Only the cluster is read, not the audit database.`,
+		RunE: listE,
+	}
+
+	cmd.Flags().BoolP("all-namespaces", "A", false, "List runs in all namespaces")
+	cmd.Flags().StringSlice("columns", nil, "Columns to show, comma-separated: namespace, run-id, vms, services, secrets, components, created")
+	return cmd
+}
+
+func newScaleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scale",
+		Short: "Add or remove VMs for an existing run",
+		Long: `Change the number of VMs of one workload in an existing run. New VMs
+continue the virtwork-<workload>-<index> numbering and are built from the
+current configuration, so pass the same config file and settings as the
+original run. When scaling down, the highest-indexed VMs are deleted.`,
+		RunE: scaleE,
+	}
+
+	f := cmd.Flags()
+	f.String("workload", "", "Workload to scale (e.g., cpu)")
+	f.Int("vm-count", 0, "Target number of VMs for the workload")
+	f.String("run-id", "", "Run (UUID) whose VMs are scaled")
+	f.Bool("dry-run", false, "Print the VMs that would be created or deleted without changing anything")
+	f.Bool("namespace-per-workload", false, "Scale the workload in its <namespace>-<workload> namespace, for runs made with --namespace-per-workload")
+	_ = cmd.MarkFlagRequired("workload")
+	_ = cmd.MarkFlagRequired("vm-count")
+	_ = cmd.MarkFlagRequired("run-id")
+	return cmd
+}
+
+func newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Live migrate the VMs of an existing run",
+		Long: `Create a VirtualMachineInstanceMigration for each running VMI of an existing
+run, for live-migration soak testing. VMs without a running VMI are skipped.
+With --wait, the command waits for every migration to finish and exits
+non-zero if any of them fails or times out.`,
+		RunE: migrateE,
+	}
+
+	f := cmd.Flags()
+	f.String("run-id", "", "Run (UUID) whose VMs are migrated")
+	f.Bool("wait", false, "Wait for the migrations to complete")
+	f.Duration("migration-timeout", constants.DefaultMigrationTimeout, "How long --wait waits for the migrations")
+	f.Bool("namespace-per-workload", false, "Migrate the VMs in the <namespace>-<workload> namespaces of runs made with --namespace-per-workload")
+	_ = cmd.MarkFlagRequired("run-id")
+	return cmd
+}
+
+func newStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Boot the VMs of a run created with --start-stopped",
+		Long: `Set spec.running to true on every VM of an existing run, so KubeVirt boots
+the VMs that "run --start-stopped" created without starting them. VMs that are
+already running are left as they are. The command does not wait for the VMs
+to become ready; use "virtwork status" to follow them.`,
+		RunE: startE,
+	}
+
+	cmd.Flags().String("run-id", "", "Run (UUID) whose VMs are started")
+	cmd.Flags().Bool("namespace-per-workload", false, "Start the VMs in the <namespace>-<workload> namespaces of runs made with --namespace-per-workload")
+	_ = cmd.MarkFlagRequired("run-id")
+	return cmd
+}
+
+func newDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Print the rendered cloud-init and VM settings of one workload",
+		Long: `Build one workload from the current configuration and print its decoded
+cloud-init userdata, VM resources, extra disks and volumes, data volume
+templates, and Service, without connecting to a cluster. Multi-VM workloads
+print the userdata of each role, or only of the role given with --role.`,
+		Args: cobra.NoArgs,
+		RunE: describeE,
+	}
+
+	cmd.Flags().String("workload", "", "Workload to describe (e.g., database)")
+	cmd.Flags().String("role", "", "Only describe this role of a multi-VM workload (e.g., server)")
+	cmd.Flags().Bool("no-redact", false, "Show passwords and SSH keys in the userdata")
+	_ = cmd.MarkFlagRequired("workload")
+	return cmd
+}
+
+func newPreflightCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preflight",
+		Short: "List the guest packages each workload installs",
+		Long: `Build the selected workloads from the current configuration and list the
+packages their cloud-init installs, without connecting to a cluster. A package
+the guest image's repositories lack only fails inside the guest, so check that
+the image can install every package listed for it.`,
+		Args: cobra.NoArgs,
+		RunE: preflightE,
+	}
+
+	cmd.Flags().String("image", "", "Container disk image to check against (default: each workload's configured image)")
+	cmd.Flags().StringSlice("workloads", workloads.AllWorkloadNames, "Workloads to check (comma-separated, or all)")
+	return cmd
+}
+
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit database",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List past executions and their outcomes",
+		Long: `List the runs and cleanups recorded in the audit database, newest first,
+with their namespace, status, VM count, and duration. Passing --namespace
+limits the list to executions in that namespace.`,
+		RunE: auditListE,
+	}
+	listCmd.Flags().String("status", "", "Only list executions with this status (in_progress, success, failed)")
+	listCmd.Flags().String("format", "table", "Output format (table, json)")
+	listCmd.Flags().StringSlice("columns", nil, "Table columns to show, comma-separated: run-id, command, namespace, status, vms, started, duration")
+
+	compareCmd := &cobra.Command{
+		Use:   "compare <run-id-a> <run-id-b>",
+		Short: "Compare the configuration and outcome of two runs",
+		Long: `Show two runs from the audit database side by side: image, CPU, memory,
+workloads and their VM counts, then status, VMs created, ready, and failed,
+duration, and error. Fields that differ are marked with "*".`,
+		Args: cobra.ExactArgs(2),
+		RunE: auditCompareE,
+	}
+	compareCmd.Flags().String("format", "table", "Output format (table, json)")
+
+	eventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "Show the events recorded for a run",
+		Long: `Show the events a run recorded in the audit database, oldest first, with
+the VM each one concerns. With --follow, keep polling for new events until
+interrupted, for watching a run in progress from another terminal.`,
+		Args: cobra.NoArgs,
+		RunE: auditEventsE,
+	}
+	eventsCmd.Flags().String("run-id", "", "Run (UUID) whose events are shown")
+	eventsCmd.Flags().BoolP("follow", "f", false, "Keep polling for new events")
+	_ = eventsCmd.MarkFlagRequired("run-id")
+
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the audit database DDL",
+		Long: `Print the DDL virtwork applies to its audit database, for building external
+tooling against it. The dialect follows the configured backend: PostgreSQL
+when --audit-dsn is set, SQLite otherwise. --dialect selects one explicitly.`,
+		Args: cobra.NoArgs,
+		RunE: auditSchemaE,
+	}
+	schemaCmd.Flags().String("dialect", "", "Schema dialect (sqlite, postgres; default: the configured backend)")
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the audit database to CSV",
+		Long: `Export the executions in the audit database as CSV with a header row, one
+row per audit_log entry, for reporting in a spreadsheet. --include-vms also
+exports the VMs of those executions from vm_details to a second file named
+after --out with a -vms suffix. --since keeps only executions started on or
+after a date (2006-01-02), an RFC 3339 time, or an age such as 7d. Rows are
+streamed from the
+database, so large histories are not held in memory.`,
+		Args: cobra.NoArgs,
+		RunE: auditExportE,
+	}
+	exportCmd.Flags().String("format", "csv", "Output format (csv)")
+	exportCmd.Flags().String("out", "", "File to write (default: standard output)")
+	exportCmd.Flags().Bool("include-vms", false, "Also export the executions' VMs to <out>-vms.csv; requires --out")
+	exportCmd.Flags().String("since", "", "Only export executions started at or after this date, RFC 3339 time, or age (e.g., 7d)")
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize the outcomes of past executions",
+		Long: `Summarize the executions in the audit database for capacity reviews: how
+many ended in each status, their average duration, the VMs runs created and
+cleanups deleted, and a breakdown by workload type. --since keeps only
+executions started on or after a date (2006-01-02), an RFC 3339 time, or an
+age such as 7d or 12h.`,
+		Args: cobra.NoArgs,
+		RunE: auditStatsE,
+	}
+	statsCmd.Flags().String("since", "", "Only count executions started at or after this date, RFC 3339 time, or age (e.g., 7d)")
+	statsCmd.Flags().String("format", "table", "Output format (table, json)")
+
+	cmd.AddCommand(listCmd, compareCmd, eventsCmd, schemaCmd, exportCmd, statsCmd)
 	return cmd
 }
 
+// rootE handles flags on the bare root command and otherwise prints help.
+func rootE(cmd *cobra.Command, args []string) error {
+	listContexts, _ := cmd.Flags().GetBool("list-contexts")
+	if !listContexts {
+		return cmd.Help()
+	}
+
+	kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+	names, current, err := cluster.ListContexts(kubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("listing contexts: %w", err)
+	}
+	for _, name := range names {
+		marker := " "
+		if name == current {
+			marker = "*"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", marker, name)
+	}
+	return nil
+}
+
 // initAuditor creates the appropriate Auditor based on configuration flags.
 func initAuditor(cmd *cobra.Command, cfg *config.Config) (audit.Auditor, error) {
 	noAudit, _ := cmd.Flags().GetBool("no-audit")
@@ -102,12 +454,72 @@ func initAuditor(cmd *cobra.Command, cfg *config.Config) (audit.Auditor, error)
 		return audit.NoOpAuditor{}, nil
 	}
 
-	dbPath := cfg.AuditDBPath
+	if dsn := auditDSN(cmd, cfg); dsn != "" {
+		return audit.NewPostgresAuditor(dsn)
+	}
+	dbPath, err := auditDBPath(cmd, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return audit.NewSQLiteAuditor(dbPath)
+}
+
+// startExecution starts the audit execution of cmdName. An audit database
+// that cannot record it must not stop the command, so on failure a warning is
+// printed and the command continues with a NoOpAuditor and a run ID generated
+// here. The returned auditor is the one to record the rest of the execution
+// with.
+func startExecution(ctx context.Context, cmd *cobra.Command, auditor audit.Auditor, cmdName string, cfg *config.Config) (audit.Auditor, int64, string) {
+	execID, runID, err := auditor.StartExecution(ctx, cmdName, cfg)
+	if err == nil {
+		return auditor, execID, runID
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Warning: starting audit execution: %v; continuing without auditing\n", err)
+	return audit.NoOpAuditor{}, 0, uuid.New().String()
+}
+
+// auditDSN returns the PostgreSQL audit DSN, preferring the --audit-dsn flag.
+// An empty result selects the SQLite database.
+func auditDSN(cmd *cobra.Command, cfg *config.Config) string {
+	if cmd.Flags().Changed("audit-dsn") {
+		dsn, _ := cmd.Flags().GetString("audit-dsn")
+		return dsn
+	}
+	return cfg.AuditDSN
+}
+
+// auditDBPath returns the audit database path, preferring the --audit-db flag.
+// With --audit-db-per-context the path is instead derived from the kubeconfig
+// context in use, so each cluster keeps its own audit history.
+func auditDBPath(cmd *cobra.Command, cfg *config.Config) (string, error) {
 	if cmd.Flags().Changed("audit-db") {
-		dbPath, _ = cmd.Flags().GetString("audit-db")
+		dbPath, _ := cmd.Flags().GetString("audit-db")
+		return dbPath, nil
+	}
+	perContext := cfg.AuditDBPerContext
+	if cmd.Flags().Changed("audit-db-per-context") {
+		perContext, _ = cmd.Flags().GetBool("audit-db-per-context")
+	}
+	if !perContext {
+		return cfg.AuditDBPath, nil
 	}
 
-	return audit.NewSQLiteAuditor(dbPath)
+	contextName := cfg.KubeContext
+	if contextName == "" {
+		_, current, err := cluster.ListContexts(cfg.KubeconfigPath)
+		if err != nil {
+			return "", fmt.Errorf("resolving context for the audit database: %w", err)
+		}
+		contextName = current
+	}
+	if contextName == "" {
+		return "", fmt.Errorf("audit-db-per-context requires a kubeconfig context; set --context or a current-context")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory for the audit database: %w", err)
+	}
+	return audit.ContextDBPath(filepath.Join(home, constants.AuditContextDir), contextName), nil
 }
 
 // vmPlan describes a single VM to be created during orchestration.
@@ -119,12 +531,413 @@ type vmPlan struct {
 	role      string
 }
 
-// runE is the main orchestration flow for the "run" subcommand.
-func runE(cmd *cobra.Command, args []string) error {
+// usesHugepages reports whether any planned VM is hugepages-backed.
+func usesHugepages(plans []vmPlan) bool {
+	for _, p := range plans {
+		if p.vmSpec.Hugepages != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// planRequests sums the CPU and memory requests of the planned VMs.
+func planRequests(plans []vmPlan) (cpu, memory resource.Quantity) {
+	for _, p := range plans {
+		c, m := vm.Requests(*p.vmSpec)
+		cpu.Add(c)
+		memory.Add(m)
+	}
+	return cpu, memory
+}
+
+// selectWorkloads returns the requested workloads to deploy, in order and
+// without duplicates, with "all" expanded to the built-in workloads. Those
+// whose config file entry sets enabled: false are left out and returned as
+// disabled instead, unless "all" already skipped them.
+func selectWorkloads(cfg *config.Config, requested []string) (selected, disabled []string) {
+	seen := make(map[string]bool)
+	for _, name := range requested {
+		names := []string{name}
+		if name == "all" {
+			names = nil
+			for _, n := range workloads.AllWorkloadNames {
+				if !cfg.WorkloadDisabled(n) {
+					names = append(names, n)
+				}
+			}
+		}
+		for _, n := range names {
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			if cfg.WorkloadDisabled(n) {
+				disabled = append(disabled, n)
+				continue
+			}
+			selected = append(selected, n)
+		}
+	}
+	return selected, disabled
+}
+
+// newRegistry returns the workload registry, including any custom workloads
+// from the configuration, and the options workloads are created with.
+func newRegistry(cfg *config.Config) (workloads.Registry, []workloads.Option, error) {
+	registry := workloads.DefaultRegistry(workloads.WithCustomUserdata(cfg.CustomUserdata, workloads.DefaultCustomName))
+	if err := registry.RegisterTemplates(cfg.CustomWorkloads, cfg.TemplateValues); err != nil {
+		return nil, nil, fmt.Errorf("registering custom workloads: %w", err)
+	}
+	var overlay *cloudinit.CloudConfigOpts
+	if cfg.CloudInitOverlay != "" {
+		parsed, err := cloudinit.ParseOverlay([]byte(cfg.CloudInitOverlay))
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing cloud-init overlay %s: %w", cfg.CloudInitOverlayPath, err)
+		}
+		overlay = &parsed
+	}
+	opts := []workloads.Option{
+		workloads.WithNamespace(cfg.Namespace),
+		workloads.WithSSHCredentials(cfg.SSHUser, cfg.SSHPassword, cfg.SSHAuthorizedKeys),
+		workloads.WithDataDiskSize(cfg.DataDiskSize),
+		workloads.WithWarmup(cfg.Warmup),
+		workloads.WithStagger(cfg.Stagger),
+		workloads.WithSeedSQL(cfg.SeedSQL),
+		workloads.WithClientsPerServer(cfg.ClientsPerServer),
+		workloads.WithDataSource(cfg.DataSourceURL, cfg.DataSourcePVC),
+		workloads.WithDataFilesystem(cfg.DataFSPVC),
+		workloads.WithStorageClass(cfg.StorageClass, cfg.AccessMode),
+		workloads.WithSyslogServer(cfg.SyslogServer),
+		workloads.WithReadOnlyRoot(cfg.ReadOnlyRoot),
+		workloads.WithNetworkPolicy(cfg.NetworkPolicy),
+		workloads.WithCloudInitOverlay(overlay, cfg.OverlayOverride),
+	}
+	return registry, opts, nil
+}
+
+// workloadOptions returns opts with the namespace set to the named workload's,
+// so the services it creates and the DNS names its clients use are in the
+// namespace its VMs go in.
+func workloadOptions(cfg *config.Config, name string, opts []workloads.Option) []workloads.Option {
+	return append(slices.Clip(opts), workloads.WithNamespace(cfg.WorkloadNamespace(name)))
+}
+
+// workloadPlans returns the plan of each VM of w, the named workload, placed
+// in the workload's namespace.
+func workloadPlans(cfg *config.Config, w workloads.Workload, name, runID string) ([]vmPlan, error) {
+	namespace := cfg.WorkloadNamespace(name)
+	res := w.VMResources()
+	var plans []vmPlan
+	if multiVM, isMulti := w.(workloads.MultiVMWorkload); !isMulti {
+		for i := 0; i < w.VMCount(); i++ {
+			// Userdata is generated per VM so each gets its stagger delay
+			workloads.SetVMIndex(w, i)
+			userdata, err := w.CloudInitUserdata()
+			if err != nil {
+				return nil, fmt.Errorf("generating cloud-init for %q: %w", name, err)
+			}
+
+			vmName := fmt.Sprintf("virtwork-%s-%d", name, i)
+			plans = append(plans, vmPlan{
+				workload:  w,
+				component: name,
+				vmName:    vmName,
+				vmSpec:    singleVMSpec(cfg, w, name, vmName, runID, userdata),
+			})
+		}
+	} else {
+		// Multi-VM workload — use UserdataForRole
+		vmIndex := 0
+		for _, rc := range multiVM.RoleCounts() {
+			role := rc.Role
+			for i := 0; i < rc.Count; i++ {
+				workloads.SetVMIndex(w, vmIndex)
+				vmIndex++
+				userdata, err := multiVM.UserdataForRole(role, namespace)
+				if err != nil {
+					return nil, fmt.Errorf("generating cloud-init for %q role %q: %w", name, role, err)
+				}
+
+				vmName := fmt.Sprintf("virtwork-%s-%s-%d", name, role, i)
+				labels := map[string]string{
+					constants.LabelAppName:   fmt.Sprintf("virtwork-%s", name),
+					constants.LabelManagedBy: constants.ManagedByValue,
+					constants.LabelComponent: name,
+					constants.LabelRunID:     runID,
+					constants.LabelRole:      role,
+				}
+				plans = append(plans, vmPlan{
+					workload:  w,
+					component: name,
+					vmName:    vmName,
+					role:      role,
+					vmSpec: &vm.VMSpecOpts{
+						Name:                    vmName,
+						Namespace:               namespace,
+						ContainerDiskImage:      cfg.EffectiveImage(name, role),
+						ImagePullSecret:         cfg.ImagePullSecret,
+						CloudInitUserdata:       cloudinit.WithHostname(userdata, vmName),
+						CPUCores:                res.CPUCores,
+						Memory:                  res.Memory,
+						Labels:                  labels,
+						ExtraDisks:              w.ExtraDisks(),
+						ExtraVolumes:            w.ExtraVolumes(),
+						Filesystems:             filesystems(w),
+						GPUs:                    gpuDevices(cfg.GPUs),
+						SecondaryNetworks:       secondaryNetworks(cfg.MultusNetworks),
+						ClockTimezone:           cfg.ClockTimezone,
+						Timers:                  cfg.Timers,
+						CompressCloudInit:       cfg.CompressCloudInit,
+						CPUModel:                cfg.CPUModel,
+						DedicatedCPUPlacement:   cfg.DedicatedCPU,
+						Hugepages:               cfg.EffectiveHugepages(name),
+						MemoryLimit:             cfg.MemoryLimit,
+						CPULimit:                cfg.CPULimit,
+						OvercommitGuestOverhead: cfg.OvercommitGuestOverhead,
+						Sockets:                 cfg.CPUSockets,
+						Threads:                 cfg.CPUThreads,
+						NodeSelector:            cfg.NodeSelector,
+						Tolerations:             cfg.Tolerations,
+						Affinity:                cfg.Affinity,
+						Spread:                  spreadOpts(cfg),
+						ServiceAccountName:      serviceAccountName(w),
+						ReadinessProbe:          readinessProbe(cfg, w, role),
+						DataDiskBlockSize:       cfg.BlockSize,
+						StartStopped:            cfg.StartStopped,
+					},
+				})
+			}
+		}
+	}
+	return plans, nil
+}
+
+// checkReadOnlyRoot rejects a workload that keeps data on the root disk when
+// guests boot with a read-only root, since that data would only reach the
+// in-memory overlay.
+func checkReadOnlyRoot(cfg *config.Config, name string, w workloads.Workload) error {
+	if !cfg.ReadOnlyRoot {
+		return nil
+	}
+	if rw, ok := w.(workloads.RootWriter); ok {
+		return fmt.Errorf("workload %q writes %s to the root disk, which --read-only-root discards",
+			name, strings.Join(rw.RootWrites(), ", "))
+	}
+	return nil
+}
+
+// validateImageKeys checks that every key of the images config names a
+// registered workload, or a multi-VM workload and one of its roles as
+// "<workload>-<role>".
+func validateImageKeys(cfg *config.Config, registry workloads.Registry, opts []workloads.Option) error {
+	if len(cfg.Images) == 0 {
+		return nil
+	}
+	var keys []string
+	for _, name := range registry.List() {
+		keys = append(keys, name)
+		w, err := registry.Get(name, cfg.EffectiveWorkload(name, 1), opts...)
+		if err != nil {
+			return fmt.Errorf("creating workload %q: %w", name, err)
+		}
+		if multiVM, ok := w.(workloads.MultiVMWorkload); ok {
+			for _, rc := range multiVM.RoleCounts() {
+				keys = append(keys, name+"-"+rc.Role)
+			}
+		}
+	}
+	for key := range cfg.Images {
+		if !slices.Contains(keys, key) {
+			return fmt.Errorf("images: unknown workload or role %q; valid keys: %s", key, strings.Join(keys, ", "))
+		}
+	}
+	return nil
+}
+
+// singleVMSpec returns the spec for one VM of a single-VM workload, labeled
+// with the workload name and run ID. The VM name is set as the guest
+// hostname in its userdata.
+func singleVMSpec(cfg *config.Config, w workloads.Workload, name, vmName, runID, userdata string) *vm.VMSpecOpts {
+	res := w.VMResources()
+	return &vm.VMSpecOpts{
+		Name:               vmName,
+		Namespace:          cfg.WorkloadNamespace(name),
+		ContainerDiskImage: cfg.EffectiveImage(name, ""),
+		ImagePullSecret:    cfg.ImagePullSecret,
+		CloudInitUserdata:  cloudinit.WithHostname(userdata, vmName),
+		CPUCores:           res.CPUCores,
+		Memory:             res.Memory,
+		Labels: map[string]string{
+			constants.LabelAppName:   fmt.Sprintf("virtwork-%s", name),
+			constants.LabelManagedBy: constants.ManagedByValue,
+			constants.LabelComponent: name,
+			constants.LabelRunID:     runID,
+		},
+		ExtraDisks:              w.ExtraDisks(),
+		ExtraVolumes:            w.ExtraVolumes(),
+		DataVolumeTemplates:     w.DataVolumeTemplates(),
+		Filesystems:             filesystems(w),
+		GPUs:                    gpuDevices(cfg.GPUs),
+		SecondaryNetworks:       secondaryNetworks(cfg.MultusNetworks),
+		ClockTimezone:           cfg.ClockTimezone,
+		Timers:                  cfg.Timers,
+		CompressCloudInit:       cfg.CompressCloudInit,
+		CPUModel:                cfg.CPUModel,
+		DedicatedCPUPlacement:   cfg.DedicatedCPU,
+		Hugepages:               cfg.EffectiveHugepages(name),
+		MemoryLimit:             cfg.MemoryLimit,
+		CPULimit:                cfg.CPULimit,
+		OvercommitGuestOverhead: cfg.OvercommitGuestOverhead,
+		Sockets:                 cfg.CPUSockets,
+		Threads:                 cfg.CPUThreads,
+		NodeSelector:            cfg.NodeSelector,
+		Tolerations:             cfg.Tolerations,
+		Affinity:                cfg.Affinity,
+		Spread:                  spreadOpts(cfg),
+		ServiceAccountName:      serviceAccountName(w),
+		ReadinessProbe:          readinessProbe(cfg, w, ""),
+		DataDiskBlockSize:       cfg.BlockSize,
+		StartStopped:            cfg.StartStopped,
+	}
+}
+
+// gpuDevices returns a GPU device, named gpu0, gpu1, and so on, for each of
+// the device resource names, or nil when there are none.
+func gpuDevices(resourceNames []string) []kubevirtv1.GPU {
+	var gpus []kubevirtv1.GPU
+	for i, name := range resourceNames {
+		gpus = append(gpus, kubevirtv1.GPU{Name: fmt.Sprintf("gpu%d", i), DeviceName: name})
+	}
+	return gpus
+}
+
+// secondaryNetworks returns a bridge-bound NIC, named net1, net2, and so on,
+// on each of the NetworkAttachmentDefinitions, or nil when there are none.
+func secondaryNetworks(nads []string) []vm.SecondaryNetwork {
+	var networks []vm.SecondaryNetwork
+	for i, nad := range nads {
+		networks = append(networks, vm.SecondaryNetwork{
+			Name:          fmt.Sprintf("net%d", i+1),
+			NADName:       nad,
+			BindingBridge: true,
+		})
+	}
+	return networks
+}
+
+// spreadOpts returns the anti-affinity term selected by
+// --anti-affinity-weight, or nil when it is off.
+func spreadOpts(cfg *config.Config) *vm.SpreadOpts {
+	switch cfg.AntiAffinityWeight {
+	case "":
+		return nil
+	case "required":
+		return &vm.SpreadOpts{Required: true}
+	}
+	weight, _ := strconv.Atoi(cfg.AntiAffinityWeight)
+	return &vm.SpreadOpts{Weight: int32(weight)}
+}
+
+// serviceAccountName returns the ServiceAccount whose token w's VMs are
+// given, or "" when w does not call the Kubernetes API.
+func serviceAccountName(w workloads.Workload) string {
+	if a, ok := w.(workloads.APIClient); ok {
+		return a.ServiceAccountName()
+	}
+	return ""
+}
+
+// filesystems returns the shared filesystem devices of a FilesystemUser
+// workload, or nil.
+func filesystems(w workloads.Workload) []kubevirtv1.Filesystem {
+	if f, ok := w.(workloads.FilesystemUser); ok {
+		return f.Filesystems()
+	}
+	return nil
+}
+
+// createRetryOptions returns the VM create retry settings selected by
+// --create-retries and --create-backoff.
+func createRetryOptions(cfg *config.Config) vm.RetryOptions {
+	return vm.RetryOptions{
+		MaxRetries:  cfg.CreateRetries,
+		BaseBackoff: cfg.CreateBackoff,
+		MaxBackoff:  constants.MaxCreateBackoff,
+	}
+}
+
+// readinessProbe returns w's readiness probe for role when workload probes are
+// enabled and w provides one, and nil otherwise.
+func readinessProbe(cfg *config.Config, w workloads.Workload, role string) *kubevirtv1.Probe {
+	if !cfg.WorkloadProbes {
+		return nil
+	}
+	if p, ok := w.(workloads.Prober); ok {
+		return p.ReadinessProbe(role)
+	}
+	return nil
+}
+
+// runE is the main orchestration flow for the "run" subcommand. With
+// --deadline, connecting, creating, and waiting all share one context that
+// expires after that long, and an expired run is audited as failed.
+func runE(cmd *cobra.Command, args []string) (err error) {
+	start := time.Now()
 	cfg, err := config.LoadConfig(cmd)
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
+	if err := config.Validate(cfg); err != nil {
+		return err
+	}
+
+	if format, _ := cmd.Flags().GetString("config-dump"); format != "" {
+		return dumpConfig(cmd, cfg, format)
+	}
+
+	// With JSON output, progress goes to stderr so stdout holds only the
+	// summary.
+	output, _ := cmd.Flags().GetString("output")
+	if output != "table" && output != "json" {
+		return fmt.Errorf("invalid --output %q: must be table or json", output)
+	}
+	progress := cmd.OutOrStdout()
+	if output == "json" {
+		progress = cmd.ErrOrStderr()
+	}
+	// Progress is logged as plain lines alongside the summary, or as JSON
+	// records on stderr so stdout keeps only the summary.
+	logFormat, _ := cmd.Flags().GetString("log-format")
+	logOut := progress
+	if logFormat == logging.FormatJSON {
+		logOut = cmd.ErrOrStderr()
+	}
+	logger, err := logging.New(logOut, logFormat, cfg.Verbose)
+	if err != nil {
+		return err
+	}
+	// --progress draws VM creation and readiness as one updating line each,
+	// in place of the per-VM creation lines. Rewriting a line in place only
+	// displays as intended on a terminal.
+	showProgress, _ := cmd.Flags().GetBool("progress")
+	showProgress = showProgress && output != "json" && logFormat != logging.FormatJSON && progressbar.IsTerminal(progress)
+
+	onReadyExec, _ := cmd.Flags().GetString("on-ready-exec")
+	if onReadyExec != "" && !cfg.WaitForReady && !cfg.DryRun {
+		return fmt.Errorf("--on-ready-exec runs after the readiness wait and cannot be combined with --no-wait or --start-stopped")
+	}
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	if recreate, _ := cmd.Flags().GetBool("recreate-failed"); failFast && recreate {
+		return fmt.Errorf("--fail-fast stops the readiness wait at the first failure and cannot be combined with --recreate-failed")
+	}
+	verifySSHPort, _ := cmd.Flags().GetBool("verify-ssh")
+	if verifySSHPort && !cfg.WaitForReady && !cfg.DryRun {
+		return fmt.Errorf("--verify-ssh runs after the readiness wait and cannot be combined with --no-wait or --start-stopped")
+	}
+	if err := vm.ValidateSecondaryNetworks(secondaryNetworks(cfg.MultusNetworks)); err != nil {
+		return fmt.Errorf("invalid --multus-network: %w", err)
+	}
 
 	// Initialize auditor
 	auditor, err := initAuditor(cmd, cfg)
@@ -134,19 +947,38 @@ func runE(cmd *cobra.Command, args []string) error {
 	defer auditor.Close()
 
 	ctx := context.Background()
+	if cfg.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Deadline)
+		defer cancel()
+	}
 
 	// Start audit execution
 	cmdName := "run"
 	if cfg.DryRun {
 		cmdName = "dry-run"
 	}
-	execID, runID, err := auditor.StartExecution(ctx, cmdName, cfg)
-	if err != nil {
-		return fmt.Errorf("starting audit execution: %w", err)
+	auditor, execID, runID := startExecution(ctx, cmd, auditor, cmdName, cfg)
+	// A re-run labels its resources with the reused run-id; the execution
+	// is linked to that run like a scale.
+	reuseRunID, _ := cmd.Flags().GetString("reuse-run-id")
+	updateVMs, _ := cmd.Flags().GetBool("update")
+	if updateVMs && reuseRunID != "" {
+		return fmt.Errorf("--update cannot be combined with --reuse-run-id, which leaves existing VMs unchanged")
 	}
+	if reuseRunID != "" {
+		runID = reuseRunID
+		_ = auditor.LinkCleanupToRuns(ctx, execID, []string{runID})
+	}
+	logger = logger.With("run_id", runID)
 	defer func() {
 		if err != nil {
-			_ = auditor.CompleteExecution(ctx, execID, "failed", err.Error())
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("deadline of %s exceeded: %w", cfg.Deadline, err)
+			}
+			// The run's context may have expired, so record the failure
+			// without it.
+			_ = auditor.CompleteExecution(context.WithoutCancel(ctx), execID, "failed", err.Error())
 		}
 	}()
 
@@ -156,14 +988,26 @@ func runE(cmd *cobra.Command, args []string) error {
 	})
 
 	// Determine which workloads to deploy
-	workloadNames, _ := cmd.Flags().GetStringSlice("workloads")
+	requested, _ := cmd.Flags().GetStringSlice("workloads")
+	workloadNames, disabled := selectWorkloads(cfg, requested)
+	for _, name := range disabled {
+		logger.Info(fmt.Sprintf("Skipping workload %s: disabled in the config file", name), "component", name)
+	}
+	if len(workloadNames) == 0 {
+		return fmt.Errorf("no workloads to deploy: every requested workload is disabled in the config file")
+	}
 	vmCountFlag, _ := cmd.Flags().GetInt("vm-count")
 
-	registry := workloads.DefaultRegistry()
-	registryOpts := []workloads.Option{
-		workloads.WithNamespace(cfg.Namespace),
-		workloads.WithSSHCredentials(cfg.SSHUser, cfg.SSHPassword, cfg.SSHAuthorizedKeys),
-		workloads.WithDataDiskSize(cfg.DataDiskSize),
+	registry, registryOpts, err := newRegistry(cfg)
+	if err != nil {
+		return err
+	}
+	// The monitor scrapes node_exporter on the VMs of this run
+	if slices.Contains(workloadNames, "monitor") {
+		registryOpts = append(registryOpts, workloads.WithMonitoring(runID))
+	}
+	if err := validateImageKeys(cfg, registry, registryOpts); err != nil {
+		return err
 	}
 
 	// Build workload instances
@@ -172,126 +1016,43 @@ func runE(cmd *cobra.Command, args []string) error {
 	auditWorkloadIDs := make(map[string]int64) // workload name -> audit workload ID
 
 	for _, name := range workloadNames {
-		wlCfg := config.WorkloadConfig{
-			Enabled:  true,
-			VMCount:  vmCountFlag,
-			CPUCores: cfg.CPUCores,
-			Memory:   cfg.Memory,
-		}
-		// Override with per-workload config from YAML if present
-		if fileCfg, ok := cfg.Workloads[name]; ok {
-			if fileCfg.CPUCores > 0 {
-				wlCfg.CPUCores = fileCfg.CPUCores
-			}
-			if fileCfg.Memory != "" {
-				wlCfg.Memory = fileCfg.Memory
-			}
-			if fileCfg.VMCount > 0 {
-				wlCfg.VMCount = fileCfg.VMCount
-			}
-		}
+		wlCfg := cfg.EffectiveWorkload(name, vmCountFlag)
 
-		w, err := registry.Get(name, wlCfg, registryOpts...)
+		w, err := registry.Get(name, wlCfg, workloadOptions(cfg, name, registryOpts)...)
 		if err != nil {
 			return fmt.Errorf("creating workload %q: %w", name, err)
 		}
+		if err := checkReadOnlyRoot(cfg, name, w); err != nil {
+			return err
+		}
 
 		vmCount := w.VMCount()
 		res := w.VMResources()
 
 		// Record workload in audit
 		wlID, _ := auditor.RecordWorkload(ctx, execID, audit.WorkloadRecord{
-			WorkloadType:    name,
-			Enabled:         true,
-			VMCount:         vmCount,
-			CPUCores:        res.CPUCores,
-			Memory:          res.Memory,
-			HasDataDisk:     len(w.DataVolumeTemplates()) > 0,
-			DataDiskSize:    cfg.DataDiskSize,
-			RequiresService: w.RequiresService(),
+			WorkloadType:       name,
+			Enabled:            true,
+			VMCount:            vmCount,
+			CPUCores:           res.CPUCores,
+			Memory:             res.Memory,
+			HasDataDisk:        len(w.DataVolumeTemplates()) > 0,
+			DataDiskSize:       cfg.DataDiskSize,
+			RequiresService:    w.RequiresService(),
+			ContainerDiskImage: cfg.EffectiveImage(name, ""),
 		})
 		auditWorkloadIDs[name] = wlID
 
-		if _, isMulti := w.(workloads.MultiVMWorkload); !isMulti {
-			userdata, err := w.CloudInitUserdata()
-			if err != nil {
-				return fmt.Errorf("generating cloud-init for %q: %w", name, err)
-			}
-
-			for i := 0; i < vmCount; i++ {
-				vmName := fmt.Sprintf("virtwork-%s-%d", name, i)
-				plans = append(plans, vmPlan{
-					workload:  w,
-					component: name,
-					vmName:    vmName,
-					vmSpec: &vm.VMSpecOpts{
-						Name:               vmName,
-						Namespace:          cfg.Namespace,
-						ContainerDiskImage: cfg.ContainerDiskImage,
-						CloudInitUserdata:  userdata,
-						CPUCores:           res.CPUCores,
-						Memory:             res.Memory,
-						Labels: map[string]string{
-							constants.LabelAppName:   fmt.Sprintf("virtwork-%s", name),
-							constants.LabelManagedBy: constants.ManagedByValue,
-							constants.LabelComponent: name,
-							constants.LabelRunID:     runID,
-						},
-						ExtraDisks:          w.ExtraDisks(),
-						ExtraVolumes:        w.ExtraVolumes(),
-						DataVolumeTemplates: w.DataVolumeTemplates(),
-					},
-				})
-				vmNames = append(vmNames, vmName)
-			}
-		} else {
-			// Multi-VM workload — use UserdataForRole
-			multiVM, ok := w.(workloads.MultiVMWorkload)
-			if !ok {
-				return fmt.Errorf("workload %q reports VMCount=%d but does not implement MultiVMWorkload", name, vmCount)
-			}
-
-			roles := []string{"server", "client"}
-			perRole := vmCount / len(roles)
-			for _, role := range roles {
-				userdata, err := multiVM.UserdataForRole(role, cfg.Namespace)
-				if err != nil {
-					return fmt.Errorf("generating cloud-init for %q role %q: %w", name, role, err)
-				}
-
-				for i := 0; i < perRole; i++ {
-					vmName := fmt.Sprintf("virtwork-%s-%s-%d", name, role, i)
-					labels := map[string]string{
-						constants.LabelAppName:   fmt.Sprintf("virtwork-%s", name),
-						constants.LabelManagedBy: constants.ManagedByValue,
-						constants.LabelComponent: name,
-						constants.LabelRunID:     runID,
-						"virtwork/role":          role,
-					}
-					plans = append(plans, vmPlan{
-						workload:  w,
-						component: name,
-						vmName:    vmName,
-						role:      role,
-						vmSpec: &vm.VMSpecOpts{
-							Name:               vmName,
-							Namespace:          cfg.Namespace,
-							ContainerDiskImage: cfg.ContainerDiskImage,
-							CloudInitUserdata:  userdata,
-							CPUCores:           res.CPUCores,
-							Memory:             res.Memory,
-							Labels:             labels,
-							ExtraDisks:         w.ExtraDisks(),
-							ExtraVolumes:       w.ExtraVolumes(),
-						},
-					})
-					vmNames = append(vmNames, vmName)
-				}
-			}
+		wPlans, err := workloadPlans(cfg, w, name, runID)
+		if err != nil {
+			return err
 		}
+		plans = append(plans, wPlans...)
+		vmNames = append(vmNames, planNames(wPlans)...)
 	}
 
 	// Update audit with total counts
+	_ = auditor.RecordTotals(ctx, execID, len(plans), len(workloadNames))
 	_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
 		EventType: "execution_started",
 		Message:   fmt.Sprintf("Planned %d VMs across %d workloads", len(plans), len(workloadNames)),
@@ -299,151 +1060,336 @@ func runE(cmd *cobra.Command, args []string) error {
 
 	// Dry-run: print specs and return
 	if cfg.DryRun {
-		if err := printDryRun(plans); err != nil {
-			return err
+		if output == "json" {
+			if err := writeJSON(cmd.OutOrStdout(), dryRunListing{
+				RunID:     runID,
+				Namespace: cfg.Namespace,
+				VMs:       planSummaries(plans),
+			}); err != nil {
+				return err
+			}
+		} else {
+			noRedact, _ := cmd.Flags().GetBool("no-redact")
+			if err := printDryRun(plans, !noRedact); err != nil {
+				return err
+			}
 		}
 		_ = auditor.CompleteExecution(ctx, execID, "success", "")
 		err = nil // clear for defer
 		return nil
 	}
 
-	// Connect to cluster
-	c, err := cluster.Connect(cfg.KubeconfigPath)
+	// Write metrics on the way out, including for failed runs
+	runMetrics := metrics.RunResult{Namespace: cfg.Namespace, RunID: runID}
+	if metricsFile, _ := cmd.Flags().GetString("metrics-file"); metricsFile != "" {
+		defer func() {
+			runMetrics.Duration = time.Since(start)
+			if werr := metrics.WriteTextfile(metricsFile, runMetrics); werr != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", werr)
+			}
+		}()
+	}
+
+	// Connect to cluster
+	c, err := cluster.ConnectWithContext(cfg.KubeconfigPath, cfg.KubeContext)
 	if err != nil {
 		return fmt.Errorf("connecting to cluster: %w", err)
 	}
+	if !cfg.SkipConnectCheck {
+		d, err := cluster.NewDiscoveryClient(cfg.KubeconfigPath, cfg.KubeContext)
+		if err != nil {
+			return fmt.Errorf("connecting to cluster: %w", err)
+		}
+		info, err := cluster.Ping(d)
+		if err != nil {
+			return err
+		}
+		logger.Debug(fmt.Sprintf("API server %s reachable", info.GitVersion),
+			"server_version", info.GitVersion)
+	}
 
-	// Ensure namespace exists
-	if err := resources.EnsureNamespace(ctx, c, cfg.Namespace, map[string]string{
-		constants.LabelManagedBy: constants.ManagedByValue,
-	}); err != nil {
-		return fmt.Errorf("ensuring namespace %q: %w", cfg.Namespace, err)
+	// Fail early, rather than deep in VM creation, without OpenShift
+	// Virtualization
+	if !cfg.SkipPreflight {
+		if err := cluster.CheckVirtInstalled(ctx, c); err != nil {
+			return err
+		}
 	}
-	fmt.Fprintf(cmd.OutOrStdout(), "Namespace %s ensured\n", cfg.Namespace)
 
-	// Create services before VMs (DNS must resolve for client VMs)
-	servicesCreated := 0
-	for _, name := range workloadNames {
-		// Re-fetch workload to check service requirement
-		wlCfg := config.WorkloadConfig{
-			Enabled:  true,
-			VMCount:  vmCountFlag,
-			CPUCores: cfg.CPUCores,
-			Memory:   cfg.Memory,
+	// Fail early if the cluster cannot satisfy the requested VM options
+	warnings, err := cluster.CheckFeatureGates(ctx, c, cluster.FeatureRequirements{
+		DedicatedCPU: cfg.DedicatedCPU,
+		Hugepages:    usesHugepages(plans),
+		GPUs:         cfg.GPUs,
+	})
+	for _, w := range warnings {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", w)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Ensure the namespaces exist; with --namespace-per-workload there is
+	// one per workload
+	namespaces := workloadNamespaces(cfg, workloadNames)
+	for _, ns := range namespaces {
+		if err := resources.EnsureNamespace(ctx, c, ns, map[string]string{
+			constants.LabelManagedBy: constants.ManagedByValue,
+		}); err != nil {
+			return fmt.Errorf("ensuring namespace %q: %w", ns, err)
 		}
-		w, err := registry.Get(name, wlCfg, registryOpts...)
-		if err != nil {
-			continue
+		logger.Info(fmt.Sprintf("Namespace %s ensured", ns), "namespace", ns)
+	}
+
+	// Everything created from here on carries the run's CI metadata
+	c = resources.WithAnnotations(c, cfg.CIAnnotations())
+
+	// A re-run creates only the planned VMs the run does not have yet
+	toCreate := plans
+	if reuseRunID != "" {
+		gaps := &scale.Gaps{}
+		for _, ns := range namespaces {
+			nsGaps, err := scale.FindGaps(ctx, c, ns, runID, planNames(plansIn(plans, ns)))
+			if err != nil {
+				return fmt.Errorf("listing VMs of run %s: %w", runID, err)
+			}
+			gaps.Existing = append(gaps.Existing, nsGaps.Existing...)
+			gaps.Missing = append(gaps.Missing, nsGaps.Missing...)
+			gaps.Extra = append(gaps.Extra, nsGaps.Extra...)
 		}
-		if w.RequiresService() {
-			svc := w.ServiceSpec()
-			if svc != nil {
-				// Add run-id label to service
-				if svc.Labels == nil {
-					svc.Labels = make(map[string]string)
-				}
-				svc.Labels[constants.LabelRunID] = runID
+		missing := make(map[string]bool, len(gaps.Missing))
+		for _, name := range gaps.Missing {
+			missing[name] = true
+		}
+		toCreate = nil
+		for _, p := range plans {
+			if missing[p.vmName] {
+				toCreate = append(toCreate, p)
+			}
+		}
+		for _, name := range gaps.Extra {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: VM %s of run %s is not in the plan; leaving it\n", name, runID)
+		}
+		logger.Info(fmt.Sprintf("Run %s has %d of %d planned VMs; creating %d",
+			runID, len(gaps.Existing), len(plans), len(toCreate)),
+			"existing", len(gaps.Existing), "planned", len(plans), "creating", len(toCreate))
+		_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+			EventType: "execution_started",
+			Message: fmt.Sprintf("Reusing run-id %s: %d of %d planned VMs exist, creating %d",
+				runID, len(gaps.Existing), len(plans), len(toCreate)),
+		})
+	}
 
-				if err := resources.CreateService(ctx, c, svc); err != nil {
-					return fmt.Errorf("creating service for %q: %w", name, err)
-				}
-				servicesCreated++
-				fmt.Fprintf(cmd.OutOrStdout(), "Service %s created\n", svc.Name)
+	// Fail early, rather than partway through VM creation, when the VMs
+	// still to create do not fit in a namespace quota
+	if cfg.CheckQuota {
+		for _, ns := range namespaces {
+			cpu, memory := planRequests(plansIn(toCreate, ns))
+			if err := resources.CheckQuota(ctx, c, ns, cpu, memory); err != nil {
+				return err
+			}
+			logger.Debug(fmt.Sprintf("Resource quotas in %s fit %s CPU and %s memory", ns, cpu.String(), memory.String()),
+				"namespace", ns, "cpu", cpu.String(), "memory", memory.String())
+		}
+	}
 
-				_, _ = auditor.RecordResource(ctx, execID, audit.ResourceRecord{
-					ResourceType: "Service",
-					ResourceName: svc.Name,
-					Namespace:    svc.Namespace,
-				})
-				_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
-					EventType: "service_created",
-					Message:   fmt.Sprintf("Service %s created", svc.Name),
-				})
+	if cfg.ImagePullSecret != "" {
+		for _, ns := range namespaces {
+			if err := resources.CheckImagePullSecret(ctx, c, ns, cfg.ImagePullSecret); err != nil {
+				return err
 			}
 		}
 	}
 
+	// Create services before VMs (DNS must resolve for client VMs)
+	servicesCreated, err := createServices(ctx, c, cfg, registry, registryOpts, workloadNames, vmCountFlag, runID, auditor, execID, logger)
+	if err != nil {
+		return err
+	}
+	runMetrics.ServicesCreated += servicesCreated
+
+	// Create ServiceAccounts before VMs (their token disks need them)
+	for _, name := range workloadNames {
+		w, err := registry.Get(name, config.WorkloadConfig{Enabled: true}, workloadOptions(cfg, name, registryOpts)...)
+		if err != nil {
+			continue
+		}
+		a, ok := w.(workloads.APIClient)
+		if !ok {
+			continue
+		}
+		saName := a.ServiceAccountName()
+		saNamespace := cfg.WorkloadNamespace(name)
+		if err := resources.CreateServiceAccountWithRole(ctx, c, saName, saNamespace, a.PolicyRules(), map[string]string{
+			constants.LabelManagedBy: constants.ManagedByValue,
+			constants.LabelComponent: name,
+			constants.LabelRunID:     runID,
+		}); err != nil {
+			return fmt.Errorf("creating service account for %q: %w", name, err)
+		}
+		logger.Info(fmt.Sprintf("ServiceAccount %s created", saName),
+			"service_account_name", saName, "component", name)
+
+		for _, kind := range []string{"ServiceAccount", "Role", "RoleBinding"} {
+			_, _ = auditor.RecordResource(ctx, execID, audit.ResourceRecord{
+				ResourceType: kind,
+				ResourceName: saName,
+				Namespace:    saNamespace,
+			})
+		}
+	}
+
 	// Create cloud-init secrets before VMs
 	secretsCreated := 0
-	for i := range plans {
-		secretName := plans[i].vmName + "-cloudinit"
+	for i := range toCreate {
+		secretName := toCreate[i].vmName + "-cloudinit"
 		secretLabels := map[string]string{
-			constants.LabelAppName:   plans[i].vmSpec.Labels[constants.LabelAppName],
+			constants.LabelAppName:   toCreate[i].vmSpec.Labels[constants.LabelAppName],
 			constants.LabelManagedBy: constants.ManagedByValue,
-			constants.LabelComponent: plans[i].component,
+			constants.LabelComponent: toCreate[i].component,
 			constants.LabelRunID:     runID,
 		}
-		if err := resources.CreateCloudInitSecret(ctx, c, secretName,
-			cfg.Namespace, plans[i].vmSpec.CloudInitUserdata, secretLabels); err != nil {
-			return fmt.Errorf("creating cloud-init secret for %q: %w", plans[i].vmName, err)
+		createSecret := resources.CreateCloudInitSecret
+		if toCreate[i].vmSpec.CompressCloudInit {
+			createSecret = resources.CreateCompressedCloudInitSecret
 		}
-		plans[i].vmSpec.CloudInitSecretName = secretName
+		if err := createSecret(ctx, c, secretName,
+			toCreate[i].vmSpec.Namespace, toCreate[i].vmSpec.CloudInitUserdata, secretLabels); err != nil {
+			return fmt.Errorf("creating cloud-init secret for %q: %w", toCreate[i].vmName, err)
+		}
+		toCreate[i].vmSpec.CloudInitSecretName = secretName
 		secretsCreated++
-		fmt.Fprintf(cmd.OutOrStdout(), "Secret %s created\n", secretName)
+		logger.Info(fmt.Sprintf("Secret %s created", secretName),
+			"secret_name", secretName, "vm_name", toCreate[i].vmName, "component", toCreate[i].component)
 
 		_, _ = auditor.RecordResource(ctx, execID, audit.ResourceRecord{
 			ResourceType: "Secret",
 			ResourceName: secretName,
-			Namespace:    cfg.Namespace,
+			Namespace:    toCreate[i].vmSpec.Namespace,
 		})
 	}
 
-	// Create VMs concurrently via errgroup
-	g, gctx := errgroup.WithContext(ctx)
-	for _, p := range plans {
-		p := p // capture loop variable
-		g.Go(func() error {
-			vmObj := vm.BuildVMSpec(*p.vmSpec)
-			if err := vm.CreateVM(gctx, c, vmObj); err != nil {
-				_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
-					EventType:   "vm_failed",
-					Message:     fmt.Sprintf("Failed to create VM %s", p.vmName),
-					ErrorDetail: err.Error(),
-				})
-				return fmt.Errorf("creating VM %q: %w", p.vmName, err)
-			}
-			fmt.Fprintf(cmd.OutOrStdout(), "VM %s created\n", p.vmName)
-
-			wlID := auditWorkloadIDs[p.component]
-			_, _ = auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
-				VMName:             p.vmName,
-				Namespace:          cfg.Namespace,
-				Component:          p.component,
-				Role:               p.role,
-				CPUCores:           p.vmSpec.CPUCores,
-				Memory:             p.vmSpec.Memory,
-				ContainerDiskImage: p.vmSpec.ContainerDiskImage,
-				HasDataDisk:        len(p.vmSpec.DataVolumeTemplates) > 0,
-				DataDiskSize:       cfg.DataDiskSize,
-			})
+	if updateVMs {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: --update changes existing VMs in place; KubeVirt may restart running VMs to apply the new settings\n")
+	}
+
+	// Create VMs concurrently, up to --create-concurrency at a time
+	var vmsCreated, vmsFailed atomic.Int32
+	var createBar *progressbar.Bar
+	if showProgress {
+		createBar = progressbar.New(progress, "Creating VMs", len(toCreate))
+	}
+	createErr := createVMs(ctx, c, cfg, toCreate, updateVMs, func(p vmPlan, updated bool, err error) {
+		if err != nil {
+			vmsFailed.Add(1)
 			_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
-				EventType: "vm_created",
-				Message:   fmt.Sprintf("VM %s created", p.vmName),
+				EventType:   "vm_failed",
+				Message:     fmt.Sprintf("Failed to create VM %s", p.vmName),
+				ErrorDetail: err.Error(),
 			})
-			return nil
+			return
+		}
+		vmsCreated.Add(1)
+		verb, eventType := "created", "vm_created"
+		if updated {
+			verb, eventType = "updated", "vm_updated"
+		}
+		if showProgress {
+			createBar.Increment()
+		} else {
+			logger.Info(fmt.Sprintf("VM %s %s", p.vmName, verb),
+				"vm_name", p.vmName, "component", p.component)
+		}
+
+		wlID := auditWorkloadIDs[p.component]
+		vmID, vmErr := auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
+			VMName:             p.vmName,
+			Namespace:          p.vmSpec.Namespace,
+			Component:          p.component,
+			Role:               p.role,
+			CPUCores:           p.vmSpec.CPUCores,
+			Memory:             p.vmSpec.Memory,
+			ContainerDiskImage: p.vmSpec.ContainerDiskImage,
+			HasDataDisk:        len(p.vmSpec.DataVolumeTemplates) > 0,
+			DataDiskSize:       cfg.DataDiskSize,
 		})
+		event := audit.EventRecord{
+			EventType: eventType,
+			Message:   fmt.Sprintf("VM %s %s", p.vmName, verb),
+		}
+		if vmErr == nil {
+			event.VMID = &vmID
+		}
+		_ = auditor.RecordEvent(ctx, execID, event)
+	})
+	createBar.Finish()
+	runMetrics.VMsCreated = int(vmsCreated.Load())
+	runMetrics.VMsFailed = int(vmsFailed.Load())
+	if createErr != nil {
+		err = fmt.Errorf("creating VMs: %w", createErr)
+		return err
 	}
-	if err := g.Wait(); err != nil {
-		return fmt.Errorf("creating VMs: %w", err)
+
+	if cfg.StartStopped {
+		startCmd := "virtwork start --run-id " + runID
+		if cfg.NamespacePerWorkload {
+			startCmd += " --namespace-per-workload"
+		}
+		logger.Info("VMs created stopped; boot them with: " + startCmd)
 	}
 
 	// Wait for readiness
 	if cfg.WaitForReady {
 		timeout := time.Duration(cfg.ReadyTimeoutSeconds) * time.Second
-		fmt.Fprintf(cmd.OutOrStdout(), "Waiting for %d VMs to become ready (timeout: %s)...\n",
-			len(vmNames), timeout)
-		results := wait.WaitForAllVMsReady(ctx, c, vmNames, cfg.Namespace,
-			timeout, constants.DefaultPollInterval)
+		logger.Info(fmt.Sprintf("Waiting for %d VMs to become ready (level: %s, timeout: %s)...",
+			len(vmNames), cfg.ReadinessLevel, timeout),
+			"vm_count", len(vmNames), "readiness_level", cfg.ReadinessLevel, "timeout", timeout.String())
+		var readyBar *progressbar.Bar
+		if showProgress {
+			readyBar = progressbar.New(progress, "Ready", len(vmNames))
+		}
+		onReady := func(_ string, r wait.Result) {
+			if r.Err == nil {
+				readyBar.Increment()
+			}
+		}
+		// Retries are logged only when no progress line is being redrawn,
+		// which they would otherwise break up.
+		waitLogger := logger
+		if showProgress {
+			waitLogger = slog.New(slog.DiscardHandler)
+		}
+		results := waitForPlans(ctx, c, cfg, plans, timeout, constants.DefaultPollInterval, failFast, waitLogger, onReady)
+		if recreate, _ := cmd.Flags().GetBool("recreate-failed"); recreate {
+			specs := make(map[string]*vm.VMSpecOpts, len(toCreate))
+			for _, p := range toCreate {
+				specs[p.vmName] = p.vmSpec
+			}
+			for _, name := range recreateFailedVMs(ctx, c, cfg, specs, results, timeout,
+				constants.DefaultPollInterval, logger, waitLogger, onReady) {
+				_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+					EventType: "vm_recreated",
+					Message:   fmt.Sprintf("VM %s recreated after its VMI failed", name),
+				})
+			}
+		}
+		readyBar.Finish()
+
+		if reportFile, _ := cmd.Flags().GetString("ready-report-file"); reportFile != "" {
+			if werr := wait.WriteReport(reportFile, results); werr != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", werr)
+			}
+		}
 
 		failures := 0
-		for name, err := range results {
-			if err != nil {
-				fmt.Fprintf(cmd.ErrOrStderr(), "VM %s: %v\n", name, err)
+		for name, result := range results {
+			if result.Err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "VM %s: %v\n", name, result.Err)
 				failures++
 				_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
 					EventType:   "vm_timeout",
 					Message:     fmt.Sprintf("VM %s failed readiness check", name),
-					ErrorDetail: err.Error(),
+					ErrorDetail: result.Err.Error(),
 				})
 			} else {
 				_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
@@ -452,11 +1398,28 @@ func runE(cmd *cobra.Command, args []string) error {
 				})
 			}
 		}
+		runMetrics.VMsReady = len(vmNames) - failures
+		runMetrics.VMsFailed += failures
 		if failures > 0 {
 			err = fmt.Errorf("%d of %d VMs failed readiness check", failures, len(vmNames))
 			return err
 		}
-		fmt.Fprintf(cmd.OutOrStdout(), "All %d VMs ready\n", len(vmNames))
+		logger.Info(fmt.Sprintf("All %d VMs ready", len(vmNames)), "vm_count", len(vmNames))
+
+		if verifySSHPort {
+			if err = verifySSH(ctx, cmd, c, auditor, execID, cfg, vmNames, vmNamespaces(plans), timeout, logger); err != nil {
+				return err
+			}
+		}
+
+		if onReadyExec != "" {
+			if err = runOnReadyHook(ctx, cmd, c, auditor, execID, onReadyExec, hook.Context{
+				RunID:     runID,
+				Namespace: cfg.Namespace,
+			}, vmNames, vmNamespaces(plans), logger, progress); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Mark all workloads as created
@@ -469,120 +1432,1984 @@ func runE(cmd *cobra.Command, args []string) error {
 	err = nil // clear for defer
 
 	// Print summary
-	printSummary(cmd, len(plans), servicesCreated, secretsCreated, cfg, runID)
-	return nil
+	summary := deploymentSummary{
+		RunID:     runID,
+		Namespace: cfg.Namespace,
+		Images:    planImages(plans),
+		Counts: summaryCounts{
+			VMs:      len(toCreate),
+			Services: servicesCreated,
+			Secrets:  secretsCreated,
+		},
+		VMs:         planSummaries(plans),
+		StartedAt:   start.UTC(),
+		CompletedAt: time.Now().UTC(),
+	}
+	if cfg.NamespacePerWorkload {
+		summary.Namespaces = namespaces
+	}
+	return renderSummary(cmd.OutOrStdout(), summary, output)
 }
 
-// cleanupE is the cleanup flow for the "cleanup" subcommand.
-func cleanupE(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadConfig(cmd)
-	if err != nil {
-		return fmt.Errorf("loading config: %w", err)
+// createVMs creates the planned VMs, or applies them over existing ones when
+// update is set, keeping at most cfg.CreateConcurrency requests in flight.
+// done is called once for each VM attempted, from the goroutine that
+// attempted it. The first failure stops VMs not yet started and is returned.
+func createVMs(ctx context.Context, c client.Client, cfg *config.Config, plans []vmPlan, update bool, done func(p vmPlan, updated bool, err error)) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(cfg.CreateConcurrency, 1))
+	for _, p := range plans {
+		p := p // capture loop variable
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			vmObj := vm.BuildVMSpec(*p.vmSpec)
+			var updated bool
+			var err error
+			if update {
+				updated, err = vm.ApplyVMWithOptions(gctx, c, vmObj, createRetryOptions(cfg))
+			} else {
+				err = vm.CreateVMWithOptions(gctx, c, vmObj, createRetryOptions(cfg))
+			}
+			done(p, updated, err)
+			if err != nil {
+				return fmt.Errorf("creating VM %q: %w", p.vmName, err)
+			}
+			return nil
+		})
 	}
+	return g.Wait()
+}
 
-	// Initialize auditor
-	auditor, err := initAuditor(cmd, cfg)
-	if err != nil {
-		return fmt.Errorf("initializing auditor: %w", err)
-	}
-	defer auditor.Close()
+// createServices creates the Service, and the NetworkPolicy where the workload
+// provides one, of each named workload that requires a Service, labelled with
+// runID. Each workload is built from its effective config, so the Service
+// exposes the port and protocol its server VMs listen on. It returns the
+// number of Services created.
+func createServices(ctx context.Context, c client.Client, cfg *config.Config, registry workloads.Registry, opts []workloads.Option, names []string, vmCount int, runID string, auditor audit.Auditor, execID int64, logger *slog.Logger) (int, error) {
+	created := 0
+	for _, name := range names {
+		w, err := registry.Get(name, cfg.EffectiveWorkload(name, vmCount), workloadOptions(cfg, name, opts)...)
+		if err != nil {
+			continue
+		}
+		if w.RequiresService() {
+			svc := w.ServiceSpec()
+			if svc != nil {
+				// Add run-id label to service
+				if svc.Labels == nil {
+					svc.Labels = make(map[string]string)
+				}
+				svc.Labels[constants.LabelRunID] = runID
 
-	ctx := context.Background()
+				if err := resources.CreateService(ctx, c, svc); err != nil {
+					return created, fmt.Errorf("creating service for %q: %w", name, err)
+				}
+				created++
+				logger.Info(fmt.Sprintf("Service %s created", svc.Name),
+					"service_name", svc.Name, "component", name)
 
-	// Start audit execution
-	execID, _, err := auditor.StartExecution(ctx, "cleanup", cfg)
-	if err != nil {
-		return fmt.Errorf("starting audit execution: %w", err)
-	}
-	defer func() {
-		if err != nil {
-			_ = auditor.CompleteExecution(ctx, execID, "failed", err.Error())
+				_, _ = auditor.RecordResource(ctx, execID, audit.ResourceRecord{
+					ResourceType: "Service",
+					ResourceName: svc.Name,
+					Namespace:    svc.Namespace,
+				})
+				_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+					EventType: "service_created",
+					Message:   fmt.Sprintf("Service %s created", svc.Name),
+				})
+			}
 		}
-	}()
+		if p, ok := w.(workloads.NetworkPolicyProvider); ok {
+			if np := p.NetworkPolicySpec(); np != nil {
+				if np.Labels == nil {
+					np.Labels = make(map[string]string)
+				}
+				np.Labels[constants.LabelRunID] = runID
 
-	deleteNS, _ := cmd.Flags().GetBool("delete-namespace")
-	targetRunID, _ := cmd.Flags().GetString("run-id")
+				if err := resources.CreateNetworkPolicy(ctx, c, np); err != nil {
+					return created, fmt.Errorf("creating network policy for %q: %w", name, err)
+				}
+				logger.Info(fmt.Sprintf("NetworkPolicy %s created", np.Name),
+					"network_policy_name", np.Name, "component", name)
 
-	_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
-		EventType: "cleanup_started",
-		Message:   fmt.Sprintf("Cleanup started (namespace: %s, run-id filter: %q)", cfg.Namespace, targetRunID),
-	})
+				_, _ = auditor.RecordResource(ctx, execID, audit.ResourceRecord{
+					ResourceType: "NetworkPolicy",
+					ResourceName: np.Name,
+					Namespace:    np.Namespace,
+				})
+				_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+					EventType: "network_policy_created",
+					Message:   fmt.Sprintf("NetworkPolicy %s created", np.Name),
+				})
+			}
+		}
+	}
+	return created, nil
+}
 
-	c, err := cluster.Connect(cfg.KubeconfigPath)
-	if err != nil {
-		return fmt.Errorf("connecting to cluster: %w", err)
+// workloadNamespaces returns the namespaces the named workloads go in, in
+// order and without repeats: just cfg.Namespace unless
+// --namespace-per-workload is set.
+func workloadNamespaces(cfg *config.Config, names []string) []string {
+	var namespaces []string
+	for _, name := range names {
+		if ns := cfg.WorkloadNamespace(name); !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
 	}
+	return namespaces
+}
 
-	result, err := cleanup.CleanupAll(ctx, c, cfg.Namespace, deleteNS, targetRunID)
-	if err != nil {
-		return fmt.Errorf("cleanup failed: %w", err)
+// plansIn returns the plans of the VMs that go in namespace, in plan order.
+func plansIn(plans []vmPlan, namespace string) []vmPlan {
+	var in []vmPlan
+	for _, p := range plans {
+		if p.vmSpec.Namespace == namespace {
+			in = append(in, p)
+		}
 	}
+	return in
+}
 
-	// Link cleanup to discovered run IDs
-	if len(result.RunIDs) > 0 {
-		_ = auditor.LinkCleanupToRuns(ctx, execID, result.RunIDs)
+// planNames returns the VM name of each plan, in plan order.
+func planNames(plans []vmPlan) []string {
+	names := make([]string, 0, len(plans))
+	for _, p := range plans {
+		names = append(names, p.vmName)
 	}
+	return names
+}
 
-	// Record cleanup counts
-	_ = auditor.RecordCleanupCounts(ctx, execID,
-		result.VMsDeleted, result.ServicesDeleted, result.SecretsDeleted, result.NamespaceDeleted)
+// vmNamespaces maps the VM of each plan to its namespace.
+func vmNamespaces(plans []vmPlan) map[string]string {
+	namespaces := make(map[string]string, len(plans))
+	for _, p := range plans {
+		namespaces[p.vmName] = p.vmSpec.Namespace
+	}
+	return namespaces
+}
 
-	_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
-		EventType: "cleanup_completed",
-		Message: fmt.Sprintf("Deleted %d VMs, %d services, %d secrets",
-			result.VMsDeleted, result.ServicesDeleted, result.SecretsDeleted),
-	})
+// namespaceOf returns the namespace of VM name in namespaces, or def when it
+// has none there.
+func namespaceOf(namespaces map[string]string, name, def string) string {
+	if ns, ok := namespaces[name]; ok {
+		return ns
+	}
+	return def
+}
 
-	// Complete audit
-	_ = auditor.CompleteExecution(ctx, execID, "success", "")
-	err = nil // clear for defer
+// waitForPlans waits for the VMs of plans to become ready at the configured
+// level, each namespace's VMs concurrently with the others', and returns
+// every VM's Result. With failFast, the first VM to fail in any namespace
+// cancels the waits for all the others. VMIs not yet created are logged at
+// debug level to logger. Calls to done are serialized across namespaces.
+func waitForPlans(ctx context.Context, c client.Client, cfg *config.Config, plans []vmPlan, timeout, interval time.Duration, failFast bool, logger *slog.Logger, done func(string, wait.Result)) map[string]wait.Result {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	waitFor := wait.WaitForAllVMsReadyAtLevelFunc
+	if failFast {
+		waitFor = wait.WaitForAllVMsReadyFailFast
+	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Cleanup complete: %d VMs deleted, %d services deleted, %d secrets deleted",
-		result.VMsDeleted, result.ServicesDeleted, result.SecretsDeleted)
-	if result.NamespaceDeleted {
-		fmt.Fprintf(cmd.OutOrStdout(), ", namespace deleted")
+	var namespaces []string
+	for _, p := range plans {
+		if !slices.Contains(namespaces, p.vmSpec.Namespace) {
+			namespaces = append(namespaces, p.vmSpec.Namespace)
+		}
 	}
-	fmt.Fprintln(cmd.OutOrStdout())
 
-	if len(result.Errors) > 0 {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Warnings (%d):\n", len(result.Errors))
-		for _, e := range result.Errors {
-			fmt.Fprintf(cmd.ErrOrStderr(), "  - %v\n", e)
+	results := make(map[string]wait.Result, len(plans))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, ns := range namespaces {
+		wg.Add(1)
+		go func(ns string) {
+			defer wg.Done()
+			nsResults := waitFor(ctx, c, planNames(plansIn(plans, ns)), ns,
+				cfg.ReadinessLevel, timeout, interval, logger, func(name string, r wait.Result) {
+					if failFast && r.Err != nil {
+						cancel()
+					}
+					if done == nil {
+						return
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					done(name, r)
+				})
+			mu.Lock()
+			defer mu.Unlock()
+			maps.Copy(results, nsResults)
+		}(ns)
+	}
+	wg.Wait()
+	return results
+}
+
+// recreateFailedVMs deletes and recreates, once, each VM of specs whose
+// result shows its VMI failed before becoming ready, then waits for those VMs
+// again and replaces their results in place. done is called as each new wait
+// ends. Elapsed then covers both attempts. The new waits log to waitLogger.
+// It returns the VMs that were recreated; a VM that could not be recreated
+// keeps a result with the error.
+func recreateFailedVMs(ctx context.Context, c client.Client, cfg *config.Config, specs map[string]*vm.VMSpecOpts, results map[string]wait.Result, timeout, interval time.Duration, logger, waitLogger *slog.Logger, done func(string, wait.Result)) []string {
+	failed := make(map[string]wait.Result)
+	for name, r := range results {
+		if _, ok := specs[name]; ok && errors.Is(r.Err, wait.ErrVMFailed) {
+			failed[name] = r
 		}
 	}
 
-	return nil
+	var recreated []string
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, first := range failed {
+		wg.Add(1)
+		go func(name string, first wait.Result) {
+			defer wg.Done()
+			logger.Info(fmt.Sprintf("VM %s failed (%s); recreating it", name, first.LastPhase),
+				"vm_name", name, "phase", string(first.LastPhase))
+			err := vm.RecreateVM(ctx, c, vm.BuildVMSpec(*specs[name]), createRetryOptions(cfg), timeout, interval)
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				first.Err = fmt.Errorf("recreating VM after %v: %w", first.Err, err)
+				results[name] = first
+				return
+			}
+			r := wait.WaitForAllVMsReadyAtLevelFunc(ctx, c, []string{name}, specs[name].Namespace, cfg.ReadinessLevel, timeout, interval, waitLogger, nil)[name]
+			r.Elapsed += first.Elapsed
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = r
+			recreated = append(recreated, name)
+			if done != nil {
+				done(name, r)
+			}
+		}(name, first)
+	}
+	wg.Wait()
+
+	sort.Strings(recreated)
+	return recreated
 }
 
-// printDryRun outputs VM specs in YAML without connecting to a cluster.
-func printDryRun(plans []vmPlan) error {
-	fmt.Println("--- Dry Run ---")
-	fmt.Printf("Total VMs to create: %d\n\n", len(plans))
+// sshPort is the port --verify-ssh checks on each VM.
+const sshPort = 22
+
+// verifySSH waits, concurrently and for up to timeout each, until every VM
+// answers on its SSH port. namespaces maps a VM to its namespace when that is
+// not cfg.Namespace. It is skipped when no SSH password or key is
+// configured. A VM that cannot be reached from here, as when its pod network
+// is not routable, is skipped with a warning rather than failed.
+func verifySSH(ctx context.Context, cmd *cobra.Command, c client.Client, auditor audit.Auditor, execID int64, cfg *config.Config, vmNames []string, namespaces map[string]string, timeout time.Duration, logger *slog.Logger) error {
+	if cfg.SSHPassword == "" && len(cfg.SSHAuthorizedKeys) == 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipping --verify-ssh: no SSH password or key is configured\n")
+		return nil
+	}
 
-	for _, p := range plans {
-		vmObj := vm.BuildVMSpec(*p.vmSpec)
-		data, err := sigyaml.Marshal(vmObj)
-		if err != nil {
-			return fmt.Errorf("marshaling VM spec for %q: %w", p.vmName, err)
+	logger.Info(fmt.Sprintf("Checking SSH on %d VMs (timeout: %s)...", len(vmNames), timeout),
+		"vm_count", len(vmNames), "timeout", timeout.String())
+	errs := make(map[string]error, len(vmNames))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range vmNames {
+		wg.Add(1)
+		go func(vmName string) {
+			defer wg.Done()
+			ip, err := vm.GetVMIIP(ctx, c, vmName, namespaceOf(namespaces, vmName, cfg.Namespace))
+			if err == nil {
+				err = wait.WaitForSSH(ctx, ip, sshPort, cfg.SSHUser, timeout)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			errs[vmName] = err
+		}(name)
+	}
+	wg.Wait()
+
+	failures, skipped := 0, 0
+	for _, name := range vmNames {
+		err := errs[name]
+		switch {
+		case err == nil:
+			_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+				EventType: "vm_ssh_ready",
+				Message:   fmt.Sprintf("VM %s answers on its SSH port", name),
+			})
+		case errors.Is(err, wait.ErrSSHUnreachable):
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipping SSH check of VM %s: %v\n", name, err)
+			skipped++
+		default:
+			fmt.Fprintf(cmd.ErrOrStderr(), "VM %s: %v\n", name, err)
+			failures++
+			_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+				EventType:   "vm_ssh_failed",
+				Message:     fmt.Sprintf("VM %s failed SSH check", name),
+				ErrorDetail: err.Error(),
+			})
 		}
-		fmt.Printf("# VM: %s (workload: %s)\n", p.vmName, p.component)
-		fmt.Println(string(data))
-		fmt.Println("---")
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d of %d VMs failed SSH check", failures, len(vmNames))
+	}
+	if skipped < len(vmNames) {
+		logger.Info(fmt.Sprintf("SSH answering on %d VMs", len(vmNames)-skipped), "vm_count", len(vmNames)-skipped)
 	}
 	return nil
 }
 
-// printSummary outputs a deployment summary table.
-func printSummary(cmd *cobra.Command, vmCount, svcCount, secCount int, cfg *config.Config, runID string) {
+// runOnReadyHook runs the --on-ready-exec command with the IPs of vmNames
+// added to hc, streaming its stdout to progress and its stderr to stderr.
+// namespaces maps a VM to its namespace when that is not hc.Namespace. A
+// failing command fails the run unless --on-ready-exec-allow-failure is set,
+// in which case a warning is printed instead.
+func runOnReadyHook(ctx context.Context, cmd *cobra.Command, c client.Client, auditor audit.Auditor, execID int64, command string, hc hook.Context, vmNames []string, namespaces map[string]string, logger *slog.Logger, progress io.Writer) error {
+	hc.VMIPs = make(map[string]string, len(vmNames))
+	for _, name := range vmNames {
+		ip, err := vm.GetVMIIP(ctx, c, name, namespaceOf(namespaces, name, hc.Namespace))
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: looking up the IP of VM %s: %v\n", name, err)
+		}
+		hc.VMIPs[name] = ip
+	}
+
+	logger.Info(fmt.Sprintf("Running on-ready hook: %s", command), "command", command)
+	err := hook.Run(ctx, command, hc, progress, cmd.ErrOrStderr())
+	if err == nil {
+		_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+			EventType: "hook_succeeded",
+			Message:   fmt.Sprintf("On-ready hook %q succeeded", command),
+		})
+		return nil
+	}
+	_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+		EventType:   "hook_failed",
+		Message:     fmt.Sprintf("On-ready hook %q failed", command),
+		ErrorDetail: err.Error(),
+	})
+	if allow, _ := cmd.Flags().GetBool("on-ready-exec-allow-failure"); allow {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: on-ready hook: %v\n", err)
+		return nil
+	}
+	return fmt.Errorf("on-ready hook: %w", err)
+}
+
+// cleanupE is the cleanup flow for the "cleanup" subcommand. Any error
+// returned once the audit execution has started marks it failed.
+func cleanupE(cmd *cobra.Command, args []string) (err error) {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	// Initialize auditor
+	auditor, err := initAuditor(cmd, cfg)
+	if err != nil {
+		return fmt.Errorf("initializing auditor: %w", err)
+	}
+	defer auditor.Close()
+
+	ctx := context.Background()
+
+	// Start audit execution
+	auditor, execID, _ := startExecution(ctx, cmd, auditor, "cleanup", cfg)
+	defer func() {
+		if err != nil {
+			_ = auditor.CompleteExecution(ctx, execID, "failed", err.Error())
+		}
+	}()
+
+	deleteNS, _ := cmd.Flags().GetBool("delete-namespace")
+	targetRunID, _ := cmd.Flags().GetString("run-id")
+	gracePeriod, _ := cmd.Flags().GetInt64("grace-period")
+	propagation, _ := cmd.Flags().GetString("propagation")
+	deleteOpts, err := cleanup.DeleteOptions(gracePeriod, propagation)
+	if err != nil {
+		return err
+	}
+	if force, _ := cmd.Flags().GetBool("force"); force {
+		if cmd.Flags().Changed("grace-period") || cmd.Flags().Changed("propagation") {
+			err = fmt.Errorf("--force cannot be combined with --grace-period or --propagation")
+			return err
+		}
+		deleteOpts = vm.ForceDeleteOptions().ClientOptions()
+	}
+	concurrency, _ := cmd.Flags().GetInt("cleanup-concurrency")
+	if concurrency < 1 {
+		return fmt.Errorf("cleanup-concurrency must be at least 1, got %d", concurrency)
+	}
+	selector, _ := cmd.Flags().GetStringToString("selector")
+	reportOrphans, _ := cmd.Flags().GetBool("report-orphans")
+	deleteOrphans, _ := cmd.Flags().GetBool("delete-orphans")
+	if deleteOrphans && !reportOrphans {
+		return fmt.Errorf("--delete-orphans requires --report-orphans")
+	}
+	if reportOrphans && (deleteNS || targetRunID != "" || len(selector) > 0) {
+		return fmt.Errorf("--report-orphans checks the whole namespace and cannot be combined with --delete-namespace, --run-id, or --selector")
+	}
+	verify, _ := cmd.Flags().GetBool("verify-cleanup-complete")
+	verifyTimeout, _ := cmd.Flags().GetDuration("verify-timeout")
+	if verify && reportOrphans {
+		return fmt.Errorf("--verify-cleanup-complete cannot be combined with --report-orphans")
+	}
+	if verifyTimeout < 0 {
+		return fmt.Errorf("verify-timeout must not be negative, got %s", verifyTimeout)
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if dryRun && (reportOrphans || verify) {
+		return fmt.Errorf("--dry-run cannot be combined with --report-orphans or --verify-cleanup-complete")
+	}
+	olderThan, _ := cmd.Flags().GetDuration("older-than")
+	if olderThan < 0 {
+		return fmt.Errorf("older-than must not be negative, got %s", olderThan)
+	}
+	if olderThan > 0 && (deleteNS || reportOrphans || verify) {
+		return fmt.Errorf("--older-than cannot be combined with --delete-namespace, --report-orphans, or --verify-cleanup-complete")
+	}
+	// Resources created before the cutoff are old enough to delete
+	var cutoff time.Time
+	if olderThan > 0 {
+		cutoff = time.Now().Add(-olderThan)
+	}
+
+	if reportOrphans {
+		var c client.Client
+		c, err = cluster.ConnectWithContext(cfg.KubeconfigPath, cfg.KubeContext)
+		if err != nil {
+			return fmt.Errorf("connecting to cluster: %w", err)
+		}
+		var namespaces []string
+		namespaces, err = runNamespaces(ctx, c, cfg)
+		if err != nil {
+			return err
+		}
+		for _, ns := range namespaces {
+			var report *cleanup.OrphanReport
+			report, err = cleanup.FindOrphans(ctx, c, ns)
+			if err != nil {
+				return fmt.Errorf("finding orphans: %w", err)
+			}
+			printOrphanReport(cmd.OutOrStdout(), ns, report)
+			_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+				EventType: "orphans_found",
+				Message: fmt.Sprintf("Found %d orphaned secrets and %d orphaned services in %s",
+					len(report.Secrets), len(report.Services), ns),
+			})
+
+			if deleteOrphans && !report.Empty() {
+				result := cleanup.DeleteOrphans(ctx, c, ns, report, concurrency, deleteOpts...)
+				_ = auditor.RecordCleanupCounts(ctx, execID, 0, result.ServicesDeleted, result.SecretsDeleted, false)
+				fmt.Fprintf(cmd.OutOrStdout(), "Orphans deleted: %d services, %d secrets\n",
+					result.ServicesDeleted, result.SecretsDeleted)
+				printCleanupWarnings(cmd.ErrOrStderr(), result.Errors)
+			}
+		}
+		_ = auditor.CompleteExecution(ctx, execID, "success", "")
+		return nil
+	}
+
+	if dryRun {
+		var c client.Client
+		c, err = cluster.ConnectWithContext(cfg.KubeconfigPath, cfg.KubeContext)
+		if err != nil {
+			return fmt.Errorf("connecting to cluster: %w", err)
+		}
+		var namespaces []string
+		namespaces, err = runNamespaces(ctx, c, cfg)
+		if err != nil {
+			return err
+		}
+		for _, ns := range namespaces {
+			var plan *cleanup.CleanupResult
+			if olderThan > 0 {
+				plan, err = cleanup.PlanCleanupOlderThan(ctx, c, ns, targetRunID, selector, cutoff)
+			} else {
+				plan, err = cleanup.PlanCleanup(ctx, c, ns, deleteNS, targetRunID, selector)
+			}
+			if err != nil {
+				return fmt.Errorf("planning cleanup: %w", err)
+			}
+			printCleanupPlan(cmd.OutOrStdout(), ns, plan)
+			_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+				EventType: "cleanup_planned",
+				Message: fmt.Sprintf("Dry run: would delete %d VMs, %d services, %d secrets (namespace: %s, run-id filter: %q, selector: %v, older than: %s)",
+					plan.VMsDeleted, plan.ServicesDeleted, plan.SecretsDeleted, ns, targetRunID, selector, olderThan),
+			})
+		}
+		_ = auditor.CompleteExecution(ctx, execID, "success", "")
+		return nil
+	}
+
+	_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+		EventType: "cleanup_started",
+		Message:   fmt.Sprintf("Cleanup started (namespace: %s, run-id filter: %q, selector: %v, older than: %s)", cfg.Namespace, targetRunID, selector, olderThan),
+	})
+
+	c, err := cluster.ConnectWithContext(cfg.KubeconfigPath, cfg.KubeContext)
+	if err != nil {
+		return fmt.Errorf("connecting to cluster: %w", err)
+	}
+
+	namespaces, err := runNamespaces(ctx, c, cfg)
+	if err != nil {
+		return err
+	}
+	result := &cleanup.CleanupResult{}
+	for _, ns := range namespaces {
+		var nsResult *cleanup.CleanupResult
+		if olderThan > 0 {
+			nsResult, err = cleanup.CleanupOlderThan(ctx, c, ns, targetRunID, selector, cutoff, concurrency, deleteOpts...)
+		} else {
+			nsResult, err = cleanup.CleanupAllConcurrent(ctx, c, ns, deleteNS, targetRunID, selector, concurrency, deleteOpts...)
+		}
+		if err != nil {
+			return fmt.Errorf("cleanup failed: %w", err)
+		}
+		result.Add(nsResult)
+	}
+
+	// Re-list after deletion so a CI teardown fails on leaked resources
+	var verifyErr error
+	if verify {
+		for _, ns := range namespaces {
+			if verifyErr = cleanup.VerifyComplete(ctx, c, ns, targetRunID, selector, verifyTimeout, constants.VerifyCleanupPollInterval); verifyErr != nil {
+				break
+			}
+		}
+	}
+
+	// Link cleanup to discovered run IDs. Resources from older releases carry
+	// no run-id label, so fall back to the namespace's most recent run.
+	linkedRunIDs := result.RunIDs
+	deleted := result.VMsDeleted + result.ServicesDeleted + result.SecretsDeleted
+	if len(linkedRunIDs) == 0 && targetRunID == "" && deleted > 0 {
+		if runIDs, err := auditor.FindRunsByNamespace(ctx, cfg.Namespace); err == nil && len(runIDs) > 0 {
+			linkedRunIDs = runIDs[:1]
+			_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+				EventType: "cleanup_linked",
+				Message:   fmt.Sprintf("No run-id labels found; linked to the most recent run %s in %s", runIDs[0], cfg.Namespace),
+			})
+		}
+	}
+	if len(linkedRunIDs) > 0 {
+		_ = auditor.LinkCleanupToRuns(ctx, execID, linkedRunIDs)
+	}
+
+	// Record cleanup counts
+	_ = auditor.RecordCleanupCounts(ctx, execID,
+		result.VMsDeleted, result.ServicesDeleted, result.SecretsDeleted, result.NamespaceDeleted)
+
+	_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+		EventType: "cleanup_completed",
+		Message: fmt.Sprintf("Deleted %d VMs, %d services, %d secrets",
+			result.VMsDeleted, result.ServicesDeleted, result.SecretsDeleted),
+	})
+
+	// Complete audit; a failed verification is recorded by the deferred call
+	if verifyErr != nil {
+		err = verifyErr
+	} else {
+		_ = auditor.CompleteExecution(ctx, execID, "success", "")
+		err = nil // clear for defer
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Cleanup complete: %d VMs deleted, %d services deleted, %d secrets deleted",
+		result.VMsDeleted, result.ServicesDeleted, result.SecretsDeleted)
+	if result.RBACDeleted > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), ", %d RBAC objects deleted", result.RBACDeleted)
+	}
+	if result.ConfigMapsDeleted > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), ", %d config maps deleted", result.ConfigMapsDeleted)
+	}
+	if result.NetworkPoliciesDeleted > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), ", %d network policies deleted", result.NetworkPoliciesDeleted)
+	}
+	if result.NamespaceDeleted {
+		fmt.Fprintf(cmd.OutOrStdout(), ", namespace deleted")
+	}
+	fmt.Fprintln(cmd.OutOrStdout())
+
+	printCleanupWarnings(cmd.ErrOrStderr(), result.Errors)
+
+	return verifyErr
+}
+
+// runNamespaces returns the namespaces a command acting on existing runs
+// covers: cfg.Namespace, or with --namespace-per-workload the namespace of
+// each registered workload that exists.
+func runNamespaces(ctx context.Context, c client.Client, cfg *config.Config) ([]string, error) {
+	if !cfg.NamespacePerWorkload {
+		return []string{cfg.Namespace}, nil
+	}
+	registry, _, err := newRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, name := range registry.List() {
+		ns := cfg.WorkloadNamespace(name)
+		exists, err := resources.NamespaceExists(ctx, c, ns)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces, nil
+}
+
+// printCleanupWarnings lists the deletions that failed during a cleanup.
+func printCleanupWarnings(w io.Writer, errs []error) {
+	if len(errs) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Warnings (%d):\n", len(errs))
+	for _, e := range errs {
+		fmt.Fprintf(w, "  - %v\n", e)
+	}
+}
+
+// printCleanupPlan lists the resources a cleanup dry run found in namespace
+// and the totals a real cleanup would delete.
+func printCleanupPlan(w io.Writer, namespace string, plan *cleanup.CleanupResult) {
+	if len(plan.Planned) == 0 {
+		fmt.Fprintf(w, "No managed resources to delete in namespace %s\n", namespace)
+	} else {
+		fmt.Fprintf(w, "Resources that would be deleted in namespace %s:\n", namespace)
+		for _, entry := range plan.Planned {
+			kind, name, _ := strings.Cut(entry, "/")
+			fmt.Fprintf(w, "  %-15s %s\n", kind, name)
+		}
+	}
+
+	fmt.Fprintf(w, "Dry run: %d VMs, %d services, %d secrets would be deleted",
+		plan.VMsDeleted, plan.ServicesDeleted, plan.SecretsDeleted)
+	if plan.RBACDeleted > 0 {
+		fmt.Fprintf(w, ", %d RBAC objects", plan.RBACDeleted)
+	}
+	if plan.ConfigMapsDeleted > 0 {
+		fmt.Fprintf(w, ", %d config maps", plan.ConfigMapsDeleted)
+	}
+	if plan.NetworkPoliciesDeleted > 0 {
+		fmt.Fprintf(w, ", %d network policies", plan.NetworkPoliciesDeleted)
+	}
+	if plan.NamespaceDeleted {
+		fmt.Fprintf(w, ", and namespace %s", namespace)
+	}
+	fmt.Fprintln(w)
+}
+
+// printOrphanReport lists the orphaned secrets and services in namespace.
+func printOrphanReport(w io.Writer, namespace string, report *cleanup.OrphanReport) {
+	if report.Empty() {
+		fmt.Fprintf(w, "No orphaned resources in namespace %s\n", namespace)
+		return
+	}
+	fmt.Fprintf(w, "Orphaned resources in namespace %s:\n", namespace)
+	for _, name := range report.Secrets {
+		fmt.Fprintf(w, "  Secret   %s (no VM %s)\n", name, strings.TrimSuffix(name, "-cloudinit"))
+	}
+	for _, name := range report.Services {
+		fmt.Fprintf(w, "  Service  %s (selects no VM)\n", name)
+	}
+}
+
+// scaleE adds or removes VMs of one workload in an existing run for the
+// "scale" subcommand.
+func scaleE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := config.Validate(cfg); err != nil {
+		return err
+	}
+
+	name, _ := cmd.Flags().GetString("workload")
+	target, _ := cmd.Flags().GetInt("vm-count")
+	targetRunID, _ := cmd.Flags().GetString("run-id")
+
+	registry, registryOpts, err := newRegistry(cfg)
+	if err != nil {
+		return err
+	}
+	w, err := registry.Get(name, cfg.EffectiveWorkload(name, target), registryOpts...)
+	if err != nil {
+		return fmt.Errorf("creating workload %q: %w", name, err)
+	}
+	if _, isMulti := w.(workloads.MultiVMWorkload); isMulti {
+		return fmt.Errorf("scaling multi-VM workload %q is not supported", name)
+	}
+	userdata, err := w.CloudInitUserdata()
+	if err != nil {
+		return fmt.Errorf("generating cloud-init for %q: %w", name, err)
+	}
+
+	// Initialize auditor
+	auditor, err := initAuditor(cmd, cfg)
+	if err != nil {
+		return fmt.Errorf("initializing auditor: %w", err)
+	}
+	defer auditor.Close()
+
+	ctx := context.Background()
+
+	// The scale execution is linked to the run it changes
+	auditor, execID, _ := startExecution(ctx, cmd, auditor, "scale", cfg)
+	defer func() {
+		if err != nil {
+			_ = auditor.CompleteExecution(ctx, execID, "failed", err.Error())
+		}
+	}()
+	_ = auditor.LinkCleanupToRuns(ctx, execID, []string{targetRunID})
+
+	c, err := cluster.ConnectWithContext(cfg.KubeconfigPath, cfg.KubeContext)
+	if err != nil {
+		return fmt.Errorf("connecting to cluster: %w", err)
+	}
+
+	namespace := cfg.WorkloadNamespace(name)
+	plan, err := scale.ComputePlan(ctx, c, namespace, targetRunID, name, target)
+	if err != nil {
+		return err
+	}
+	_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+		EventType: "scale_started",
+		Message: fmt.Sprintf("Scaling %s in run %s from %d to %d VMs",
+			name, targetRunID, len(plan.Existing), target),
+	})
+
+	if cfg.DryRun {
+		printScalePlan(cmd, plan)
+		_ = auditor.CompleteExecution(ctx, execID, "success", "")
+		err = nil // clear for defer
+		return nil
+	}
+
+	// Fail early if the cluster cannot satisfy the requested VM options
+	var warnings []string
+	if len(plan.Create) > 0 {
+		warnings, err = cluster.CheckFeatureGates(ctx, c, cluster.FeatureRequirements{
+			DedicatedCPU: cfg.DedicatedCPU,
+			Hugepages:    cfg.EffectiveHugepages(name) != "",
+		})
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", w)
+	}
+	if err != nil {
+		return err
+	}
+
+	res := w.VMResources()
+	wlID, _ := auditor.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+		WorkloadType:    name,
+		Enabled:         true,
+		VMCount:         len(plan.Create),
+		CPUCores:        res.CPUCores,
+		Memory:          res.Memory,
+		HasDataDisk:     len(w.DataVolumeTemplates()) > 0,
+		DataDiskSize:    cfg.DataDiskSize,
+		RequiresService: w.RequiresService(),
+	})
+
+	result, err := scale.Apply(ctx, c, plan, func(vmName string) *vm.VMSpecOpts {
+		return singleVMSpec(cfg, w, name, vmName, targetRunID, userdata)
+	})
+	for _, vmName := range result.Created {
+		fmt.Fprintf(cmd.OutOrStdout(), "VM %s created\n", vmName)
+		_, _ = auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
+			VMName:             vmName,
+			Namespace:          namespace,
+			Component:          name,
+			CPUCores:           res.CPUCores,
+			Memory:             res.Memory,
+			ContainerDiskImage: cfg.EffectiveImage(name, ""),
+			HasDataDisk:        len(w.DataVolumeTemplates()) > 0,
+			DataDiskSize:       cfg.DataDiskSize,
+		})
+		_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+			EventType: "vm_created",
+			Message:   fmt.Sprintf("VM %s created", vmName),
+		})
+	}
+	for _, vmName := range result.Deleted {
+		fmt.Fprintf(cmd.OutOrStdout(), "VM %s deleted\n", vmName)
+		_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+			EventType: "vm_deleted",
+			Message:   fmt.Sprintf("VM %s deleted", vmName),
+		})
+	}
+	if len(result.Deleted) > 0 {
+		_ = auditor.RecordCleanupCounts(ctx, execID, len(result.Deleted), 0, len(result.Deleted), false)
+	}
+	if err != nil {
+		return fmt.Errorf("scaling %q: %w", name, err)
+	}
+
+	_ = auditor.UpdateWorkloadStatus(ctx, wlID, "created")
+	_ = auditor.CompleteExecution(ctx, execID, "success", "")
+	err = nil // clear for defer
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Scaled %s in run %s from %d to %d VMs\n",
+		name, targetRunID, len(plan.Existing), target)
+	return nil
+}
+
+// printScalePlan prints the VMs a scale would create or delete.
+func printScalePlan(cmd *cobra.Command, plan *scale.Plan) {
 	out := cmd.OutOrStdout()
-	fmt.Fprintln(out, strings.Repeat("=", 50))
-	fmt.Fprintln(out, "Deployment Summary")
-	fmt.Fprintln(out, strings.Repeat("=", 50))
-	fmt.Fprintf(out, "Run ID:       %s\n", runID)
-	fmt.Fprintf(out, "Namespace:    %s\n", cfg.Namespace)
-	fmt.Fprintf(out, "VMs created:  %d\n", vmCount)
-	fmt.Fprintf(out, "Services:     %d\n", svcCount)
-	fmt.Fprintf(out, "Secrets:      %d\n", secCount)
-	fmt.Fprintf(out, "Image:        %s\n", cfg.ContainerDiskImage)
-	fmt.Fprintln(out, strings.Repeat("=", 50))
+	for _, vmName := range plan.Create {
+		fmt.Fprintf(out, "Would create VM %s\n", vmName)
+	}
+	for _, vmName := range plan.Delete {
+		fmt.Fprintf(out, "Would delete VM %s\n", vmName)
+	}
+	fmt.Fprintf(out, "Dry run: %s in run %s would go from %d to %d VMs (%d created, %d deleted)\n",
+		plan.Workload, plan.RunID, len(plan.Existing),
+		len(plan.Existing)+len(plan.Create)-len(plan.Delete), len(plan.Create), len(plan.Delete))
+}
+
+// migrateE starts a live migration of each running VM of a run for the
+// "migrate" subcommand.
+func migrateE(cmd *cobra.Command, args []string) (err error) {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	targetRunID, _ := cmd.Flags().GetString("run-id")
+	waitForMigrations, _ := cmd.Flags().GetBool("wait")
+	timeout, _ := cmd.Flags().GetDuration("migration-timeout")
+	if timeout <= 0 {
+		return fmt.Errorf("migration-timeout must be positive, got %s", timeout)
+	}
+
+	auditor, err := initAuditor(cmd, cfg)
+	if err != nil {
+		return fmt.Errorf("initializing auditor: %w", err)
+	}
+	defer auditor.Close()
+
+	ctx := context.Background()
+
+	// The migrate execution is linked to the run it migrates
+	auditor, execID, _ := startExecution(ctx, cmd, auditor, "migrate", cfg)
+	defer func() {
+		if err != nil {
+			_ = auditor.CompleteExecution(ctx, execID, "failed", err.Error())
+		}
+	}()
+	_ = auditor.LinkCleanupToRuns(ctx, execID, []string{targetRunID})
+
+	c, err := cluster.ConnectWithContext(cfg.KubeconfigPath, cfg.KubeContext)
+	if err != nil {
+		return fmt.Errorf("connecting to cluster: %w", err)
+	}
+
+	namespaces, err := runNamespaces(ctx, c, cfg)
+	if err != nil {
+		return err
+	}
+	// names lists the migrations in creation order; byNamespace groups them
+	// for the wait.
+	var names []string
+	byNamespace := make(map[string][]string)
+	vmOf := make(map[string]string)
+	for _, ns := range namespaces {
+		var result *migrate.Result
+		result, err = migrate.Start(ctx, c, ns, targetRunID)
+		for _, vmName := range result.Skipped {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: VM %s has no running VMI; skipped\n", vmName)
+		}
+		for _, m := range result.Started {
+			fmt.Fprintf(cmd.OutOrStdout(), "Migration %s created for VM %s\n", m.Name, m.VMName)
+			_, _ = auditor.RecordResource(ctx, execID, audit.ResourceRecord{
+				ResourceType: "VirtualMachineInstanceMigration",
+				ResourceName: m.Name,
+				Namespace:    ns,
+			})
+			_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+				EventType: "migration_created",
+				Message:   fmt.Sprintf("Migration %s created for VM %s", m.Name, m.VMName),
+			})
+			names = append(names, m.Name)
+			byNamespace[ns] = append(byNamespace[ns], m.Name)
+			vmOf[m.Name] = m.VMName
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(names) == 0 {
+		err = fmt.Errorf("run %s has no running VMs to migrate in namespace %s", targetRunID, strings.Join(namespaces, ", "))
+		return err
+	}
+
+	if waitForMigrations {
+		fmt.Fprintf(cmd.OutOrStdout(), "Waiting for %d migrations to complete (timeout: %s)...\n", len(names), timeout)
+		results := make(map[string]error, len(names))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for ns, nsNames := range byNamespace {
+			wg.Add(1)
+			go func(ns string, nsNames []string) {
+				defer wg.Done()
+				nsResults := wait.WaitForAllMigrations(ctx, c, nsNames, ns, timeout, constants.DefaultPollInterval)
+				mu.Lock()
+				defer mu.Unlock()
+				maps.Copy(results, nsResults)
+			}(ns, nsNames)
+		}
+		wg.Wait()
+
+		failures := 0
+		for _, name := range names {
+			if results[name] != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "VM %s: %v\n", vmOf[name], results[name])
+				failures++
+				_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+					EventType:   "migration_failed",
+					Message:     fmt.Sprintf("Migration %s of VM %s did not complete", name, vmOf[name]),
+					ErrorDetail: results[name].Error(),
+				})
+			} else {
+				_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+					EventType: "migration_succeeded",
+					Message:   fmt.Sprintf("Migration %s of VM %s succeeded", name, vmOf[name]),
+				})
+			}
+		}
+		if failures > 0 {
+			err = fmt.Errorf("%d of %d migrations failed", failures, len(names))
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "All %d migrations succeeded\n", len(names))
+	}
+
+	_ = auditor.CompleteExecution(ctx, execID, "success", "")
+	err = nil // clear for defer
+	return nil
+}
+
+// startE boots the VMs of an existing run for the "start" subcommand.
+func startE(cmd *cobra.Command, args []string) (err error) {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	targetRunID, _ := cmd.Flags().GetString("run-id")
+
+	auditor, err := initAuditor(cmd, cfg)
+	if err != nil {
+		return fmt.Errorf("initializing auditor: %w", err)
+	}
+	defer auditor.Close()
+
+	ctx := context.Background()
+
+	// The start execution is linked to the run it starts
+	auditor, execID, _ := startExecution(ctx, cmd, auditor, "start", cfg)
+	defer func() {
+		if err != nil {
+			_ = auditor.CompleteExecution(ctx, execID, "failed", err.Error())
+		}
+	}()
+	_ = auditor.LinkCleanupToRuns(ctx, execID, []string{targetRunID})
+
+	c, err := cluster.ConnectWithContext(cfg.KubeconfigPath, cfg.KubeContext)
+	if err != nil {
+		return fmt.Errorf("connecting to cluster: %w", err)
+	}
+
+	namespaces, err := runNamespaces(ctx, c, cfg)
+	if err != nil {
+		return err
+	}
+	started, err := startRunVMs(ctx, c, namespaces, targetRunID)
+	for _, vmName := range started {
+		fmt.Fprintf(cmd.OutOrStdout(), "VM %s started\n", vmName)
+		_ = auditor.RecordEvent(ctx, execID, audit.EventRecord{
+			EventType: "vm_started",
+			Message:   fmt.Sprintf("VM %s started", vmName),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if len(started) == 0 {
+		err = fmt.Errorf("run %s has no VMs in namespace %s", targetRunID, strings.Join(namespaces, ", "))
+		return err
+	}
+
+	_ = auditor.CompleteExecution(ctx, execID, "success", "")
+	err = nil // clear for defer
+	return nil
+}
+
+// startRunVMs sets spec.running on each VM of the run in namespaces, in
+// namespace then name order, and returns the names of the VMs it started. On
+// error, the VMs started before it are still returned.
+func startRunVMs(ctx context.Context, c client.Client, namespaces []string, runID string) ([]string, error) {
+	var started []string
+	for _, namespace := range namespaces {
+		vms, err := vm.ListVMs(ctx, c, namespace, map[string]string{
+			constants.LabelManagedBy: constants.ManagedByValue,
+			constants.LabelRunID:     runID,
+		})
+		if err != nil {
+			return started, err
+		}
+		sort.Slice(vms, func(i, j int) bool { return vms[i].Name < vms[j].Name })
+
+		for _, v := range vms {
+			if err := vm.StartVM(ctx, c, v.Name, namespace); err != nil {
+				return started, err
+			}
+			started = append(started, v.Name)
+		}
+	}
+	return started, nil
+}
+
+// describeE prints one workload's rendered settings for the "describe"
+// subcommand.
+func describeE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	name, _ := cmd.Flags().GetString("workload")
+	role, _ := cmd.Flags().GetString("role")
+	noRedact, _ := cmd.Flags().GetBool("no-redact")
+	return describeWorkload(cmd.OutOrStdout(), cfg, name, role, !noRedact)
+}
+
+// describeWorkload writes the VM resources, image, extra disks and volumes,
+// data volume templates, Service, and cloud-init userdata of the named
+// workload to w. For a multi-VM workload, the userdata of role, or of every
+// role when role is empty, is written. When redact is set, passwords and
+// keys in the userdata are masked.
+func describeWorkload(w io.Writer, cfg *config.Config, name, role string, redact bool) error {
+	registry, registryOpts, err := newRegistry(cfg)
+	if err != nil {
+		return err
+	}
+	wl, err := registry.Get(name, cfg.EffectiveWorkload(name, 0), registryOpts...)
+	if err != nil {
+		return fmt.Errorf("creating workload %q: %w", name, err)
+	}
+
+	userdata := make(map[string]string)
+	var roles []string
+	if multiVM, ok := wl.(workloads.MultiVMWorkload); ok {
+		for _, rc := range multiVM.RoleCounts() {
+			roles = append(roles, rc.Role)
+		}
+		if role != "" && !slices.Contains(roles, role) {
+			return fmt.Errorf("workload %q has no role %q; roles: %s", name, role, strings.Join(roles, ", "))
+		}
+		if role != "" {
+			roles = []string{role}
+		}
+		for _, r := range roles {
+			if userdata[r], err = multiVM.UserdataForRole(r, cfg.Namespace); err != nil {
+				return fmt.Errorf("generating cloud-init for %q role %q: %w", name, r, err)
+			}
+		}
+	} else {
+		if role != "" {
+			return fmt.Errorf("workload %q has no roles; --role only applies to multi-VM workloads", name)
+		}
+		roles = []string{""}
+		if userdata[""], err = wl.CloudInitUserdata(); err != nil {
+			return fmt.Errorf("generating cloud-init for %q: %w", name, err)
+		}
+	}
+
+	res := wl.VMResources()
+	fmt.Fprintf(w, "Workload:  %s\n", name)
+	fmt.Fprintf(w, "VMs:       %d\n", wl.VMCount())
+	fmt.Fprintf(w, "CPU cores: %d\n", res.CPUCores)
+	fmt.Fprintf(w, "Memory:    %s\n", res.Memory)
+	for _, r := range roles {
+		label := "Image:"
+		if r != "" {
+			label = fmt.Sprintf("Image (%s):", r)
+		}
+		fmt.Fprintf(w, "%-10s %s\n", label, cfg.EffectiveImage(name, r))
+	}
+
+	sections := []struct {
+		title string
+		value interface{}
+		empty bool
+	}{
+		{"Extra disks", wl.ExtraDisks(), len(wl.ExtraDisks()) == 0},
+		{"Extra volumes", wl.ExtraVolumes(), len(wl.ExtraVolumes()) == 0},
+		{"Data volume templates", wl.DataVolumeTemplates(), len(wl.DataVolumeTemplates()) == 0},
+		{"Service", wl.ServiceSpec(), wl.ServiceSpec() == nil},
+	}
+	for _, s := range sections {
+		if s.empty {
+			fmt.Fprintf(w, "\n# %s: none\n", s.title)
+			continue
+		}
+		data, err := sigyaml.Marshal(s.value)
+		if err != nil {
+			return fmt.Errorf("marshaling %s of %q: %w", strings.ToLower(s.title), name, err)
+		}
+		fmt.Fprintf(w, "\n# %s\n%s", s.title, data)
+	}
+
+	for _, r := range roles {
+		ud := userdata[r]
+		if redact {
+			ud = cloudinit.Redact(ud)
+		}
+		title := "Cloud-init userdata"
+		if r != "" {
+			title = fmt.Sprintf("Cloud-init userdata (role %s)", r)
+		}
+		fmt.Fprintf(w, "\n# %s\n%s", title, ud)
+		if !strings.HasSuffix(ud, "\n") {
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
+
+// preflightE lists the packages each selected workload installs for the
+// "preflight" subcommand.
+func preflightE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	image, _ := cmd.Flags().GetString("image")
+	requested, _ := cmd.Flags().GetStringSlice("workloads")
+	names, _ := selectWorkloads(cfg, requested)
+	return printRequiredPackages(cmd.OutOrStdout(), cfg, names, image)
+}
+
+// printRequiredPackages writes a table of the image and required packages of
+// each named workload to w. A non-empty image replaces each workload's
+// configured image.
+func printRequiredPackages(w io.Writer, cfg *config.Config, names []string, image string) error {
+	registry, registryOpts, err := newRegistry(cfg)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "WORKLOAD\tIMAGE\tPACKAGES")
+	for _, name := range names {
+		wl, err := registry.Get(name, cfg.EffectiveWorkload(name, 0), registryOpts...)
+		if err != nil {
+			return fmt.Errorf("creating workload %q: %w", name, err)
+		}
+		img := image
+		if img == "" {
+			img = cfg.EffectiveImage(name, "")
+		}
+		packages := "-"
+		if pkgs := wl.RequiredPackages(); len(pkgs) > 0 {
+			packages = strings.Join(pkgs, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, img, packages)
+	}
+	return tw.Flush()
+}
+
+// statusE reports the live phase of managed VMs for the "status" subcommand.
+func statusE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	ctx := context.Background()
+	targetRunID, _ := cmd.Flags().GetString("run-id")
+
+	// Cross-reference the audit database when one is configured. A SQLite
+	// file is only opened if it already exists; status is read-only and
+	// should not create one.
+	var timestamps status.TimestampLookup
+	noAudit, _ := cmd.Flags().GetBool("no-audit")
+	if !noAudit && cfg.AuditEnabled {
+		reader, err := openAuditReader(cmd, cfg)
+		if err != nil {
+			return err
+		}
+		if reader != nil {
+			defer reader.Close()
+			timestamps = reader
+		}
+	}
+	columns, err := selectColumns(cmd, statusColumns, defaultStatusColumns(timestamps != nil))
+	if err != nil {
+		return err
+	}
+
+	c, err := cluster.ConnectWithContext(cfg.KubeconfigPath, cfg.KubeContext)
+	if err != nil {
+		return fmt.Errorf("connecting to cluster: %w", err)
+	}
+
+	namespaces, err := runNamespaces(ctx, c, cfg)
+	if err != nil {
+		return err
+	}
+	var statuses []status.VMStatus
+	for _, ns := range namespaces {
+		nsStatuses, err := status.Collect(ctx, c, ns, targetRunID, timestamps)
+		if err != nil {
+			return fmt.Errorf("collecting VM status: %w", err)
+		}
+		statuses = append(statuses, nsStatuses...)
+	}
+
+	printStatus(cmd.OutOrStdout(), statuses, columns)
+
+	if failed := status.CountFailed(statuses); failed > 0 {
+		return fmt.Errorf("%d VM(s) in Failed phase", failed)
+	}
+	return nil
+}
+
+// listE lists the runs found on the cluster for the "list" subcommand.
+func listE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	namespace := cfg.Namespace
+	allNamespaces, _ := cmd.Flags().GetBool("all-namespaces")
+	if allNamespaces {
+		namespace = ""
+	}
+	columns, err := selectColumns(cmd, runColumns, defaultRunColumns(allNamespaces))
+	if err != nil {
+		return err
+	}
+
+	c, err := cluster.ConnectWithContext(cfg.KubeconfigPath, cfg.KubeContext)
+	if err != nil {
+		return fmt.Errorf("connecting to cluster: %w", err)
+	}
+
+	runs, err := discover.Runs(context.Background(), c, namespace)
+	if err != nil {
+		return fmt.Errorf("discovering runs: %w", err)
+	}
+	printRuns(cmd.OutOrStdout(), runs, columns)
+	return nil
+}
+
+// auditReader is an audit database opened by a read-only command.
+type auditReader interface {
+	audit.Auditor
+	status.TimestampLookup
+}
+
+// openAuditReader opens the configured audit database for reading. It returns
+// nil when the SQLite file does not exist yet, since read-only commands should
+// not create one.
+func openAuditReader(cmd *cobra.Command, cfg *config.Config) (auditReader, error) {
+	if dsn := auditDSN(cmd, cfg); dsn != "" {
+		auditor, err := audit.NewPostgresAuditor(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("opening audit database: %w", err)
+		}
+		return auditor, nil
+	}
+	dbPath, err := auditDBPath(cmd, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if !fileExists(dbPath) {
+		return nil, nil
+	}
+	auditor, err := audit.NewSQLiteAuditor(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit database: %w", err)
+	}
+	return auditor, nil
+}
+
+// auditListE lists past executions recorded in the audit database.
+func auditListE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	filter := audit.ExecutionFilter{}
+	filter.Status, _ = cmd.Flags().GetString("status")
+	if cmd.Flags().Changed("namespace") {
+		filter.Namespace = cfg.Namespace
+	}
+	format, _ := cmd.Flags().GetString("format")
+
+	switch filter.Status {
+	case "", "in_progress", "success", "failed":
+	default:
+		return fmt.Errorf("invalid --status %q: must be in_progress, success, or failed", filter.Status)
+	}
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid --format %q: must be table or json", format)
+	}
+	if format == "json" && cmd.Flags().Changed("columns") {
+		return fmt.Errorf("--columns applies only to --format table")
+	}
+	columns, err := selectColumns(cmd, executionColumns, executionColumns)
+	if err != nil {
+		return err
+	}
+
+	reader, err := openAuditReader(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	var summaries []audit.ExecutionSummary
+	if reader != nil {
+		defer reader.Close()
+		summaries, err = reader.ListExecutions(context.Background(), filter)
+		if err != nil {
+			return fmt.Errorf("listing executions: %w", err)
+		}
+	}
+
+	if format == "json" {
+		if summaries == nil {
+			summaries = []audit.ExecutionSummary{}
+		}
+		data, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling executions: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+	printExecutions(cmd.OutOrStdout(), summaries, columns)
+	return nil
+}
+
+// auditCompareE compares two runs recorded in the audit database.
+func auditCompareE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	format, _ := cmd.Flags().GetString("format")
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid --format %q: must be table or json", format)
+	}
+
+	reader, err := openAuditReader(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	if reader == nil {
+		return fmt.Errorf("no audit database found")
+	}
+	defer reader.Close()
+
+	ctx := context.Background()
+	runA, err := reader.GetExecution(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	runB, err := reader.GetExecution(ctx, args[1])
+	if err != nil {
+		return err
+	}
+	diffs := audit.CompareExecutions(runA, runB)
+
+	if format == "json" {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"run_a":  runA.RunID,
+			"run_b":  runB.RunID,
+			"fields": diffs,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling comparison: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+	printComparison(cmd, runA.RunID, runB.RunID, diffs)
+	return nil
+}
+
+// auditEventsE prints the events of a run, following new ones with --follow.
+func auditEventsE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	runID, _ := cmd.Flags().GetString("run-id")
+	follow, _ := cmd.Flags().GetBool("follow")
+
+	reader, err := openAuditReader(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	if reader == nil {
+		return fmt.Errorf("no audit database found")
+	}
+	defer reader.Close()
+
+	return tailEvents(cmd.Context(), reader, runID, follow, constants.EventsPollInterval, cmd.OutOrStdout())
+}
+
+// tailEvents writes the events of runID to w. With follow it then polls
+// every interval for events after the last one written until ctx is done,
+// which ends it without error.
+func tailEvents(ctx context.Context, a audit.Auditor, runID string, follow bool, interval time.Duration, w io.Writer) error {
+	var lastID int64
+	for {
+		events, err := a.ListEvents(ctx, runID, lastID)
+		if err != nil {
+			if follow && ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("listing events: %w", err)
+		}
+		printEvents(w, events)
+		for _, e := range events {
+			lastID = max(lastID, e.ID)
+		}
+		if !follow {
+			if lastID == 0 {
+				fmt.Fprintf(w, "No events found for run %s\n", runID)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// printEvents writes one line per event: time, type, VM, and the message,
+// followed by the error detail when there is one.
+func printEvents(w io.Writer, events []audit.EventRow) {
+	for _, e := range events {
+		vmName := e.VMName
+		if vmName == "" {
+			vmName = "-"
+		}
+		line := fmt.Sprintf("%s  %-22s  %-28s  %s", e.OccurredAt, e.EventType, vmName, e.Message)
+		if e.ErrorDetail != "" {
+			line += " (" + e.ErrorDetail + ")"
+		}
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+}
+
+// auditExportE writes audit_log, and with --include-vms vm_details, to CSV.
+func auditExportE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	format, _ := cmd.Flags().GetString("format")
+	out, _ := cmd.Flags().GetString("out")
+	includeVMs, _ := cmd.Flags().GetBool("include-vms")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+
+	if format != "csv" {
+		return fmt.Errorf("invalid --format %q: must be csv", format)
+	}
+	if includeVMs && out == "" {
+		return fmt.Errorf("--include-vms writes a second file and requires --out")
+	}
+	since, err := parseSince(sinceFlag)
+	if err != nil {
+		return err
+	}
+
+	reader, err := openAuditReader(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	if reader == nil {
+		return fmt.Errorf("no audit database found")
+	}
+	defer reader.Close()
+
+	ctx := context.Background()
+	if err := exportCSV(ctx, reader, audit.ExportAuditLog, since, out, cmd.OutOrStdout()); err != nil {
+		return err
+	}
+	if includeVMs {
+		return exportCSV(ctx, reader, audit.ExportVMDetails, since, vmsExportPath(out), nil)
+	}
+	return nil
+}
+
+// exportCSV writes table as CSV to the file at path, or to stdout when path
+// is empty.
+func exportCSV(ctx context.Context, a audit.Auditor, table, since, path string, stdout io.Writer) (err error) {
+	w := stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", path, err)
+		}
+		defer func() {
+			if cerr := f.Close(); cerr != nil && err == nil {
+				err = fmt.Errorf("writing %s: %w", path, cerr)
+			}
+		}()
+		w = f
+	}
+
+	cw := csv.NewWriter(w)
+	if err := a.ExportRows(ctx, table, since, cw); err != nil {
+		return fmt.Errorf("exporting %s: %w", table, err)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("writing %s: %w", table, err)
+	}
+	return nil
+}
+
+// vmsExportPath returns the file --include-vms writes next to out: runs.csv
+// becomes runs-vms.csv.
+func vmsExportPath(out string) string {
+	return strings.TrimSuffix(out, ".csv") + "-vms.csv"
+}
+
+// parseSince normalizes a --since date (2006-01-02), RFC 3339 time, or age
+// before now (7d, 12h) to the UTC RFC 3339 form the audit database stores
+// timestamps in, so the two compare as strings. An empty value disables the
+// filter.
+func parseSince(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format(time.RFC3339), nil
+		}
+	}
+	if age, ok := parseAge(s); ok {
+		return time.Now().Add(-age).UTC().Format(time.RFC3339), nil
+	}
+	return "", fmt.Errorf("invalid --since %q: must be a date (2006-01-02), RFC 3339 time, or age such as 7d or 12h", s)
+}
+
+// parseAge parses a positive Go duration, or a whole number of days with a
+// "d" suffix, which time.ParseDuration does not accept.
+func parseAge(s string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, false
+		}
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// auditStatsE prints aggregate statistics over the audit database.
+func auditStatsE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	format, _ := cmd.Flags().GetString("format")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid --format %q: must be table or json", format)
+	}
+	since, err := parseSince(sinceFlag)
+	if err != nil {
+		return err
+	}
+
+	reader, err := openAuditReader(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	var a audit.Auditor = audit.NoOpAuditor{}
+	if reader != nil {
+		defer reader.Close()
+		a = reader
+	}
+	stats, err := a.AggregateStats(context.Background(), since)
+	if err != nil {
+		return fmt.Errorf("aggregating executions: %w", err)
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling stats: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+	printStats(cmd.OutOrStdout(), stats)
+	return nil
+}
+
+// printStats writes the totals of stats, then a table of its workloads.
+func printStats(w io.Writer, stats *audit.Stats) {
+	statuses := make([]string, 0, len(stats.ByStatus))
+	for status := range stats.ByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	counts := make([]string, len(statuses))
+	for i, status := range statuses {
+		counts[i] = fmt.Sprintf("%s %d", status, stats.ByStatus[status])
+	}
+	executions := strconv.Itoa(stats.Executions)
+	if len(counts) > 0 {
+		executions += " (" + strings.Join(counts, ", ") + ")"
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if stats.Since != "" {
+		fmt.Fprintf(tw, "Since:\t%s\n", stats.Since)
+	}
+	fmt.Fprintf(tw, "Executions:\t%s\n", executions)
+	fmt.Fprintf(tw, "Average duration:\t%s\n", time.Duration(stats.AverageDurationSeconds)*time.Second)
+	fmt.Fprintf(tw, "VMs created:\t%d\n", stats.VMsCreated)
+	fmt.Fprintf(tw, "VMs deleted:\t%d\n", stats.VMsDeleted)
+	_ = tw.Flush()
+
+	if len(stats.Workloads) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	_ = table.Render(w, workloadStatsColumns, stats.Workloads)
+}
+
+// workloadStatsColumns are the columns of the audit stats workload table.
+var workloadStatsColumns = []table.Column[audit.WorkloadStats]{
+	{Name: "workload", Header: "WORKLOAD", Value: func(s audit.WorkloadStats) string { return s.Type }},
+	{Name: "executions", Header: "EXECUTIONS", Value: func(s audit.WorkloadStats) string { return strconv.Itoa(s.Executions) }},
+	{Name: "succeeded", Header: "SUCCEEDED", Value: func(s audit.WorkloadStats) string { return strconv.Itoa(s.Succeeded) }},
+	{Name: "failed", Header: "FAILED", Value: func(s audit.WorkloadStats) string { return strconv.Itoa(s.Failed) }},
+	{Name: "vms", Header: "VMS", Value: func(s audit.WorkloadStats) string { return strconv.Itoa(s.VMs) }},
+}
+
+// auditSchemaE prints the audit DDL for the selected or configured backend.
+func auditSchemaE(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	dialect, _ := cmd.Flags().GetString("dialect")
+	if dialect == "" {
+		dialect = audit.DialectSQLite
+		if auditDSN(cmd, cfg) != "" {
+			dialect = audit.DialectPostgres
+		}
+	}
+	ddl, err := audit.Schema(dialect)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(cmd.OutOrStdout(), strings.TrimPrefix(ddl, "\n"))
+	return err
+}
+
+// printComparison outputs two runs side by side, marking changed fields.
+func printComparison(cmd *cobra.Command, runA, runB string, diffs []audit.FieldDiff) {
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "\tFIELD\t%s\t%s\n", runA, runB)
+	for _, d := range diffs {
+		marker := " "
+		if d.Changed {
+			marker = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", marker, d.Field, d.A, d.B)
+	}
+	_ = tw.Flush()
+}
+
+// printExecutions outputs a table of past executions.
+func printExecutions(w io.Writer, summaries []audit.ExecutionSummary, columns []table.Column[audit.ExecutionSummary]) {
+	if len(summaries) == 0 {
+		fmt.Fprintln(w, "No executions found")
+		return
+	}
+	_ = table.Render(w, columns, summaries)
+}
+
+// executionColumns are the audit list columns, all shown by default.
+var executionColumns = []table.Column[audit.ExecutionSummary]{
+	{Name: "run-id", Header: "RUN ID", Value: func(s audit.ExecutionSummary) string { return s.RunID }},
+	{Name: "command", Header: "COMMAND", Value: func(s audit.ExecutionSummary) string { return s.Command }},
+	{Name: "namespace", Header: "NAMESPACE", Value: func(s audit.ExecutionSummary) string { return s.Namespace }},
+	{Name: "status", Header: "STATUS", Value: func(s audit.ExecutionSummary) string { return s.Status }},
+	{Name: "vms", Header: "VMS", Value: func(s audit.ExecutionSummary) string { return strconv.Itoa(s.VMCount) }},
+	{Name: "started", Header: "STARTED", Value: func(s audit.ExecutionSummary) string { return s.StartedAt }},
+	{Name: "duration", Header: "DURATION", Value: func(s audit.ExecutionSummary) string {
+		if s.CompletedAt == "" {
+			return "-"
+		}
+		return (time.Duration(s.DurationSeconds) * time.Second).String()
+	}},
+}
+
+// selectColumns returns the columns named by the --columns flag, or
+// defaults when it is not set.
+func selectColumns[T any](cmd *cobra.Command, all, defaults []table.Column[T]) ([]table.Column[T], error) {
+	if !cmd.Flags().Changed("columns") {
+		return defaults, nil
+	}
+	names, _ := cmd.Flags().GetStringSlice("columns")
+	columns, err := table.Select(all, names)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --columns: %w", err)
+	}
+	return columns, nil
+}
+
+// fileExists reports whether path exists.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// printStatus outputs a table of VM phases.
+func printStatus(w io.Writer, statuses []status.VMStatus, columns []table.Column[status.VMStatus]) {
+	if len(statuses) == 0 {
+		fmt.Fprintln(w, "No managed VMs found")
+		return
+	}
+	_ = table.Render(w, columns, statuses)
+}
+
+// statusColumns are the status columns. The audit timestamps, last, are
+// shown by default only when an audit database is available.
+var statusColumns = []table.Column[status.VMStatus]{
+	{Name: "name", Header: "NAME", Value: func(s status.VMStatus) string { return s.Name }},
+	{Name: "component", Header: "COMPONENT", Value: func(s status.VMStatus) string { return orDash(s.Component) }},
+	{Name: "role", Header: "ROLE", Value: func(s status.VMStatus) string { return orDash(s.Role) }},
+	{Name: "phase", Header: "PHASE", Value: func(s status.VMStatus) string { return orDash(string(s.Phase)) }},
+	{Name: "vm-ready", Header: "VM-READY", Value: func(s status.VMStatus) string { return strconv.FormatBool(s.VMReady) }},
+	{Name: "agent", Header: "AGENT", Value: func(s status.VMStatus) string { return strconv.FormatBool(s.AgentConnected) }},
+	{Name: "node", Header: "NODE", Value: func(s status.VMStatus) string { return orDash(s.Node) }},
+	{Name: "message", Header: "MESSAGE", Value: func(s status.VMStatus) string { return orDash(s.Message) }},
+	{Name: "created", Header: "CREATED", Value: func(s status.VMStatus) string { return orDash(s.CreatedAt) }},
+	{Name: "ready", Header: "READY", Value: func(s status.VMStatus) string { return orDash(s.ReadyAt) }},
+}
+
+// defaultStatusColumns returns the status columns shown without --columns.
+func defaultStatusColumns(withTimestamps bool) []table.Column[status.VMStatus] {
+	if withTimestamps {
+		return statusColumns
+	}
+	return statusColumns[:len(statusColumns)-2]
+}
+
+// printRuns renders the discovered runs as a table.
+func printRuns(w io.Writer, runs []discover.Run, columns []table.Column[discover.Run]) {
+	if len(runs) == 0 {
+		fmt.Fprintln(w, "No runs found")
+		return
+	}
+	_ = table.Render(w, columns, runs)
+}
+
+// runColumns are the list columns. The namespace is shown by default only
+// for runs from all namespaces.
+var runColumns = []table.Column[discover.Run]{
+	{Name: "namespace", Header: "NAMESPACE", Value: func(r discover.Run) string { return r.Namespace }},
+	{Name: "run-id", Header: "RUN-ID", Value: func(r discover.Run) string { return r.RunID }},
+	{Name: "vms", Header: "VMS", Value: func(r discover.Run) string { return strconv.Itoa(r.VMs) }},
+	{Name: "services", Header: "SERVICES", Value: func(r discover.Run) string { return strconv.Itoa(r.Services) }},
+	{Name: "secrets", Header: "SECRETS", Value: func(r discover.Run) string { return strconv.Itoa(r.Secrets) }},
+	{Name: "components", Header: "COMPONENTS", Value: func(r discover.Run) string { return orDash(strings.Join(r.Components, ",")) }},
+	{Name: "created", Header: "CREATED", Value: func(r discover.Run) string {
+		if r.CreatedAt.IsZero() {
+			return "-"
+		}
+		return r.CreatedAt.UTC().Format(time.RFC3339)
+	}},
+}
+
+// defaultRunColumns returns the list columns shown without --columns.
+func defaultRunColumns(withNamespace bool) []table.Column[discover.Run] {
+	if withNamespace {
+		return runColumns
+	}
+	return runColumns[1:]
+}
+
+// orDash returns s, or "-" when s is empty, for table output.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// dumpConfig prints the resolved configuration, including the effective
+// settings of each selected workload, without touching the cluster.
+func dumpConfig(cmd *cobra.Command, cfg *config.Config, format string) error {
+	requested, _ := cmd.Flags().GetStringSlice("workloads")
+	workloadNames, _ := selectWorkloads(cfg, requested)
+	vmCountFlag, _ := cmd.Flags().GetInt("vm-count")
+
+	effective := make(map[string]config.WorkloadConfig, len(workloadNames))
+	for _, name := range workloadNames {
+		effective[name] = cfg.EffectiveWorkload(name, vmCountFlag)
+	}
+
+	data, err := config.Dump(cfg, effective, format)
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(data)
+	return err
+}
+
+// printDryRun outputs VM specs in YAML without connecting to a cluster.
+// When redact is set, passwords and keys in the userdata are masked.
+func printDryRun(plans []vmPlan, redact bool) error {
+	fmt.Println("--- Dry Run ---")
+	fmt.Printf("Total VMs to create: %d\n\n", len(plans))
+
+	for _, p := range plans {
+		spec := *p.vmSpec
+		if redact {
+			spec.CloudInitUserdata = cloudinit.Redact(spec.CloudInitUserdata)
+		}
+		vmObj := vm.BuildVMSpec(spec)
+		data, err := sigyaml.Marshal(vmObj)
+		if err != nil {
+			return fmt.Errorf("marshaling VM spec for %q: %w", p.vmName, err)
+		}
+		fmt.Printf("# VM: %s (workload: %s)\n", p.vmName, p.component)
+		fmt.Println(string(data))
+		fmt.Println("---")
+	}
+	return nil
+}
+
+// deploymentSummary is what "run" reports after a successful deployment.
+type deploymentSummary struct {
+	RunID     string `json:"run_id"`
+	Namespace string `json:"namespace"`
+	// Namespaces lists the namespace of each workload with
+	// --namespace-per-workload.
+	Namespaces  []string      `json:"namespaces,omitempty"`
+	Images      []string      `json:"images"`
+	Counts      summaryCounts `json:"counts"`
+	VMs         []vmSummary   `json:"vms"`
+	StartedAt   time.Time     `json:"started_at"`
+	CompletedAt time.Time     `json:"completed_at"`
+}
+
+// summaryCounts holds the number of each resource kind a run created.
+type summaryCounts struct {
+	VMs      int `json:"vms"`
+	Services int `json:"services"`
+	Secrets  int `json:"secrets"`
+}
+
+// vmSummary identifies one VM of a run.
+type vmSummary struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Component string `json:"component"`
+	Role      string `json:"role,omitempty"`
+	Image     string `json:"image"`
+}
+
+// dryRunListing is the JSON form of a dry run: the VMs that would be created.
+type dryRunListing struct {
+	RunID     string      `json:"run_id"`
+	Namespace string      `json:"namespace"`
+	VMs       []vmSummary `json:"vms"`
+}
+
+// planSummaries returns the summary of each planned VM, in plan order.
+func planSummaries(plans []vmPlan) []vmSummary {
+	vms := make([]vmSummary, 0, len(plans))
+	for _, p := range plans {
+		vms = append(vms, vmSummary{
+			Name:      p.vmName,
+			Namespace: p.vmSpec.Namespace,
+			Component: p.component,
+			Role:      p.role,
+			Image:     p.vmSpec.ContainerDiskImage,
+		})
+	}
+	return vms
+}
+
+// planImages returns the distinct container disk images of the planned VMs,
+// in plan order.
+func planImages(plans []vmPlan) []string {
+	images := []string{}
+	for _, p := range plans {
+		if !slices.Contains(images, p.vmSpec.ContainerDiskImage) {
+			images = append(images, p.vmSpec.ContainerDiskImage)
+		}
+	}
+	return images
+}
+
+// writeJSON writes v to w as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling output: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// renderSummary writes a deployment summary to w as a table or as JSON.
+func renderSummary(w io.Writer, summary deploymentSummary, format string) error {
+	switch format {
+	case "json":
+		return writeJSON(w, summary)
+	case "table":
+		fmt.Fprintln(w, strings.Repeat("=", 50))
+		fmt.Fprintln(w, "Deployment Summary")
+		fmt.Fprintln(w, strings.Repeat("=", 50))
+		fmt.Fprintf(w, "Run ID:       %s\n", summary.RunID)
+		if len(summary.Namespaces) > 0 {
+			fmt.Fprintf(w, "Namespaces:   %s\n", strings.Join(summary.Namespaces, ", "))
+		} else {
+			fmt.Fprintf(w, "Namespace:    %s\n", summary.Namespace)
+		}
+		fmt.Fprintf(w, "VMs created:  %d\n", summary.Counts.VMs)
+		fmt.Fprintf(w, "Services:     %d\n", summary.Counts.Services)
+		fmt.Fprintf(w, "Secrets:      %d\n", summary.Counts.Secrets)
+		if len(summary.Images) > 1 {
+			fmt.Fprintf(w, "Images:       %s\n", strings.Join(summary.Images, ", "))
+		} else {
+			fmt.Fprintf(w, "Image:        %s\n", strings.Join(summary.Images, ""))
+		}
+		fmt.Fprintln(w, strings.Repeat("=", 50))
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q: must be table or json", format)
+	}
 }
@@ -7,6 +7,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -18,8 +20,10 @@ import (
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	sigyaml "sigs.k8s.io/yaml"
 
 	"github.com/opdev/virtwork/internal/cleanup"
+	"github.com/opdev/virtwork/internal/cloudinit"
 	"github.com/opdev/virtwork/internal/cluster"
 	"github.com/opdev/virtwork/internal/config"
 	"github.com/opdev/virtwork/internal/constants"
@@ -42,6 +46,8 @@ func newRootCmd() *cobra.Command {
 	pf.String("kubeconfig", "", "Path to kubeconfig file")
 	pf.String("config", "", "Path to YAML config file")
 	pf.Bool("verbose", false, "Enable verbose output")
+	pf.String("output", "table", "Output format of the run summary and dry-run listing (table, json)")
+	pf.String("log-format", "text", "Format of run progress logs (text, json); json logs go to stderr")
 
 	runCmd := &cobra.Command{
 		Use:   "run",
@@ -51,19 +57,69 @@ func newRootCmd() *cobra.Command {
 		},
 	}
 	rf := runCmd.Flags()
-	rf.StringSlice("workloads", workloads.AllWorkloadNames, "Workloads to deploy (comma-separated)")
+	rf.StringSlice("workloads", workloads.AllWorkloadNames, "Workloads to deploy (comma-separated, or all)")
 	rf.Int("vm-count", 1, "Number of VMs per workload")
 	rf.Int("cpu-cores", 0, "CPU cores per VM")
 	rf.String("memory", "", "Memory per VM (e.g., 2Gi)")
 	rf.String("disk-size", "", "Data disk size")
 	rf.String("container-disk-image", "", "Container disk image for VMs")
+	rf.String("image-pull-secret", "", "Secret in the namespace used to pull container disk images from a private registry")
 	rf.Bool("dry-run", false, "Print specs without creating resources")
+	rf.Bool("no-redact", false, "Show passwords and SSH keys in dry-run output")
 	rf.Bool("no-wait", false, "Skip waiting for VM readiness")
 	rf.Int("timeout", 0, "Readiness timeout in seconds")
+	rf.Duration("stagger", 0, "Spread workload start times across each workload's VMs over this window (e.g., 2m)")
+	rf.Bool("skip-connect-check", false, "Skip pinging the API server before creating resources")
+	rf.Bool("skip-preflight", false, "Skip checking that the KubeVirt and CDI CRDs are installed before creating resources")
+	rf.Bool("check-quota", false, "Fail before creating resources if the planned VM requests exceed a ResourceQuota in the namespace")
+	rf.Int("create-concurrency", constants.DefaultCreateConcurrency, "Maximum VM creates in flight at once")
+	rf.Int("create-retries", constants.DefaultCreateRetries, "Retries of a VM create after transient API errors")
+	rf.Duration("create-backoff", constants.DefaultCreateBackoff, "Wait before the first VM create retry, doubling per retry up to 30s")
+	rf.Duration("deadline", 0, "Abort the run if connecting, creating, and waiting take longer than this (e.g., 30m; 0 = no deadline)")
+	rf.String("readiness-level", "", "When a VM counts as ready: phase (default), agent, or ready")
+	rf.Bool("workload-probes", false, "Gate disk, database, fs, network, redis, and web VM readiness on the workload service running")
 	rf.String("ssh-user", "", "SSH user for VMs")
 	rf.String("ssh-password", "", "SSH password for VMs")
 	rf.StringSlice("ssh-key", nil, "SSH authorized key (repeatable)")
 	rf.StringSlice("ssh-key-file", nil, "SSH key file path (repeatable)")
+	rf.StringToString("node-selector", nil, "Node label the VMs must be scheduled on (key=value, repeatable)")
+	rf.StringArray("toleration", nil, "Taint the VMs tolerate: key[=value][:effect] (repeatable)")
+	rf.StringArray("gpu", nil, "GPU device resource to pass through to each VM, e.g. nvidia.com/GA102GL_A10 (repeatable)")
+	rf.StringArray("multus-network", nil, "NetworkAttachmentDefinition ([namespace/]name) each VM gets a second NIC on (repeatable)")
+	rf.String("affinity-from-file", "", "YAML file holding a Kubernetes affinity for the VMs")
+	rf.String("anti-affinity-weight", "", "Keep each workload's VMs on separate nodes: required, preferred, or a preferred weight 1-100")
+	rf.Lookup("anti-affinity-weight").NoOptDefVal = "preferred"
+	rf.String("hugepages", "", "Back guest memory with hugepages of this size: 2Mi or 1Gi")
+	rf.String("memory-limit", "", "Memory limit per VM (e.g., 4Gi); at least --memory")
+	rf.String("cpu-limit", "", "CPU limit per VM (e.g., 2 or 1500m)")
+	rf.Bool("overcommit-guest-overhead", false, "Leave the virt-launcher memory overhead out of each VM's memory request")
+	rf.Bool("read-only-root", false, "Mount each guest's root filesystem read-only under an ephemeral overlay")
+	rf.Bool("network-policy", false, "Allow ingress to network workload servers only from their clients")
+	rf.Bool("namespace-per-workload", false, "Deploy each workload into its own namespace, <namespace>-<workload>")
+	rf.String("cloud-init-overlay", "", "Cloud-config YAML file merged into every workload's cloud-init")
+	rf.Bool("overlay-override", false, "Let --cloud-init-overlay values replace the workload's where both set one")
+	rf.String("data-source-url", "", "Registry image to populate disk and database data disks from (e.g., docker://quay.io/org/img)")
+	rf.String("data-source-pvc", "", "PVC ([namespace/]name) to clone disk and database data disks from")
+	rf.String("data-fs", "", "Share a PVC with disk and database VMs over virtiofs instead of a data disk: pvc=<name>")
+	rf.String("storage-class", "", "StorageClass for disk, database, and fs data disks (default: cluster default)")
+	rf.String("access-mode", "", "Access mode for data disks: ReadWriteOnce, ReadWriteMany, ReadOnlyMany, or ReadWriteOncePod")
+	rf.String("syslog-server", "", "Forward guest logs to this syslog server over TCP (host:port)")
+	rf.String("block-size", "", "Data disk block size: logical=N,physical=N or match-volume")
+	rf.String("metrics-file", "", "Write Prometheus text-format metrics for the run to this file")
+	rf.String("ready-report-file", "", "Write a JSON report of each VM's readiness outcome to this file")
+	rf.Bool("recreate-failed", false, "Delete and recreate, once, each VM whose VMI fails before becoming ready")
+	rf.Bool("fail-fast", false, "Stop waiting for the other VMs as soon as one fails its readiness wait")
+	rf.Bool("verify-ssh", false, "After the readiness wait, check that each VM's SSH port answers")
+	rf.String("on-ready-exec", "", "Shell command to run locally once all VMs are ready; gets VIRTWORK_RUN_ID, VIRTWORK_NAMESPACE, and VIRTWORK_VM_IPS")
+	rf.Bool("on-ready-exec-allow-failure", false, "Only warn, rather than fail the run, when the --on-ready-exec command fails")
+	rf.Bool("progress", false, "Show a single updating line of VM creation and readiness progress on a terminal")
+	rf.String("reuse-run-id", "", "Re-run into an existing run (UUID), creating only its missing VMs")
+	rf.Bool("update", false, "Update the memory, CPU, resources, and labels of VMs that already exist instead of leaving them")
+	rf.Bool("label-run-with-git", false, "Annotate created resources with the git SHA, branch, and build URL detected from CI env vars")
+	rf.String("git-sha", "", "Git SHA to annotate created resources with (overrides the detected one)")
+	rf.String("git-branch", "", "Git branch to annotate created resources with (overrides the detected one)")
+	rf.String("ci-url", "", "CI build URL to annotate created resources with (overrides the detected one)")
+	rf.Bool("start-stopped", false, "Create the VMs stopped; boot them later with \"virtwork start\"")
 
 	cleanupCmd := &cobra.Command{
 		Use:   "cleanup",
@@ -73,206 +129,1125 @@ func newRootCmd() *cobra.Command {
 		},
 	}
 	cleanupCmd.Flags().Bool("delete-namespace", false, "Also delete the namespace")
+	cleanupCmd.Flags().Int64("grace-period", -1, "Seconds to wait before force-deleting VMs and secrets (-1 uses the server default)")
+	cleanupCmd.Flags().String("propagation", "", "Deletion propagation for VMs and secrets: Foreground, Background, or Orphan")
+	cleanupCmd.Flags().Bool("force", false, "Force-delete VMs and secrets: no grace period and background propagation")
+	cleanupCmd.Flags().Int("cleanup-concurrency", constants.DefaultCleanupConcurrency, "Maximum deletions of each resource kind in flight at once")
+	cleanupCmd.Flags().StringToString("selector", nil, "Only delete resources that also carry this label: key=value (repeatable)")
+	cleanupCmd.Flags().Duration("older-than", 0, "Only delete resources created longer ago than this (e.g., 24h; 0 = any age)")
+	cleanupCmd.Flags().Bool("report-orphans", false, "Report cloud-init secrets without a VM and services without a backing VM instead of cleaning up")
+	cleanupCmd.Flags().Bool("delete-orphans", false, "Delete the orphans found by --report-orphans")
+	cleanupCmd.Flags().Bool("verify-cleanup-complete", false, "Exit non-zero if any managed resource still exists after deletion")
+	cleanupCmd.Flags().Duration("verify-timeout", constants.DefaultVerifyCleanupTimeout, "How long --verify-cleanup-complete waits for deleted resources to disappear")
+	cleanupCmd.Flags().Bool("dry-run", false, "List the resources that would be deleted without deleting anything")
+	cleanupCmd.Flags().Bool("namespace-per-workload", false, "Clean up the <namespace>-<workload> namespaces of runs made with --namespace-per-workload")
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the current phase of managed VMs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	statusCmd.Flags().String("run-id", "", "Only show VMs from this specific run (UUID)")
+	statusCmd.Flags().StringSlice("columns", nil, "Columns to show, comma-separated: name, component, role, phase, vm-ready, agent, node, message, created, ready")
+	statusCmd.Flags().Bool("namespace-per-workload", false, "Show the VMs in the <namespace>-<workload> namespaces of runs made with --namespace-per-workload")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the runs deployed on the cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	listCmd.Flags().BoolP("all-namespaces", "A", false, "List runs in all namespaces")
+	listCmd.Flags().StringSlice("columns", nil, "Columns to show, comma-separated: namespace, run-id, vms, services, secrets, components, created")
+
+	scaleCmd := &cobra.Command{
+		Use:   "scale",
+		Short: "Add or remove VMs for an existing run",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	scaleCmd.Flags().String("workload", "", "Workload to scale (e.g., cpu)")
+	scaleCmd.Flags().Int("vm-count", 0, "Target number of VMs for the workload")
+	scaleCmd.Flags().String("run-id", "", "Run (UUID) whose VMs are scaled")
+	scaleCmd.Flags().Bool("dry-run", false, "Print the VMs that would be created or deleted without changing anything")
+	scaleCmd.Flags().Bool("namespace-per-workload", false, "Scale the workload in its <namespace>-<workload> namespace, for runs made with --namespace-per-workload")
+	_ = scaleCmd.MarkFlagRequired("workload")
+	_ = scaleCmd.MarkFlagRequired("vm-count")
+	_ = scaleCmd.MarkFlagRequired("run-id")
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Live migrate the VMs of an existing run",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	migrateCmd.Flags().String("run-id", "", "Run (UUID) whose VMs are migrated")
+	migrateCmd.Flags().Bool("wait", false, "Wait for the migrations to complete")
+	migrateCmd.Flags().Duration("migration-timeout", constants.DefaultMigrationTimeout, "How long --wait waits for the migrations")
+	migrateCmd.Flags().Bool("namespace-per-workload", false, "Migrate the VMs in the <namespace>-<workload> namespaces of runs made with --namespace-per-workload")
+	_ = migrateCmd.MarkFlagRequired("run-id")
+
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Boot the VMs of a run created with --start-stopped",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	startCmd.Flags().String("run-id", "", "Run (UUID) whose VMs are started")
+	startCmd.Flags().Bool("namespace-per-workload", false, "Start the VMs in the <namespace>-<workload> namespaces of runs made with --namespace-per-workload")
+	_ = startCmd.MarkFlagRequired("run-id")
+
+	describeCmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Print the rendered cloud-init and VM settings of one workload",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	describeCmd.Flags().String("workload", "", "Workload to describe (e.g., database)")
+	describeCmd.Flags().String("role", "", "Only describe this role of a multi-VM workload (e.g., server)")
+	describeCmd.Flags().Bool("no-redact", false, "Show passwords and SSH keys in the userdata")
+	_ = describeCmd.MarkFlagRequired("workload")
+
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit database",
+	}
+	auditListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List past executions and their outcomes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	auditListCmd.Flags().String("status", "", "Only list executions with this status (in_progress, success, failed)")
+	auditListCmd.Flags().String("format", "table", "Output format (table, json)")
+	auditListCmd.Flags().StringSlice("columns", nil, "Table columns to show, comma-separated: run-id, command, namespace, status, vms, started, duration")
+	auditCompareCmd := &cobra.Command{
+		Use:   "compare <run-id-a> <run-id-b>",
+		Short: "Compare the configuration and outcome of two runs",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	auditCompareCmd.Flags().String("format", "table", "Output format (table, json)")
+	auditEventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "Show the events recorded for a run",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	auditEventsCmd.Flags().String("run-id", "", "Run (UUID) whose events are shown")
+	auditEventsCmd.Flags().BoolP("follow", "f", false, "Keep polling for new events")
+	_ = auditEventsCmd.MarkFlagRequired("run-id")
+	auditSchemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the audit database DDL",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	auditSchemaCmd.Flags().String("dialect", "", "Schema dialect (sqlite, postgres; default: the configured backend)")
+	auditCmd.AddCommand(auditListCmd, auditCompareCmd, auditEventsCmd, auditSchemaCmd)
+
+	rootCmd.AddCommand(runCmd, cleanupCmd, statusCmd, listCmd, scaleCmd, migrateCmd, startCmd, describeCmd, auditCmd)
+	return rootCmd
+}
+
+var _ = Describe("Run command flags", func() {
+	var rootCmd *cobra.Command
+
+	BeforeEach(func() {
+		rootCmd = newRootCmd()
+	})
+
+	It("should have default namespace", func() {
+		rootCmd.SetArgs([]string{"run"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("namespace")
+		Expect(err).NotTo(HaveOccurred())
+		// Default is empty string since Viper provides defaults
+		Expect(val).To(Equal(""))
+	})
+
+	It("should accept custom namespace", func() {
+		rootCmd.SetArgs([]string{"run", "--namespace", "test-ns"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("namespace")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("test-ns"))
+	})
+
+	It("should accept workloads CSV", func() {
+		rootCmd.SetArgs([]string{"run", "--workloads", "cpu,memory"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetStringSlice("workloads")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal([]string{"cpu", "memory"}))
+	})
+
+	It("should accept vm-count", func() {
+		rootCmd.SetArgs([]string{"run", "--vm-count", "3"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetInt("vm-count")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(3))
+	})
+
+	It("should accept cpu-cores", func() {
+		rootCmd.SetArgs([]string{"run", "--cpu-cores", "4"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetInt("cpu-cores")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(4))
+	})
+
+	It("should accept memory", func() {
+		rootCmd.SetArgs([]string{"run", "--memory", "4Gi"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("memory")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("4Gi"))
+	})
+
+	It("should accept disk-size", func() {
+		rootCmd.SetArgs([]string{"run", "--disk-size", "20Gi"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("disk-size")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("20Gi"))
+	})
+
+	It("should default output to table", func() {
+		rootCmd.SetArgs([]string{"run"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("output")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("table"))
+	})
+
+	It("should accept output flag", func() {
+		rootCmd.SetArgs([]string{"run", "--output", "json"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("output")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("json"))
+	})
+
+	It("should accept log-format flag", func() {
+		rootCmd.SetArgs([]string{"run", "--log-format", "json"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("log-format")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("json"))
+	})
+
+	It("should accept dry-run flag", func() {
+		rootCmd.SetArgs([]string{"run", "--dry-run"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("dry-run")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept no-wait flag", func() {
+		rootCmd.SetArgs([]string{"run", "--no-wait"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("no-wait")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept verbose flag", func() {
+		rootCmd.SetArgs([]string{"run", "--verbose"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("verbose")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept timeout", func() {
+		rootCmd.SetArgs([]string{"run", "--timeout", "300"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetInt("timeout")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(300))
+	})
+
+	It("should accept config file", func() {
+		rootCmd.SetArgs([]string{"run", "--config", "/tmp/config.yaml"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("config")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("/tmp/config.yaml"))
+	})
+
+	It("should accept ssh-user flag", func() {
+		rootCmd.SetArgs([]string{"run", "--ssh-user", "admin"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("ssh-user")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("admin"))
+	})
+
+	It("should accept ssh-password flag", func() {
+		rootCmd.SetArgs([]string{"run", "--ssh-password", "secret"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("ssh-password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("secret"))
+	})
+
+	It("should accept ssh-key flag (repeatable)", func() {
+		rootCmd.SetArgs([]string{"run", "--ssh-key", "ssh-rsa AAAA key1", "--ssh-key", "ssh-ed25519 BBBB key2"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetStringSlice("ssh-key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(HaveLen(2))
+	})
+
+	It("should accept ssh-key-file flag (repeatable)", func() {
+		rootCmd.SetArgs([]string{"run", "--ssh-key-file", "/home/user/.ssh/id_rsa.pub"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetStringSlice("ssh-key-file")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(HaveLen(1))
+		Expect(val[0]).To(Equal("/home/user/.ssh/id_rsa.pub"))
+	})
+
+	It("should accept stagger flag", func() {
+		rootCmd.SetArgs([]string{"run", "--stagger", "2m"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetDuration("stagger")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(2 * time.Minute))
+	})
+
+	It("should accept deadline flag", func() {
+		rootCmd.SetArgs([]string{"run", "--deadline", "45m"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetDuration("deadline")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(45 * time.Minute))
+	})
+
+	It("should accept skip-connect-check flag", func() {
+		rootCmd.SetArgs([]string{"run", "--skip-connect-check"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("skip-connect-check")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept skip-preflight flag", func() {
+		rootCmd.SetArgs([]string{"run", "--skip-preflight"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("skip-preflight")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept check-quota flag", func() {
+		rootCmd.SetArgs([]string{"run", "--check-quota"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("check-quota")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept create-concurrency flag", func() {
+		rootCmd.SetArgs([]string{"run", "--create-concurrency", "4"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetInt("create-concurrency")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(4))
+	})
+
+	It("should accept create-retries and create-backoff flags", func() {
+		rootCmd.SetArgs([]string{"run", "--create-retries", "10", "--create-backoff", "2s"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		retries, err := runCmd.Flags().GetInt("create-retries")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(retries).To(Equal(10))
+		backoff, err := runCmd.Flags().GetDuration("create-backoff")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backoff).To(Equal(2 * time.Second))
+	})
+
+	It("should accept readiness-level flag", func() {
+		rootCmd.SetArgs([]string{"run", "--readiness-level", "agent"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("readiness-level")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("agent"))
+	})
+
+	It("should accept workload-probes flag", func() {
+		rootCmd.SetArgs([]string{"run", "--workload-probes"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("workload-probes")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept image-pull-secret flag", func() {
+		rootCmd.SetArgs([]string{"run", "--image-pull-secret", "registry-creds"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("image-pull-secret")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("registry-creds"))
+	})
+
+	It("should accept storage-class and access-mode flags", func() {
+		rootCmd.SetArgs([]string{"run", "--storage-class", "lvms-vg1", "--access-mode", "ReadWriteOnce"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		storageClass, err := runCmd.Flags().GetString("storage-class")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storageClass).To(Equal("lvms-vg1"))
+		accessMode, err := runCmd.Flags().GetString("access-mode")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(accessMode).To(Equal("ReadWriteOnce"))
+	})
+
+	It("should accept metrics-file flag", func() {
+		rootCmd.SetArgs([]string{"run", "--metrics-file", "/tmp/virtwork.prom"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("metrics-file")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("/tmp/virtwork.prom"))
+	})
+
+	It("should accept ready-report-file flag", func() {
+		rootCmd.SetArgs([]string{"run", "--ready-report-file", "/tmp/ready.json"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("ready-report-file")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("/tmp/ready.json"))
+	})
+
+	It("should accept fail-fast flag", func() {
+		rootCmd.SetArgs([]string{"run", "--fail-fast"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("fail-fast")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept recreate-failed flag", func() {
+		rootCmd.SetArgs([]string{"run", "--recreate-failed"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("recreate-failed")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept verify-ssh flag", func() {
+		rootCmd.SetArgs([]string{"run", "--verify-ssh"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("verify-ssh")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept network-policy flag", func() {
+		rootCmd.SetArgs([]string{"run", "--network-policy"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("network-policy")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept namespace-per-workload flag", func() {
+		rootCmd.SetArgs([]string{"run", "--namespace-per-workload"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("namespace-per-workload")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept cloud-init-overlay and overlay-override flags", func() {
+		rootCmd.SetArgs([]string{"run", "--cloud-init-overlay", "/tmp/overlay.yaml", "--overlay-override"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		path, err := runCmd.Flags().GetString("cloud-init-overlay")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(Equal("/tmp/overlay.yaml"))
+		override, err := runCmd.Flags().GetBool("overlay-override")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(override).To(BeTrue())
+	})
+
+	It("should accept update flag", func() {
+		rootCmd.SetArgs([]string{"run", "--update"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("update")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept reuse-run-id flag", func() {
+		rootCmd.SetArgs([]string{"run", "--reuse-run-id", "0b5c6a3e-6f1d-4e2a-9a57-6d2f0f8e7c11"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("reuse-run-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("0b5c6a3e-6f1d-4e2a-9a57-6d2f0f8e7c11"))
+	})
+
+	It("should accept CI metadata flags", func() {
+		rootCmd.SetArgs([]string{"run", "--label-run-with-git", "--git-sha", "0123abcd", "--ci-url", "https://ci.example.com/1"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		label, err := runCmd.Flags().GetBool("label-run-with-git")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(label).To(BeTrue())
+		sha, _ := runCmd.Flags().GetString("git-sha")
+		Expect(sha).To(Equal("0123abcd"))
+		url, _ := runCmd.Flags().GetString("ci-url")
+		Expect(url).To(Equal("https://ci.example.com/1"))
+	})
+
+	It("should accept on-ready-exec flags", func() {
+		rootCmd.SetArgs([]string{"run", "--on-ready-exec", "./run-tests.sh --quick", "--on-ready-exec-allow-failure"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		command, err := runCmd.Flags().GetString("on-ready-exec")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(command).To(Equal("./run-tests.sh --quick"))
+		allow, err := runCmd.Flags().GetBool("on-ready-exec-allow-failure")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allow).To(BeTrue())
+	})
+
+	It("should accept progress flag", func() {
+		rootCmd.SetArgs([]string{"run", "--progress"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		show, err := runCmd.Flags().GetBool("progress")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(show).To(BeTrue())
+	})
+
+	It("should accept start-stopped flag", func() {
+		rootCmd.SetArgs([]string{"run", "--start-stopped"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetBool("start-stopped")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept node-selector flag (repeatable)", func() {
+		rootCmd.SetArgs([]string{"run", "--node-selector", "zone=a", "--node-selector", "baremetal=true"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetStringToString("node-selector")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(map[string]string{"zone": "a", "baremetal": "true"}))
+	})
+
+	It("should accept toleration flag (repeatable)", func() {
+		rootCmd.SetArgs([]string{"run", "--toleration", "baremetal:NoSchedule", "--toleration", "gpu=nvidia"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetStringArray("toleration")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal([]string{"baremetal:NoSchedule", "gpu=nvidia"}))
+	})
+
+	It("should accept gpu flag (repeatable)", func() {
+		rootCmd.SetArgs([]string{"run", "--gpu", "nvidia.com/GA102GL_A10", "--gpu", "nvidia.com/NVIDIA_A10-4Q"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetStringArray("gpu")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal([]string{"nvidia.com/GA102GL_A10", "nvidia.com/NVIDIA_A10-4Q"}))
+	})
+
+	It("should accept multus-network flag (repeatable)", func() {
+		rootCmd.SetArgs([]string{"run", "--multus-network", "east-west", "--multus-network", "virtwork/storage"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetStringArray("multus-network")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal([]string{"east-west", "virtwork/storage"}))
+	})
+
+	It("should default a bare anti-affinity-weight flag to preferred", func() {
+		rootCmd.SetArgs([]string{"run", "--anti-affinity-weight"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("anti-affinity-weight")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("preferred"))
+	})
+
+	It("should accept anti-affinity-weight flag with a value", func() {
+		rootCmd.SetArgs([]string{"run", "--anti-affinity-weight=required"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		runCmd, _, _ := rootCmd.Find([]string{"run"})
+		val, err := runCmd.Flags().GetString("anti-affinity-weight")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal("required"))
+	})
+})
+
+var _ = Describe("Cleanup command flags", func() {
+	var rootCmd *cobra.Command
+
+	BeforeEach(func() {
+		rootCmd = newRootCmd()
+	})
+
+	It("should accept delete-namespace flag", func() {
+		rootCmd.SetArgs([]string{"cleanup", "--delete-namespace"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		val, err := cleanupCmd.Flags().GetBool("delete-namespace")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should default delete-namespace to false", func() {
+		rootCmd.SetArgs([]string{"cleanup"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		val, err := cleanupCmd.Flags().GetBool("delete-namespace")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeFalse())
+	})
+
+	It("should accept namespace-per-workload flag", func() {
+		rootCmd.SetArgs([]string{"cleanup", "--namespace-per-workload"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		val, err := cleanupCmd.Flags().GetBool("namespace-per-workload")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should accept grace-period and propagation flags", func() {
+		rootCmd.SetArgs([]string{"cleanup", "--grace-period", "0", "--propagation", "Background"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		grace, err := cleanupCmd.Flags().GetInt64("grace-period")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(grace).To(Equal(int64(0)))
+		propagation, err := cleanupCmd.Flags().GetString("propagation")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(propagation).To(Equal("Background"))
+	})
+
+	It("should accept cleanup-concurrency flag", func() {
+		rootCmd.SetArgs([]string{"cleanup", "--cleanup-concurrency", "25"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		val, err := cleanupCmd.Flags().GetInt("cleanup-concurrency")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(25))
+	})
+
+	It("should default cleanup-concurrency to 10", func() {
+		rootCmd.SetArgs([]string{"cleanup"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		val, err := cleanupCmd.Flags().GetInt("cleanup-concurrency")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(10))
+	})
+
+	It("should accept repeated selector flags", func() {
+		rootCmd.SetArgs([]string{"cleanup", "--selector", "app.kubernetes.io/component=cpu", "--selector", "team=perf"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		val, err := cleanupCmd.Flags().GetStringToString("selector")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(map[string]string{
+			"app.kubernetes.io/component": "cpu",
+			"team":                        "perf",
+		}))
+	})
+
+	It("should accept older-than flag", func() {
+		rootCmd.SetArgs([]string{"cleanup", "--older-than", "24h"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		val, err := cleanupCmd.Flags().GetDuration("older-than")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(Equal(24 * time.Hour))
+	})
+
+	It("should accept report-orphans with delete-orphans", func() {
+		rootCmd.SetArgs([]string{"cleanup", "--report-orphans", "--delete-orphans"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		report, err := cleanupCmd.Flags().GetBool("report-orphans")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(BeTrue())
+		del, err := cleanupCmd.Flags().GetBool("delete-orphans")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(del).To(BeTrue())
+	})
+
+	It("should accept verify-cleanup-complete with verify-timeout", func() {
+		rootCmd.SetArgs([]string{"cleanup", "--verify-cleanup-complete", "--verify-timeout", "30s"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		verify, err := cleanupCmd.Flags().GetBool("verify-cleanup-complete")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verify).To(BeTrue())
+		timeout, err := cleanupCmd.Flags().GetDuration("verify-timeout")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(timeout).To(Equal(30 * time.Second))
+	})
+
+	It("should accept dry-run flag", func() {
+		rootCmd.SetArgs([]string{"cleanup", "--dry-run"})
+		Expect(rootCmd.Execute()).To(Succeed())
 
-	rootCmd.AddCommand(runCmd, cleanupCmd)
-	return rootCmd
-}
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		val, err := cleanupCmd.Flags().GetBool("dry-run")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
+	})
 
-var _ = Describe("Run command flags", func() {
+	It("should default verify-timeout to 2 minutes", func() {
+		rootCmd.SetArgs([]string{"cleanup"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		timeout, err := cleanupCmd.Flags().GetDuration("verify-timeout")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(timeout).To(Equal(2 * time.Minute))
+	})
+
+	It("should accept force flag", func() {
+		rootCmd.SetArgs([]string{"cleanup", "--force"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		force, err := cleanupCmd.Flags().GetBool("force")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(force).To(BeTrue())
+	})
+
+	It("should default grace-period to -1", func() {
+		rootCmd.SetArgs([]string{"cleanup"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
+		grace, err := cleanupCmd.Flags().GetInt64("grace-period")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(grace).To(Equal(int64(-1)))
+	})
+})
+
+var _ = Describe("Migrate command flags", func() {
 	var rootCmd *cobra.Command
 
 	BeforeEach(func() {
 		rootCmd = newRootCmd()
 	})
 
-	It("should have default namespace", func() {
-		rootCmd.SetArgs([]string{"run"})
+	It("should accept run-id, wait, and migration-timeout flags", func() {
+		rootCmd.SetArgs([]string{"migrate", "--run-id", "abc-123", "--wait", "--migration-timeout", "20m"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetString("namespace")
+		migrateCmd, _, _ := rootCmd.Find([]string{"migrate"})
+		runID, err := migrateCmd.Flags().GetString("run-id")
 		Expect(err).NotTo(HaveOccurred())
-		// Default is empty string since Viper provides defaults
-		Expect(val).To(Equal(""))
+		Expect(runID).To(Equal("abc-123"))
+		wait, err := migrateCmd.Flags().GetBool("wait")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wait).To(BeTrue())
+		timeout, err := migrateCmd.Flags().GetDuration("migration-timeout")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(timeout).To(Equal(20 * time.Minute))
 	})
 
-	It("should accept custom namespace", func() {
-		rootCmd.SetArgs([]string{"run", "--namespace", "test-ns"})
+	It("should accept namespace-per-workload flag", func() {
+		rootCmd.SetArgs([]string{"migrate", "--run-id", "abc-123", "--namespace-per-workload"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetString("namespace")
+		migrateCmd, _, _ := rootCmd.Find([]string{"migrate"})
+		val, err := migrateCmd.Flags().GetBool("namespace-per-workload")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(Equal("test-ns"))
+		Expect(val).To(BeTrue())
 	})
 
-	It("should accept workloads CSV", func() {
-		rootCmd.SetArgs([]string{"run", "--workloads", "cpu,memory"})
+	It("should default migration-timeout to ten minutes", func() {
+		rootCmd.SetArgs([]string{"migrate", "--run-id", "abc-123"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetStringSlice("workloads")
+		migrateCmd, _, _ := rootCmd.Find([]string{"migrate"})
+		timeout, err := migrateCmd.Flags().GetDuration("migration-timeout")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(Equal([]string{"cpu", "memory"}))
+		Expect(timeout).To(Equal(10 * time.Minute))
 	})
 
-	It("should accept vm-count", func() {
-		rootCmd.SetArgs([]string{"run", "--vm-count", "3"})
+	It("should require run-id", func() {
+		rootCmd.SetArgs([]string{"migrate"})
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring(`required flag(s) "run-id" not set`)))
+	})
+})
+
+var _ = Describe("Start command flags", func() {
+	var rootCmd *cobra.Command
+
+	BeforeEach(func() {
+		rootCmd = newRootCmd()
+	})
+
+	It("should accept run-id", func() {
+		rootCmd.SetArgs([]string{"start", "--run-id", "abc-123"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetInt("vm-count")
+		startCmd, _, _ := rootCmd.Find([]string{"start"})
+		runID, err := startCmd.Flags().GetString("run-id")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(Equal(3))
+		Expect(runID).To(Equal("abc-123"))
 	})
 
-	It("should accept cpu-cores", func() {
-		rootCmd.SetArgs([]string{"run", "--cpu-cores", "4"})
+	It("should accept namespace-per-workload flag", func() {
+		rootCmd.SetArgs([]string{"start", "--run-id", "abc-123", "--namespace-per-workload"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetInt("cpu-cores")
+		startCmd, _, _ := rootCmd.Find([]string{"start"})
+		val, err := startCmd.Flags().GetBool("namespace-per-workload")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(Equal(4))
+		Expect(val).To(BeTrue())
 	})
 
-	It("should accept memory", func() {
-		rootCmd.SetArgs([]string{"run", "--memory", "4Gi"})
+	It("should require run-id", func() {
+		rootCmd.SetArgs([]string{"start"})
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring(`required flag(s) "run-id" not set`)))
+	})
+})
+
+var _ = Describe("Describe command flags", func() {
+	var rootCmd *cobra.Command
+
+	BeforeEach(func() {
+		rootCmd = newRootCmd()
+	})
+
+	It("should accept workload and role", func() {
+		rootCmd.SetArgs([]string{"describe", "--workload", "network", "--role", "server"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetString("memory")
+		describeCmd, _, _ := rootCmd.Find([]string{"describe"})
+		workload, err := describeCmd.Flags().GetString("workload")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(Equal("4Gi"))
+		Expect(workload).To(Equal("network"))
+		role, err := describeCmd.Flags().GetString("role")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(role).To(Equal("server"))
 	})
 
-	It("should accept disk-size", func() {
-		rootCmd.SetArgs([]string{"run", "--disk-size", "20Gi"})
+	It("should require workload", func() {
+		rootCmd.SetArgs([]string{"describe"})
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring(`required flag(s) "workload" not set`)))
+	})
+})
+
+var _ = Describe("Audit list command flags", func() {
+	var rootCmd *cobra.Command
+
+	BeforeEach(func() {
+		rootCmd = newRootCmd()
+	})
+
+	It("should accept status and format flags", func() {
+		rootCmd.SetArgs([]string{"audit", "list", "--status", "failed", "--format", "json"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetString("disk-size")
+		listCmd, _, _ := rootCmd.Find([]string{"audit", "list"})
+		statusFilter, err := listCmd.Flags().GetString("status")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(Equal("20Gi"))
+		Expect(statusFilter).To(Equal("failed"))
+		format, err := listCmd.Flags().GetString("format")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(format).To(Equal("json"))
 	})
 
-	It("should accept dry-run flag", func() {
-		rootCmd.SetArgs([]string{"run", "--dry-run"})
+	It("should default format to table", func() {
+		rootCmd.SetArgs([]string{"audit", "list"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetBool("dry-run")
+		listCmd, _, _ := rootCmd.Find([]string{"audit", "list"})
+		format, err := listCmd.Flags().GetString("format")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(BeTrue())
+		Expect(format).To(Equal("table"))
 	})
 
-	It("should accept no-wait flag", func() {
-		rootCmd.SetArgs([]string{"run", "--no-wait"})
+	It("should inherit the persistent namespace flag", func() {
+		rootCmd.SetArgs([]string{"audit", "list", "--namespace", "ns-a"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetBool("no-wait")
+		listCmd, _, _ := rootCmd.Find([]string{"audit", "list"})
+		ns, err := listCmd.Flags().GetString("namespace")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(BeTrue())
+		Expect(ns).To(Equal("ns-a"))
 	})
 
-	It("should accept verbose flag", func() {
-		rootCmd.SetArgs([]string{"run", "--verbose"})
+	It("should accept two run IDs and a format flag for compare", func() {
+		rootCmd.SetArgs([]string{"audit", "compare", "run-a", "run-b", "--format", "json"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetBool("verbose")
+		compareCmd, _, _ := rootCmd.Find([]string{"audit", "compare"})
+		format, err := compareCmd.Flags().GetString("format")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(BeTrue())
+		Expect(format).To(Equal("json"))
 	})
 
-	It("should accept timeout", func() {
-		rootCmd.SetArgs([]string{"run", "--timeout", "300"})
+	It("should accept run-id and follow flags for events", func() {
+		rootCmd.SetArgs([]string{"audit", "events", "--run-id", "run-a", "-f"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetInt("timeout")
+		eventsCmd, _, _ := rootCmd.Find([]string{"audit", "events"})
+		runID, err := eventsCmd.Flags().GetString("run-id")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(Equal(300))
+		Expect(runID).To(Equal("run-a"))
+		follow, err := eventsCmd.Flags().GetBool("follow")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(follow).To(BeTrue())
 	})
 
-	It("should accept config file", func() {
-		rootCmd.SetArgs([]string{"run", "--config", "/tmp/config.yaml"})
+	It("should require run-id for events", func() {
+		rootCmd.SetArgs([]string{"audit", "events"})
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring(`required flag(s) "run-id" not set`)))
+	})
+
+	It("should accept a dialect flag for schema", func() {
+		rootCmd.SetArgs([]string{"audit", "schema", "--dialect", "postgres"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetString("config")
+		schemaCmd, _, _ := rootCmd.Find([]string{"audit", "schema"})
+		dialect, err := schemaCmd.Flags().GetString("dialect")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(Equal("/tmp/config.yaml"))
+		Expect(dialect).To(Equal("postgres"))
 	})
 
-	It("should accept ssh-user flag", func() {
-		rootCmd.SetArgs([]string{"run", "--ssh-user", "admin"})
+	It("should require exactly two run IDs for compare", func() {
+		rootCmd.SetArgs([]string{"audit", "compare", "run-a"})
+		rootCmd.SilenceErrors = true
+		rootCmd.SilenceUsage = true
+		Expect(rootCmd.Execute()).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Status command flags", func() {
+	var rootCmd *cobra.Command
+
+	BeforeEach(func() {
+		rootCmd = newRootCmd()
+	})
+
+	It("should accept run-id flag", func() {
+		rootCmd.SetArgs([]string{"status", "--run-id", "abc-123"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetString("ssh-user")
+		statusCmd, _, _ := rootCmd.Find([]string{"status"})
+		val, err := statusCmd.Flags().GetString("run-id")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(Equal("admin"))
+		Expect(val).To(Equal("abc-123"))
 	})
 
-	It("should accept ssh-password flag", func() {
-		rootCmd.SetArgs([]string{"run", "--ssh-password", "secret"})
+	It("should accept namespace-per-workload flag", func() {
+		rootCmd.SetArgs([]string{"status", "--run-id", "abc-123", "--namespace-per-workload"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetString("ssh-password")
+		statusCmd, _, _ := rootCmd.Find([]string{"status"})
+		val, err := statusCmd.Flags().GetBool("namespace-per-workload")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(Equal("secret"))
+		Expect(val).To(BeTrue())
 	})
+})
 
-	It("should accept ssh-key flag (repeatable)", func() {
-		rootCmd.SetArgs([]string{"run", "--ssh-key", "ssh-rsa AAAA key1", "--ssh-key", "ssh-ed25519 BBBB key2"})
+var _ = Describe("List command flags", func() {
+	var rootCmd *cobra.Command
+
+	BeforeEach(func() {
+		rootCmd = newRootCmd()
+	})
+
+	It("should default all-namespaces to false", func() {
+		rootCmd.SetArgs([]string{"list"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetStringSlice("ssh-key")
+		listCmd, _, _ := rootCmd.Find([]string{"list"})
+		val, err := listCmd.Flags().GetBool("all-namespaces")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(HaveLen(2))
+		Expect(val).To(BeFalse())
 	})
 
-	It("should accept ssh-key-file flag (repeatable)", func() {
-		rootCmd.SetArgs([]string{"run", "--ssh-key-file", "/home/user/.ssh/id_rsa.pub"})
+	It("should accept a comma-separated columns flag for list", func() {
+		rootCmd.SetArgs([]string{"list", "--columns", "run-id,vms"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		runCmd, _, _ := rootCmd.Find([]string{"run"})
-		val, err := runCmd.Flags().GetStringSlice("ssh-key-file")
+		listCmd, _, _ := rootCmd.Find([]string{"list"})
+		val, err := listCmd.Flags().GetStringSlice("columns")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(HaveLen(1))
-		Expect(val[0]).To(Equal("/home/user/.ssh/id_rsa.pub"))
+		Expect(val).To(Equal([]string{"run-id", "vms"}))
+	})
+
+	It("should accept the -A shorthand for all-namespaces", func() {
+		rootCmd.SetArgs([]string{"list", "-A"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		listCmd, _, _ := rootCmd.Find([]string{"list"})
+		val, err := listCmd.Flags().GetBool("all-namespaces")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeTrue())
 	})
 })
 
-var _ = Describe("Cleanup command flags", func() {
+var _ = Describe("Scale command flags", func() {
 	var rootCmd *cobra.Command
 
 	BeforeEach(func() {
 		rootCmd = newRootCmd()
 	})
 
-	It("should accept delete-namespace flag", func() {
-		rootCmd.SetArgs([]string{"cleanup", "--delete-namespace"})
+	It("should accept workload, vm-count, run-id, and dry-run flags", func() {
+		rootCmd.SetArgs([]string{"scale", "--workload", "cpu", "--vm-count", "5", "--run-id", "abc-123", "--dry-run"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
-		val, err := cleanupCmd.Flags().GetBool("delete-namespace")
-		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(BeTrue())
+		scaleCmd, _, _ := rootCmd.Find([]string{"scale"})
+		workload, _ := scaleCmd.Flags().GetString("workload")
+		Expect(workload).To(Equal("cpu"))
+		count, _ := scaleCmd.Flags().GetInt("vm-count")
+		Expect(count).To(Equal(5))
+		runID, _ := scaleCmd.Flags().GetString("run-id")
+		Expect(runID).To(Equal("abc-123"))
+		dryRun, _ := scaleCmd.Flags().GetBool("dry-run")
+		Expect(dryRun).To(BeTrue())
 	})
 
-	It("should default delete-namespace to false", func() {
-		rootCmd.SetArgs([]string{"cleanup"})
+	It("should accept namespace-per-workload flag", func() {
+		rootCmd.SetArgs([]string{"scale", "--workload", "cpu", "--vm-count", "5", "--run-id", "abc-123", "--dry-run", "--namespace-per-workload"})
 		Expect(rootCmd.Execute()).To(Succeed())
 
-		cleanupCmd, _, _ := rootCmd.Find([]string{"cleanup"})
-		val, err := cleanupCmd.Flags().GetBool("delete-namespace")
+		scaleCmd, _, _ := rootCmd.Find([]string{"scale"})
+		val, err := scaleCmd.Flags().GetBool("namespace-per-workload")
 		Expect(err).NotTo(HaveOccurred())
-		Expect(val).To(BeFalse())
+		Expect(val).To(BeTrue())
+	})
+
+	It("should require the run-id flag", func() {
+		rootCmd.SetArgs([]string{"scale", "--workload", "cpu", "--vm-count", "5"})
+		rootCmd.SilenceErrors = true
+		rootCmd.SilenceUsage = true
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring(`required flag(s) "run-id" not set`)))
 	})
 })
 
@@ -328,6 +1303,237 @@ var _ = Describe("Run orchestration", func() {
 			Expect(vmSpec.Namespace).To(Equal(constants.DefaultNamespace))
 		})
 
+		It("should attach an affinity loaded from --affinity-from-file to the VM template", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "affinity.yaml")
+			Expect(os.WriteFile(path, []byte(`
+nodeAffinity:
+  requiredDuringSchedulingIgnoredDuringExecution:
+    nodeSelectorTerms:
+      - matchExpressions:
+          - key: kubernetes.io/arch
+            operator: In
+            values: [amd64]
+podAntiAffinity:
+  requiredDuringSchedulingIgnoredDuringExecution:
+    - topologyKey: kubernetes.io/hostname
+      labelSelector:
+        matchLabels:
+          app.kubernetes.io/component: cpu
+`), 0644)).To(Succeed())
+
+			cmd := &cobra.Command{Use: "run"}
+			config.BindFlags(cmd)
+			Expect(cmd.Flags().Set("affinity-from-file", path)).To(Succeed())
+			cfg, err := config.LoadConfig(cmd)
+			Expect(err).NotTo(HaveOccurred())
+
+			vmSpec := vm.BuildVMSpec(vm.VMSpecOpts{
+				Name:               "virtwork-cpu-0",
+				Namespace:          constants.DefaultNamespace,
+				ContainerDiskImage: constants.DefaultContainerDiskImage,
+				CPUCores:           constants.DefaultCPUCores,
+				Memory:             constants.DefaultMemory,
+				Affinity:           cfg.Affinity,
+			})
+			affinity := vmSpec.Spec.Template.Spec.Affinity
+			Expect(affinity).NotTo(BeNil())
+			Expect(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].
+				MatchExpressions[0].Values).To(Equal([]string{"amd64"}))
+			Expect(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey).
+				To(Equal("kubernetes.io/hostname"))
+		})
+
+		It("should attach --block-size to the disk workload's data disk", func() {
+			cmd := &cobra.Command{Use: "run"}
+			config.BindFlags(cmd)
+			Expect(cmd.Flags().Set("block-size", "logical=512,physical=4096")).To(Succeed())
+			cfg, err := config.LoadConfig(cmd)
+			Expect(err).NotTo(HaveOccurred())
+
+			w, err := workloads.DefaultRegistry().Get("disk", cfg.EffectiveWorkload("disk", 1))
+			Expect(err).NotTo(HaveOccurred())
+			vmSpec := vm.BuildVMSpec(vm.VMSpecOpts{
+				Name:                "virtwork-disk-0",
+				Namespace:           cfg.Namespace,
+				ContainerDiskImage:  cfg.EffectiveImage("disk", ""),
+				CPUCores:            constants.DefaultCPUCores,
+				Memory:              constants.DefaultMemory,
+				ExtraDisks:          w.ExtraDisks(),
+				ExtraVolumes:        w.ExtraVolumes(),
+				DataVolumeTemplates: w.DataVolumeTemplates(),
+				DataDiskBlockSize:   cfg.BlockSize,
+			})
+
+			for _, d := range vmSpec.Spec.Template.Spec.Domain.Devices.Disks {
+				if d.Name == "datadisk" {
+					Expect(d.BlockSize).NotTo(BeNil())
+					Expect(d.BlockSize.Custom.Logical).To(Equal(uint(512)))
+					Expect(d.BlockSize.Custom.Physical).To(Equal(uint(4096)))
+				} else {
+					Expect(d.BlockSize).To(BeNil(), "disk %s", d.Name)
+				}
+			}
+		})
+
+		It("should share --data-fs with the disk workload over virtiofs", func() {
+			cmd := &cobra.Command{Use: "run"}
+			config.BindFlags(cmd)
+			Expect(cmd.Flags().Set("data-fs", "pvc=shared-data")).To(Succeed())
+			cfg, err := config.LoadConfig(cmd)
+			Expect(err).NotTo(HaveOccurred())
+
+			w, err := workloads.DefaultRegistry().Get("disk", cfg.EffectiveWorkload("disk", 1),
+				workloads.WithDataFilesystem(cfg.DataFSPVC))
+			Expect(err).NotTo(HaveOccurred())
+			fsUser, ok := w.(workloads.FilesystemUser)
+			Expect(ok).To(BeTrue())
+			vmSpec := vm.BuildVMSpec(vm.VMSpecOpts{
+				Name:                "virtwork-disk-0",
+				Namespace:           cfg.Namespace,
+				ContainerDiskImage:  cfg.EffectiveImage("disk", ""),
+				CPUCores:            constants.DefaultCPUCores,
+				Memory:              constants.DefaultMemory,
+				ExtraDisks:          w.ExtraDisks(),
+				ExtraVolumes:        w.ExtraVolumes(),
+				DataVolumeTemplates: w.DataVolumeTemplates(),
+				Filesystems:         fsUser.Filesystems(),
+			})
+
+			Expect(vmSpec.Spec.DataVolumeTemplates).To(BeEmpty())
+			Expect(vmSpec.Spec.Template.Spec.Domain.Devices.Disks).To(HaveLen(2))
+			filesystems := vmSpec.Spec.Template.Spec.Domain.Devices.Filesystems
+			Expect(filesystems).To(HaveLen(1))
+			Expect(filesystems[0].Virtiofs).NotTo(BeNil())
+			var claim string
+			for _, v := range vmSpec.Spec.Template.Spec.Volumes {
+				if v.Name == filesystems[0].Name {
+					claim = v.PersistentVolumeClaim.ClaimName
+				}
+			}
+			Expect(claim).To(Equal("shared-data"))
+		})
+
+		It("should back --hugepages VMs with hugepages", func() {
+			cmd := &cobra.Command{Use: "run"}
+			config.BindFlags(cmd)
+			Expect(cmd.Flags().Set("hugepages", "2Mi")).To(Succeed())
+			cfg, err := config.LoadConfig(cmd)
+			Expect(err).NotTo(HaveOccurred())
+
+			vmSpec := vm.BuildVMSpec(vm.VMSpecOpts{
+				Name:               "virtwork-memory-0",
+				Namespace:          cfg.Namespace,
+				ContainerDiskImage: cfg.EffectiveImage("memory", ""),
+				CPUCores:           constants.DefaultCPUCores,
+				Memory:             constants.DefaultMemory,
+				Hugepages:          cfg.EffectiveHugepages("memory"),
+			})
+			memory := vmSpec.Spec.Template.Spec.Domain.Memory
+			Expect(memory).NotTo(BeNil())
+			Expect(memory.Hugepages.PageSize).To(Equal("2Mi"))
+		})
+
+		It("should give --memory-limit and --cpu-limit VMs resource limits", func() {
+			cmd := &cobra.Command{Use: "run"}
+			config.BindFlags(cmd)
+			Expect(cmd.Flags().Set("memory-limit", "4Gi")).To(Succeed())
+			Expect(cmd.Flags().Set("cpu-limit", "2")).To(Succeed())
+			Expect(cmd.Flags().Set("overcommit-guest-overhead", "true")).To(Succeed())
+			cfg, err := config.LoadConfig(cmd)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Validate(cfg)).To(Succeed())
+
+			vmSpec := vm.BuildVMSpec(vm.VMSpecOpts{
+				Name:                    "virtwork-cpu-0",
+				Namespace:               cfg.Namespace,
+				ContainerDiskImage:      cfg.EffectiveImage("cpu", ""),
+				CPUCores:                cfg.CPUCores,
+				Memory:                  cfg.Memory,
+				MemoryLimit:             cfg.MemoryLimit,
+				CPULimit:                cfg.CPULimit,
+				OvercommitGuestOverhead: cfg.OvercommitGuestOverhead,
+			})
+			res := vmSpec.Spec.Template.Spec.Domain.Resources
+			Expect(res.Limits.Memory().String()).To(Equal("4Gi"))
+			Expect(res.Limits.Cpu().String()).To(Equal("2"))
+			Expect(res.OvercommitGuestOverhead).To(BeTrue())
+		})
+
+		It("should use a workload's container-disk-image only for that workload's VMs", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "virtwork.yaml")
+			Expect(os.WriteFile(path, []byte(`
+workloads:
+  memory:
+    container-disk-image: quay.io/containerdisks/centos-stream:9
+`), 0644)).To(Succeed())
+
+			cmd := &cobra.Command{Use: "run"}
+			config.BindFlags(cmd)
+			Expect(cmd.Flags().Set("config", path)).To(Succeed())
+			cfg, err := config.LoadConfig(cmd)
+			Expect(err).NotTo(HaveOccurred())
+
+			images := make(map[string]string)
+			for _, name := range []string{"memory", "database"} {
+				vmSpec := vm.BuildVMSpec(vm.VMSpecOpts{
+					Name:               fmt.Sprintf("virtwork-%s-0", name),
+					Namespace:          cfg.Namespace,
+					ContainerDiskImage: cfg.EffectiveImage(name, ""),
+					CPUCores:           constants.DefaultCPUCores,
+					Memory:             constants.DefaultMemory,
+				})
+				for _, v := range vmSpec.Spec.Template.Spec.Volumes {
+					if v.ContainerDisk != nil {
+						images[name] = v.ContainerDisk.Image
+					}
+				}
+			}
+			Expect(images["memory"]).To(Equal("quay.io/containerdisks/centos-stream:9"))
+			Expect(images["database"]).To(Equal(constants.DefaultContainerDiskImage))
+		})
+
+		It("should use a role-specific image from the images map only for that role's VMs", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "virtwork.yaml")
+			Expect(os.WriteFile(path, []byte(`
+images:
+  network-server: quay.io/example/iperf-server:latest
+`), 0644)).To(Succeed())
+
+			cmd := &cobra.Command{Use: "run"}
+			config.BindFlags(cmd)
+			Expect(cmd.Flags().Set("config", path)).To(Succeed())
+			cfg, err := config.LoadConfig(cmd)
+			Expect(err).NotTo(HaveOccurred())
+
+			w, err := workloads.DefaultRegistry().Get("network", cfg.EffectiveWorkload("network", 1),
+				workloads.WithNamespace(cfg.Namespace), workloads.WithClientsPerServer(2))
+			Expect(err).NotTo(HaveOccurred())
+
+			images := make(map[string][]string)
+			for _, rc := range w.(workloads.MultiVMWorkload).RoleCounts() {
+				for i := 0; i < rc.Count; i++ {
+					vmSpec := vm.BuildVMSpec(vm.VMSpecOpts{
+						Name:               fmt.Sprintf("virtwork-network-%s-%d", rc.Role, i),
+						Namespace:          cfg.Namespace,
+						ContainerDiskImage: cfg.EffectiveImage("network", rc.Role),
+						CPUCores:           constants.DefaultCPUCores,
+						Memory:             constants.DefaultMemory,
+					})
+					var image string
+					for _, v := range vmSpec.Spec.Template.Spec.Volumes {
+						if v.ContainerDisk != nil {
+							image = v.ContainerDisk.Image
+						}
+					}
+					images[rc.Role] = append(images[rc.Role], image)
+				}
+			}
+			Expect(images["server"]).To(Equal([]string{"quay.io/example/iperf-server:latest"}))
+			Expect(images["client"]).To(Equal([]string{
+				constants.DefaultContainerDiskImage, constants.DefaultContainerDiskImage,
+			}))
+		})
+
 		It("should print specs to stdout in dry-run", func() {
 			registry := workloads.DefaultRegistry()
 			cfg := config.WorkloadConfig{
@@ -370,6 +1576,60 @@ var _ = Describe("Run orchestration", func() {
 			Expect(buf.String()).To(ContainSubstring("virtwork-cpu-0"))
 			Expect(buf.String()).To(ContainSubstring("cpu=2"))
 		})
+
+		Context("redaction", func() {
+			var userdata string
+
+			BeforeEach(func() {
+				w, err := workloads.DefaultRegistry().Get("cpu", config.WorkloadConfig{Enabled: true, VMCount: 1},
+					workloads.WithSSHCredentials(constants.DefaultSSHUser, "hunter2", []string{"ssh-ed25519 AAAAsecretkey"}),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				userdata, err = w.CloudInitUserdata()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			dryRunYAML := func(userdata string) string {
+				vmSpec := vm.BuildVMSpec(vm.VMSpecOpts{
+					Name:               "virtwork-cpu-0",
+					Namespace:          constants.DefaultNamespace,
+					ContainerDiskImage: constants.DefaultContainerDiskImage,
+					CloudInitUserdata:  userdata,
+					CPUCores:           constants.DefaultCPUCores,
+					Memory:             constants.DefaultMemory,
+				})
+				data, err := sigyaml.Marshal(vmSpec)
+				Expect(err).NotTo(HaveOccurred())
+				return string(data)
+			}
+
+			It("should redact the password and keys by default", func() {
+				rootCmd := newRootCmd()
+				rootCmd.SetArgs([]string{"run", "--dry-run"})
+				Expect(rootCmd.Execute()).To(Succeed())
+				runCmd, _, _ := rootCmd.Find([]string{"run"})
+				noRedact, _ := runCmd.Flags().GetBool("no-redact")
+				Expect(noRedact).To(BeFalse())
+
+				out := dryRunYAML(cloudinit.Redact(userdata))
+				Expect(out).NotTo(ContainSubstring("hunter2"))
+				Expect(out).NotTo(ContainSubstring("AAAAsecretkey"))
+				Expect(out).To(ContainSubstring("***"))
+			})
+
+			It("should show the password only with --no-redact", func() {
+				rootCmd := newRootCmd()
+				rootCmd.SetArgs([]string{"run", "--dry-run", "--no-redact"})
+				Expect(rootCmd.Execute()).To(Succeed())
+				runCmd, _, _ := rootCmd.Find([]string{"run"})
+				noRedact, _ := runCmd.Flags().GetBool("no-redact")
+				Expect(noRedact).To(BeTrue())
+
+				out := dryRunYAML(userdata)
+				Expect(out).To(ContainSubstring("hunter2"))
+				Expect(out).To(ContainSubstring("AAAAsecretkey"))
+			})
+		})
 	})
 
 	Context("normal mode", func() {
@@ -625,7 +1885,7 @@ var _ = Describe("CLI end-to-end scenarios", func() {
 
 	Context("when running with default arguments", func() {
 		It("should create VMs for all workloads", func() {
-			// Default run creates 6 VMs: cpu=1 + memory=1 + disk=1 + database=1 + network=2
+			// Default run creates 10 VMs: cpu=1 + memory=1 + disk=1 + fs=1 + database=1 + network=2 + redis=1 + web=2
 			registry := workloads.DefaultRegistry()
 			totalVMs := 0
 			for _, name := range workloads.AllWorkloadNames {
@@ -643,8 +1903,8 @@ var _ = Describe("CLI end-to-end scenarios", func() {
 				Expect(err).NotTo(HaveOccurred())
 				totalVMs += w.VMCount()
 			}
-			// cpu=1 + database=1 + disk=1 + memory=1 + network=2 = 6
-			Expect(totalVMs).To(Equal(6))
+			// cpu=1 + database=1 + disk=1 + fs=1 + memory=1 + network=2 + redis=1 + web=2 = 10
+			Expect(totalVMs).To(Equal(10))
 		})
 	})
 
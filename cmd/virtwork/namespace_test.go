@@ -0,0 +1,148 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/logging"
+	"github.com/opdev/virtwork/internal/wait"
+)
+
+var _ = Describe("namespace per workload", func() {
+	wlCfg := config.WorkloadConfig{Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi"}
+
+	plansFor := func(cfg *config.Config, names ...string) []vmPlan {
+		registry, opts, err := newRegistry(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		var plans []vmPlan
+		for _, name := range names {
+			w, err := registry.Get(name, wlCfg, workloadOptions(cfg, name, opts)...)
+			Expect(err).NotTo(HaveOccurred())
+			wPlans, err := workloadPlans(cfg, w, name, "run-1")
+			Expect(err).NotTo(HaveOccurred())
+			plans = append(plans, wPlans...)
+		}
+		return plans
+	}
+
+	It("should place each workload's VMs in its own namespace", func() {
+		cfg := &config.Config{Namespace: "virtwork", NamespacePerWorkload: true}
+		plans := plansFor(cfg, "cpu", "network")
+
+		Expect(vmNamespaces(plans)).To(Equal(map[string]string{
+			"virtwork-cpu-0":            "virtwork-cpu",
+			"virtwork-network-server-0": "virtwork-network",
+			"virtwork-network-client-0": "virtwork-network",
+		}))
+		Expect(workloadNamespaces(cfg, []string{"cpu", "network"})).To(Equal([]string{"virtwork-cpu", "virtwork-network"}))
+	})
+
+	It("should keep every workload in the namespace by default", func() {
+		cfg := &config.Config{Namespace: "virtwork"}
+		for _, p := range plansFor(cfg, "cpu", "network") {
+			Expect(p.vmSpec.Namespace).To(Equal("virtwork"), p.vmName)
+		}
+		Expect(workloadNamespaces(cfg, []string{"cpu", "network"})).To(Equal([]string{"virtwork"}))
+	})
+
+	It("should resolve the network server in the network workload's namespace", func() {
+		cfg := &config.Config{Namespace: "virtwork", NamespacePerWorkload: true}
+		plans := plansIn(plansFor(cfg, "cpu", "network"), "virtwork-network")
+		Expect(planNames(plans)).To(Equal([]string{"virtwork-network-server-0", "virtwork-network-client-0"}))
+
+		Expect(plans[1].vmSpec.CloudInitUserdata).To(
+			ContainSubstring("virtwork-iperf3-server.virtwork-network.svc.cluster.local"))
+		svc := plans[1].workload.ServiceSpec()
+		Expect(svc.Namespace).To(Equal("virtwork-network"))
+	})
+
+	It("should wait for the VMs of every namespace", func() {
+		vmi := func(name, namespace string) *kubevirtv1.VirtualMachineInstance {
+			return &kubevirtv1.VirtualMachineInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: kubevirtv1.Running},
+			}
+		}
+		c := fake.NewClientBuilder().WithScheme(cluster.NewScheme()).WithObjects(
+			vmi("virtwork-cpu-0", "virtwork-cpu"),
+			vmi("virtwork-network-server-0", "virtwork-network"),
+			vmi("virtwork-network-client-0", "virtwork-network"),
+		).Build()
+		cfg := &config.Config{Namespace: "virtwork", NamespacePerWorkload: true, ReadinessLevel: constants.ReadinessPhase}
+
+		var done []string
+		results := waitForPlans(context.Background(), c, cfg, plansFor(cfg, "cpu", "network"),
+			time.Second, 10*time.Millisecond, false, nil, func(name string, _ wait.Result) { done = append(done, name) })
+		Expect(results).To(HaveLen(3))
+		for name, r := range results {
+			Expect(r.Err).NotTo(HaveOccurred(), name)
+		}
+		Expect(done).To(HaveLen(3))
+	})
+
+	It("should log a VMI not yet created as a JSON record with its VM name", func() {
+		c := fake.NewClientBuilder().WithScheme(cluster.NewScheme()).Build()
+		cfg := &config.Config{Namespace: "virtwork", ReadinessLevel: constants.ReadinessPhase}
+
+		var logs bytes.Buffer
+		logger, err := logging.New(&logs, logging.FormatJSON, true)
+		Expect(err).NotTo(HaveOccurred())
+		waitForPlans(context.Background(), c, cfg, plansFor(cfg, "cpu"),
+			30*time.Millisecond, 10*time.Millisecond, false, logger.With("run_id", "run-1"), nil)
+
+		var record map[string]any
+		Expect(json.Unmarshal(bytes.SplitN(logs.Bytes(), []byte("\n"), 2)[0], &record)).To(Succeed())
+		Expect(record).To(HaveKeyWithValue("level", "DEBUG"))
+		Expect(record).To(HaveKeyWithValue("msg", "VM virtwork-cpu-0: VMI not yet created, retrying..."))
+		Expect(record).To(HaveKeyWithValue("vm_name", "virtwork-cpu-0"))
+		Expect(record).To(HaveKeyWithValue("namespace", "virtwork"))
+		Expect(record).To(HaveKeyWithValue("run_id", "run-1"))
+	})
+
+	It("should clean up the derived namespaces that exist", func() {
+		c := fake.NewClientBuilder().WithScheme(cluster.NewScheme()).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "virtwork-cpu"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "virtwork-network"}},
+		).Build()
+
+		namespaces, err := runNamespaces(context.Background(), c, &config.Config{Namespace: "virtwork", NamespacePerWorkload: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namespaces).To(ConsistOf("virtwork-cpu", "virtwork-network"))
+
+		namespaces, err = runNamespaces(context.Background(), c, &config.Config{Namespace: "virtwork"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namespaces).To(Equal([]string{"virtwork"}))
+	})
+
+	It("should list each VM's namespace in a dry run", func() {
+		var out bytes.Buffer
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"run", "--dry-run", "--no-audit", "--output", "json",
+			"--workloads", "cpu,memory", "--namespace-per-workload"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		var listing dryRunListing
+		Expect(json.Unmarshal(out.Bytes(), &listing)).To(Succeed())
+		Expect(listing.VMs).To(HaveLen(2))
+		Expect(listing.VMs[0].Namespace).To(Equal("virtwork-cpu"))
+		Expect(listing.VMs[1].Namespace).To(Equal("virtwork-memory"))
+	})
+})
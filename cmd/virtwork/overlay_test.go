@@ -0,0 +1,42 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/config"
+)
+
+var _ = Describe("cloud-init overlay", func() {
+	wlCfg := config.WorkloadConfig{Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi"}
+
+	It("should merge the overlay into the userdata of registry workloads", func() {
+		cfg := &config.Config{Namespace: "virtwork", CloudInitOverlay: "runcmd:\n  - echo overlay\n"}
+		registry, opts, err := newRegistry(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		w, err := registry.Get("cpu", wlCfg, opts...)
+		Expect(err).NotTo(HaveOccurred())
+		userdata, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userdata).To(ContainSubstring("echo overlay"))
+	})
+
+	It("should fail a dry run with an invalid overlay", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "overlay.yaml")
+		Expect(os.WriteFile(path, []byte("users:\n  - name: admin\n"), 0644)).To(Succeed())
+
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"run", "--dry-run", "--no-audit", "--workloads", "cpu", "--cloud-init-overlay", path})
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring("parsing cloud-init overlay " + path)))
+	})
+})
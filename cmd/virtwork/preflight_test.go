@@ -0,0 +1,55 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("preflight command", func() {
+	preflight := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"preflight"}, args...))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	It("should list the packages of every default workload", func() {
+		out, err := preflight()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`(?m)^WORKLOAD\s+IMAGE\s+PACKAGES$`))
+		Expect(out).To(MatchRegexp(`(?m)^cpu\s+quay\.io/containerdisks/fedora:41\s+stress-ng$`))
+		Expect(out).To(MatchRegexp(`(?m)^redis\s+\S+\s+redis, memtier-benchmark$`))
+		Expect(out).To(MatchRegexp(`(?m)^web\s+\S+\s+nginx, wrk$`))
+		Expect(out).To(MatchRegexp(`(?m)^fs\s+\S+\s+-$`))
+		Expect(out).NotTo(ContainSubstring("monitor"))
+	})
+
+	It("should check only the named workloads against the given image", func() {
+		out, err := preflight("--workloads", "database,monitor", "--image", "quay.io/example/rhel:9")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`(?m)^database\s+quay\.io/example/rhel:9\s+postgresql-server$`))
+		Expect(out).To(MatchRegexp(`(?m)^monitor\s+quay\.io/example/rhel:9\s+golang-github-prometheus$`))
+		Expect(out).NotTo(ContainSubstring("stress-ng"))
+	})
+
+	It("should use each workload's configured image and skip disabled workloads", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte(
+			"images:\n  disk: quay.io/example/fio:1\nworkloads:\n  cpu:\n    enabled: false\n"), 0o600)).To(Succeed())
+
+		out, err := preflight("--config", path, "--workloads", "cpu,disk")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`(?m)^disk\s+quay\.io/example/fio:1\s+fio$`))
+		Expect(out).NotTo(MatchRegexp(`(?m)^cpu\s`))
+	})
+})
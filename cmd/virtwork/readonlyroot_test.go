@@ -0,0 +1,44 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+var _ = Describe("checkReadOnlyRoot", func() {
+	wlCfg := config.WorkloadConfig{Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi"}
+
+	It("should accept workloads that keep their data on a data disk", func() {
+		cfg := &config.Config{ReadOnlyRoot: true}
+		for _, name := range workloads.AllWorkloadNames {
+			w, err := workloads.DefaultRegistry().Get(name, wlCfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(checkReadOnlyRoot(cfg, name, w)).To(Succeed(), name)
+		}
+	})
+
+	It("should reject a workload that writes to the root disk", func() {
+		w, err := workloads.DefaultRegistry().Get("monitor", wlCfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(checkReadOnlyRoot(&config.Config{}, "monitor", w)).To(Succeed())
+		Expect(checkReadOnlyRoot(&config.Config{ReadOnlyRoot: true}, "monitor", w)).To(
+			MatchError(`workload "monitor" writes /var/lib/virtwork-prometheus to the root disk, which --read-only-root discards`))
+	})
+
+	It("should fail a dry run that includes the monitor", func() {
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"run", "--dry-run", "--no-audit", "--read-only-root", "--workloads", "cpu,monitor"})
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring("--read-only-root discards")))
+	})
+})
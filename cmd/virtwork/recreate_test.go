@@ -0,0 +1,162 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/logging"
+	"github.com/opdev/virtwork/internal/vm"
+	"github.com/opdev/virtwork/internal/wait"
+)
+
+var _ = Describe("recreateFailedVMs", func() {
+	const namespace = "test-ns"
+	ctx := context.Background()
+	cfg := &config.Config{Namespace: namespace, ReadinessLevel: constants.ReadinessPhase, CreateRetries: 1}
+
+	spec := func(name string) *vm.VMSpecOpts {
+		return &vm.VMSpecOpts{
+			Name:               name,
+			Namespace:          namespace,
+			ContainerDiskImage: "test-image",
+			CloudInitUserdata:  "#cloud-config\n",
+			CPUCores:           1,
+			Memory:             "1Gi",
+		}
+	}
+
+	vmi := func(name string, phase kubevirtv1.VirtualMachineInstancePhase) *kubevirtv1.VirtualMachineInstance {
+		return &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: phase},
+		}
+	}
+
+	// newClient simulates KubeVirt: deleting a VM deletes its VMI, and a
+	// created VM has a Running VMI.
+	newClient := func(objs ...client.Object) (client.Client, func() []string) {
+		var mu sync.Mutex
+		var created []string
+		c := fake.NewClientBuilder().
+			WithScheme(cluster.NewScheme()).
+			WithObjects(objs...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					if _, ok := obj.(*kubevirtv1.VirtualMachine); ok {
+						_ = cl.Delete(ctx, vmi(obj.GetName(), ""))
+					}
+					return cl.Delete(ctx, obj, opts...)
+				},
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if err := cl.Create(ctx, obj, opts...); err != nil {
+						return err
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					created = append(created, obj.GetName())
+					return nil
+				},
+				Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					mu.Lock()
+					started := slices.Contains(created, key.Name)
+					mu.Unlock()
+					if v, ok := obj.(*kubevirtv1.VirtualMachineInstance); ok && started {
+						*v = *vmi(key.Name, kubevirtv1.Running)
+						return nil
+					}
+					return cl.Get(ctx, key, obj, opts...)
+				},
+			}).
+			Build()
+		return c, func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+			return created
+		}
+	}
+
+	It("should recreate a failed VM once and wait for it again", func() {
+		c, created := newClient(vm.BuildVMSpec(*spec("virtwork-cpu-0")), vmi("virtwork-cpu-0", kubevirtv1.Failed))
+		results := map[string]wait.Result{
+			"virtwork-cpu-0": {
+				Err:       fmt.Errorf("VM test-ns/virtwork-cpu-0 entered phase Failed: %w", wait.ErrVMFailed),
+				Elapsed:   time.Minute,
+				LastPhase: kubevirtv1.Failed,
+			},
+		}
+
+		var progress bytes.Buffer
+		var done []string
+		recreated := recreateFailedVMs(ctx, c, cfg, map[string]*vm.VMSpecOpts{"virtwork-cpu-0": spec("virtwork-cpu-0")},
+			results, time.Second, 10*time.Millisecond, textLogger(&progress), nil, func(name string, _ wait.Result) { done = append(done, name) })
+
+		Expect(recreated).To(Equal([]string{"virtwork-cpu-0"}))
+		Expect(created()).To(Equal([]string{"virtwork-cpu-0"}))
+		Expect(done).To(Equal([]string{"virtwork-cpu-0"}))
+		Expect(results["virtwork-cpu-0"].Err).NotTo(HaveOccurred())
+		Expect(results["virtwork-cpu-0"].LastPhase).To(Equal(kubevirtv1.Running))
+		Expect(results["virtwork-cpu-0"].Elapsed).To(BeNumerically(">=", time.Minute))
+		Expect(progress.String()).To(ContainSubstring("VM virtwork-cpu-0 failed (Failed); recreating it"))
+	})
+
+	It("should log the recreated VM as a JSON record with its fields", func() {
+		c, _ := newClient(vm.BuildVMSpec(*spec("virtwork-cpu-0")), vmi("virtwork-cpu-0", kubevirtv1.Failed))
+		results := map[string]wait.Result{
+			"virtwork-cpu-0": {
+				Err:       fmt.Errorf("VM test-ns/virtwork-cpu-0 entered phase Failed: %w", wait.ErrVMFailed),
+				LastPhase: kubevirtv1.Failed,
+			},
+		}
+
+		var logs bytes.Buffer
+		logger, err := logging.New(&logs, logging.FormatJSON, false)
+		Expect(err).NotTo(HaveOccurred())
+		recreateFailedVMs(ctx, c, cfg, map[string]*vm.VMSpecOpts{"virtwork-cpu-0": spec("virtwork-cpu-0")},
+			results, time.Second, 10*time.Millisecond, logger.With("run_id", "run-a"), nil, nil)
+
+		var record map[string]any
+		Expect(json.Unmarshal(bytes.SplitN(logs.Bytes(), []byte("\n"), 2)[0], &record)).To(Succeed())
+		Expect(record).To(HaveKeyWithValue("level", "INFO"))
+		Expect(record).To(HaveKeyWithValue("msg", "VM virtwork-cpu-0 failed (Failed); recreating it"))
+		Expect(record).To(HaveKeyWithValue("vm_name", "virtwork-cpu-0"))
+		Expect(record).To(HaveKeyWithValue("phase", "Failed"))
+		Expect(record).To(HaveKeyWithValue("run_id", "run-a"))
+	})
+
+	It("should leave VMs that did not fail, or were not created by the run, alone", func() {
+		c, created := newClient(vmi("virtwork-cpu-1", kubevirtv1.Failed))
+		timedOut := wait.Result{Err: fmt.Errorf("timed out waiting for VM test-ns/virtwork-cpu-0 to become ready")}
+		reused := wait.Result{Err: fmt.Errorf("VM test-ns/virtwork-cpu-1 entered phase Failed: %w", wait.ErrVMFailed)}
+		results := map[string]wait.Result{
+			"virtwork-cpu-0": timedOut,
+			"virtwork-cpu-1": reused,
+		}
+
+		recreated := recreateFailedVMs(ctx, c, cfg, map[string]*vm.VMSpecOpts{"virtwork-cpu-0": spec("virtwork-cpu-0")},
+			results, time.Second, 10*time.Millisecond, textLogger(&bytes.Buffer{}), nil, nil)
+
+		Expect(recreated).To(BeEmpty())
+		Expect(created()).To(BeEmpty())
+		Expect(results["virtwork-cpu-0"]).To(Equal(timedOut))
+		Expect(results["virtwork-cpu-1"]).To(Equal(reused))
+	})
+})
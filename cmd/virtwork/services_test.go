@@ -0,0 +1,60 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/audit"
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/config"
+)
+
+var _ = Describe("createServices", func() {
+	const namespace = "test-ns"
+	ctx := context.Background()
+
+	It("should expose and admit the configured network port and protocol", func() {
+		c := fake.NewClientBuilder().WithScheme(cluster.NewScheme()).Build()
+		cfg := &config.Config{
+			Namespace:     namespace,
+			NetworkPolicy: true,
+			Workloads: map[string]config.WorkloadConfig{
+				"network": {Enabled: true, Port: 6000, Protocol: "udp"},
+			},
+		}
+		registry, opts, err := newRegistry(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		created, err := createServices(ctx, c, cfg, registry, opts, []string{"cpu", "network"}, 1, "run-a", audit.NoOpAuditor{}, 0, slog.New(slog.DiscardHandler))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(Equal(1))
+
+		svc := &corev1.Service{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "virtwork-iperf3-server", Namespace: namespace}, svc)).To(Succeed())
+		Expect(svc.Spec.Ports).To(Equal([]corev1.ServicePort{
+			{Name: "iperf3", Port: 6000, TargetPort: intstr.FromInt32(6000), Protocol: corev1.ProtocolTCP},
+			{Name: "iperf3-udp", Port: 6000, TargetPort: intstr.FromInt32(6000), Protocol: corev1.ProtocolUDP},
+		}))
+
+		np := &networkingv1.NetworkPolicy{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "virtwork-iperf3-server", Namespace: namespace}, np)).To(Succeed())
+		Expect(np.Spec.Ingress).To(HaveLen(1))
+		port, tcp, udp := intstr.FromInt32(6000), corev1.ProtocolTCP, corev1.ProtocolUDP
+		Expect(np.Spec.Ingress[0].Ports).To(Equal([]networkingv1.NetworkPolicyPort{
+			{Protocol: &tcp, Port: &port},
+			{Protocol: &udp, Port: &port},
+		}))
+	})
+})
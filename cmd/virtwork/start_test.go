@@ -0,0 +1,85 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+var _ = Describe("startRunVMs", func() {
+	const namespace = "test-ns"
+	ctx := context.Background()
+
+	stoppedVMIn := func(name, ns, runID string) *kubevirtv1.VirtualMachine {
+		return vm.BuildVMSpec(vm.VMSpecOpts{
+			Name:               name,
+			Namespace:          ns,
+			ContainerDiskImage: "test-image",
+			CloudInitUserdata:  "#cloud-config\n",
+			CPUCores:           1,
+			Memory:             "1Gi",
+			Labels: map[string]string{
+				constants.LabelManagedBy: constants.ManagedByValue,
+				constants.LabelRunID:     runID,
+			},
+			StartStopped: true,
+		})
+	}
+	stoppedVM := func(name, runID string) *kubevirtv1.VirtualMachine {
+		return stoppedVMIn(name, namespace, runID)
+	}
+
+	running := func(c client.Client, name string) bool {
+		got := &kubevirtv1.VirtualMachine{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, got)).To(Succeed())
+		return got.Spec.Running != nil && *got.Spec.Running
+	}
+
+	It("should start only the VMs of the run, in name order", func() {
+		c := fake.NewClientBuilder().WithScheme(cluster.NewScheme()).WithObjects(
+			stoppedVM("virtwork-memory-0", "run-a"),
+			stoppedVM("virtwork-cpu-0", "run-a"),
+			stoppedVM("virtwork-cpu-1", "run-b"),
+		).Build()
+
+		started, err := startRunVMs(ctx, c, []string{namespace}, "run-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(started).To(Equal([]string{"virtwork-cpu-0", "virtwork-memory-0"}))
+		Expect(running(c, "virtwork-cpu-0")).To(BeTrue())
+		Expect(running(c, "virtwork-memory-0")).To(BeTrue())
+		Expect(running(c, "virtwork-cpu-1")).To(BeFalse())
+	})
+
+	It("should start the VMs of the run in every namespace", func() {
+		c := fake.NewClientBuilder().WithScheme(cluster.NewScheme()).WithObjects(
+			stoppedVMIn("virtwork-cpu-0", "virtwork-cpu", "run-a"),
+			stoppedVMIn("virtwork-memory-0", "virtwork-memory", "run-a"),
+		).Build()
+
+		started, err := startRunVMs(ctx, c, []string{"virtwork-cpu", "virtwork-memory"}, "run-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(started).To(Equal([]string{"virtwork-cpu-0", "virtwork-memory-0"}))
+		got := &kubevirtv1.VirtualMachine{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "virtwork-memory-0", Namespace: "virtwork-memory"}, got)).To(Succeed())
+		Expect(*got.Spec.Running).To(BeTrue())
+	})
+
+	It("should start nothing for an unknown run", func() {
+		c := fake.NewClientBuilder().WithScheme(cluster.NewScheme()).Build()
+
+		started, err := startRunVMs(ctx, c, []string{namespace}, "run-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(started).To(BeEmpty())
+	})
+})
@@ -0,0 +1,272 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/virtwork/internal/audit"
+	"github.com/opdev/virtwork/internal/cleanup"
+	"github.com/opdev/virtwork/internal/discover"
+	"github.com/opdev/virtwork/internal/status"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+var _ = Describe("renderSummary", func() {
+	var summary deploymentSummary
+	plans := []vmPlan{
+		{vmName: "virtwork-cpu-0", component: "cpu", vmSpec: &vm.VMSpecOpts{ContainerDiskImage: "img-a"}},
+		{vmName: "virtwork-network-server-0", component: "network", role: "server", vmSpec: &vm.VMSpecOpts{ContainerDiskImage: "img-b"}},
+		{vmName: "virtwork-network-client-0", component: "network", role: "client", vmSpec: &vm.VMSpecOpts{ContainerDiskImage: "img-b"}},
+	}
+
+	BeforeEach(func() {
+		started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		summary = deploymentSummary{
+			RunID:       "abc-123",
+			Namespace:   "virtwork",
+			Counts:      summaryCounts{VMs: 3, Services: 1, Secrets: 3},
+			VMs:         planSummaries(plans),
+			Images:      planImages(plans),
+			StartedAt:   started,
+			CompletedAt: started.Add(90 * time.Second),
+		}
+	})
+
+	It("should render the table format", func() {
+		var buf bytes.Buffer
+		Expect(renderSummary(&buf, summary, "table")).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("Deployment Summary"))
+		Expect(buf.String()).To(ContainSubstring("Run ID:       abc-123"))
+		Expect(buf.String()).To(ContainSubstring("VMs created:  3"))
+		Expect(buf.String()).To(ContainSubstring("Images:       img-a, img-b"))
+	})
+
+	It("should render valid JSON with the expected keys", func() {
+		var buf bytes.Buffer
+		Expect(renderSummary(&buf, summary, "json")).To(Succeed())
+
+		var parsed map[string]interface{}
+		Expect(json.Unmarshal(buf.Bytes(), &parsed)).To(Succeed())
+		Expect(parsed).To(HaveKeyWithValue("run_id", "abc-123"))
+		Expect(parsed).To(HaveKeyWithValue("namespace", "virtwork"))
+		Expect(parsed).To(HaveKeyWithValue("images", []interface{}{"img-a", "img-b"}))
+		Expect(parsed).To(HaveKeyWithValue("started_at", "2026-03-01T12:00:00Z"))
+		Expect(parsed).To(HaveKeyWithValue("completed_at", "2026-03-01T12:01:30Z"))
+		Expect(parsed["counts"]).To(Equal(map[string]interface{}{
+			"vms": 3.0, "services": 1.0, "secrets": 3.0,
+		}))
+
+		vms, ok := parsed["vms"].([]interface{})
+		Expect(ok).To(BeTrue())
+		Expect(vms).To(HaveLen(3))
+		Expect(vms[0]).To(Equal(map[string]interface{}{
+			"name": "virtwork-cpu-0", "component": "cpu", "image": "img-a",
+		}))
+		Expect(vms[1]).To(HaveKeyWithValue("role", "server"))
+	})
+
+	It("should show a single image on one line", func() {
+		summary.Images = planImages(plans[:1])
+		var buf bytes.Buffer
+		Expect(renderSummary(&buf, summary, "table")).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("Image:        img-a\n"))
+	})
+
+	It("should reject an unknown format", func() {
+		var buf bytes.Buffer
+		Expect(renderSummary(&buf, summary, "xml")).To(MatchError(ContainSubstring(`invalid output format "xml"`)))
+		Expect(buf.Len()).To(BeZero())
+	})
+})
+
+var _ = Describe("dry-run JSON listing", func() {
+	It("should list the planned VMs as valid JSON", func() {
+		var buf bytes.Buffer
+		Expect(writeJSON(&buf, dryRunListing{
+			RunID:     "abc-123",
+			Namespace: "virtwork",
+			VMs: planSummaries([]vmPlan{
+				{vmName: "virtwork-cpu-0", component: "cpu", vmSpec: &vm.VMSpecOpts{ContainerDiskImage: "img-a"}},
+			}),
+		})).To(Succeed())
+
+		var parsed map[string]interface{}
+		Expect(json.Unmarshal(buf.Bytes(), &parsed)).To(Succeed())
+		Expect(parsed).To(HaveKeyWithValue("run_id", "abc-123"))
+		Expect(parsed).To(HaveKeyWithValue("namespace", "virtwork"))
+		Expect(parsed["vms"]).To(ConsistOf(map[string]interface{}{
+			"name": "virtwork-cpu-0", "component": "cpu", "image": "img-a",
+		}))
+	})
+
+	It("should list an empty plan as an empty array", func() {
+		var buf bytes.Buffer
+		Expect(writeJSON(&buf, dryRunListing{VMs: planSummaries(nil)})).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring(`"vms": []`))
+	})
+})
+
+var _ = Describe("printOrphanReport", func() {
+	It("should list each orphan with the reason", func() {
+		var buf bytes.Buffer
+		printOrphanReport(&buf, "virtwork", &cleanup.OrphanReport{
+			Secrets:  []string{"virtwork-cpu-1-cloudinit"},
+			Services: []string{"virtwork-nginx-server"},
+		})
+		Expect(buf.String()).To(ContainSubstring("Orphaned resources in namespace virtwork:"))
+		Expect(buf.String()).To(ContainSubstring("Secret   virtwork-cpu-1-cloudinit (no VM virtwork-cpu-1)"))
+		Expect(buf.String()).To(ContainSubstring("Service  virtwork-nginx-server (selects no VM)"))
+	})
+
+	It("should say so when there are no orphans", func() {
+		var buf bytes.Buffer
+		printOrphanReport(&buf, "virtwork", &cleanup.OrphanReport{})
+		Expect(buf.String()).To(Equal("No orphaned resources in namespace virtwork\n"))
+	})
+})
+
+var _ = Describe("printCleanupPlan", func() {
+	It("should list each resource and the totals that would be deleted", func() {
+		var buf bytes.Buffer
+		printCleanupPlan(&buf, "virtwork", &cleanup.CleanupResult{
+			VMsDeleted:       1,
+			SecretsDeleted:   1,
+			RBACDeleted:      1,
+			NamespaceDeleted: true,
+			Planned: []string{
+				"Secret/virtwork-cpu-0-cloudinit",
+				"ServiceAccount/virtwork-api-churn",
+				"VM/virtwork-cpu-0",
+			},
+		})
+		Expect(buf.String()).To(Equal(`Resources that would be deleted in namespace virtwork:
+  Secret          virtwork-cpu-0-cloudinit
+  ServiceAccount  virtwork-api-churn
+  VM              virtwork-cpu-0
+Dry run: 1 VMs, 0 services, 1 secrets would be deleted, 1 RBAC objects, and namespace virtwork
+`))
+	})
+
+	It("should say so when nothing matches", func() {
+		var buf bytes.Buffer
+		printCleanupPlan(&buf, "virtwork", &cleanup.CleanupResult{})
+		Expect(buf.String()).To(Equal("No managed resources to delete in namespace virtwork\n" +
+			"Dry run: 0 VMs, 0 services, 0 secrets would be deleted\n"))
+	})
+})
+
+var _ = Describe("printRuns", func() {
+	runs := []discover.Run{
+		{RunID: "run-a", Namespace: "virtwork", VMs: 3, Services: 1, Secrets: 3,
+			Components: []string{"memory", "network"}, CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{RunID: "run-b", Namespace: "other", Secrets: 1},
+	}
+
+	It("should print a row per run", func() {
+		var buf bytes.Buffer
+		printRuns(&buf, runs, defaultRunColumns(false))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(3))
+		Expect(strings.Fields(lines[0])).To(Equal([]string{"RUN-ID", "VMS", "SERVICES", "SECRETS", "COMPONENTS", "CREATED"}))
+		Expect(strings.Fields(lines[1])).To(Equal([]string{"run-a", "3", "1", "3", "memory,network", "2026-03-01T12:00:00Z"}))
+		Expect(strings.Fields(lines[2])).To(Equal([]string{"run-b", "0", "0", "1", "-", "-"}))
+	})
+
+	It("should add a namespace column for all namespaces", func() {
+		var buf bytes.Buffer
+		printRuns(&buf, runs, defaultRunColumns(true))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(strings.Fields(lines[0])[0]).To(Equal("NAMESPACE"))
+		Expect(strings.Fields(lines[2])[:2]).To(Equal([]string{"other", "run-b"}))
+	})
+
+	It("should say so when no runs are found", func() {
+		var buf bytes.Buffer
+		printRuns(&buf, nil, defaultRunColumns(false))
+		Expect(buf.String()).To(Equal("No runs found\n"))
+	})
+})
+
+var _ = Describe("--columns", func() {
+	newCmd := func(columns string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringSlice("columns", nil, "")
+		if columns != "" {
+			Expect(cmd.Flags().Set("columns", columns)).To(Succeed())
+		}
+		return cmd
+	}
+
+	It("should print the selected audit list columns in the requested order", func() {
+		columns, err := selectColumns(newCmd("status,run-id,vms"), executionColumns, executionColumns)
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		printExecutions(&buf, []audit.ExecutionSummary{
+			{RunID: "run-a", Command: "run", Namespace: "virtwork", Status: "success", VMCount: 7},
+		}, columns)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(strings.Fields(lines[0])).To(Equal([]string{"STATUS", "RUN", "ID", "VMS"}))
+		Expect(strings.Fields(lines[1])).To(Equal([]string{"success", "run-a", "7"}))
+	})
+
+	It("should keep every audit list column by default", func() {
+		columns, err := selectColumns(newCmd(""), executionColumns, executionColumns)
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		printExecutions(&buf, []audit.ExecutionSummary{
+			{RunID: "run-a", Command: "run", Namespace: "virtwork", Status: "in_progress", StartedAt: "2026-01-01T00:00:00Z"},
+		}, columns)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(strings.Fields(lines[1])).To(Equal([]string{"run-a", "run", "virtwork", "in_progress", "0", "2026-01-01T00:00:00Z", "-"}))
+	})
+
+	It("should print the selected status columns", func() {
+		columns, err := selectColumns(newCmd("phase,name,ready"), statusColumns, defaultStatusColumns(false))
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		printStatus(&buf, []status.VMStatus{{Name: "virtwork-cpu-0", Phase: "Running"}}, columns)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(strings.Fields(lines[0])).To(Equal([]string{"PHASE", "NAME", "READY"}))
+		Expect(strings.Fields(lines[1])).To(Equal([]string{"Running", "virtwork-cpu-0", "-"}))
+	})
+
+	It("should print the live VM state in the default status columns", func() {
+		columns, err := selectColumns(newCmd(""), statusColumns, defaultStatusColumns(false))
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		printStatus(&buf, []status.VMStatus{{
+			Name: "virtwork-cpu-0", Component: "cpu", Phase: "Running",
+			VMReady: true, AgentConnected: true, Node: "worker-0",
+		}}, columns)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(strings.Fields(lines[0])).To(Equal([]string{"NAME", "COMPONENT", "ROLE", "PHASE", "VM-READY", "AGENT", "NODE", "MESSAGE"}))
+		Expect(strings.Fields(lines[1])).To(Equal([]string{"virtwork-cpu-0", "cpu", "-", "Running", "true", "true", "worker-0", "-"}))
+	})
+
+	It("should print the selected list columns", func() {
+		columns, err := selectColumns(newCmd("run-id,namespace"), runColumns, defaultRunColumns(false))
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		printRuns(&buf, []discover.Run{{RunID: "run-a", Namespace: "virtwork"}}, columns)
+		Expect(strings.Fields(buf.String())).To(Equal([]string{"RUN-ID", "NAMESPACE", "run-a", "virtwork"}))
+	})
+
+	It("should reject an unknown column", func() {
+		_, err := selectColumns(newCmd("run-id,owner"), executionColumns, executionColumns)
+		Expect(err).To(MatchError(ContainSubstring(`invalid --columns: unknown column "owner"`)))
+	})
+})
@@ -0,0 +1,22 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("run --update", func() {
+	It("should reject --reuse-run-id", func() {
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"run", "--dry-run", "--no-audit", "--update",
+			"--reuse-run-id", "0b5c6a3e-6f1d-4e2a-9a57-6d2f0f8e7c11"})
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring("--update cannot be combined with --reuse-run-id")))
+	})
+})
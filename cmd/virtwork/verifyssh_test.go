@@ -0,0 +1,72 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/audit"
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/config"
+)
+
+var _ = Describe("verifySSH", func() {
+	const namespace = "test-ns"
+	ctx := context.Background()
+
+	var (
+		runCmd           *cobra.Command
+		c                client.Client
+		cfg              *config.Config
+		progress, stderr bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		runCmd, _, _ = newRootCmd().Find([]string{"run"})
+		progress.Reset()
+		stderr.Reset()
+		runCmd.SetErr(&stderr)
+		cfg = &config.Config{Namespace: namespace, SSHUser: "virtwork", SSHPassword: "secret"}
+		// The VMI has not reported an IP, as when its pod network is not
+		// routable from here.
+		c = fake.NewClientBuilder().WithScheme(cluster.NewScheme()).WithObjects(
+			&kubevirtv1.VirtualMachineInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "virtwork-cpu-0", Namespace: namespace},
+			},
+		).Build()
+	})
+
+	verify := func(vmNames ...string) error {
+		return verifySSH(ctx, runCmd, c, audit.NoOpAuditor{}, 0, cfg, vmNames, nil, time.Second, textLogger(&progress))
+	}
+
+	It("should skip the check when no SSH credentials are configured", func() {
+		cfg.SSHPassword = ""
+		Expect(verify("virtwork-cpu-0")).To(Succeed())
+		Expect(stderr.String()).To(ContainSubstring("skipping --verify-ssh: no SSH password or key is configured"))
+		Expect(progress.String()).To(BeEmpty())
+	})
+
+	It("should skip, with a warning, a VM it cannot reach", func() {
+		Expect(verify("virtwork-cpu-0")).To(Succeed())
+		Expect(stderr.String()).To(ContainSubstring("Warning: skipping SSH check of VM virtwork-cpu-0"))
+		Expect(progress.String()).NotTo(ContainSubstring("SSH answering"))
+	})
+
+	It("should fail when a VM's VMI cannot be read", func() {
+		err := verify("virtwork-cpu-0", "virtwork-cpu-1")
+		Expect(err).To(MatchError("1 of 2 VMs failed SSH check"))
+		Expect(stderr.String()).To(ContainSubstring("VM virtwork-cpu-1: getting VMI test-ns/virtwork-cpu-1"))
+	})
+})
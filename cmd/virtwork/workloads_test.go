@@ -0,0 +1,84 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+var _ = Describe("selectWorkloads", func() {
+	cfg := &config.Config{Workloads: map[string]config.WorkloadConfig{
+		"disk":   {Enabled: false},
+		"cpu":    {Enabled: true, VMCount: 2},
+		"memory": {Enabled: true},
+	}}
+
+	It("should skip a requested workload that is disabled", func() {
+		selected, disabled := selectWorkloads(cfg, []string{"cpu", "disk", "memory"})
+		Expect(selected).To(Equal([]string{"cpu", "memory"}))
+		Expect(disabled).To(Equal([]string{"disk"}))
+	})
+
+	It("should expand all to every built-in workload not disabled", func() {
+		selected, disabled := selectWorkloads(cfg, []string{"all"})
+		Expect(selected).To(HaveLen(len(workloads.AllWorkloadNames) - 1))
+		Expect(selected).NotTo(ContainElement("disk"))
+		Expect(selected).To(ContainElements("cpu", "database", "network"))
+		Expect(disabled).To(BeEmpty())
+	})
+
+	It("should add named workloads to all without duplicates", func() {
+		selected, _ := selectWorkloads(cfg, []string{"all", "monitor", "cpu"})
+		Expect(selected[len(selected)-1]).To(Equal("monitor"))
+		Expect(selected).To(HaveLen(len(workloads.AllWorkloadNames)))
+	})
+
+	It("should keep every workload without a config file", func() {
+		selected, disabled := selectWorkloads(&config.Config{}, []string{"all"})
+		Expect(selected).To(Equal(workloads.AllWorkloadNames))
+		Expect(disabled).To(BeEmpty())
+	})
+})
+
+var _ = Describe("run with disabled workloads", func() {
+	run := func(args ...string) (dryRunListing, string, error) {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte("workloads:\n  disk:\n    enabled: false\n"), 0o600)).To(Succeed())
+
+		var out, stderr bytes.Buffer
+		rootCmd := newRootCmd()
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&stderr)
+		rootCmd.SetArgs(append([]string{"run", "--dry-run", "--no-audit", "--output", "json", "--config", path}, args...))
+		err := rootCmd.Execute()
+
+		var listing dryRunListing
+		if err == nil {
+			Expect(json.Unmarshal(out.Bytes(), &listing)).To(Succeed())
+		}
+		return listing, stderr.String(), err
+	}
+
+	It("should plan no VMs for a disabled workload and say why", func() {
+		listing, stderr, err := run("--workloads", "cpu,disk")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listing.VMs).To(HaveLen(1))
+		Expect(listing.VMs[0].Component).To(Equal("cpu"))
+		Expect(stderr).To(ContainSubstring("Skipping workload disk: disabled in the config file"))
+	})
+
+	It("should fail when every requested workload is disabled", func() {
+		_, _, err := run("--workloads", "disk")
+		Expect(err).To(MatchError(ContainSubstring("no workloads to deploy")))
+	})
+})
@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,7 +15,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 
 	"github.com/opdev/virtwork/internal/config"
 )
@@ -25,10 +26,12 @@ type Auditor interface {
 	StartExecution(ctx context.Context, cmd string, cfg *config.Config) (executionID int64, runID string, err error)
 	// CompleteExecution finalises the audit_log row with status and optional error summary.
 	CompleteExecution(ctx context.Context, id int64, status string, errSummary string) error
-	// LinkCleanupToRuns sets linked_run_ids on a cleanup audit_log row.
+	// LinkCleanupToRuns sets linked_run_ids on a cleanup or scale audit_log row.
 	LinkCleanupToRuns(ctx context.Context, cleanupID int64, runIDs []string) error
 	// RecordCleanupCounts updates cleanup-specific counters on the audit_log row.
 	RecordCleanupCounts(ctx context.Context, id int64, vmsDeleted, servicesDeleted, secretsDeleted int, namespaceDeleted bool) error
+	// RecordTotals sets the planned VM and workload counts on the audit_log row.
+	RecordTotals(ctx context.Context, id int64, vmCount, workloadCount int) error
 
 	// RecordWorkload inserts a workload_details row.
 	RecordWorkload(ctx context.Context, executionID int64, w WorkloadRecord) (workloadID int64, err error)
@@ -50,13 +53,46 @@ type Auditor interface {
 	// RecordEvent inserts an events row.
 	RecordEvent(ctx context.Context, executionID int64, e EventRecord) error
 
+	// ListExecutions returns audit_log rows matching filter, newest first.
+	ListExecutions(ctx context.Context, filter ExecutionFilter) ([]ExecutionSummary, error)
+	// GetExecution returns the configuration and outcome of the run with runID.
+	GetExecution(ctx context.Context, runID string) (*ExecutionDetail, error)
+	// FindRunsByNamespace returns the run IDs of successful run executions in
+	// namespace, newest first.
+	FindRunsByNamespace(ctx context.Context, namespace string) ([]string, error)
+	// ListEvents returns the events of the run with runID whose ID is above
+	// sinceID, oldest first.
+	ListEvents(ctx context.Context, runID string, sinceID int64) ([]EventRow, error)
+	// ExportRows streams the header and rows of an exportable table to w.
+	ExportRows(ctx context.Context, table, since string, w RowWriter) error
+	// AggregateStats summarizes the executions started at or after since.
+	AggregateStats(ctx context.Context, since string) (*Stats, error)
+
 	// Close releases database resources.
 	Close() error
 }
 
+// sqlAuditor implements Auditor over database/sql. Queries are written with
+// "?" placeholders and rebound for the target database.
+type sqlAuditor struct {
+	db       *sql.DB
+	postgres bool
+}
+
+// sqliteBusyTimeout is how long a SQLite write waits for another writer to
+// release the database lock before failing as busy.
+const sqliteBusyTimeout = 5 * time.Second
+
+// Writes still busy after the busy timeout are retried up to writeRetries
+// times, waiting writeRetryBackoff longer before each retry.
+const (
+	writeRetries      = 3
+	writeRetryBackoff = 100 * time.Millisecond
+)
+
 // SQLiteAuditor implements Auditor backed by a SQLite database.
 type SQLiteAuditor struct {
-	db *sql.DB
+	sqlAuditor
 }
 
 // NewSQLiteAuditor opens (or creates) the SQLite database at dbPath and ensures
@@ -69,9 +105,12 @@ func NewSQLiteAuditor(dbPath string) (*SQLiteAuditor, error) {
 		}
 	}
 
-	dsn := dbPath + "?_journal_mode=WAL&_foreign_keys=on"
+	// The busy timeout makes a write wait for a concurrent writer's lock
+	// rather than fail with "database is locked"
+	params := fmt.Sprintf("_foreign_keys=on&_busy_timeout=%d", sqliteBusyTimeout.Milliseconds())
+	dsn := dbPath + "?_journal_mode=WAL&" + params
 	if dbPath == ":memory:" {
-		dsn = "file::memory:?mode=memory&cache=shared&_foreign_keys=on"
+		dsn = "file::memory:?mode=memory&cache=shared&" + params
 	}
 	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
@@ -82,15 +121,98 @@ func NewSQLiteAuditor(dbPath string) (*SQLiteAuditor, error) {
 		db.Close()
 		return nil, fmt.Errorf("applying audit schema: %w", err)
 	}
+	if err := addMissingColumns(db, false); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("applying audit schema: %w", err)
+	}
+
+	return &SQLiteAuditor{sqlAuditor{db: db}}, nil
+}
+
+// ContextDBPath returns the SQLite audit database path for a kubeconfig
+// context: dir/<context>.db, with every character other than letters, digits,
+// '.', '_', and '-' in the context name replaced by '_'. OpenShift context
+// names such as "default/api-example-com:6443/kube:admin" thus map to a single
+// file name.
+func ContextDBPath(dir, contextName string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, contextName)
+	if strings.Trim(sanitized, ".") == "" {
+		// "", "." and ".." would not name a file inside dir
+		sanitized = "_" + sanitized
+	}
+	return filepath.Join(dir, sanitized+".db")
+}
+
+// rebind rewrites "?" placeholders as "$1", "$2", ... for PostgreSQL. The
+// queries in this package contain no literal question marks.
+func (a *sqlAuditor) rebind(query string) string {
+	if !a.postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// insert executes an INSERT and returns the new row's id. PostgreSQL does not
+// support LastInsertId, so the id is read back with RETURNING instead.
+func (a *sqlAuditor) insert(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	if a.postgres {
+		var id int64
+		err := a.db.QueryRowContext(ctx, a.rebind(query)+" RETURNING id", args...).Scan(&id)
+		return id, err
+	}
+	res, err := a.exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// exec executes a write, retrying it while SQLite reports the database as
+// busy or locked. Other errors, and PostgreSQL errors, are returned at once.
+func (a *sqlAuditor) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := a.db.ExecContext(ctx, query, args...)
+		if err == nil || attempt > writeRetries || !isBusy(err) {
+			return res, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(time.Duration(attempt) * writeRetryBackoff):
+		}
+	}
+}
 
-	return &SQLiteAuditor{db: db}, nil
+// isBusy reports whether err is SQLite's SQLITE_BUSY or SQLITE_LOCKED.
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
 }
 
 func now() string {
 	return time.Now().UTC().Format(time.RFC3339)
 }
 
-func (a *SQLiteAuditor) StartExecution(ctx context.Context, cmd string, cfg *config.Config) (int64, string, error) {
+func (a *sqlAuditor) StartExecution(ctx context.Context, cmd string, cfg *config.Config) (int64, string, error) {
 	runID := uuid.New().String()
 
 	workloadNames := make([]string, 0, len(cfg.Workloads))
@@ -101,82 +223,88 @@ func (a *SQLiteAuditor) StartExecution(ctx context.Context, cmd string, cfg *con
 
 	sshConfigured := cfg.SSHPassword != "" || len(cfg.SSHAuthorizedKeys) > 0
 
-	res, err := a.db.ExecContext(ctx, `
+	id, err := a.insert(ctx, `
 		INSERT INTO audit_log (
-			run_id, command, status, kubeconfig_path, namespace,
+			run_id, command, status, kubeconfig_path, cluster_context, namespace,
 			container_disk_image, default_cpu_cores, default_memory, data_disk_size,
 			workloads_csv, dry_run, ssh_auth_configured, cleanup_mode,
-			wait_for_ready, ready_timeout_seconds, started_at
-		) VALUES (?, ?, 'in_progress', ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		runID, cmd, cfg.KubeconfigPath, cfg.Namespace,
+			wait_for_ready, ready_timeout_seconds, started_at,
+			git_sha, git_branch, ci_url
+		) VALUES (?, ?, 'in_progress', ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		runID, cmd, cfg.KubeconfigPath, nullIfEmpty(cfg.KubeContext), cfg.Namespace,
 		cfg.ContainerDiskImage, cfg.CPUCores, cfg.Memory, cfg.DataDiskSize,
 		workloadsCSV, boolToInt(cfg.DryRun), boolToInt(sshConfigured), cfg.CleanupMode,
 		boolToInt(cfg.WaitForReady), cfg.ReadyTimeoutSeconds, now(),
+		nullIfEmpty(cfg.GitSHA), nullIfEmpty(cfg.GitBranch), nullIfEmpty(cfg.CIURL),
 	)
 	if err != nil {
 		return 0, "", fmt.Errorf("inserting audit_log: %w", err)
 	}
-
-	id, err := res.LastInsertId()
-	if err != nil {
-		return 0, "", fmt.Errorf("getting audit_log id: %w", err)
-	}
 	return id, runID, nil
 }
 
-func (a *SQLiteAuditor) CompleteExecution(ctx context.Context, id int64, status string, errSummary string) error {
+func (a *sqlAuditor) CompleteExecution(ctx context.Context, id int64, status string, errSummary string) error {
 	var errPtr *string
 	if errSummary != "" {
 		errPtr = &errSummary
 	}
-	_, err := a.db.ExecContext(ctx,
-		`UPDATE audit_log SET status = ?, completed_at = ?, error_summary = ? WHERE id = ?`,
+	_, err := a.exec(ctx,
+		a.rebind(`UPDATE audit_log SET status = ?, completed_at = ?, error_summary = ? WHERE id = ?`),
 		status, now(), errPtr, id)
 	return err
 }
 
-func (a *SQLiteAuditor) LinkCleanupToRuns(ctx context.Context, cleanupID int64, runIDs []string) error {
+func (a *sqlAuditor) LinkCleanupToRuns(ctx context.Context, cleanupID int64, runIDs []string) error {
 	data, err := json.Marshal(runIDs)
 	if err != nil {
 		return fmt.Errorf("marshaling run IDs: %w", err)
 	}
-	_, err = a.db.ExecContext(ctx,
-		`UPDATE audit_log SET linked_run_ids = ? WHERE id = ?`,
+	_, err = a.exec(ctx,
+		a.rebind(`UPDATE audit_log SET linked_run_ids = ? WHERE id = ?`),
 		string(data), cleanupID)
 	return err
 }
 
-func (a *SQLiteAuditor) RecordCleanupCounts(ctx context.Context, id int64, vmsDeleted, servicesDeleted, secretsDeleted int, namespaceDeleted bool) error {
-	_, err := a.db.ExecContext(ctx,
-		`UPDATE audit_log SET vms_deleted = ?, services_deleted = ?, secrets_deleted = ?, namespace_deleted = ? WHERE id = ?`,
+func (a *sqlAuditor) RecordCleanupCounts(ctx context.Context, id int64, vmsDeleted, servicesDeleted, secretsDeleted int, namespaceDeleted bool) error {
+	_, err := a.exec(ctx,
+		a.rebind(`UPDATE audit_log SET vms_deleted = ?, services_deleted = ?, secrets_deleted = ?, namespace_deleted = ? WHERE id = ?`),
 		vmsDeleted, servicesDeleted, secretsDeleted, boolToInt(namespaceDeleted), id)
 	return err
 }
 
-func (a *SQLiteAuditor) RecordWorkload(ctx context.Context, executionID int64, w WorkloadRecord) (int64, error) {
-	res, err := a.db.ExecContext(ctx, `
+func (a *sqlAuditor) RecordTotals(ctx context.Context, id int64, vmCount, workloadCount int) error {
+	_, err := a.exec(ctx,
+		a.rebind(`UPDATE audit_log SET total_vm_count = ?, total_workload_count = ? WHERE id = ?`),
+		vmCount, workloadCount, id)
+	return err
+}
+
+func (a *sqlAuditor) RecordWorkload(ctx context.Context, executionID int64, w WorkloadRecord) (int64, error) {
+	id, err := a.insert(ctx, `
 		INSERT INTO workload_details (
 			audit_id, workload_type, enabled, vm_count, cpu_cores, memory,
-			has_data_disk, data_disk_size, requires_service, status, started_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 'created', ?)`,
+			has_data_disk, data_disk_size, requires_service, container_disk_image,
+			status, started_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'created', ?)`,
 		executionID, w.WorkloadType, boolToInt(w.Enabled), w.VMCount, w.CPUCores, w.Memory,
-		boolToInt(w.HasDataDisk), nullIfEmpty(w.DataDiskSize), boolToInt(w.RequiresService), now(),
+		boolToInt(w.HasDataDisk), nullIfEmpty(w.DataDiskSize), boolToInt(w.RequiresService),
+		nullIfEmpty(w.ContainerDiskImage), now(),
 	)
 	if err != nil {
 		return 0, fmt.Errorf("inserting workload_details: %w", err)
 	}
-	return res.LastInsertId()
+	return id, nil
 }
 
-func (a *SQLiteAuditor) UpdateWorkloadStatus(ctx context.Context, id int64, status string) error {
-	_, err := a.db.ExecContext(ctx,
-		`UPDATE workload_details SET status = ?, completed_at = ? WHERE id = ?`,
+func (a *sqlAuditor) UpdateWorkloadStatus(ctx context.Context, id int64, status string) error {
+	_, err := a.exec(ctx,
+		a.rebind(`UPDATE workload_details SET status = ?, completed_at = ? WHERE id = ?`),
 		status, now(), id)
 	return err
 }
 
-func (a *SQLiteAuditor) RecordVM(ctx context.Context, executionID int64, workloadID int64, v VMRecord) (int64, error) {
-	res, err := a.db.ExecContext(ctx, `
+func (a *sqlAuditor) RecordVM(ctx context.Context, executionID int64, workloadID int64, v VMRecord) (int64, error) {
+	id, err := a.insert(ctx, `
 		INSERT INTO vm_details (
 			audit_id, workload_id, vm_name, namespace, component, role,
 			cpu_cores, memory, container_disk_image, has_data_disk, data_disk_size,
@@ -189,31 +317,31 @@ func (a *SQLiteAuditor) RecordVM(ctx context.Context, executionID int64, workloa
 	if err != nil {
 		return 0, fmt.Errorf("inserting vm_details: %w", err)
 	}
-	return res.LastInsertId()
+	return id, nil
 }
 
-func (a *SQLiteAuditor) UpdateVMStatus(ctx context.Context, id int64, phase string, status string) error {
+func (a *sqlAuditor) UpdateVMStatus(ctx context.Context, id int64, phase string, status string) error {
 	if status == "ready" {
-		_, err := a.db.ExecContext(ctx,
-			`UPDATE vm_details SET phase = ?, status = ?, ready_at = ? WHERE id = ?`,
+		_, err := a.exec(ctx,
+			a.rebind(`UPDATE vm_details SET phase = ?, status = ?, ready_at = ? WHERE id = ?`),
 			phase, status, now(), id)
 		return err
 	}
-	_, err := a.db.ExecContext(ctx,
-		`UPDATE vm_details SET phase = ?, status = ? WHERE id = ?`,
+	_, err := a.exec(ctx,
+		a.rebind(`UPDATE vm_details SET phase = ?, status = ? WHERE id = ?`),
 		phase, status, id)
 	return err
 }
 
-func (a *SQLiteAuditor) RecordVMDeletion(ctx context.Context, id int64) error {
-	_, err := a.db.ExecContext(ctx,
-		`UPDATE vm_details SET status = 'deleted', deleted_at = ? WHERE id = ?`,
+func (a *sqlAuditor) RecordVMDeletion(ctx context.Context, id int64) error {
+	_, err := a.exec(ctx,
+		a.rebind(`UPDATE vm_details SET status = 'deleted', deleted_at = ? WHERE id = ?`),
 		now(), id)
 	return err
 }
 
-func (a *SQLiteAuditor) RecordResource(ctx context.Context, executionID int64, r ResourceRecord) (int64, error) {
-	res, err := a.db.ExecContext(ctx, `
+func (a *sqlAuditor) RecordResource(ctx context.Context, executionID int64, r ResourceRecord) (int64, error) {
+	id, err := a.insert(ctx, `
 		INSERT INTO resource_details (audit_id, resource_type, resource_name, namespace, status, created_at)
 		VALUES (?, ?, ?, ?, 'created', ?)`,
 		executionID, r.ResourceType, r.ResourceName, r.Namespace, now(),
@@ -221,32 +349,231 @@ func (a *SQLiteAuditor) RecordResource(ctx context.Context, executionID int64, r
 	if err != nil {
 		return 0, fmt.Errorf("inserting resource_details: %w", err)
 	}
-	return res.LastInsertId()
+	return id, nil
 }
 
-func (a *SQLiteAuditor) RecordResourceDeletion(ctx context.Context, id int64) error {
-	_, err := a.db.ExecContext(ctx,
-		`UPDATE resource_details SET status = 'deleted', deleted_at = ? WHERE id = ?`,
+func (a *sqlAuditor) RecordResourceDeletion(ctx context.Context, id int64) error {
+	_, err := a.exec(ctx,
+		a.rebind(`UPDATE resource_details SET status = 'deleted', deleted_at = ? WHERE id = ?`),
 		now(), id)
 	return err
 }
 
-func (a *SQLiteAuditor) RecordEvent(ctx context.Context, executionID int64, e EventRecord) error {
-	_, err := a.db.ExecContext(ctx, `
+func (a *sqlAuditor) RecordEvent(ctx context.Context, executionID int64, e EventRecord) error {
+	_, err := a.exec(ctx, a.rebind(`
 		INSERT INTO events (audit_id, vm_id, workload_id, event_type, message, error_detail, occurred_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		VALUES (?, ?, ?, ?, ?, ?, ?)`),
 		executionID, e.VMID, e.WorkloadID, e.EventType,
 		nullIfEmpty(e.Message), nullIfEmpty(e.ErrorDetail), now(),
 	)
 	return err
 }
 
+// VMTimestamps returns the created_at and ready_at recorded for the most
+// recent vm_details row with the given VM name in the given run. An empty
+// runID matches any run. Unknown VMs and unset timestamps yield "".
+func (a *sqlAuditor) VMTimestamps(ctx context.Context, runID, vmName string) (string, string, error) {
+	var createdAt, readyAt sql.NullString
+	err := a.db.QueryRowContext(ctx, a.rebind(`
+		SELECT v.created_at, v.ready_at
+		FROM vm_details v
+		JOIN audit_log l ON l.id = v.audit_id
+		WHERE v.vm_name = ? AND (? = '' OR l.run_id = ?)
+		ORDER BY v.id DESC
+		LIMIT 1`),
+		vmName, runID, runID,
+	).Scan(&createdAt, &readyAt)
+	if err == sql.ErrNoRows {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("querying vm_details: %w", err)
+	}
+	return createdAt.String, readyAt.String, nil
+}
+
+// ListExecutions returns the executions matching filter, newest first. The VM
+// count is the number of VMs deleted for cleanups and the number of VMs
+// recorded otherwise. Duration is zero for executions still in progress.
+func (a *sqlAuditor) ListExecutions(ctx context.Context, filter ExecutionFilter) ([]ExecutionSummary, error) {
+	rows, err := a.db.QueryContext(ctx, a.rebind(`
+		SELECT l.run_id, l.command, l.namespace, l.status, l.started_at, l.completed_at,
+			COALESCE(l.vms_deleted, (SELECT COUNT(*) FROM vm_details v WHERE v.audit_id = l.id))
+		FROM audit_log l
+		WHERE (? = '' OR l.status = ?) AND (? = '' OR l.namespace = ?)
+		ORDER BY l.started_at DESC, l.id DESC`),
+		filter.Status, filter.Status, filter.Namespace, filter.Namespace,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying audit_log: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []ExecutionSummary
+	for rows.Next() {
+		var s ExecutionSummary
+		var completedAt sql.NullString
+		if err := rows.Scan(&s.RunID, &s.Command, &s.Namespace, &s.Status,
+			&s.StartedAt, &completedAt, &s.VMCount); err != nil {
+			return nil, fmt.Errorf("scanning audit_log: %w", err)
+		}
+		s.CompletedAt = completedAt.String
+		s.DurationSeconds = durationSeconds(s.StartedAt, s.CompletedAt)
+		summaries = append(summaries, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit_log: %w", err)
+	}
+	return summaries, nil
+}
+
+// FindRunsByNamespace returns the run IDs of the successful "run" executions
+// in namespace, newest first. Dry runs, scales, and cleanups are excluded.
+// Cleanup uses it to link to the run it removed when the deleted resources
+// carried no run-id label.
+func (a *sqlAuditor) FindRunsByNamespace(ctx context.Context, namespace string) ([]string, error) {
+	rows, err := a.db.QueryContext(ctx, a.rebind(`
+		SELECT run_id FROM audit_log
+		WHERE namespace = ? AND command = 'run' AND status = 'success'
+		ORDER BY started_at DESC, id DESC`),
+		namespace,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying audit_log: %w", err)
+	}
+	defer rows.Close()
+
+	var runIDs []string
+	for rows.Next() {
+		var runID string
+		if err := rows.Scan(&runID); err != nil {
+			return nil, fmt.Errorf("scanning audit_log: %w", err)
+		}
+		runIDs = append(runIDs, runID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit_log: %w", err)
+	}
+	return runIDs, nil
+}
+
+// ListEvents returns the events recorded by the execution with the given run
+// ID, ordered by occurred_at. Only events with an ID above sinceID are
+// returned, so a caller following a run passes the ID of the last event it
+// saw. Events about a VM carry its name.
+func (a *sqlAuditor) ListEvents(ctx context.Context, runID string, sinceID int64) ([]EventRow, error) {
+	rows, err := a.db.QueryContext(ctx, a.rebind(`
+		SELECT e.id, e.event_type, v.vm_name, e.message, e.error_detail, e.occurred_at
+		FROM events e
+		JOIN audit_log l ON l.id = e.audit_id
+		LEFT JOIN vm_details v ON v.id = e.vm_id
+		WHERE l.run_id = ? AND e.id > ?
+		ORDER BY e.occurred_at, e.id`),
+		runID, sinceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []EventRow
+	for rows.Next() {
+		var e EventRow
+		var vmName, message, errorDetail sql.NullString
+		if err := rows.Scan(&e.ID, &e.EventType, &vmName, &message, &errorDetail, &e.OccurredAt); err != nil {
+			return nil, fmt.Errorf("scanning events: %w", err)
+		}
+		e.VMName = vmName.String
+		e.Message = message.String
+		e.ErrorDetail = errorDetail.String
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading events: %w", err)
+	}
+	return events, nil
+}
+
+// GetExecution returns the configuration and outcome of the execution with
+// the given run ID. VMs count as ready or failed by their vm_ready, vm_timeout,
+// and vm_failed events, so runs without a readiness wait report none ready.
+func (a *sqlAuditor) GetExecution(ctx context.Context, runID string) (*ExecutionDetail, error) {
+	d := &ExecutionDetail{}
+	var id int64
+	var image, memory, diskSize, completedAt, errSummary sql.NullString
+	var cpuCores sql.NullInt64
+	err := a.db.QueryRowContext(ctx, a.rebind(`
+		SELECT l.id, l.run_id, l.command, l.namespace, l.status, l.container_disk_image,
+			l.default_cpu_cores, l.default_memory, l.data_disk_size,
+			l.started_at, l.completed_at, l.error_summary,
+			(SELECT COUNT(*) FROM vm_details v WHERE v.audit_id = l.id),
+			(SELECT COUNT(*) FROM events e WHERE e.audit_id = l.id AND e.event_type = 'vm_ready'),
+			(SELECT COUNT(*) FROM events e WHERE e.audit_id = l.id AND e.event_type IN ('vm_timeout', 'vm_failed'))
+		FROM audit_log l
+		WHERE l.run_id = ?`),
+		runID,
+	).Scan(&id, &d.RunID, &d.Command, &d.Namespace, &d.Status, &image,
+		&cpuCores, &memory, &diskSize,
+		&d.StartedAt, &completedAt, &errSummary,
+		&d.VMsCreated, &d.VMsReady, &d.VMsFailed)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("run %s not found in the audit database", runID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying audit_log: %w", err)
+	}
+	d.ContainerDiskImage = image.String
+	d.CPUCores = int(cpuCores.Int64)
+	d.Memory = memory.String
+	d.DataDiskSize = diskSize.String
+	d.CompletedAt = completedAt.String
+	d.ErrorSummary = errSummary.String
+	d.DurationSeconds = durationSeconds(d.StartedAt, d.CompletedAt)
+
+	rows, err := a.db.QueryContext(ctx, a.rebind(`
+		SELECT workload_type, vm_count, cpu_cores, memory
+		FROM workload_details
+		WHERE audit_id = ?
+		ORDER BY workload_type, id`),
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying workload_details: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w WorkloadSummary
+		if err := rows.Scan(&w.Type, &w.VMCount, &w.CPUCores, &w.Memory); err != nil {
+			return nil, fmt.Errorf("scanning workload_details: %w", err)
+		}
+		d.Workloads = append(d.Workloads, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading workload_details: %w", err)
+	}
+	return d, nil
+}
+
+// durationSeconds returns the whole seconds between two RFC 3339 timestamps,
+// or zero if either is missing or malformed.
+func durationSeconds(start, end string) int64 {
+	startedAt, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return 0
+	}
+	completedAt, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return 0
+	}
+	return int64(completedAt.Sub(startedAt).Seconds())
+}
+
 // DB returns the underlying sql.DB for testing purposes.
-func (a *SQLiteAuditor) DB() *sql.DB {
+func (a *sqlAuditor) DB() *sql.DB {
 	return a.db
 }
 
-func (a *SQLiteAuditor) Close() error {
+func (a *sqlAuditor) Close() error {
 	return a.db.Close()
 }
 
@@ -261,6 +588,7 @@ func (NoOpAuditor) LinkCleanupToRuns(_ context.Context, _ int64, _ []string) err
 func (NoOpAuditor) RecordCleanupCounts(_ context.Context, _ int64, _, _, _ int, _ bool) error {
 	return nil
 }
+func (NoOpAuditor) RecordTotals(_ context.Context, _ int64, _, _ int) error { return nil }
 func (NoOpAuditor) RecordWorkload(_ context.Context, _ int64, _ WorkloadRecord) (int64, error) {
 	return 0, nil
 }
@@ -277,6 +605,24 @@ func (NoOpAuditor) RecordResourceDeletion(_ context.Context, _ int64) error { re
 func (NoOpAuditor) RecordEvent(_ context.Context, _ int64, _ EventRecord) error {
 	return nil
 }
+func (NoOpAuditor) FindRunsByNamespace(_ context.Context, _ string) ([]string, error) {
+	return nil, nil
+}
+func (NoOpAuditor) ListEvents(_ context.Context, _ string, _ int64) ([]EventRow, error) {
+	return nil, nil
+}
+func (NoOpAuditor) ExportRows(_ context.Context, _, _ string, _ RowWriter) error {
+	return nil
+}
+func (NoOpAuditor) AggregateStats(_ context.Context, since string) (*Stats, error) {
+	return &Stats{Since: since, ByStatus: map[string]int{}, Workloads: []WorkloadStats{}}, nil
+}
+func (NoOpAuditor) ListExecutions(_ context.Context, _ ExecutionFilter) ([]ExecutionSummary, error) {
+	return nil, nil
+}
+func (NoOpAuditor) GetExecution(_ context.Context, runID string) (*ExecutionDetail, error) {
+	return nil, fmt.Errorf("run %s not found: auditing is disabled", runID)
+}
 func (NoOpAuditor) Close() error { return nil }
 
 func boolToInt(b bool) int {
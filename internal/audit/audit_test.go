@@ -4,10 +4,15 @@
 package audit_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"path/filepath"
 	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -34,6 +39,51 @@ var _ = Describe("SQLiteAuditor", func() {
 	})
 
 	Describe("schema creation", func() {
+		It("adds columns missing from a database created by an older release", func() {
+			dbPath := filepath.Join(GinkgoT().TempDir(), "old.db")
+			old, err := sql.Open("sqlite3", dbPath)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = old.Exec(`CREATE TABLE workload_details (
+				id               INTEGER PRIMARY KEY AUTOINCREMENT,
+				audit_id         INTEGER NOT NULL,
+				workload_type    TEXT    NOT NULL,
+				enabled          INTEGER NOT NULL DEFAULT 1,
+				vm_count         INTEGER NOT NULL,
+				cpu_cores        INTEGER NOT NULL,
+				memory           TEXT    NOT NULL,
+				has_data_disk    INTEGER NOT NULL DEFAULT 0,
+				data_disk_size   TEXT,
+				requires_service INTEGER NOT NULL DEFAULT 0,
+				status           TEXT    NOT NULL DEFAULT 'pending',
+				started_at       TEXT,
+				completed_at     TEXT
+			)`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(old.Close()).To(Succeed())
+
+			upgraded, err := audit.NewSQLiteAuditor(dbPath)
+			Expect(err).NotTo(HaveOccurred())
+			defer upgraded.Close()
+
+			execID, _, err := upgraded.StartExecution(ctx, "run", &config.Config{Namespace: "test-ns"})
+			Expect(err).NotTo(HaveOccurred())
+			wlID, err := upgraded.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+				WorkloadType: "cpu", Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi",
+				ContainerDiskImage: "quay.io/example/cpu:latest",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			var image string
+			Expect(upgraded.DB().QueryRow(`SELECT container_disk_image FROM workload_details WHERE id = ?`, wlID).
+				Scan(&image)).To(Succeed())
+			Expect(image).To(Equal("quay.io/example/cpu:latest"))
+
+			// Reopening an upgraded database leaves it as is
+			reopened, err := audit.NewSQLiteAuditor(dbPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reopened.Close()).To(Succeed())
+		})
+
 		It("creates all expected tables", func() {
 			db := auditor.DB()
 			tables := []string{"audit_log", "workload_details", "vm_details", "resource_details", "events"}
@@ -120,6 +170,12 @@ var _ = Describe("SQLiteAuditor", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(wlID).To(BeNumerically(">", 0))
 
+			// Without an image, the workload's container_disk_image is NULL
+			var wlImage sql.NullString
+			err = db.QueryRow(`SELECT container_disk_image FROM workload_details WHERE id = ?`, wlID).Scan(&wlImage)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(wlImage.Valid).To(BeFalse())
+
 			// Record VM
 			vmID, err := auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
 				VMName:             "virtwork-cpu-0",
@@ -238,6 +294,44 @@ var _ = Describe("SQLiteAuditor", func() {
 			Expect(ids).To(ConsistOf(runID1, runID2))
 		})
 
+		It("finds the successful runs in a namespace, newest first", func() {
+			seed := []struct {
+				command   string
+				namespace string
+				status    string
+			}{
+				{"run", "test-ns", "success"},
+				{"run", "test-ns", "failed"},
+				{"dry-run", "test-ns", "success"},
+				{"run", "other-ns", "success"},
+				{"cleanup", "test-ns", "success"},
+				{"run", "test-ns", "success"},
+			}
+			var runIDs []string
+			for i, s := range seed {
+				execID, runID, err := auditor.StartExecution(ctx, s.command, &config.Config{Namespace: s.namespace})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(auditor.CompleteExecution(ctx, execID, s.status, "")).To(Succeed())
+				_, err = auditor.DB().Exec(`UPDATE audit_log SET started_at = ? WHERE id = ?`,
+					fmt.Sprintf("2026-01-0%dT10:00:00Z", i+1), execID)
+				Expect(err).NotTo(HaveOccurred())
+				runIDs = append(runIDs, runID)
+			}
+
+			found, err := auditor.FindRunsByNamespace(ctx, "test-ns")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(Equal([]string{runIDs[5], runIDs[0]}))
+		})
+
+		It("finds no runs in a namespace without successful runs", func() {
+			_, _, err := auditor.StartExecution(ctx, "run", &config.Config{Namespace: "test-ns"})
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err := auditor.FindRunsByNamespace(ctx, "test-ns")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeEmpty())
+		})
+
 		It("records cleanup counts", func() {
 			cfg := &config.Config{Namespace: "test-ns"}
 
@@ -259,6 +353,23 @@ var _ = Describe("SQLiteAuditor", func() {
 			Expect(secrets).To(Equal(10))
 			Expect(nsDeleted).To(Equal(1))
 		})
+
+		It("records planned totals", func() {
+			execID, _, err := auditor.StartExecution(ctx, "run", &config.Config{Namespace: "test-ns"})
+			Expect(err).NotTo(HaveOccurred())
+
+			var vms, workloads sql.NullInt64
+			query := `SELECT total_vm_count, total_workload_count FROM audit_log WHERE id = ?`
+			Expect(auditor.DB().QueryRow(query, execID).Scan(&vms, &workloads)).To(Succeed())
+			Expect(vms.Valid).To(BeFalse())
+			Expect(workloads.Valid).To(BeFalse())
+
+			Expect(auditor.RecordTotals(ctx, execID, 5, 2)).To(Succeed())
+
+			Expect(auditor.DB().QueryRow(query, execID).Scan(&vms, &workloads)).To(Succeed())
+			Expect(vms.Int64).To(Equal(int64(5)))
+			Expect(workloads.Int64).To(Equal(int64(2)))
+		})
 	})
 
 	Describe("VM deletion tracking", func() {
@@ -353,6 +464,85 @@ var _ = Describe("SQLiteAuditor", func() {
 		})
 	})
 
+	Describe("listing events", func() {
+		var (
+			runID string
+			ids   []int64
+		)
+
+		BeforeEach(func() {
+			var execID int64
+			var err error
+			execID, runID, err = auditor.StartExecution(ctx, "run", &config.Config{Namespace: "test-ns"})
+			Expect(err).NotTo(HaveOccurred())
+			wlID, err := auditor.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+				WorkloadType: "cpu", Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			vmID, err := auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
+				VMName: "virtwork-cpu-0", Namespace: "test-ns", Component: "cpu",
+				CPUCores: 2, Memory: "2Gi", ContainerDiskImage: "img",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{EventType: "execution_started", Message: "started"})).To(Succeed())
+			Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{VMID: &vmID, EventType: "vm_created"})).To(Succeed())
+			Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{
+				VMID: &vmID, EventType: "vm_timeout", ErrorDetail: "not ready after 600s",
+			})).To(Succeed())
+
+			// Another run's events are never returned
+			otherID, _, err := auditor.StartExecution(ctx, "run", &config.Config{Namespace: "test-ns"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(auditor.RecordEvent(ctx, otherID, audit.EventRecord{EventType: "execution_started"})).To(Succeed())
+
+			db := auditor.DB()
+			rows, err := db.Query(`SELECT id FROM events WHERE audit_id = ? ORDER BY id`, execID)
+			Expect(err).NotTo(HaveOccurred())
+			defer rows.Close()
+			ids = nil
+			for rows.Next() {
+				var id int64
+				Expect(rows.Scan(&id)).To(Succeed())
+				ids = append(ids, id)
+			}
+			_, err = db.Exec(`UPDATE events SET occurred_at = ? WHERE id = ?`, "2026-01-01T00:00:00Z", ids[0])
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.Exec(`UPDATE events SET occurred_at = ? WHERE id = ?`, "2026-01-01T00:00:30Z", ids[1])
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.Exec(`UPDATE events SET occurred_at = ? WHERE id = ?`, "2026-01-01T00:00:10Z", ids[2])
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the run's events ordered by occurred_at with VM names", func() {
+			events, err := auditor.ListEvents(ctx, runID, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(Equal([]audit.EventRow{
+				{ID: ids[0], EventType: "execution_started", Message: "started", OccurredAt: "2026-01-01T00:00:00Z"},
+				{ID: ids[2], EventType: "vm_timeout", VMName: "virtwork-cpu-0", ErrorDetail: "not ready after 600s", OccurredAt: "2026-01-01T00:00:10Z"},
+				{ID: ids[1], EventType: "vm_created", VMName: "virtwork-cpu-0", OccurredAt: "2026-01-01T00:00:30Z"},
+			}))
+		})
+
+		It("returns only events after sinceID", func() {
+			events, err := auditor.ListEvents(ctx, runID, ids[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].ID).To(Equal(ids[2]))
+			Expect(events[1].ID).To(Equal(ids[1]))
+
+			events, err = auditor.ListEvents(ctx, runID, ids[2])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+		})
+
+		It("returns nothing for an unknown run", func() {
+			events, err := auditor.ListEvents(ctx, "no-such-run", 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+		})
+	})
+
 	Describe("concurrent writes", func() {
 		It("handles concurrent event inserts without errors", func() {
 			cfg := &config.Config{Namespace: "test-ns"}
@@ -383,6 +573,40 @@ var _ = Describe("SQLiteAuditor", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(count).To(Equal(20))
 		})
+
+		It("retries a write while another connection holds the table lock", func() {
+			execID, _, err := auditor.StartExecution(ctx, "run", &config.Config{Namespace: "test-ns"})
+			Expect(err).NotTo(HaveOccurred())
+
+			// In the shared-cache in-memory database a connection writing to
+			// events locks the table, and other writers get SQLITE_LOCKED,
+			// which the busy timeout does not cover
+			conn, err := auditor.DB().Conn(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+			_, err = conn.ExecContext(ctx, `BEGIN IMMEDIATE`)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = conn.ExecContext(ctx,
+				`INSERT INTO events (audit_id, event_type, occurred_at) VALUES (?, 'lock_holder', '2026-01-01T00:00:00Z')`, execID)
+			Expect(err).NotTo(HaveOccurred())
+			released := make(chan error, 1)
+			go func() {
+				time.Sleep(150 * time.Millisecond)
+				_, err := conn.ExecContext(ctx, `COMMIT`)
+				released <- err
+			}()
+
+			Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{
+				EventType: "vm_created",
+				Message:   "written while locked",
+			})).To(Succeed())
+			Expect(<-released).To(Succeed())
+
+			var count int
+			err = auditor.DB().QueryRow(`SELECT COUNT(*) FROM events WHERE audit_id = ?`, execID).Scan(&count)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+		})
 	})
 
 	Describe("SSH auth tracking", func() {
@@ -413,6 +637,503 @@ var _ = Describe("SQLiteAuditor", func() {
 			Expect(sshAuth).To(Equal(0))
 		})
 	})
+
+	Describe("cluster context tracking", func() {
+		It("records the kubeconfig context when set", func() {
+			cfg := &config.Config{Namespace: "test-ns", KubeContext: "prod-admin"}
+			execID, _, err := auditor.StartExecution(ctx, "run", cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			db := auditor.DB()
+			var clusterContext sql.NullString
+			err = db.QueryRow(`SELECT cluster_context FROM audit_log WHERE id = ?`, execID).Scan(&clusterContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterContext.Valid).To(BeTrue())
+			Expect(clusterContext.String).To(Equal("prod-admin"))
+		})
+
+		It("stores NULL when no context is set", func() {
+			cfg := &config.Config{Namespace: "test-ns"}
+			execID, _, err := auditor.StartExecution(ctx, "run", cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			db := auditor.DB()
+			var clusterContext sql.NullString
+			err = db.QueryRow(`SELECT cluster_context FROM audit_log WHERE id = ?`, execID).Scan(&clusterContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterContext.Valid).To(BeFalse())
+		})
+	})
+
+	Describe("CI metadata tracking", func() {
+		It("records the git and CI values when set", func() {
+			cfg := &config.Config{
+				Namespace: "test-ns",
+				GitSHA:    "0123abcd",
+				GitBranch: "main",
+				CIURL:     "https://ci.example.com/jobs/42",
+			}
+			execID, _, err := auditor.StartExecution(ctx, "run", cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			var sha, branch, url sql.NullString
+			err = auditor.DB().QueryRow(`SELECT git_sha, git_branch, ci_url FROM audit_log WHERE id = ?`, execID).
+				Scan(&sha, &branch, &url)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sha.String).To(Equal("0123abcd"))
+			Expect(branch.String).To(Equal("main"))
+			Expect(url.String).To(Equal("https://ci.example.com/jobs/42"))
+		})
+
+		It("stores NULL when no CI metadata is set", func() {
+			execID, _, err := auditor.StartExecution(ctx, "run", &config.Config{Namespace: "test-ns"})
+			Expect(err).NotTo(HaveOccurred())
+
+			var sha, branch, url sql.NullString
+			err = auditor.DB().QueryRow(`SELECT git_sha, git_branch, ci_url FROM audit_log WHERE id = ?`, execID).
+				Scan(&sha, &branch, &url)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sha.Valid).To(BeFalse())
+			Expect(branch.Valid).To(BeFalse())
+			Expect(url.Valid).To(BeFalse())
+		})
+	})
+
+	Describe("VM timestamp lookup", func() {
+		var runID string
+		var vmID int64
+
+		BeforeEach(func() {
+			cfg := &config.Config{Namespace: "test-ns"}
+			var execID int64
+			var err error
+			execID, runID, err = auditor.StartExecution(ctx, "run", cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			wlID, err := auditor.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+				WorkloadType: "cpu", Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			vmID, err = auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
+				VMName: "virtwork-cpu-0", Namespace: "test-ns", Component: "cpu",
+				CPUCores: 2, Memory: "2Gi", ContainerDiskImage: "img",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns created_at and an empty ready_at before the VM is ready", func() {
+			createdAt, readyAt, err := auditor.VMTimestamps(ctx, runID, "virtwork-cpu-0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdAt).NotTo(BeEmpty())
+			Expect(readyAt).To(BeEmpty())
+		})
+
+		It("returns ready_at once the VM is ready", func() {
+			Expect(auditor.UpdateVMStatus(ctx, vmID, "Running", "ready")).To(Succeed())
+
+			_, readyAt, err := auditor.VMTimestamps(ctx, runID, "virtwork-cpu-0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readyAt).NotTo(BeEmpty())
+		})
+
+		It("matches any run when the run ID is empty", func() {
+			createdAt, _, err := auditor.VMTimestamps(ctx, "", "virtwork-cpu-0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdAt).NotTo(BeEmpty())
+		})
+
+		It("returns empty timestamps for an unknown VM or run", func() {
+			createdAt, readyAt, err := auditor.VMTimestamps(ctx, runID, "virtwork-cpu-9")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdAt).To(BeEmpty())
+			Expect(readyAt).To(BeEmpty())
+
+			createdAt, _, err = auditor.VMTimestamps(ctx, "other-run", "virtwork-cpu-0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdAt).To(BeEmpty())
+		})
+	})
+	Describe("exporting", func() {
+		BeforeEach(func() {
+			for i, vms := range []int{2, 1} {
+				execID, _, err := auditor.StartExecution(ctx, "run", &config.Config{Namespace: "test-ns"})
+				Expect(err).NotTo(HaveOccurred())
+				wlID, err := auditor.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+					WorkloadType: "cpu", Enabled: true, VMCount: vms, CPUCores: 1, Memory: "1Gi",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				for j := 0; j < vms; j++ {
+					_, err := auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
+						VMName: fmt.Sprintf("vm-%d-%d", i, j), Namespace: "test-ns", Component: "cpu",
+						CPUCores: 1, Memory: "1Gi", ContainerDiskImage: "img",
+					})
+					Expect(err).NotTo(HaveOccurred())
+				}
+				_, err = auditor.DB().Exec(
+					`UPDATE audit_log SET started_at = ? WHERE id = ?`,
+					fmt.Sprintf("2026-01-0%dT10:00:00Z", i+1), execID)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		export := func(table, since string) [][]string {
+			var buf bytes.Buffer
+			w := csv.NewWriter(&buf)
+			Expect(auditor.ExportRows(ctx, table, since, w)).To(Succeed())
+			w.Flush()
+			Expect(w.Error()).NotTo(HaveOccurred())
+			records, err := csv.NewReader(&buf).ReadAll()
+			Expect(err).NotTo(HaveOccurred())
+			return records
+		}
+
+		It("writes a header and one row per execution", func() {
+			records := export(audit.ExportAuditLog, "")
+			Expect(records).To(HaveLen(3))
+			Expect(records[0]).To(ContainElements("id", "run_id", "command", "started_at", "namespace"))
+			Expect(records[1]).To(ContainElement("2026-01-01T10:00:00Z"))
+		})
+
+		It("writes NULL columns as empty fields", func() {
+			records := export(audit.ExportAuditLog, "")
+			col := -1
+			for i, name := range records[0] {
+				if name == "completed_at" {
+					col = i
+				}
+			}
+			Expect(col).NotTo(Equal(-1))
+			Expect(records[1][col]).To(BeEmpty())
+		})
+
+		It("exports the VMs of each execution", func() {
+			records := export(audit.ExportVMDetails, "")
+			Expect(records).To(HaveLen(4))
+			Expect(records[0]).To(ContainElements("id", "audit_id", "vm_name"))
+		})
+
+		It("keeps only executions started at or after since", func() {
+			Expect(export(audit.ExportAuditLog, "2026-01-02T00:00:00Z")).To(HaveLen(2))
+			Expect(export(audit.ExportVMDetails, "2026-01-02T00:00:00Z")).To(HaveLen(2))
+			Expect(export(audit.ExportAuditLog, "2027-01-01T00:00:00Z")).To(HaveLen(1))
+		})
+
+		It("rejects an unknown table", func() {
+			err := auditor.ExportRows(ctx, "events", "", csv.NewWriter(&bytes.Buffer{}))
+			Expect(err).To(MatchError(ContainSubstring(`unknown export table "events"`)))
+		})
+	})
+
+	Describe("listing executions", func() {
+		var runIDs []string
+
+		BeforeEach(func() {
+			runIDs = nil
+			seed := []struct {
+				command   string
+				namespace string
+				vms       int
+				status    string
+			}{
+				{"run", "ns-a", 2, "success"},
+				{"run", "ns-b", 1, "failed"},
+				{"cleanup", "ns-a", 0, "success"},
+				{"run", "ns-a", 1, ""},
+			}
+			for i, s := range seed {
+				execID, runID, err := auditor.StartExecution(ctx, s.command, &config.Config{Namespace: s.namespace})
+				Expect(err).NotTo(HaveOccurred())
+				runIDs = append(runIDs, runID)
+
+				wlID, err := auditor.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+					WorkloadType: "cpu", Enabled: true, VMCount: s.vms, CPUCores: 1, Memory: "1Gi",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				for j := 0; j < s.vms; j++ {
+					_, err := auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
+						VMName: fmt.Sprintf("vm-%d-%d", i, j), Namespace: s.namespace, Component: "cpu",
+						CPUCores: 1, Memory: "1Gi", ContainerDiskImage: "img",
+					})
+					Expect(err).NotTo(HaveOccurred())
+				}
+				if s.command == "cleanup" {
+					Expect(auditor.RecordCleanupCounts(ctx, execID, 3, 3, 3, false)).To(Succeed())
+				}
+				if s.status != "" {
+					Expect(auditor.CompleteExecution(ctx, execID, s.status, "")).To(Succeed())
+				}
+
+				// Spread start times so ordering and durations are deterministic.
+				_, err = auditor.DB().Exec(
+					`UPDATE audit_log SET started_at = ? WHERE id = ?`,
+					fmt.Sprintf("2026-01-0%dT10:00:00Z", i+1), execID)
+				Expect(err).NotTo(HaveOccurred())
+				if s.status != "" {
+					_, err = auditor.DB().Exec(
+						`UPDATE audit_log SET completed_at = ? WHERE id = ?`,
+						fmt.Sprintf("2026-01-0%dT10:01:30Z", i+1), execID)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}
+		})
+
+		It("returns all executions newest first", func() {
+			summaries, err := auditor.ListExecutions(ctx, audit.ExecutionFilter{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summaries).To(HaveLen(4))
+			Expect(summaries[0].RunID).To(Equal(runIDs[3]))
+			Expect(summaries[3].RunID).To(Equal(runIDs[0]))
+		})
+
+		It("reports VM counts and durations", func() {
+			summaries, err := auditor.ListExecutions(ctx, audit.ExecutionFilter{})
+			Expect(err).NotTo(HaveOccurred())
+
+			byRun := map[string]audit.ExecutionSummary{}
+			for _, s := range summaries {
+				byRun[s.RunID] = s
+			}
+			Expect(byRun[runIDs[0]].VMCount).To(Equal(2))
+			Expect(byRun[runIDs[0]].DurationSeconds).To(Equal(int64(90)))
+			Expect(byRun[runIDs[2]].Command).To(Equal("cleanup"))
+			Expect(byRun[runIDs[2]].VMCount).To(Equal(3))
+			Expect(byRun[runIDs[3]].Status).To(Equal("in_progress"))
+			Expect(byRun[runIDs[3]].CompletedAt).To(BeEmpty())
+			Expect(byRun[runIDs[3]].DurationSeconds).To(BeZero())
+		})
+
+		It("filters by status", func() {
+			summaries, err := auditor.ListExecutions(ctx, audit.ExecutionFilter{Status: "failed"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summaries).To(HaveLen(1))
+			Expect(summaries[0].RunID).To(Equal(runIDs[1]))
+		})
+
+		It("filters by namespace", func() {
+			summaries, err := auditor.ListExecutions(ctx, audit.ExecutionFilter{Namespace: "ns-a"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summaries).To(HaveLen(3))
+			for _, s := range summaries {
+				Expect(s.Namespace).To(Equal("ns-a"))
+			}
+		})
+
+		It("combines filters", func() {
+			summaries, err := auditor.ListExecutions(ctx, audit.ExecutionFilter{Status: "success", Namespace: "ns-a"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summaries).To(HaveLen(2))
+		})
+
+		It("marshals to JSON with snake_case keys", func() {
+			summaries, err := auditor.ListExecutions(ctx, audit.ExecutionFilter{Status: "failed"})
+			Expect(err).NotTo(HaveOccurred())
+			data, err := json.Marshal(summaries)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"run_id":"` + runIDs[1] + `"`))
+			Expect(string(data)).To(ContainSubstring(`"duration_seconds":90`))
+		})
+	})
+})
+
+var _ = Describe("AggregateStats", func() {
+	var (
+		auditor *audit.SQLiteAuditor
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		auditor, err = audit.NewSQLiteAuditor(":memory:")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(auditor.Close)
+
+		seed := []struct {
+			command   string
+			workloads map[string]int
+			status    string
+			seconds   int
+		}{
+			{"run", map[string]int{"cpu": 2, "disk": 1}, "success", 60},
+			{"run", map[string]int{"cpu": 1}, "failed", 120},
+			{"cleanup", nil, "success", 30},
+			{"run", map[string]int{"disk": 3}, "", 0},
+		}
+		for i, s := range seed {
+			execID, _, err := auditor.StartExecution(ctx, s.command, &config.Config{Namespace: "test-ns"})
+			Expect(err).NotTo(HaveOccurred())
+			for workload, vms := range s.workloads {
+				wlID, err := auditor.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+					WorkloadType: workload, Enabled: true, VMCount: vms, CPUCores: 1, Memory: "1Gi",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				for j := range vms {
+					_, err := auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
+						VMName: fmt.Sprintf("%s-%d-%d", workload, i, j), Namespace: "test-ns", Component: workload,
+						CPUCores: 1, Memory: "1Gi", ContainerDiskImage: "img",
+					})
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}
+			if s.command == "cleanup" {
+				Expect(auditor.RecordCleanupCounts(ctx, execID, 3, 3, 3, false)).To(Succeed())
+			}
+			if s.status != "" {
+				Expect(auditor.CompleteExecution(ctx, execID, s.status, "")).To(Succeed())
+			}
+
+			started := time.Date(2026, 1, i+1, 10, 0, 0, 0, time.UTC)
+			_, err = auditor.DB().Exec(`UPDATE audit_log SET started_at = ? WHERE id = ?`,
+				started.Format(time.RFC3339), execID)
+			Expect(err).NotTo(HaveOccurred())
+			if s.status != "" {
+				_, err = auditor.DB().Exec(`UPDATE audit_log SET completed_at = ? WHERE id = ?`,
+					started.Add(time.Duration(s.seconds)*time.Second).Format(time.RFC3339), execID)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+	})
+
+	It("counts executions by status and averages completed durations", func() {
+		stats, err := auditor.AggregateStats(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Executions).To(Equal(4))
+		Expect(stats.ByStatus).To(Equal(map[string]int{"success": 2, "failed": 1, "in_progress": 1}))
+		Expect(stats.AverageDurationSeconds).To(Equal(int64(70)))
+		Expect(stats.VMsCreated).To(Equal(7))
+		Expect(stats.VMsDeleted).To(Equal(3))
+	})
+
+	It("breaks executions down by workload type", func() {
+		stats, err := auditor.AggregateStats(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Workloads).To(Equal([]audit.WorkloadStats{
+			{Type: "cpu", Executions: 2, Succeeded: 1, Failed: 1, VMs: 3},
+			{Type: "disk", Executions: 2, Succeeded: 1, Failed: 0, VMs: 4},
+		}))
+	})
+
+	It("keeps only executions started at or after since", func() {
+		stats, err := auditor.AggregateStats(ctx, "2026-01-02T00:00:00Z")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Since).To(Equal("2026-01-02T00:00:00Z"))
+		Expect(stats.Executions).To(Equal(3))
+		Expect(stats.ByStatus).To(Equal(map[string]int{"success": 1, "failed": 1, "in_progress": 1}))
+		Expect(stats.AverageDurationSeconds).To(Equal(int64(75)))
+		Expect(stats.VMsCreated).To(Equal(4))
+		Expect(stats.Workloads).To(HaveLen(2))
+		Expect(stats.Workloads[0]).To(Equal(audit.WorkloadStats{Type: "cpu", Executions: 1, Failed: 1, VMs: 1}))
+	})
+
+	It("returns zeroed stats when nothing matches", func() {
+		stats, err := auditor.AggregateStats(ctx, "2999-01-01T00:00:00Z")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Executions).To(BeZero())
+		Expect(stats.ByStatus).To(BeEmpty())
+		Expect(stats.AverageDurationSeconds).To(BeZero())
+		Expect(stats.VMsCreated).To(BeZero())
+		Expect(stats.VMsDeleted).To(BeZero())
+		Expect(stats.Workloads).To(BeEmpty())
+	})
+})
+
+var _ = Describe("GetExecution", func() {
+	var (
+		auditor *audit.SQLiteAuditor
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		auditor, err = audit.NewSQLiteAuditor(":memory:")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(auditor.Close()).To(Succeed())
+	})
+
+	It("returns the run's configuration, workloads, and outcome", func() {
+		execID, runID, err := auditor.StartExecution(ctx, "run", &config.Config{
+			Namespace:          "bench",
+			ContainerDiskImage: "quay.io/containerdisks/fedora:41",
+			CPUCores:           2,
+			Memory:             "4Gi",
+			DataDiskSize:       "20Gi",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		wlID, err := auditor.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+			WorkloadType: "disk", Enabled: true, VMCount: 2, CPUCores: 2, Memory: "4Gi",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = auditor.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+			WorkloadType: "cpu", Enabled: true, VMCount: 1, CPUCores: 4, Memory: "2Gi",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		for i := 0; i < 3; i++ {
+			_, err := auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
+				VMName: fmt.Sprintf("vm-%d", i), Namespace: "bench", Component: "disk",
+				CPUCores: 2, Memory: "4Gi", ContainerDiskImage: "img",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{EventType: "vm_ready"})).To(Succeed())
+		Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{EventType: "vm_ready"})).To(Succeed())
+		Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{EventType: "vm_timeout", ErrorDetail: "timed out"})).To(Succeed())
+		Expect(auditor.CompleteExecution(ctx, execID, "failed", "1 of 3 VMs failed readiness check")).To(Succeed())
+
+		d, err := auditor.GetExecution(ctx, runID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.RunID).To(Equal(runID))
+		Expect(d.Command).To(Equal("run"))
+		Expect(d.Namespace).To(Equal("bench"))
+		Expect(d.Status).To(Equal("failed"))
+		Expect(d.ContainerDiskImage).To(Equal("quay.io/containerdisks/fedora:41"))
+		Expect(d.CPUCores).To(Equal(2))
+		Expect(d.Memory).To(Equal("4Gi"))
+		Expect(d.DataDiskSize).To(Equal("20Gi"))
+		Expect(d.Workloads).To(Equal([]audit.WorkloadSummary{
+			{Type: "cpu", VMCount: 1, CPUCores: 4, Memory: "2Gi"},
+			{Type: "disk", VMCount: 2, CPUCores: 2, Memory: "4Gi"},
+		}))
+		Expect(d.VMsCreated).To(Equal(3))
+		Expect(d.VMsReady).To(Equal(2))
+		Expect(d.VMsFailed).To(Equal(1))
+		Expect(d.CompletedAt).NotTo(BeEmpty())
+		Expect(d.ErrorSummary).To(Equal("1 of 3 VMs failed readiness check"))
+	})
+
+	It("returns an error for an unknown run", func() {
+		_, err := auditor.GetExecution(ctx, "no-such-run")
+		Expect(err).To(MatchError(ContainSubstring("run no-such-run not found")))
+	})
+})
+
+var _ = Describe("ContextDBPath", func() {
+	It("should name the database after the context", func() {
+		Expect(audit.ContextDBPath("/home/user/.virtwork", "lab-east")).To(
+			Equal("/home/user/.virtwork/lab-east.db"))
+	})
+
+	It("should sanitize OpenShift-style context names", func() {
+		path := audit.ContextDBPath("/home/user/.virtwork", "default/api-lab-example-com:6443/kube:admin")
+		Expect(path).To(Equal("/home/user/.virtwork/default_api-lab-example-com_6443_kube_admin.db"))
+		Expect(filepath.Dir(path)).To(Equal("/home/user/.virtwork"))
+	})
+
+	It("should keep the database inside the directory", func() {
+		for _, name := range []string{"", ".", "..", "../../etc/passwd"} {
+			path := audit.ContextDBPath("/home/user/.virtwork", name)
+			Expect(filepath.Dir(path)).To(Equal("/home/user/.virtwork"), "context %q", name)
+			Expect(filepath.Base(path)).NotTo(Equal(".db"), "context %q", name)
+		}
+	})
+
+	It("should give distinct contexts distinct paths", func() {
+		Expect(audit.ContextDBPath("dir", "cluster-a")).NotTo(Equal(audit.ContextDBPath("dir", "cluster-b")))
+	})
 })
 
 var _ = Describe("NoOpAuditor", func() {
@@ -430,6 +1151,7 @@ var _ = Describe("NoOpAuditor", func() {
 		Expect(a.CompleteExecution(ctx, 0, "success", "")).To(Succeed())
 		Expect(a.LinkCleanupToRuns(ctx, 0, []string{"abc"})).To(Succeed())
 		Expect(a.RecordCleanupCounts(ctx, 0, 1, 2, 3, true)).To(Succeed())
+		Expect(a.RecordTotals(ctx, 0, 4, 2)).To(Succeed())
 
 		wlID, err := a.RecordWorkload(ctx, 0, audit.WorkloadRecord{})
 		Expect(err).NotTo(HaveOccurred())
@@ -442,12 +1164,34 @@ var _ = Describe("NoOpAuditor", func() {
 		Expect(a.UpdateVMStatus(ctx, 0, "Running", "ready")).To(Succeed())
 		Expect(a.RecordVMDeletion(ctx, 0)).To(Succeed())
 
+		summaries, err := a.ListExecutions(ctx, audit.ExecutionFilter{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summaries).To(BeEmpty())
+
+		runIDs, err := a.FindRunsByNamespace(ctx, "test-ns")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runIDs).To(BeEmpty())
+
+		events, err := a.ListEvents(ctx, "abc", 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(BeEmpty())
+
 		resID, err := a.RecordResource(ctx, 0, audit.ResourceRecord{})
 		Expect(err).NotTo(HaveOccurred())
 		Expect(resID).To(Equal(int64(0)))
 		Expect(a.RecordResourceDeletion(ctx, 0)).To(Succeed())
 
 		Expect(a.RecordEvent(ctx, 0, audit.EventRecord{})).To(Succeed())
+		Expect(a.ExportRows(ctx, audit.ExportAuditLog, "", csv.NewWriter(&bytes.Buffer{}))).To(Succeed())
+
+		stats, err := a.AggregateStats(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*stats).To(Equal(audit.Stats{ByStatus: map[string]int{}, Workloads: []audit.WorkloadStats{}}))
 		Expect(a.Close()).To(Succeed())
 	})
+
+	It("finds no executions to compare", func() {
+		_, err := a.GetExecution(context.Background(), "abc")
+		Expect(err).To(MatchError(ContainSubstring("auditing is disabled")))
+	})
 })
@@ -0,0 +1,122 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// missingValue stands in for a field one execution does not have, such as a
+// workload that only ran in the other.
+const missingValue = "-"
+
+// FieldDiff is one field of two executions set side by side.
+type FieldDiff struct {
+	Field   string `json:"field"`
+	A       string `json:"a"`
+	B       string `json:"b"`
+	Changed bool   `json:"changed"`
+}
+
+// CompareExecutions lines up the configuration and outcome of two executions
+// field by field, in a fixed order: the run settings, then each workload's VM
+// count and size, then the outcome. Per-workload fields cover the workloads of
+// both runs, sorted by name. Changed marks the fields whose values differ.
+func CompareExecutions(a, b *ExecutionDetail) []FieldDiff {
+	var diffs []FieldDiff
+	add := func(field, va, vb string) {
+		diffs = append(diffs, FieldDiff{Field: field, A: va, B: vb, Changed: va != vb})
+	}
+
+	add("command", a.Command, b.Command)
+	add("namespace", a.Namespace, b.Namespace)
+	add("container_disk_image", a.ContainerDiskImage, b.ContainerDiskImage)
+	add("cpu_cores", strconv.Itoa(a.CPUCores), strconv.Itoa(b.CPUCores))
+	add("memory", a.Memory, b.Memory)
+	add("data_disk_size", a.DataDiskSize, b.DataDiskSize)
+	add("workloads", workloadTypes(a), workloadTypes(b))
+
+	wa, wb := workloadsByType(a), workloadsByType(b)
+	for _, name := range unionKeys(wa, wb) {
+		x, inA := wa[name]
+		y, inB := wb[name]
+		field := func(f func(WorkloadSummary) string) (string, string) {
+			va, vb := missingValue, missingValue
+			if inA {
+				va = f(x)
+			}
+			if inB {
+				vb = f(y)
+			}
+			return va, vb
+		}
+		va, vb := field(func(w WorkloadSummary) string { return strconv.Itoa(w.VMCount) })
+		add(name+".vm_count", va, vb)
+		va, vb = field(func(w WorkloadSummary) string { return strconv.Itoa(w.CPUCores) })
+		add(name+".cpu_cores", va, vb)
+		va, vb = field(func(w WorkloadSummary) string { return w.Memory })
+		add(name+".memory", va, vb)
+	}
+
+	add("status", a.Status, b.Status)
+	add("vms_created", strconv.Itoa(a.VMsCreated), strconv.Itoa(b.VMsCreated))
+	add("vms_ready", strconv.Itoa(a.VMsReady), strconv.Itoa(b.VMsReady))
+	add("vms_failed", strconv.Itoa(a.VMsFailed), strconv.Itoa(b.VMsFailed))
+	add("duration", formatDuration(a), formatDuration(b))
+	add("error", orMissing(a.ErrorSummary), orMissing(b.ErrorSummary))
+	return diffs
+}
+
+// workloadTypes returns the execution's workload names, comma-separated.
+func workloadTypes(d *ExecutionDetail) string {
+	names := make([]string, 0, len(d.Workloads))
+	for _, w := range d.Workloads {
+		names = append(names, w.Type)
+	}
+	sort.Strings(names)
+	return orMissing(strings.Join(names, ","))
+}
+
+func workloadsByType(d *ExecutionDetail) map[string]WorkloadSummary {
+	m := make(map[string]WorkloadSummary, len(d.Workloads))
+	for _, w := range d.Workloads {
+		m[w.Type] = w
+	}
+	return m
+}
+
+func unionKeys(a, b map[string]WorkloadSummary) []string {
+	set := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		set[k] = struct{}{}
+	}
+	for k := range b {
+		set[k] = struct{}{}
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatDuration renders the execution's duration, or missingValue while it
+// is still in progress.
+func formatDuration(d *ExecutionDetail) string {
+	if d.CompletedAt == "" {
+		return missingValue
+	}
+	return (time.Duration(d.DurationSeconds) * time.Second).String()
+}
+
+func orMissing(s string) string {
+	if s == "" {
+		return missingValue
+	}
+	return s
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/audit"
+	"github.com/opdev/virtwork/internal/config"
+)
+
+var _ = Describe("CompareExecutions", func() {
+	var (
+		auditor *audit.SQLiteAuditor
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		auditor, err = audit.NewSQLiteAuditor(":memory:")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(auditor.Close()).To(Succeed())
+	})
+
+	// seed records a completed run with the given workloads, created VM count,
+	// ready VM count, and duration, and returns its details.
+	seed := func(cfg *config.Config, workloads []audit.WorkloadRecord, vms, ready int, status, errSummary string, duration time.Duration) *audit.ExecutionDetail {
+		execID, runID, err := auditor.StartExecution(ctx, "run", cfg)
+		Expect(err).NotTo(HaveOccurred())
+		var wlID int64
+		for _, w := range workloads {
+			wlID, err = auditor.RecordWorkload(ctx, execID, w)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		for i := 0; i < vms; i++ {
+			_, err := auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
+				VMName: "vm", Namespace: cfg.Namespace, Component: "cpu",
+				CPUCores: cfg.CPUCores, Memory: cfg.Memory, ContainerDiskImage: cfg.ContainerDiskImage,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		for i := 0; i < vms; i++ {
+			eventType := "vm_ready"
+			if i >= ready {
+				eventType = "vm_timeout"
+			}
+			Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{EventType: eventType})).To(Succeed())
+		}
+		Expect(auditor.CompleteExecution(ctx, execID, status, errSummary)).To(Succeed())
+		startedAt := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+		_, err = auditor.DB().Exec(`UPDATE audit_log SET started_at = ?, completed_at = ? WHERE id = ?`,
+			startedAt.Format(time.RFC3339), startedAt.Add(duration).Format(time.RFC3339), execID)
+		Expect(err).NotTo(HaveOccurred())
+
+		d, err := auditor.GetExecution(ctx, runID)
+		Expect(err).NotTo(HaveOccurred())
+		return d
+	}
+
+	byField := func(diffs []audit.FieldDiff) map[string]audit.FieldDiff {
+		m := map[string]audit.FieldDiff{}
+		for _, d := range diffs {
+			m[d.Field] = d
+		}
+		return m
+	}
+
+	It("highlights the fields that differ between two runs", func() {
+		a := seed(&config.Config{
+			Namespace: "bench", ContainerDiskImage: "quay.io/containerdisks/fedora:41", CPUCores: 2, Memory: "2Gi",
+		}, []audit.WorkloadRecord{
+			{WorkloadType: "cpu", VMCount: 2, CPUCores: 2, Memory: "2Gi"},
+			{WorkloadType: "disk", VMCount: 1, CPUCores: 2, Memory: "2Gi"},
+		}, 3, 3, "success", "", time.Minute)
+		b := seed(&config.Config{
+			Namespace: "bench", ContainerDiskImage: "quay.io/containerdisks/fedora:42", CPUCores: 2, Memory: "4Gi",
+		}, []audit.WorkloadRecord{
+			{WorkloadType: "cpu", VMCount: 4, CPUCores: 2, Memory: "4Gi"},
+			{WorkloadType: "network", VMCount: 1, CPUCores: 2, Memory: "4Gi"},
+		}, 6, 5, "failed", "1 of 6 VMs failed readiness check", 2*time.Minute)
+
+		diffs := byField(audit.CompareExecutions(a, b))
+
+		Expect(diffs["container_disk_image"]).To(Equal(audit.FieldDiff{
+			Field: "container_disk_image", A: "quay.io/containerdisks/fedora:41", B: "quay.io/containerdisks/fedora:42", Changed: true,
+		}))
+		Expect(diffs["memory"].Changed).To(BeTrue())
+		Expect(diffs["workloads"]).To(Equal(audit.FieldDiff{
+			Field: "workloads", A: "cpu,disk", B: "cpu,network", Changed: true,
+		}))
+		Expect(diffs["cpu.vm_count"]).To(Equal(audit.FieldDiff{Field: "cpu.vm_count", A: "2", B: "4", Changed: true}))
+		Expect(diffs["disk.vm_count"]).To(Equal(audit.FieldDiff{Field: "disk.vm_count", A: "1", B: "-", Changed: true}))
+		Expect(diffs["network.vm_count"]).To(Equal(audit.FieldDiff{Field: "network.vm_count", A: "-", B: "1", Changed: true}))
+		Expect(diffs["status"]).To(Equal(audit.FieldDiff{Field: "status", A: "success", B: "failed", Changed: true}))
+		Expect(diffs["vms_ready"]).To(Equal(audit.FieldDiff{Field: "vms_ready", A: "3", B: "5", Changed: true}))
+		Expect(diffs["vms_failed"]).To(Equal(audit.FieldDiff{Field: "vms_failed", A: "0", B: "1", Changed: true}))
+		Expect(diffs["duration"]).To(Equal(audit.FieldDiff{Field: "duration", A: "1m0s", B: "2m0s", Changed: true}))
+		Expect(diffs["error"]).To(Equal(audit.FieldDiff{
+			Field: "error", A: "-", B: "1 of 6 VMs failed readiness check", Changed: true,
+		}))
+	})
+
+	It("leaves matching fields unmarked", func() {
+		cfg := &config.Config{Namespace: "bench", ContainerDiskImage: "img", CPUCores: 2, Memory: "2Gi"}
+		workloads := []audit.WorkloadRecord{{WorkloadType: "cpu", VMCount: 2, CPUCores: 2, Memory: "2Gi"}}
+		a := seed(cfg, workloads, 2, 2, "success", "", time.Minute)
+		b := seed(cfg, workloads, 2, 2, "success", "", time.Minute)
+
+		for _, d := range audit.CompareExecutions(a, b) {
+			Expect(d.Changed).To(BeFalse(), d.Field)
+			Expect(d.A).To(Equal(d.B), d.Field)
+		}
+	})
+
+	It("lists fields in a fixed order", func() {
+		cfg := &config.Config{Namespace: "bench", ContainerDiskImage: "img", CPUCores: 2, Memory: "2Gi"}
+		a := seed(cfg, []audit.WorkloadRecord{{WorkloadType: "cpu", VMCount: 1, CPUCores: 2, Memory: "2Gi"}}, 1, 1, "success", "", time.Minute)
+
+		var fields []string
+		for _, d := range audit.CompareExecutions(a, a) {
+			fields = append(fields, d.Field)
+		}
+		Expect(fields).To(Equal([]string{
+			"command", "namespace", "container_disk_image", "cpu_cores", "memory", "data_disk_size", "workloads",
+			"cpu.vm_count", "cpu.cpu_cores", "cpu.memory",
+			"status", "vms_created", "vms_ready", "vms_failed", "duration", "error",
+		}))
+	})
+})
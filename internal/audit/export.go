@@ -0,0 +1,83 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Tables ExportRows can export.
+const (
+	ExportAuditLog  = "audit_log"
+	ExportVMDetails = "vm_details"
+)
+
+// RowWriter receives exported rows as strings, one call per row. A
+// *csv.Writer satisfies it.
+type RowWriter interface {
+	Write(record []string) error
+}
+
+// exportQueries select every column of each exportable table, filtered by
+// the started_at of the execution a row belongs to.
+var exportQueries = map[string]string{
+	ExportAuditLog: `
+		SELECT * FROM audit_log
+		WHERE (? = '' OR started_at >= ?)
+		ORDER BY started_at, id`,
+	ExportVMDetails: `
+		SELECT v.* FROM vm_details v
+		JOIN audit_log l ON l.id = v.audit_id
+		WHERE (? = '' OR l.started_at >= ?)
+		ORDER BY l.started_at, v.id`,
+}
+
+// ExportRows writes the column names of table and then each of its rows to
+// w, reading one row at a time so that large databases are not loaded into
+// memory. NULL is written as an empty string. A non-empty since, an RFC 3339
+// timestamp or a prefix of one such as a date, keeps only the rows of
+// executions started at or after it.
+func (a *sqlAuditor) ExportRows(ctx context.Context, table, since string, w RowWriter) error {
+	query, ok := exportQueries[table]
+	if !ok {
+		return fmt.Errorf("unknown export table %q: must be %s or %s", table, ExportAuditLog, ExportVMDetails)
+	}
+	rows, err := a.db.QueryContext(ctx, a.rebind(query), since, since)
+	if err != nil {
+		return fmt.Errorf("querying %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("reading %s columns: %w", table, err)
+	}
+	if err := w.Write(columns); err != nil {
+		return err
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	record := make([]string, len(columns))
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("scanning %s: %w", table, err)
+		}
+		for i, v := range values {
+			record[i] = v.String
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", table, err)
+	}
+	return nil
+}
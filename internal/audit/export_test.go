@@ -0,0 +1,124 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// PostgresSchemaSQL exposes the PostgreSQL DDL for testing.
+var PostgresSchemaSQL = postgresSchemaSQL
+
+// RebindForPostgres exposes placeholder rebinding for testing.
+func RebindForPostgres(query string) string {
+	return (&sqlAuditor{postgres: true}).rebind(query)
+}
+
+// NewRecordingPostgresAuditor returns a PostgresAuditor over a driver that
+// records every statement it is sent, and a func returning them. Statements
+// succeed; each query returns one row of 1s with as many columns as its
+// select list, so methods run all their queries.
+func NewRecordingPostgresAuditor() (*PostgresAuditor, func() []string) {
+	d := &recordingDriver{}
+	db := sql.OpenDB(d)
+	if _, err := db.Exec(postgresSchemaSQL); err != nil {
+		panic(err)
+	}
+	if err := addMissingColumns(db, true); err != nil {
+		panic(err)
+	}
+	return &PostgresAuditor{sqlAuditor{db: db, postgres: true}}, d.recorded
+}
+
+type recordingDriver struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (d *recordingDriver) Connect(context.Context) (driver.Conn, error) { return recordingConn{d}, nil }
+func (d *recordingDriver) Driver() driver.Driver                        { return nil }
+
+func (d *recordingDriver) record(query string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, query)
+}
+
+func (d *recordingDriver) recorded() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.queries)
+}
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (c recordingConn) Close() error { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+func (c recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.d.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.d.record(query)
+	return &recordingRows{columns: selectColumnCount(query)}, nil
+}
+
+// selectColumnCount counts the items of the outermost select list of query,
+// or returns 1 for an INSERT ... RETURNING id.
+func selectColumnCount(query string) int {
+	start := strings.Index(query, "SELECT")
+	if start < 0 {
+		return 1
+	}
+	n, depth := 1, 0
+	for i := start + len("SELECT"); i < len(query); i++ {
+		switch query[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				n++
+			}
+		}
+		if depth == 0 && strings.HasPrefix(query[i:], "FROM") {
+			break
+		}
+	}
+	return n
+}
+
+type recordingRows struct {
+	columns int
+	done    bool
+}
+
+func (r *recordingRows) Columns() []string { return make([]string, r.columns) }
+func (r *recordingRows) Close() error      { return nil }
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	for i := range dest {
+		dest[i] = int64(1)
+	}
+	return nil
+}
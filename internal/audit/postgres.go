@@ -0,0 +1,47 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// postgresSchemaSQL adapts schemaSQL for PostgreSQL: autoincrement keys
+// become SERIAL and linked_run_ids becomes JSONB. Timestamps stay ISO 8601
+// TEXT so both backends store identical values.
+var postgresSchemaSQL = strings.NewReplacer(
+	"INTEGER PRIMARY KEY AUTOINCREMENT", "SERIAL PRIMARY KEY",
+	"linked_run_ids        TEXT", "linked_run_ids        JSONB",
+).Replace(schemaSQL)
+
+// PostgresAuditor implements Auditor backed by a PostgreSQL database, for
+// sharing one audit store across many virtwork invocations.
+type PostgresAuditor struct {
+	sqlAuditor
+}
+
+// NewPostgresAuditor connects to the PostgreSQL database at dsn and ensures
+// the schema is applied. The DSN may be a URL (postgres://...) or a
+// key=value connection string.
+func NewPostgresAuditor(dsn string) (*PostgresAuditor, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit db: %w", err)
+	}
+
+	if _, err := db.Exec(postgresSchemaSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("applying audit schema: %w", err)
+	}
+	if err := addMissingColumns(db, true); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("applying audit schema: %w", err)
+	}
+
+	return &PostgresAuditor{sqlAuditor{db: db, postgres: true}}, nil
+}
@@ -0,0 +1,135 @@
+//go:build integration
+
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/audit"
+	"github.com/opdev/virtwork/internal/config"
+)
+
+// postgresDSN returns the DSN of a disposable PostgreSQL database used by
+// the integration tests. Tests are skipped when it is unset.
+func postgresDSN() string {
+	return os.Getenv("VIRTWORK_TEST_AUDIT_DSN")
+}
+
+var _ = Describe("PostgresAuditor [integration]", func() {
+	var (
+		auditor *audit.PostgresAuditor
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		if postgresDSN() == "" {
+			Skip("VIRTWORK_TEST_AUDIT_DSN not set")
+		}
+		ctx = context.Background()
+		var err error
+		auditor, err = audit.NewPostgresAuditor(postgresDSN())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		if auditor != nil {
+			Expect(auditor.Close()).To(Succeed())
+		}
+	})
+
+	It("is idempotent when the schema already exists", func() {
+		second, err := audit.NewPostgresAuditor(postgresDSN())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.Close()).To(Succeed())
+	})
+
+	It("records a full run lifecycle", func() {
+		cfg := &config.Config{
+			Namespace:           "test-ns",
+			ContainerDiskImage:  "quay.io/test/image:latest",
+			CPUCores:            4,
+			Memory:              "4Gi",
+			KubeContext:         "prod-admin",
+			WaitForReady:        true,
+			ReadyTimeoutSeconds: 300,
+			Workloads: map[string]config.WorkloadConfig{
+				"cpu": {Enabled: true, VMCount: 1, CPUCores: 4, Memory: "4Gi"},
+			},
+		}
+
+		execID, runID, err := auditor.StartExecution(ctx, "run", cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(execID).To(BeNumerically(">", 0))
+
+		wlID, err := auditor.RecordWorkload(ctx, execID, audit.WorkloadRecord{
+			WorkloadType: "cpu", Enabled: true, VMCount: 1, CPUCores: 4, Memory: "4Gi",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wlID).To(BeNumerically(">", 0))
+
+		vmID, err := auditor.RecordVM(ctx, execID, wlID, audit.VMRecord{
+			VMName: "virtwork-cpu-0", Namespace: "test-ns", Component: "cpu",
+			CPUCores: 4, Memory: "4Gi", ContainerDiskImage: "quay.io/test/image:latest",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auditor.UpdateVMStatus(ctx, vmID, "Running", "ready")).To(Succeed())
+
+		createdAt, readyAt, err := auditor.VMTimestamps(ctx, runID, "virtwork-cpu-0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(createdAt).NotTo(BeEmpty())
+		Expect(readyAt).NotTo(BeEmpty())
+
+		resID, err := auditor.RecordResource(ctx, execID, audit.ResourceRecord{
+			ResourceType: "Secret", ResourceName: "virtwork-cpu-0-cloudinit", Namespace: "test-ns",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auditor.RecordResourceDeletion(ctx, resID)).To(Succeed())
+
+		Expect(auditor.RecordEvent(ctx, execID, audit.EventRecord{
+			VMID: &vmID, EventType: "vm_ready", Message: "VM ready",
+		})).To(Succeed())
+		Expect(auditor.UpdateWorkloadStatus(ctx, wlID, "created")).To(Succeed())
+		Expect(auditor.RecordVMDeletion(ctx, vmID)).To(Succeed())
+		Expect(auditor.CompleteExecution(ctx, execID, "success", "")).To(Succeed())
+
+		var status string
+		var clusterContext sql.NullString
+		err = auditor.DB().QueryRow(
+			`SELECT status, cluster_context FROM audit_log WHERE id = $1`, execID,
+		).Scan(&status, &clusterContext)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal("success"))
+		Expect(clusterContext.String).To(Equal("prod-admin"))
+	})
+
+	It("links cleanup to runs via a JSONB array", func() {
+		cfg := &config.Config{Namespace: "test-ns"}
+		_, runID, err := auditor.StartExecution(ctx, "run", cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		cleanupID, _, err := auditor.StartExecution(ctx, "cleanup", cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auditor.LinkCleanupToRuns(ctx, cleanupID, []string{runID})).To(Succeed())
+		Expect(auditor.RecordCleanupCounts(ctx, cleanupID, 1, 0, 1, false)).To(Succeed())
+
+		var linked string
+		err = auditor.DB().QueryRow(
+			`SELECT linked_run_ids::text FROM audit_log WHERE id = $1 AND jsonb_typeof(linked_run_ids) = 'array'`,
+			cleanupID,
+		).Scan(&linked)
+		Expect(err).NotTo(HaveOccurred())
+
+		var ids []string
+		Expect(json.Unmarshal([]byte(linked), &ids)).To(Succeed())
+		Expect(ids).To(ConsistOf(runID))
+	})
+})
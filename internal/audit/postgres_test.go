@@ -0,0 +1,97 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/audit"
+	"github.com/opdev/virtwork/internal/config"
+)
+
+var _ = Describe("PostgresAuditor", func() {
+	Describe("schema", func() {
+		It("uses SERIAL keys instead of AUTOINCREMENT", func() {
+			Expect(audit.PostgresSchemaSQL).NotTo(ContainSubstring("AUTOINCREMENT"))
+			Expect(audit.PostgresSchemaSQL).To(ContainSubstring("id                    SERIAL PRIMARY KEY"))
+		})
+
+		It("stores linked_run_ids as JSONB", func() {
+			Expect(audit.PostgresSchemaSQL).To(MatchRegexp(`linked_run_ids\s+JSONB`))
+		})
+
+		It("creates the same tables and indexes as SQLite", func() {
+			for _, name := range []string{
+				"audit_log", "workload_details", "vm_details", "resource_details", "events",
+				"idx_audit_log_run_id", "idx_vm_details_vm_name", "idx_events_occurred_at",
+			} {
+				Expect(audit.PostgresSchemaSQL).To(ContainSubstring(name))
+			}
+		})
+	})
+
+	Describe("placeholder rebinding", func() {
+		It("numbers placeholders in order", func() {
+			Expect(audit.RebindForPostgres(`UPDATE audit_log SET status = ?, completed_at = ? WHERE id = ?`)).
+				To(Equal(`UPDATE audit_log SET status = $1, completed_at = $2 WHERE id = $3`))
+		})
+
+		It("leaves queries without placeholders unchanged", func() {
+			Expect(audit.RebindForPostgres(`SELECT 1`)).To(Equal(`SELECT 1`))
+		})
+
+		It("rebinds every query the auditor sends", func() {
+			a, recorded := audit.NewRecordingPostgresAuditor()
+			ctx := context.Background()
+
+			id, _, err := a.StartExecution(ctx, "run", &config.Config{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a.CompleteExecution(ctx, id, "success", "")).To(Succeed())
+			Expect(a.LinkCleanupToRuns(ctx, id, []string{"run-a"})).To(Succeed())
+			Expect(a.RecordCleanupCounts(ctx, id, 1, 1, 1, true)).To(Succeed())
+			Expect(a.RecordTotals(ctx, id, 1, 1)).To(Succeed())
+			wlID, err := a.RecordWorkload(ctx, id, audit.WorkloadRecord{WorkloadType: "cpu"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a.UpdateWorkloadStatus(ctx, wlID, "created")).To(Succeed())
+			vmID, err := a.RecordVM(ctx, id, wlID, audit.VMRecord{VMName: "virtwork-cpu-0"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a.UpdateVMStatus(ctx, vmID, "Running", "ready")).To(Succeed())
+			Expect(a.UpdateVMStatus(ctx, vmID, "Failed", "failed")).To(Succeed())
+			Expect(a.RecordVMDeletion(ctx, vmID)).To(Succeed())
+			resID, err := a.RecordResource(ctx, id, audit.ResourceRecord{ResourceType: "Service"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a.RecordResourceDeletion(ctx, resID)).To(Succeed())
+			Expect(a.RecordEvent(ctx, id, audit.EventRecord{EventType: "vm_ready"})).To(Succeed())
+			_, _, err = a.VMTimestamps(ctx, "run-a", "virtwork-cpu-0")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = a.ListExecutions(ctx, audit.ExecutionFilter{Status: "success", Namespace: "virtwork"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = a.GetExecution(ctx, "run-a")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = a.FindRunsByNamespace(ctx, "virtwork")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = a.ListEvents(ctx, "run-a", 0)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = a.AggregateStats(ctx, "2026-01-01")
+			Expect(err).NotTo(HaveOccurred())
+			for _, table := range []string{audit.ExportAuditLog, audit.ExportVMDetails} {
+				Expect(a.ExportRows(ctx, table, "2026-01-01", &discardRows{})).To(Succeed())
+			}
+
+			queries := recorded()
+			Expect(len(queries)).To(BeNumerically(">", 20))
+			for _, q := range queries {
+				Expect(q).NotTo(ContainSubstring("?"), q)
+			}
+		})
+	})
+})
+
+// discardRows is a RowWriter that discards what it is given.
+type discardRows struct{}
+
+func (discardRows) Write([]string) error { return nil }
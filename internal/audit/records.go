@@ -13,6 +13,9 @@ type WorkloadRecord struct {
 	HasDataDisk    bool
 	DataDiskSize   string
 	RequiresService bool
+	// ContainerDiskImage is the workload's effective image; role-specific
+	// images are recorded on each VMRecord.
+	ContainerDiskImage string
 }
 
 // VMRecord holds data for inserting a vm_details row.
@@ -43,3 +46,61 @@ type EventRecord struct {
 	Message     string
 	ErrorDetail string
 }
+
+// EventRow is one events row as returned by ListEvents.
+type EventRow struct {
+	ID          int64  `json:"id"`
+	EventType   string `json:"event_type"`
+	VMName      string `json:"vm_name,omitempty"`
+	Message     string `json:"message,omitempty"`
+	ErrorDetail string `json:"error_detail,omitempty"`
+	OccurredAt  string `json:"occurred_at"`
+}
+
+// ExecutionFilter narrows the executions returned by ListExecutions. Empty
+// fields match everything.
+type ExecutionFilter struct {
+	Status    string
+	Namespace string
+}
+
+// ExecutionSummary is a one-line view of an audit_log row.
+type ExecutionSummary struct {
+	RunID           string `json:"run_id"`
+	Command         string `json:"command"`
+	Namespace       string `json:"namespace"`
+	Status          string `json:"status"`
+	VMCount         int    `json:"vm_count"`
+	StartedAt       string `json:"started_at"`
+	CompletedAt     string `json:"completed_at,omitempty"`
+	DurationSeconds int64  `json:"duration_seconds"`
+}
+
+// ExecutionDetail is the configuration and outcome of one audit_log row, as
+// returned by GetExecution.
+type ExecutionDetail struct {
+	RunID              string            `json:"run_id"`
+	Command            string            `json:"command"`
+	Namespace          string            `json:"namespace"`
+	Status             string            `json:"status"`
+	ContainerDiskImage string            `json:"container_disk_image"`
+	CPUCores           int               `json:"cpu_cores"`
+	Memory             string            `json:"memory"`
+	DataDiskSize       string            `json:"data_disk_size"`
+	Workloads          []WorkloadSummary `json:"workloads"`
+	VMsCreated         int               `json:"vms_created"`
+	VMsReady           int               `json:"vms_ready"`
+	VMsFailed          int               `json:"vms_failed"`
+	StartedAt          string            `json:"started_at"`
+	CompletedAt        string            `json:"completed_at,omitempty"`
+	DurationSeconds    int64             `json:"duration_seconds"`
+	ErrorSummary       string            `json:"error_summary,omitempty"`
+}
+
+// WorkloadSummary is the recorded configuration of one workload in a run.
+type WorkloadSummary struct {
+	Type     string `json:"type"`
+	VMCount  int    `json:"vm_count"`
+	CPUCores int    `json:"cpu_cores"`
+	Memory   string `json:"memory"`
+}
@@ -3,6 +3,65 @@
 
 package audit
 
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Schema dialects accepted by Schema.
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+// Schema returns the audit DDL for the given dialect, as applied by
+// NewSQLiteAuditor or NewPostgresAuditor.
+func Schema(dialect string) (string, error) {
+	switch dialect {
+	case DialectSQLite:
+		return schemaSQL, nil
+	case DialectPostgres:
+		return postgresSchemaSQL, nil
+	default:
+		return "", fmt.Errorf("unknown schema dialect %q: must be %s or %s", dialect, DialectSQLite, DialectPostgres)
+	}
+}
+
+// addedColumns lists columns added to existing tables after their first
+// release. CREATE TABLE IF NOT EXISTS leaves databases created before then
+// without them, so addMissingColumns adds them when a database is opened.
+var addedColumns = []struct {
+	table, column, decl string
+}{
+	{"workload_details", "container_disk_image", "TEXT"},
+	{"audit_log", "git_sha", "TEXT"},
+	{"audit_log", "git_branch", "TEXT"},
+	{"audit_log", "ci_url", "TEXT"},
+}
+
+// addMissingColumns adds any of addedColumns that db lacks.
+func addMissingColumns(db *sql.DB, postgres bool) error {
+	for _, c := range addedColumns {
+		if postgres {
+			if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`, c.table, c.column, c.decl)); err != nil {
+				return fmt.Errorf("adding %s.%s: %w", c.table, c.column, err)
+			}
+			continue
+		}
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, c.table, c.column).Scan(&n); err != nil {
+			return fmt.Errorf("inspecting %s: %w", c.table, err)
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, c.table, c.column, c.decl)); err != nil {
+			return fmt.Errorf("adding %s.%s: %w", c.table, c.column, err)
+		}
+	}
+	return nil
+}
+
 // schemaSQL contains the DDL for the audit database.
 // All timestamps are stored as ISO 8601 TEXT for SQLite compatibility
 // while remaining PostgreSQL-compatible (TEXT maps to TEXT/TIMESTAMP,
@@ -35,7 +94,10 @@ CREATE TABLE IF NOT EXISTS audit_log (
 	namespace_deleted     INTEGER,
 	started_at            TEXT    NOT NULL,
 	completed_at          TEXT,
-	error_summary         TEXT
+	error_summary         TEXT,
+	git_sha               TEXT,
+	git_branch            TEXT,
+	ci_url                TEXT
 );
 
 CREATE INDEX IF NOT EXISTS idx_audit_log_started_at ON audit_log(started_at);
@@ -44,19 +106,20 @@ CREATE INDEX IF NOT EXISTS idx_audit_log_status     ON audit_log(status);
 CREATE INDEX IF NOT EXISTS idx_audit_log_run_id     ON audit_log(run_id);
 
 CREATE TABLE IF NOT EXISTS workload_details (
-	id               INTEGER PRIMARY KEY AUTOINCREMENT,
-	audit_id         INTEGER NOT NULL REFERENCES audit_log(id),
-	workload_type    TEXT    NOT NULL,
-	enabled          INTEGER NOT NULL DEFAULT 1,
-	vm_count         INTEGER NOT NULL,
-	cpu_cores        INTEGER NOT NULL,
-	memory           TEXT    NOT NULL,
-	has_data_disk    INTEGER NOT NULL DEFAULT 0,
-	data_disk_size   TEXT,
-	requires_service INTEGER NOT NULL DEFAULT 0,
-	status           TEXT    NOT NULL DEFAULT 'pending',
-	started_at       TEXT,
-	completed_at     TEXT
+	id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+	audit_id             INTEGER NOT NULL REFERENCES audit_log(id),
+	workload_type        TEXT    NOT NULL,
+	enabled              INTEGER NOT NULL DEFAULT 1,
+	vm_count             INTEGER NOT NULL,
+	cpu_cores            INTEGER NOT NULL,
+	memory               TEXT    NOT NULL,
+	has_data_disk        INTEGER NOT NULL DEFAULT 0,
+	data_disk_size       TEXT,
+	requires_service     INTEGER NOT NULL DEFAULT 0,
+	container_disk_image TEXT,
+	status               TEXT    NOT NULL DEFAULT 'pending',
+	started_at           TEXT,
+	completed_at         TEXT
 );
 
 CREATE INDEX IF NOT EXISTS idx_workload_details_audit_id ON workload_details(audit_id);
@@ -0,0 +1,45 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/audit"
+)
+
+var _ = Describe("Schema", func() {
+	tables := []string{
+		"CREATE TABLE IF NOT EXISTS audit_log",
+		"CREATE TABLE IF NOT EXISTS workload_details",
+		"CREATE TABLE IF NOT EXISTS vm_details",
+		"CREATE TABLE IF NOT EXISTS resource_details",
+		"CREATE TABLE IF NOT EXISTS events",
+	}
+
+	It("returns the SQLite DDL", func() {
+		ddl, err := audit.Schema(audit.DialectSQLite)
+		Expect(err).NotTo(HaveOccurred())
+		for _, t := range tables {
+			Expect(ddl).To(ContainSubstring(t))
+		}
+		Expect(ddl).To(ContainSubstring("INTEGER PRIMARY KEY AUTOINCREMENT"))
+	})
+
+	It("returns the PostgreSQL DDL", func() {
+		ddl, err := audit.Schema(audit.DialectPostgres)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ddl).To(Equal(audit.PostgresSchemaSQL))
+		for _, t := range tables {
+			Expect(ddl).To(ContainSubstring(t))
+		}
+		Expect(ddl).NotTo(ContainSubstring("AUTOINCREMENT"))
+	})
+
+	It("rejects an unknown dialect", func() {
+		_, err := audit.Schema("mysql")
+		Expect(err).To(MatchError(ContainSubstring(`unknown schema dialect "mysql"`)))
+	})
+})
@@ -0,0 +1,133 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Stats aggregates the executions recorded in the audit database.
+type Stats struct {
+	Since                  string          `json:"since,omitempty"`
+	Executions             int             `json:"executions"`
+	ByStatus               map[string]int  `json:"by_status"`
+	AverageDurationSeconds int64           `json:"average_duration_seconds"`
+	VMsCreated             int             `json:"vms_created"`
+	VMsDeleted             int             `json:"vms_deleted"`
+	Workloads              []WorkloadStats `json:"workloads"`
+}
+
+// WorkloadStats aggregates the executions that recorded one workload type.
+type WorkloadStats struct {
+	Type       string `json:"type"`
+	Executions int    `json:"executions"`
+	Succeeded  int    `json:"succeeded"`
+	Failed     int    `json:"failed"`
+	VMs        int    `json:"vms"`
+}
+
+// AggregateStats summarizes the executions started at or after since, an
+// RFC 3339 timestamp or a prefix of one; an empty since covers them all. The
+// average duration is over completed executions only. VMs created counts the
+// VMs recorded by runs and VMs deleted sums the counts recorded by cleanups.
+// Workloads are sorted by type.
+func (a *sqlAuditor) AggregateStats(ctx context.Context, since string) (*Stats, error) {
+	stats := &Stats{Since: since, ByStatus: map[string]int{}, Workloads: []WorkloadStats{}}
+
+	rows, err := a.db.QueryContext(ctx, a.rebind(`
+		SELECT status, COUNT(*) FROM audit_log
+		WHERE (? = '' OR started_at >= ?)
+		GROUP BY status`),
+		since, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying audit_log: %w", err)
+	}
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning audit_log: %w", err)
+		}
+		stats.ByStatus[status] = n
+		stats.Executions += n
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit_log: %w", err)
+	}
+
+	rows, err = a.db.QueryContext(ctx, a.rebind(`
+		SELECT started_at, completed_at FROM audit_log
+		WHERE (? = '' OR started_at >= ?) AND completed_at IS NOT NULL`),
+		since, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying audit_log: %w", err)
+	}
+	var total int64
+	var completed int64
+	for rows.Next() {
+		var startedAt, completedAt string
+		if err := rows.Scan(&startedAt, &completedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning audit_log: %w", err)
+		}
+		total += durationSeconds(startedAt, completedAt)
+		completed++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit_log: %w", err)
+	}
+	if completed > 0 {
+		stats.AverageDurationSeconds = total / completed
+	}
+
+	var vmsDeleted sql.NullInt64
+	err = a.db.QueryRowContext(ctx, a.rebind(`
+		SELECT
+			(SELECT COUNT(*) FROM vm_details v
+				JOIN audit_log l ON l.id = v.audit_id
+				WHERE (? = '' OR l.started_at >= ?)),
+			(SELECT SUM(vms_deleted) FROM audit_log
+				WHERE (? = '' OR started_at >= ?))`),
+		since, since, since, since,
+	).Scan(&stats.VMsCreated, &vmsDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("counting VMs: %w", err)
+	}
+	stats.VMsDeleted = int(vmsDeleted.Int64)
+
+	rows, err = a.db.QueryContext(ctx, a.rebind(`
+		SELECT w.workload_type, COUNT(DISTINCT l.id),
+			COUNT(DISTINCT CASE WHEN l.status = 'success' THEN l.id END),
+			COUNT(DISTINCT CASE WHEN l.status = 'failed' THEN l.id END),
+			SUM(w.vm_count)
+		FROM workload_details w
+		JOIN audit_log l ON l.id = w.audit_id
+		WHERE (? = '' OR l.started_at >= ?)
+		GROUP BY w.workload_type
+		ORDER BY w.workload_type`),
+		since, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying workload_details: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var w WorkloadStats
+		if err := rows.Scan(&w.Type, &w.Executions, &w.Succeeded, &w.Failed, &w.VMs); err != nil {
+			return nil, fmt.Errorf("scanning workload_details: %w", err)
+		}
+		stats.Workloads = append(stats.Workloads, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading workload_details: %w", err)
+	}
+	return stats, nil
+}
@@ -6,8 +6,14 @@ package cleanup
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubevirtv1 "kubevirt.io/api/core/v1"
@@ -24,74 +30,233 @@ type CleanupResult struct {
 	NamespaceDeleted bool
 	Errors           []error
 	RunIDs           []string // unique run IDs collected from cleaned-up resources
+
+	// RBACDeleted counts ServiceAccounts, Roles, and RoleBindings.
+	RBACDeleted int
+	// ConfigMapsDeleted counts ConfigMaps left behind by the api-churn
+	// workload's guests.
+	ConfigMapsDeleted int
+	// NetworkPoliciesDeleted counts NetworkPolicies created with
+	// --network-policy.
+	NetworkPoliciesDeleted int
+
+	// Planned lists the resources PlanCleanup found as sorted "kind/name"
+	// entries. A cleanup that deletes leaves it empty.
+	Planned []string
+}
+
+// Add folds other into r, as when one cleanup spans several namespaces:
+// counts are summed, errors and planned entries appended, and run IDs kept
+// unique. NamespaceDeleted is set if either deleted its namespace.
+func (r *CleanupResult) Add(other *CleanupResult) {
+	r.VMsDeleted += other.VMsDeleted
+	r.ServicesDeleted += other.ServicesDeleted
+	r.SecretsDeleted += other.SecretsDeleted
+	r.RBACDeleted += other.RBACDeleted
+	r.ConfigMapsDeleted += other.ConfigMapsDeleted
+	r.NetworkPoliciesDeleted += other.NetworkPoliciesDeleted
+	r.NamespaceDeleted = r.NamespaceDeleted || other.NamespaceDeleted
+	r.Errors = append(r.Errors, other.Errors...)
+	r.Planned = append(r.Planned, other.Planned...)
+	for _, id := range other.RunIDs {
+		if !slices.Contains(r.RunIDs, id) {
+			r.RunIDs = append(r.RunIDs, id)
+		}
+	}
+}
+
+// DeleteOptions builds the delete options for VMs and Secrets from the
+// cleanup flags. A negative gracePeriodSeconds and an empty propagation leave
+// the API server defaults in place. Propagation is one of Foreground,
+// Background, or Orphan (case-insensitive).
+func DeleteOptions(gracePeriodSeconds int64, propagation string) ([]client.DeleteOption, error) {
+	var opts []client.DeleteOption
+	if gracePeriodSeconds >= 0 {
+		opts = append(opts, client.GracePeriodSeconds(gracePeriodSeconds))
+	}
+	if propagation != "" {
+		policy, err := parsePropagation(propagation)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.PropagationPolicy(policy))
+	}
+	return opts, nil
 }
 
-// CleanupAll deletes all virtwork-managed resources in the given namespace.
+// parsePropagation maps a user-supplied propagation name to its policy.
+func parsePropagation(s string) (metav1.DeletionPropagation, error) {
+	for _, p := range []metav1.DeletionPropagation{
+		metav1.DeletePropagationForeground,
+		metav1.DeletePropagationBackground,
+		metav1.DeletePropagationOrphan,
+	} {
+		if strings.EqualFold(s, string(p)) {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("invalid propagation %q: must be Foreground, Background, or Orphan", s)
+}
+
+// CleanupAll deletes all virtwork-managed resources in the given namespace,
+// running up to constants.DefaultCleanupConcurrency deletions at a time. See
+// CleanupAllConcurrent.
+func CleanupAll(ctx context.Context, c client.Client, namespace string, deleteNamespace bool, runID string, deleteOpts ...client.DeleteOption) (*CleanupResult, error) {
+	return CleanupAllConcurrent(ctx, c, namespace, deleteNamespace, runID, nil, constants.DefaultCleanupConcurrency, deleteOpts...)
+}
+
+// CleanupAllConcurrent deletes all virtwork-managed resources in the given namespace.
 // If runID is non-empty, only resources with that specific virtwork/run-id label are deleted.
+// Any selector labels narrow the match further; the managed-by label is
+// always required, so a selector cannot reach resources virtwork does not own.
+// VMs, then Services, NetworkPolicies, Secrets, ConfigMaps, RoleBindings,
+// Roles, and ServiceAccounts are deleted, each kind with up to
+// concurrency deletions in flight; a concurrency below 1 deletes one at a time.
 // Individual deletion failures are recorded but do not abort the operation.
 // If deleteNamespace is true, the namespace itself is deleted as the final step.
-func CleanupAll(ctx context.Context, c client.Client, namespace string, deleteNamespace bool, runID string) (*CleanupResult, error) {
+// Any deleteOpts are applied when deleting VMs and Secrets, the resources that
+// own or carry VM data; Services and the namespace use the defaults.
+func CleanupAllConcurrent(ctx context.Context, c client.Client, namespace string, deleteNamespace bool, runID string, selector map[string]string, concurrency int, deleteOpts ...client.DeleteOption) (*CleanupResult, error) {
+	return cleanupMatching(ctx, c, namespace, deleteNamespace, runID, selector, time.Time{}, concurrency, deleteOpts)
+}
+
+// CleanupOlderThan deletes the virtwork-managed resources in the namespace
+// that CleanupAllConcurrent would delete for runID and selector, but only
+// those created before cutoff, so stale runs can be pruned while newer ones
+// keep running. The namespace itself is never deleted.
+func CleanupOlderThan(ctx context.Context, c client.Client, namespace, runID string, selector map[string]string, cutoff time.Time, concurrency int, deleteOpts ...client.DeleteOption) (*CleanupResult, error) {
+	return cleanupMatching(ctx, c, namespace, false, runID, selector, cutoff, concurrency, deleteOpts)
+}
+
+// cleanupMatching implements CleanupAllConcurrent and CleanupOlderThan. A zero
+// cutoff matches resources of any age.
+func cleanupMatching(ctx context.Context, c client.Client, namespace string, deleteNamespace bool, runID string, selector map[string]string, cutoff time.Time, concurrency int, deleteOpts []client.DeleteOption) (*CleanupResult, error) {
 	result := &CleanupResult{}
-	managedLabels := map[string]string{
-		constants.LabelManagedBy: constants.ManagedByValue,
+	if err := checkSelector(selector); err != nil {
+		return result, err
 	}
-	if runID != "" {
-		managedLabels[constants.LabelRunID] = runID
-	}
-
 	runIDSet := make(map[string]struct{})
 
 	// Delete VMs by label
 	vmList := &kubevirtv1.VirtualMachineList{}
 	listOpts := []client.ListOption{
 		client.InNamespace(namespace),
-		client.MatchingLabels(managedLabels),
+		client.MatchingLabels(managedLabels(runID, selector)),
 	}
 	if err := c.List(ctx, vmList, listOpts...); err != nil {
 		return result, fmt.Errorf("listing VMs in %s: %w", namespace, err)
 	}
+	vms := make([]client.Object, 0, len(vmList.Items))
 	for i := range vmList.Items {
-		collectRunID(vmList.Items[i].Labels, runIDSet)
-		if err := c.Delete(ctx, &vmList.Items[i]); err != nil {
-			if !apierrors.IsNotFound(err) {
-				result.Errors = append(result.Errors, fmt.Errorf("deleting VM %s: %w", vmList.Items[i].Name, err))
-			}
+		if !createdBefore(&vmList.Items[i], cutoff) {
 			continue
 		}
-		result.VMsDeleted++
+		collectRunID(vmList.Items[i].Labels, runIDSet)
+		vms = append(vms, &vmList.Items[i])
 	}
+	result.VMsDeleted = deleteObjects(ctx, c, vms, "VM", concurrency, deleteOpts, result)
 
 	// Delete services by label
 	svcList := &corev1.ServiceList{}
 	if err := c.List(ctx, svcList, listOpts...); err != nil {
 		return result, fmt.Errorf("listing services in %s: %w", namespace, err)
 	}
+	svcs := make([]client.Object, 0, len(svcList.Items))
 	for i := range svcList.Items {
+		if !createdBefore(&svcList.Items[i], cutoff) {
+			continue
+		}
 		collectRunID(svcList.Items[i].Labels, runIDSet)
-		if err := c.Delete(ctx, &svcList.Items[i]); err != nil {
-			if !apierrors.IsNotFound(err) {
-				result.Errors = append(result.Errors, fmt.Errorf("deleting service %s: %w", svcList.Items[i].Name, err))
-			}
+		svcs = append(svcs, &svcList.Items[i])
+	}
+	result.ServicesDeleted = deleteObjects(ctx, c, svcs, "service", concurrency, nil, result)
+
+	// Delete network policies by label
+	npList := &networkingv1.NetworkPolicyList{}
+	if err := c.List(ctx, npList, listOpts...); err != nil {
+		return result, fmt.Errorf("listing network policies in %s: %w", namespace, err)
+	}
+	nps := make([]client.Object, 0, len(npList.Items))
+	for i := range npList.Items {
+		if !createdBefore(&npList.Items[i], cutoff) {
 			continue
 		}
-		result.ServicesDeleted++
+		collectRunID(npList.Items[i].Labels, runIDSet)
+		nps = append(nps, &npList.Items[i])
 	}
+	result.NetworkPoliciesDeleted = deleteObjects(ctx, c, nps, "network policy", concurrency, nil, result)
 
 	// Delete secrets by label
 	secretList := &corev1.SecretList{}
 	if err := c.List(ctx, secretList, listOpts...); err != nil {
 		return result, fmt.Errorf("listing secrets in %s: %w", namespace, err)
 	}
+	secrets := make([]client.Object, 0, len(secretList.Items))
 	for i := range secretList.Items {
+		if !createdBefore(&secretList.Items[i], cutoff) {
+			continue
+		}
 		collectRunID(secretList.Items[i].Labels, runIDSet)
-		if err := c.Delete(ctx, &secretList.Items[i]); err != nil {
-			if !apierrors.IsNotFound(err) {
-				result.Errors = append(result.Errors, fmt.Errorf("deleting secret %s: %w", secretList.Items[i].Name, err))
-			}
+		secrets = append(secrets, &secretList.Items[i])
+	}
+	result.SecretsDeleted = deleteObjects(ctx, c, secrets, "secret", concurrency, deleteOpts, result)
+
+	// Delete ConfigMaps created from inside api-churn VMs by label
+	cmList := &corev1.ConfigMapList{}
+	if err := c.List(ctx, cmList, listOpts...); err != nil {
+		return result, fmt.Errorf("listing config maps in %s: %w", namespace, err)
+	}
+	cms := make([]client.Object, 0, len(cmList.Items))
+	for i := range cmList.Items {
+		if !createdBefore(&cmList.Items[i], cutoff) {
+			continue
+		}
+		cms = append(cms, &cmList.Items[i])
+	}
+	result.ConfigMapsDeleted = deleteObjects(ctx, c, cms, "config map", concurrency, nil, result)
+
+	// Delete RBAC objects by label, bindings before what they bind
+	bindingList := &rbacv1.RoleBindingList{}
+	if err := c.List(ctx, bindingList, listOpts...); err != nil {
+		return result, fmt.Errorf("listing role bindings in %s: %w", namespace, err)
+	}
+	bindings := make([]client.Object, 0, len(bindingList.Items))
+	for i := range bindingList.Items {
+		if !createdBefore(&bindingList.Items[i], cutoff) {
 			continue
 		}
-		result.SecretsDeleted++
+		collectRunID(bindingList.Items[i].Labels, runIDSet)
+		bindings = append(bindings, &bindingList.Items[i])
 	}
+	result.RBACDeleted += deleteObjects(ctx, c, bindings, "role binding", concurrency, nil, result)
+
+	roleList := &rbacv1.RoleList{}
+	if err := c.List(ctx, roleList, listOpts...); err != nil {
+		return result, fmt.Errorf("listing roles in %s: %w", namespace, err)
+	}
+	roles := make([]client.Object, 0, len(roleList.Items))
+	for i := range roleList.Items {
+		if !createdBefore(&roleList.Items[i], cutoff) {
+			continue
+		}
+		collectRunID(roleList.Items[i].Labels, runIDSet)
+		roles = append(roles, &roleList.Items[i])
+	}
+	result.RBACDeleted += deleteObjects(ctx, c, roles, "role", concurrency, nil, result)
+
+	saList := &corev1.ServiceAccountList{}
+	if err := c.List(ctx, saList, listOpts...); err != nil {
+		return result, fmt.Errorf("listing service accounts in %s: %w", namespace, err)
+	}
+	sas := make([]client.Object, 0, len(saList.Items))
+	for i := range saList.Items {
+		if !createdBefore(&saList.Items[i], cutoff) {
+			continue
+		}
+		collectRunID(saList.Items[i].Labels, runIDSet)
+		sas = append(sas, &saList.Items[i])
+	}
+	result.RBACDeleted += deleteObjects(ctx, c, sas, "service account", concurrency, nil, result)
 
 	// Collect unique run IDs
 	for id := range runIDSet {
@@ -117,6 +282,61 @@ func CleanupAll(ctx context.Context, c client.Client, namespace string, deleteNa
 	return result, nil
 }
 
+// createdBefore reports whether obj was created before cutoff, or true for a
+// zero cutoff.
+func createdBefore(obj metav1.Object, cutoff time.Time) bool {
+	return cutoff.IsZero() || obj.GetCreationTimestamp().Time.Before(cutoff)
+}
+
+// checkSelector rejects a selector that would change the managed-by label,
+// so that it cannot reach resources virtwork does not own.
+func checkSelector(selector map[string]string) error {
+	if v, ok := selector[constants.LabelManagedBy]; ok && v != constants.ManagedByValue {
+		return fmt.Errorf("selector cannot change %s: only %s=%s resources are cleaned up",
+			constants.LabelManagedBy, constants.LabelManagedBy, constants.ManagedByValue)
+	}
+	return nil
+}
+
+// deleteObjects deletes objs with up to concurrency deletions in flight and
+// returns how many were deleted. Failures other than NotFound are appended to
+// result.Errors in the order of objs, so the outcome does not depend on
+// scheduling.
+func deleteObjects(ctx context.Context, c client.Client, objs []client.Object, kind string, concurrency int, deleteOpts []client.DeleteOption, result *CleanupResult) int {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	// Each goroutine writes only its own slot, so no locking is needed.
+	errs := make([]error, len(objs))
+	deleted := make([]bool, len(objs))
+	var g errgroup.Group
+	g.SetLimit(concurrency)
+	for i, obj := range objs {
+		g.Go(func() error {
+			if err := c.Delete(ctx, obj, deleteOpts...); err != nil {
+				if !apierrors.IsNotFound(err) {
+					errs[i] = fmt.Errorf("deleting %s %s: %w", kind, obj.GetName(), err)
+				}
+				return nil
+			}
+			deleted[i] = true
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	count := 0
+	for i := range objs {
+		if errs[i] != nil {
+			result.Errors = append(result.Errors, errs[i])
+		}
+		if deleted[i] {
+			count++
+		}
+	}
+	return count
+}
+
 // collectRunID extracts the virtwork/run-id label from a resource's labels and adds it to the set.
 func collectRunID(labels map[string]string, set map[string]struct{}) {
 	if id, ok := labels[constants.LabelRunID]; ok && id != "" {
@@ -6,11 +6,16 @@ package cleanup_test
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -96,15 +101,15 @@ var _ = Describe("CleanupAll", func() {
 	It("should tolerate individual VM deletion errors", func() {
 		vm1 := newManagedVM("vm-1")
 		vm2 := newManagedVM("vm-2")
-		callCount := 0
+		// Deletions run concurrently, so count them atomically.
+		var callCount atomic.Int32
 		c := fake.NewClientBuilder().
 			WithScheme(scheme).
 			WithObjects(vm1, vm2).
 			WithInterceptorFuncs(interceptor.Funcs{
 				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
 					if _, ok := obj.(*kubevirtv1.VirtualMachine); ok {
-						callCount++
-						if callCount == 1 {
+						if callCount.Add(1) == 1 {
 							return fmt.Errorf("simulated delete error")
 						}
 					}
@@ -135,18 +140,38 @@ var _ = Describe("CleanupAll", func() {
 		Expect(svcList.Items).To(BeEmpty())
 	})
 
+	It("should delete network policies by managed-by label", func() {
+		np := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "virtwork-iperf3-server", Namespace: namespace, Labels: labels},
+		}
+		other := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: namespace},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(np, other).Build()
+
+		result, err := cleanup.CleanupAll(ctx, c, namespace, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.NetworkPoliciesDeleted).To(Equal(1))
+		Expect(result.Errors).To(BeEmpty())
+
+		npList := &networkingv1.NetworkPolicyList{}
+		Expect(c.List(ctx, npList, client.InNamespace(namespace))).To(Succeed())
+		Expect(npList.Items).To(HaveLen(1))
+		Expect(npList.Items[0].Name).To(Equal("other"))
+	})
+
 	It("should tolerate individual service deletion errors", func() {
 		svc1 := newManagedService("svc-1")
 		svc2 := newManagedService("svc-2")
-		callCount := 0
+		// Deletions run concurrently, so count them atomically.
+		var callCount atomic.Int32
 		c := fake.NewClientBuilder().
 			WithScheme(scheme).
 			WithObjects(svc1, svc2).
 			WithInterceptorFuncs(interceptor.Funcs{
 				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
 					if _, ok := obj.(*corev1.Service); ok {
-						callCount++
-						if callCount == 1 {
+						if callCount.Add(1) == 1 {
 							return fmt.Errorf("simulated service delete error")
 						}
 					}
@@ -176,18 +201,48 @@ var _ = Describe("CleanupAll", func() {
 		Expect(secretList.Items).To(BeEmpty())
 	})
 
+	It("should delete the api-churn RBAC objects and ConfigMaps by managed-by label", func() {
+		meta := func() metav1.ObjectMeta {
+			return metav1.ObjectMeta{Name: "virtwork-api-churn", Namespace: namespace, Labels: labels}
+		}
+		unmanaged := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "user-config", Namespace: namespace},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.ServiceAccount{ObjectMeta: meta()},
+			&rbacv1.Role{ObjectMeta: meta()},
+			&rbacv1.RoleBinding{ObjectMeta: meta(), RoleRef: rbacv1.RoleRef{Kind: "Role", Name: "virtwork-api-churn"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "virtwork-churn-vm-0", Namespace: namespace, Labels: labels}},
+			unmanaged,
+		).Build()
+
+		result, err := cleanup.CleanupAll(ctx, c, namespace, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RBACDeleted).To(Equal(3))
+		Expect(result.ConfigMapsDeleted).To(Equal(1))
+		Expect(result.Errors).To(BeEmpty())
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(unmanaged), &corev1.ConfigMap{})).To(Succeed())
+		saList := &corev1.ServiceAccountList{}
+		Expect(c.List(ctx, saList, client.InNamespace(namespace))).To(Succeed())
+		Expect(saList.Items).To(BeEmpty())
+		bindingList := &rbacv1.RoleBindingList{}
+		Expect(c.List(ctx, bindingList, client.InNamespace(namespace))).To(Succeed())
+		Expect(bindingList.Items).To(BeEmpty())
+	})
+
 	It("should tolerate individual secret deletion errors", func() {
 		sec1 := newManagedSecret("sec-1")
 		sec2 := newManagedSecret("sec-2")
-		callCount := 0
+		// Deletions run concurrently, so count them atomically.
+		var callCount atomic.Int32
 		c := fake.NewClientBuilder().
 			WithScheme(scheme).
 			WithObjects(sec1, sec2).
 			WithInterceptorFuncs(interceptor.Funcs{
 				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
 					if _, ok := obj.(*corev1.Secret); ok {
-						callCount++
-						if callCount == 1 {
+						if callCount.Add(1) == 1 {
 							return fmt.Errorf("simulated secret delete error")
 						}
 					}
@@ -333,4 +388,376 @@ var _ = Describe("CleanupAll", func() {
 		Expect(svcList.Items).To(HaveLen(1))
 		Expect(svcList.Items[0].Name).To(Equal("unmanaged-svc"))
 	})
+
+	Describe("delete options", func() {
+		capture := func(objs ...client.Object) (client.Client, map[string]*client.DeleteOptions) {
+			captured := map[string]*client.DeleteOptions{}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(objs...).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+						do := &client.DeleteOptions{}
+						do.ApplyOptions(opts)
+						captured[fmt.Sprintf("%T/%s", obj, obj.GetName())] = do
+						return cl.Delete(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			return c, captured
+		}
+
+		It("should apply grace period and propagation to VMs and secrets", func() {
+			c, captured := capture(newManagedVM("vm-1"), newManagedSecret("secret-1"), newManagedService("svc-1"))
+
+			opts, err := cleanup.DeleteOptions(0, "background")
+			Expect(err).NotTo(HaveOccurred())
+			result, err := cleanup.CleanupAll(ctx, c, namespace, false, "", opts...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.VMsDeleted).To(Equal(1))
+
+			for _, key := range []string{"*v1.VirtualMachine/vm-1", "*v1.Secret/secret-1"} {
+				Expect(captured).To(HaveKey(key))
+				Expect(captured[key].GracePeriodSeconds).To(HaveValue(BeEquivalentTo(0)))
+				Expect(captured[key].PropagationPolicy).To(HaveValue(Equal(metav1.DeletePropagationBackground)))
+			}
+
+			Expect(captured).To(HaveKey("*v1.Service/svc-1"))
+			Expect(captured["*v1.Service/svc-1"].GracePeriodSeconds).To(BeNil())
+			Expect(captured["*v1.Service/svc-1"].PropagationPolicy).To(BeNil())
+		})
+
+		It("should force-delete VMs and secrets with the force options", func() {
+			c, captured := capture(newManagedVM("vm-1"), newManagedSecret("secret-1"))
+
+			_, err := cleanup.CleanupAll(ctx, c, namespace, false, "", vm.ForceDeleteOptions().ClientOptions()...)
+			Expect(err).NotTo(HaveOccurred())
+			for _, key := range []string{"*v1.VirtualMachine/vm-1", "*v1.Secret/secret-1"} {
+				Expect(captured[key].GracePeriodSeconds).To(HaveValue(BeEquivalentTo(0)))
+				Expect(captured[key].PropagationPolicy).To(HaveValue(Equal(metav1.DeletePropagationBackground)))
+			}
+		})
+
+		It("should leave defaults unset when no options are given", func() {
+			c, captured := capture(newManagedVM("vm-1"))
+
+			opts, err := cleanup.DeleteOptions(-1, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(opts).To(BeEmpty())
+
+			_, err = cleanup.CleanupAll(ctx, c, namespace, false, "", opts...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(captured["*v1.VirtualMachine/vm-1"].GracePeriodSeconds).To(BeNil())
+			Expect(captured["*v1.VirtualMachine/vm-1"].PropagationPolicy).To(BeNil())
+		})
+
+		It("should accept each propagation policy", func() {
+			for _, p := range []metav1.DeletionPropagation{
+				metav1.DeletePropagationForeground,
+				metav1.DeletePropagationBackground,
+				metav1.DeletePropagationOrphan,
+			} {
+				opts, err := cleanup.DeleteOptions(-1, string(p))
+				Expect(err).NotTo(HaveOccurred())
+				do := &client.DeleteOptions{}
+				do.ApplyOptions(opts)
+				Expect(do.PropagationPolicy).To(HaveValue(Equal(p)))
+			}
+		})
+
+		It("should reject an unknown propagation policy", func() {
+			_, err := cleanup.DeleteOptions(-1, "Cascade")
+			Expect(err).To(MatchError(ContainSubstring("invalid propagation")))
+		})
+	})
+})
+
+var _ = Describe("CleanupAllConcurrent", func() {
+	var (
+		ctx       context.Context
+		scheme    = cluster.NewScheme()
+		namespace = "test-ns"
+		labels    = map[string]string{
+			constants.LabelManagedBy: constants.ManagedByValue,
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	managedVMs := func(n int) []client.Object {
+		objs := make([]client.Object, n)
+		for i := range objs {
+			objs[i] = vm.BuildVMSpec(vm.VMSpecOpts{
+				Name:               fmt.Sprintf("vm-%d", i),
+				Namespace:          namespace,
+				ContainerDiskImage: "test-image",
+				CloudInitUserdata:  "#cloud-config\n",
+				CPUCores:           1,
+				Memory:             "1Gi",
+				Labels:             labels,
+			})
+		}
+		return objs
+	}
+
+	// trackInFlight returns a client whose VM deletions take a moment, and a
+	// function reporting the most deletions that were ever in flight at once.
+	trackInFlight := func(objs []client.Object) (client.Client, func() int32) {
+		var mu sync.Mutex
+		var inFlight, peak int32
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					mu.Lock()
+					inFlight++
+					peak = max(peak, inFlight)
+					mu.Unlock()
+					time.Sleep(10 * time.Millisecond)
+					mu.Lock()
+					inFlight--
+					mu.Unlock()
+					return cl.Delete(ctx, obj, opts...)
+				},
+			}).
+			Build()
+		return c, func() int32 {
+			mu.Lock()
+			defer mu.Unlock()
+			return peak
+		}
+	}
+
+	It("should run deletions concurrently up to the limit", func() {
+		c, peak := trackInFlight(managedVMs(12))
+
+		result, err := cleanup.CleanupAllConcurrent(ctx, c, namespace, false, "", nil, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.VMsDeleted).To(Equal(12))
+		Expect(result.Errors).To(BeEmpty())
+		Expect(peak()).To(BeNumerically(">", 1))
+		Expect(peak()).To(BeNumerically("<=", 4))
+	})
+
+	It("should delete one at a time with a concurrency below 1", func() {
+		c, peak := trackInFlight(managedVMs(3))
+
+		result, err := cleanup.CleanupAllConcurrent(ctx, c, namespace, false, "", nil, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.VMsDeleted).To(Equal(3))
+		Expect(peak()).To(Equal(int32(1)))
+	})
+
+	It("should count deletions and collect every error under concurrency", func() {
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(managedVMs(20)...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					var i int
+					fmt.Sscanf(obj.GetName(), "vm-%d", &i)
+					if i%4 == 0 {
+						return fmt.Errorf("simulated delete error")
+					}
+					return cl.Delete(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		result, err := cleanup.CleanupAllConcurrent(ctx, c, namespace, false, "", nil, 8)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.VMsDeleted).To(Equal(15))
+		Expect(result.Errors).To(HaveLen(5))
+		for _, e := range result.Errors {
+			Expect(e.Error()).To(HavePrefix("deleting VM vm-"))
+		}
+	})
+
+	It("should delete only the VMs matching the selector", func() {
+		withComponent := func(name, component string) client.Object {
+			return vm.BuildVMSpec(vm.VMSpecOpts{
+				Name:               name,
+				Namespace:          namespace,
+				ContainerDiskImage: "test-image",
+				CloudInitUserdata:  "#cloud-config\n",
+				CPUCores:           1,
+				Memory:             "1Gi",
+				Labels: map[string]string{
+					constants.LabelManagedBy: constants.ManagedByValue,
+					constants.LabelComponent: component,
+				},
+			})
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			withComponent("virtwork-cpu-0", "cpu"),
+			withComponent("virtwork-cpu-1", "cpu"),
+			withComponent("virtwork-disk-0", "disk"),
+		).Build()
+
+		result, err := cleanup.CleanupAllConcurrent(ctx, c, namespace, false, "",
+			map[string]string{constants.LabelComponent: "cpu"}, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.VMsDeleted).To(Equal(2))
+
+		vmList := &kubevirtv1.VirtualMachineList{}
+		Expect(c.List(ctx, vmList, client.InNamespace(namespace))).To(Succeed())
+		Expect(vmList.Items).To(HaveLen(1))
+		Expect(vmList.Items[0].Name).To(Equal("virtwork-disk-0"))
+	})
+
+	It("should still require the managed-by label with a selector", func() {
+		unmanaged := &kubevirtv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other-cpu",
+				Namespace: namespace,
+				Labels:    map[string]string{constants.LabelComponent: "cpu"},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(unmanaged).Build()
+
+		result, err := cleanup.CleanupAllConcurrent(ctx, c, namespace, false, "",
+			map[string]string{constants.LabelComponent: "cpu"}, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.VMsDeleted).To(Equal(0))
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(unmanaged), &kubevirtv1.VirtualMachine{})).To(Succeed())
+	})
+
+	It("should reject a selector that overrides the managed-by label", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		_, err := cleanup.CleanupAllConcurrent(ctx, c, namespace, false, "",
+			map[string]string{constants.LabelManagedBy: "someone-else"}, 4)
+		Expect(err).To(MatchError(ContainSubstring("selector cannot change")))
+	})
+})
+
+var _ = Describe("CleanupOlderThan", func() {
+	var (
+		ctx       context.Context
+		scheme    = cluster.NewScheme()
+		namespace = "test-ns"
+		now       = time.Now()
+		cutoff    = now.Add(-24 * time.Hour)
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	// meta returns the metadata of a resource from run runID created age ago.
+	meta := func(name, runID string, age time.Duration) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			Labels: map[string]string{
+				constants.LabelManagedBy: constants.ManagedByValue,
+				constants.LabelRunID:     runID,
+				constants.LabelComponent: "cpu",
+			},
+		}
+	}
+
+	newVM := func(name, runID string, age time.Duration) *kubevirtv1.VirtualMachine {
+		return &kubevirtv1.VirtualMachine{ObjectMeta: meta(name, runID, age)}
+	}
+
+	fixtures := func() []client.Object {
+		return []client.Object{
+			newVM("virtwork-cpu-0", "run-old", 48*time.Hour),
+			newVM("virtwork-cpu-1", "run-new", time.Hour),
+			&corev1.Secret{ObjectMeta: meta("virtwork-cpu-0-cloudinit", "run-old", 48*time.Hour)},
+			&corev1.Secret{ObjectMeta: meta("virtwork-cpu-1-cloudinit", "run-new", time.Hour)},
+			&corev1.Service{ObjectMeta: meta("virtwork-old-svc", "run-old", 30*time.Hour)},
+			&corev1.Service{ObjectMeta: meta("virtwork-new-svc", "run-new", 23*time.Hour)},
+		}
+	}
+
+	It("should delete only the resources created before the cutoff", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(fixtures()...).Build()
+
+		result, err := cleanup.CleanupOlderThan(ctx, c, namespace, "", nil, cutoff, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.VMsDeleted).To(Equal(1))
+		Expect(result.SecretsDeleted).To(Equal(1))
+		Expect(result.ServicesDeleted).To(Equal(1))
+		Expect(result.RunIDs).To(ConsistOf("run-old"))
+
+		vms := &kubevirtv1.VirtualMachineList{}
+		Expect(c.List(ctx, vms, client.InNamespace(namespace))).To(Succeed())
+		Expect(vms.Items).To(HaveLen(1))
+		Expect(vms.Items[0].Name).To(Equal("virtwork-cpu-1"))
+
+		secrets := &corev1.SecretList{}
+		Expect(c.List(ctx, secrets, client.InNamespace(namespace))).To(Succeed())
+		Expect(secrets.Items).To(HaveLen(1))
+		Expect(secrets.Items[0].Name).To(Equal("virtwork-cpu-1-cloudinit"))
+
+		svcs := &corev1.ServiceList{}
+		Expect(c.List(ctx, svcs, client.InNamespace(namespace))).To(Succeed())
+		Expect(svcs.Items).To(HaveLen(1))
+		Expect(svcs.Items[0].Name).To(Equal("virtwork-new-svc"))
+	})
+
+	It("should combine the cutoff with a run-id filter", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(fixtures()...).Build()
+
+		result, err := cleanup.CleanupOlderThan(ctx, c, namespace, "run-new", nil, cutoff, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.VMsDeleted).To(Equal(0))
+		Expect(result.SecretsDeleted).To(Equal(0))
+		Expect(result.ServicesDeleted).To(Equal(0))
+	})
+
+	It("should combine the cutoff with a selector", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(fixtures()...).Build()
+
+		result, err := cleanup.CleanupOlderThan(ctx, c, namespace, "",
+			map[string]string{constants.LabelComponent: "disk"}, cutoff, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.VMsDeleted).To(Equal(0))
+
+		result, err = cleanup.CleanupOlderThan(ctx, c, namespace, "",
+			map[string]string{constants.LabelComponent: "cpu"}, cutoff, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.VMsDeleted).To(Equal(1))
+	})
+
+	It("should never delete the namespace", func() {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns).Build()
+
+		result, err := cleanup.CleanupOlderThan(ctx, c, namespace, "", nil, cutoff, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.NamespaceDeleted).To(BeFalse())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(ns), &corev1.Namespace{})).To(Succeed())
+	})
+})
+
+var _ = Describe("CleanupResult", func() {
+	It("should add the results of another namespace", func() {
+		result := &cleanup.CleanupResult{VMsDeleted: 2, SecretsDeleted: 2, RunIDs: []string{"run-a"}}
+		result.Add(&cleanup.CleanupResult{
+			VMsDeleted:             1,
+			ServicesDeleted:        1,
+			SecretsDeleted:         1,
+			RBACDeleted:            3,
+			NetworkPoliciesDeleted: 1,
+			NamespaceDeleted:       true,
+			Errors:                 []error{fmt.Errorf("deleting VM x: boom")},
+			RunIDs:                 []string{"run-a", "run-b"},
+		})
+
+		Expect(result.VMsDeleted).To(Equal(3))
+		Expect(result.ServicesDeleted).To(Equal(1))
+		Expect(result.SecretsDeleted).To(Equal(3))
+		Expect(result.RBACDeleted).To(Equal(3))
+		Expect(result.NetworkPoliciesDeleted).To(Equal(1))
+		Expect(result.NamespaceDeleted).To(BeTrue())
+		Expect(result.Errors).To(HaveLen(1))
+		Expect(result.RunIDs).To(Equal([]string{"run-a", "run-b"}))
+	})
 })
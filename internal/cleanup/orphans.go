@@ -0,0 +1,126 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/opdev/virtwork/internal/constants"
+)
+
+// cloudInitSuffix ends the name of the cloud-init secret run creates for each
+// VM: <vm-name>-cloudinit.
+const cloudInitSuffix = "-cloudinit"
+
+// OrphanReport lists managed resources left without the VMs they belong to,
+// typically after a run crashed part way through.
+type OrphanReport struct {
+	// Secrets are cloud-init secrets whose VM does not exist.
+	Secrets []string
+	// Services are services whose selector matches no managed VM.
+	Services []string
+}
+
+// Empty reports whether no orphans were found.
+func (r *OrphanReport) Empty() bool {
+	return len(r.Secrets) == 0 && len(r.Services) == 0
+}
+
+// FindOrphans cross-references the virtwork-managed VMs, secrets, and
+// services in namespace. A cloud-init secret is orphaned when no VM carries
+// the name before its -cloudinit suffix; other secrets are not checked. A
+// service is orphaned when its selector matches the template labels of no
+// VM, so it has no server to send traffic to; services without a selector
+// are not checked. Both lists are sorted by name.
+func FindOrphans(ctx context.Context, c client.Client, namespace string) (*OrphanReport, error) {
+	listOpts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels{constants.LabelManagedBy: constants.ManagedByValue},
+	}
+
+	vmList := &kubevirtv1.VirtualMachineList{}
+	if err := c.List(ctx, vmList, listOpts...); err != nil {
+		return nil, fmt.Errorf("listing VMs in %s: %w", namespace, err)
+	}
+	vmNames := make(map[string]bool, len(vmList.Items))
+	var templateLabels []labels.Set
+	for _, v := range vmList.Items {
+		vmNames[v.Name] = true
+		if v.Spec.Template != nil {
+			templateLabels = append(templateLabels, labels.Set(v.Spec.Template.ObjectMeta.Labels))
+		}
+	}
+
+	report := &OrphanReport{}
+
+	secretList := &corev1.SecretList{}
+	if err := c.List(ctx, secretList, listOpts...); err != nil {
+		return nil, fmt.Errorf("listing secrets in %s: %w", namespace, err)
+	}
+	for _, s := range secretList.Items {
+		vmName, ok := strings.CutSuffix(s.Name, cloudInitSuffix)
+		if ok && !vmNames[vmName] {
+			report.Secrets = append(report.Secrets, s.Name)
+		}
+	}
+
+	svcList := &corev1.ServiceList{}
+	if err := c.List(ctx, svcList, listOpts...); err != nil {
+		return nil, fmt.Errorf("listing services in %s: %w", namespace, err)
+	}
+	for _, svc := range svcList.Items {
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		backed := false
+		for _, l := range templateLabels {
+			if selector.Matches(l) {
+				backed = true
+				break
+			}
+		}
+		if !backed {
+			report.Services = append(report.Services, svc.Name)
+		}
+	}
+
+	sort.Strings(report.Secrets)
+	sort.Strings(report.Services)
+	return report, nil
+}
+
+// DeleteOrphans deletes the secrets and services in report, with up to
+// concurrency deletions of each kind in flight. As with CleanupAllConcurrent,
+// deleteOpts apply to the secrets, NotFound is not an error, and other
+// failures are collected in the result rather than aborting.
+func DeleteOrphans(ctx context.Context, c client.Client, namespace string, report *OrphanReport, concurrency int, deleteOpts ...client.DeleteOption) *CleanupResult {
+	result := &CleanupResult{}
+
+	svcs := make([]client.Object, len(report.Services))
+	for i, name := range report.Services {
+		svcs[i] = &corev1.Service{}
+		svcs[i].SetName(name)
+		svcs[i].SetNamespace(namespace)
+	}
+	result.ServicesDeleted = deleteObjects(ctx, c, svcs, "service", concurrency, nil, result)
+
+	secrets := make([]client.Object, len(report.Secrets))
+	for i, name := range report.Secrets {
+		secrets[i] = &corev1.Secret{}
+		secrets[i].SetName(name)
+		secrets[i].SetNamespace(namespace)
+	}
+	result.SecretsDeleted = deleteObjects(ctx, c, secrets, "secret", concurrency, deleteOpts, result)
+
+	return result
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cleanup_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/cleanup"
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+var _ = Describe("FindOrphans", func() {
+	var (
+		ctx       context.Context
+		scheme    = cluster.NewScheme()
+		namespace = "test-ns"
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	newVM := func(name, component, role string) *kubevirtv1.VirtualMachine {
+		labels := map[string]string{
+			constants.LabelManagedBy: constants.ManagedByValue,
+			constants.LabelComponent: component,
+		}
+		if role != "" {
+			labels[constants.LabelRole] = role
+		}
+		return vm.BuildVMSpec(vm.VMSpecOpts{
+			Name:               name,
+			Namespace:          namespace,
+			ContainerDiskImage: "test-image",
+			CloudInitUserdata:  "#cloud-config\n",
+			CPUCores:           1,
+			Memory:             "1Gi",
+			Labels:             labels,
+		})
+	}
+
+	newSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{constants.LabelManagedBy: constants.ManagedByValue},
+			},
+		}
+	}
+
+	newService := func(name string, selector map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{constants.LabelManagedBy: constants.ManagedByValue},
+			},
+			Spec: corev1.ServiceSpec{Selector: selector},
+		}
+	}
+
+	serverSelector := func(component string) map[string]string {
+		return map[string]string{
+			constants.LabelComponent: component,
+			constants.LabelRole:      "server",
+		}
+	}
+
+	It("should report cloud-init secrets whose VM is gone", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newVM("virtwork-cpu-0", "cpu", ""),
+			newSecret("virtwork-cpu-0-cloudinit"),
+			newSecret("virtwork-cpu-1-cloudinit"),
+			newSecret("virtwork-memory-0-cloudinit"),
+			newSecret("virtwork-ssh-keys"),
+		).Build()
+
+		report, err := cleanup.FindOrphans(ctx, c, namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Secrets).To(Equal([]string{"virtwork-cpu-1-cloudinit", "virtwork-memory-0-cloudinit"}))
+		Expect(report.Services).To(BeEmpty())
+	})
+
+	It("should report services that select no VM", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newVM("virtwork-network-server-0", "network", "server"),
+			newVM("virtwork-web-client-0", "web", "client"),
+			newService("virtwork-iperf3-server", serverSelector("network")),
+			newService("virtwork-nginx-server", serverSelector("web")),
+			newService("virtwork-headless", nil),
+		).Build()
+
+		report, err := cleanup.FindOrphans(ctx, c, namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Services).To(Equal([]string{"virtwork-nginx-server"}))
+		Expect(report.Empty()).To(BeFalse())
+	})
+
+	It("should ignore resources virtwork does not manage", func() {
+		unmanaged := newSecret("other-cloudinit")
+		unmanaged.Labels = nil
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(unmanaged).Build()
+
+		report, err := cleanup.FindOrphans(ctx, c, namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Empty()).To(BeTrue())
+	})
+
+	It("should delete only the reported orphans", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newVM("virtwork-cpu-0", "cpu", ""),
+			newSecret("virtwork-cpu-0-cloudinit"),
+			newSecret("virtwork-cpu-1-cloudinit"),
+			newService("virtwork-nginx-server", serverSelector("web")),
+		).Build()
+
+		report, err := cleanup.FindOrphans(ctx, c, namespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		result := cleanup.DeleteOrphans(ctx, c, namespace, report, 2)
+		Expect(result.Errors).To(BeEmpty())
+		Expect(result.SecretsDeleted).To(Equal(1))
+		Expect(result.ServicesDeleted).To(Equal(1))
+
+		err = c.Get(ctx, client.ObjectKey{Name: "virtwork-cpu-1-cloudinit", Namespace: namespace}, &corev1.Secret{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "virtwork-cpu-0-cloudinit", Namespace: namespace}, &corev1.Secret{})).To(Succeed())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "virtwork-cpu-0", Namespace: namespace}, &kubevirtv1.VirtualMachine{})).To(Succeed())
+
+		report, err = cleanup.FindOrphans(ctx, c, namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Empty()).To(BeTrue())
+	})
+})
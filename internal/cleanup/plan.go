@@ -0,0 +1,100 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PlanCleanup reports what CleanupAllConcurrent would delete for the same
+// namespace, deleteNamespace, runID, and selector, without deleting anything.
+// The counts and run IDs of the result are those of the matching resources,
+// NamespaceDeleted reports whether deleteNamespace is set and the namespace
+// exists, and Planned lists every matching resource.
+func PlanCleanup(ctx context.Context, c client.Client, namespace string, deleteNamespace bool, runID string, selector map[string]string) (*CleanupResult, error) {
+	return planMatching(ctx, c, namespace, deleteNamespace, runID, selector, time.Time{})
+}
+
+// PlanCleanupOlderThan reports what CleanupOlderThan would delete for the
+// same namespace, runID, selector, and cutoff, without deleting anything.
+func PlanCleanupOlderThan(ctx context.Context, c client.Client, namespace, runID string, selector map[string]string, cutoff time.Time) (*CleanupResult, error) {
+	return planMatching(ctx, c, namespace, false, runID, selector, cutoff)
+}
+
+// planMatching implements PlanCleanup and PlanCleanupOlderThan. A zero cutoff
+// matches resources of any age.
+func planMatching(ctx context.Context, c client.Client, namespace string, deleteNamespace bool, runID string, selector map[string]string, cutoff time.Time) (*CleanupResult, error) {
+	result := &CleanupResult{}
+	if err := checkSelector(selector); err != nil {
+		return result, err
+	}
+
+	listOpts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(managedLabels(runID, selector)),
+	}
+	// Kinds in deletion order, with the count each adds to. ConfigMaps are
+	// created by guests rather than by a run, so they do not link run IDs.
+	lists := []struct {
+		kind   string
+		list   client.ObjectList
+		count  *int
+		linked bool
+	}{
+		{"VM", &kubevirtv1.VirtualMachineList{}, &result.VMsDeleted, true},
+		{"Service", &corev1.ServiceList{}, &result.ServicesDeleted, true},
+		{"NetworkPolicy", &networkingv1.NetworkPolicyList{}, &result.NetworkPoliciesDeleted, true},
+		{"Secret", &corev1.SecretList{}, &result.SecretsDeleted, true},
+		{"ConfigMap", &corev1.ConfigMapList{}, &result.ConfigMapsDeleted, false},
+		{"RoleBinding", &rbacv1.RoleBindingList{}, &result.RBACDeleted, true},
+		{"Role", &rbacv1.RoleList{}, &result.RBACDeleted, true},
+		{"ServiceAccount", &corev1.ServiceAccountList{}, &result.RBACDeleted, true},
+	}
+
+	runIDSet := make(map[string]struct{})
+	for _, l := range lists {
+		if err := c.List(ctx, l.list, listOpts...); err != nil {
+			return result, fmt.Errorf("listing %ss in %s: %w", l.kind, namespace, err)
+		}
+		objs, err := listedObjects(l.list)
+		if err != nil {
+			return result, err
+		}
+		for _, obj := range objs {
+			if !createdBefore(obj, cutoff) {
+				continue
+			}
+			if l.linked {
+				collectRunID(obj.GetLabels(), runIDSet)
+			}
+			result.Planned = append(result.Planned, l.kind+"/"+obj.GetName())
+			*l.count++
+		}
+	}
+	sort.Strings(result.Planned)
+
+	for id := range runIDSet {
+		result.RunIDs = append(result.RunIDs, id)
+	}
+	sort.Strings(result.RunIDs)
+
+	if deleteNamespace {
+		err := c.Get(ctx, client.ObjectKey{Name: namespace}, &corev1.Namespace{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return result, fmt.Errorf("getting namespace %s: %w", namespace, err)
+		}
+		result.NamespaceDeleted = err == nil
+	}
+	return result, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cleanup_test
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/opdev/virtwork/internal/cleanup"
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+var _ = Describe("PlanCleanup", func() {
+	var (
+		ctx       context.Context
+		scheme    = cluster.NewScheme()
+		namespace = "test-ns"
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	managed := func(runID string) map[string]string {
+		return map[string]string{
+			constants.LabelManagedBy: constants.ManagedByValue,
+			constants.LabelRunID:     runID,
+		}
+	}
+
+	newVM := func(name, runID string) *kubevirtv1.VirtualMachine {
+		return vm.BuildVMSpec(vm.VMSpecOpts{
+			Name:               name,
+			Namespace:          namespace,
+			ContainerDiskImage: "test-image",
+			CloudInitUserdata:  "#cloud-config\n",
+			CPUCores:           1,
+			Memory:             "1Gi",
+			Labels:             managed(runID),
+		})
+	}
+
+	meta := func(name, runID string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: managed(runID)}
+	}
+
+	// newClient counts the deletions made through it in deletes.
+	newClient := func(deletes *atomic.Int32, objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					deletes.Add(1)
+					return cl.Delete(ctx, obj, opts...)
+				},
+			}).
+			Build()
+	}
+
+	fixtures := func() []client.Object {
+		return []client.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}},
+			newVM("virtwork-cpu-0", "run-a"),
+			newVM("virtwork-cpu-1", "run-a"),
+			newVM("virtwork-memory-0", "run-b"),
+			&corev1.Service{ObjectMeta: meta("virtwork-network-server", "run-a")},
+			&corev1.Secret{ObjectMeta: meta("virtwork-cpu-0-cloudinit", "run-a")},
+			&corev1.Secret{ObjectMeta: meta("virtwork-memory-0-cloudinit", "run-b")},
+			&corev1.ServiceAccount{ObjectMeta: meta("virtwork-api-churn", "run-a")},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: namespace}},
+		}
+	}
+
+	It("should count what cleanup would delete without deleting it", func() {
+		var deletes atomic.Int32
+		c := newClient(&deletes, fixtures()...)
+
+		result, err := cleanup.PlanCleanup(ctx, c, namespace, true, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletes.Load()).To(BeZero())
+
+		Expect(result.VMsDeleted).To(Equal(3))
+		Expect(result.ServicesDeleted).To(Equal(1))
+		Expect(result.SecretsDeleted).To(Equal(2))
+		Expect(result.RBACDeleted).To(Equal(1))
+		Expect(result.NamespaceDeleted).To(BeTrue())
+		Expect(result.RunIDs).To(Equal([]string{"run-a", "run-b"}))
+		Expect(result.Planned).To(Equal([]string{
+			"Secret/virtwork-cpu-0-cloudinit",
+			"Secret/virtwork-memory-0-cloudinit",
+			"Service/virtwork-network-server",
+			"ServiceAccount/virtwork-api-churn",
+			"VM/virtwork-cpu-0",
+			"VM/virtwork-cpu-1",
+			"VM/virtwork-memory-0",
+		}))
+
+		vms := &kubevirtv1.VirtualMachineList{}
+		Expect(c.List(ctx, vms)).To(Succeed())
+		Expect(vms.Items).To(HaveLen(3))
+	})
+
+	It("should match the counts of the cleanup it plans", func() {
+		var deletes atomic.Int32
+		c := newClient(&deletes, fixtures()...)
+
+		plan, err := cleanup.PlanCleanup(ctx, c, namespace, false, "run-a", nil)
+		Expect(err).NotTo(HaveOccurred())
+		result, err := cleanup.CleanupAll(ctx, c, namespace, false, "run-a")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(plan.VMsDeleted).To(Equal(result.VMsDeleted))
+		Expect(plan.ServicesDeleted).To(Equal(result.ServicesDeleted))
+		Expect(plan.SecretsDeleted).To(Equal(result.SecretsDeleted))
+		Expect(plan.RBACDeleted).To(Equal(result.RBACDeleted))
+		Expect(plan.NamespaceDeleted).To(BeFalse())
+		Expect(plan.VMsDeleted).To(Equal(2))
+		Expect(deletes.Load()).To(BeNumerically("==", len(plan.Planned)))
+	})
+
+	It("should not plan to delete a namespace that does not exist", func() {
+		var deletes atomic.Int32
+		c := newClient(&deletes, newVM("virtwork-cpu-0", "run-a"))
+
+		result, err := cleanup.PlanCleanup(ctx, c, namespace, true, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.NamespaceDeleted).To(BeFalse())
+		Expect(result.VMsDeleted).To(Equal(1))
+	})
+
+	It("should plan only the resources created before the cutoff", func() {
+		objs := fixtures()
+		old := metav1.NewTime(time.Now().Add(-48 * time.Hour))
+		for _, obj := range objs {
+			obj.SetCreationTimestamp(metav1.Now())
+			if obj.GetName() == "virtwork-cpu-0" || obj.GetName() == "virtwork-cpu-0-cloudinit" {
+				obj.SetCreationTimestamp(old)
+			}
+		}
+		var deletes atomic.Int32
+		c := newClient(&deletes, objs...)
+
+		result, err := cleanup.PlanCleanupOlderThan(ctx, c, namespace, "", nil, time.Now().Add(-24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletes.Load()).To(BeZero())
+		Expect(result.VMsDeleted).To(Equal(1))
+		Expect(result.SecretsDeleted).To(Equal(1))
+		Expect(result.NamespaceDeleted).To(BeFalse())
+		Expect(result.RunIDs).To(Equal([]string{"run-a"}))
+		Expect(result.Planned).To(Equal([]string{
+			"Secret/virtwork-cpu-0-cloudinit",
+			"VM/virtwork-cpu-0",
+		}))
+	})
+
+	It("should reject a selector that changes the managed-by label", func() {
+		var deletes atomic.Int32
+		c := newClient(&deletes)
+
+		_, err := cleanup.PlanCleanup(ctx, c, namespace, false, "",
+			map[string]string{constants.LabelManagedBy: "someone-else"})
+		Expect(err).To(MatchError(ContainSubstring("selector cannot change")))
+	})
+})
@@ -0,0 +1,133 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/opdev/virtwork/internal/constants"
+)
+
+// Remaining lists the managed resources that CleanupAllConcurrent would
+// delete for the same runID and selector and that still exist, as sorted
+// "kind/name" entries. Resources that are terminating but not yet gone are
+// included.
+func Remaining(ctx context.Context, c client.Client, namespace, runID string, selector map[string]string) ([]string, error) {
+	listOpts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(managedLabels(runID, selector)),
+	}
+	lists := []struct {
+		kind string
+		list client.ObjectList
+	}{
+		{"VM", &kubevirtv1.VirtualMachineList{}},
+		{"Service", &corev1.ServiceList{}},
+		{"NetworkPolicy", &networkingv1.NetworkPolicyList{}},
+		{"Secret", &corev1.SecretList{}},
+		{"ConfigMap", &corev1.ConfigMapList{}},
+		{"RoleBinding", &rbacv1.RoleBindingList{}},
+		{"Role", &rbacv1.RoleList{}},
+		{"ServiceAccount", &corev1.ServiceAccountList{}},
+	}
+
+	var remaining []string
+	for _, l := range lists {
+		if err := c.List(ctx, l.list, listOpts...); err != nil {
+			return nil, fmt.Errorf("listing %ss in %s: %w", l.kind, namespace, err)
+		}
+		names, err := objectNames(l.list)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			remaining = append(remaining, l.kind+"/"+name)
+		}
+	}
+	sort.Strings(remaining)
+	return remaining, nil
+}
+
+// VerifyComplete re-lists the resources cleanup targeted until none remain,
+// polling every interval for up to timeout; a zero timeout checks once. It
+// returns an error naming every resource still present when time runs out.
+func VerifyComplete(ctx context.Context, c client.Client, namespace, runID string, selector map[string]string, timeout, interval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		remaining, err := Remaining(ctx, c, namespace, runID, selector)
+		if err != nil {
+			return fmt.Errorf("verifying cleanup: %w", err)
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("cleanup incomplete: %d resources remain in %s after %s: %s",
+				len(remaining), namespace, timeout, strings.Join(remaining, ", "))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled verifying cleanup in %s: %w", namespace, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// managedLabels returns the labels a resource must carry to be cleaned up:
+// the managed-by label, the run ID when set, and any selector labels.
+func managedLabels(runID string, selector map[string]string) map[string]string {
+	labels := make(map[string]string, len(selector)+2)
+	for k, v := range selector {
+		labels[k] = v
+	}
+	labels[constants.LabelManagedBy] = constants.ManagedByValue
+	if runID != "" {
+		labels[constants.LabelRunID] = runID
+	}
+	return labels
+}
+
+// objectNames returns the names of the items in list.
+func objectNames(list client.ObjectList) ([]string, error) {
+	objs, err := listedObjects(list)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		names = append(names, obj.GetName())
+	}
+	return names, nil
+}
+
+// listedObjects returns the metadata of the items in list.
+func listedObjects(list client.ObjectList) ([]metav1.Object, error) {
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("reading %T: %w", list, err)
+	}
+	objs := make([]metav1.Object, 0, len(items))
+	for _, item := range items {
+		obj, err := apimeta.Accessor(item)
+		if err != nil {
+			return nil, fmt.Errorf("reading %T: %w", list, err)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
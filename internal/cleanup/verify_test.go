@@ -0,0 +1,146 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cleanup_test
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/opdev/virtwork/internal/cleanup"
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+var _ = Describe("VerifyComplete", func() {
+	var (
+		ctx       context.Context
+		scheme    = cluster.NewScheme()
+		namespace = "test-ns"
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	newVM := func(name, runID string) *kubevirtv1.VirtualMachine {
+		return vm.BuildVMSpec(vm.VMSpecOpts{
+			Name:               name,
+			Namespace:          namespace,
+			ContainerDiskImage: "test-image",
+			CloudInitUserdata:  "#cloud-config\n",
+			CPUCores:           1,
+			Memory:             "1Gi",
+			Labels: map[string]string{
+				constants.LabelManagedBy: constants.ManagedByValue,
+				constants.LabelRunID:     runID,
+			},
+		})
+	}
+
+	newSecret := func(name, runID string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					constants.LabelManagedBy: constants.ManagedByValue,
+					constants.LabelRunID:     runID,
+				},
+			},
+		}
+	}
+
+	// leaveStraggler accepts the deletion of the named object without
+	// removing it, as when a finalizer holds a VM back.
+	leaveStraggler := func(name string) interceptor.Funcs {
+		return interceptor.Funcs{
+			Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				if obj.GetName() == name {
+					return nil
+				}
+				return cl.Delete(ctx, obj, opts...)
+			},
+		}
+	}
+
+	It("should succeed when cleanup removed everything", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newVM("virtwork-cpu-0", "run-1"),
+			newSecret("virtwork-cpu-0-cloudinit", "run-1"),
+		).Build()
+
+		_, err := cleanup.CleanupAll(ctx, c, namespace, false, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cleanup.VerifyComplete(ctx, c, namespace, "", nil, 0, time.Millisecond)).To(Succeed())
+	})
+
+	It("should fail listing the resources a deletion left behind", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newVM("virtwork-cpu-0", "run-1"),
+			newVM("virtwork-cpu-1", "run-1"),
+			newSecret("virtwork-cpu-1-cloudinit", "run-1"),
+		).WithInterceptorFuncs(leaveStraggler("virtwork-cpu-1")).Build()
+
+		result, err := cleanup.CleanupAll(ctx, c, namespace, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Errors).To(BeEmpty())
+
+		err = cleanup.VerifyComplete(ctx, c, namespace, "", nil, 20*time.Millisecond, 5*time.Millisecond)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("1 resources remain"))
+		Expect(err.Error()).To(ContainSubstring("VM/virtwork-cpu-1"))
+		Expect(err.Error()).NotTo(ContainSubstring("virtwork-cpu-0"))
+	})
+
+	It("should succeed once a straggler disappears within the timeout", func() {
+		straggler := newVM("virtwork-cpu-0", "run-1")
+		var vmLists atomic.Int32
+		funcs := leaveStraggler("virtwork-cpu-0")
+		funcs.List = func(ctx context.Context, cl client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			// The straggler's finalizer completes after the first re-list
+			if _, ok := list.(*kubevirtv1.VirtualMachineList); ok && vmLists.Add(1) == 3 {
+				if err := cl.Delete(ctx, straggler); err != nil {
+					return err
+				}
+			}
+			return cl.List(ctx, list, opts...)
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(straggler).
+			WithInterceptorFuncs(funcs).Build()
+
+		_, err := cleanup.CleanupAll(ctx, c, namespace, false, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cleanup.VerifyComplete(ctx, c, namespace, "", nil, time.Minute, time.Millisecond)).To(Succeed())
+		Expect(vmLists.Load()).To(Equal(int32(3)))
+	})
+
+	It("should only check the run it cleaned up", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newVM("virtwork-cpu-0", "run-1"),
+			newVM("virtwork-cpu-1", "run-2"),
+		).Build()
+
+		_, err := cleanup.CleanupAll(ctx, c, namespace, false, "run-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cleanup.VerifyComplete(ctx, c, namespace, "run-1", nil, 0, time.Millisecond)).To(Succeed())
+		remaining, err := cleanup.Remaining(ctx, c, namespace, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(Equal([]string{"VM/virtwork-cpu-1"}))
+	})
+})
@@ -4,9 +4,18 @@
 package cloudinit
 
 import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+
 	"gopkg.in/yaml.v3"
 )
 
+// CompletionSentinel is the final_message workloads emit by default. cloud-init
+// prints it to the console and its output log once every module has run, so
+// finding it marks the guest as fully provisioned.
+const CompletionSentinel = "VIRTWORK_CLOUD_INIT_COMPLETE"
+
 // WriteFile represents a file to be written by cloud-init.
 type WriteFile struct {
 	Path        string `yaml:"path"`
@@ -14,15 +23,53 @@ type WriteFile struct {
 	Permissions string `yaml:"permissions"`
 }
 
+// DiskSetup describes how cloud-init partitions a disk. Layout true creates
+// a single partition spanning the disk; false leaves the disk unpartitioned.
+type DiskSetup struct {
+	TableType string `yaml:"table_type,omitempty"`
+	Layout    bool   `yaml:"layout"`
+	Overwrite bool   `yaml:"overwrite"`
+}
+
+// FSSetup describes a filesystem cloud-init creates. Partition "none" formats
+// the whole device. Without Overwrite, a device that already holds a
+// filesystem is left untouched.
+type FSSetup struct {
+	Label      string `yaml:"label,omitempty"`
+	Filesystem string `yaml:"filesystem"`
+	Device     string `yaml:"device"`
+	Partition  string `yaml:"partition,omitempty"`
+	Overwrite  bool   `yaml:"overwrite,omitempty"`
+}
+
+// PowerState asks cloud-init to reboot or power off the guest once every
+// module has run, for changes such as kernel arguments that only take effect
+// on the next boot. Mode is "reboot", "poweroff", or "halt".
+type PowerState struct {
+	Mode    string `yaml:"mode"`
+	Message string `yaml:"message,omitempty"`
+}
+
 // CloudConfigOpts holds the options for building a cloud-config YAML document.
+// BootCmd runs on every boot, early and before any other module. DiskSetup
+// (keyed by device), FSSetup, and Mounts are applied before packages are
+// installed; each Mounts entry follows the fstab field order: device, mount
+// point, filesystem type, options, dump, pass. PowerState, when set, takes
+// effect after the final message is printed.
 type CloudConfigOpts struct {
 	Packages          []string
 	WriteFiles        []WriteFile
 	RunCmd            [][]string
+	BootCmd           [][]string
+	DiskSetup         map[string]DiskSetup
+	FSSetup           []FSSetup
+	Mounts            [][]string
 	Extra             map[string]interface{}
 	SSHUser           string
 	SSHPassword       string
 	SSHAuthorizedKeys []string
+	FinalMessage      string
+	PowerState        *PowerState
 }
 
 // BuildCloudConfig produces a cloud-init YAML document from the given options.
@@ -43,6 +90,22 @@ func BuildCloudConfig(opts CloudConfigOpts) (string, error) {
 		doc["runcmd"] = opts.RunCmd
 	}
 
+	if len(opts.BootCmd) > 0 {
+		doc["bootcmd"] = opts.BootCmd
+	}
+
+	if len(opts.DiskSetup) > 0 {
+		doc["disk_setup"] = opts.DiskSetup
+	}
+
+	if len(opts.FSSetup) > 0 {
+		doc["fs_setup"] = opts.FSSetup
+	}
+
+	if len(opts.Mounts) > 0 {
+		doc["mounts"] = opts.Mounts
+	}
+
 	// SSH user block
 	if opts.SSHUser != "" {
 		user := map[string]interface{}{
@@ -66,6 +129,14 @@ func BuildCloudConfig(opts CloudConfigOpts) (string, error) {
 		doc["users"] = []map[string]interface{}{user}
 	}
 
+	if opts.FinalMessage != "" {
+		doc["final_message"] = opts.FinalMessage
+	}
+
+	if opts.PowerState != nil {
+		doc["power_state"] = opts.PowerState
+	}
+
 	// Merge extra keys at top level
 	for k, v := range opts.Extra {
 		doc[k] = v
@@ -82,3 +153,32 @@ func BuildCloudConfig(opts CloudConfigOpts) (string, error) {
 
 	return "#cloud-config\n" + string(yamlBytes), nil
 }
+
+// HasCompleted reports whether cloud-init output, such as the serial console
+// or /var/log/cloud-init-output.log read through the guest agent, contains
+// CompletionSentinel.
+func HasCompleted(output string) bool {
+	return strings.Contains(output, CompletionSentinel)
+}
+
+// BuildCompressedCloudConfig renders the cloud-config like BuildCloudConfig
+// and gzips the result. cloud-init detects gzip-compressed userdata and
+// decompresses it before parsing, so the "#cloud-config" header inside the
+// payload still selects the format.
+func BuildCompressedCloudConfig(opts CloudConfigOpts) ([]byte, error) {
+	userdata, err := BuildCloudConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return Compress(userdata), nil
+}
+
+// Compress gzips rendered userdata. Writing to an in-memory buffer cannot
+// fail, so no error is returned.
+func Compress(userdata string) []byte {
+	var buf bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	_, _ = zw.Write([]byte(userdata))
+	_ = zw.Close()
+	return buf.Bytes()
+}
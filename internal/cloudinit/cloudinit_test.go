@@ -4,6 +4,11 @@
 package cloudinit_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"gopkg.in/yaml.v3"
@@ -106,6 +111,90 @@ var _ = Describe("BuildCloudConfig", func() {
 		Expect(parsed).NotTo(HaveKey("runcmd"))
 		Expect(parsed).NotTo(HaveKey("users"))
 		Expect(parsed).NotTo(HaveKey("ssh_pwauth"))
+		Expect(parsed).NotTo(HaveKey("final_message"))
+		Expect(parsed).NotTo(HaveKey("bootcmd"))
+		Expect(parsed).NotTo(HaveKey("disk_setup"))
+		Expect(parsed).NotTo(HaveKey("fs_setup"))
+		Expect(parsed).NotTo(HaveKey("mounts"))
+	})
+
+	It("should include bootcmd entries", func() {
+		opts := cloudinit.CloudConfigOpts{
+			BootCmd: [][]string{{"sysctl", "-w", "vm.swappiness=10"}},
+		}
+		result, err := cloudinit.BuildCloudConfig(opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		var parsed map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(result), &parsed)).To(Succeed())
+		Expect(parsed["bootcmd"]).To(Equal([]interface{}{
+			[]interface{}{"sysctl", "-w", "vm.swappiness=10"},
+		}))
+	})
+
+	It("should include disk_setup keyed by device", func() {
+		opts := cloudinit.CloudConfigOpts{
+			DiskSetup: map[string]cloudinit.DiskSetup{
+				"/dev/vdc": {TableType: "gpt", Layout: true},
+			},
+		}
+		result, err := cloudinit.BuildCloudConfig(opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		var parsed map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(result), &parsed)).To(Succeed())
+		Expect(parsed["disk_setup"]).To(Equal(map[string]interface{}{
+			"/dev/vdc": map[string]interface{}{
+				"table_type": "gpt",
+				"layout":     true,
+				"overwrite":  false,
+			},
+		}))
+	})
+
+	It("should include fs_setup entries", func() {
+		opts := cloudinit.CloudConfigOpts{
+			FSSetup: []cloudinit.FSSetup{
+				{Filesystem: "xfs", Device: "/dev/vdc", Partition: "none"},
+			},
+		}
+		result, err := cloudinit.BuildCloudConfig(opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		var parsed map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(result), &parsed)).To(Succeed())
+		Expect(parsed["fs_setup"]).To(Equal([]interface{}{
+			map[string]interface{}{
+				"filesystem": "xfs",
+				"device":     "/dev/vdc",
+				"partition":  "none",
+			},
+		}))
+	})
+
+	It("should include mounts entries", func() {
+		opts := cloudinit.CloudConfigOpts{
+			Mounts: [][]string{{"/dev/vdc", "/data", "xfs", "defaults", "0", "0"}},
+		}
+		result, err := cloudinit.BuildCloudConfig(opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		var parsed map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(result), &parsed)).To(Succeed())
+		Expect(parsed["mounts"]).To(Equal([]interface{}{
+			[]interface{}{"/dev/vdc", "/data", "xfs", "defaults", "0", "0"},
+		}))
+	})
+
+	It("should include final_message when set", func() {
+		result, err := cloudinit.BuildCloudConfig(cloudinit.CloudConfigOpts{
+			FinalMessage: cloudinit.CompletionSentinel,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		var parsed map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(result), &parsed)).To(Succeed())
+		Expect(parsed["final_message"]).To(Equal(cloudinit.CompletionSentinel))
 	})
 
 	It("should produce output parseable by yaml.Unmarshal", func() {
@@ -334,3 +423,66 @@ var _ = Describe("BuildCloudConfig", func() {
 		})
 	})
 })
+
+// gunzip decompresses data, failing the spec on error.
+func gunzip(data []byte) string {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	out, err := io.ReadAll(zr)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	return string(out)
+}
+
+var _ = Describe("BuildCompressedCloudConfig", func() {
+	opts := cloudinit.CloudConfigOpts{
+		Packages: []string{"postgresql-server"},
+		WriteFiles: []cloudinit.WriteFile{
+			{Path: "/usr/local/bin/setup.sh", Content: strings.Repeat("echo setup\n", 200), Permissions: "0755"},
+		},
+		RunCmd: [][]string{{"systemctl", "daemon-reload"}},
+	}
+
+	It("should produce gzip data that round-trips to the uncompressed document", func() {
+		plain, err := cloudinit.BuildCloudConfig(opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		compressed, err := cloudinit.BuildCompressedCloudConfig(opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(compressed[:2]).To(Equal([]byte{0x1f, 0x8b}), "gzip magic lets cloud-init detect the format")
+		Expect(gunzip(compressed)).To(Equal(plain))
+	})
+
+	It("should keep the #cloud-config header and valid YAML after decompression", func() {
+		compressed, err := cloudinit.BuildCompressedCloudConfig(opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		decoded := gunzip(compressed)
+		Expect(decoded).To(HavePrefix("#cloud-config\n"))
+
+		var parsed map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(decoded), &parsed)).To(Succeed())
+		Expect(parsed["packages"]).To(ConsistOf("postgresql-server"))
+	})
+
+	It("should be smaller than the uncompressed document for repetitive scripts", func() {
+		plain, err := cloudinit.BuildCloudConfig(opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		compressed, err := cloudinit.BuildCompressedCloudConfig(opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(compressed)).To(BeNumerically("<", len(plain)))
+	})
+})
+
+var _ = Describe("HasCompleted", func() {
+	It("should recognize the sentinel in cloud-init output", func() {
+		output := "Cloud-init v. 24.4 running 'modules:final'\n" +
+			cloudinit.CompletionSentinel + "\n"
+		Expect(cloudinit.HasCompleted(output)).To(BeTrue())
+	})
+
+	It("should not report completion before the sentinel is printed", func() {
+		Expect(cloudinit.HasCompleted("Cloud-init v. 24.4 running 'modules:config'\n")).To(BeFalse())
+		Expect(cloudinit.HasCompleted("")).To(BeFalse())
+	})
+})
@@ -0,0 +1,32 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudinit
+
+import (
+	"regexp"
+	"strings"
+)
+
+// cloudConfigHeader is the first line that marks userdata as a cloud-config.
+const cloudConfigHeader = "#cloud-config"
+
+// hostnameKey matches a top-level hostname key.
+var hostnameKey = regexp.MustCompile(`(?m)^hostname\s*:`)
+
+// WithHostname returns userdata with a top-level "hostname: <name>" key, so
+// that VMs rendered from the same workload userdata each boot with their own
+// hostname. The key is inserted right after the "#cloud-config" header and
+// the rest of the document is kept as written. Userdata that is not a
+// cloud-config, such as a shell script, or that already sets a hostname is
+// returned unchanged.
+func WithHostname(userdata, name string) string {
+	if !strings.HasPrefix(userdata, cloudConfigHeader) || hostnameKey.MatchString(userdata) {
+		return userdata
+	}
+	header, body, found := strings.Cut(userdata, "\n")
+	if !found {
+		header, body = userdata, ""
+	}
+	return header + "\nhostname: " + name + "\n" + body
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudinit_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+
+	"github.com/opdev/virtwork/internal/cloudinit"
+)
+
+var _ = Describe("WithHostname", func() {
+	parse := func(userdata string) map[string]interface{} {
+		var parsed map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(userdata), &parsed)).To(Succeed())
+		return parsed
+	}
+
+	It("should add the hostname after the header and keep the workload content", func() {
+		userdata, err := cloudinit.BuildCloudConfig(cloudinit.CloudConfigOpts{
+			Packages: []string{"stress-ng"},
+			RunCmd:   [][]string{{"systemctl", "enable", "--now", "virtwork-cpu.service"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		named := cloudinit.WithHostname(userdata, "virtwork-cpu-0")
+		Expect(named).To(HavePrefix("#cloud-config\nhostname: virtwork-cpu-0\n"))
+		Expect(named).To(HaveSuffix(userdata[len("#cloud-config\n"):]))
+
+		parsed := parse(named)
+		Expect(parsed["hostname"]).To(Equal("virtwork-cpu-0"))
+		Expect(parsed["packages"]).To(ConsistOf("stress-ng"))
+		Expect(parsed["runcmd"]).To(HaveLen(1))
+	})
+
+	It("should give VMs rendered from the same userdata distinct hostnames", func() {
+		userdata, err := cloudinit.BuildCloudConfig(cloudinit.CloudConfigOpts{Packages: []string{"stress-ng"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(parse(cloudinit.WithHostname(userdata, "virtwork-cpu-0"))["hostname"]).To(Equal("virtwork-cpu-0"))
+		Expect(parse(cloudinit.WithHostname(userdata, "virtwork-cpu-1"))["hostname"]).To(Equal("virtwork-cpu-1"))
+	})
+
+	It("should handle an empty cloud-config", func() {
+		named := cloudinit.WithHostname("#cloud-config", "virtwork-cpu-0")
+		Expect(named).To(Equal("#cloud-config\nhostname: virtwork-cpu-0\n"))
+	})
+
+	It("should keep a hostname the userdata already sets", func() {
+		userdata := "#cloud-config\npackages:\n  - nginx\nhostname: custom\n"
+		Expect(cloudinit.WithHostname(userdata, "virtwork-cpu-0")).To(Equal(userdata))
+	})
+
+	It("should not treat a nested hostname key as top-level", func() {
+		userdata := "#cloud-config\nwrite_files:\n  - path: /etc/app.yaml\n    content: |\n      hostname: db\n"
+		named := cloudinit.WithHostname(userdata, "virtwork-web-client-0")
+		Expect(parse(named)["hostname"]).To(Equal("virtwork-web-client-0"))
+	})
+
+	It("should leave userdata that is not a cloud-config unchanged", func() {
+		script := "#!/bin/bash\necho hello\n"
+		Expect(cloudinit.WithHostname(script, "virtwork-custom-0")).To(Equal(script))
+	})
+})
@@ -0,0 +1,174 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudinit
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// overlayDoc is the cloud-config an overlay file holds. Keys without a
+// CloudConfigOpts field are collected into Extra.
+type overlayDoc struct {
+	Packages     []string               `yaml:"packages"`
+	WriteFiles   []WriteFile            `yaml:"write_files"`
+	RunCmd       commandList            `yaml:"runcmd"`
+	BootCmd      commandList            `yaml:"bootcmd"`
+	DiskSetup    map[string]DiskSetup   `yaml:"disk_setup"`
+	FSSetup      []FSSetup              `yaml:"fs_setup"`
+	Mounts       [][]string             `yaml:"mounts"`
+	FinalMessage string                 `yaml:"final_message"`
+	PowerState   *PowerState            `yaml:"power_state"`
+	Extra        map[string]interface{} `yaml:",inline"`
+}
+
+// commandList is a runcmd or bootcmd list. cloud-init runs a string entry
+// through the shell, so one is stored as sh -c.
+type commandList [][]string
+
+func (c *commandList) UnmarshalYAML(value *yaml.Node) error {
+	var entries []yaml.Node
+	if err := value.Decode(&entries); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Kind == yaml.ScalarNode {
+			*c = append(*c, []string{"sh", "-c", entry.Value})
+			continue
+		}
+		var argv []string
+		if err := entry.Decode(&argv); err != nil {
+			return err
+		}
+		*c = append(*c, argv)
+	}
+	return nil
+}
+
+// ParseOverlay reads an operator-supplied cloud-config fragment to merge into
+// every workload's with MergeOverlay. The "#cloud-config" header is optional.
+// Guest users and SSH access are set with virtwork's SSH options instead, so
+// users and ssh_pwauth are rejected. A write_files entry without permissions
+// gets 0644.
+func ParseOverlay(data []byte) (CloudConfigOpts, error) {
+	var doc overlayDoc
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return CloudConfigOpts{}, err
+	}
+	for _, key := range []string{"users", "ssh_pwauth"} {
+		if _, ok := doc.Extra[key]; ok {
+			return CloudConfigOpts{}, fmt.Errorf("%s cannot be set in an overlay; use the SSH options instead", key)
+		}
+	}
+	for i := range doc.WriteFiles {
+		if doc.WriteFiles[i].Path == "" {
+			return CloudConfigOpts{}, fmt.Errorf("write_files entry %d has no path", i)
+		}
+		if doc.WriteFiles[i].Permissions == "" {
+			doc.WriteFiles[i].Permissions = "0644"
+		}
+	}
+	return CloudConfigOpts{
+		Packages:     doc.Packages,
+		WriteFiles:   doc.WriteFiles,
+		RunCmd:       doc.RunCmd,
+		BootCmd:      doc.BootCmd,
+		DiskSetup:    doc.DiskSetup,
+		FSSetup:      doc.FSSetup,
+		Mounts:       doc.Mounts,
+		FinalMessage: doc.FinalMessage,
+		PowerState:   doc.PowerState,
+		Extra:        doc.Extra,
+	}, nil
+}
+
+// MergeOverlay merges overlay into opts and returns the result; neither input
+// is modified. Lists are concatenated with the overlay's entries last, except
+// that packages already listed are not repeated. Maps are merged key by key,
+// recursively for the nested maps of Extra. Where both set a scalar, the same
+// write_files path, or the same disk_setup device, opts' value is kept unless
+// override is set. The SSH fields of overlay are ignored.
+func MergeOverlay(opts, overlay CloudConfigOpts, override bool) CloudConfigOpts {
+	merged := opts
+
+	merged.Packages = slices.Clone(opts.Packages)
+	for _, p := range overlay.Packages {
+		if !slices.Contains(merged.Packages, p) {
+			merged.Packages = append(merged.Packages, p)
+		}
+	}
+
+	merged.WriteFiles = slices.Clone(opts.WriteFiles)
+	for _, f := range overlay.WriteFiles {
+		i := slices.IndexFunc(merged.WriteFiles, func(own WriteFile) bool { return own.Path == f.Path })
+		switch {
+		case i < 0:
+			merged.WriteFiles = append(merged.WriteFiles, f)
+		case override:
+			merged.WriteFiles[i] = f
+		}
+	}
+
+	merged.RunCmd = append(slices.Clone(opts.RunCmd), overlay.RunCmd...)
+	merged.BootCmd = append(slices.Clone(opts.BootCmd), overlay.BootCmd...)
+	merged.FSSetup = append(slices.Clone(opts.FSSetup), overlay.FSSetup...)
+	merged.Mounts = append(slices.Clone(opts.Mounts), overlay.Mounts...)
+
+	if len(overlay.DiskSetup) > 0 {
+		merged.DiskSetup = maps.Clone(opts.DiskSetup)
+		if merged.DiskSetup == nil {
+			merged.DiskSetup = make(map[string]DiskSetup, len(overlay.DiskSetup))
+		}
+		for device, setup := range overlay.DiskSetup {
+			if _, ok := merged.DiskSetup[device]; !ok || override {
+				merged.DiskSetup[device] = setup
+			}
+		}
+	}
+
+	if overlay.FinalMessage != "" && (merged.FinalMessage == "" || override) {
+		merged.FinalMessage = overlay.FinalMessage
+	}
+	if overlay.PowerState != nil && (merged.PowerState == nil || override) {
+		merged.PowerState = overlay.PowerState
+	}
+
+	if len(overlay.Extra) > 0 {
+		merged.Extra = mergeMaps(opts.Extra, overlay.Extra, override)
+	}
+	return merged
+}
+
+// mergeMaps returns the union of base and overlay. A key in both holds the
+// merged maps or concatenated lists when both values are maps or lists, and
+// otherwise base's value unless override is set.
+func mergeMaps(base, overlay map[string]interface{}, override bool) map[string]interface{} {
+	merged := maps.Clone(base)
+	if merged == nil {
+		merged = make(map[string]interface{}, len(overlay))
+	}
+	for k, ov := range overlay {
+		bv, ok := merged[k]
+		if !ok {
+			merged[k] = ov
+			continue
+		}
+		bm, bIsMap := bv.(map[string]interface{})
+		om, oIsMap := ov.(map[string]interface{})
+		bl, bIsList := bv.([]interface{})
+		ol, oIsList := ov.([]interface{})
+		switch {
+		case bIsMap && oIsMap:
+			merged[k] = mergeMaps(bm, om, override)
+		case bIsList && oIsList:
+			merged[k] = append(slices.Clone(bl), ol...)
+		case override:
+			merged[k] = ov
+		}
+	}
+	return merged
+}
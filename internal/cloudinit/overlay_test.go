@@ -0,0 +1,164 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudinit_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/cloudinit"
+)
+
+var _ = Describe("ParseOverlay", func() {
+	It("should read the known keys and collect the rest into Extra", func() {
+		overlay, err := cloudinit.ParseOverlay([]byte(`#cloud-config
+packages: [htop]
+write_files:
+  - path: /etc/motd
+    content: lab VM
+runcmd:
+  - [touch, /run/overlay]
+  - echo hello > /run/greeting
+timezone: Europe/Prague
+chrony:
+  servers: [ntp.example.com]
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(overlay.Packages).To(Equal([]string{"htop"}))
+		Expect(overlay.WriteFiles).To(Equal([]cloudinit.WriteFile{
+			{Path: "/etc/motd", Content: "lab VM", Permissions: "0644"},
+		}))
+		Expect(overlay.RunCmd).To(Equal([][]string{
+			{"touch", "/run/overlay"},
+			{"sh", "-c", "echo hello > /run/greeting"},
+		}))
+		Expect(overlay.Extra).To(HaveKeyWithValue("timezone", "Europe/Prague"))
+		Expect(overlay.Extra).To(HaveKey("chrony"))
+		Expect(overlay.Extra).NotTo(HaveKey("packages"))
+	})
+
+	It("should accept an empty overlay", func() {
+		overlay, err := cloudinit.ParseOverlay([]byte("#cloud-config\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(overlay).To(Equal(cloudinit.CloudConfigOpts{}))
+	})
+
+	It("should reject users and ssh_pwauth", func() {
+		_, err := cloudinit.ParseOverlay([]byte("users:\n  - name: admin\n"))
+		Expect(err).To(MatchError(ContainSubstring("users cannot be set in an overlay")))
+
+		_, err = cloudinit.ParseOverlay([]byte("ssh_pwauth: true\n"))
+		Expect(err).To(MatchError(ContainSubstring("ssh_pwauth cannot be set in an overlay")))
+	})
+
+	It("should reject a write_files entry without a path", func() {
+		_, err := cloudinit.ParseOverlay([]byte("write_files:\n  - content: x\n"))
+		Expect(err).To(MatchError(ContainSubstring("write_files entry 0 has no path")))
+	})
+
+	It("should reject malformed YAML", func() {
+		_, err := cloudinit.ParseOverlay([]byte("packages: [htop\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("MergeOverlay", func() {
+	workload := func() cloudinit.CloudConfigOpts {
+		return cloudinit.CloudConfigOpts{
+			Packages:     []string{"stress-ng"},
+			WriteFiles:   []cloudinit.WriteFile{{Path: "/usr/local/bin/run.sh", Content: "#!/bin/bash\n", Permissions: "0755"}},
+			RunCmd:       [][]string{{"systemctl", "enable", "--now", "virtwork-cpu.service"}},
+			DiskSetup:    map[string]cloudinit.DiskSetup{"/dev/vdb": {Layout: false}},
+			FinalMessage: cloudinit.CompletionSentinel,
+			SSHUser:      "virtwork",
+			Extra: map[string]interface{}{
+				"timezone": "UTC",
+				"chrony":   map[string]interface{}{"servers": []interface{}{"a.example.com"}, "pools": []interface{}{}},
+			},
+		}
+	}
+
+	It("should concatenate lists with the overlay's entries last", func() {
+		merged := cloudinit.MergeOverlay(workload(), cloudinit.CloudConfigOpts{
+			Packages:   []string{"htop", "stress-ng"},
+			WriteFiles: []cloudinit.WriteFile{{Path: "/etc/motd", Content: "lab VM", Permissions: "0644"}},
+			RunCmd:     [][]string{{"touch", "/run/overlay"}},
+			BootCmd:    [][]string{{"echo", "boot"}},
+			Mounts:     [][]string{{"tmpfs", "/scratch", "tmpfs", "defaults", "0", "0"}},
+		}, false)
+
+		Expect(merged.Packages).To(Equal([]string{"stress-ng", "htop"}))
+		Expect(merged.WriteFiles).To(HaveLen(2))
+		Expect(merged.WriteFiles[1].Path).To(Equal("/etc/motd"))
+		Expect(merged.RunCmd).To(Equal([][]string{
+			{"systemctl", "enable", "--now", "virtwork-cpu.service"},
+			{"touch", "/run/overlay"},
+		}))
+		Expect(merged.BootCmd).To(Equal([][]string{{"echo", "boot"}}))
+		Expect(merged.Mounts).To(HaveLen(1))
+		Expect(merged.SSHUser).To(Equal("virtwork"))
+	})
+
+	It("should merge maps recursively, concatenating nested lists", func() {
+		merged := cloudinit.MergeOverlay(workload(), cloudinit.CloudConfigOpts{
+			DiskSetup: map[string]cloudinit.DiskSetup{"/dev/vdc": {TableType: "gpt", Layout: true}},
+			Extra: map[string]interface{}{
+				"chrony": map[string]interface{}{"servers": []interface{}{"b.example.com"}, "makestep": "1.0 3"},
+				"locale": "en_US.UTF-8",
+			},
+		}, false)
+
+		Expect(merged.DiskSetup).To(HaveKey("/dev/vdb"))
+		Expect(merged.DiskSetup).To(HaveKey("/dev/vdc"))
+		Expect(merged.Extra).To(HaveKeyWithValue("locale", "en_US.UTF-8"))
+		Expect(merged.Extra["chrony"]).To(Equal(map[string]interface{}{
+			"servers":  []interface{}{"a.example.com", "b.example.com"},
+			"pools":    []interface{}{},
+			"makestep": "1.0 3",
+		}))
+	})
+
+	It("should keep the workload's scalars and files by default", func() {
+		merged := cloudinit.MergeOverlay(workload(), cloudinit.CloudConfigOpts{
+			WriteFiles:   []cloudinit.WriteFile{{Path: "/usr/local/bin/run.sh", Content: "exit 0\n", Permissions: "0755"}},
+			DiskSetup:    map[string]cloudinit.DiskSetup{"/dev/vdb": {TableType: "gpt", Layout: true}},
+			FinalMessage: "overlay done",
+			PowerState:   &cloudinit.PowerState{Mode: "poweroff"},
+			Extra:        map[string]interface{}{"timezone": "Europe/Prague"},
+		}, false)
+
+		Expect(merged.WriteFiles).To(Equal(workload().WriteFiles))
+		Expect(merged.DiskSetup["/dev/vdb"].Layout).To(BeFalse())
+		Expect(merged.FinalMessage).To(Equal(cloudinit.CompletionSentinel))
+		Expect(merged.PowerState).To(Equal(&cloudinit.PowerState{Mode: "poweroff"}))
+		Expect(merged.Extra).To(HaveKeyWithValue("timezone", "UTC"))
+	})
+
+	It("should prefer the overlay's scalars and files with override", func() {
+		merged := cloudinit.MergeOverlay(workload(), cloudinit.CloudConfigOpts{
+			WriteFiles:   []cloudinit.WriteFile{{Path: "/usr/local/bin/run.sh", Content: "exit 0\n", Permissions: "0755"}},
+			DiskSetup:    map[string]cloudinit.DiskSetup{"/dev/vdb": {TableType: "gpt", Layout: true}},
+			FinalMessage: "overlay done",
+			Extra:        map[string]interface{}{"timezone": "Europe/Prague"},
+		}, true)
+
+		Expect(merged.WriteFiles).To(HaveLen(1))
+		Expect(merged.WriteFiles[0].Content).To(Equal("exit 0\n"))
+		Expect(merged.DiskSetup["/dev/vdb"].Layout).To(BeTrue())
+		Expect(merged.FinalMessage).To(Equal("overlay done"))
+		Expect(merged.Extra).To(HaveKeyWithValue("timezone", "Europe/Prague"))
+	})
+
+	It("should leave its inputs unmodified", func() {
+		opts := workload()
+		overlay := cloudinit.CloudConfigOpts{
+			Packages: []string{"htop"},
+			Extra:    map[string]interface{}{"chrony": map[string]interface{}{"makestep": "1.0 3"}},
+		}
+		_ = cloudinit.MergeOverlay(opts, overlay, true)
+
+		Expect(opts).To(Equal(workload()))
+		Expect(overlay.Packages).To(Equal([]string{"htop"}))
+	})
+})
@@ -0,0 +1,90 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudinit
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces secret values in redacted userdata. YAML values are
+// written quoted, since a bare "*" starts a YAML alias.
+const Redacted = "***"
+
+// redactedValue is Redacted as a YAML scalar.
+const redactedValue = `"` + Redacted + `"`
+
+var (
+	// passwordLine matches a password key and its value, e.g.
+	// "plain_text_passwd: hunter2" or "- password: hunter2".
+	passwordLine = regexp.MustCompile(`^(\s*(?:-\s+)?(?:plain_text_passwd|hashed_passwd|passwd|password)\s*:\s*)\S.*$`)
+	// keysLine matches the ssh_authorized_keys key, with an optional inline
+	// flow sequence as its value.
+	keysLine = regexp.MustCompile(`^(\s*(?:-\s+)?ssh_authorized_keys\s*:)\s*(\S.*)?$`)
+	// listItem matches a block sequence entry.
+	listItem = regexp.MustCompile(`^(\s*-\s+)\S.*$`)
+	// privateKeyBegin and privateKeyEnd delimit a PEM private key.
+	privateKeyBegin = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+	privateKeyEnd   = regexp.MustCompile(`-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+// Redact returns userdata with password values, SSH authorized keys, and the
+// body of any PEM private key replaced by Redacted. It works line by line on
+// the rendered text, so the layout is kept and scripts and custom
+// cloud-configs are covered as well as virtwork's own output.
+func Redact(userdata string) string {
+	lines := strings.Split(userdata, "\n")
+	inKeys := false
+	keysIndent := 0
+	inPrivateKey := false
+
+	for i, line := range lines {
+		switch {
+		case inPrivateKey:
+			if privateKeyEnd.MatchString(line) {
+				inPrivateKey = false
+				continue
+			}
+			lines[i] = leadingSpace(line) + Redacted
+			continue
+		case privateKeyBegin.MatchString(line):
+			inPrivateKey = !privateKeyEnd.MatchString(line)
+			continue
+		}
+
+		if inKeys {
+			if m := listItem.FindStringSubmatch(line); m != nil && indentOf(line) >= keysIndent {
+				lines[i] = m[1] + redactedValue
+				continue
+			}
+			inKeys = false
+		}
+
+		if m := keysLine.FindStringSubmatch(line); m != nil {
+			if m[2] != "" {
+				lines[i] = m[1] + " " + redactedValue
+			} else {
+				// Entries belong to the key when indented at least as far
+				// as the key itself, which may follow a "- " marker.
+				inKeys = true
+				keysIndent = strings.Index(line, "ssh_authorized_keys")
+			}
+			continue
+		}
+		if m := passwordLine.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + redactedValue
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentOf returns the number of leading spaces in line.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// leadingSpace returns the leading whitespace of line.
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
@@ -6,18 +6,25 @@ package cluster
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/opdev/virtwork/internal/constants"
 )
 
-// NewScheme builds a runtime.Scheme with core K8s types, KubeVirt types,
-// and CDI (Containerized Data Importer) types registered.
+// NewScheme builds a runtime.Scheme with core K8s types, KubeVirt types
+// (including VirtualMachineInstanceMigration), and CDI (Containerized Data
+// Importer) types registered.
 func NewScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	_ = clientgoscheme.AddToScheme(scheme)
@@ -31,12 +38,49 @@ func NewScheme() *runtime.Scheme {
 // the given path (checking the KUBECONFIG env var when the path is empty).
 // Both failures produce a wrapped error.
 func Connect(kubeconfigPath string) (client.Client, error) {
-	scheme := NewScheme()
+	return ConnectWithContext(kubeconfigPath, "")
+}
+
+// ConnectWithContext behaves like Connect but, when contextName is non-empty,
+// skips in-cluster configuration and uses the named kubeconfig context. The
+// context is validated first so an unknown name fails with the list of
+// contexts that are available.
+func ConnectWithContext(kubeconfigPath, contextName string) (client.Client, error) {
+	restConfig, err := RESTConfig(kubeconfigPath, contextName)
+	if err != nil {
+		return nil, err
+	}
 
+	c, err := client.New(restConfig, client.Options{Scheme: NewScheme()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create controller-runtime client: %w", err)
+	}
+
+	return c, nil
+}
+
+// RESTConfig resolves the REST configuration ConnectWithContext connects
+// with: the named kubeconfig context when contextName is non-empty, and
+// otherwise in-cluster configuration falling back to the kubeconfig.
+func RESTConfig(kubeconfigPath, contextName string) (*rest.Config, error) {
 	if kubeconfigPath == "" {
 		kubeconfigPath = os.Getenv("KUBECONFIG")
 	}
 
+	if contextName != "" {
+		if err := ValidateContext(kubeconfigPath, contextName); err != nil {
+			return nil, err
+		}
+		restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			loadingRules(kubeconfigPath),
+			&clientcmd.ConfigOverrides{CurrentContext: contextName},
+		).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build kubeconfig from %q for context %q: %w", kubeconfigPath, contextName, err)
+		}
+		return restConfig, nil
+	}
+
 	restConfig, err := rest.InClusterConfig()
 	if err != nil {
 		restConfig, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
@@ -44,11 +88,80 @@ func Connect(kubeconfigPath string) (client.Client, error) {
 			return nil, fmt.Errorf("failed to build kubeconfig from %q: %w", kubeconfigPath, err)
 		}
 	}
+	return restConfig, nil
+}
 
-	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+// NewDiscoveryClient returns a discovery client for the same API server
+// ConnectWithContext connects to. Its requests time out after
+// constants.DefaultConnectCheckTimeout, so a Ping through it cannot hang.
+func NewDiscoveryClient(kubeconfigPath, contextName string) (discovery.ServerVersionInterface, error) {
+	restConfig, err := RESTConfig(kubeconfigPath, contextName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create controller-runtime client: %w", err)
+		return nil, err
 	}
+	restConfig = rest.CopyConfig(restConfig)
+	restConfig.Timeout = constants.DefaultConnectCheckTimeout
 
-	return c, nil
+	d, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	return d, nil
+}
+
+// Ping asks the API server for its version. Unlike connecting, which does
+// not dial the server, it fails when the server is unreachable or rejects
+// the client's credentials.
+func Ping(d discovery.ServerVersionInterface) (*version.Info, error) {
+	info, err := d.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("cannot reach API server: %w", err)
+	}
+	return info, nil
+}
+
+// ListContexts returns the sorted context names defined in the kubeconfig at
+// the given path, along with its current-context. An empty path uses the
+// standard resolution (KUBECONFIG, then ~/.kube/config).
+func ListContexts(kubeconfigPath string) ([]string, string, error) {
+	raw, err := loadingRules(kubeconfigPath).Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("loading kubeconfig %q: %w", kubeconfigPath, err)
+	}
+
+	names := make([]string, 0, len(raw.Contexts))
+	for name := range raw.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, raw.CurrentContext, nil
+}
+
+// ValidateContext returns an error listing the available contexts when
+// contextName is not defined in the kubeconfig at the given path.
+func ValidateContext(kubeconfigPath, contextName string) error {
+	names, _, err := ListContexts(kubeconfigPath)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if name == contextName {
+			return nil
+		}
+	}
+	available := "none"
+	if len(names) > 0 {
+		available = strings.Join(names, ", ")
+	}
+	return fmt.Errorf("context %q not found in kubeconfig; available: %s", contextName, available)
+}
+
+// loadingRules returns kubeconfig loading rules that honour an explicit path
+// and otherwise fall back to the client-go defaults.
+func loadingRules(kubeconfigPath string) *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfigPath != "" {
+		rules.ExplicitPath = kubeconfigPath
+	}
+	return rules
 }
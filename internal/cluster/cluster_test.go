@@ -4,6 +4,7 @@
 package cluster_test
 
 import (
+	"errors"
 	"os"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -11,6 +12,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
@@ -38,6 +42,13 @@ var _ = Describe("NewScheme", func() {
 		Expect(gvks).NotTo(BeEmpty())
 	})
 
+	It("should register KubeVirt migration types", func() {
+		obj := &kubevirtv1.VirtualMachineInstanceMigration{}
+		gvks, _, err := scheme.ObjectKinds(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gvks).NotTo(BeEmpty())
+	})
+
 	It("should register CDI types", func() {
 		obj := &cdiv1beta1.DataVolume{}
 		gvks, _, err := scheme.ObjectKinds(obj)
@@ -103,3 +114,144 @@ users:
 		Expect(c).NotTo(BeNil())
 	})
 })
+
+const multiContextKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:99999
+  name: alpha
+- cluster:
+    server: https://127.0.0.1:99998
+  name: beta
+contexts:
+- context:
+    cluster: beta
+    user: test
+  name: beta-admin
+- context:
+    cluster: alpha
+    user: test
+  name: alpha-admin
+current-context: alpha-admin
+users:
+- name: test
+  user:
+    token: fake-token
+`
+
+// writeKubeconfig writes content to a temp file and returns its path.
+func writeKubeconfig(content string) string {
+	tmpFile, err := os.CreateTemp("", "kubeconfig-*.yaml")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(os.Remove, tmpFile.Name())
+
+	_, err = tmpFile.WriteString(content)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(tmpFile.Close()).To(Succeed())
+	return tmpFile.Name()
+}
+
+var _ = Describe("ListContexts", func() {
+	It("should return sorted context names and the current context", func() {
+		path := writeKubeconfig(multiContextKubeconfig)
+
+		names, current, err := cluster.ListContexts(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names).To(Equal([]string{"alpha-admin", "beta-admin"}))
+		Expect(current).To(Equal("alpha-admin"))
+	})
+
+	It("should return error for a nonexistent kubeconfig", func() {
+		_, _, err := cluster.ListContexts("/nonexistent/kubeconfig/path")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ValidateContext", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = writeKubeconfig(multiContextKubeconfig)
+	})
+
+	It("should accept a context defined in the kubeconfig", func() {
+		Expect(cluster.ValidateContext(path, "beta-admin")).To(Succeed())
+	})
+
+	It("should list available contexts when the context is unknown", func() {
+		err := cluster.ValidateContext(path, "gamma-admin")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`"gamma-admin" not found`))
+		Expect(err.Error()).To(ContainSubstring("alpha-admin, beta-admin"))
+	})
+})
+
+var _ = Describe("ConnectWithContext", func() {
+	BeforeEach(func() {
+		origHost := os.Getenv("KUBERNETES_SERVICE_HOST")
+		os.Unsetenv("KUBERNETES_SERVICE_HOST")
+		DeferCleanup(func() {
+			if origHost != "" {
+				os.Setenv("KUBERNETES_SERVICE_HOST", origHost)
+			}
+		})
+	})
+
+	It("should connect with a valid non-current context", func() {
+		path := writeKubeconfig(multiContextKubeconfig)
+
+		c, err := cluster.ConnectWithContext(path, "beta-admin")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).NotTo(BeNil())
+	})
+
+	It("should fail with available contexts for an unknown context", func() {
+		path := writeKubeconfig(multiContextKubeconfig)
+
+		_, err := cluster.ConnectWithContext(path, "missing")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("available: alpha-admin, beta-admin"))
+	})
+})
+
+var _ = Describe("Ping", func() {
+	It("should return the server version when the API server answers", func() {
+		d := &fakediscovery.FakeDiscovery{
+			Fake:               &clienttesting.Fake{},
+			FakedServerVersion: &version.Info{GitVersion: "v1.33.2"},
+		}
+
+		info, err := cluster.Ping(d)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.GitVersion).To(Equal("v1.33.2"))
+	})
+
+	It("should fail with a clear message when the API server cannot be reached", func() {
+		fake := &clienttesting.Fake{}
+		fake.AddReactor("get", "version", func(clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("dial tcp 127.0.0.1:6443: connect: connection refused")
+		})
+
+		_, err := cluster.Ping(&fakediscovery.FakeDiscovery{Fake: fake})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("cannot reach API server: "))
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
+
+var _ = Describe("NewDiscoveryClient", func() {
+	It("should fail like Connect when no configuration is found", func() {
+		origHost := os.Getenv("KUBERNETES_SERVICE_HOST")
+		os.Unsetenv("KUBERNETES_SERVICE_HOST")
+		defer func() {
+			if origHost != "" {
+				os.Setenv("KUBERNETES_SERVICE_HOST", origHost)
+			}
+		}()
+
+		_, err := cluster.NewDiscoveryClient("/nonexistent/kubeconfig/path", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("kubeconfig"))
+	})
+})
@@ -0,0 +1,147 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cpuManagerFeatureGate is the KubeVirt feature gate that older releases
+// require before VMs may request dedicated CPUs.
+const cpuManagerFeatureGate = "CPUManager"
+
+// FeatureRequirements lists the requested VM options that only work when
+// the cluster has the matching KubeVirt or node configuration.
+type FeatureRequirements struct {
+	// DedicatedCPU requires the CPU manager, signalled either by the
+	// CPUManager feature gate or by nodes labeled cpumanager=true.
+	DedicatedCPU bool
+	// Hugepages requires at least one node with hugepages allocatable.
+	Hugepages bool
+	// GPUs lists device resource names that each require at least one node
+	// with the resource allocatable.
+	GPUs []string
+}
+
+// any reports whether at least one option has been requested.
+func (r FeatureRequirements) any() bool {
+	return r.DedicatedCPU || r.Hugepages || len(r.GPUs) > 0
+}
+
+// CheckFeatureGates is a best-effort preflight for the requested options.
+// It returns an error naming every option the cluster definitely cannot
+// satisfy. When the KubeVirt CR or the node list cannot be read (for
+// example, because of RBAC), the affected checks are skipped and reported
+// as warnings instead.
+func CheckFeatureGates(ctx context.Context, c client.Client, req FeatureRequirements) ([]string, error) {
+	if !req.any() {
+		return nil, nil
+	}
+
+	var warnings []string
+
+	gates, gatesErr := kubeVirtFeatureGates(ctx, c)
+	if gatesErr != nil {
+		warnings = append(warnings, fmt.Sprintf("unable to read KubeVirt configuration: %v", gatesErr))
+	}
+
+	nodes := &corev1.NodeList{}
+	nodesErr := c.List(ctx, nodes)
+	if nodesErr != nil {
+		warnings = append(warnings, fmt.Sprintf("unable to list nodes: %v", nodesErr))
+	}
+
+	var missing []string
+
+	if req.DedicatedCPU {
+		enabled := gates[cpuManagerFeatureGate] || (nodesErr == nil && anyNode(nodes, hasCPUManager))
+		switch {
+		case enabled:
+		case gatesErr != nil || nodesErr != nil:
+			warnings = append(warnings, "could not confirm the CPU manager is enabled for dedicated CPU placement")
+		default:
+			missing = append(missing, fmt.Sprintf(
+				"dedicated CPU placement requires the CPU manager (no node is labeled %s=true and the %s feature gate is not enabled)",
+				kubevirtv1.CPUManager, cpuManagerFeatureGate))
+		}
+	}
+
+	if req.Hugepages && nodesErr == nil && !anyNode(nodes, hasHugepages) {
+		missing = append(missing, "hugepages require at least one node with hugepages allocatable")
+	}
+
+	if nodesErr == nil {
+		for _, name := range req.GPUs {
+			if !anyNode(nodes, func(node *corev1.Node) bool { return hasResource(node, name) }) {
+				missing = append(missing, fmt.Sprintf("GPU %s is not allocatable on any node", name))
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return warnings, fmt.Errorf("cluster does not support requested options: %s", strings.Join(missing, "; "))
+	}
+	return warnings, nil
+}
+
+// kubeVirtFeatureGates returns the feature gates enabled on the cluster's
+// KubeVirt CR. The CR is looked up across all namespaces since its location
+// differs between upstream KubeVirt and OpenShift Virtualization.
+func kubeVirtFeatureGates(ctx context.Context, c client.Client) (map[string]bool, error) {
+	kvList := &kubevirtv1.KubeVirtList{}
+	if err := c.List(ctx, kvList); err != nil {
+		return nil, fmt.Errorf("listing KubeVirt resources: %w", err)
+	}
+	if len(kvList.Items) == 0 {
+		return nil, fmt.Errorf("no KubeVirt resource found")
+	}
+
+	gates := make(map[string]bool)
+	cfg := kvList.Items[0].Spec.Configuration
+	if cfg.DeveloperConfiguration != nil {
+		for _, gate := range cfg.DeveloperConfiguration.FeatureGates {
+			gates[gate] = true
+		}
+	}
+	return gates, nil
+}
+
+// anyNode reports whether any node in the list satisfies match.
+func anyNode(nodes *corev1.NodeList, match func(*corev1.Node) bool) bool {
+	for i := range nodes.Items {
+		if match(&nodes.Items[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCPUManager reports whether KubeVirt has labeled the node as running
+// the kubelet CPU manager.
+func hasCPUManager(node *corev1.Node) bool {
+	return node.Labels[kubevirtv1.CPUManager] == "true"
+}
+
+// hasHugepages reports whether the node has any hugepages allocatable.
+func hasHugepages(node *corev1.Node) bool {
+	for name, qty := range node.Status.Allocatable {
+		if strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix) && !qty.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasResource reports whether the node has any of the named resource
+// allocatable, as a device plugin advertises it.
+func hasResource(node *corev1.Node, name string) bool {
+	qty, ok := node.Status.Allocatable[corev1.ResourceName(name)]
+	return ok && !qty.IsZero()
+}
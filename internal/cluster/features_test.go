@@ -0,0 +1,178 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/opdev/virtwork/internal/cluster"
+)
+
+func kubeVirtWithGates(gates ...string) *kubevirtv1.KubeVirt {
+	return &kubevirtv1.KubeVirt{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubevirt", Namespace: "kubevirt"},
+		Spec: kubevirtv1.KubeVirtSpec{
+			Configuration: kubevirtv1.KubeVirtConfiguration{
+				DeveloperConfiguration: &kubevirtv1.DeveloperConfiguration{
+					FeatureGates: gates,
+				},
+			},
+		},
+	}
+}
+
+func workerNode(name string, labels map[string]string, allocatable corev1.ResourceList) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status:     corev1.NodeStatus{Allocatable: allocatable},
+	}
+}
+
+var _ = Describe("CheckFeatureGates", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should skip all checks when nothing is requested", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		warnings, err := cluster.CheckFeatureGates(ctx, c, cluster.FeatureRequirements{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	Context("dedicated CPU", func() {
+		req := cluster.FeatureRequirements{DedicatedCPU: true}
+
+		It("should pass when the CPUManager feature gate is enabled", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(kubeVirtWithGates("CPUManager"), workerNode("w1", nil, nil)).
+				Build()
+
+			warnings, err := cluster.CheckFeatureGates(ctx, c, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should pass when a node is labeled for the CPU manager", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(kubeVirtWithGates(), workerNode("w1", map[string]string{"cpumanager": "true"}, nil)).
+				Build()
+
+			_, err := cluster.CheckFeatureGates(ctx, c, req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return an error when the CPU manager is not enabled", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(kubeVirtWithGates("Snapshot"), workerNode("w1", map[string]string{"cpumanager": "false"}, nil)).
+				Build()
+
+			_, err := cluster.CheckFeatureGates(ctx, c, req)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("dedicated CPU"))
+			Expect(err.Error()).To(ContainSubstring("CPUManager"))
+		})
+
+		It("should warn instead of failing when the KubeVirt CR is not readable", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(workerNode("w1", nil, nil)).
+				WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, cl client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						if _, ok := list.(*kubevirtv1.KubeVirtList); ok {
+							return fmt.Errorf("forbidden")
+						}
+						return cl.List(ctx, list, opts...)
+					},
+				}).
+				Build()
+
+			warnings, err := cluster.CheckFeatureGates(ctx, c, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("unable to read KubeVirt configuration")))
+			Expect(warnings).To(ContainElement(ContainSubstring("could not confirm the CPU manager")))
+		})
+	})
+
+	Context("hugepages", func() {
+		req := cluster.FeatureRequirements{Hugepages: true}
+
+		It("should pass when a node has hugepages allocatable", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(kubeVirtWithGates(), workerNode("w1", nil, corev1.ResourceList{
+					"hugepages-2Mi": resource.MustParse("1Gi"),
+				})).
+				Build()
+
+			_, err := cluster.CheckFeatureGates(ctx, c, req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return an error when no node has hugepages allocatable", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(kubeVirtWithGates(), workerNode("w1", nil, corev1.ResourceList{
+					"hugepages-2Mi": resource.MustParse("0"),
+				})).
+				Build()
+
+			_, err := cluster.CheckFeatureGates(ctx, c, req)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("hugepages"))
+		})
+	})
+
+	Context("GPUs", func() {
+		req := cluster.FeatureRequirements{GPUs: []string{"nvidia.com/GA102GL_A10"}}
+
+		It("should pass when a node has the GPU allocatable", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(kubeVirtWithGates(), workerNode("w1", nil, corev1.ResourceList{
+					"nvidia.com/GA102GL_A10": resource.MustParse("2"),
+				})).
+				Build()
+
+			_, err := cluster.CheckFeatureGates(ctx, c, req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return an error naming a GPU no node has allocatable", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(kubeVirtWithGates(), workerNode("w1", nil, corev1.ResourceList{
+					"nvidia.com/GA102GL_A10": resource.MustParse("0"),
+				})).
+				Build()
+
+			_, err := cluster.CheckFeatureGates(ctx, c, req)
+			Expect(err).To(MatchError(ContainSubstring("GPU nvidia.com/GA102GL_A10 is not allocatable on any node")))
+		})
+	})
+
+	It("should report every unsupported option in a single error", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(kubeVirtWithGates(), workerNode("w1", nil, nil)).
+			Build()
+
+		_, err := cluster.CheckFeatureGates(ctx, c, cluster.FeatureRequirements{DedicatedCPU: true, Hugepages: true})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("dedicated CPU"))
+		Expect(err.Error()).To(ContainSubstring("hugepages"))
+	})
+})
@@ -0,0 +1,53 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// requiredKinds are the API kinds virtwork creates that only exist once
+// OpenShift Virtualization (or upstream KubeVirt and CDI) is installed,
+// keyed by the CRD that serves them.
+var requiredKinds = []struct {
+	crd  string
+	kind schema.GroupKind
+}{
+	{crd: "virtualmachines.kubevirt.io", kind: schema.GroupKind{Group: "kubevirt.io", Kind: "VirtualMachine"}},
+	{crd: "datavolumes.cdi.kubevirt.io", kind: schema.GroupKind{Group: "cdi.kubevirt.io", Kind: "DataVolume"}},
+}
+
+// CheckVirtInstalled verifies that the cluster serves the KubeVirt
+// VirtualMachine and CDI DataVolume APIs, looking them up through c's REST
+// mapper. It returns an error naming every missing CRD and how to install
+// them, so a cluster without OpenShift Virtualization fails before anything
+// is created.
+func CheckVirtInstalled(ctx context.Context, c client.Client) error {
+	var missing []string
+	for _, r := range requiredKinds {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		_, err := c.RESTMapper().RESTMapping(r.kind)
+		if meta.IsNoMatchError(err) {
+			missing = append(missing, r.crd)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("looking up the %s API: %w", r.crd, err)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("cluster is missing the %s CRDs: install OpenShift Virtualization "+
+			"(or KubeVirt and CDI) before running virtwork, or pass --skip-preflight to try anyway",
+			strings.Join(missing, " and "))
+	}
+	return nil
+}
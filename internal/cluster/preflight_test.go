@@ -0,0 +1,67 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/cluster"
+)
+
+var _ = Describe("CheckVirtInstalled", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	// mapperFor returns a REST mapper that knows only the given kinds, as
+	// discovery reports for a cluster with just those CRDs installed.
+	mapperFor := func(gvks ...schema.GroupVersionKind) meta.RESTMapper {
+		var versions []schema.GroupVersion
+		for _, gvk := range gvks {
+			versions = append(versions, gvk.GroupVersion())
+		}
+		mapper := meta.NewDefaultRESTMapper(versions)
+		for _, gvk := range gvks {
+			mapper.Add(gvk, meta.RESTScopeNamespace)
+		}
+		return mapper
+	}
+	vmKind := schema.GroupVersionKind{Group: "kubevirt.io", Version: "v1", Kind: "VirtualMachine"}
+	dvKind := schema.GroupVersionKind{Group: "cdi.kubevirt.io", Version: "v1beta1", Kind: "DataVolume"}
+
+	It("should pass when KubeVirt and CDI are installed", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapperFor(vmKind, dvKind)).Build()
+
+		Expect(cluster.CheckVirtInstalled(ctx, c)).To(Succeed())
+	})
+
+	It("should name both CRDs when neither is installed", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapperFor()).Build()
+
+		err := cluster.CheckVirtInstalled(ctx, c)
+		Expect(err).To(MatchError(ContainSubstring(
+			"cluster is missing the virtualmachines.kubevirt.io and datavolumes.cdi.kubevirt.io CRDs")))
+		Expect(err.Error()).To(ContainSubstring("install OpenShift Virtualization"))
+	})
+
+	It("should name only the missing CRD", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapperFor(vmKind)).Build()
+
+		err := cluster.CheckVirtInstalled(ctx, c)
+		Expect(err).To(MatchError(ContainSubstring("missing the datavolumes.cdi.kubevirt.io CRDs")))
+		Expect(err.Error()).NotTo(ContainSubstring("virtualmachines.kubevirt.io"))
+	})
+})
@@ -0,0 +1,60 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import "github.com/opdev/virtwork/internal/constants"
+
+// CIMetadata identifies the source revision and CI build a run came from.
+type CIMetadata struct {
+	GitSHA    string
+	GitBranch string
+	CIURL     string
+}
+
+// DetectCI reads CI metadata from the environment variables set by GitHub
+// Actions, GitLab CI, Jenkins, and Prow, taking the first one set for each
+// field. getenv is os.Getenv outside of tests. Fields no CI system sets are
+// left empty.
+func DetectCI(getenv func(string) string) CIMetadata {
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := getenv(k); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	md := CIMetadata{
+		GitSHA: first("GITHUB_SHA", "CI_COMMIT_SHA", "GIT_COMMIT", "PULL_PULL_SHA", "PULL_BASE_SHA"),
+		// GITHUB_HEAD_REF is the source branch of a pull request, where
+		// GITHUB_REF_NAME is the merge ref.
+		GitBranch: first("GITHUB_HEAD_REF", "GITHUB_REF_NAME", "CI_COMMIT_REF_NAME", "GIT_BRANCH", "PULL_BASE_REF"),
+		CIURL:     first("CI_JOB_URL", "BUILD_URL"),
+	}
+	server, repo, runID := getenv("GITHUB_SERVER_URL"), getenv("GITHUB_REPOSITORY"), getenv("GITHUB_RUN_ID")
+	if server != "" && repo != "" && runID != "" {
+		md.CIURL = server + "/" + repo + "/actions/runs/" + runID
+	}
+	return md
+}
+
+// CIAnnotations returns the CI metadata annotations to stamp on created
+// resources, or nil when no metadata was given or detected.
+func (c *Config) CIAnnotations() map[string]string {
+	annotations := make(map[string]string, 3)
+	for key, value := range map[string]string{
+		constants.AnnotationGitSHA:    c.GitSHA,
+		constants.AnnotationGitBranch: c.GitBranch,
+		constants.AnnotationCIURL:     c.CIURL,
+	} {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
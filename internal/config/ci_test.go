@@ -0,0 +1,97 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/constants"
+)
+
+var _ = Describe("CI metadata", func() {
+	envFrom := func(env map[string]string) func(string) string {
+		return func(key string) string { return env[key] }
+	}
+
+	It("should detect GitHub Actions metadata", func() {
+		md := config.DetectCI(envFrom(map[string]string{
+			"GITHUB_SHA":        "0123abcd",
+			"GITHUB_REF_NAME":   "42/merge",
+			"GITHUB_HEAD_REF":   "feature-x",
+			"GITHUB_SERVER_URL": "https://github.com",
+			"GITHUB_REPOSITORY": "opdev/virtwork",
+			"GITHUB_RUN_ID":     "9001",
+		}))
+		Expect(md.GitSHA).To(Equal("0123abcd"))
+		Expect(md.GitBranch).To(Equal("feature-x"))
+		Expect(md.CIURL).To(Equal("https://github.com/opdev/virtwork/actions/runs/9001"))
+	})
+
+	It("should detect GitLab CI metadata", func() {
+		md := config.DetectCI(envFrom(map[string]string{
+			"CI_COMMIT_SHA":      "deadbeef",
+			"CI_COMMIT_REF_NAME": "main",
+			"CI_JOB_URL":         "https://gitlab.example.com/group/project/-/jobs/7",
+		}))
+		Expect(md).To(Equal(config.CIMetadata{
+			GitSHA:    "deadbeef",
+			GitBranch: "main",
+			CIURL:     "https://gitlab.example.com/group/project/-/jobs/7",
+		}))
+	})
+
+	It("should detect Jenkins metadata", func() {
+		md := config.DetectCI(envFrom(map[string]string{
+			"GIT_COMMIT": "cafef00d",
+			"GIT_BRANCH": "origin/release-1.2",
+			"BUILD_URL":  "https://jenkins.example.com/job/virtwork/12/",
+		}))
+		Expect(md.GitSHA).To(Equal("cafef00d"))
+		Expect(md.GitBranch).To(Equal("origin/release-1.2"))
+		Expect(md.CIURL).To(Equal("https://jenkins.example.com/job/virtwork/12/"))
+	})
+
+	It("should return empty metadata outside of CI", func() {
+		Expect(config.DetectCI(envFrom(nil))).To(Equal(config.CIMetadata{}))
+	})
+
+	It("should build annotations only for values that are set", func() {
+		cfg := &config.Config{GitSHA: "0123abcd", CIURL: "https://ci.example.com/1"}
+		Expect(cfg.CIAnnotations()).To(Equal(map[string]string{
+			constants.AnnotationGitSHA: "0123abcd",
+			constants.AnnotationCIURL:  "https://ci.example.com/1",
+		}))
+		Expect((&config.Config{}).CIAnnotations()).To(BeNil())
+	})
+
+	It("should leave CI metadata unset without --label-run-with-git", func() {
+		os.Setenv("GITHUB_SHA", "0123abcd")
+		defer os.Unsetenv("GITHUB_SHA")
+
+		cfg, err := config.LoadConfig(newTestCommand())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GitSHA).To(BeEmpty())
+		Expect(cfg.CIAnnotations()).To(BeNil())
+	})
+
+	It("should fill CI metadata from the environment with --label-run-with-git", func() {
+		os.Setenv("GITHUB_SHA", "0123abcd")
+		defer os.Unsetenv("GITHUB_SHA")
+		os.Setenv("GITHUB_REF_NAME", "main")
+		defer os.Unsetenv("GITHUB_REF_NAME")
+
+		cmd := newTestCommand()
+		Expect(cmd.Flags().Set("label-run-with-git", "true")).To(Succeed())
+		Expect(cmd.Flags().Set("git-branch", "override")).To(Succeed())
+		cfg, err := config.LoadConfig(cmd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.LabelRunWithGit).To(BeTrue())
+		Expect(cfg.GitSHA).To(Equal("0123abcd"))
+		Expect(cfg.GitBranch).To(Equal("override"))
+	})
+})
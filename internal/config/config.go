@@ -5,80 +5,489 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	sigyaml "sigs.k8s.io/yaml"
 
 	"github.com/opdev/virtwork/internal/constants"
 )
 
-// WorkloadConfig holds per-workload configuration.
+// WorkloadConfig holds per-workload configuration. The iperf3 settings
+// (Protocol through Bandwidth) only apply to the network workload; zero values
+// keep its defaults of TCP on port 5201 with 4 bidirectional streams.
 type WorkloadConfig struct {
-	Enabled  bool   `mapstructure:"enabled"`
-	VMCount  int    `mapstructure:"vm-count"`
-	CPUCores int    `mapstructure:"cpu-cores"`
-	Memory   string `mapstructure:"memory"`
+	Enabled         bool   `mapstructure:"enabled"`
+	VMCount         int    `mapstructure:"vm-count"`
+	CPUCores        int    `mapstructure:"cpu-cores"`
+	Memory          string `mapstructure:"memory"`
+	Protocol        string `mapstructure:"protocol,omitempty"`
+	Port            int    `mapstructure:"port,omitempty"`
+	ParallelStreams int    `mapstructure:"parallel-streams,omitempty"`
+	Bidir           *bool  `mapstructure:"bidir,omitempty"`
+	Bandwidth       string `mapstructure:"bandwidth,omitempty"`
+	// ContainerDiskImage overrides the global container disk image for the
+	// workload's VMs, like its entry in the images section.
+	ContainerDiskImage string `mapstructure:"container-disk-image,omitempty"`
+	// Hugepages overrides the global hugepages page size for the workload's
+	// VMs.
+	Hugepages string `mapstructure:"hugepages,omitempty"`
+	// ExtraWriteFiles are added to the workload's cloud-init write_files
+	// after its own files.
+	ExtraWriteFiles []WriteFile `mapstructure:"extra-write-files,omitempty"`
+	// IsolatedCPUs is a guest CPU list such as "1-3" that the cpu workload
+	// removes from the guest scheduler with isolcpus and nohz_full and pins
+	// its stress-ng workers to.
+	IsolatedCPUs string `mapstructure:"isolated-cpus,omitempty"`
+	// RestartSeconds is how long systemd waits before restarting the
+	// workload's service. Zero keeps the default of 10.
+	RestartSeconds int `mapstructure:"restart-seconds,omitempty"`
+	// LoopSleepSeconds is the pause between iterations of the workload's
+	// benchmark loop. Zero keeps the default of 10.
+	LoopSleepSeconds int `mapstructure:"loop-sleep-seconds,omitempty"`
+	// RunDurationSeconds is how long each iteration of the benchmark loop
+	// runs. Zero keeps the workload's default: 60 for iperf3 and 300 for
+	// the others.
+	RunDurationSeconds int `mapstructure:"run-duration-seconds,omitempty"`
+}
+
+// WriteFile is a file dropped into a workload's guest through
+// extra-write-files. An empty Permissions defaults to 0644.
+type WriteFile struct {
+	Path        string `mapstructure:"path"`
+	Content     string `mapstructure:"content"`
+	Permissions string `mapstructure:"permissions"`
+}
+
+// filePermissions matches an octal file mode such as 644 or 0755.
+var filePermissions = regexp.MustCompile(`^0?[0-7]{3}$`)
+
+// validate checks the iperf3 settings, loop timing, and extra write files of
+// the named workload entry.
+func (w WorkloadConfig) validate(name string) error {
+	switch strings.ToLower(w.Protocol) {
+	case "", "tcp", "udp":
+	default:
+		return fmt.Errorf("workloads.%s.protocol must be tcp or udp, got %q", name, w.Protocol)
+	}
+	if w.Port < 0 || w.Port > 65535 {
+		return fmt.Errorf("workloads.%s.port must be between 1 and 65535, got %d", name, w.Port)
+	}
+	if w.ParallelStreams < 0 || w.ParallelStreams > 128 {
+		return fmt.Errorf("workloads.%s.parallel-streams must be between 1 and 128, got %d", name, w.ParallelStreams)
+	}
+	if err := validateHugepages("workloads."+name+".hugepages", w.Hugepages); err != nil {
+		return err
+	}
+	if w.RestartSeconds < 0 {
+		return fmt.Errorf("workloads.%s.restart-seconds must not be negative, got %d", name, w.RestartSeconds)
+	}
+	if w.LoopSleepSeconds < 0 {
+		return fmt.Errorf("workloads.%s.loop-sleep-seconds must not be negative, got %d", name, w.LoopSleepSeconds)
+	}
+	if w.RunDurationSeconds < 0 {
+		return fmt.Errorf("workloads.%s.run-duration-seconds must not be negative, got %d", name, w.RunDurationSeconds)
+	}
+	seen := make(map[string]bool, len(w.ExtraWriteFiles))
+	for i, f := range w.ExtraWriteFiles {
+		field := fmt.Sprintf("workloads.%s.extra-write-files[%d]", name, i)
+		if !path.IsAbs(f.Path) || path.Clean(f.Path) != f.Path {
+			return fmt.Errorf("%s.path must be a clean absolute path, got %q", field, f.Path)
+		}
+		if seen[f.Path] {
+			return fmt.Errorf("%s.path %s is listed more than once", field, f.Path)
+		}
+		seen[f.Path] = true
+		if f.Permissions != "" && !filePermissions.MatchString(f.Permissions) {
+			return fmt.Errorf("%s.permissions must be an octal mode such as 0644, got %q", field, f.Permissions)
+		}
+	}
+	if w.IsolatedCPUs != "" {
+		if name != "cpu" {
+			return fmt.Errorf("workloads.%s.isolated-cpus is only supported by the cpu workload", name)
+		}
+		cpus, err := ParseCPUList(w.IsolatedCPUs)
+		if err != nil {
+			return fmt.Errorf("workloads.%s.isolated-cpus: %w", name, err)
+		}
+		if cpus[0] == 0 {
+			return fmt.Errorf("workloads.%s.isolated-cpus must leave CPU 0 for the guest's housekeeping, got %q", name, w.IsolatedCPUs)
+		}
+	}
+	return nil
+}
+
+// EffectiveWorkload returns the settings a workload runs with: the global
+// CPU and memory defaults and the given VM count, overridden by any non-zero
+// values from the workloads section of the config file, plus that section's
+// network and loop timing settings. Hugepages is resolved as
+// EffectiveHugepages does.
+func (c *Config) EffectiveWorkload(name string, vmCount int) WorkloadConfig {
+	wlCfg := WorkloadConfig{
+		Enabled:   true,
+		VMCount:   vmCount,
+		CPUCores:  c.CPUCores,
+		Memory:    c.Memory,
+		Hugepages: c.EffectiveHugepages(name),
+	}
+	if fileCfg, ok := c.Workloads[name]; ok {
+		if fileCfg.CPUCores > 0 {
+			wlCfg.CPUCores = fileCfg.CPUCores
+		}
+		if fileCfg.Memory != "" {
+			wlCfg.Memory = fileCfg.Memory
+		}
+		if fileCfg.VMCount > 0 {
+			wlCfg.VMCount = fileCfg.VMCount
+		}
+		wlCfg.Protocol = fileCfg.Protocol
+		wlCfg.Port = fileCfg.Port
+		wlCfg.ParallelStreams = fileCfg.ParallelStreams
+		wlCfg.Bidir = fileCfg.Bidir
+		wlCfg.Bandwidth = fileCfg.Bandwidth
+		wlCfg.ContainerDiskImage = fileCfg.ContainerDiskImage
+		wlCfg.ExtraWriteFiles = fileCfg.ExtraWriteFiles
+		wlCfg.IsolatedCPUs = fileCfg.IsolatedCPUs
+		wlCfg.RestartSeconds = fileCfg.RestartSeconds
+		wlCfg.LoopSleepSeconds = fileCfg.LoopSleepSeconds
+		wlCfg.RunDurationSeconds = fileCfg.RunDurationSeconds
+	}
+	return wlCfg
+}
+
+// EffectiveImage returns the container disk image for a VM of the named
+// workload: the images entry for "<name>-<role>" when role is set, else the
+// workload's container-disk-image or its images entry, else the global
+// container disk image.
+func (c *Config) EffectiveImage(name, role string) string {
+	if role != "" {
+		if image, ok := c.Images[name+"-"+role]; ok && image != "" {
+			return image
+		}
+	}
+	if image := c.Workloads[name].ContainerDiskImage; image != "" {
+		return image
+	}
+	if image, ok := c.Images[name]; ok && image != "" {
+		return image
+	}
+	return c.ContainerDiskImage
+}
+
+// WorkloadDisabled reports whether the config file's workloads section sets
+// enabled: false for the named workload.
+func (c *Config) WorkloadDisabled(name string) bool {
+	wl, ok := c.Workloads[name]
+	return ok && !wl.Enabled
+}
+
+// EffectiveHugepages returns the hugepages page size for the VMs of the named
+// workload: the workload's hugepages entry, else the global one. Empty means
+// guest memory is not hugepages-backed.
+func (c *Config) EffectiveHugepages(name string) string {
+	if size := c.Workloads[name].Hugepages; size != "" {
+		return size
+	}
+	return c.Hugepages
+}
+
+// WorkloadNamespace returns the namespace the named workload's resources go
+// in: "<namespace>-<name>" with namespace-per-workload set, else the
+// namespace.
+func (c *Config) WorkloadNamespace(name string) string {
+	if c.NamespacePerWorkload {
+		return c.Namespace + "-" + name
+	}
+	return c.Namespace
+}
+
+// ParseCPUList parses a kernel CPU list such as "1-3,6" into its sorted,
+// distinct CPU numbers.
+func ParseCPUList(list string) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, item := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(item, "-")
+		lo, err := strconv.Atoi(first)
+		if err != nil || lo < 0 {
+			return nil, fmt.Errorf("invalid CPU list %q: %q is not a CPU number or range", list, item)
+		}
+		hi := lo
+		if isRange {
+			hi, err = strconv.Atoi(last)
+			if err != nil || hi < lo {
+				return nil, fmt.Errorf("invalid CPU list %q: %q is not a CPU number or range", list, item)
+			}
+		}
+		for cpu := lo; cpu <= hi; cpu++ {
+			seen[cpu] = true
+		}
+	}
+	cpus := make([]int, 0, len(seen))
+	for cpu := range seen {
+		cpus = append(cpus, cpu)
+	}
+	slices.Sort(cpus)
+	return cpus, nil
+}
+
+// WorkloadTemplate defines a user-supplied workload in the config file. Every
+// string field is a Go template rendered against the --template-values file,
+// so one definition can be reused with different parameters.
+type WorkloadTemplate struct {
+	Packages   []string       `mapstructure:"packages"`
+	WriteFiles []TemplateFile `mapstructure:"write-files"`
+	Commands   []string       `mapstructure:"commands"`
+}
+
+// TemplateFile is a write_files entry in a WorkloadTemplate.
+type TemplateFile struct {
+	Path        string `mapstructure:"path"`
+	Content     string `mapstructure:"content"`
+	Permissions string `mapstructure:"permissions"`
 }
 
 // Config holds the complete application configuration.
 type Config struct {
-	Namespace           string                    `mapstructure:"namespace"`
-	ContainerDiskImage  string                    `mapstructure:"container-disk-image"`
-	DataDiskSize        string                    `mapstructure:"data-disk-size"`
-	CPUCores            int                       `mapstructure:"cpu-cores"`
-	Memory              string                    `mapstructure:"memory"`
-	Workloads           map[string]WorkloadConfig `mapstructure:"workloads"`
-	KubeconfigPath      string                    `mapstructure:"kubeconfig"`
-	CleanupMode         string                    `mapstructure:"cleanup-mode"`
-	WaitForReady        bool                      `mapstructure:"wait-for-ready"`
-	ReadyTimeoutSeconds int                       `mapstructure:"timeout"`
-	DryRun              bool                      `mapstructure:"dry-run"`
-	Verbose             bool                      `mapstructure:"verbose"`
-	SSHUser             string                    `mapstructure:"ssh-user"`
-	SSHPassword         string                    `mapstructure:"ssh-password"`
-	SSHAuthorizedKeys   []string                  `mapstructure:"ssh-authorized-keys"`
-	AuditEnabled        bool                      `mapstructure:"audit"`
-	AuditDBPath         string                    `mapstructure:"audit-db"`
+	Namespace               string                      `mapstructure:"namespace"`
+	ContainerDiskImage      string                      `mapstructure:"container-disk-image"`
+	ImagePullSecret         string                      `mapstructure:"image-pull-secret"`
+	Images                  map[string]string           `mapstructure:"images"`
+	DataDiskSize            string                      `mapstructure:"data-disk-size"`
+	CPUCores                int                         `mapstructure:"cpu-cores"`
+	Memory                  string                      `mapstructure:"memory"`
+	Workloads               map[string]WorkloadConfig   `mapstructure:"workloads"`
+	KubeconfigPath          string                      `mapstructure:"kubeconfig"`
+	KubeContext             string                      `mapstructure:"context"`
+	CleanupMode             string                      `mapstructure:"cleanup-mode"`
+	WaitForReady            bool                        `mapstructure:"wait-for-ready"`
+	ReadyTimeoutSeconds     int                         `mapstructure:"timeout"`
+	ReadinessLevel          string                      `mapstructure:"readiness-level"`
+	DryRun                  bool                        `mapstructure:"dry-run"`
+	Verbose                 bool                        `mapstructure:"verbose"`
+	SSHUser                 string                      `mapstructure:"ssh-user"`
+	SSHPassword             string                      `mapstructure:"ssh-password"`
+	SSHAuthorizedKeys       []string                    `mapstructure:"ssh-authorized-keys"`
+	AuditEnabled            bool                        `mapstructure:"audit"`
+	AuditDBPath             string                      `mapstructure:"audit-db"`
+	AuditDSN                string                      `mapstructure:"audit-dsn"`
+	AuditDBPerContext       bool                        `mapstructure:"audit-db-per-context"`
+	Warmup                  time.Duration               `mapstructure:"warmup"`
+	Stagger                 time.Duration               `mapstructure:"stagger"`
+	Deadline                time.Duration               `mapstructure:"deadline"`
+	SkipConnectCheck        bool                        `mapstructure:"skip-connect-check"`
+	SkipPreflight           bool                        `mapstructure:"skip-preflight"`
+	CheckQuota              bool                        `mapstructure:"check-quota"`
+	CreateConcurrency       int                         `mapstructure:"create-concurrency"`
+	CreateRetries           int                         `mapstructure:"create-retries"`
+	CreateBackoff           time.Duration               `mapstructure:"create-backoff"`
+	CustomWorkloads         map[string]WorkloadTemplate `mapstructure:"custom-workloads"`
+	TemplateValuesPath      string                      `mapstructure:"template-values"`
+	TemplateValues          map[string]interface{}      `mapstructure:"-"`
+	SeedSQLPath             string                      `mapstructure:"seed-sql"`
+	SeedSQL                 string                      `mapstructure:"-"`
+	ClockTimezone           string                      `mapstructure:"clock-timezone"`
+	Timers                  map[string]bool             `mapstructure:"-"`
+	CompressCloudInit       bool                        `mapstructure:"compress-cloud-init"`
+	WorkloadProbes          bool                        `mapstructure:"workload-probes"`
+	ClientsPerServer        int                         `mapstructure:"clients-per-server"`
+	CPUModel                string                      `mapstructure:"cpu-model"`
+	DedicatedCPU            bool                        `mapstructure:"dedicated-cpu"`
+	CPUSockets              int                         `mapstructure:"cpu-sockets"`
+	CPUThreads              int                         `mapstructure:"cpu-threads"`
+	Hugepages               string                      `mapstructure:"hugepages"`
+	MemoryLimit             string                      `mapstructure:"memory-limit"`
+	CPULimit                string                      `mapstructure:"cpu-limit"`
+	OvercommitGuestOverhead bool                        `mapstructure:"overcommit-guest-overhead"`
+	ReadOnlyRoot            bool                        `mapstructure:"read-only-root"`
+	NetworkPolicy           bool                        `mapstructure:"network-policy"`
+	NamespacePerWorkload    bool                        `mapstructure:"namespace-per-workload"`
+	CustomUserdata          string                      `mapstructure:"custom-userdata"`
+	CloudInitOverlayPath    string                      `mapstructure:"cloud-init-overlay"`
+	CloudInitOverlay        string                      `mapstructure:"-"`
+	OverlayOverride         bool                        `mapstructure:"overlay-override"`
+	NodeSelector            map[string]string           `mapstructure:"-"`
+	Tolerations             []corev1.Toleration         `mapstructure:"-"`
+	GPUs                    []string                    `mapstructure:"gpus"`
+	MultusNetworks          []string                    `mapstructure:"multus-networks"`
+	AffinityFile            string                      `mapstructure:"affinity-from-file"`
+	Affinity                *corev1.Affinity            `mapstructure:"-"`
+	AntiAffinityWeight      string                      `mapstructure:"anti-affinity-weight"`
+	DataSourceURL           string                      `mapstructure:"data-source-url"`
+	DataSourcePVC           string                      `mapstructure:"data-source-pvc"`
+	DataFSSpec              string                      `mapstructure:"data-fs"`
+	DataFSPVC               string                      `mapstructure:"-"`
+	StorageClass            string                      `mapstructure:"storage-class"`
+	AccessMode              string                      `mapstructure:"access-mode"`
+	SyslogServer            string                      `mapstructure:"syslog-server"`
+	BlockSizeSpec           string                      `mapstructure:"block-size"`
+	BlockSize               *kubevirtv1.BlockSize       `mapstructure:"-"`
+	LabelRunWithGit         bool                        `mapstructure:"label-run-with-git"`
+	GitSHA                  string                      `mapstructure:"git-sha"`
+	GitBranch               string                      `mapstructure:"git-branch"`
+	CIURL                   string                      `mapstructure:"ci-url"`
+	StartStopped            bool                        `mapstructure:"start-stopped"`
 }
 
 // SetDefaults registers Viper defaults.
 func SetDefaults(v *viper.Viper) {
 	v.SetDefault("namespace", constants.DefaultNamespace)
 	v.SetDefault("container-disk-image", constants.DefaultContainerDiskImage)
+	v.SetDefault("image-pull-secret", "")
 	v.SetDefault("data-disk-size", constants.DefaultDiskSize)
 	v.SetDefault("cpu-cores", constants.DefaultCPUCores)
 	v.SetDefault("memory", constants.DefaultMemory)
 	v.SetDefault("wait-for-ready", true)
 	v.SetDefault("timeout", 600)
+	v.SetDefault("readiness-level", constants.ReadinessPhase)
 	v.SetDefault("dry-run", false)
 	v.SetDefault("verbose", false)
 	v.SetDefault("ssh-user", constants.DefaultSSHUser)
 	v.SetDefault("ssh-password", "")
 	v.SetDefault("kubeconfig", "")
+	v.SetDefault("context", "")
 	v.SetDefault("cleanup-mode", "")
 	v.SetDefault("audit", true)
 	v.SetDefault("audit-db", constants.DefaultAuditDBPath)
+	v.SetDefault("audit-dsn", "")
+	v.SetDefault("audit-db-per-context", false)
+	v.SetDefault("warmup", time.Duration(0))
+	v.SetDefault("stagger", time.Duration(0))
+	v.SetDefault("deadline", time.Duration(0))
+	v.SetDefault("skip-connect-check", false)
+	v.SetDefault("skip-preflight", false)
+	v.SetDefault("check-quota", false)
+	v.SetDefault("create-concurrency", constants.DefaultCreateConcurrency)
+	v.SetDefault("create-retries", constants.DefaultCreateRetries)
+	v.SetDefault("create-backoff", constants.DefaultCreateBackoff)
+	v.SetDefault("template-values", "")
+	v.SetDefault("seed-sql", "")
+	v.SetDefault("clock-timezone", "")
+	v.SetDefault("compress-cloud-init", false)
+	v.SetDefault("workload-probes", false)
+	v.SetDefault("clients-per-server", 1)
+	v.SetDefault("cpu-model", "")
+	v.SetDefault("dedicated-cpu", false)
+	v.SetDefault("cpu-sockets", 0)
+	v.SetDefault("cpu-threads", 0)
+	v.SetDefault("hugepages", "")
+	v.SetDefault("memory-limit", "")
+	v.SetDefault("cpu-limit", "")
+	v.SetDefault("overcommit-guest-overhead", false)
+	v.SetDefault("read-only-root", false)
+	v.SetDefault("network-policy", false)
+	v.SetDefault("namespace-per-workload", false)
+	v.SetDefault("cloud-init-overlay", "")
+	v.SetDefault("overlay-override", false)
+	v.SetDefault("custom-userdata", "")
+	v.SetDefault("affinity-from-file", "")
+	v.SetDefault("anti-affinity-weight", "")
+	v.SetDefault("data-source-url", "")
+	v.SetDefault("data-source-pvc", "")
+	v.SetDefault("data-fs", "")
+	v.SetDefault("storage-class", "")
+	v.SetDefault("access-mode", "")
+	v.SetDefault("syslog-server", "")
+	v.SetDefault("block-size", "")
+	v.SetDefault("label-run-with-git", false)
+	v.SetDefault("start-stopped", false)
+	v.SetDefault("git-sha", "")
+	v.SetDefault("git-branch", "")
+	v.SetDefault("ci-url", "")
+}
+
+// NormalizeFlagName maps flag aliases to the flags they stand for, so that
+// --kube-context sets --context. Install it with
+// cobra.Command.SetGlobalNormalizationFunc.
+func NormalizeFlagName(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	if name == "kube-context" {
+		name = "context"
+	}
+	return pflag.NormalizedName(name)
 }
 
 // BindFlags registers Cobra flags on the given command.
 func BindFlags(cmd *cobra.Command) {
+	cmd.SetGlobalNormalizationFunc(NormalizeFlagName)
 	f := cmd.Flags()
 	f.String("namespace", "", "Kubernetes namespace for VMs")
 	f.String("kubeconfig", "", "Path to kubeconfig file")
+	f.String("context", "", "Kubeconfig context to use (alias: --kube-context)")
 	f.String("config", "", "Path to YAML config file")
 	f.String("container-disk-image", "", "Container disk image for VMs")
+	f.String("image-pull-secret", "", "Secret in the namespace used to pull container disk images from a private registry")
 	f.String("data-disk-size", "", "Data disk size")
 	f.Int("cpu-cores", 0, "CPU cores per VM")
 	f.String("memory", "", "Memory per VM (e.g., 2Gi)")
 	f.Bool("dry-run", false, "Print specs without creating resources")
 	f.Bool("no-wait", false, "Skip waiting for VM readiness")
 	f.Int("timeout", 0, "Readiness timeout in seconds")
+	f.String("readiness-level", "", "When a VM counts as ready: phase (default), agent, or ready")
 	f.Bool("verbose", false, "Enable verbose output")
 	f.String("ssh-user", "", "SSH user for VMs")
 	f.String("ssh-password", "", "SSH password for VMs")
 	f.StringSlice("ssh-key", nil, "SSH authorized key (repeatable)")
+	f.StringSlice("ssh-key-file", nil, "SSH key file path (repeatable)")
+	f.Duration("warmup", 0, "Warmup period before each workload's measured loop (e.g., 5m)")
+	f.Duration("stagger", 0, "Spread workload start times across each workload's VMs over this window (e.g., 2m)")
+	f.Duration("deadline", 0, "Abort the run if connecting, creating, and waiting take longer than this (e.g., 30m; 0 = no deadline)")
+	f.Bool("skip-connect-check", false, "Skip pinging the API server before creating resources")
+	f.Bool("skip-preflight", false, "Skip checking that the KubeVirt and CDI CRDs are installed before creating resources")
+	f.Bool("check-quota", false, "Fail before creating resources if the planned VM requests exceed a ResourceQuota in the namespace")
+	f.Int("create-concurrency", constants.DefaultCreateConcurrency, "Maximum VM creates in flight at once")
+	f.Int("create-retries", constants.DefaultCreateRetries, "Retries of a VM create after transient API errors")
+	f.Duration("create-backoff", constants.DefaultCreateBackoff, "Wait before the first VM create retry, doubling per retry up to 30s")
+	f.String("template-values", "", "YAML file of values substituted into custom workload templates")
+	f.String("seed-sql", "", "SQL file applied to the database workload before benchmarking")
+	f.String("clock-timezone", "", "Guest clock: UTC or an IANA timezone (e.g., America/New_York)")
+	f.StringToString("timers", nil, "Guest timers to enable or disable (e.g., hpet=false,kvm=true)")
+	f.Bool("compress-cloud-init", false, "Gzip cloud-init userdata to reduce its size")
+	f.Bool("workload-probes", false, "Gate disk, database, fs, network, redis, and web VM readiness on the workload service running")
+	f.Int("clients-per-server", 1, "Network workload client VMs per iperf3 server")
+	f.String("cpu-model", "", "Guest CPU model (e.g., host-passthrough)")
+	f.Bool("dedicated-cpu", false, "Pin each vCPU to a dedicated host CPU")
+	f.Int("cpu-sockets", 0, "Guest CPU sockets (0 uses the KubeVirt default)")
+	f.Int("cpu-threads", 0, "Guest CPU threads per core (0 uses the KubeVirt default)")
+	f.String("hugepages", "", "Back guest memory with hugepages of this size: 2Mi or 1Gi")
+	f.String("memory-limit", "", "Memory limit per VM (e.g., 4Gi); at least --memory")
+	f.String("cpu-limit", "", "CPU limit per VM (e.g., 2 or 1500m)")
+	f.Bool("overcommit-guest-overhead", false, "Leave the virt-launcher memory overhead out of each VM's memory request")
+	f.Bool("read-only-root", false, "Mount each guest's root filesystem read-only under an ephemeral overlay")
+	f.Bool("network-policy", false, "Allow ingress to network workload servers only from their clients")
+	f.Bool("namespace-per-workload", false, "Deploy each workload into its own namespace, <namespace>-<workload>")
+	f.String("cloud-init-overlay", "", "Cloud-config YAML file merged into every workload's cloud-init")
+	f.Bool("overlay-override", false, "Let --cloud-init-overlay values replace the workload's where both set one")
+	f.String("custom-userdata", "", "Cloud-config or script file run by the \"custom\" workload")
+	f.StringToString("node-selector", nil, "Node label the VMs must be scheduled on (key=value, repeatable)")
+	f.StringArray("toleration", nil, "Taint the VMs tolerate: key[=value][:effect] (repeatable)")
+	f.StringArray("gpu", nil, "GPU device resource to pass through to each VM, e.g. nvidia.com/GA102GL_A10 (repeatable)")
+	f.StringArray("multus-network", nil, "NetworkAttachmentDefinition ([namespace/]name) each VM gets a second NIC on (repeatable)")
+	f.String("affinity-from-file", "", "YAML file holding a Kubernetes affinity for the VMs")
+	f.String("anti-affinity-weight", "", "Keep each workload's VMs on separate nodes: required, preferred, or a preferred weight 1-100")
+	f.Lookup("anti-affinity-weight").NoOptDefVal = "preferred"
+	f.String("data-source-url", "", "Registry image to populate disk and database data disks from (e.g., docker://quay.io/org/img)")
+	f.String("data-source-pvc", "", "PVC ([namespace/]name) to clone disk and database data disks from")
+	f.String("data-fs", "", "Share a PVC with disk and database VMs over virtiofs instead of a data disk: pvc=<name>")
+	f.String("storage-class", "", "StorageClass for disk, database, and fs data disks (default: cluster default)")
+	f.String("access-mode", "", "Access mode for data disks: ReadWriteOnce, ReadWriteMany, ReadOnlyMany, or ReadWriteOncePod")
+	f.String("syslog-server", "", "Forward guest logs to this syslog server over TCP (host:port)")
+	f.String("block-size", "", "Data disk block size: logical=N,physical=N or match-volume")
+	f.Bool("label-run-with-git", false, "Annotate created resources with the git SHA, branch, and build URL detected from CI env vars")
+	f.String("git-sha", "", "Git SHA to annotate created resources with (overrides the detected one)")
+	f.String("git-branch", "", "Git branch to annotate created resources with (overrides the detected one)")
+	f.String("ci-url", "", "CI build URL to annotate created resources with (overrides the detected one)")
+	f.Bool("start-stopped", false, "Create the VMs stopped; boot them later with \"virtwork start\"")
 }
 
 // LoadConfig loads configuration from flags, environment variables, config file,
@@ -106,11 +515,35 @@ func LoadConfig(cmd *cobra.Command) (*Config, error) {
 	// Bind flags (highest priority — only overrides when explicitly set)
 	bindFlagIfSet(v, cmd, "namespace")
 	bindFlagIfSet(v, cmd, "kubeconfig")
+	bindFlagIfSet(v, cmd, "context")
 	bindFlagIfSet(v, cmd, "container-disk-image")
+	bindFlagIfSet(v, cmd, "image-pull-secret")
 	bindFlagIfSet(v, cmd, "data-disk-size")
 	bindFlagIfSet(v, cmd, "memory")
 	bindFlagIfSet(v, cmd, "ssh-user")
 	bindFlagIfSet(v, cmd, "ssh-password")
+	bindFlagIfSet(v, cmd, "template-values")
+	bindFlagIfSet(v, cmd, "seed-sql")
+	bindFlagIfSet(v, cmd, "cloud-init-overlay")
+	bindFlagIfSet(v, cmd, "clock-timezone")
+	bindFlagIfSet(v, cmd, "cpu-model")
+	bindFlagIfSet(v, cmd, "hugepages")
+	bindFlagIfSet(v, cmd, "memory-limit")
+	bindFlagIfSet(v, cmd, "cpu-limit")
+	bindFlagIfSet(v, cmd, "custom-userdata")
+	bindFlagIfSet(v, cmd, "readiness-level")
+	bindFlagIfSet(v, cmd, "affinity-from-file")
+	bindFlagIfSet(v, cmd, "anti-affinity-weight")
+	bindFlagIfSet(v, cmd, "data-source-url")
+	bindFlagIfSet(v, cmd, "data-source-pvc")
+	bindFlagIfSet(v, cmd, "data-fs")
+	bindFlagIfSet(v, cmd, "storage-class")
+	bindFlagIfSet(v, cmd, "access-mode")
+	bindFlagIfSet(v, cmd, "syslog-server")
+	bindFlagIfSet(v, cmd, "block-size")
+	bindFlagIfSet(v, cmd, "git-sha")
+	bindFlagIfSet(v, cmd, "git-branch")
+	bindFlagIfSet(v, cmd, "ci-url")
 
 	if cmd.Flags().Changed("cpu-cores") {
 		val, _ := cmd.Flags().GetInt("cpu-cores")
@@ -128,6 +561,94 @@ func LoadConfig(cmd *cobra.Command) (*Config, error) {
 		val, _ := cmd.Flags().GetBool("verbose")
 		v.Set("verbose", val)
 	}
+	if cmd.Flags().Changed("warmup") {
+		val, _ := cmd.Flags().GetDuration("warmup")
+		v.Set("warmup", val)
+	}
+	if cmd.Flags().Changed("stagger") {
+		val, _ := cmd.Flags().GetDuration("stagger")
+		v.Set("stagger", val)
+	}
+	if cmd.Flags().Changed("deadline") {
+		val, _ := cmd.Flags().GetDuration("deadline")
+		v.Set("deadline", val)
+	}
+	if cmd.Flags().Changed("skip-connect-check") {
+		val, _ := cmd.Flags().GetBool("skip-connect-check")
+		v.Set("skip-connect-check", val)
+	}
+	if cmd.Flags().Changed("skip-preflight") {
+		val, _ := cmd.Flags().GetBool("skip-preflight")
+		v.Set("skip-preflight", val)
+	}
+	if cmd.Flags().Changed("check-quota") {
+		val, _ := cmd.Flags().GetBool("check-quota")
+		v.Set("check-quota", val)
+	}
+	if cmd.Flags().Changed("label-run-with-git") {
+		val, _ := cmd.Flags().GetBool("label-run-with-git")
+		v.Set("label-run-with-git", val)
+	}
+	if cmd.Flags().Changed("start-stopped") {
+		val, _ := cmd.Flags().GetBool("start-stopped")
+		v.Set("start-stopped", val)
+	}
+	if cmd.Flags().Changed("create-concurrency") {
+		val, _ := cmd.Flags().GetInt("create-concurrency")
+		v.Set("create-concurrency", val)
+	}
+	if cmd.Flags().Changed("create-retries") {
+		val, _ := cmd.Flags().GetInt("create-retries")
+		v.Set("create-retries", val)
+	}
+	if cmd.Flags().Changed("create-backoff") {
+		val, _ := cmd.Flags().GetDuration("create-backoff")
+		v.Set("create-backoff", val)
+	}
+	if cmd.Flags().Changed("compress-cloud-init") {
+		val, _ := cmd.Flags().GetBool("compress-cloud-init")
+		v.Set("compress-cloud-init", val)
+	}
+	if cmd.Flags().Changed("workload-probes") {
+		val, _ := cmd.Flags().GetBool("workload-probes")
+		v.Set("workload-probes", val)
+	}
+	if cmd.Flags().Changed("clients-per-server") {
+		val, _ := cmd.Flags().GetInt("clients-per-server")
+		v.Set("clients-per-server", val)
+	}
+	if cmd.Flags().Changed("dedicated-cpu") {
+		val, _ := cmd.Flags().GetBool("dedicated-cpu")
+		v.Set("dedicated-cpu", val)
+	}
+	if cmd.Flags().Changed("overcommit-guest-overhead") {
+		val, _ := cmd.Flags().GetBool("overcommit-guest-overhead")
+		v.Set("overcommit-guest-overhead", val)
+	}
+	if cmd.Flags().Changed("overlay-override") {
+		val, _ := cmd.Flags().GetBool("overlay-override")
+		v.Set("overlay-override", val)
+	}
+	if cmd.Flags().Changed("read-only-root") {
+		val, _ := cmd.Flags().GetBool("read-only-root")
+		v.Set("read-only-root", val)
+	}
+	if cmd.Flags().Changed("network-policy") {
+		val, _ := cmd.Flags().GetBool("network-policy")
+		v.Set("network-policy", val)
+	}
+	if cmd.Flags().Changed("namespace-per-workload") {
+		val, _ := cmd.Flags().GetBool("namespace-per-workload")
+		v.Set("namespace-per-workload", val)
+	}
+	if cmd.Flags().Changed("cpu-sockets") {
+		val, _ := cmd.Flags().GetInt("cpu-sockets")
+		v.Set("cpu-sockets", val)
+	}
+	if cmd.Flags().Changed("cpu-threads") {
+		val, _ := cmd.Flags().GetInt("cpu-threads")
+		v.Set("cpu-threads", val)
+	}
 	if cmd.Flags().Changed("no-wait") {
 		val, _ := cmd.Flags().GetBool("no-wait")
 		v.Set("wait-for-ready", !val)
@@ -137,22 +658,68 @@ func LoadConfig(cmd *cobra.Command) (*Config, error) {
 	cfg := &Config{}
 	cfg.Namespace = v.GetString("namespace")
 	cfg.ContainerDiskImage = v.GetString("container-disk-image")
+	cfg.ImagePullSecret = v.GetString("image-pull-secret")
 	cfg.DataDiskSize = v.GetString("data-disk-size")
 	cfg.CPUCores = v.GetInt("cpu-cores")
 	cfg.Memory = v.GetString("memory")
 	cfg.KubeconfigPath = v.GetString("kubeconfig")
+	cfg.KubeContext = v.GetString("context")
 	cfg.CleanupMode = v.GetString("cleanup-mode")
 	cfg.WaitForReady = v.GetBool("wait-for-ready")
+	cfg.StartStopped = v.GetBool("start-stopped")
+	// Stopped VMs never become ready, so there is nothing to wait for
+	if cfg.StartStopped {
+		cfg.WaitForReady = false
+	}
 	cfg.ReadyTimeoutSeconds = v.GetInt("timeout")
+	cfg.ReadinessLevel = v.GetString("readiness-level")
+	switch cfg.ReadinessLevel {
+	case constants.ReadinessPhase, constants.ReadinessAgent, constants.ReadinessReady:
+	default:
+		return nil, fmt.Errorf("readiness-level must be phase, agent, or ready, got %q", cfg.ReadinessLevel)
+	}
 	cfg.DryRun = v.GetBool("dry-run")
 	cfg.Verbose = v.GetBool("verbose")
 	cfg.SSHUser = v.GetString("ssh-user")
 	cfg.SSHPassword = v.GetString("ssh-password")
 	cfg.AuditEnabled = v.GetBool("audit")
 	cfg.AuditDBPath = v.GetString("audit-db")
+	cfg.AuditDSN = v.GetString("audit-dsn")
+	cfg.AuditDBPerContext = v.GetBool("audit-db-per-context")
+	cfg.Warmup = v.GetDuration("warmup")
+	if cfg.Warmup < 0 {
+		return nil, fmt.Errorf("warmup must not be negative, got %s", cfg.Warmup)
+	}
+	cfg.Stagger = v.GetDuration("stagger")
+	if cfg.Stagger < 0 {
+		return nil, fmt.Errorf("stagger must not be negative, got %s", cfg.Stagger)
+	}
+	cfg.Deadline = v.GetDuration("deadline")
+	if cfg.Deadline < 0 {
+		return nil, fmt.Errorf("deadline must not be negative, got %s", cfg.Deadline)
+	}
+	cfg.SkipConnectCheck = v.GetBool("skip-connect-check")
+	cfg.SkipPreflight = v.GetBool("skip-preflight")
+	cfg.CheckQuota = v.GetBool("check-quota")
+	cfg.CreateConcurrency = v.GetInt("create-concurrency")
+	if cfg.CreateConcurrency < 1 {
+		return nil, fmt.Errorf("create-concurrency must be at least 1, got %d", cfg.CreateConcurrency)
+	}
+	cfg.CreateRetries = v.GetInt("create-retries")
+	if cfg.CreateRetries < 0 {
+		return nil, fmt.Errorf("create-retries must not be negative, got %d", cfg.CreateRetries)
+	}
+	cfg.CreateBackoff = v.GetDuration("create-backoff")
+	if cfg.CreateBackoff <= 0 {
+		return nil, fmt.Errorf("create-backoff must be positive, got %s", cfg.CreateBackoff)
+	}
 
 	// Handle SSH authorized keys: CLI flags, env var (comma-split), or YAML list
-	cfg.SSHAuthorizedKeys = resolveSSHKeys(v, cmd)
+	sshKeys, err := resolveSSHKeys(v, cmd)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SSHAuthorizedKeys = sshKeys
 
 	// Unmarshal workloads map if present in config file
 	workloads := make(map[string]WorkloadConfig)
@@ -161,11 +728,573 @@ func LoadConfig(cmd *cobra.Command) (*Config, error) {
 			return nil, fmt.Errorf("parsing workloads config: %w", err)
 		}
 	}
+	for name, wl := range workloads {
+		if err := wl.validate(name); err != nil {
+			return nil, err
+		}
+		// An entry only disables its workload with an explicit enabled: false
+		if !v.IsSet("workloads." + name + ".enabled") {
+			wl.Enabled = true
+			workloads[name] = wl
+		}
+	}
 	cfg.Workloads = workloads
 
+	// Per-workload and per-role image overrides, keyed "<workload>" or
+	// "<workload>-<role>"; the keys are checked against the registry at run time
+	images := make(map[string]string)
+	if v.IsSet("images") {
+		if err := v.UnmarshalKey("images", &images); err != nil {
+			return nil, fmt.Errorf("parsing images config: %w", err)
+		}
+	}
+	cfg.Images = images
+	for name, wl := range workloads {
+		if image := images[name]; wl.ContainerDiskImage != "" && image != "" && image != wl.ContainerDiskImage {
+			return nil, fmt.Errorf("workloads.%s.container-disk-image %q conflicts with images.%s %q", name, wl.ContainerDiskImage, name, image)
+		}
+	}
+
+	// Unmarshal config-defined custom workloads and their template values
+	customWorkloads := make(map[string]WorkloadTemplate)
+	if v.IsSet("custom-workloads") {
+		if err := v.UnmarshalKey("custom-workloads", &customWorkloads); err != nil {
+			return nil, fmt.Errorf("parsing custom-workloads config: %w", err)
+		}
+	}
+	cfg.CustomWorkloads = customWorkloads
+
+	cfg.TemplateValuesPath = v.GetString("template-values")
+	values, err := loadTemplateValues(cfg.TemplateValuesPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.TemplateValues = values
+
+	cfg.SeedSQLPath = v.GetString("seed-sql")
+	seedSQL, err := loadSeedSQL(cfg.SeedSQLPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SeedSQL = seedSQL
+
+	cfg.ClockTimezone = v.GetString("clock-timezone")
+	cfg.CompressCloudInit = v.GetBool("compress-cloud-init")
+	cfg.WorkloadProbes = v.GetBool("workload-probes")
+	cfg.ClientsPerServer = v.GetInt("clients-per-server")
+	if cfg.ClientsPerServer < 1 {
+		return nil, fmt.Errorf("clients-per-server must be at least 1, got %d", cfg.ClientsPerServer)
+	}
+	cfg.CPUModel = v.GetString("cpu-model")
+	cfg.CustomUserdata = v.GetString("custom-userdata")
+	cfg.CloudInitOverlayPath = v.GetString("cloud-init-overlay")
+	overlay, err := loadCloudInitOverlay(cfg.CloudInitOverlayPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.CloudInitOverlay = overlay
+	cfg.OverlayOverride = v.GetBool("overlay-override")
+	cfg.DedicatedCPU = v.GetBool("dedicated-cpu")
+	cfg.CPUSockets = v.GetInt("cpu-sockets")
+	cfg.CPUThreads = v.GetInt("cpu-threads")
+	if cfg.CPUSockets < 0 || cfg.CPUThreads < 0 {
+		return nil, fmt.Errorf("cpu-sockets and cpu-threads must not be negative")
+	}
+	cfg.Hugepages = v.GetString("hugepages")
+	cfg.MemoryLimit = v.GetString("memory-limit")
+	cfg.CPULimit = v.GetString("cpu-limit")
+	cfg.OvercommitGuestOverhead = v.GetBool("overcommit-guest-overhead")
+	cfg.ReadOnlyRoot = v.GetBool("read-only-root")
+	cfg.NetworkPolicy = v.GetBool("network-policy")
+	cfg.NamespacePerWorkload = v.GetBool("namespace-per-workload")
+	if err := validateHugepages("hugepages", cfg.Hugepages); err != nil {
+		return nil, err
+	}
+	timers, err := resolveTimers(v, cmd)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Timers = timers
+	cfg.NodeSelector = resolveNodeSelector(v, cmd)
+	tolerations, err := resolveTolerations(v, cmd)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Tolerations = tolerations
+	gpus, err := resolveGPUs(v, cmd)
+	if err != nil {
+		return nil, err
+	}
+	cfg.GPUs = gpus
+	multusNetworks, err := resolveMultusNetworks(v, cmd)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MultusNetworks = multusNetworks
+	cfg.AffinityFile = v.GetString("affinity-from-file")
+	affinity, err := loadAffinity(cfg.AffinityFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Affinity = affinity
+	weight, err := parseAntiAffinityWeight(v.GetString("anti-affinity-weight"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.AntiAffinityWeight = weight
+	cfg.DataSourceURL = v.GetString("data-source-url")
+	cfg.DataSourcePVC = v.GetString("data-source-pvc")
+	if cfg.DataSourceURL != "" && cfg.DataSourcePVC != "" {
+		return nil, fmt.Errorf("data-source-url and data-source-pvc are mutually exclusive")
+	}
+	if cfg.DataSourceURL != "" && !strings.Contains(cfg.DataSourceURL, "://") {
+		// CDI registry imports need a scheme; a bare image reference means docker://
+		cfg.DataSourceURL = "docker://" + cfg.DataSourceURL
+	}
+	cfg.DataFSSpec = v.GetString("data-fs")
+	dataFSPVC, err := parseDataFS(cfg.DataFSSpec)
+	if err != nil {
+		return nil, err
+	}
+	if dataFSPVC != "" && (cfg.DataSourceURL != "" || cfg.DataSourcePVC != "") {
+		return nil, fmt.Errorf("data-fs cannot be combined with data-source-url or data-source-pvc")
+	}
+	cfg.DataFSPVC = dataFSPVC
+	cfg.StorageClass = v.GetString("storage-class")
+	cfg.AccessMode = v.GetString("access-mode")
+	switch corev1.PersistentVolumeAccessMode(cfg.AccessMode) {
+	case "", corev1.ReadWriteOnce, corev1.ReadWriteMany, corev1.ReadOnlyMany, corev1.ReadWriteOncePod:
+	default:
+		return nil, fmt.Errorf("access-mode must be ReadWriteOnce, ReadWriteMany, ReadOnlyMany, or ReadWriteOncePod, got %q", cfg.AccessMode)
+	}
+	cfg.SyslogServer = v.GetString("syslog-server")
+	if err := validateSyslogServer(cfg.SyslogServer); err != nil {
+		return nil, err
+	}
+	cfg.BlockSizeSpec = v.GetString("block-size")
+	blockSize, err := parseBlockSize(cfg.BlockSizeSpec)
+	if err != nil {
+		return nil, err
+	}
+	cfg.BlockSize = blockSize
+
+	// Explicit values win; --label-run-with-git fills the rest from CI env vars
+	cfg.LabelRunWithGit = v.GetBool("label-run-with-git")
+	cfg.GitSHA = v.GetString("git-sha")
+	cfg.GitBranch = v.GetString("git-branch")
+	cfg.CIURL = v.GetString("ci-url")
+	if cfg.LabelRunWithGit {
+		detected := DetectCI(os.Getenv)
+		if cfg.GitSHA == "" {
+			cfg.GitSHA = detected.GitSHA
+		}
+		if cfg.GitBranch == "" {
+			cfg.GitBranch = detected.GitBranch
+		}
+		if cfg.CIURL == "" {
+			cfg.CIURL = detected.CIURL
+		}
+	}
+
 	return cfg, nil
 }
 
+// validateHugepages checks that value, set for field, is a hugepages page
+// size KubeVirt supports. Empty means hugepages are not used.
+func validateHugepages(field, value string) error {
+	switch value {
+	case "", "2Mi", "1Gi":
+		return nil
+	}
+	return fmt.Errorf("%s must be 2Mi or 1Gi, got %q", field, value)
+}
+
+// validTimers lists the guest timers that can be toggled with --timers.
+var validTimers = []string{"hpet", "hyperv", "kvm", "pit"}
+
+// resolveTimers returns the guest timer overrides from the --timers flag,
+// the VIRTWORK_TIMERS env var ("name=bool" pairs, comma-separated), or the
+// YAML map, in that order. Unknown timer names and non-boolean values are
+// rejected.
+func resolveTimers(v *viper.Viper, cmd *cobra.Command) (map[string]bool, error) {
+	raw := make(map[string]string)
+	switch val := v.Get("timers").(type) {
+	case string:
+		for _, pair := range strings.Split(val, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(pair, "=")
+			raw[name] = value
+		}
+	case map[string]interface{}:
+		for name, value := range val {
+			raw[name] = fmt.Sprint(value)
+		}
+	}
+	if cmd.Flags().Changed("timers") {
+		raw, _ = cmd.Flags().GetStringToString("timers")
+	}
+
+	timers := make(map[string]bool, len(raw))
+	for name, value := range raw {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(validTimers, name) {
+			return nil, fmt.Errorf("unknown timer %q; valid timers: %s", name, strings.Join(validTimers, ", "))
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("timer %q must be true or false, got %q", name, value)
+		}
+		timers[name] = enabled
+	}
+	return timers, nil
+}
+
+// resolveNodeSelector returns the node selector from the --node-selector
+// flag, the VIRTWORK_NODE_SELECTOR env var ("key=value" pairs,
+// comma-separated), or the YAML map, in that order. It returns nil when no
+// labels are set so that scheduling stays unconstrained.
+func resolveNodeSelector(v *viper.Viper, cmd *cobra.Command) map[string]string {
+	selector := make(map[string]string)
+	switch val := v.Get("node-selector").(type) {
+	case string:
+		for _, pair := range strings.Split(val, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			key, value, _ := strings.Cut(pair, "=")
+			selector[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	case map[string]interface{}:
+		for key, value := range val {
+			selector[key] = fmt.Sprint(value)
+		}
+	}
+	if cmd.Flags().Changed("node-selector") {
+		selector, _ = cmd.Flags().GetStringToString("node-selector")
+	}
+	if len(selector) == 0 {
+		return nil
+	}
+	return selector
+}
+
+// validTaintEffects lists the effects a --toleration may name.
+var validTaintEffects = []corev1.TaintEffect{
+	corev1.TaintEffectNoSchedule,
+	corev1.TaintEffectPreferNoSchedule,
+	corev1.TaintEffectNoExecute,
+}
+
+// resolveTolerations returns the tolerations from repeated --toleration
+// flags, the VIRTWORK_TOLERATIONS env var (comma-separated), or the YAML
+// list, in that order. It returns nil when none are set.
+func resolveTolerations(v *viper.Viper, cmd *cobra.Command) ([]corev1.Toleration, error) {
+	var raw []string
+	switch val := v.Get("tolerations").(type) {
+	case string:
+		raw = strings.Split(val, ",")
+	case []interface{}:
+		for _, item := range val {
+			raw = append(raw, fmt.Sprint(item))
+		}
+	}
+	if cmd.Flags().Changed("toleration") {
+		raw, _ = cmd.Flags().GetStringArray("toleration")
+	}
+
+	var tolerations []corev1.Toleration
+	for _, spec := range raw {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		t, err := parseToleration(spec)
+		if err != nil {
+			return nil, err
+		}
+		tolerations = append(tolerations, t)
+	}
+	return tolerations, nil
+}
+
+// resolveGPUs returns the GPU device resource names from repeated --gpu
+// flags, the VIRTWORK_GPUS env var (comma-separated), or the YAML list, in
+// that order. It returns nil when none are set.
+func resolveGPUs(v *viper.Viper, cmd *cobra.Command) ([]string, error) {
+	var raw []string
+	switch val := v.Get("gpus").(type) {
+	case string:
+		raw = strings.Split(val, ",")
+	case []interface{}:
+		for _, item := range val {
+			raw = append(raw, fmt.Sprint(item))
+		}
+	}
+	if cmd.Flags().Changed("gpu") {
+		raw, _ = cmd.Flags().GetStringArray("gpu")
+	}
+
+	var gpus []string
+	for _, name := range raw {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		// Device plugins advertise GPUs as extended resources, which
+		// always carry a domain prefix.
+		domain, device, _ := strings.Cut(name, "/")
+		if domain == "" || device == "" {
+			return nil, fmt.Errorf("gpu %q must be a device resource name of the form <vendor-domain>/<device>, e.g. nvidia.com/GA102GL_A10", name)
+		}
+		gpus = append(gpus, name)
+	}
+	return gpus, nil
+}
+
+// resolveMultusNetworks returns the NetworkAttachmentDefinitions from
+// repeated --multus-network flags, the VIRTWORK_MULTUS_NETWORKS env var
+// (comma-separated), or the YAML list, in that order. Each is "name" or
+// "namespace/name". It returns nil when none are set.
+func resolveMultusNetworks(v *viper.Viper, cmd *cobra.Command) ([]string, error) {
+	var raw []string
+	switch val := v.Get("multus-networks").(type) {
+	case string:
+		raw = strings.Split(val, ",")
+	case []interface{}:
+		for _, item := range val {
+			raw = append(raw, fmt.Sprint(item))
+		}
+	}
+	if cmd.Flags().Changed("multus-network") {
+		raw, _ = cmd.Flags().GetStringArray("multus-network")
+	}
+
+	var nads []string
+	for _, nad := range raw {
+		nad = strings.TrimSpace(nad)
+		if nad == "" {
+			continue
+		}
+		name := nad
+		if namespace, n, ok := strings.Cut(nad, "/"); ok {
+			if namespace == "" || strings.Contains(n, "/") {
+				return nil, fmt.Errorf("multus-network %q must be a NetworkAttachmentDefinition name or namespace/name", nad)
+			}
+			name = n
+		}
+		if name == "" {
+			return nil, fmt.Errorf("multus-network %q must be a NetworkAttachmentDefinition name or namespace/name", nad)
+		}
+		if slices.Contains(nads, nad) {
+			return nil, fmt.Errorf("multus-network %q is given more than once", nad)
+		}
+		nads = append(nads, nad)
+	}
+	return nads, nil
+}
+
+// parseToleration parses "key[=value][:effect]", the form kubectl taint
+// uses. A value makes the toleration match with the Equal operator, otherwise
+// any value of the key is tolerated (Exists). Without an effect all effects
+// are tolerated.
+func parseToleration(spec string) (corev1.Toleration, error) {
+	spec = strings.TrimSpace(spec)
+	rest, effect, _ := strings.Cut(spec, ":")
+	key, value, hasValue := strings.Cut(rest, "=")
+	if key == "" {
+		return corev1.Toleration{}, fmt.Errorf("toleration %q must have a key", spec)
+	}
+
+	t := corev1.Toleration{Key: key, Operator: corev1.TolerationOpExists}
+	if hasValue {
+		t.Operator = corev1.TolerationOpEqual
+		t.Value = value
+	}
+	if effect != "" {
+		t.Effect = corev1.TaintEffect(effect)
+		if !slices.Contains(validTaintEffects, t.Effect) {
+			return corev1.Toleration{}, fmt.Errorf("toleration %q has unknown effect %q; valid effects: NoSchedule, PreferNoSchedule, NoExecute", spec, effect)
+		}
+	}
+	return t, nil
+}
+
+// formatToleration renders a toleration in the form parseToleration accepts.
+func formatToleration(t corev1.Toleration) string {
+	s := t.Key
+	if t.Operator == corev1.TolerationOpEqual {
+		s += "=" + t.Value
+	}
+	if t.Effect != "" {
+		s += ":" + string(t.Effect)
+	}
+	return s
+}
+
+// loadAffinity reads a corev1.Affinity from a YAML file, using the field
+// names of a pod spec's affinity (nodeAffinity, podAntiAffinity, ...).
+// Unknown fields are rejected so that a typo does not silently drop a rule.
+// An empty path yields nil.
+func loadAffinity(path string) (*corev1.Affinity, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading affinity file: %w", err)
+	}
+	affinity := &corev1.Affinity{}
+	if err := sigyaml.UnmarshalStrict(data, affinity); err != nil {
+		return nil, fmt.Errorf("parsing affinity file %s: %w", path, err)
+	}
+	return affinity, nil
+}
+
+// parseAntiAffinityWeight normalizes --anti-affinity-weight: "required"
+// stays as is, "preferred" becomes constants.DefaultAntiAffinityWeight, and a
+// preferred weight must be between 1 and 100. An empty value leaves the
+// anti-affinity term off.
+func parseAntiAffinityWeight(s string) (string, error) {
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "", "required":
+		return s, nil
+	case "preferred":
+		return strconv.Itoa(constants.DefaultAntiAffinityWeight), nil
+	}
+	weight, err := strconv.Atoi(s)
+	if err != nil || weight < 1 || weight > 100 {
+		return "", fmt.Errorf("anti-affinity-weight must be required, preferred, or a weight between 1 and 100, got %q", s)
+	}
+	return strconv.Itoa(weight), nil
+}
+
+// validateSyslogServer checks that a --syslog-server address is host:port
+// with a port between 1 and 65535. An empty address disables forwarding.
+func validateSyslogServer(addr string) error {
+	if addr == "" {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return fmt.Errorf("syslog-server must be host:port, got %q", addr)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("syslog-server port must be between 1 and 65535, got %q", port)
+	}
+	return nil
+}
+
+// parseDataFS parses --data-fs, "pvc=<name>", and returns the PVC name. The
+// PVC must be in the run's namespace. An empty spec returns "".
+func parseDataFS(spec string) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return "", nil
+	}
+	name, ok := strings.CutPrefix(spec, "pvc=")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("data-fs must be pvc=<name>, got %q", spec)
+	}
+	return name, nil
+}
+
+// parseBlockSize parses --block-size: "match-volume" presents each data
+// disk with the block size of its volume, and "logical=N,physical=N" sets
+// custom sizes. Both sizes must be powers of two, logical at least 512 and
+// physical at least logical. An empty spec leaves KubeVirt's default.
+func parseBlockSize(spec string) (*kubevirtv1.BlockSize, error) {
+	spec = strings.TrimSpace(spec)
+	switch spec {
+	case "":
+		return nil, nil
+	case "match-volume":
+		enabled := true
+		return &kubevirtv1.BlockSize{MatchVolume: &kubevirtv1.FeatureState{Enabled: &enabled}}, nil
+	}
+	sizes := make(map[string]uint)
+	for _, part := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || (key != "logical" && key != "physical") {
+			return nil, fmt.Errorf("block-size must be logical=N,physical=N or match-volume, got %q", spec)
+		}
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || n == 0 || n&(n-1) != 0 {
+			return nil, fmt.Errorf("block-size %s must be a power of two, got %q", key, value)
+		}
+		sizes[key] = uint(n)
+	}
+	logical, hasLogical := sizes["logical"]
+	physical, hasPhysical := sizes["physical"]
+	if !hasLogical || !hasPhysical {
+		return nil, fmt.Errorf("block-size must set both logical and physical, got %q", spec)
+	}
+	if logical < 512 {
+		return nil, fmt.Errorf("block-size logical must be at least 512, got %d", logical)
+	}
+	if physical < logical {
+		return nil, fmt.Errorf("block-size physical (%d) must not be smaller than logical (%d)", physical, logical)
+	}
+	return &kubevirtv1.BlockSize{Custom: &kubevirtv1.CustomBlockSize{Logical: logical, Physical: physical}}, nil
+}
+
+// loadSeedSQL reads the SQL file applied by the database workload's setup
+// script. The file must exist and be no larger than constants.MaxSeedSQLSize
+// since it is embedded in cloud-init userdata. An empty path yields "".
+func loadSeedSQL(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("reading seed SQL: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("seed SQL %s is a directory", path)
+	}
+	if info.Size() > constants.MaxSeedSQLSize {
+		return "", fmt.Errorf("seed SQL %s is %d bytes; the limit is %d bytes", path, info.Size(), constants.MaxSeedSQLSize)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading seed SQL: %w", err)
+	}
+	return string(data), nil
+}
+
+// loadCloudInitOverlay reads the cloud-config merged into every workload's
+// cloud-init. An empty path yields "".
+func loadCloudInitOverlay(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading cloud-init overlay: %w", err)
+	}
+	return string(data), nil
+}
+
+// loadTemplateValues reads the YAML values file used to render custom
+// workload templates. An empty path yields an empty map.
+func loadTemplateValues(path string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if path == "" {
+		return values, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading template values: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("parsing template values %s: %w", path, err)
+	}
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	return values, nil
+}
+
 // bindFlagIfSet sets a Viper key from a Cobra flag only when the flag was explicitly provided.
 func bindFlagIfSet(v *viper.Viper, cmd *cobra.Command, name string) {
 	if cmd.Flags().Changed(name) {
@@ -175,15 +1304,27 @@ func bindFlagIfSet(v *viper.Viper, cmd *cobra.Command, name string) {
 }
 
 // resolveSSHKeys resolves SSH authorized keys from CLI flags, env vars, or config file.
-// Priority: CLI --ssh-key flags > VIRTWORK_SSH_AUTHORIZED_KEYS env var > YAML config.
-func resolveSSHKeys(v *viper.Viper, cmd *cobra.Command) []string {
+// Priority: CLI --ssh-key and --ssh-key-file flags (merged, --ssh-key first) >
+// VIRTWORK_SSH_AUTHORIZED_KEYS env var > YAML config.
+func resolveSSHKeys(v *viper.Viper, cmd *cobra.Command) ([]string, error) {
 	// CLI flags take highest priority
+	var keys []string
 	if cmd.Flags().Changed("ssh-key") {
-		keys, _ := cmd.Flags().GetStringSlice("ssh-key")
-		if len(keys) > 0 {
-			return keys
+		keys, _ = cmd.Flags().GetStringSlice("ssh-key")
+	}
+	if cmd.Flags().Changed("ssh-key-file") {
+		paths, _ := cmd.Flags().GetStringSlice("ssh-key-file")
+		for _, path := range paths {
+			fileKeys, err := readSSHKeyFile(path)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, fileKeys...)
 		}
 	}
+	if len(keys) > 0 {
+		return keys, nil
+	}
 
 	// Check env var with comma splitting
 	envVal := os.Getenv("VIRTWORK_SSH_AUTHORIZED_KEYS")
@@ -197,10 +1338,45 @@ func resolveSSHKeys(v *viper.Viper, cmd *cobra.Command) []string {
 			}
 		}
 		if len(keys) > 0 {
-			return keys
+			return keys, nil
 		}
 	}
 
 	// Fall back to YAML config list
-	return v.GetStringSlice("ssh-authorized-keys")
+	return v.GetStringSlice("ssh-authorized-keys"), nil
+}
+
+// sshKeyTypes are the key type prefixes accepted in an SSH public key file.
+var sshKeyTypes = []string{"ssh-", "ecdsa-sha2-", "sk-ssh-", "sk-ecdsa-sha2-"}
+
+// readSSHKeyFile reads the public keys in an --ssh-key-file, one per line as
+// in an authorized_keys file. Blank lines and # comments are skipped. The
+// file must hold at least one key, and every line must look like a public
+// key, so that passing a private key by mistake is caught.
+func readSSHKeyFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading ssh-key-file: %w", err)
+	}
+	var keys []string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "-----BEGIN") {
+			return nil, fmt.Errorf("ssh-key-file %s holds a private key; pass the public key (.pub) file", path)
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !slices.ContainsFunc(sshKeyTypes, func(prefix string) bool {
+			return strings.HasPrefix(fields[0], prefix)
+		}) {
+			return nil, fmt.Errorf("ssh-key-file %s line %d is not an SSH public key", path, i+1)
+		}
+		keys = append(keys, line)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("ssh-key-file %s holds no SSH public key", path)
+	}
+	return keys, nil
 }
@@ -0,0 +1,124 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secret values in a config dump.
+const redacted = "xxxxx"
+
+// dsnPassword matches the password in a key=value PostgreSQL DSN.
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('[^']*'|\S+)`)
+
+// Dump renders the resolved configuration as "yaml" or "json", keyed by the
+// same names the config file uses. The SSH password and the password in the
+// audit DSN are redacted. workloads replaces the config file's workloads
+// section so the dump shows the effective settings of each selected workload.
+func Dump(cfg *Config, workloads map[string]WorkloadConfig, format string) ([]byte, error) {
+	out := structToMap(reflect.ValueOf(*cfg))
+	out["workloads"] = toDumpValue(reflect.ValueOf(workloads))
+	out["timers"] = toDumpValue(reflect.ValueOf(cfg.Timers))
+	out["node-selector"] = toDumpValue(reflect.ValueOf(cfg.NodeSelector))
+	tolerations := make([]string, 0, len(cfg.Tolerations))
+	for _, t := range cfg.Tolerations {
+		tolerations = append(tolerations, formatToleration(t))
+	}
+	out["tolerations"] = tolerations
+	if cfg.SSHPassword != "" {
+		out["ssh-password"] = redacted
+	}
+	out["audit-dsn"] = redactDSN(cfg.AuditDSN)
+
+	switch format {
+	case "yaml":
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(out); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case "json":
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("invalid config dump format %q: must be yaml or json", format)
+	}
+}
+
+// structToMap converts a struct to a map keyed by its mapstructure tags.
+// Fields tagged "-" are derived from other fields and are omitted, as are
+// zero-valued fields tagged omitempty.
+func structToMap(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{})
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key, opts, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		if opts == "omitempty" && v.Field(i).IsZero() {
+			continue
+		}
+		out[key] = toDumpValue(v.Field(i))
+	}
+	return out
+}
+
+// toDumpValue converts a config value to plain maps, slices, and scalars
+// that marshal cleanly as YAML and JSON.
+func toDumpValue(v reflect.Value) interface{} {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return structToMap(v)
+	case reflect.Map:
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = toDumpValue(iter.Value())
+		}
+		return out
+	case reflect.Slice:
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = toDumpValue(v.Index(i))
+		}
+		return out
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return toDumpValue(v.Elem())
+	default:
+		return v.Interface()
+	}
+}
+
+// redactDSN hides the password in a URL or key=value PostgreSQL DSN.
+func redactDSN(dsn string) string {
+	if strings.Contains(dsn, "://") {
+		if u, err := url.Parse(dsn); err == nil {
+			return u.Redacted()
+		}
+		return redacted
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Validate checks the resource settings VM specs are built from, so that a
+// malformed value fails before anything is created rather than panicking
+// while the specs are built. It checks the global CPU cores, memory, data
+// disk size, and limits and each workload's overrides, and returns one error
+// listing every invalid field. The memory limit must be at least every memory
+// request it applies to. The CPU limit is only checked for format, since
+// without dedicated CPUs the VM makes no explicit CPU request.
+func Validate(cfg *Config) error {
+	var problems []string
+	if cfg.CPUCores < 1 {
+		problems = append(problems, fmt.Sprintf("cpu-cores must be at least 1, got %d", cfg.CPUCores))
+	}
+	if msg := checkQuantity("memory", cfg.Memory); msg != "" {
+		problems = append(problems, msg)
+	}
+	if msg := checkQuantity("data-disk-size", cfg.DataDiskSize); msg != "" {
+		problems = append(problems, msg)
+	}
+	memoryLimitOK := false
+	if cfg.MemoryLimit != "" {
+		msg := checkQuantity("memory-limit", cfg.MemoryLimit)
+		if msg != "" {
+			problems = append(problems, msg)
+		}
+		memoryLimitOK = msg == ""
+	}
+	if cfg.CPULimit != "" {
+		if q, err := resource.ParseQuantity(cfg.CPULimit); err != nil || q.Sign() <= 0 {
+			problems = append(problems, fmt.Sprintf("cpu-limit %q is not a positive CPU quantity such as 2 or 1500m", cfg.CPULimit))
+		}
+	}
+	if cfg.DedicatedCPU && (cfg.MemoryLimit != "" || cfg.CPULimit != "") {
+		problems = append(problems, "memory-limit and cpu-limit cannot be combined with dedicated-cpu, which sets the limits equal to the requests")
+	}
+	if memoryLimitOK {
+		if msg := checkBelowLimit("memory", cfg.Memory, cfg.MemoryLimit); msg != "" {
+			problems = append(problems, msg)
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Workloads))
+	for name := range cfg.Workloads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		wl := cfg.Workloads[name]
+		if wl.CPUCores < 0 {
+			problems = append(problems, fmt.Sprintf("workloads.%s.cpu-cores must be at least 1, got %d", name, wl.CPUCores))
+		}
+		if wl.Memory != "" {
+			if msg := checkQuantity("workloads."+name+".memory", wl.Memory); msg != "" {
+				problems = append(problems, msg)
+			} else if memoryLimitOK {
+				if msg := checkBelowLimit("workloads."+name+".memory", wl.Memory, cfg.MemoryLimit); msg != "" {
+					problems = append(problems, msg)
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkQuantity returns a message naming field when value is not a positive
+// Kubernetes quantity such as 2Gi or 512Mi, and "" otherwise.
+func checkQuantity(field, value string) string {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return fmt.Sprintf("%s %q is not a valid quantity (use a Kubernetes quantity such as 2Gi or 512Mi)", field, value)
+	}
+	if q.Sign() <= 0 {
+		return fmt.Sprintf("%s must be positive, got %q", field, value)
+	}
+	return ""
+}
+
+// checkBelowLimit returns a message naming field when the memory request
+// value exceeds the memory limit, and "" otherwise or when value is not a
+// valid quantity, which checkQuantity reports.
+func checkBelowLimit(field, value, limit string) string {
+	request, err := resource.ParseQuantity(value)
+	if err != nil {
+		return ""
+	}
+	if request.Cmp(resource.MustParse(limit)) > 0 {
+		return fmt.Sprintf("memory-limit %s is below the %s request of %s", limit, field, value)
+	}
+	return ""
+}
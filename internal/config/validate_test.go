@@ -0,0 +1,91 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/config"
+)
+
+var _ = Describe("Validate", func() {
+	load := func(args map[string]string, yamlBody string) *config.Config {
+		cmd := newTestCommand()
+		if yamlBody != "" {
+			Expect(cmd.Flags().Set("config", writeConfigFile(GinkgoT().TempDir(), yamlBody))).To(Succeed())
+		}
+		for name, value := range args {
+			Expect(cmd.Flags().Set(name, value)).To(Succeed())
+		}
+		cfg, err := config.LoadConfig(cmd)
+		Expect(err).NotTo(HaveOccurred())
+		return cfg
+	}
+
+	It("should accept the defaults", func() {
+		Expect(config.Validate(load(nil, ""))).To(Succeed())
+	})
+
+	It("should accept valid overrides", func() {
+		cfg := load(map[string]string{"memory": "512Mi", "data-disk-size": "1.5Ti", "cpu-cores": "8"}, `
+workloads:
+  database:
+    memory: 16G
+    cpu-cores: 4
+`)
+		Expect(config.Validate(cfg)).To(Succeed())
+	})
+
+	DescribeTable("should reject a malformed setting with the field named",
+		func(args map[string]string, yamlBody, msg string) {
+			err := config.Validate(load(args, yamlBody))
+			Expect(err).To(MatchError(ContainSubstring(msg)))
+		},
+		Entry("memory with a byte suffix", map[string]string{"memory": "2GB"}, "",
+			`memory "2GB" is not a valid quantity`),
+		Entry("memory without a number", map[string]string{"memory": "lots"}, "",
+			`memory "lots" is not a valid quantity`),
+		Entry("zero memory", map[string]string{"memory": "0"}, "",
+			`memory must be positive, got "0"`),
+		Entry("data disk size", map[string]string{"data-disk-size": "10 Gi"}, "",
+			`data-disk-size "10 Gi" is not a valid quantity`),
+		Entry("cpu cores", map[string]string{"cpu-cores": "-2"}, "",
+			"cpu-cores must be at least 1, got -2"),
+		Entry("workload memory", nil, "workloads:\n  cpu:\n    memory: 4gb\n",
+			`workloads.cpu.memory "4gb" is not a valid quantity`),
+		Entry("workload cpu cores", nil, "workloads:\n  cpu:\n    cpu-cores: -1\n",
+			"workloads.cpu.cpu-cores must be at least 1, got -1"),
+		Entry("memory limit", map[string]string{"memory-limit": "4GB"}, "",
+			`memory-limit "4GB" is not a valid quantity`),
+		Entry("memory limit below the request", map[string]string{"memory": "4Gi", "memory-limit": "2Gi"}, "",
+			"memory-limit 2Gi is below the memory request of 4Gi"),
+		Entry("memory limit below a workload request", map[string]string{"memory-limit": "4Gi"},
+			"workloads:\n  database:\n    memory: 8Gi\n",
+			"memory-limit 4Gi is below the workloads.database.memory request of 8Gi"),
+		Entry("cpu limit", map[string]string{"cpu-limit": "two"}, "",
+			`cpu-limit "two" is not a positive CPU quantity`),
+		Entry("limits with dedicated CPUs", map[string]string{"cpu-limit": "2", "dedicated-cpu": "true"}, "",
+			"memory-limit and cpu-limit cannot be combined with dedicated-cpu"),
+	)
+
+	It("should accept limits at or above the requests", func() {
+		cfg := load(map[string]string{"memory": "2Gi", "memory-limit": "2Gi", "cpu-limit": "1500m"},
+			"workloads:\n  database:\n    memory: 1Gi\n")
+		Expect(config.Validate(cfg)).To(Succeed())
+	})
+
+	It("should list every invalid field in one error", func() {
+		cfg := load(map[string]string{"memory": "2GB", "data-disk-size": "big"},
+			"workloads:\n  disk:\n    memory: 1 Gi\n  cpu:\n    memory: x\n")
+
+		err := config.Validate(cfg)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("invalid configuration: "))
+		Expect(err.Error()).To(ContainSubstring(`memory "2GB"`))
+		Expect(err.Error()).To(ContainSubstring(`data-disk-size "big"`))
+		Expect(err.Error()).To(ContainSubstring(`workloads.cpu.memory "x"`))
+		Expect(err.Error()).To(ContainSubstring(`workloads.disk.memory "1 Gi"`))
+	})
+})
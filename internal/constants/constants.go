@@ -37,11 +37,51 @@ const (
 	LabelComponent = "app.kubernetes.io/component"
 	ManagedByValue = "virtwork"
 	LabelRunID     = "virtwork/run-id"
+	LabelRole      = "virtwork/role"
+)
+
+// CI metadata annotations stamped on created resources by
+// --label-run-with-git and the --git-sha, --git-branch, and --ci-url flags.
+const (
+	AnnotationGitSHA    = "virtwork/git-sha"
+	AnnotationGitBranch = "virtwork/git-branch"
+	AnnotationCIURL     = "virtwork/ci-url"
 )
 
 // Audit defaults.
 const (
 	DefaultAuditDBPath = "virtwork.db"
+	// AuditContextDir is the directory under the user's home that holds the
+	// per-context audit databases selected by --audit-db-per-context.
+	AuditContextDir = ".virtwork"
+)
+
+// Scheduling defaults.
+const (
+	// DefaultAntiAffinityWeight is the weight of the preferred anti-affinity
+	// term synthesized by --anti-affinity-weight=preferred.
+	DefaultAntiAffinityWeight = 100
+)
+
+// Cleanup defaults.
+const (
+	// DefaultCleanupConcurrency is how many deletions of one resource kind
+	// cleanup runs at a time.
+	DefaultCleanupConcurrency = 10
+)
+
+// Database workload limits.
+const (
+	// MaxSeedSQLSize caps the seed SQL file, which is embedded in the VM's
+	// cloud-init userdata and therefore stored in the VirtualMachine object.
+	MaxSeedSQLSize = 256 * 1024
+)
+
+// Readiness levels selectable with --readiness-level.
+const (
+	ReadinessPhase = "phase" // VMI phase is Running
+	ReadinessAgent = "agent" // and the guest agent is connected
+	ReadinessReady = "ready" // and the VMI Ready condition is true
 )
 
 // Polling defaults for VMI readiness.
@@ -49,3 +89,38 @@ const (
 	DefaultReadyTimeout = 600 * time.Second
 	DefaultPollInterval = 15 * time.Second
 )
+
+// EventsPollInterval is how often audit events --follow checks for new
+// events.
+const EventsPollInterval = 2 * time.Second
+
+// DefaultMigrationTimeout bounds how long migrate --wait waits for the
+// migrations it started.
+const DefaultMigrationTimeout = 10 * time.Minute
+
+// Polling defaults for cleanup --verify-cleanup-complete, which waits for
+// deleted resources to disappear before failing on the ones left.
+const (
+	DefaultVerifyCleanupTimeout = 2 * time.Minute
+	VerifyCleanupPollInterval   = 5 * time.Second
+)
+
+// VM create retry defaults, overridable with --create-retries and
+// --create-backoff.
+const (
+	// DefaultCreateConcurrency is how many VM creates a run keeps in flight
+	// at a time, overridable with --create-concurrency.
+	DefaultCreateConcurrency = 10
+	// DefaultCreateRetries is the number of retries after the first attempt
+	// to create a VM.
+	DefaultCreateRetries = 5
+	// DefaultCreateBackoff is the wait before the first retry; it doubles
+	// for each retry after.
+	DefaultCreateBackoff = time.Second
+	// MaxCreateBackoff caps the wait before any single retry.
+	MaxCreateBackoff = 30 * time.Second
+)
+
+// DefaultConnectCheckTimeout bounds the API server ping made before a run
+// unless --skip-connect-check is set.
+const DefaultConnectCheckTimeout = 10 * time.Second
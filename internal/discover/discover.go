@@ -0,0 +1,124 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package discover
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/opdev/virtwork/internal/constants"
+)
+
+// Run summarises the resources of one run found on the cluster.
+type Run struct {
+	RunID     string
+	Namespace string
+	VMs       int
+	Services  int
+	Secrets   int
+	// Components are the workloads of the run's VMs, sorted.
+	Components []string
+	// CreatedAt is the earliest creation timestamp of the run's resources.
+	CreatedAt time.Time
+}
+
+// runKey identifies a run; the same run-id may appear in several namespaces.
+type runKey struct {
+	namespace string
+	runID     string
+}
+
+// Runs lists the virtwork-managed VMs, services, and secrets in namespace, or
+// in all namespaces when namespace is empty, and groups them by their
+// virtwork/run-id label. Resources without a run-id are skipped. It reads
+// only the cluster, not the audit database. Runs are sorted by creation
+// time, oldest first.
+func Runs(ctx context.Context, c client.Client, namespace string) ([]Run, error) {
+	listOpts := []client.ListOption{
+		client.MatchingLabels{constants.LabelManagedBy: constants.ManagedByValue},
+	}
+	if namespace != "" {
+		listOpts = append(listOpts, client.InNamespace(namespace))
+	}
+
+	runs := make(map[runKey]*Run)
+	components := make(map[runKey]map[string]bool)
+	add := func(obj client.Object) *Run {
+		id := obj.GetLabels()[constants.LabelRunID]
+		if id == "" {
+			return nil
+		}
+		key := runKey{namespace: obj.GetNamespace(), runID: id}
+		r, ok := runs[key]
+		if !ok {
+			r = &Run{RunID: id, Namespace: obj.GetNamespace()}
+			runs[key] = r
+			components[key] = make(map[string]bool)
+		}
+		created := obj.GetCreationTimestamp().Time
+		if !created.IsZero() && (r.CreatedAt.IsZero() || created.Before(r.CreatedAt)) {
+			r.CreatedAt = created
+		}
+		return r
+	}
+
+	vmList := &kubevirtv1.VirtualMachineList{}
+	if err := c.List(ctx, vmList, listOpts...); err != nil {
+		return nil, fmt.Errorf("listing VMs: %w", err)
+	}
+	for i := range vmList.Items {
+		v := &vmList.Items[i]
+		if r := add(v); r != nil {
+			r.VMs++
+			if component := v.Labels[constants.LabelComponent]; component != "" {
+				components[runKey{namespace: r.Namespace, runID: r.RunID}][component] = true
+			}
+		}
+	}
+
+	svcList := &corev1.ServiceList{}
+	if err := c.List(ctx, svcList, listOpts...); err != nil {
+		return nil, fmt.Errorf("listing services: %w", err)
+	}
+	for i := range svcList.Items {
+		if r := add(&svcList.Items[i]); r != nil {
+			r.Services++
+		}
+	}
+
+	secretList := &corev1.SecretList{}
+	if err := c.List(ctx, secretList, listOpts...); err != nil {
+		return nil, fmt.Errorf("listing secrets: %w", err)
+	}
+	for i := range secretList.Items {
+		if r := add(&secretList.Items[i]); r != nil {
+			r.Secrets++
+		}
+	}
+
+	result := make([]Run, 0, len(runs))
+	for key, r := range runs {
+		for component := range components[key] {
+			r.Components = append(r.Components, component)
+		}
+		sort.Strings(r.Components)
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].RunID < result[j].RunID
+	})
+	return result, nil
+}
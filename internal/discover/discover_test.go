@@ -0,0 +1,110 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package discover_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/discover"
+)
+
+var _ = Describe("Runs", func() {
+	var (
+		ctx     context.Context
+		scheme  = cluster.NewScheme()
+		earlier = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		later   = time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	managedMeta := func(name, namespace, component, runID string, created time.Time) metav1.ObjectMeta {
+		labels := map[string]string{constants.LabelManagedBy: constants.ManagedByValue}
+		if component != "" {
+			labels[constants.LabelComponent] = component
+		}
+		if runID != "" {
+			labels[constants.LabelRunID] = runID
+		}
+		return metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			Labels:            labels,
+			CreationTimestamp: metav1.NewTime(created),
+		}
+	}
+
+	newVM := func(name, namespace, component, runID string, created time.Time) client.Object {
+		return &kubevirtv1.VirtualMachine{ObjectMeta: managedMeta(name, namespace, component, runID, created)}
+	}
+	newSecret := func(name, namespace, runID string, created time.Time) client.Object {
+		return &corev1.Secret{ObjectMeta: managedMeta(name, namespace, "", runID, created)}
+	}
+	newService := func(name, namespace, runID string, created time.Time) client.Object {
+		return &corev1.Service{ObjectMeta: managedMeta(name, namespace, "network", runID, created)}
+	}
+
+	seeded := func() client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newVM("virtwork-cpu-0", "virtwork", "cpu", "run-b", later),
+			newSecret("virtwork-cpu-0-cloudinit", "virtwork", "run-b", later),
+			newVM("virtwork-network-server-0", "virtwork", "network", "run-a", earlier.Add(time.Minute)),
+			newVM("virtwork-network-client-0", "virtwork", "network", "run-a", earlier.Add(time.Minute)),
+			newVM("virtwork-memory-0", "virtwork", "memory", "run-a", earlier.Add(time.Minute)),
+			newService("virtwork-iperf3-server", "virtwork", "run-a", earlier),
+			newSecret("virtwork-ssh", "virtwork", "", earlier),
+			newVM("virtwork-disk-0", "other", "disk", "run-c", earlier),
+		).Build()
+	}
+
+	It("should group the resources of a namespace by run-id", func() {
+		runs, err := discover.Runs(ctx, seeded(), "virtwork")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runs).To(HaveLen(2))
+
+		Expect(runs[0].RunID).To(Equal("run-a"))
+		Expect(runs[0].Namespace).To(Equal("virtwork"))
+		Expect(runs[0].VMs).To(Equal(3))
+		Expect(runs[0].Services).To(Equal(1))
+		Expect(runs[0].Secrets).To(Equal(0))
+		Expect(runs[0].Components).To(Equal([]string{"memory", "network"}))
+		Expect(runs[0].CreatedAt.Equal(earlier)).To(BeTrue())
+
+		Expect(runs[1].RunID).To(Equal("run-b"))
+		Expect(runs[1].VMs).To(Equal(1))
+		Expect(runs[1].Secrets).To(Equal(1))
+		Expect(runs[1].Components).To(Equal([]string{"cpu"}))
+		Expect(runs[1].CreatedAt.Equal(later)).To(BeTrue())
+	})
+
+	It("should list runs in every namespace when namespace is empty", func() {
+		runs, err := discover.Runs(ctx, seeded(), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runs).To(HaveLen(3))
+		Expect(runs[0].RunID).To(Equal("run-c"))
+		Expect(runs[0].Namespace).To(Equal("other"))
+		Expect(runs[0].Components).To(Equal([]string{"disk"}))
+	})
+
+	It("should return no runs for an empty namespace", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		runs, err := discover.Runs(ctx, c, "virtwork")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runs).To(BeEmpty())
+	})
+})
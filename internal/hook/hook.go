@@ -0,0 +1,73 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package hook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Environment variables a hook command receives in addition to virtwork's
+// own environment.
+const (
+	EnvRunID     = "VIRTWORK_RUN_ID"
+	EnvNamespace = "VIRTWORK_NAMESPACE"
+	EnvVMIPs     = "VIRTWORK_VM_IPS"
+)
+
+// hookWaitDelay bounds how long Run waits for the command's output to close
+// once ctx is done and the shell has been killed.
+const hookWaitDelay = time.Second
+
+// Context describes the run a hook is invoked for.
+type Context struct {
+	RunID     string
+	Namespace string
+	// VMIPs maps each VM name to the IP address of its VMI. VMs whose IP
+	// is unknown map to "".
+	VMIPs map[string]string
+}
+
+// Env returns the hook's environment variables as KEY=value pairs. VMIPs
+// is rendered as comma-separated name=ip pairs sorted by VM name.
+func (c Context) Env() []string {
+	names := make([]string, 0, len(c.VMIPs))
+	for name := range c.VMIPs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + c.VMIPs[name]
+	}
+	return []string{
+		EnvRunID + "=" + c.RunID,
+		EnvNamespace + "=" + c.Namespace,
+		EnvVMIPs + "=" + strings.Join(pairs, ","),
+	}
+}
+
+// Run executes command with /bin/sh -c, streaming its output to stdout and
+// stderr as it is produced. The command inherits virtwork's environment plus
+// the variables from hc.Env. The shell is killed if ctx is done. A non-zero exit
+// status is returned as an error.
+func Run(ctx context.Context, command string, hc Context, stdout, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	cmd.Env = append(os.Environ(), hc.Env()...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Children of the shell can keep its output open after it is killed;
+	// stop waiting for them shortly after.
+	cmd.WaitDelay = hookWaitDelay
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running %q: %w", command, err)
+	}
+	return nil
+}
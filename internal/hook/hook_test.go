@@ -0,0 +1,69 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package hook_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/hook"
+)
+
+var _ = Describe("Run", func() {
+	hc := hook.Context{
+		RunID:     "0b5c6a3e-6f1d-4e2a-9a57-6d2f0f8e7c11",
+		Namespace: "virtwork",
+		VMIPs: map[string]string{
+			"virtwork-memory-0": "10.128.0.12",
+			"virtwork-cpu-0":    "10.128.0.11",
+		},
+	}
+
+	It("should pass the run ID, namespace, and VM IPs in the environment", func() {
+		var stdout, stderr bytes.Buffer
+		err := hook.Run(context.Background(),
+			`echo "$VIRTWORK_RUN_ID $VIRTWORK_NAMESPACE $VIRTWORK_VM_IPS"`, hc, &stdout, &stderr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout.String()).To(Equal(
+			"0b5c6a3e-6f1d-4e2a-9a57-6d2f0f8e7c11 virtwork virtwork-cpu-0=10.128.0.11,virtwork-memory-0=10.128.0.12\n"))
+		Expect(stderr.String()).To(BeEmpty())
+	})
+
+	It("should keep virtwork's own environment", func() {
+		GinkgoT().Setenv("HOOK_TEST_VALUE", "inherited")
+		var stdout bytes.Buffer
+		Expect(hook.Run(context.Background(), `echo "$HOOK_TEST_VALUE"`, hc, &stdout, &bytes.Buffer{})).To(Succeed())
+		Expect(stdout.String()).To(Equal("inherited\n"))
+	})
+
+	It("should stream stderr separately", func() {
+		var stdout, stderr bytes.Buffer
+		Expect(hook.Run(context.Background(), `echo out; echo err >&2`, hc, &stdout, &stderr)).To(Succeed())
+		Expect(stdout.String()).To(Equal("out\n"))
+		Expect(stderr.String()).To(Equal("err\n"))
+	})
+
+	It("should return an error for a non-zero exit status", func() {
+		err := hook.Run(context.Background(), "exit 3", hc, &bytes.Buffer{}, &bytes.Buffer{})
+		Expect(err).To(MatchError(ContainSubstring(`running "exit 3"`)))
+		var exitErr *exec.ExitError
+		Expect(errors.As(err, &exitErr)).To(BeTrue())
+		Expect(exitErr.ExitCode()).To(Equal(3))
+	})
+
+	It("should stop the command when the context is done", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := hook.Run(ctx, "sleep 10", hc, &bytes.Buffer{}, &bytes.Buffer{})
+		Expect(err).To(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+})
@@ -0,0 +1,72 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+// Package logging builds the slog.Logger that reports a run's progress,
+// either as the plain lines virtwork prints for a person or as JSON records
+// for log aggregation.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// Log formats accepted by New.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// New returns a logger writing to w in format. Debug records are written
+// only when verbose is set. The text format writes each record's message on
+// a line of its own and drops its attributes; the JSON format writes one
+// slog JSON object per record, attributes included.
+func New(w io.Writer, format string, verbose bool) (*slog.Logger, error) {
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	switch format {
+	case FormatText:
+		return slog.New(&lineHandler{mu: &sync.Mutex{}, w: w, level: level}), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: must be %s or %s", format, FormatText, FormatJSON)
+	}
+}
+
+// lineHandler writes only the message of each record, so text logs read as
+// they did before virtwork logged through slog. Its lock is shared with the
+// handlers derived from it, since they all write to w.
+type lineHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Level
+}
+
+// Enabled reports whether records at level are written.
+func (h *lineHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// Handle writes the record's message followed by a newline.
+func (h *lineHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintln(h.w, r.Message)
+	return err
+}
+
+// WithAttrs returns h, as attributes are not written.
+func (h *lineHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	return h
+}
+
+// WithGroup returns h, as attributes are not written.
+func (h *lineHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/logging"
+)
+
+var _ = Describe("New", func() {
+	var out bytes.Buffer
+
+	BeforeEach(func() {
+		out.Reset()
+	})
+
+	It("should write only the message in text format", func() {
+		logger, err := logging.New(&out, logging.FormatText, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		logger.With("run_id", "r1").Info("VM virtwork-cpu-0 created", "vm_name", "virtwork-cpu-0")
+		Expect(out.String()).To(Equal("VM virtwork-cpu-0 created\n"))
+	})
+
+	It("should write JSON records with their attributes", func() {
+		logger, err := logging.New(&out, logging.FormatJSON, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		logger.With("run_id", "r1").Info("VM virtwork-cpu-0 created", "vm_name", "virtwork-cpu-0")
+		var record map[string]any
+		Expect(json.Unmarshal(out.Bytes(), &record)).To(Succeed())
+		Expect(record).To(HaveKeyWithValue("level", "INFO"))
+		Expect(record).To(HaveKeyWithValue("msg", "VM virtwork-cpu-0 created"))
+		Expect(record).To(HaveKeyWithValue("run_id", "r1"))
+		Expect(record).To(HaveKeyWithValue("vm_name", "virtwork-cpu-0"))
+	})
+
+	DescribeTable("should write debug records only when verbose",
+		func(format string) {
+			logger, err := logging.New(&out, format, false)
+			Expect(err).NotTo(HaveOccurred())
+			logger.Debug("API server reachable")
+			Expect(out.String()).To(BeEmpty())
+
+			logger, err = logging.New(&out, format, true)
+			Expect(err).NotTo(HaveOccurred())
+			logger.Debug("API server reachable")
+			Expect(out.String()).To(ContainSubstring("API server reachable"))
+		},
+		Entry("text", logging.FormatText),
+		Entry("json", logging.FormatJSON),
+	)
+
+	It("should reject an unknown format", func() {
+		_, err := logging.New(&out, "xml", false)
+		Expect(err).To(MatchError(`invalid log format "xml": must be text or json`))
+	})
+})
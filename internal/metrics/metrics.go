@@ -0,0 +1,87 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RunResult is the outcome of a run, exported as metrics.
+type RunResult struct {
+	Namespace       string
+	RunID           string
+	VMsCreated      int
+	VMsReady        int
+	VMsFailed       int
+	ServicesCreated int
+	Duration        time.Duration
+}
+
+// metric is one sample in the text format.
+type metric struct {
+	name  string
+	help  string
+	kind  string
+	value string
+}
+
+// WriteTextfile writes r to path in the Prometheus text exposition format,
+// as read by the node_exporter textfile collector. Every sample is labeled
+// with the run's namespace and run_id. The file is written under a temporary
+// name and renamed into place so a collector never reads a partial file.
+func WriteTextfile(path string, r RunResult) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating metrics file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(format(r)); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing metrics file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing metrics file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("writing metrics file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing metrics file: %w", err)
+	}
+	return nil
+}
+
+// format renders r in the Prometheus text exposition format.
+func format(r RunResult) string {
+	metrics := []metric{
+		{"virtwork_vms_created_total", "VMs created by the run.", "counter", strconv.Itoa(r.VMsCreated)},
+		{"virtwork_vms_ready_total", "VMs that passed the readiness check.", "counter", strconv.Itoa(r.VMsReady)},
+		{"virtwork_vms_failed_total", "VMs that failed to be created or to become ready.", "counter", strconv.Itoa(r.VMsFailed)},
+		{"virtwork_services_created_total", "Services created by the run.", "counter", strconv.Itoa(r.ServicesCreated)},
+		{"virtwork_run_duration_seconds", "Wall-clock duration of the run.", "gauge", strconv.FormatFloat(r.Duration.Seconds(), 'f', -1, 64)},
+	}
+	labels := fmt.Sprintf(`{namespace="%s",run_id="%s"}`, escapeLabel(r.Namespace), escapeLabel(r.RunID))
+
+	var b strings.Builder
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", m.name, m.kind)
+		fmt.Fprintf(&b, "%s%s %s\n", m.name, labels, m.value)
+	}
+	return b.String()
+}
+
+// labelEscaper escapes a label value as the text format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel returns v escaped for use as a label value.
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/metrics"
+)
+
+var _ = Describe("WriteTextfile", func() {
+	var (
+		dir  string
+		path string
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		path = filepath.Join(dir, "virtwork.prom")
+	})
+
+	It("should write every metric labeled by namespace and run_id", func() {
+		err := metrics.WriteTextfile(path, metrics.RunResult{
+			Namespace:       "virtwork",
+			RunID:           "3f2a9c1e",
+			VMsCreated:      5,
+			VMsReady:        4,
+			VMsFailed:       1,
+			ServicesCreated: 1,
+			Duration:        2*time.Minute + 1500*time.Millisecond,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`# HELP virtwork_vms_created_total VMs created by the run.
+# TYPE virtwork_vms_created_total counter
+virtwork_vms_created_total{namespace="virtwork",run_id="3f2a9c1e"} 5
+# HELP virtwork_vms_ready_total VMs that passed the readiness check.
+# TYPE virtwork_vms_ready_total counter
+virtwork_vms_ready_total{namespace="virtwork",run_id="3f2a9c1e"} 4
+# HELP virtwork_vms_failed_total VMs that failed to be created or to become ready.
+# TYPE virtwork_vms_failed_total counter
+virtwork_vms_failed_total{namespace="virtwork",run_id="3f2a9c1e"} 1
+# HELP virtwork_services_created_total Services created by the run.
+# TYPE virtwork_services_created_total counter
+virtwork_services_created_total{namespace="virtwork",run_id="3f2a9c1e"} 1
+# HELP virtwork_run_duration_seconds Wall-clock duration of the run.
+# TYPE virtwork_run_duration_seconds gauge
+virtwork_run_duration_seconds{namespace="virtwork",run_id="3f2a9c1e"} 121.5
+`))
+	})
+
+	It("should escape label values", func() {
+		err := metrics.WriteTextfile(path, metrics.RunResult{Namespace: `a"b\c`, RunID: "line\nbreak"})
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`virtwork_vms_created_total{namespace="a\"b\\c",run_id="line\nbreak"} 0` + "\n"))
+	})
+
+	It("should replace an existing file and leave no temporary files", func() {
+		Expect(os.WriteFile(path, []byte("stale\n"), 0644)).To(Succeed())
+
+		Expect(metrics.WriteTextfile(path, metrics.RunResult{VMsCreated: 2})).To(Succeed())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("stale"))
+		entries, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should return an error when the directory does not exist", func() {
+		err := metrics.WriteTextfile(filepath.Join(dir, "missing", "virtwork.prom"), metrics.RunResult{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("creating metrics file"))
+	})
+})
@@ -0,0 +1,68 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+// Migration is a live migration started for one VM.
+type Migration struct {
+	VMName string
+	Name   string // generated name of the VirtualMachineInstanceMigration
+}
+
+// Result reports the migrations Start created and the VMs it skipped.
+type Result struct {
+	Started []Migration
+	Skipped []string // VMs without a running VMI
+}
+
+// Start creates a VirtualMachineInstanceMigration for each VM of the run
+// whose VMI is Running, in VM name order. VMs that are stopped or still
+// starting cannot be migrated and are skipped. Each migration carries the
+// managed-by, component, and run-id labels of its VM. On error, the result
+// still lists the migrations started before it.
+func Start(ctx context.Context, c client.Client, namespace, runID string) (*Result, error) {
+	result := &Result{}
+	vms, err := vm.ListVMs(ctx, c, namespace, map[string]string{
+		constants.LabelManagedBy: constants.ManagedByValue,
+		constants.LabelRunID:     runID,
+	})
+	if err != nil {
+		return result, err
+	}
+	sort.Slice(vms, func(i, j int) bool { return vms[i].Name < vms[j].Name })
+
+	for _, v := range vms {
+		phase, err := vm.GetVMIPhase(ctx, c, v.Name, namespace)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return result, err
+		}
+		if phase != kubevirtv1.Running {
+			result.Skipped = append(result.Skipped, v.Name)
+			continue
+		}
+
+		m := vm.BuildMigration(v.Name, namespace, map[string]string{
+			constants.LabelManagedBy: constants.ManagedByValue,
+			constants.LabelComponent: v.Labels[constants.LabelComponent],
+			constants.LabelRunID:     runID,
+		})
+		if err := vm.CreateMigration(ctx, c, m); err != nil {
+			return result, fmt.Errorf("creating migration for VM %q: %w", v.Name, err)
+		}
+		result.Started = append(result.Started, Migration{VMName: v.Name, Name: m.Name})
+	}
+	return result, nil
+}
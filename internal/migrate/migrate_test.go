@@ -0,0 +1,128 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/migrate"
+)
+
+const (
+	testNamespace = "virtwork"
+	testRunID     = "run-1"
+)
+
+func runVM(name, component, runID string) *kubevirtv1.VirtualMachine {
+	return &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.LabelManagedBy: constants.ManagedByValue,
+				constants.LabelComponent: component,
+				constants.LabelRunID:     runID,
+			},
+		},
+	}
+}
+
+func vmi(name string, phase kubevirtv1.VirtualMachineInstancePhase) *kubevirtv1.VirtualMachineInstance {
+	return &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: phase},
+	}
+}
+
+var _ = Describe("Start", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	listMigrations := func(c client.Client) []kubevirtv1.VirtualMachineInstanceMigration {
+		list := &kubevirtv1.VirtualMachineInstanceMigrationList{}
+		Expect(c.List(ctx, list, client.InNamespace(testNamespace))).To(Succeed())
+		return list.Items
+	}
+
+	It("should create a labeled migration for each running VMI of the run", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			runVM("virtwork-cpu-1", "cpu", testRunID), vmi("virtwork-cpu-1", kubevirtv1.Running),
+			runVM("virtwork-cpu-0", "cpu", testRunID), vmi("virtwork-cpu-0", kubevirtv1.Running),
+			runVM("virtwork-memory-0", "memory", "other-run"), vmi("virtwork-memory-0", kubevirtv1.Running),
+		).Build()
+
+		result, err := migrate.Start(ctx, c, testNamespace, testRunID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Skipped).To(BeEmpty())
+		Expect(result.Started).To(HaveLen(2))
+		Expect(result.Started[0].VMName).To(Equal("virtwork-cpu-0"))
+		Expect(result.Started[0].Name).To(HavePrefix("virtwork-cpu-0-migration-"))
+		Expect(result.Started[1].VMName).To(Equal("virtwork-cpu-1"))
+
+		migrations := listMigrations(c)
+		Expect(migrations).To(HaveLen(2))
+		for _, m := range migrations {
+			Expect(m.Labels).To(HaveKeyWithValue(constants.LabelRunID, testRunID))
+			Expect(m.Labels).To(HaveKeyWithValue(constants.LabelManagedBy, constants.ManagedByValue))
+			Expect(m.Labels).To(HaveKeyWithValue(constants.LabelComponent, "cpu"))
+		}
+		Expect([]string{migrations[0].Spec.VMIName, migrations[1].Spec.VMIName}).To(
+			ConsistOf("virtwork-cpu-0", "virtwork-cpu-1"))
+	})
+
+	It("should skip VMs without a running VMI", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			runVM("virtwork-cpu-0", "cpu", testRunID), vmi("virtwork-cpu-0", kubevirtv1.Running),
+			runVM("virtwork-cpu-1", "cpu", testRunID), vmi("virtwork-cpu-1", kubevirtv1.Scheduling),
+			runVM("virtwork-cpu-2", "cpu", testRunID),
+		).Build()
+
+		result, err := migrate.Start(ctx, c, testNamespace, testRunID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Started).To(HaveLen(1))
+		Expect(result.Skipped).To(Equal([]string{"virtwork-cpu-1", "virtwork-cpu-2"}))
+		Expect(listMigrations(c)).To(HaveLen(1))
+	})
+
+	It("should return the migrations started before a failure", func() {
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				runVM("virtwork-cpu-0", "cpu", testRunID), vmi("virtwork-cpu-0", kubevirtv1.Running),
+				runVM("virtwork-cpu-1", "cpu", testRunID), vmi("virtwork-cpu-1", kubevirtv1.Running),
+			).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if m, ok := obj.(*kubevirtv1.VirtualMachineInstanceMigration); ok && m.Spec.VMIName == "virtwork-cpu-1" {
+						return fmt.Errorf("migration rejected")
+					}
+					return cl.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		result, err := migrate.Start(ctx, c, testNamespace, testRunID)
+		Expect(err).To(MatchError(ContainSubstring(`creating migration for VM "virtwork-cpu-1"`)))
+		Expect(result.Started).To(HaveLen(1))
+		Expect(result.Started[0].VMName).To(Equal("virtwork-cpu-0"))
+	})
+})
@@ -0,0 +1,71 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+// Package progressbar renders a single status line, such as
+// "Creating VMs 12/50", that is rewritten in place as work completes.
+package progressbar
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// Bar counts completed items out of a fixed total and redraws its line on
+// every update. A nil *Bar is valid and draws nothing, so callers can leave
+// it unset when progress is disabled. Its methods are safe for concurrent
+// use.
+type Bar struct {
+	mu    sync.Mutex
+	w     io.Writer
+	label string
+	total int
+	done  int
+}
+
+// New returns a Bar labelled label for total items and draws it at 0/total.
+func New(w io.Writer, label string, total int) *Bar {
+	b := &Bar{w: w, label: label, total: total}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.render()
+	return b
+}
+
+// Increment counts one more item as done and redraws the line.
+func (b *Bar) Increment() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done++
+	b.render()
+}
+
+// Finish ends the line, leaving its last state visible. Output written after
+// Finish starts on a new line.
+func (b *Bar) Finish() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fmt.Fprintln(b.w)
+}
+
+// render redraws the line from its start. The count only grows, so the new
+// line always covers the old one. b.mu must be held.
+func (b *Bar) render() {
+	fmt.Fprintf(b.w, "\r%s %d/%d", b.label, b.done, b.total)
+}
+
+// IsTerminal reports whether w is a terminal, where rewriting a line in
+// place displays as intended. Anything but an *os.File is not a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,16 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package progressbar_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestProgressbar(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Progressbar Suite")
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package progressbar_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/progressbar"
+)
+
+var _ = Describe("Bar", func() {
+	var buf *bytes.Buffer
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+	})
+
+	It("should redraw the line once per update of a simulated run", func() {
+		create := progressbar.New(buf, "Creating VMs", 5)
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				create.Increment()
+			}()
+		}
+		wg.Wait()
+		create.Finish()
+
+		ready := progressbar.New(buf, "Ready", 5)
+		for i := 0; i < 5; i++ {
+			ready.Increment()
+		}
+		ready.Finish()
+
+		// Each bar draws 0/5 and then once per item
+		Expect(strings.Count(buf.String(), "\r")).To(Equal(12))
+		Expect(strings.Count(buf.String(), "\n")).To(Equal(2))
+		Expect(buf.String()).To(ContainSubstring("\rCreating VMs 0/5"))
+		Expect(buf.String()).To(ContainSubstring("\rCreating VMs 5/5\n"))
+		Expect(buf.String()).To(HaveSuffix("\rReady 5/5\n"))
+	})
+
+	It("should draw nothing from a nil bar", func() {
+		var bar *progressbar.Bar
+		bar.Increment()
+		bar.Finish()
+		Expect(buf.Len()).To(BeZero())
+	})
+
+	It("should not treat a buffer as a terminal", func() {
+		Expect(progressbar.IsTerminal(buf)).To(BeFalse())
+	})
+})
@@ -0,0 +1,16 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"time"
+
+	"github.com/opdev/virtwork/internal/retry"
+)
+
+// SetBaseRetryBackoff overrides the retry backoff duration for testing.
+// Returns a function that restores the original value.
+func SetBaseRetryBackoff(d time.Duration) func() {
+	return retry.SetBaseBackoff(d)
+}
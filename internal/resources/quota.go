@@ -0,0 +1,62 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CheckQuota returns an error naming every ResourceQuota in the namespace
+// whose remaining CPU or memory requests, hard minus used, cannot fit cpu and
+// mem more. Both the requests.cpu and the cpu keys of a quota limit requests,
+// and likewise for memory. A namespace without quotas, or one that does not
+// exist yet, passes.
+func CheckQuota(ctx context.Context, c client.Client, namespace string, cpu, mem resource.Quantity) error {
+	quotas := &corev1.ResourceQuotaList{}
+	if err := c.List(ctx, quotas, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("listing resource quotas in %s: %w", namespace, err)
+	}
+
+	requested := map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceRequestsCPU:    cpu,
+		corev1.ResourceCPU:            cpu,
+		corev1.ResourceRequestsMemory: mem,
+		corev1.ResourceMemory:         mem,
+	}
+	var exceeded []string
+	for _, q := range quotas.Items {
+		for _, name := range []corev1.ResourceName{
+			corev1.ResourceRequestsCPU, corev1.ResourceCPU,
+			corev1.ResourceRequestsMemory, corev1.ResourceMemory,
+		} {
+			hard, ok := q.Status.Hard[name]
+			if !ok {
+				hard, ok = q.Spec.Hard[name]
+			}
+			if !ok {
+				continue
+			}
+			available := hard.DeepCopy()
+			if used, ok := q.Status.Used[name]; ok {
+				available.Sub(used)
+			}
+			want := requested[name]
+			if want.Cmp(available) > 0 {
+				exceeded = append(exceeded, fmt.Sprintf("%s %s needs %s but only %s of %s is left",
+					q.Name, name, want.String(), available.String(), hard.String()))
+			}
+		}
+	}
+	if len(exceeded) > 0 {
+		return fmt.Errorf("the run would exceed resource quotas in namespace %s: %s",
+			namespace, strings.Join(exceeded, "; "))
+	}
+	return nil
+}
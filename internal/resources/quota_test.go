@@ -0,0 +1,103 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/resources"
+)
+
+var _ = Describe("CheckQuota", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	quota := func(name string, hard, used corev1.ResourceList) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+
+	It("should pass when the namespace has no quota", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		err := resources.CheckQuota(ctx, c, "test-ns", resource.MustParse("100"), resource.MustParse("1Ti"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should pass when the requests fit in what the quota has left", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			quota("compute", corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("4"),
+				corev1.ResourceRequestsMemory: resource.MustParse("16Gi"),
+			}, corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("1"),
+				corev1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+			}),
+		).Build()
+
+		err := resources.CheckQuota(ctx, c, "test-ns", resource.MustParse("3"), resource.MustParse("8Gi"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should fail naming the quota and resource the requests exceed", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			quota("compute", corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("4"),
+				corev1.ResourceMemory:      resource.MustParse("16Gi"),
+			}, corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("1"),
+				corev1.ResourceMemory:      resource.MustParse("12Gi"),
+			}),
+		).Build()
+
+		err := resources.CheckQuota(ctx, c, "test-ns", resource.MustParse("2"), resource.MustParse("8Gi"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("namespace test-ns"))
+		Expect(err.Error()).To(ContainSubstring("compute memory needs 8Gi but only 4Gi of 16Gi is left"))
+		Expect(err.Error()).NotTo(ContainSubstring("requests.cpu"))
+	})
+
+	It("should ignore quotas in other namespaces", func() {
+		other := quota("compute", corev1.ResourceList{
+			corev1.ResourceRequestsCPU: resource.MustParse("1"),
+		}, nil)
+		other.Namespace = "other-ns"
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(other).Build()
+
+		err := resources.CheckQuota(ctx, c, "test-ns", resource.MustParse("2"), resource.MustParse("1Gi"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error when quotas cannot be listed", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(_ context.Context, _ client.WithWatch, _ client.ObjectList, _ ...client.ListOption) error {
+				return errors.New("forbidden")
+			},
+		}).Build()
+
+		err := resources.CheckQuota(ctx, c, "test-ns", resource.MustParse("1"), resource.MustParse("1Gi"))
+		Expect(err).To(MatchError(ContainSubstring("listing resource quotas in test-ns")))
+	})
+})
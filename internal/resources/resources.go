@@ -8,9 +8,14 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/opdev/virtwork/internal/cloudinit"
+	"github.com/opdev/virtwork/internal/retry"
 )
 
 // EnsureNamespace creates a namespace with the given labels if it does not
@@ -29,19 +34,34 @@ func EnsureNamespace(ctx context.Context, c client.Client, name string, labels m
 	return err
 }
 
+// NamespaceExists reports whether the named namespace exists.
+func NamespaceExists(ctx context.Context, c client.Client, name string) (bool, error) {
+	err := c.Get(ctx, client.ObjectKey{Name: name}, &corev1.Namespace{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("getting namespace %s: %w", name, err)
+	}
+	return true, nil
+}
+
 // CreateService creates a Kubernetes Service. AlreadyExists errors are treated
-// as success (idempotent).
+// as success (idempotent). Transient errors are retried with exponential backoff.
 func CreateService(ctx context.Context, c client.Client, svc *corev1.Service) error {
-	err := c.Create(ctx, svc)
-	if apierrors.IsAlreadyExists(err) {
-		return nil
-	}
-	return err
+	return createIdempotent(ctx, c, svc)
+}
+
+// CreateNetworkPolicy creates a NetworkPolicy. AlreadyExists errors are
+// treated as success (idempotent). Transient errors are retried with
+// exponential backoff.
+func CreateNetworkPolicy(ctx context.Context, c client.Client, np *networkingv1.NetworkPolicy) error {
+	return createIdempotent(ctx, c, np)
 }
 
 // CreateCloudInitSecret creates a Secret holding cloud-init userdata.
 // The secret is labeled for cleanup. AlreadyExists errors are treated as
-// success (idempotent).
+// success (idempotent). Transient errors are retried with exponential backoff.
 func CreateCloudInitSecret(ctx context.Context, c client.Client, name, namespace, userdata string, labels map[string]string) error {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -53,11 +73,77 @@ func CreateCloudInitSecret(ctx context.Context, c client.Client, name, namespace
 			"userdata": userdata,
 		},
 	}
-	err := c.Create(ctx, secret)
-	if apierrors.IsAlreadyExists(err) {
-		return nil
+	return createIdempotent(ctx, c, secret)
+}
+
+// CheckImagePullSecret returns an error if the Secret name does not exist in
+// the namespace, so that a mistyped pull secret fails a run before any VM is
+// created rather than leaving the VMIs unable to pull their container disk.
+func CheckImagePullSecret(ctx context.Context, c client.Client, namespace, name string) error {
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &corev1.Secret{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("image pull secret %q not found in namespace %s: create it before the run", name, namespace)
 	}
-	return err
+	if err != nil {
+		return fmt.Errorf("getting image pull secret %q: %w", name, err)
+	}
+	return nil
+}
+
+// CreateCompressedCloudInitSecret creates a Secret holding gzip-compressed
+// cloud-init userdata. The compressed bytes are stored in Data since they are
+// not valid UTF-8. The secret is labeled for cleanup. AlreadyExists errors are
+// treated as success (idempotent). Transient errors are retried with exponential
+// backoff.
+func CreateCompressedCloudInitSecret(ctx context.Context, c client.Client, name, namespace, userdata string, labels map[string]string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: map[string][]byte{
+			"userdata": cloudinit.Compress(userdata),
+		},
+	}
+	return createIdempotent(ctx, c, secret)
+}
+
+// CreateServiceAccountWithRole creates a ServiceAccount, a Role granting rules
+// in the namespace, and a RoleBinding of the Role to the ServiceAccount, all
+// named name and labeled for cleanup. AlreadyExists errors are treated as
+// success (idempotent). Transient errors are retried with exponential backoff.
+func CreateServiceAccountWithRole(ctx context.Context, c client.Client, name, namespace string, rules []rbacv1.PolicyRule, labels map[string]string) error {
+	meta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels:    labels,
+	}
+	sa := &corev1.ServiceAccount{ObjectMeta: *meta.DeepCopy()}
+	if err := createIdempotent(ctx, c, sa); err != nil {
+		return fmt.Errorf("creating service account %s: %w", name, err)
+	}
+	role := &rbacv1.Role{ObjectMeta: *meta.DeepCopy(), Rules: rules}
+	if err := createIdempotent(ctx, c, role); err != nil {
+		return fmt.Errorf("creating role %s: %w", name, err)
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: *meta.DeepCopy(),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      name,
+			Namespace: namespace,
+		}},
+	}
+	if err := createIdempotent(ctx, c, binding); err != nil {
+		return fmt.Errorf("creating role binding %s: %w", name, err)
+	}
+	return nil
 }
 
 // DeleteManagedSecrets lists and deletes secrets matching the given labels in
@@ -109,3 +195,46 @@ func DeleteManagedServices(ctx context.Context, c client.Client, namespace strin
 	}
 	return deleted, nil
 }
+
+// createIdempotent creates obj, treating AlreadyExists as success and retrying
+// transient errors.
+func createIdempotent(ctx context.Context, c client.Client, obj client.Object) error {
+	return retry.OnTransient(ctx, func() error {
+		err := c.Create(ctx, obj)
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}, retry.DefaultMaxRetries)
+}
+
+// annotatingClient is a client.Client that adds fixed annotations to every
+// object it creates.
+type annotatingClient struct {
+	client.Client
+	annotations map[string]string
+}
+
+// WithAnnotations returns a client that adds annotations to every object it
+// creates, so metadata such as the CI build reaches all of a run's resources
+// without threading it through each create helper. Annotations an object
+// already has are kept. With no annotations, c is returned unchanged.
+func WithAnnotations(c client.Client, annotations map[string]string) client.Client {
+	if len(annotations) == 0 {
+		return c
+	}
+	return &annotatingClient{Client: c, annotations: annotations}
+}
+
+// Create adds the client's annotations to obj and creates it.
+func (c *annotatingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	merged := make(map[string]string, len(c.annotations)+len(obj.GetAnnotations()))
+	for k, v := range c.annotations {
+		merged[k] = v
+	}
+	for k, v := range obj.GetAnnotations() {
+		merged[k] = v
+	}
+	obj.SetAnnotations(merged)
+	return c.Client.Create(ctx, obj, opts...)
+}
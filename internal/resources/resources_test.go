@@ -4,12 +4,18 @@
 package resources_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"io"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -98,6 +104,24 @@ var _ = Describe("EnsureNamespace", func() {
 	})
 })
 
+var _ = Describe("NamespaceExists", func() {
+	scheme := cluster.NewScheme()
+
+	It("should report whether the namespace exists", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "virtwork-cpu"},
+		}).Build()
+
+		exists, err := resources.NamespaceExists(context.Background(), c, "virtwork-cpu")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeTrue())
+
+		exists, err = resources.NamespaceExists(context.Background(), c, "virtwork-disk")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+})
+
 var _ = Describe("CreateService", func() {
 	var (
 		ctx    context.Context
@@ -170,6 +194,217 @@ var _ = Describe("CreateService", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(apierrors.IsUnauthorized(err)).To(BeTrue())
 	})
+
+	It("should retry on transient errors", func() {
+		DeferCleanup(resources.SetBaseRetryBackoff(time.Millisecond))
+		callCount := 0
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					callCount++
+					if callCount == 1 {
+						return apierrors.NewServiceUnavailable("temporarily unavailable")
+					}
+					return cl.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		svc := newTestService("retry-svc", "default")
+		Expect(resources.CreateService(ctx, c, svc)).To(Succeed())
+		Expect(callCount).To(Equal(2))
+
+		got := &corev1.Service{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "retry-svc", Namespace: "default"}, got)).To(Succeed())
+	})
+})
+
+var _ = Describe("CheckImagePullSecret", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should pass when the secret exists", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-creds", Namespace: "test-ns"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+		}).Build()
+
+		Expect(resources.CheckImagePullSecret(ctx, c, "test-ns", "registry-creds")).To(Succeed())
+	})
+
+	It("should fail naming a secret that is missing from the namespace", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-creds", Namespace: "other-ns"},
+		}).Build()
+
+		err := resources.CheckImagePullSecret(ctx, c, "test-ns", "registry-creds")
+		Expect(err).To(MatchError(`image pull secret "registry-creds" not found in namespace test-ns: create it before the run`))
+	})
+})
+
+var _ = Describe("CreateCompressedCloudInitSecret", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should store gzip-compressed userdata in Data", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		userdata := "#cloud-config\npackages:\n  - vim\n"
+
+		err := resources.CreateCompressedCloudInitSecret(ctx, c, "test-cloudinit", "default",
+			userdata, map[string]string{"app.kubernetes.io/managed-by": "virtwork"})
+		Expect(err).NotTo(HaveOccurred())
+
+		got := &corev1.Secret{}
+		err = c.Get(ctx, client.ObjectKey{Name: "test-cloudinit", Namespace: "default"}, got)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got.StringData).To(BeEmpty())
+
+		zr, err := gzip.NewReader(bytes.NewReader(got.Data["userdata"]))
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := io.ReadAll(zr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(decoded)).To(Equal(userdata))
+		Expect(got.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "virtwork"))
+	})
+
+	It("should skip on AlreadyExists", func() {
+		existing := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "existing-secret",
+				Namespace: "default",
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+		err := resources.CreateCompressedCloudInitSecret(ctx, c, "existing-secret", "default",
+			"#cloud-config\n", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("CreateServiceAccountWithRole", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+		labels = map[string]string{
+			"app.kubernetes.io/managed-by": "virtwork",
+			"app.kubernetes.io/component":  "api-churn",
+			"virtwork/run-id":              "run-1",
+		}
+		rules = []rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     []string{"create", "delete"},
+		}}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should create a labeled ServiceAccount, Role, and RoleBinding", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		err := resources.CreateServiceAccountWithRole(ctx, c, "virtwork-api-churn", "default", rules, labels)
+		Expect(err).NotTo(HaveOccurred())
+
+		key := client.ObjectKey{Name: "virtwork-api-churn", Namespace: "default"}
+		sa := &corev1.ServiceAccount{}
+		Expect(c.Get(ctx, key, sa)).To(Succeed())
+		Expect(sa.Labels).To(Equal(labels))
+
+		role := &rbacv1.Role{}
+		Expect(c.Get(ctx, key, role)).To(Succeed())
+		Expect(role.Labels).To(Equal(labels))
+		Expect(role.Rules).To(Equal(rules))
+
+		binding := &rbacv1.RoleBinding{}
+		Expect(c.Get(ctx, key, binding)).To(Succeed())
+		Expect(binding.Labels).To(Equal(labels))
+		Expect(binding.RoleRef).To(Equal(rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     "virtwork-api-churn",
+		}))
+		Expect(binding.Subjects).To(ConsistOf(rbacv1.Subject{
+			Kind:      "ServiceAccount",
+			Name:      "virtwork-api-churn",
+			Namespace: "default",
+		}))
+	})
+
+	It("should skip objects that already exist", func() {
+		existing := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "virtwork-api-churn",
+				Namespace: "default",
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+		err := resources.CreateServiceAccountWithRole(ctx, c, "virtwork-api-churn", "default", rules, labels)
+		Expect(err).NotTo(HaveOccurred())
+		err = resources.CreateServiceAccountWithRole(ctx, c, "virtwork-api-churn", "default", rules, labels)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Get(ctx, client.ObjectKey{Name: "virtwork-api-churn", Namespace: "default"}, &rbacv1.RoleBinding{})).To(Succeed())
+	})
+})
+
+var _ = Describe("CreateNetworkPolicy", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	newTestPolicy := func(name, namespace string) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "virtwork",
+				},
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"virtwork/role": "server"}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+	}
+
+	It("should create a network policy", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		Expect(resources.CreateNetworkPolicy(ctx, c, newTestPolicy("test-np", "default"))).To(Succeed())
+
+		got := &networkingv1.NetworkPolicy{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-np", Namespace: "default"}, got)).To(Succeed())
+		Expect(got.Spec.PodSelector.MatchLabels).To(HaveKeyWithValue("virtwork/role", "server"))
+	})
+
+	It("should skip on AlreadyExists", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newTestPolicy("existing-np", "default")).Build()
+
+		Expect(resources.CreateNetworkPolicy(ctx, c, newTestPolicy("existing-np", "default"))).To(Succeed())
+	})
 })
 
 var _ = Describe("CreateCloudInitSecret", func() {
@@ -248,6 +483,31 @@ var _ = Describe("CreateCloudInitSecret", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(apierrors.IsForbidden(err)).To(BeTrue())
 	})
+
+	It("should retry on transient errors", func() {
+		DeferCleanup(resources.SetBaseRetryBackoff(time.Millisecond))
+		callCount := 0
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					callCount++
+					if callCount == 1 {
+						return apierrors.NewTooManyRequests("slow down", 1)
+					}
+					return cl.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		err := resources.CreateCloudInitSecret(ctx, c, "retry-secret", "default",
+			"#cloud-config\n", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(callCount).To(Equal(2))
+
+		got := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "retry-secret", Namespace: "default"}, got)).To(Succeed())
+	})
 })
 
 var _ = Describe("DeleteManagedSecrets", func() {
@@ -412,3 +672,62 @@ var _ = Describe("DeleteManagedServices", func() {
 		Expect(err).To(HaveOccurred())
 	})
 })
+
+var _ = Describe("WithAnnotations", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	annotations := map[string]string{
+		"virtwork/git-sha": "0123abc",
+		"virtwork/ci-url":  "https://ci.example.com/job/42",
+	}
+
+	It("should annotate every object created through it", func() {
+		base := fake.NewClientBuilder().WithScheme(scheme).Build()
+		c := resources.WithAnnotations(base, annotations)
+
+		Expect(resources.CreateCloudInitSecret(ctx, c, "vm-0-cloudinit", "default", "#cloud-config\n", nil)).To(Succeed())
+		Expect(resources.CreateServiceAccountWithRole(ctx, c, "sa", "default", nil, nil)).To(Succeed())
+
+		secret := &corev1.Secret{}
+		Expect(base.Get(ctx, client.ObjectKey{Name: "vm-0-cloudinit", Namespace: "default"}, secret)).To(Succeed())
+		Expect(secret.Annotations).To(Equal(annotations))
+
+		role := &rbacv1.Role{}
+		Expect(base.Get(ctx, client.ObjectKey{Name: "sa", Namespace: "default"}, role)).To(Succeed())
+		Expect(role.Annotations).To(Equal(annotations))
+	})
+
+	It("should keep annotations the object already has", func() {
+		base := fake.NewClientBuilder().WithScheme(scheme).Build()
+		c := resources.WithAnnotations(base, annotations)
+
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "svc",
+				Namespace:   "default",
+				Annotations: map[string]string{"virtwork/git-sha": "explicit", "other": "kept"},
+			},
+		}
+		Expect(resources.CreateService(ctx, c, svc)).To(Succeed())
+
+		created := &corev1.Service{}
+		Expect(base.Get(ctx, client.ObjectKey{Name: "svc", Namespace: "default"}, created)).To(Succeed())
+		Expect(created.Annotations).To(Equal(map[string]string{
+			"virtwork/git-sha": "explicit",
+			"virtwork/ci-url":  "https://ci.example.com/job/42",
+			"other":            "kept",
+		}))
+	})
+
+	It("should return the client unchanged without annotations", func() {
+		base := fake.NewClientBuilder().WithScheme(scheme).Build()
+		Expect(resources.WithAnnotations(base, nil)).To(BeIdenticalTo(base))
+	})
+})
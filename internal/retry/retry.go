@@ -0,0 +1,89 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// DefaultMaxRetries is the number of retries after the first attempt used for
+// create and delete calls against the API server.
+const DefaultMaxRetries = 5
+
+var baseBackoff = time.Second
+
+// SetBaseBackoff overrides the initial backoff duration. It exists for tests
+// and returns a function that restores the original value.
+func SetBaseBackoff(d time.Duration) func() {
+	old := baseBackoff
+	baseBackoff = d
+	return func() { baseBackoff = old }
+}
+
+// OnTransient retries fn on transient API errors with exponential backoff.
+// Any other error is returned immediately.
+func OnTransient(ctx context.Context, fn func() error, maxRetries int) error {
+	return OnTransientWithBackoff(ctx, fn, maxRetries, 0, 0)
+}
+
+// OnTransientWithBackoff behaves like OnTransient, starting the backoff at
+// base and doubling it after each retry up to max. A zero base uses the
+// default initial backoff, and a zero max leaves the backoff uncapped.
+func OnTransientWithBackoff(ctx context.Context, fn func() error, maxRetries int, base, max time.Duration) error {
+	if base <= 0 {
+		base = baseBackoff
+	}
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("context cancelled: %w", err)
+		}
+
+		lastErr = fn()
+		if lastErr == nil {
+			return nil
+		}
+
+		if !IsTransient(lastErr) {
+			return lastErr
+		}
+
+		if attempt < maxRetries {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("context cancelled during retry backoff: %w", ctx.Err())
+			case <-time.After(Backoff(attempt, base, max)):
+			}
+		}
+	}
+	return fmt.Errorf("max retries (%d) exceeded: %w", maxRetries, lastErr)
+}
+
+// Backoff returns the wait before retry attempt+1: base doubled attempt
+// times, capped at max when max is positive.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	backoff := base
+	for i := 0; i < attempt; i++ {
+		if max > 0 && backoff >= max {
+			break
+		}
+		backoff *= 2
+	}
+	if max > 0 && backoff > max {
+		return max
+	}
+	return backoff
+}
+
+// IsTransient returns true for API errors that are worth retrying.
+func IsTransient(err error) bool {
+	return apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err)
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/opdev/virtwork/internal/retry"
+)
+
+var _ = Describe("OnTransient", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		DeferCleanup(retry.SetBaseBackoff(time.Millisecond))
+	})
+
+	It("should retry transient errors until fn succeeds", func() {
+		calls := 0
+		err := retry.OnTransient(ctx, func() error {
+			calls++
+			if calls <= 2 {
+				return apierrors.NewTooManyRequests("slow down", 1)
+			}
+			return nil
+		}, retry.DefaultMaxRetries)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal(3))
+	})
+
+	It("should return non-transient errors without retrying", func() {
+		calls := 0
+		err := retry.OnTransient(ctx, func() error {
+			calls++
+			return apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "s", nil)
+		}, retry.DefaultMaxRetries)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should give up after maxRetries", func() {
+		calls := 0
+		err := retry.OnTransient(ctx, func() error {
+			calls++
+			return apierrors.NewServiceUnavailable("down")
+		}, 2)
+		Expect(err).To(MatchError(ContainSubstring("max retries (2) exceeded")))
+		Expect(apierrors.IsServiceUnavailable(err)).To(BeTrue())
+		Expect(calls).To(Equal(3))
+	})
+
+	It("should stop when the context is cancelled", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		err := retry.OnTransient(cancelled, func() error { return nil }, retry.DefaultMaxRetries)
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+	})
+})
+
+var _ = Describe("OnTransientWithBackoff", func() {
+	It("should use the given backoff and give up after maxRetries", func() {
+		calls := 0
+		err := retry.OnTransientWithBackoff(context.Background(), func() error {
+			calls++
+			return apierrors.NewTooManyRequests("slow down", 1)
+		}, 3, time.Millisecond, 2*time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("max retries (3) exceeded")))
+		Expect(apierrors.IsTooManyRequests(err)).To(BeTrue())
+		Expect(calls).To(Equal(4))
+	})
+})
+
+var _ = Describe("Backoff", func() {
+	It("should double the base for each attempt", func() {
+		Expect(retry.Backoff(0, time.Second, 0)).To(Equal(time.Second))
+		Expect(retry.Backoff(1, time.Second, 0)).To(Equal(2 * time.Second))
+		Expect(retry.Backoff(3, time.Second, 0)).To(Equal(8 * time.Second))
+	})
+
+	It("should cap the backoff at max", func() {
+		Expect(retry.Backoff(4, time.Second, 10*time.Second)).To(Equal(10 * time.Second))
+		Expect(retry.Backoff(60, time.Second, 30*time.Second)).To(Equal(30 * time.Second))
+	})
+
+	It("should cap a base above max", func() {
+		Expect(retry.Backoff(0, time.Minute, 30*time.Second)).To(Equal(30 * time.Second))
+	})
+})
+
+var _ = Describe("IsTransient", func() {
+	It("should classify API errors", func() {
+		gr := schema.GroupResource{Resource: "services"}
+		Expect(retry.IsTransient(apierrors.NewServerTimeout(gr, "create", 1))).To(BeTrue())
+		Expect(retry.IsTransient(apierrors.NewInternalError(errors.New("boom")))).To(BeTrue())
+		Expect(retry.IsTransient(apierrors.NewNotFound(gr, "svc"))).To(BeFalse())
+		Expect(retry.IsTransient(errors.New("plain"))).To(BeFalse())
+	})
+})
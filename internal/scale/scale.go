@@ -0,0 +1,179 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package scale
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/resources"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+// Plan is the change needed to bring one workload of a run to a target VM
+// count. VM names follow the virtwork-<workload>-<index> scheme used by run.
+type Plan struct {
+	Workload  string
+	RunID     string
+	Namespace string
+	Existing  []string // current VMs, lowest index first
+	Create    []string // VMs to add, continuing after the highest index
+	Delete    []string // VMs to remove, highest index first
+}
+
+// Result reports the VMs Apply created and deleted.
+type Result struct {
+	Created []string
+	Deleted []string
+}
+
+// indexedVM is an existing VM name with its parsed index.
+type indexedVM struct {
+	name  string
+	index int
+}
+
+// ComputePlan lists the VMs of the workload in the given run and works out
+// which VMs to create or delete to reach target. It fails if the run has no
+// VMs for the workload, since there is nothing to scale, or if any of them
+// belongs to a multi-VM workload role.
+func ComputePlan(ctx context.Context, c client.Client, namespace, runID, workload string, target int) (*Plan, error) {
+	if target < 1 {
+		return nil, fmt.Errorf("vm-count must be at least 1, got %d (use cleanup to remove a run)", target)
+	}
+
+	vms, err := vm.ListVMs(ctx, c, namespace, map[string]string{
+		constants.LabelManagedBy: constants.ManagedByValue,
+		constants.LabelComponent: workload,
+		constants.LabelRunID:     runID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(vms) == 0 {
+		return nil, fmt.Errorf("no %s VMs found for run %s in namespace %s", workload, runID, namespace)
+	}
+
+	prefix := fmt.Sprintf("virtwork-%s-", workload)
+	existing := make([]indexedVM, 0, len(vms))
+	for _, v := range vms {
+		if role := v.Labels[constants.LabelRole]; role != "" {
+			return nil, fmt.Errorf("VM %s has role %q: scaling multi-VM workloads is not supported", v.Name, role)
+		}
+		index, err := strconv.Atoi(strings.TrimPrefix(v.Name, prefix))
+		if err != nil || !strings.HasPrefix(v.Name, prefix) {
+			return nil, fmt.Errorf("VM %s does not follow the %s<index> naming scheme", v.Name, prefix)
+		}
+		existing = append(existing, indexedVM{name: v.Name, index: index})
+	}
+	sort.Slice(existing, func(i, j int) bool { return existing[i].index < existing[j].index })
+
+	plan := &Plan{Workload: workload, RunID: runID, Namespace: namespace}
+	for _, e := range existing {
+		plan.Existing = append(plan.Existing, e.name)
+	}
+
+	next := existing[len(existing)-1].index + 1
+	for i := len(existing); i < target; i++ {
+		plan.Create = append(plan.Create, fmt.Sprintf("%s%d", prefix, next))
+		next++
+	}
+	for i := len(existing) - 1; i >= target; i-- {
+		plan.Delete = append(plan.Delete, existing[i].name)
+	}
+	return plan, nil
+}
+
+// Apply carries out the plan. Each new VM gets its own cloud-init secret,
+// created before the VM as run does; specFor returns the spec for a new VM
+// name. Deleted VMs have their cloud-init secrets removed as well. Apply stops
+// at the first error and reports what it changed up to that point.
+func Apply(ctx context.Context, c client.Client, plan *Plan, specFor func(vmName string) *vm.VMSpecOpts) (*Result, error) {
+	result := &Result{}
+
+	for _, name := range plan.Create {
+		spec := specFor(name)
+		secretName := name + "-cloudinit"
+		secretLabels := map[string]string{
+			constants.LabelAppName:   spec.Labels[constants.LabelAppName],
+			constants.LabelManagedBy: constants.ManagedByValue,
+			constants.LabelComponent: plan.Workload,
+			constants.LabelRunID:     plan.RunID,
+		}
+		createSecret := resources.CreateCloudInitSecret
+		if spec.CompressCloudInit {
+			createSecret = resources.CreateCompressedCloudInitSecret
+		}
+		if err := createSecret(ctx, c, secretName, plan.Namespace, spec.CloudInitUserdata, secretLabels); err != nil {
+			return result, fmt.Errorf("creating cloud-init secret for %q: %w", name, err)
+		}
+		spec.CloudInitSecretName = secretName
+
+		if err := vm.CreateVM(ctx, c, vm.BuildVMSpec(*spec)); err != nil {
+			return result, fmt.Errorf("creating VM %q: %w", name, err)
+		}
+		result.Created = append(result.Created, name)
+	}
+
+	for _, name := range plan.Delete {
+		if err := vm.DeleteVM(ctx, c, name, plan.Namespace); err != nil {
+			return result, fmt.Errorf("deleting VM %q: %w", name, err)
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name + "-cloudinit", Namespace: plan.Namespace}}
+		if err := client.IgnoreNotFound(c.Delete(ctx, secret)); err != nil {
+			return result, fmt.Errorf("deleting cloud-init secret for %q: %w", name, err)
+		}
+		result.Deleted = append(result.Deleted, name)
+	}
+
+	return result, nil
+}
+
+// Gaps compares the VMs a run should have with the VMs it has.
+type Gaps struct {
+	Existing []string // planned VMs that exist, in plan order
+	Missing  []string // planned VMs that do not exist, in plan order
+	Extra    []string // VMs of the run that are not planned, sorted
+}
+
+// FindGaps lists the VMs labeled with runID in namespace and splits the
+// planned VM names into those that exist and those that are missing. VMs of
+// the run that are not in planned are reported as Extra and left alone.
+func FindGaps(ctx context.Context, c client.Client, namespace, runID string, planned []string) (*Gaps, error) {
+	vms, err := vm.ListVMs(ctx, c, namespace, map[string]string{
+		constants.LabelManagedBy: constants.ManagedByValue,
+		constants.LabelRunID:     runID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(vms))
+	for _, v := range vms {
+		existing[v.Name] = true
+	}
+
+	gaps := &Gaps{}
+	for _, name := range planned {
+		if existing[name] {
+			gaps.Existing = append(gaps.Existing, name)
+			delete(existing, name)
+		} else {
+			gaps.Missing = append(gaps.Missing, name)
+		}
+	}
+	for name := range existing {
+		gaps.Extra = append(gaps.Extra, name)
+	}
+	sort.Strings(gaps.Extra)
+	return gaps, nil
+}
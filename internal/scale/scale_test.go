@@ -0,0 +1,282 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package scale_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/scale"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+const (
+	testNamespace = "virtwork"
+	testRunID     = "run-1"
+)
+
+func runVM(name, component, runID string) *kubevirtv1.VirtualMachine {
+	return &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.LabelManagedBy: constants.ManagedByValue,
+				constants.LabelComponent: component,
+				constants.LabelRunID:     runID,
+			},
+		},
+	}
+}
+
+func cloudInitSecret(vmName string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: vmName + "-cloudinit", Namespace: testNamespace},
+	}
+}
+
+func specFor(vmName string) *vm.VMSpecOpts {
+	return &vm.VMSpecOpts{
+		Name:               vmName,
+		Namespace:          testNamespace,
+		ContainerDiskImage: "test-image",
+		CloudInitUserdata:  "#cloud-config\n",
+		CPUCores:           2,
+		Memory:             "2Gi",
+		Labels: map[string]string{
+			constants.LabelAppName:   "virtwork-cpu",
+			constants.LabelManagedBy: constants.ManagedByValue,
+			constants.LabelComponent: "cpu",
+			constants.LabelRunID:     testRunID,
+		},
+	}
+}
+
+func vmNames(ctx context.Context, c client.Client) []string {
+	vms, err := vm.ListVMs(ctx, c, testNamespace, map[string]string{constants.LabelRunID: testRunID})
+	Expect(err).NotTo(HaveOccurred())
+	var names []string
+	for _, v := range vms {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+var _ = Describe("ComputePlan", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	newClient := func(objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	It("should continue the numbering when scaling up", func() {
+		c := newClient(runVM("virtwork-cpu-0", "cpu", testRunID), runVM("virtwork-cpu-1", "cpu", testRunID))
+
+		plan, err := scale.ComputePlan(ctx, c, testNamespace, testRunID, "cpu", 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Existing).To(Equal([]string{"virtwork-cpu-0", "virtwork-cpu-1"}))
+		Expect(plan.Create).To(Equal([]string{"virtwork-cpu-2", "virtwork-cpu-3"}))
+		Expect(plan.Delete).To(BeEmpty())
+	})
+
+	It("should number after the highest index when there are gaps", func() {
+		c := newClient(runVM("virtwork-cpu-0", "cpu", testRunID), runVM("virtwork-cpu-5", "cpu", testRunID))
+
+		plan, err := scale.ComputePlan(ctx, c, testNamespace, testRunID, "cpu", 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Create).To(Equal([]string{"virtwork-cpu-6"}))
+	})
+
+	It("should delete the highest-indexed VMs when scaling down", func() {
+		c := newClient(
+			runVM("virtwork-cpu-0", "cpu", testRunID),
+			runVM("virtwork-cpu-2", "cpu", testRunID),
+			runVM("virtwork-cpu-10", "cpu", testRunID),
+		)
+
+		plan, err := scale.ComputePlan(ctx, c, testNamespace, testRunID, "cpu", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Create).To(BeEmpty())
+		Expect(plan.Delete).To(Equal([]string{"virtwork-cpu-10", "virtwork-cpu-2"}))
+	})
+
+	It("should plan nothing at the current count", func() {
+		c := newClient(runVM("virtwork-cpu-0", "cpu", testRunID))
+
+		plan, err := scale.ComputePlan(ctx, c, testNamespace, testRunID, "cpu", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Create).To(BeEmpty())
+		Expect(plan.Delete).To(BeEmpty())
+	})
+
+	It("should only consider VMs of the given workload and run", func() {
+		c := newClient(
+			runVM("virtwork-cpu-0", "cpu", testRunID),
+			runVM("virtwork-cpu-7", "cpu", "other-run"),
+			runVM("virtwork-memory-0", "memory", testRunID),
+		)
+
+		plan, err := scale.ComputePlan(ctx, c, testNamespace, testRunID, "cpu", 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Existing).To(Equal([]string{"virtwork-cpu-0"}))
+		Expect(plan.Create).To(Equal([]string{"virtwork-cpu-1"}))
+	})
+
+	It("should fail when the run has no VMs for the workload", func() {
+		c := newClient(runVM("virtwork-cpu-0", "cpu", "other-run"))
+
+		_, err := scale.ComputePlan(ctx, c, testNamespace, testRunID, "cpu", 2)
+		Expect(err).To(MatchError(ContainSubstring("no cpu VMs found for run run-1")))
+	})
+
+	It("should reject multi-VM workload roles", func() {
+		server := runVM("virtwork-network-server-0", "network", testRunID)
+		server.Labels[constants.LabelRole] = "server"
+		c := newClient(server)
+
+		_, err := scale.ComputePlan(ctx, c, testNamespace, testRunID, "network", 2)
+		Expect(err).To(MatchError(ContainSubstring("scaling multi-VM workloads is not supported")))
+	})
+
+	It("should reject a target below one", func() {
+		_, err := scale.ComputePlan(ctx, newClient(), testNamespace, testRunID, "cpu", 0)
+		Expect(err).To(MatchError(ContainSubstring("vm-count must be at least 1")))
+	})
+})
+
+var _ = Describe("Apply", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should create the planned VMs with their cloud-init secrets", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(runVM("virtwork-cpu-0", "cpu", testRunID)).Build()
+		plan, err := scale.ComputePlan(ctx, c, testNamespace, testRunID, "cpu", 3)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := scale.Apply(ctx, c, plan, specFor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Created).To(HaveLen(2))
+		Expect(result.Deleted).To(BeEmpty())
+		Expect(vmNames(ctx, c)).To(ConsistOf("virtwork-cpu-0", "virtwork-cpu-1", "virtwork-cpu-2"))
+
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "virtwork-cpu-2-cloudinit", Namespace: testNamespace}, secret)).To(Succeed())
+		Expect(secret.Labels).To(HaveKeyWithValue(constants.LabelRunID, testRunID))
+		Expect(secret.Labels).To(HaveKeyWithValue(constants.LabelComponent, "cpu"))
+
+		created := &kubevirtv1.VirtualMachine{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "virtwork-cpu-2", Namespace: testNamespace}, created)).To(Succeed())
+		Expect(created.Labels).To(HaveKeyWithValue(constants.LabelRunID, testRunID))
+		volumes := created.Spec.Template.Spec.Volumes
+		Expect(volumes).To(ContainElement(HaveField("VolumeSource.CloudInitNoCloud.UserDataSecretRef.Name",
+			"virtwork-cpu-2-cloudinit")))
+	})
+
+	It("should delete the planned VMs and their cloud-init secrets", func() {
+		var objs []client.Object
+		for i := 0; i < 4; i++ {
+			name := fmt.Sprintf("virtwork-cpu-%d", i)
+			objs = append(objs, runVM(name, "cpu", testRunID), cloudInitSecret(name))
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		plan, err := scale.ComputePlan(ctx, c, testNamespace, testRunID, "cpu", 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := scale.Apply(ctx, c, plan, specFor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Created).To(BeEmpty())
+		Expect(result.Deleted).To(Equal([]string{"virtwork-cpu-3", "virtwork-cpu-2", "virtwork-cpu-1"}))
+		Expect(vmNames(ctx, c)).To(ConsistOf("virtwork-cpu-0"))
+
+		err = c.Get(ctx, client.ObjectKey{Name: "virtwork-cpu-3-cloudinit", Namespace: testNamespace}, &corev1.Secret{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "virtwork-cpu-0-cloudinit", Namespace: testNamespace}, &corev1.Secret{})).To(Succeed())
+	})
+
+	It("should tolerate a missing cloud-init secret when deleting", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			runVM("virtwork-cpu-0", "cpu", testRunID), runVM("virtwork-cpu-1", "cpu", testRunID),
+		).Build()
+		plan, err := scale.ComputePlan(ctx, c, testNamespace, testRunID, "cpu", 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := scale.Apply(ctx, c, plan, specFor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Deleted).To(Equal([]string{"virtwork-cpu-1"}))
+	})
+})
+
+var _ = Describe("FindGaps", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	planned := []string{"virtwork-cpu-0", "virtwork-cpu-1", "virtwork-cpu-2", "virtwork-disk-0"}
+
+	It("should report only the planned VMs the run is missing", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			runVM("virtwork-cpu-0", "cpu", testRunID),
+			runVM("virtwork-cpu-2", "cpu", testRunID),
+			runVM("virtwork-cpu-1", "cpu", "other-run"),
+		).Build()
+
+		gaps, err := scale.FindGaps(ctx, c, testNamespace, testRunID, planned)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gaps.Existing).To(Equal([]string{"virtwork-cpu-0", "virtwork-cpu-2"}))
+		Expect(gaps.Missing).To(Equal([]string{"virtwork-cpu-1", "virtwork-disk-0"}))
+		Expect(gaps.Extra).To(BeEmpty())
+	})
+
+	It("should report every planned VM as missing when the run has none", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		gaps, err := scale.FindGaps(ctx, c, testNamespace, testRunID, planned)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gaps.Existing).To(BeEmpty())
+		Expect(gaps.Missing).To(Equal(planned))
+	})
+
+	It("should report VMs of the run that are not planned as extra", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			runVM("virtwork-cpu-0", "cpu", testRunID),
+			runVM("virtwork-memory-1", "memory", testRunID),
+			runVM("virtwork-memory-0", "memory", testRunID),
+		).Build()
+
+		gaps, err := scale.FindGaps(ctx, c, testNamespace, testRunID, planned)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gaps.Extra).To(Equal([]string{"virtwork-memory-0", "virtwork-memory-1"}))
+	})
+})
@@ -0,0 +1,111 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+// VMStatus describes the current state of a single virtwork-managed VM.
+type VMStatus struct {
+	Name      string
+	Component string
+	Role      string
+	RunID     string
+	// Phase is the VMI phase, or empty when the VM has no running instance.
+	Phase kubevirtv1.VirtualMachineInstancePhase
+	// VMReady, AgentConnected, Node, and Message are the VM's live state as
+	// reported by vm.GetVMStatus.
+	VMReady        bool
+	AgentConnected bool
+	Node           string
+	Message        string
+	// CreatedAt and ReadyAt are the audit timestamps, empty when unknown.
+	CreatedAt string
+	ReadyAt   string
+}
+
+// TimestampLookup returns the recorded creation and readiness timestamps
+// for a VM. It is satisfied by *audit.SQLiteAuditor and *audit.PostgresAuditor.
+type TimestampLookup interface {
+	VMTimestamps(ctx context.Context, runID, vmName string) (createdAt, readyAt string, err error)
+}
+
+// Collect lists the virtwork-managed VMs in the namespace and reports the
+// phase of each VM's instance along with its readiness, guest agent, node,
+// and the reason it is not ready. If runID is non-empty, only VMs from that run
+// are included. When timestamps is non-nil, each VM is cross-referenced
+// against it for its recorded created/ready times. Results are sorted by name.
+func Collect(ctx context.Context, c client.Client, namespace, runID string, timestamps TimestampLookup) ([]VMStatus, error) {
+	labels := map[string]string{
+		constants.LabelManagedBy: constants.ManagedByValue,
+	}
+	if runID != "" {
+		labels[constants.LabelRunID] = runID
+	}
+
+	vms, err := vm.ListVMs(ctx, c, namespace, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]VMStatus, 0, len(vms))
+	for _, v := range vms {
+		s := VMStatus{
+			Name:      v.Name,
+			Component: v.Labels[constants.LabelComponent],
+			Role:      v.Labels[constants.LabelRole],
+			RunID:     v.Labels[constants.LabelRunID],
+		}
+
+		phase, err := vm.GetVMIPhase(ctx, c, v.Name, namespace)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		s.Phase = phase
+
+		live, err := vm.GetVMStatus(ctx, c, v.Name, namespace)
+		if err != nil {
+			return nil, err
+		}
+		s.VMReady = live.Ready
+		s.AgentConnected = live.AgentConnected
+		s.Node = live.NodeName
+		s.Message = live.Message
+
+		if timestamps != nil {
+			s.CreatedAt, s.ReadyAt, err = timestamps.VMTimestamps(ctx, s.RunID, s.Name)
+			if err != nil {
+				return nil, fmt.Errorf("looking up audit timestamps for %s: %w", s.Name, err)
+			}
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses, nil
+}
+
+// CountFailed returns how many of the VMs are in the Failed phase.
+func CountFailed(statuses []VMStatus) int {
+	n := 0
+	for _, s := range statuses {
+		if s.Phase == kubevirtv1.Failed {
+			n++
+		}
+	}
+	return n
+}
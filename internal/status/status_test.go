@@ -0,0 +1,186 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package status_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/status"
+)
+
+const testNamespace = "virtwork"
+
+func managedVM(name, component, role, runID string) *kubevirtv1.VirtualMachine {
+	labels := map[string]string{
+		constants.LabelManagedBy: constants.ManagedByValue,
+		constants.LabelComponent: component,
+		constants.LabelRunID:     runID,
+	}
+	if role != "" {
+		labels[constants.LabelRole] = role
+	}
+	return &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: labels},
+	}
+}
+
+func vmiInPhase(name string, phase kubevirtv1.VirtualMachineInstancePhase) *kubevirtv1.VirtualMachineInstance {
+	return &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: phase},
+	}
+}
+
+// fakeTimestamps is a TimestampLookup keyed by VM name.
+type fakeTimestamps map[string][2]string
+
+func (f fakeTimestamps) VMTimestamps(_ context.Context, _ string, vmName string) (string, string, error) {
+	ts, ok := f[vmName]
+	if !ok {
+		return "", "", fmt.Errorf("no record for %s", vmName)
+	}
+	return ts[0], ts[1], nil
+}
+
+var _ = Describe("Collect", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+		c      client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			managedVM("virtwork-network-server-0", "network", "server", "run-a"),
+			managedVM("virtwork-cpu-0", "cpu", "", "run-a"),
+			managedVM("virtwork-memory-0", "memory", "", "run-b"),
+			vmiInPhase("virtwork-cpu-0", kubevirtv1.Running),
+			vmiInPhase("virtwork-network-server-0", kubevirtv1.Failed),
+			&kubevirtv1.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: testNamespace},
+			},
+		).Build()
+	})
+
+	It("should report component, role, and phase for each managed VM sorted by name", func() {
+		statuses, err := status.Collect(ctx, c, testNamespace, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(HaveLen(3))
+
+		Expect(statuses[0].Name).To(Equal("virtwork-cpu-0"))
+		Expect(statuses[0].Component).To(Equal("cpu"))
+		Expect(statuses[0].Phase).To(Equal(kubevirtv1.Running))
+
+		Expect(statuses[2].Name).To(Equal("virtwork-network-server-0"))
+		Expect(statuses[2].Role).To(Equal("server"))
+		Expect(statuses[2].Phase).To(Equal(kubevirtv1.Failed))
+	})
+
+	It("should leave the phase empty for VMs without an instance", func() {
+		statuses, err := status.Collect(ctx, c, testNamespace, "run-b", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].Name).To(Equal("virtwork-memory-0"))
+		Expect(statuses[0].Phase).To(BeEmpty())
+	})
+
+	It("should report readiness, guest agent, node, and message from the VM and its VMI", func() {
+		ready := managedVM("virtwork-disk-0", "disk", "", "run-c")
+		ready.Status.Ready = true
+		starting := managedVM("virtwork-disk-1", "disk", "", "run-c")
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			ready,
+			starting,
+			&kubevirtv1.VirtualMachineInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "virtwork-disk-0", Namespace: testNamespace},
+				Status: kubevirtv1.VirtualMachineInstanceStatus{
+					Phase:    kubevirtv1.Running,
+					NodeName: "worker-0",
+					Conditions: []kubevirtv1.VirtualMachineInstanceCondition{
+						{Type: kubevirtv1.VirtualMachineInstanceAgentConnected, Status: corev1.ConditionTrue},
+						{Type: kubevirtv1.VirtualMachineInstanceReady, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			&kubevirtv1.VirtualMachineInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "virtwork-disk-1", Namespace: testNamespace},
+				Status: kubevirtv1.VirtualMachineInstanceStatus{
+					Phase:    kubevirtv1.Scheduling,
+					NodeName: "worker-1",
+					Conditions: []kubevirtv1.VirtualMachineInstanceCondition{
+						{Type: kubevirtv1.VirtualMachineInstanceReady, Status: corev1.ConditionFalse, Message: "virt-launcher pod not ready"},
+					},
+				},
+			},
+		).Build()
+
+		statuses, err := status.Collect(ctx, c, testNamespace, "run-c", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(HaveLen(2))
+
+		Expect(statuses[0].VMReady).To(BeTrue())
+		Expect(statuses[0].AgentConnected).To(BeTrue())
+		Expect(statuses[0].Node).To(Equal("worker-0"))
+		Expect(statuses[0].Message).To(BeEmpty())
+
+		Expect(statuses[1].VMReady).To(BeFalse())
+		Expect(statuses[1].AgentConnected).To(BeFalse())
+		Expect(statuses[1].Node).To(Equal("worker-1"))
+		Expect(statuses[1].Message).To(Equal("virt-launcher pod not ready"))
+	})
+
+	It("should filter by run ID", func() {
+		statuses, err := status.Collect(ctx, c, testNamespace, "run-a", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(HaveLen(2))
+		for _, s := range statuses {
+			Expect(s.RunID).To(Equal("run-a"))
+		}
+	})
+
+	It("should fill in audit timestamps when a lookup is provided", func() {
+		lookup := fakeTimestamps{
+			"virtwork-cpu-0":            {"2026-01-01T00:00:00Z", "2026-01-01T00:05:00Z"},
+			"virtwork-memory-0":         {"2026-01-02T00:00:00Z", ""},
+			"virtwork-network-server-0": {"2026-01-01T00:00:00Z", ""},
+		}
+
+		statuses, err := status.Collect(ctx, c, testNamespace, "", lookup)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses[0].CreatedAt).To(Equal("2026-01-01T00:00:00Z"))
+		Expect(statuses[0].ReadyAt).To(Equal("2026-01-01T00:05:00Z"))
+		Expect(statuses[1].ReadyAt).To(BeEmpty())
+	})
+
+	It("should return an error when the timestamp lookup fails", func() {
+		_, err := status.Collect(ctx, c, testNamespace, "", fakeTimestamps{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("audit timestamps"))
+	})
+})
+
+var _ = Describe("CountFailed", func() {
+	It("should count only VMs in the Failed phase", func() {
+		statuses := []status.VMStatus{
+			{Name: "a", Phase: kubevirtv1.Running},
+			{Name: "b", Phase: kubevirtv1.Failed},
+			{Name: "c"},
+			{Name: "d", Phase: kubevirtv1.Failed},
+		}
+		Expect(status.CountFailed(statuses)).To(Equal(2))
+	})
+})
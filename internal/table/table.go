@@ -0,0 +1,80 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+// Package table renders rows as aligned text tables whose columns can be
+// picked by name, as the read commands do for --columns.
+package table
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Column is one column of a table of T rows.
+type Column[T any] struct {
+	// Name selects the column in --columns, e.g. "run-id".
+	Name string
+	// Header is printed above the column, e.g. "RUN ID".
+	Header string
+	// Value renders the column for one row.
+	Value func(T) string
+}
+
+// Names returns the names of columns, in order.
+func Names[T any](columns []Column[T]) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Select returns the columns of all named by names, in the order of names.
+// Names match case-insensitively. An unknown or repeated name is an error
+// listing the valid names.
+func Select[T any](all []Column[T], names []string) ([]Column[T], error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no columns selected (valid columns: %s)", strings.Join(Names(all), ", "))
+	}
+	selected := make([]Column[T], 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if seen[name] {
+			return nil, fmt.Errorf("column %q selected more than once", name)
+		}
+		seen[name] = true
+		found := false
+		for _, c := range all {
+			if c.Name == name {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q (valid columns: %s)", name, strings.Join(Names(all), ", "))
+		}
+	}
+	return selected, nil
+}
+
+// Render writes a header line and one line per row, with the columns
+// aligned two spaces apart.
+func Render[T any](w io.Writer, columns []Column[T], rows []T) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	cells := make([]string, len(columns))
+	for i, c := range columns {
+		cells[i] = c.Header
+	}
+	fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	for _, row := range rows {
+		for i, c := range columns {
+			cells[i] = c.Value(row)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
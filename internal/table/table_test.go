@@ -0,0 +1,75 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package table_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/table"
+)
+
+type run struct {
+	id     string
+	status string
+	vms    int
+}
+
+var columns = []table.Column[run]{
+	{Name: "run-id", Header: "RUN ID", Value: func(r run) string { return r.id }},
+	{Name: "status", Header: "STATUS", Value: func(r run) string { return r.status }},
+	{Name: "vms", Header: "VMS", Value: func(r run) string { return strconv.Itoa(r.vms) }},
+}
+
+var _ = Describe("Select", func() {
+	It("should return the named columns in the requested order", func() {
+		selected, err := table.Select(columns, []string{"vms", "RUN-ID"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(table.Names(selected)).To(Equal([]string{"vms", "run-id"}))
+	})
+
+	It("should reject an unknown column and list the valid ones", func() {
+		_, err := table.Select(columns, []string{"run-id", "owner"})
+		Expect(err).To(MatchError(`unknown column "owner" (valid columns: run-id, status, vms)`))
+	})
+
+	It("should reject a repeated column", func() {
+		_, err := table.Select(columns, []string{"status", "status"})
+		Expect(err).To(MatchError(`column "status" selected more than once`))
+	})
+
+	It("should reject an empty selection", func() {
+		_, err := table.Select(columns, nil)
+		Expect(err).To(MatchError(ContainSubstring("no columns selected")))
+	})
+})
+
+var _ = Describe("Render", func() {
+	It("should align the selected columns under their headers", func() {
+		selected, err := table.Select(columns, []string{"status", "run-id"})
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		Expect(table.Render(&buf, selected, []run{
+			{id: "run-a", status: "success", vms: 3},
+			{id: "run-bb", status: "failed"},
+		})).To(Succeed())
+		Expect(buf.String()).To(Equal(strings.Join([]string{
+			"STATUS   RUN ID",
+			"success  run-a",
+			"failed   run-bb",
+			"",
+		}, "\n")))
+	})
+
+	It("should print only the header without rows", func() {
+		var buf bytes.Buffer
+		Expect(table.Render(&buf, columns, nil)).To(Succeed())
+		Expect(strings.Fields(buf.String())).To(Equal([]string{"RUN", "ID", "STATUS", "VMS"}))
+	})
+})
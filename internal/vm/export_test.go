@@ -3,12 +3,14 @@
 
 package vm
 
-import "time"
+import (
+	"time"
+
+	"github.com/opdev/virtwork/internal/retry"
+)
 
 // SetBaseRetryBackoff overrides the retry backoff duration for testing.
 // Returns a function that restores the original value.
 func SetBaseRetryBackoff(d time.Duration) func() {
-	old := baseRetryBackoff
-	baseRetryBackoff = d
-	return func() { baseRetryBackoff = old }
+	return retry.SetBaseBackoff(d)
 }
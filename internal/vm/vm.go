@@ -5,42 +5,165 @@ package vm
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientretry "k8s.io/client-go/util/retry"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-)
-
-const defaultMaxRetries = 5
 
-var baseRetryBackoff = time.Second
+	"github.com/opdev/virtwork/internal/cloudinit"
+	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/retry"
+)
 
 // VMSpecOpts contains all parameters needed to construct a VirtualMachine spec.
 type VMSpecOpts struct {
 	Name                string
 	Namespace           string
 	ContainerDiskImage  string
+	ImagePullSecret     string // Registry Secret in Namespace for pulling ContainerDiskImage
 	CloudInitUserdata   string
 	CloudInitSecretName string // When set, use UserDataSecretRef instead of inline
-	CPUCores            int
-	Memory              string
-	Labels              map[string]string
+	// CompressCloudInit gzips inline userdata and emits it as userDataBase64.
+	// Secret-backed userdata must be compressed when the secret is created.
+	CompressCloudInit bool
+	CPUCores          int
+	Memory            string
+	Labels            map[string]string
+	// TemplateAnnotations are set on spec.template.metadata only, so they
+	// reach the VMI and its virt-launcher pod but not the VM object. Multus
+	// network attachments (k8s.v1.cni.cncf.io/networks) must be set here.
+	TemplateAnnotations map[string]string
 	ExtraDisks          []kubevirtv1.Disk
 	ExtraVolumes        []kubevirtv1.Volume
 	DataVolumeTemplates []kubevirtv1.DataVolumeTemplateSpec
+	// Filesystems are shared filesystem devices, such as virtiofs, each
+	// backed by the ExtraVolumes entry of the same name. The guest mounts a
+	// filesystem by its name, which KubeVirt uses as the virtiofs tag.
+	Filesystems []kubevirtv1.Filesystem
+	// GPUs are passed through to the guest, each naming the device plugin
+	// resource, such as a PCI GPU or a mediated vGPU type, it is taken from.
+	GPUs []kubevirtv1.GPU
+	// ClockTimezone sets the guest clock offset: "UTC" or an IANA timezone.
+	// When empty (and no timers are set) KubeVirt's default clock is used.
+	ClockTimezone string
+	// Timers enables or disables guest timers by name: hpet, hyperv, kvm, pit.
+	Timers map[string]bool
+	// CPUModel sets the guest CPU model, e.g. "host-passthrough" or a named
+	// model. When empty KubeVirt's cluster default is used.
+	CPUModel string
+	// DedicatedCPUPlacement pins each vCPU to a host CPU. The VM then also
+	// requests CPU and memory limits equal to its requests so that it gets
+	// the Guaranteed QoS class KubeVirt requires.
+	DedicatedCPUPlacement bool
+	// Hugepages, when set, backs guest memory with hugepages of this page
+	// size ("2Mi" or "1Gi"). The memory request and limit are then rounded
+	// up to a whole number of pages.
+	Hugepages string
+	// MemoryLimit and CPULimit, when set, are the VM's memory and CPU limits.
+	// They are ignored with DedicatedCPUPlacement, which sets limits equal to
+	// the requests.
+	MemoryLimit string
+	CPULimit    string
+	// OvercommitGuestOverhead leaves KubeVirt's estimate of the virt-launcher
+	// overhead out of the memory request, so more VMs fit on a node at the
+	// risk of the pod being OOM-killed under pressure.
+	OvercommitGuestOverhead bool
+	// Sockets and Threads set the guest CPU topology. Zero leaves KubeVirt's
+	// default of one socket and one thread per core.
+	Sockets int
+	Threads int
+	// NodeSelector, Tolerations, and Affinity constrain which nodes the VMI
+	// is scheduled on. Empty values leave scheduling unconstrained.
+	NodeSelector map[string]string
+	Tolerations  []corev1.Toleration
+	Affinity     *corev1.Affinity
+	// Spread, when set, adds a pod anti-affinity term that keeps VMs sharing
+	// this VM's component label on separate nodes. It is merged into Affinity.
+	Spread *SpreadOpts
+	// ServiceAccountName, when set, attaches that ServiceAccount's token,
+	// CA certificate, and namespace as a read-only disk with serial
+	// ServiceAccountDiskSerial, so the guest can call the Kubernetes API.
+	ServiceAccountName string
+	// ReadinessProbe, when set, gates the VMI's Ready condition on the probe
+	// succeeding rather than on the guest having booted.
+	ReadinessProbe *kubevirtv1.Probe
+	// DataDiskBlockSize, when set, is the block size presented to the guest
+	// for each extra disk whose volume is a DataVolume. Disks that already
+	// set a block size keep it.
+	DataDiskBlockSize *kubevirtv1.BlockSize
+	// StartStopped creates the VM with spec.running false, so it is not
+	// booted until StartVM is called for it.
+	StartStopped bool
+	// SecondaryNetworks attach extra NICs on Multus networks after the
+	// default pod network, which stays first for readiness checks and
+	// Services. See ValidateSecondaryNetworks.
+	SecondaryNetworks []SecondaryNetwork
+}
+
+// ServiceAccountDiskSerial is the serial of the disk attached for
+// VMSpecOpts.ServiceAccountName. The guest finds it at
+// /dev/disk/by-id/virtio-<serial>.
+const ServiceAccountDiskSerial = "virtwork-sa"
+
+// SpreadOpts sets how strongly the VMs of one workload avoid sharing a node.
+type SpreadOpts struct {
+	// Required makes the anti-affinity term a hard scheduling constraint.
+	// Otherwise it is a preference with the given Weight (1-100).
+	Required bool
+	Weight   int32
+}
+
+// PodNetworkName is the name of the interface and network that connect a VM
+// to the pod network with masquerade binding.
+const PodNetworkName = "default"
+
+// SecondaryNetwork is an extra NIC on a Multus NetworkAttachmentDefinition.
+type SecondaryNetwork struct {
+	// Name names the VM's interface and network.
+	Name string
+	// NADName is the NetworkAttachmentDefinition, as "name" in the VM's
+	// namespace or "namespace/name".
+	NADName string
+	// BindingBridge connects the interface with bridge binding, for NADs
+	// such as Linux bridges. Otherwise SR-IOV binding is used, for NADs
+	// backed by an SR-IOV network.
+	BindingBridge bool
+}
+
+// ValidateSecondaryNetworks checks that each network has a NAD and a name
+// distinct from the others and from PodNetworkName, so that the pod network
+// is kept.
+func ValidateSecondaryNetworks(networks []SecondaryNetwork) error {
+	seen := map[string]bool{PodNetworkName: true}
+	for _, n := range networks {
+		if n.NADName == "" {
+			return fmt.Errorf("secondary network %q has no NetworkAttachmentDefinition", n.Name)
+		}
+		if n.Name == PodNetworkName {
+			return fmt.Errorf("secondary network %q would replace the pod network", n.Name)
+		}
+		if n.Name == "" || seen[n.Name] {
+			return fmt.Errorf("secondary network for %s needs a unique name, got %q", n.NADName, n.Name)
+		}
+		seen[n.Name] = true
+	}
+	return nil
 }
 
 // BuildVMSpec constructs a KubeVirt VirtualMachine from the given options.
 // It configures a containerDisk for the OS image, cloudInitNoCloud for userdata,
 // masquerade networking, and virtio disk bus.
 func BuildVMSpec(opts VMSpecOpts) *kubevirtv1.VirtualMachine {
-	running := true
+	running := !opts.StartStopped
 
 	disks := []kubevirtv1.Disk{
 		{
@@ -61,6 +184,30 @@ func BuildVMSpec(opts VMSpecOpts) *kubevirtv1.VirtualMachine {
 		},
 	}
 	disks = append(disks, opts.ExtraDisks...)
+	if opts.DataDiskBlockSize != nil {
+		dataVolumes := make(map[string]bool)
+		for _, v := range opts.ExtraVolumes {
+			if v.DataVolume != nil {
+				dataVolumes[v.Name] = true
+			}
+		}
+		for i := range disks {
+			if dataVolumes[disks[i].Name] && disks[i].BlockSize == nil {
+				disks[i].BlockSize = opts.DataDiskBlockSize.DeepCopy()
+			}
+		}
+	}
+	if opts.ServiceAccountName != "" {
+		disks = append(disks, kubevirtv1.Disk{
+			Name:   "serviceaccountdisk",
+			Serial: ServiceAccountDiskSerial,
+			DiskDevice: kubevirtv1.DiskDevice{
+				Disk: &kubevirtv1.DiskTarget{
+					Bus: "virtio",
+				},
+			},
+		})
+	}
 
 	var cloudInitVolume kubevirtv1.Volume
 	if opts.CloudInitSecretName != "" {
@@ -74,6 +221,15 @@ func BuildVMSpec(opts VMSpecOpts) *kubevirtv1.VirtualMachine {
 				},
 			},
 		}
+	} else if opts.CompressCloudInit {
+		cloudInitVolume = kubevirtv1.Volume{
+			Name: "cloudinitdisk",
+			VolumeSource: kubevirtv1.VolumeSource{
+				CloudInitNoCloud: &kubevirtv1.CloudInitNoCloudSource{
+					UserDataBase64: base64.StdEncoding.EncodeToString(cloudinit.Compress(opts.CloudInitUserdata)),
+				},
+			},
+		}
 	} else {
 		cloudInitVolume = kubevirtv1.Volume{
 			Name: "cloudinitdisk",
@@ -90,13 +246,25 @@ func BuildVMSpec(opts VMSpecOpts) *kubevirtv1.VirtualMachine {
 			Name: "containerdisk",
 			VolumeSource: kubevirtv1.VolumeSource{
 				ContainerDisk: &kubevirtv1.ContainerDiskSource{
-					Image: opts.ContainerDiskImage,
+					Image:           opts.ContainerDiskImage,
+					ImagePullSecret: opts.ImagePullSecret,
 				},
 			},
 		},
 		cloudInitVolume,
 	}
 	volumes = append(volumes, opts.ExtraVolumes...)
+	if opts.ServiceAccountName != "" {
+		volumes = append(volumes, kubevirtv1.Volume{
+			Name: "serviceaccountdisk",
+			VolumeSource: kubevirtv1.VolumeSource{
+				ServiceAccount: &kubevirtv1.ServiceAccountVolumeSource{
+					ServiceAccountName: opts.ServiceAccountName,
+				},
+			},
+		})
+	}
+	interfaces, networks := buildNetworks(opts)
 
 	return &kubevirtv1.VirtualMachine{
 		TypeMeta: metav1.TypeMeta{
@@ -112,39 +280,28 @@ func BuildVMSpec(opts VMSpecOpts) *kubevirtv1.VirtualMachine {
 			Running: &running,
 			Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: opts.Labels,
+					Labels:      opts.Labels,
+					Annotations: opts.TemplateAnnotations,
 				},
 				Spec: kubevirtv1.VirtualMachineInstanceSpec{
 					Domain: kubevirtv1.DomainSpec{
-						CPU: &kubevirtv1.CPU{
-							Cores: uint32(opts.CPUCores),
-						},
-						Resources: kubevirtv1.ResourceRequirements{
-							Requests: corev1.ResourceList{
-								corev1.ResourceMemory: resource.MustParse(opts.Memory),
-							},
-						},
+						CPU:       buildCPU(opts),
+						Clock:     buildClock(opts.ClockTimezone, opts.Timers),
+						Memory:    buildMemory(opts.Hugepages),
+						Resources: buildResources(opts),
 						Devices: kubevirtv1.Devices{
-							Disks: disks,
-							Interfaces: []kubevirtv1.Interface{
-								{
-									Name: "default",
-									InterfaceBindingMethod: kubevirtv1.InterfaceBindingMethod{
-										Masquerade: &kubevirtv1.InterfaceMasquerade{},
-									},
-								},
-							},
-						},
-					},
-					Networks: []kubevirtv1.Network{
-						{
-							Name: "default",
-							NetworkSource: kubevirtv1.NetworkSource{
-								Pod: &kubevirtv1.PodNetwork{},
-							},
+							Disks:       disks,
+							Filesystems: opts.Filesystems,
+							GPUs:        opts.GPUs,
+							Interfaces:  interfaces,
 						},
 					},
-					Volumes: volumes,
+					Networks:       networks,
+					Volumes:        volumes,
+					NodeSelector:   opts.NodeSelector,
+					Tolerations:    opts.Tolerations,
+					Affinity:       buildAffinity(opts),
+					ReadinessProbe: opts.ReadinessProbe,
 				},
 			},
 			DataVolumeTemplates: opts.DataVolumeTemplates,
@@ -152,18 +309,230 @@ func BuildVMSpec(opts VMSpecOpts) *kubevirtv1.VirtualMachine {
 	}
 }
 
+// buildNetworks returns the VM's interfaces and networks: the pod network
+// with masquerade binding, then each of opts.SecondaryNetworks on Multus.
+func buildNetworks(opts VMSpecOpts) ([]kubevirtv1.Interface, []kubevirtv1.Network) {
+	interfaces := []kubevirtv1.Interface{{
+		Name: PodNetworkName,
+		InterfaceBindingMethod: kubevirtv1.InterfaceBindingMethod{
+			Masquerade: &kubevirtv1.InterfaceMasquerade{},
+		},
+	}}
+	networks := []kubevirtv1.Network{{
+		Name: PodNetworkName,
+		NetworkSource: kubevirtv1.NetworkSource{
+			Pod: &kubevirtv1.PodNetwork{},
+		},
+	}}
+	for _, n := range opts.SecondaryNetworks {
+		binding := kubevirtv1.InterfaceBindingMethod{SRIOV: &kubevirtv1.InterfaceSRIOV{}}
+		if n.BindingBridge {
+			binding = kubevirtv1.InterfaceBindingMethod{Bridge: &kubevirtv1.InterfaceBridge{}}
+		}
+		interfaces = append(interfaces, kubevirtv1.Interface{Name: n.Name, InterfaceBindingMethod: binding})
+		networks = append(networks, kubevirtv1.Network{
+			Name: n.Name,
+			NetworkSource: kubevirtv1.NetworkSource{
+				Multus: &kubevirtv1.MultusNetwork{NetworkName: n.NADName},
+			},
+		})
+	}
+	return interfaces, networks
+}
+
+// buildAffinity returns opts.Affinity with the Spread anti-affinity term
+// added. The term selects the virt-launcher pods of virtwork VMs with the same
+// component label, so VMs of one workload are spread across nodes while other
+// workloads may share them. opts.Affinity itself is not modified.
+func buildAffinity(opts VMSpecOpts) *corev1.Affinity {
+	if opts.Spread == nil {
+		return opts.Affinity
+	}
+	affinity := &corev1.Affinity{}
+	if opts.Affinity != nil {
+		affinity = opts.Affinity.DeepCopy()
+	}
+	if affinity.PodAntiAffinity == nil {
+		affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				constants.LabelManagedBy: constants.ManagedByValue,
+				constants.LabelComponent: opts.Labels[constants.LabelComponent],
+			},
+		},
+		TopologyKey: corev1.LabelHostname,
+	}
+	anti := affinity.PodAntiAffinity
+	if opts.Spread.Required {
+		anti.RequiredDuringSchedulingIgnoredDuringExecution = append(anti.RequiredDuringSchedulingIgnoredDuringExecution, term)
+	} else {
+		anti.PreferredDuringSchedulingIgnoredDuringExecution = append(anti.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{Weight: opts.Spread.Weight, PodAffinityTerm: term})
+	}
+	return affinity
+}
+
+// buildCPU returns the domain CPU topology, model, and placement.
+func buildCPU(opts VMSpecOpts) *kubevirtv1.CPU {
+	return &kubevirtv1.CPU{
+		Cores:                 uint32(opts.CPUCores),
+		Sockets:               uint32(opts.Sockets),
+		Threads:               uint32(opts.Threads),
+		Model:                 opts.CPUModel,
+		DedicatedCPUPlacement: opts.DedicatedCPUPlacement,
+	}
+}
+
+// buildResources returns the domain resource requirements. Dedicated CPU
+// placement needs the Guaranteed QoS class, so CPU and memory limits are set
+// equal to the requests, with one CPU per vCPU in the topology. Otherwise the
+// limits are MemoryLimit and CPULimit, when set.
+func buildResources(opts VMSpecOpts) kubevirtv1.ResourceRequirements {
+	memory := resource.MustParse(opts.Memory)
+	if opts.Hugepages != "" {
+		memory = alignToPageSize(memory, resource.MustParse(opts.Hugepages))
+	}
+	res := kubevirtv1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: memory,
+		},
+		OvercommitGuestOverhead: opts.OvercommitGuestOverhead,
+	}
+	if !opts.DedicatedCPUPlacement {
+		if opts.MemoryLimit != "" || opts.CPULimit != "" {
+			res.Limits = corev1.ResourceList{}
+		}
+		if opts.MemoryLimit != "" {
+			limit := resource.MustParse(opts.MemoryLimit)
+			if opts.Hugepages != "" {
+				limit = alignToPageSize(limit, resource.MustParse(opts.Hugepages))
+			}
+			res.Limits[corev1.ResourceMemory] = limit
+		}
+		if opts.CPULimit != "" {
+			res.Limits[corev1.ResourceCPU] = resource.MustParse(opts.CPULimit)
+		}
+		return res
+	}
+
+	vcpus := *resource.NewQuantity(int64(atLeastOne(opts.CPUCores)*atLeastOne(opts.Sockets)*atLeastOne(opts.Threads)), resource.DecimalSI)
+	res.Requests[corev1.ResourceCPU] = vcpus
+	res.Limits = corev1.ResourceList{
+		corev1.ResourceCPU:    vcpus,
+		corev1.ResourceMemory: memory,
+	}
+	return res
+}
+
+// Requests returns the CPU and memory that a VM built from opts requests.
+// With dedicated CPU placement that is one CPU per vCPU. Otherwise KubeVirt
+// requests 100m per vCPU for the virt-launcher pod, its default with a CPU
+// allocation ratio of 10. The virt-launcher memory overhead is not included.
+func Requests(opts VMSpecOpts) (cpu, memory resource.Quantity) {
+	res := buildResources(opts)
+	memory = res.Requests[corev1.ResourceMemory]
+	cpu, ok := res.Requests[corev1.ResourceCPU]
+	if !ok {
+		vcpus := atLeastOne(opts.CPUCores) * atLeastOne(opts.Sockets) * atLeastOne(opts.Threads)
+		cpu = *resource.NewMilliQuantity(int64(vcpus)*100, resource.DecimalSI)
+	}
+	return cpu, memory
+}
+
+// buildMemory returns the domain memory for the given hugepages page size, or
+// nil when hugepages are not requested.
+func buildMemory(hugepages string) *kubevirtv1.Memory {
+	if hugepages == "" {
+		return nil
+	}
+	return &kubevirtv1.Memory{
+		Hugepages: &kubevirtv1.Hugepages{PageSize: hugepages},
+	}
+}
+
+// alignToPageSize rounds memory up to a whole number of pages. KubeVirt
+// rejects hugepages-backed VMs whose memory is not a multiple of the page
+// size.
+func alignToPageSize(memory, pageSize resource.Quantity) resource.Quantity {
+	page := pageSize.Value()
+	pages := (memory.Value() + page - 1) / page
+	return *resource.NewQuantity(pages*page, resource.BinarySI)
+}
+
+// atLeastOne returns n, or 1 when n is unset.
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// buildClock returns the domain clock for the given timezone and timer
+// overrides, or nil when neither is set. Timers without a timezone keep the
+// guest clock in UTC.
+func buildClock(timezone string, timers map[string]bool) *kubevirtv1.Clock {
+	if timezone == "" && len(timers) == 0 {
+		return nil
+	}
+
+	clock := &kubevirtv1.Clock{}
+	if timezone == "" || strings.EqualFold(timezone, "UTC") {
+		clock.UTC = &kubevirtv1.ClockOffsetUTC{}
+	} else {
+		tz := kubevirtv1.ClockOffsetTimezone(timezone)
+		clock.Timezone = &tz
+	}
+
+	if len(timers) > 0 {
+		clock.Timer = &kubevirtv1.Timer{}
+		for name, enabled := range timers {
+			enabled := enabled
+			switch name {
+			case "hpet":
+				clock.Timer.HPET = &kubevirtv1.HPETTimer{Enabled: &enabled}
+			case "hyperv":
+				clock.Timer.Hyperv = &kubevirtv1.HypervTimer{Enabled: &enabled}
+			case "kvm":
+				clock.Timer.KVM = &kubevirtv1.KVMTimer{Enabled: &enabled}
+			case "pit":
+				clock.Timer.PIT = &kubevirtv1.PITTimer{Enabled: &enabled}
+			}
+		}
+	}
+	return clock
+}
+
+// DataVolumeOpts holds optional storage settings for a DataVolumeTemplateSpec.
+// Unset fields are left for CDI to fill from the cluster's default
+// StorageClass and its storage profile.
+type DataVolumeOpts struct {
+	StorageClassName *string
+	AccessModes      []corev1.PersistentVolumeAccessMode
+}
+
 // BuildDataVolumeTemplate constructs a DataVolumeTemplateSpec for a blank disk
-// with the given name and size.
-func BuildDataVolumeTemplate(name, size string) kubevirtv1.DataVolumeTemplateSpec {
+// of the given size.
+func BuildDataVolumeTemplate(name, size string, opts DataVolumeOpts) kubevirtv1.DataVolumeTemplateSpec {
+	return BuildDataVolumeTemplateFromSource(name, size, cdiv1beta1.DataVolumeSource{
+		Blank: &cdiv1beta1.DataVolumeBlankImage{},
+	}, opts)
+}
+
+// BuildDataVolumeTemplateFromSource constructs a DataVolumeTemplateSpec of the
+// given size whose contents CDI populates from source, e.g. RegistrySource or
+// PVCSource. The size must be at least that of the source image or volume.
+func BuildDataVolumeTemplateFromSource(name, size string, source cdiv1beta1.DataVolumeSource, opts DataVolumeOpts) kubevirtv1.DataVolumeTemplateSpec {
 	return kubevirtv1.DataVolumeTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
 		Spec: cdiv1beta1.DataVolumeSpec{
-			Source: &cdiv1beta1.DataVolumeSource{
-				Blank: &cdiv1beta1.DataVolumeBlankImage{},
-			},
+			Source: &source,
 			Storage: &cdiv1beta1.StorageSpec{
+				StorageClassName: opts.StorageClassName,
+				AccessModes:      opts.AccessModes,
 				Resources: corev1.VolumeResourceRequirements{
 					Requests: corev1.ResourceList{
 						corev1.ResourceStorage: resource.MustParse(size),
@@ -174,34 +543,264 @@ func BuildDataVolumeTemplate(name, size string) kubevirtv1.DataVolumeTemplateSpe
 	}
 }
 
+// RegistrySource returns a DataVolumeSource that imports a disk image from a
+// container registry, e.g. "docker://quay.io/example/pgbench-data:latest".
+func RegistrySource(url string) cdiv1beta1.DataVolumeSource {
+	return cdiv1beta1.DataVolumeSource{
+		Registry: &cdiv1beta1.DataVolumeSourceRegistry{URL: &url},
+	}
+}
+
+// PVCSource returns a DataVolumeSource that clones an existing PVC, such as
+// one backing a DataVolume.
+func PVCSource(namespace, name string) cdiv1beta1.DataVolumeSource {
+	return cdiv1beta1.DataVolumeSource{
+		PVC: &cdiv1beta1.DataVolumeSourcePVC{Namespace: namespace, Name: name},
+	}
+}
+
+// RetryOptions sets how CreateVMWithOptions retries transient API errors.
+type RetryOptions struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// BaseBackoff is the wait before the first retry, doubled for each one
+	// after. Zero uses the default of one second.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the wait before any single retry. Zero leaves it
+	// uncapped.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryOptions returns the retry settings CreateVM uses.
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{MaxRetries: retry.DefaultMaxRetries}
+}
+
 // CreateVM creates a VirtualMachine. AlreadyExists errors are treated as
 // success (idempotent). Transient errors are retried with exponential backoff.
 func CreateVM(ctx context.Context, c client.Client, vm *kubevirtv1.VirtualMachine) error {
-	return retryOnTransient(ctx, func() error {
+	return CreateVMWithOptions(ctx, c, vm, DefaultRetryOptions())
+}
+
+// CreateVMWithOptions behaves like CreateVM, retrying transient errors as
+// opts sets. Once the retries are used up, the last error is returned
+// wrapped in a "max retries exceeded" error.
+func CreateVMWithOptions(ctx context.Context, c client.Client, vm *kubevirtv1.VirtualMachine, opts RetryOptions) error {
+	return retry.OnTransientWithBackoff(ctx, func() error {
 		err := c.Create(ctx, vm)
 		if apierrors.IsAlreadyExists(err) {
 			return nil
 		}
 		return err
-	}, defaultMaxRetries)
+	}, opts.MaxRetries, opts.BaseBackoff, opts.MaxBackoff)
+}
+
+// ApplyVM creates a VirtualMachine or, if one of the same name exists,
+// updates the existing VM's labels and its template's labels, CPU, memory,
+// and resources to match vm. The rest of the existing spec is left alone.
+// It reports whether an existing VM was updated. KubeVirt applies the new
+// template when the VM next boots, which may mean restarting a running VM.
+// Transient errors and update conflicts are retried.
+func ApplyVM(ctx context.Context, c client.Client, vm *kubevirtv1.VirtualMachine) (bool, error) {
+	return ApplyVMWithOptions(ctx, c, vm, DefaultRetryOptions())
+}
+
+// ApplyVMWithOptions behaves like ApplyVM, retrying as opts sets.
+func ApplyVMWithOptions(ctx context.Context, c client.Client, vm *kubevirtv1.VirtualMachine, opts RetryOptions) (bool, error) {
+	updated := false
+	err := retry.OnTransientWithBackoff(ctx, func() error {
+		err := c.Create(ctx, vm)
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+
+		// Update the version just read, again if it changes before the
+		// update lands.
+		if err := clientretry.RetryOnConflict(clientretry.DefaultRetry, func() error {
+			existing := &kubevirtv1.VirtualMachine{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(vm), existing); err != nil {
+				return fmt.Errorf("getting VM %s/%s: %w", vm.Namespace, vm.Name, err)
+			}
+			applyMutableFields(existing, vm)
+			if err := c.Update(ctx, existing); err != nil {
+				return fmt.Errorf("updating VM %s/%s: %w", vm.Namespace, vm.Name, err)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		updated = true
+		return nil
+	}, opts.MaxRetries, opts.BaseBackoff, opts.MaxBackoff)
+	return updated, err
+}
+
+// applyMutableFields copies desired's labels, and its template's labels,
+// CPU, memory, and resources, onto existing. Labels on existing that desired
+// does not set are kept.
+func applyMutableFields(existing, desired *kubevirtv1.VirtualMachine) {
+	existing.Labels = mergeLabels(existing.Labels, desired.Labels)
+	if existing.Spec.Template == nil || desired.Spec.Template == nil {
+		return
+	}
+	existing.Spec.Template.ObjectMeta.Labels = mergeLabels(existing.Spec.Template.ObjectMeta.Labels, desired.Spec.Template.ObjectMeta.Labels)
+	domain := &existing.Spec.Template.Spec.Domain
+	want := desired.Spec.Template.Spec.Domain
+	domain.CPU = want.CPU.DeepCopy()
+	domain.Memory = want.Memory.DeepCopy()
+	domain.Resources = *want.Resources.DeepCopy()
+}
+
+// mergeLabels returns labels with every entry of overrides set on it.
+func mergeLabels(labels, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return labels
+	}
+	if labels == nil {
+		labels = make(map[string]string, len(overrides))
+	}
+	for k, v := range overrides {
+		labels[k] = v
+	}
+	return labels
+}
+
+// DeleteOptions sets how DeleteVMWithOptions deletes a VirtualMachine. Nil
+// fields leave the API server defaults in place.
+type DeleteOptions struct {
+	// GracePeriodSeconds is how long the guest gets to shut down before its
+	// VMI is killed. Zero deletes it immediately.
+	GracePeriodSeconds *int64
+	// PropagationPolicy sets whether dependents, such as the VMI and the
+	// DataVolumes, are deleted in the foreground, in the background, or
+	// orphaned.
+	PropagationPolicy *metav1.DeletionPropagation
+}
+
+// ForceDeleteOptions returns the options for force-deleting a VM whose VMI
+// is stuck: no grace period and background propagation.
+func ForceDeleteOptions() DeleteOptions {
+	grace := int64(0)
+	policy := metav1.DeletePropagationBackground
+	return DeleteOptions{GracePeriodSeconds: &grace, PropagationPolicy: &policy}
+}
+
+// ClientOptions converts o to controller-runtime delete options.
+func (o DeleteOptions) ClientOptions() []client.DeleteOption {
+	var opts []client.DeleteOption
+	if o.GracePeriodSeconds != nil {
+		opts = append(opts, client.GracePeriodSeconds(*o.GracePeriodSeconds))
+	}
+	if o.PropagationPolicy != nil {
+		opts = append(opts, client.PropagationPolicy(*o.PropagationPolicy))
+	}
+	return opts
 }
 
 // DeleteVM deletes a VirtualMachine by name and namespace. NotFound errors are
 // treated as success (idempotent). Transient errors are retried.
 func DeleteVM(ctx context.Context, c client.Client, name, namespace string) error {
+	return DeleteVMWithOptions(ctx, c, name, namespace, DeleteOptions{})
+}
+
+// DeleteVMWithOptions behaves like DeleteVM, passing opts to the API server.
+func DeleteVMWithOptions(ctx context.Context, c client.Client, name, namespace string, opts DeleteOptions) error {
 	vm := &kubevirtv1.VirtualMachine{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
 		},
 	}
-	return retryOnTransient(ctx, func() error {
-		err := c.Delete(ctx, vm)
+	deleteOpts := opts.ClientOptions()
+	return retry.OnTransient(ctx, func() error {
+		err := c.Delete(ctx, vm, deleteOpts...)
 		if apierrors.IsNotFound(err) {
 			return nil
 		}
 		return err
-	}, defaultMaxRetries)
+	}, retry.DefaultMaxRetries)
+}
+
+// StartVM sets spec.running to true on a VirtualMachine created stopped,
+// prompting KubeVirt to boot it. Starting a running VM is a no-op.
+// Transient errors are retried.
+func StartVM(ctx context.Context, c client.Client, name, namespace string) error {
+	return retry.OnTransient(ctx, func() error {
+		vm := &kubevirtv1.VirtualMachine{}
+		if err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, vm); err != nil {
+			return fmt.Errorf("getting VM %s/%s: %w", namespace, name, err)
+		}
+		patch := client.MergeFrom(vm.DeepCopy())
+		running := true
+		vm.Spec.Running = &running
+		if err := c.Patch(ctx, vm, patch); err != nil {
+			return fmt.Errorf("starting VM %s/%s: %w", namespace, name, err)
+		}
+		return nil
+	}, retry.DefaultMaxRetries)
+}
+
+// RecreateVM deletes the VirtualMachine named by vm, waits until it, its VMI,
+// and the DataVolumes of its DataVolumeTemplates are gone, and creates vm
+// again, so a VM whose VMI failed gets a fresh VMI and disks. Creating it
+// while an old DataVolume is still terminating would fail as already
+// existing. It polls every interval and fails if the old objects are still
+// present after timeout. vm must not have been created through c before, as
+// Create fills in its server-side fields.
+func RecreateVM(ctx context.Context, c client.Client, vm *kubevirtv1.VirtualMachine, opts RetryOptions, timeout, interval time.Duration) error {
+	name, namespace := vm.Name, vm.Namespace
+	if err := DeleteVM(ctx, c, name, namespace); err != nil {
+		return fmt.Errorf("deleting VM %s/%s: %w", namespace, name, err)
+	}
+
+	key := client.ObjectKey{Name: name, Namespace: namespace}
+	deadline := time.Now().Add(timeout)
+	for {
+		vmGone, err := isGone(ctx, c, key, &kubevirtv1.VirtualMachine{})
+		if err != nil {
+			return err
+		}
+		vmiGone, err := isGone(ctx, c, key, &kubevirtv1.VirtualMachineInstance{})
+		if err != nil {
+			return err
+		}
+		dvsGone := true
+		for _, dvt := range vm.Spec.DataVolumeTemplates {
+			gone, err := isGone(ctx, c, client.ObjectKey{Name: dvt.Name, Namespace: namespace}, &cdiv1beta1.DataVolume{})
+			if err != nil {
+				return err
+			}
+			dvsGone = dvsGone && gone
+		}
+		if vmGone && vmiGone && dvsGone {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for VM %s/%s to be deleted", namespace, name)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled waiting for VM %s/%s to be deleted: %w", namespace, name, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+
+	if err := CreateVMWithOptions(ctx, c, vm, opts); err != nil {
+		return fmt.Errorf("creating VM %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// isGone reports whether the object at key no longer exists.
+func isGone(ctx context.Context, c client.Client, key client.ObjectKey, obj client.Object) (bool, error) {
+	err := c.Get(ctx, key, obj)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("getting %s/%s: %w", key.Namespace, key.Name, err)
+	}
+	return false, nil
 }
 
 // ListVMs returns VirtualMachines matching the given labels in the namespace.
@@ -227,39 +826,123 @@ func GetVMIPhase(ctx context.Context, c client.Client, name, namespace string) (
 	return vmi.Status.Phase, nil
 }
 
-// retryOnTransient retries fn on transient API errors with exponential backoff.
-func retryOnTransient(ctx context.Context, fn func() error, maxRetries int) error {
-	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if err := ctx.Err(); err != nil {
-			return fmt.Errorf("context cancelled: %w", err)
-		}
+// VMStatus is the state of a VirtualMachine and its instance, as reported by
+// GetVMStatus.
+type VMStatus struct {
+	// Phase is the VMI phase, or Unknown when the VM has no VMI yet.
+	Phase kubevirtv1.VirtualMachineInstancePhase
+	// Ready is the VM's status.ready: its VMI is running and ready.
+	Ready bool
+	// AgentConnected reports whether the guest agent has connected.
+	AgentConnected bool
+	// NodeName is the node the VMI runs on, or "" before it is scheduled.
+	NodeName string
+	// PrintableStatus is the status kubectl shows for the VM, such as
+	// Starting, Running, or ErrImagePull.
+	PrintableStatus kubevirtv1.VirtualMachinePrintableStatus
+	// Message explains why the VM is not ready, from the Ready condition of
+	// its VMI, or of the VM when there is no VMI. It is "" once ready.
+	Message string
+}
 
-		lastErr = fn()
-		if lastErr == nil {
-			return nil
-		}
+// GetVMStatus returns the state of the named VirtualMachine, combining the
+// VM's status with the conditions and node of its VMI. A VM whose VMI does
+// not exist yet, such as one created stopped, reports the Unknown phase
+// rather than an error.
+func GetVMStatus(ctx context.Context, c client.Client, name, namespace string) (VMStatus, error) {
+	key := client.ObjectKey{Name: name, Namespace: namespace}
+	vm := &kubevirtv1.VirtualMachine{}
+	if err := c.Get(ctx, key, vm); err != nil {
+		return VMStatus{}, fmt.Errorf("getting VM %s/%s: %w", namespace, name, err)
+	}
+	status := VMStatus{
+		Phase:           kubevirtv1.Unknown,
+		Ready:           vm.Status.Ready,
+		PrintableStatus: vm.Status.PrintableStatus,
+	}
 
-		if !isTransientError(lastErr) {
-			return lastErr
+	vmi := &kubevirtv1.VirtualMachineInstance{}
+	if err := c.Get(ctx, key, vmi); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return VMStatus{}, fmt.Errorf("getting VMI %s/%s: %w", namespace, name, err)
+		}
+		for _, cond := range vm.Status.Conditions {
+			if cond.Type == kubevirtv1.VirtualMachineReady && cond.Status != corev1.ConditionTrue {
+				status.Message = cond.Message
+			}
 		}
+		return status, nil
+	}
 
-		if attempt < maxRetries {
-			backoff := baseRetryBackoff * time.Duration(1<<uint(attempt))
-			select {
-			case <-ctx.Done():
-				return fmt.Errorf("context cancelled during retry backoff: %w", ctx.Err())
-			case <-time.After(backoff):
+	status.Phase = vmi.Status.Phase
+	status.NodeName = vmi.Status.NodeName
+	for _, cond := range vmi.Status.Conditions {
+		switch cond.Type {
+		case kubevirtv1.VirtualMachineInstanceAgentConnected:
+			status.AgentConnected = cond.Status == corev1.ConditionTrue
+		case kubevirtv1.VirtualMachineInstanceReady:
+			if cond.Status != corev1.ConditionTrue {
+				status.Message = cond.Message
 			}
 		}
 	}
-	return fmt.Errorf("max retries (%d) exceeded: %w", maxRetries, lastErr)
+	return status, nil
+}
+
+// GetVMIIP returns the pod network IP address of a VirtualMachineInstance,
+// falling back to its first interface when none is named PodNetworkName, or
+// "" if it has not reported one yet.
+func GetVMIIP(ctx context.Context, c client.Client, name, namespace string) (string, error) {
+	vmi := &kubevirtv1.VirtualMachineInstance{}
+	key := client.ObjectKey{Name: name, Namespace: namespace}
+	if err := c.Get(ctx, key, vmi); err != nil {
+		return "", fmt.Errorf("getting VMI %s/%s: %w", namespace, name, err)
+	}
+	if len(vmi.Status.Interfaces) == 0 {
+		return "", nil
+	}
+	for _, iface := range vmi.Status.Interfaces {
+		if iface.Name == PodNetworkName {
+			return iface.IP, nil
+		}
+	}
+	return vmi.Status.Interfaces[0].IP, nil
+}
+
+// BuildMigration constructs a VirtualMachineInstanceMigration that live
+// migrates the VMI of the named VM. The API server names it from the
+// <vm-name>-migration- prefix, so each migration of a VM gets its own object.
+func BuildMigration(vmName, namespace string, labels map[string]string) *kubevirtv1.VirtualMachineInstanceMigration {
+	return &kubevirtv1.VirtualMachineInstanceMigration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kubevirtv1.SchemeGroupVersion.String(),
+			Kind:       "VirtualMachineInstanceMigration",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: vmName + "-migration-",
+			Namespace:    namespace,
+			Labels:       labels,
+		},
+		Spec: kubevirtv1.VirtualMachineInstanceMigrationSpec{
+			VMIName: vmName,
+		},
+	}
+}
+
+// CreateMigration creates a VirtualMachineInstanceMigration, retrying
+// transient errors, and fills in the name the API server generated.
+func CreateMigration(ctx context.Context, c client.Client, m *kubevirtv1.VirtualMachineInstanceMigration) error {
+	return retry.OnTransient(ctx, func() error {
+		return c.Create(ctx, m)
+	}, retry.DefaultMaxRetries)
 }
 
-// isTransientError returns true for API errors that are worth retrying.
-func isTransientError(err error) bool {
-	return apierrors.IsTooManyRequests(err) ||
-		apierrors.IsServerTimeout(err) ||
-		apierrors.IsServiceUnavailable(err) ||
-		apierrors.IsInternalError(err)
+// GetMigrationPhase returns the current phase of a VirtualMachineInstanceMigration.
+func GetMigrationPhase(ctx context.Context, c client.Client, name, namespace string) (kubevirtv1.VirtualMachineInstanceMigrationPhase, error) {
+	m := &kubevirtv1.VirtualMachineInstanceMigration{}
+	key := client.ObjectKey{Name: name, Namespace: namespace}
+	if err := c.Get(ctx, key, m); err != nil {
+		return "", fmt.Errorf("getting migration %s/%s: %w", namespace, name, err)
+	}
+	return m.Status.Phase, nil
 }
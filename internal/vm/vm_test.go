@@ -4,7 +4,11 @@
 package vm_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
+	"io"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -16,6 +20,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
@@ -68,6 +73,18 @@ var _ = Describe("BuildVMSpec", func() {
 		Expect(containerDisk).NotTo(BeNil())
 		Expect(containerDisk.ContainerDisk).NotTo(BeNil())
 		Expect(containerDisk.ContainerDisk.Image).To(Equal("quay.io/containerdisks/fedora:41"))
+		Expect(containerDisk.ContainerDisk.ImagePullSecret).To(BeEmpty())
+	})
+
+	It("should set the image pull secret on the containerDisk volume", func() {
+		opts.ContainerDiskImage = "registry.example.com/private/fedora:41"
+		opts.ImagePullSecret = "registry-creds"
+		result = vm.BuildVMSpec(opts)
+
+		Expect(result.Spec.Template.Spec.Volumes[0].Name).To(Equal("containerdisk"))
+		containerDisk := result.Spec.Template.Spec.Volumes[0].ContainerDisk
+		Expect(containerDisk.Image).To(Equal("registry.example.com/private/fedora:41"))
+		Expect(containerDisk.ImagePullSecret).To(Equal("registry-creds"))
 	})
 
 	It("should configure cloudInitNoCloud volume", func() {
@@ -91,6 +108,132 @@ var _ = Describe("BuildVMSpec", func() {
 		Expect(result.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "virtwork"))
 	})
 
+	It("should not set template annotations by default", func() {
+		Expect(result.Annotations).To(BeEmpty())
+		Expect(result.Spec.Template.ObjectMeta.Annotations).To(BeEmpty())
+	})
+
+	It("should set template annotations on the template only", func() {
+		opts.TemplateAnnotations = map[string]string{
+			"k8s.v1.cni.cncf.io/networks": "virtwork/storage-net",
+		}
+		result = vm.BuildVMSpec(opts)
+
+		Expect(result.Spec.Template.ObjectMeta.Annotations).To(
+			HaveKeyWithValue("k8s.v1.cni.cncf.io/networks", "virtwork/storage-net"))
+		Expect(result.Annotations).NotTo(HaveKey("k8s.v1.cni.cncf.io/networks"))
+		Expect(result.Spec.Template.ObjectMeta.Labels).To(Equal(result.Labels))
+	})
+
+	It("should leave scheduling constraints nil by default", func() {
+		spec := result.Spec.Template.Spec
+		Expect(spec.NodeSelector).To(BeNil())
+		Expect(spec.Tolerations).To(BeNil())
+		Expect(spec.Affinity).To(BeNil())
+	})
+
+	It("should set node selector, tolerations, and affinity on the template", func() {
+		opts.NodeSelector = map[string]string{"node-role.kubernetes.io/worker": ""}
+		opts.Tolerations = []corev1.Toleration{{
+			Key:      "baremetal",
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		}}
+		opts.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "kubernetes.io/arch",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"amd64"},
+						}},
+					}},
+				},
+			},
+		}
+		result = vm.BuildVMSpec(opts)
+
+		spec := result.Spec.Template.Spec
+		Expect(spec.NodeSelector).To(HaveKeyWithValue("node-role.kubernetes.io/worker", ""))
+		Expect(spec.Tolerations).To(Equal(opts.Tolerations))
+		Expect(spec.Affinity).To(Equal(opts.Affinity))
+	})
+
+	Context("with a spread anti-affinity term", func() {
+		BeforeEach(func() {
+			opts.Labels = map[string]string{
+				"app.kubernetes.io/managed-by": "virtwork",
+				"app.kubernetes.io/component":  "database",
+			}
+		})
+
+		expectedTerm := corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app.kubernetes.io/managed-by": "virtwork",
+					"app.kubernetes.io/component":  "database",
+				},
+			},
+			TopologyKey: "kubernetes.io/hostname",
+		}
+
+		It("should add a required term when the spread is required", func() {
+			opts.Spread = &vm.SpreadOpts{Required: true}
+			result = vm.BuildVMSpec(opts)
+
+			anti := result.Spec.Template.Spec.Affinity.PodAntiAffinity
+			Expect(anti.RequiredDuringSchedulingIgnoredDuringExecution).To(Equal([]corev1.PodAffinityTerm{expectedTerm}))
+			Expect(anti.PreferredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+		})
+
+		It("should add a preferred term with the given weight otherwise", func() {
+			opts.Spread = &vm.SpreadOpts{Weight: 40}
+			result = vm.BuildVMSpec(opts)
+
+			anti := result.Spec.Template.Spec.Affinity.PodAntiAffinity
+			Expect(anti.PreferredDuringSchedulingIgnoredDuringExecution).To(Equal([]corev1.WeightedPodAffinityTerm{
+				{Weight: 40, PodAffinityTerm: expectedTerm},
+			}))
+			Expect(anti.RequiredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+		})
+
+		It("should merge the term into an affinity from file without modifying it", func() {
+			opts.Affinity = &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+						TopologyKey: "topology.kubernetes.io/zone",
+					}},
+				},
+			}
+			opts.Spread = &vm.SpreadOpts{Required: true}
+			result = vm.BuildVMSpec(opts)
+
+			anti := result.Spec.Template.Spec.Affinity.PodAntiAffinity
+			Expect(anti.RequiredDuringSchedulingIgnoredDuringExecution).To(HaveLen(2))
+			Expect(anti.RequiredDuringSchedulingIgnoredDuringExecution[1]).To(Equal(expectedTerm))
+			Expect(opts.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+		})
+	})
+
+	It("should not set a readiness probe by default", func() {
+		Expect(result.Spec.Template.Spec.ReadinessProbe).To(BeNil())
+	})
+
+	It("should set the readiness probe on the template", func() {
+		opts.ReadinessProbe = &kubevirtv1.Probe{
+			Handler: kubevirtv1.Handler{
+				Exec: &corev1.ExecAction{
+					Command: []string{"systemctl", "is-active", "--quiet", "virtwork-disk.service"},
+				},
+			},
+			PeriodSeconds: 10,
+		}
+		result = vm.BuildVMSpec(opts)
+
+		Expect(result.Spec.Template.Spec.ReadinessProbe).To(Equal(opts.ReadinessProbe))
+	})
+
 	It("should set CPU and memory resources", func() {
 		domain := result.Spec.Template.Spec.Domain
 		Expect(domain.CPU).NotTo(BeNil())
@@ -105,6 +248,13 @@ var _ = Describe("BuildVMSpec", func() {
 		Expect(*result.Spec.Running).To(BeTrue())
 	})
 
+	It("should set running false when StartStopped", func() {
+		opts.StartStopped = true
+		result = vm.BuildVMSpec(opts)
+		Expect(result.Spec.Running).NotTo(BeNil())
+		Expect(*result.Spec.Running).To(BeFalse())
+	})
+
 	It("should configure masquerade networking", func() {
 		interfaces := result.Spec.Template.Spec.Domain.Devices.Interfaces
 		Expect(interfaces).To(HaveLen(1))
@@ -117,6 +267,30 @@ var _ = Describe("BuildVMSpec", func() {
 		Expect(networks[0].Pod).NotTo(BeNil())
 	})
 
+	It("should add secondary networks after the masquerade default", func() {
+		opts.SecondaryNetworks = []vm.SecondaryNetwork{
+			{Name: "net1", NADName: "virtwork/east-west", BindingBridge: true},
+			{Name: "net2", NADName: "sriov-net"},
+		}
+		result = vm.BuildVMSpec(opts)
+
+		interfaces := result.Spec.Template.Spec.Domain.Devices.Interfaces
+		Expect(interfaces).To(HaveLen(3))
+		Expect(interfaces[0].Name).To(Equal("default"))
+		Expect(interfaces[0].Masquerade).NotTo(BeNil())
+		Expect(interfaces[1].Name).To(Equal("net1"))
+		Expect(interfaces[1].Bridge).NotTo(BeNil())
+		Expect(interfaces[2].Name).To(Equal("net2"))
+		Expect(interfaces[2].SRIOV).NotTo(BeNil())
+
+		networks := result.Spec.Template.Spec.Networks
+		Expect(networks).To(HaveLen(3))
+		Expect(networks[0].Pod).NotTo(BeNil())
+		Expect(networks[1].Name).To(Equal("net1"))
+		Expect(networks[1].Multus).To(Equal(&kubevirtv1.MultusNetwork{NetworkName: "virtwork/east-west"}))
+		Expect(networks[2].Multus.NetworkName).To(Equal("sriov-net"))
+	})
+
 	It("should use UserDataSecretRef when CloudInitSecretName is set", func() {
 		opts.CloudInitSecretName = "my-vm-cloudinit"
 		result = vm.BuildVMSpec(opts)
@@ -184,35 +358,448 @@ var _ = Describe("BuildVMSpec", func() {
 		Expect(volumes).To(HaveLen(3))
 	})
 
+	Context("with a data disk block size", func() {
+		BeforeEach(func() {
+			opts.ExtraDisks = []kubevirtv1.Disk{
+				{Name: "datadisk", DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: "virtio"}}},
+				{Name: "scratch", DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: "virtio"}}},
+			}
+			opts.ExtraVolumes = []kubevirtv1.Volume{
+				{Name: "datadisk", VolumeSource: kubevirtv1.VolumeSource{
+					DataVolume: &kubevirtv1.DataVolumeSource{Name: "test-data"},
+				}},
+				{Name: "scratch", VolumeSource: kubevirtv1.VolumeSource{
+					EmptyDisk: &kubevirtv1.EmptyDiskSource{Capacity: resource.MustParse("1Gi")},
+				}},
+			}
+		})
+
+		It("should set custom sizes on DataVolume disks only", func() {
+			opts.DataDiskBlockSize = &kubevirtv1.BlockSize{
+				Custom: &kubevirtv1.CustomBlockSize{Logical: 512, Physical: 4096},
+			}
+			result = vm.BuildVMSpec(opts)
+
+			disks := result.Spec.Template.Spec.Domain.Devices.Disks
+			Expect(disks).To(HaveLen(4))
+			Expect(disks[2].Name).To(Equal("datadisk"))
+			Expect(disks[2].BlockSize).To(Equal(&kubevirtv1.BlockSize{
+				Custom: &kubevirtv1.CustomBlockSize{Logical: 512, Physical: 4096},
+			}))
+			Expect(disks[0].BlockSize).To(BeNil())
+			Expect(disks[1].BlockSize).To(BeNil())
+			Expect(disks[3].BlockSize).To(BeNil())
+			Expect(opts.ExtraDisks[0].BlockSize).To(BeNil())
+		})
+
+		It("should set matchVolume on DataVolume disks", func() {
+			enabled := true
+			opts.DataDiskBlockSize = &kubevirtv1.BlockSize{MatchVolume: &kubevirtv1.FeatureState{Enabled: &enabled}}
+			result = vm.BuildVMSpec(opts)
+
+			disk := result.Spec.Template.Spec.Domain.Devices.Disks[2]
+			Expect(disk.BlockSize.MatchVolume).NotTo(BeNil())
+			Expect(*disk.BlockSize.MatchVolume.Enabled).To(BeTrue())
+		})
+
+		It("should keep a block size the disk already sets", func() {
+			opts.ExtraDisks[0].BlockSize = &kubevirtv1.BlockSize{
+				Custom: &kubevirtv1.CustomBlockSize{Logical: 4096, Physical: 4096},
+			}
+			opts.DataDiskBlockSize = &kubevirtv1.BlockSize{
+				Custom: &kubevirtv1.CustomBlockSize{Logical: 512, Physical: 512},
+			}
+			result = vm.BuildVMSpec(opts)
+
+			Expect(result.Spec.Template.Spec.Domain.Devices.Disks[2].BlockSize.Custom.Logical).To(Equal(uint(4096)))
+		})
+
+		It("should leave the block size unset by default", func() {
+			result = vm.BuildVMSpec(opts)
+			for _, d := range result.Spec.Template.Spec.Domain.Devices.Disks {
+				Expect(d.BlockSize).To(BeNil())
+			}
+		})
+	})
+
+	It("should attach the service account as a disk when named", func() {
+		opts.ServiceAccountName = "virtwork-api-churn"
+		result = vm.BuildVMSpec(opts)
+
+		disks := result.Spec.Template.Spec.Domain.Devices.Disks
+		Expect(disks).To(HaveLen(3))
+		Expect(disks[2].Name).To(Equal("serviceaccountdisk"))
+		Expect(disks[2].Serial).To(Equal(vm.ServiceAccountDiskSerial))
+
+		volumes := result.Spec.Template.Spec.Volumes
+		Expect(volumes).To(HaveLen(3))
+		Expect(volumes[2].Name).To(Equal("serviceaccountdisk"))
+		Expect(volumes[2].ServiceAccount).NotTo(BeNil())
+		Expect(volumes[2].ServiceAccount.ServiceAccountName).To(Equal("virtwork-api-churn"))
+	})
+
+	It("should attach filesystems with their volumes", func() {
+		opts.Filesystems = []kubevirtv1.Filesystem{
+			{Name: "datafs", Virtiofs: &kubevirtv1.FilesystemVirtiofs{}},
+		}
+		opts.ExtraVolumes = []kubevirtv1.Volume{
+			{
+				Name: "datafs",
+				VolumeSource: kubevirtv1.VolumeSource{
+					PersistentVolumeClaim: &kubevirtv1.PersistentVolumeClaimVolumeSource{
+						PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{ClaimName: "shared-data"},
+					},
+				},
+			},
+		}
+		result = vm.BuildVMSpec(opts)
+
+		filesystems := result.Spec.Template.Spec.Domain.Devices.Filesystems
+		Expect(filesystems).To(HaveLen(1))
+		Expect(filesystems[0].Name).To(Equal("datafs"))
+		Expect(filesystems[0].Virtiofs).NotTo(BeNil())
+
+		volumes := result.Spec.Template.Spec.Volumes
+		Expect(volumes).To(HaveLen(3))
+		Expect(volumes[2].Name).To(Equal("datafs"))
+		Expect(volumes[2].PersistentVolumeClaim.ClaimName).To(Equal("shared-data"))
+	})
+
+	It("should not attach filesystems by default", func() {
+		Expect(result.Spec.Template.Spec.Domain.Devices.Filesystems).To(BeEmpty())
+	})
+
+	It("should pass through the requested GPUs", func() {
+		opts.GPUs = []kubevirtv1.GPU{
+			{Name: "gpu0", DeviceName: "nvidia.com/GA102GL_A10"},
+			{Name: "gpu1", DeviceName: "nvidia.com/NVIDIA_A10-4Q"},
+		}
+		result = vm.BuildVMSpec(opts)
+
+		Expect(result.Spec.Template.Spec.Domain.Devices.GPUs).To(Equal(opts.GPUs))
+	})
+
+	It("should not attach GPUs by default", func() {
+		Expect(result.Spec.Template.Spec.Domain.Devices.GPUs).To(BeEmpty())
+	})
+
+	It("should not attach a service account disk by default", func() {
+		for _, v := range result.Spec.Template.Spec.Volumes {
+			Expect(v.ServiceAccount).To(BeNil())
+		}
+	})
+
 	It("should include data volume templates when provided", func() {
-		dvt := vm.BuildDataVolumeTemplate("test-data", "10Gi")
+		dvt := vm.BuildDataVolumeTemplate("test-data", "10Gi", vm.DataVolumeOpts{})
 		opts.DataVolumeTemplates = []kubevirtv1.DataVolumeTemplateSpec{dvt}
 		result = vm.BuildVMSpec(opts)
 
 		Expect(result.Spec.DataVolumeTemplates).To(HaveLen(1))
 		Expect(result.Spec.DataVolumeTemplates[0].Name).To(Equal("test-data"))
 	})
+
+	It("should emit gzip+base64 userdata when CompressCloudInit is set", func() {
+		opts.CloudInitUserdata = "#cloud-config\npackages:\n  - fio\n"
+		opts.CompressCloudInit = true
+		result = vm.BuildVMSpec(opts)
+
+		var ciVol *kubevirtv1.Volume
+		for i, v := range result.Spec.Template.Spec.Volumes {
+			if v.Name == "cloudinitdisk" {
+				ciVol = &result.Spec.Template.Spec.Volumes[i]
+			}
+		}
+		Expect(ciVol).NotTo(BeNil())
+		Expect(ciVol.CloudInitNoCloud.UserData).To(BeEmpty())
+
+		raw, err := base64.StdEncoding.DecodeString(ciVol.CloudInitNoCloud.UserDataBase64)
+		Expect(err).NotTo(HaveOccurred())
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := io.ReadAll(zr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(decoded)).To(Equal("#cloud-config\npackages:\n  - fio\n"))
+	})
+
+	It("should keep the secret reference when CompressCloudInit is set with a secret", func() {
+		opts.CloudInitSecretName = "test-vm-cloudinit"
+		opts.CompressCloudInit = true
+		result = vm.BuildVMSpec(opts)
+
+		for _, v := range result.Spec.Template.Spec.Volumes {
+			if v.Name == "cloudinitdisk" {
+				Expect(v.CloudInitNoCloud.UserDataSecretRef.Name).To(Equal("test-vm-cloudinit"))
+				Expect(v.CloudInitNoCloud.UserDataBase64).To(BeEmpty())
+			}
+		}
+	})
+
+	It("should leave the clock unset by default", func() {
+		Expect(result.Spec.Template.Spec.Domain.Clock).To(BeNil())
+	})
+
+	It("should set a UTC clock when ClockTimezone is UTC", func() {
+		opts.ClockTimezone = "UTC"
+		result = vm.BuildVMSpec(opts)
+
+		clock := result.Spec.Template.Spec.Domain.Clock
+		Expect(clock).NotTo(BeNil())
+		Expect(clock.UTC).NotTo(BeNil())
+		Expect(clock.Timezone).To(BeNil())
+		Expect(clock.Timer).To(BeNil())
+	})
+
+	It("should set a timezone clock when ClockTimezone names a zone", func() {
+		opts.ClockTimezone = "America/New_York"
+		result = vm.BuildVMSpec(opts)
+
+		clock := result.Spec.Template.Spec.Domain.Clock
+		Expect(clock.UTC).To(BeNil())
+		Expect(clock.Timezone).NotTo(BeNil())
+		Expect(string(*clock.Timezone)).To(Equal("America/New_York"))
+	})
+
+	It("should enable and disable the configured timers", func() {
+		opts.Timers = map[string]bool{"hpet": false, "pit": false, "kvm": true, "hyperv": true}
+		result = vm.BuildVMSpec(opts)
+
+		clock := result.Spec.Template.Spec.Domain.Clock
+		Expect(clock).NotTo(BeNil())
+		Expect(clock.UTC).NotTo(BeNil())
+		Expect(clock.Timer).NotTo(BeNil())
+		Expect(*clock.Timer.HPET.Enabled).To(BeFalse())
+		Expect(*clock.Timer.PIT.Enabled).To(BeFalse())
+		Expect(*clock.Timer.KVM.Enabled).To(BeTrue())
+		Expect(*clock.Timer.Hyperv.Enabled).To(BeTrue())
+		Expect(clock.Timer.RTC).To(BeNil())
+	})
+
+	It("should leave timers that were not configured unset", func() {
+		opts.Timers = map[string]bool{"hpet": false}
+		result = vm.BuildVMSpec(opts)
+
+		timer := result.Spec.Template.Spec.Domain.Clock.Timer
+		Expect(timer.HPET).NotTo(BeNil())
+		Expect(timer.KVM).To(BeNil())
+		Expect(timer.PIT).To(BeNil())
+		Expect(timer.Hyperv).To(BeNil())
+	})
+
+	It("should leave CPU model, topology, and placement at KubeVirt defaults", func() {
+		cpu := result.Spec.Template.Spec.Domain.CPU
+		Expect(cpu.Model).To(BeEmpty())
+		Expect(cpu.DedicatedCPUPlacement).To(BeFalse())
+		Expect(cpu.Sockets).To(BeZero())
+		Expect(cpu.Threads).To(BeZero())
+		Expect(result.Spec.Template.Spec.Domain.Resources.Limits).To(BeEmpty())
+	})
+
+	It("should set the CPU model", func() {
+		opts.CPUModel = "host-passthrough"
+		result = vm.BuildVMSpec(opts)
+
+		cpu := result.Spec.Template.Spec.Domain.CPU
+		Expect(cpu.Model).To(Equal("host-passthrough"))
+		Expect(cpu.DedicatedCPUPlacement).To(BeFalse())
+		Expect(result.Spec.Template.Spec.Domain.Resources.Limits).To(BeEmpty())
+	})
+
+	It("should set sockets and threads", func() {
+		opts.Sockets = 2
+		opts.Threads = 2
+		result = vm.BuildVMSpec(opts)
+
+		cpu := result.Spec.Template.Spec.Domain.CPU
+		Expect(cpu.Cores).To(Equal(uint32(2)))
+		Expect(cpu.Sockets).To(Equal(uint32(2)))
+		Expect(cpu.Threads).To(Equal(uint32(2)))
+	})
+
+	It("should request equal CPU and memory limits with dedicated placement", func() {
+		opts.DedicatedCPUPlacement = true
+		result = vm.BuildVMSpec(opts)
+
+		domain := result.Spec.Template.Spec.Domain
+		Expect(domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+		Expect(domain.Resources.Requests.Cpu().String()).To(Equal("2"))
+		Expect(domain.Resources.Limits.Cpu().String()).To(Equal("2"))
+		Expect(domain.Resources.Limits.Memory().String()).To(Equal("2Gi"))
+		Expect(domain.Resources.Requests.Memory().String()).To(Equal("2Gi"))
+	})
+
+	It("should size dedicated CPU limits by the full topology", func() {
+		opts.DedicatedCPUPlacement = true
+		opts.CPUModel = "host-passthrough"
+		opts.Sockets = 2
+		opts.Threads = 2
+		result = vm.BuildVMSpec(opts)
+
+		domain := result.Spec.Template.Spec.Domain
+		Expect(domain.CPU.Model).To(Equal("host-passthrough"))
+		Expect(domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+		Expect(domain.Resources.Requests.Cpu().String()).To(Equal("8"))
+		Expect(domain.Resources.Limits.Cpu().String()).To(Equal("8"))
+	})
+
+	It("should not request hugepages by default", func() {
+		Expect(result.Spec.Template.Spec.Domain.Memory).To(BeNil())
+	})
+
+	It("should back guest memory with the requested hugepages", func() {
+		opts.Hugepages = "1Gi"
+		result = vm.BuildVMSpec(opts)
+
+		domain := result.Spec.Template.Spec.Domain
+		Expect(domain.Memory).NotTo(BeNil())
+		Expect(domain.Memory.Hugepages).To(Equal(&kubevirtv1.Hugepages{PageSize: "1Gi"}))
+		Expect(domain.Resources.Requests.Memory().String()).To(Equal("2Gi"))
+	})
+
+	It("should round memory up to a whole number of hugepages", func() {
+		opts.Memory = "1500Mi"
+		opts.Hugepages = "1Gi"
+		result = vm.BuildVMSpec(opts)
+		Expect(result.Spec.Template.Spec.Domain.Resources.Requests.Memory().String()).To(Equal("2Gi"))
+
+		opts.Memory = "1001Mi"
+		opts.Hugepages = "2Mi"
+		result = vm.BuildVMSpec(opts)
+		Expect(result.Spec.Template.Spec.Domain.Resources.Requests.Memory().String()).To(Equal("1002Mi"))
+	})
+
+	It("should set the memory and CPU limits", func() {
+		opts.MemoryLimit = "4Gi"
+		opts.CPULimit = "1500m"
+		result = vm.BuildVMSpec(opts)
+
+		res := result.Spec.Template.Spec.Domain.Resources
+		Expect(res.Requests).To(Equal(corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		}))
+		Expect(res.Limits).To(Equal(corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+			corev1.ResourceCPU:    resource.MustParse("1500m"),
+		}))
+		Expect(res.OvercommitGuestOverhead).To(BeFalse())
+	})
+
+	It("should set only the limits that are given", func() {
+		opts.CPULimit = "2"
+		result = vm.BuildVMSpec(opts)
+
+		Expect(result.Spec.Template.Spec.Domain.Resources.Limits).To(Equal(corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("2"),
+		}))
+	})
+
+	It("should round the memory limit up to a whole number of hugepages", func() {
+		opts.MemoryLimit = "2500Mi"
+		opts.Hugepages = "1Gi"
+		result = vm.BuildVMSpec(opts)
+
+		Expect(result.Spec.Template.Spec.Domain.Resources.Limits.Memory().String()).To(Equal("3Gi"))
+	})
+
+	It("should keep dedicated placement limits equal to the requests", func() {
+		opts.DedicatedCPUPlacement = true
+		opts.MemoryLimit = "4Gi"
+		opts.CPULimit = "1"
+		result = vm.BuildVMSpec(opts)
+
+		res := result.Spec.Template.Spec.Domain.Resources
+		Expect(res.Limits.Memory().String()).To(Equal("2Gi"))
+		Expect(res.Limits.Cpu().String()).To(Equal("2"))
+	})
+
+	It("should overcommit the guest overhead when requested", func() {
+		opts.OvercommitGuestOverhead = true
+		result = vm.BuildVMSpec(opts)
+
+		res := result.Spec.Template.Spec.Domain.Resources
+		Expect(res.OvercommitGuestOverhead).To(BeTrue())
+		Expect(res.Limits).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Requests", func() {
+	It("should request 100m CPU per vCPU without dedicated placement", func() {
+		cpu, memory := vm.Requests(vm.VMSpecOpts{CPUCores: 2, Sockets: 2, Memory: "4Gi"})
+		Expect(cpu.Equal(resource.MustParse("400m"))).To(BeTrue())
+		Expect(memory.Equal(resource.MustParse("4Gi"))).To(BeTrue())
+	})
+
+	It("should request a whole CPU per vCPU with dedicated placement", func() {
+		cpu, _ := vm.Requests(vm.VMSpecOpts{CPUCores: 4, Memory: "4Gi", DedicatedCPUPlacement: true})
+		Expect(cpu.Equal(resource.MustParse("4"))).To(BeTrue())
+	})
+
+	It("should round memory up to the hugepages size", func() {
+		_, memory := vm.Requests(vm.VMSpecOpts{CPUCores: 1, Memory: "1500Mi", Hugepages: "1Gi"})
+		Expect(memory.Equal(resource.MustParse("2Gi"))).To(BeTrue())
+	})
 })
 
 var _ = Describe("BuildDataVolumeTemplate", func() {
 	It("should set name", func() {
-		dvt := vm.BuildDataVolumeTemplate("data-disk", "20Gi")
+		dvt := vm.BuildDataVolumeTemplate("data-disk", "20Gi", vm.DataVolumeOpts{})
 		Expect(dvt.Name).To(Equal("data-disk"))
 	})
 
 	It("should set blank source", func() {
-		dvt := vm.BuildDataVolumeTemplate("data-disk", "20Gi")
+		dvt := vm.BuildDataVolumeTemplate("data-disk", "20Gi", vm.DataVolumeOpts{})
 		Expect(dvt.Spec.Source).NotTo(BeNil())
 		Expect(dvt.Spec.Source.Blank).NotTo(BeNil())
 	})
 
 	It("should set storage size", func() {
-		dvt := vm.BuildDataVolumeTemplate("data-disk", "20Gi")
+		dvt := vm.BuildDataVolumeTemplate("data-disk", "20Gi", vm.DataVolumeOpts{})
 		Expect(dvt.Spec.Storage).NotTo(BeNil())
 		storageReq := dvt.Spec.Storage.Resources.Requests[corev1.ResourceStorage]
 		expected := resource.MustParse("20Gi")
 		Expect(storageReq.Equal(expected)).To(BeTrue())
 	})
+
+	It("should leave storage class and access modes unset by default", func() {
+		dvt := vm.BuildDataVolumeTemplate("data-disk", "20Gi", vm.DataVolumeOpts{})
+		Expect(dvt.Spec.Storage.StorageClassName).To(BeNil())
+		Expect(dvt.Spec.Storage.AccessModes).To(BeNil())
+	})
+
+	It("should set storage class and access modes when provided", func() {
+		storageClass := "ocs-storagecluster-ceph-rbd"
+		dvt := vm.BuildDataVolumeTemplate("data-disk", "20Gi", vm.DataVolumeOpts{
+			StorageClassName: &storageClass,
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+		})
+		Expect(dvt.Spec.Storage.StorageClassName).NotTo(BeNil())
+		Expect(*dvt.Spec.Storage.StorageClassName).To(Equal("ocs-storagecluster-ceph-rbd"))
+		Expect(dvt.Spec.Storage.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+	})
+})
+
+var _ = Describe("BuildDataVolumeTemplateFromSource", func() {
+	It("should import from a registry source", func() {
+		dvt := vm.BuildDataVolumeTemplateFromSource("data-disk", "20Gi",
+			vm.RegistrySource("docker://quay.io/example/pgbench-data:latest"), vm.DataVolumeOpts{})
+		Expect(dvt.Name).To(Equal("data-disk"))
+		Expect(dvt.Spec.Source.Blank).To(BeNil())
+		Expect(dvt.Spec.Source.Registry).NotTo(BeNil())
+		Expect(*dvt.Spec.Source.Registry.URL).To(Equal("docker://quay.io/example/pgbench-data:latest"))
+	})
+
+	It("should clone from a PVC source", func() {
+		dvt := vm.BuildDataVolumeTemplateFromSource("data-disk", "20Gi", vm.PVCSource("golden", "pgbench-seed"), vm.DataVolumeOpts{})
+		Expect(dvt.Spec.Source.Blank).To(BeNil())
+		Expect(dvt.Spec.Source.PVC).NotTo(BeNil())
+		Expect(dvt.Spec.Source.PVC.Namespace).To(Equal("golden"))
+		Expect(dvt.Spec.Source.PVC.Name).To(Equal("pgbench-seed"))
+	})
+
+	It("should set storage size", func() {
+		dvt := vm.BuildDataVolumeTemplateFromSource("data-disk", "50Gi", vm.PVCSource("golden", "pgbench-seed"), vm.DataVolumeOpts{})
+		storageReq := dvt.Spec.Storage.Resources.Requests[corev1.ResourceStorage]
+		Expect(storageReq.Equal(resource.MustParse("50Gi"))).To(BeTrue())
+	})
 })
 
 var _ = Describe("CreateVM", func() {
@@ -317,9 +904,36 @@ var _ = Describe("CreateVM", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(apierrors.IsUnauthorized(err)).To(BeTrue())
 	})
+
+	It("should abort a slow create when the deadline expires", func() {
+		// A long backoff shows the deadline also interrupts the retry wait.
+		DeferCleanup(vm.SetBaseRetryBackoff(time.Second))
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(20 * time.Millisecond):
+					}
+					return apierrors.NewServerTimeout(
+						schema.GroupResource{Group: "kubevirt.io", Resource: "virtualmachines"}, "create", 1)
+				},
+			}).
+			Build()
+
+		deadlineCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := vm.CreateVM(deadlineCtx, c, newTestVM("slow-vm"))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+	})
 })
 
-var _ = Describe("DeleteVM", func() {
+var _ = Describe("CreateVMWithOptions", func() {
 	var (
 		ctx    context.Context
 		scheme = cluster.NewScheme()
@@ -327,20 +941,90 @@ var _ = Describe("DeleteVM", func() {
 
 	BeforeEach(func() {
 		ctx = context.Background()
-		restore := vm.SetBaseRetryBackoff(time.Millisecond)
-		DeferCleanup(restore)
 	})
 
-	It("should delete VM successfully", func() {
-		testVM := vm.BuildVMSpec(vm.VMSpecOpts{
-			Name:               "delete-me",
+	unavailable := func(calls *int) client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					*calls++
+					return apierrors.NewServiceUnavailable("temporarily unavailable")
+				},
+			}).
+			Build()
+	}
+
+	testVM := func() *kubevirtv1.VirtualMachine {
+		return vm.BuildVMSpec(vm.VMSpecOpts{
+			Name:               "retry-vm",
 			Namespace:          "default",
 			ContainerDiskImage: "test-image",
-			CloudInitUserdata:  "#cloud-config\n",
 			CPUCores:           1,
 			Memory:             "1Gi",
 		})
-		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(testVM).Build()
+	}
+
+	It("should retry MaxRetries times and then report exhaustion", func() {
+		calls := 0
+		err := vm.CreateVMWithOptions(ctx, unavailable(&calls), testVM(), vm.RetryOptions{
+			MaxRetries:  7,
+			BaseBackoff: time.Millisecond,
+			MaxBackoff:  2 * time.Millisecond,
+		})
+		Expect(err).To(MatchError(ContainSubstring("max retries (7) exceeded")))
+		Expect(apierrors.IsServiceUnavailable(err)).To(BeTrue())
+		Expect(calls).To(Equal(8))
+	})
+
+	It("should keep every backoff within MaxBackoff", func() {
+		// Uncapped, ten retries from 1ms would wait over a second in total.
+		calls := 0
+		start := time.Now()
+		err := vm.CreateVMWithOptions(ctx, unavailable(&calls), testVM(), vm.RetryOptions{
+			MaxRetries:  10,
+			BaseBackoff: time.Millisecond,
+			MaxBackoff:  5 * time.Millisecond,
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(calls).To(Equal(11))
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+	})
+
+	It("should make a single attempt with zero retries", func() {
+		calls := 0
+		err := vm.CreateVMWithOptions(ctx, unavailable(&calls), testVM(), vm.RetryOptions{})
+		Expect(err).To(MatchError(ContainSubstring("max retries (0) exceeded")))
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should default to five retries", func() {
+		Expect(vm.DefaultRetryOptions().MaxRetries).To(Equal(5))
+	})
+})
+
+var _ = Describe("DeleteVM", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		restore := vm.SetBaseRetryBackoff(time.Millisecond)
+		DeferCleanup(restore)
+	})
+
+	It("should delete VM successfully", func() {
+		testVM := vm.BuildVMSpec(vm.VMSpecOpts{
+			Name:               "delete-me",
+			Namespace:          "default",
+			ContainerDiskImage: "test-image",
+			CloudInitUserdata:  "#cloud-config\n",
+			CPUCores:           1,
+			Memory:             "1Gi",
+		})
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(testVM).Build()
 
 		err := vm.DeleteVM(ctx, c, "delete-me", "default")
 		Expect(err).NotTo(HaveOccurred())
@@ -358,6 +1042,335 @@ var _ = Describe("DeleteVM", func() {
 	})
 })
 
+var _ = Describe("DeleteVMWithOptions", func() {
+	var (
+		ctx      context.Context
+		scheme   = cluster.NewScheme()
+		captured *client.DeleteOptions
+		c        client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		captured = nil
+		testVM := vm.BuildVMSpec(vm.VMSpecOpts{
+			Name:               "stuck",
+			Namespace:          "default",
+			ContainerDiskImage: "test-image",
+			CloudInitUserdata:  "#cloud-config\n",
+			CPUCores:           1,
+			Memory:             "1Gi",
+		})
+		c = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(testVM).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					captured = &client.DeleteOptions{}
+					captured.ApplyOptions(opts)
+					return cl.Delete(ctx, obj, opts...)
+				},
+			}).
+			Build()
+	})
+
+	It("should forward the grace period and propagation policy", func() {
+		Expect(vm.DeleteVMWithOptions(ctx, c, "stuck", "default", vm.ForceDeleteOptions())).To(Succeed())
+
+		Expect(captured).NotTo(BeNil())
+		Expect(captured.GracePeriodSeconds).To(HaveValue(Equal(int64(0))))
+		Expect(captured.PropagationPolicy).To(HaveValue(Equal(metav1.DeletePropagationBackground)))
+	})
+
+	It("should leave the server defaults in place without options", func() {
+		Expect(vm.DeleteVM(ctx, c, "stuck", "default")).To(Succeed())
+
+		Expect(captured).NotTo(BeNil())
+		Expect(captured.GracePeriodSeconds).To(BeNil())
+		Expect(captured.PropagationPolicy).To(BeNil())
+	})
+
+	It("should skip on NotFound", func() {
+		Expect(vm.DeleteVMWithOptions(ctx, c, "nonexistent", "default", vm.ForceDeleteOptions())).To(Succeed())
+	})
+})
+
+var _ = Describe("ApplyVM", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		restore := vm.SetBaseRetryBackoff(time.Millisecond)
+		DeferCleanup(restore)
+	})
+
+	newTestVM := func(memory string, labels map[string]string) *kubevirtv1.VirtualMachine {
+		return vm.BuildVMSpec(vm.VMSpecOpts{
+			Name:               "apply-vm",
+			Namespace:          "default",
+			ContainerDiskImage: "test-image",
+			CloudInitUserdata:  "#cloud-config\n",
+			CPUCores:           1,
+			Memory:             memory,
+			Labels:             labels,
+		})
+	}
+
+	get := func(c client.Client) *kubevirtv1.VirtualMachine {
+		got := &kubevirtv1.VirtualMachine{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "apply-vm", Namespace: "default"}, got)).To(Succeed())
+		return got
+	}
+
+	It("should create a VM that does not exist", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		updated, err := vm.ApplyVM(ctx, c, newTestVM("1Gi", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+		Expect(get(c).Spec.Template.Spec.Domain.Resources.Requests.Memory().String()).To(Equal("1Gi"))
+	})
+
+	It("should apply a memory change on the second run", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		_, err := vm.ApplyVM(ctx, c, newTestVM("1Gi", map[string]string{"run": "first", "kept": "yes"}))
+		Expect(err).NotTo(HaveOccurred())
+		before := get(c)
+
+		updated, err := vm.ApplyVM(ctx, c, newTestVM("4Gi", map[string]string{"run": "second"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+
+		got := get(c)
+		Expect(got.Spec.Template.Spec.Domain.Resources.Requests.Memory().String()).To(Equal("4Gi"))
+		Expect(got.Labels).To(Equal(map[string]string{"run": "second", "kept": "yes"}))
+		Expect(got.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue("run", "second"))
+		Expect(got.ResourceVersion).NotTo(Equal(before.ResourceVersion))
+	})
+
+	It("should leave the rest of the existing spec alone", func() {
+		existing := newTestVM("1Gi", nil)
+		existing.Spec.Template.Spec.Volumes[0].ContainerDisk.Image = "old-image"
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+		_, err := vm.ApplyVM(ctx, c, newTestVM("2Gi", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get(c).Spec.Template.Spec.Volumes[0].ContainerDisk.Image).To(Equal("old-image"))
+	})
+
+	It("should retry an update that conflicts", func() {
+		var updates int
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(newTestVM("1Gi", nil)).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+					updates++
+					if updates == 1 {
+						return apierrors.NewConflict(schema.GroupResource{Resource: "virtualmachines"}, obj.GetName(), nil)
+					}
+					return cl.Update(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		updated, err := vm.ApplyVM(ctx, c, newTestVM("2Gi", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updates).To(Equal(2))
+		Expect(get(c).Spec.Template.Spec.Domain.Resources.Requests.Memory().String()).To(Equal("2Gi"))
+	})
+})
+
+var _ = Describe("StartVM", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		restore := vm.SetBaseRetryBackoff(time.Millisecond)
+		DeferCleanup(restore)
+	})
+
+	It("should set running true on a stopped VM", func() {
+		testVM := vm.BuildVMSpec(vm.VMSpecOpts{
+			Name:               "stopped",
+			Namespace:          "default",
+			ContainerDiskImage: "test-image",
+			CloudInitUserdata:  "#cloud-config\n",
+			CPUCores:           1,
+			Memory:             "1Gi",
+			StartStopped:       true,
+		})
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(testVM).Build()
+
+		Expect(vm.StartVM(ctx, c, "stopped", "default")).To(Succeed())
+
+		got := &kubevirtv1.VirtualMachine{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "stopped", Namespace: "default"}, got)).To(Succeed())
+		Expect(got.Spec.Running).NotTo(BeNil())
+		Expect(*got.Spec.Running).To(BeTrue())
+	})
+
+	It("should fail for a missing VM", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		err := vm.StartVM(ctx, c, "nonexistent", "default")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("RecreateVM", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		restore := vm.SetBaseRetryBackoff(time.Millisecond)
+		DeferCleanup(restore)
+	})
+
+	newTestVM := func(name string) *kubevirtv1.VirtualMachine {
+		return vm.BuildVMSpec(vm.VMSpecOpts{
+			Name:               name,
+			Namespace:          "default",
+			ContainerDiskImage: "test-image",
+			CloudInitUserdata:  "#cloud-config\n",
+			CPUCores:           1,
+			Memory:             "1Gi",
+		})
+	}
+
+	failedVMI := func(name string) *kubevirtv1.VirtualMachineInstance {
+		return &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: kubevirtv1.Failed},
+		}
+	}
+
+	It("should delete the VM and create it again once its VMI is gone", func() {
+		var deletes, creates int
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(newTestVM("failed-vm"), failedVMI("failed-vm")).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					deletes++
+					// KubeVirt deletes the VMI along with its VM
+					if err := cl.Delete(ctx, failedVMI(obj.GetName())); err != nil {
+						return err
+					}
+					return cl.Delete(ctx, obj, opts...)
+				},
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					creates++
+					return cl.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		err := vm.RecreateVM(ctx, c, newTestVM("failed-vm"), vm.DefaultRetryOptions(), time.Second, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletes).To(Equal(1))
+		Expect(creates).To(Equal(1))
+
+		got := &kubevirtv1.VirtualMachine{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "failed-vm", Namespace: "default"}, got)).To(Succeed())
+	})
+
+	It("should not create the VM while its old VMI remains", func() {
+		var creates int
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(newTestVM("stuck-vm"), failedVMI("stuck-vm")).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					creates++
+					return cl.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		err := vm.RecreateVM(ctx, c, newTestVM("stuck-vm"), vm.DefaultRetryOptions(), 50*time.Millisecond, 10*time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("timed out waiting for VM default/stuck-vm to be deleted")))
+		Expect(creates).To(BeZero())
+	})
+
+	It("should not create the VM until its old DataVolumes are gone", func() {
+		withDisk := func() *kubevirtv1.VirtualMachine {
+			v := newTestVM("disk-vm")
+			v.Spec.DataVolumeTemplates = []kubevirtv1.DataVolumeTemplateSpec{
+				vm.BuildDataVolumeTemplate("disk-vm-data", "1Gi", vm.DataVolumeOpts{}),
+			}
+			return v
+		}
+		oldDV := &cdiv1beta1.DataVolume{ObjectMeta: metav1.ObjectMeta{Name: "disk-vm-data", Namespace: "default"}}
+
+		// The old DataVolume lingers for a few polls after its VM is deleted
+		var dvGets int
+		var dvExistedAtCreate bool
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(withDisk(), failedVMI("disk-vm"), oldDV).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					if err := cl.Delete(ctx, failedVMI(obj.GetName())); err != nil {
+						return err
+					}
+					return cl.Delete(ctx, obj, opts...)
+				},
+				Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if _, ok := obj.(*cdiv1beta1.DataVolume); ok {
+						if dvGets++; dvGets == 3 {
+							Expect(cl.Delete(ctx, oldDV.DeepCopy())).To(Succeed())
+						}
+					}
+					return cl.Get(ctx, key, obj, opts...)
+				},
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					err := cl.Get(ctx, client.ObjectKeyFromObject(oldDV), &cdiv1beta1.DataVolume{})
+					dvExistedAtCreate = err == nil
+					return cl.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		err := vm.RecreateVM(ctx, c, withDisk(), vm.DefaultRetryOptions(), time.Second, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dvGets).To(BeNumerically(">=", 3))
+		Expect(dvExistedAtCreate).To(BeFalse())
+	})
+
+	It("should time out while an old DataVolume remains", func() {
+		v := newTestVM("stuck-disk-vm")
+		v.Spec.DataVolumeTemplates = []kubevirtv1.DataVolumeTemplateSpec{
+			vm.BuildDataVolumeTemplate("stuck-disk-vm-data", "1Gi", vm.DataVolumeOpts{}),
+		}
+		var creates int
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(&cdiv1beta1.DataVolume{ObjectMeta: metav1.ObjectMeta{Name: "stuck-disk-vm-data", Namespace: "default"}}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					creates++
+					return cl.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		err := vm.RecreateVM(ctx, c, v, vm.DefaultRetryOptions(), 50*time.Millisecond, 10*time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("timed out waiting for VM default/stuck-disk-vm to be deleted")))
+		Expect(creates).To(BeZero())
+	})
+})
+
 var _ = Describe("ListVMs", func() {
 	var (
 		ctx    context.Context
@@ -442,3 +1455,299 @@ var _ = Describe("GetVMIPhase", func() {
 		Expect(err).To(HaveOccurred())
 	})
 })
+
+var _ = Describe("GetVMStatus", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	virtualMachine := func(status kubevirtv1.VirtualMachineStatus) *kubevirtv1.VirtualMachine {
+		return &kubevirtv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-vm", Namespace: "default"},
+			Status:     status,
+		}
+	}
+
+	It("should report a ready VM with its agent connected", func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-vm", Namespace: "default"},
+			Status: kubevirtv1.VirtualMachineInstanceStatus{
+				Phase:    kubevirtv1.Running,
+				NodeName: "worker-1",
+				Conditions: []kubevirtv1.VirtualMachineInstanceCondition{
+					{Type: kubevirtv1.VirtualMachineInstanceReady, Status: corev1.ConditionTrue},
+					{Type: kubevirtv1.VirtualMachineInstanceAgentConnected, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			virtualMachine(kubevirtv1.VirtualMachineStatus{Ready: true, PrintableStatus: kubevirtv1.VirtualMachineStatusRunning}),
+			vmi,
+		).Build()
+
+		status, err := vm.GetVMStatus(ctx, c, "test-vm", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(vm.VMStatus{
+			Phase:           kubevirtv1.Running,
+			Ready:           true,
+			AgentConnected:  true,
+			NodeName:        "worker-1",
+			PrintableStatus: kubevirtv1.VirtualMachineStatusRunning,
+		}))
+	})
+
+	It("should explain why a running VM is not ready", func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-vm", Namespace: "default"},
+			Status: kubevirtv1.VirtualMachineInstanceStatus{
+				Phase:    kubevirtv1.Running,
+				NodeName: "worker-2",
+				Conditions: []kubevirtv1.VirtualMachineInstanceCondition{
+					{Type: kubevirtv1.VirtualMachineInstanceReady, Status: corev1.ConditionFalse, Message: "readiness probe failed"},
+					{Type: kubevirtv1.VirtualMachineInstanceAgentConnected, Status: corev1.ConditionFalse},
+				},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			virtualMachine(kubevirtv1.VirtualMachineStatus{PrintableStatus: kubevirtv1.VirtualMachineStatusRunning}),
+			vmi,
+		).Build()
+
+		status, err := vm.GetVMStatus(ctx, c, "test-vm", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(vm.VMStatus{
+			Phase:           kubevirtv1.Running,
+			NodeName:        "worker-2",
+			PrintableStatus: kubevirtv1.VirtualMachineStatusRunning,
+			Message:         "readiness probe failed",
+		}))
+	})
+
+	It("should report the Unknown phase for a VM without a VMI", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			virtualMachine(kubevirtv1.VirtualMachineStatus{
+				PrintableStatus: kubevirtv1.VirtualMachineStatusStopped,
+				Conditions: []kubevirtv1.VirtualMachineCondition{
+					{Type: kubevirtv1.VirtualMachineReady, Status: corev1.ConditionFalse, Message: "VMI does not exist"},
+				},
+			}),
+		).Build()
+
+		status, err := vm.GetVMStatus(ctx, c, "test-vm", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(vm.VMStatus{
+			Phase:           kubevirtv1.Unknown,
+			PrintableStatus: kubevirtv1.VirtualMachineStatusStopped,
+			Message:         "VMI does not exist",
+		}))
+	})
+
+	It("should return an error for a nonexistent VM", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		_, err := vm.GetVMStatus(ctx, c, "nonexistent", "default")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should return an error when getting the VMI fails", func() {
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(virtualMachine(kubevirtv1.VirtualMachineStatus{})).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if _, ok := obj.(*kubevirtv1.VirtualMachineInstance); ok {
+						return apierrors.NewServiceUnavailable("try again")
+					}
+					return cl.Get(ctx, key, obj, opts...)
+				},
+			}).
+			Build()
+
+		_, err := vm.GetVMStatus(ctx, c, "test-vm", "default")
+		Expect(err).To(MatchError(ContainSubstring("getting VMI default/test-vm")))
+	})
+})
+
+var _ = Describe("ValidateSecondaryNetworks", func() {
+	It("should accept uniquely named networks", func() {
+		Expect(vm.ValidateSecondaryNetworks(nil)).To(Succeed())
+		Expect(vm.ValidateSecondaryNetworks([]vm.SecondaryNetwork{
+			{Name: "net1", NADName: "east-west"},
+			{Name: "net2", NADName: "virtwork/storage"},
+		})).To(Succeed())
+	})
+
+	It("should reject a network that would replace the pod network", func() {
+		err := vm.ValidateSecondaryNetworks([]vm.SecondaryNetwork{{Name: "default", NADName: "east-west"}})
+		Expect(err).To(MatchError(ContainSubstring("would replace the pod network")))
+	})
+
+	It("should reject duplicate or empty names and a missing NAD", func() {
+		err := vm.ValidateSecondaryNetworks([]vm.SecondaryNetwork{
+			{Name: "net1", NADName: "east-west"},
+			{Name: "net1", NADName: "storage"},
+		})
+		Expect(err).To(MatchError(ContainSubstring("needs a unique name")))
+
+		err = vm.ValidateSecondaryNetworks([]vm.SecondaryNetwork{{NADName: "east-west"}})
+		Expect(err).To(MatchError(ContainSubstring("needs a unique name")))
+
+		err = vm.ValidateSecondaryNetworks([]vm.SecondaryNetwork{{Name: "net1"}})
+		Expect(err).To(MatchError(ContainSubstring("has no NetworkAttachmentDefinition")))
+	})
+})
+
+var _ = Describe("GetVMIIP", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should return the IP of the first interface", func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-vmi",
+				Namespace: "default",
+			},
+			Status: kubevirtv1.VirtualMachineInstanceStatus{
+				Interfaces: []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+					{Name: "default", IP: "10.128.0.11"},
+					{Name: "secondary", IP: "192.168.1.5"},
+				},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmi).Build()
+
+		ip, err := vm.GetVMIIP(ctx, c, "test-vmi", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ip).To(Equal("10.128.0.11"))
+	})
+
+	It("should return the pod network IP when it is not the first interface", func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-vmi",
+				Namespace: "default",
+			},
+			Status: kubevirtv1.VirtualMachineInstanceStatus{
+				Interfaces: []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+					{Name: "net1", IP: "192.168.1.5"},
+					{Name: "default", IP: "10.128.0.11"},
+				},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmi).Build()
+
+		ip, err := vm.GetVMIIP(ctx, c, "test-vmi", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ip).To(Equal("10.128.0.11"))
+	})
+
+	It("should return an empty IP before the VMI reports interfaces", func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-vmi",
+				Namespace: "default",
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmi).Build()
+
+		ip, err := vm.GetVMIIP(ctx, c, "test-vmi", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ip).To(BeEmpty())
+	})
+
+	It("should return error for nonexistent VMI", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		_, err := vm.GetVMIIP(ctx, c, "nonexistent", "default")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("BuildMigration", func() {
+	It("should target the VM's VMI with a generated name", func() {
+		labels := map[string]string{"app.kubernetes.io/managed-by": "virtwork", "virtwork/run-id": "run-1"}
+		m := vm.BuildMigration("virtwork-cpu-0", "virtwork", labels)
+
+		Expect(m.Kind).To(Equal("VirtualMachineInstanceMigration"))
+		Expect(m.APIVersion).To(Equal(kubevirtv1.SchemeGroupVersion.String()))
+		Expect(m.Name).To(BeEmpty())
+		Expect(m.GenerateName).To(Equal("virtwork-cpu-0-migration-"))
+		Expect(m.Namespace).To(Equal("virtwork"))
+		Expect(m.Labels).To(Equal(labels))
+		Expect(m.Spec.VMIName).To(Equal("virtwork-cpu-0"))
+	})
+})
+
+var _ = Describe("CreateMigration", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should create a migration and report its generated name", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		m := vm.BuildMigration("virtwork-cpu-0", "default", nil)
+		Expect(vm.CreateMigration(ctx, c, m)).To(Succeed())
+		Expect(m.Name).To(HavePrefix("virtwork-cpu-0-migration-"))
+
+		created := &kubevirtv1.VirtualMachineInstanceMigration{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: m.Name, Namespace: "default"}, created)).To(Succeed())
+		Expect(created.Spec.VMIName).To(Equal("virtwork-cpu-0"))
+	})
+
+	It("should create a separate migration each time", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		first := vm.BuildMigration("virtwork-cpu-0", "default", nil)
+		second := vm.BuildMigration("virtwork-cpu-0", "default", nil)
+		Expect(vm.CreateMigration(ctx, c, first)).To(Succeed())
+		Expect(vm.CreateMigration(ctx, c, second)).To(Succeed())
+		Expect(second.Name).NotTo(Equal(first.Name))
+	})
+})
+
+var _ = Describe("GetMigrationPhase", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should return the migration phase", func() {
+		m := &kubevirtv1.VirtualMachineInstanceMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: "m-1", Namespace: "default"},
+			Status:     kubevirtv1.VirtualMachineInstanceMigrationStatus{Phase: kubevirtv1.MigrationRunning},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(m).Build()
+
+		phase, err := vm.GetMigrationPhase(ctx, c, "m-1", "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(kubevirtv1.MigrationRunning))
+	})
+
+	It("should return error for a nonexistent migration", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		_, err := vm.GetMigrationPhase(ctx, c, "nonexistent", "default")
+		Expect(err).To(HaveOccurred())
+	})
+})
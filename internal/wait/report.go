@@ -0,0 +1,57 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package wait
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Report statuses.
+const (
+	StatusReady  = "ready"
+	StatusFailed = "failed"
+)
+
+// ReportEntry is the readiness outcome of one VM in a readiness report.
+type ReportEntry struct {
+	Status         string  `json:"status"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	LastPhase      string  `json:"last_phase"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// NewReport converts readiness results into report entries keyed by VM name.
+// Elapsed times are rounded to the millisecond.
+func NewReport(results map[string]Result) map[string]ReportEntry {
+	report := make(map[string]ReportEntry, len(results))
+	for name, r := range results {
+		entry := ReportEntry{
+			Status:         StatusReady,
+			ElapsedSeconds: r.Elapsed.Round(time.Millisecond).Seconds(),
+			LastPhase:      string(r.LastPhase),
+		}
+		if r.Err != nil {
+			entry.Status = StatusFailed
+			entry.Error = r.Err.Error()
+		}
+		report[name] = entry
+	}
+	return report
+}
+
+// WriteReport writes the readiness report for results to path as indented
+// JSON, with VMs in name order.
+func WriteReport(path string, results map[string]Result) error {
+	data, err := json.MarshalIndent(NewReport(results), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding readiness report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing readiness report: %w", err)
+	}
+	return nil
+}
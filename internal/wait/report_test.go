@@ -0,0 +1,90 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package wait_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/opdev/virtwork/internal/wait"
+)
+
+var _ = Describe("Readiness report", func() {
+	var results map[string]wait.Result
+
+	BeforeEach(func() {
+		results = map[string]wait.Result{
+			"virtwork-cpu-0": {
+				Elapsed:   42*time.Second + 123456*time.Microsecond,
+				LastPhase: kubevirtv1.Running,
+			},
+			"virtwork-cpu-1": {
+				Err:       errors.New("timed out waiting for VM virtwork/virtwork-cpu-1 to become ready"),
+				Elapsed:   10 * time.Minute,
+				LastPhase: kubevirtv1.Scheduling,
+			},
+			"virtwork-disk-0": {
+				Err:     errors.New("timed out waiting for VM virtwork/virtwork-disk-0 to become ready"),
+				Elapsed: 10 * time.Minute,
+			},
+		}
+	})
+
+	It("should build an entry for each VM from mixed results", func() {
+		report := wait.NewReport(results)
+
+		Expect(report).To(Equal(map[string]wait.ReportEntry{
+			"virtwork-cpu-0": {
+				Status:         wait.StatusReady,
+				ElapsedSeconds: 42.123,
+				LastPhase:      "Running",
+			},
+			"virtwork-cpu-1": {
+				Status:         wait.StatusFailed,
+				ElapsedSeconds: 600,
+				LastPhase:      "Scheduling",
+				Error:          "timed out waiting for VM virtwork/virtwork-cpu-1 to become ready",
+			},
+			"virtwork-disk-0": {
+				Status:         wait.StatusFailed,
+				ElapsedSeconds: 600,
+				Error:          "timed out waiting for VM virtwork/virtwork-disk-0 to become ready",
+			},
+		}))
+	})
+
+	It("should write the report as JSON keyed by VM name", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "ready.json")
+		Expect(wait.WriteReport(path, results)).To(Succeed())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		var decoded map[string]map[string]interface{}
+		Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+		Expect(decoded).To(HaveLen(3))
+		Expect(decoded["virtwork-cpu-0"]).To(Equal(map[string]interface{}{
+			"status":          "ready",
+			"elapsed_seconds": 42.123,
+			"last_phase":      "Running",
+		}))
+		Expect(decoded["virtwork-cpu-1"]).To(HaveKeyWithValue("status", "failed"))
+		Expect(decoded["virtwork-cpu-1"]).To(HaveKeyWithValue("last_phase", "Scheduling"))
+		Expect(decoded["virtwork-cpu-1"]).To(HaveKey("error"))
+	})
+
+	It("should return an error when the file cannot be written", func() {
+		err := wait.WriteReport(filepath.Join(GinkgoT().TempDir(), "missing", "ready.json"), results)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("writing readiness report"))
+	})
+})
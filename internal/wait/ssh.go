@@ -0,0 +1,104 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package wait
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrSSHUnreachable is wrapped by the error WaitForSSH returns when no
+// connection attempt ever reached the host, which usually means there is no
+// route from here to the VM's pod network rather than that SSH is down.
+var ErrSSHUnreachable = errors.New("no route to the VM")
+
+// sshPollInterval is how long WaitForSSH waits between connection attempts.
+const sshPollInterval = 2 * time.Second
+
+// sshDialTimeout bounds a single connection attempt or banner read.
+const sshDialTimeout = 5 * time.Second
+
+// WaitForSSH polls host:port until it accepts a TCP connection and greets
+// with an SSH identification banner, or the timeout expires. It does not log
+// in; user only names the account in errors. A host that refuses the
+// connection is reachable and is retried. If host is empty, the route is
+// unreachable, or every connection attempt timed out, the error wraps
+// ErrSSHUnreachable so a caller can skip the check instead of failing.
+func WaitForSSH(ctx context.Context, host string, port int, user string, timeout time.Duration) error {
+	if host == "" {
+		return fmt.Errorf("waiting for SSH as %s: VM has no IP address: %w", user, ErrSSHUnreachable)
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	deadline := time.Now().Add(timeout)
+	answered := false
+	var lastErr error
+
+	for {
+		attempt := min(sshDialTimeout, time.Until(deadline))
+		if attempt <= 0 {
+			break
+		}
+		err := sshBanner(ctx, addr, attempt)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if isUnreachable(err) {
+			return fmt.Errorf("waiting for SSH as %s on %s: %v: %w", user, addr, err, ErrSSHUnreachable)
+		}
+		var opErr *net.OpError
+		if !errors.As(err, &opErr) || opErr.Op != "dial" || !opErr.Timeout() {
+			answered = true
+		}
+
+		if time.Now().Add(sshPollInterval).After(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for SSH as %s on %s: %w", user, addr, ctx.Err())
+		case <-time.After(sshPollInterval):
+		}
+	}
+
+	if !answered {
+		return fmt.Errorf("waiting for SSH as %s on %s: no answer after %s: %w", user, addr, timeout, ErrSSHUnreachable)
+	}
+	return fmt.Errorf("SSH as %s on %s not ready after %s: %w", user, addr, timeout, lastErr)
+}
+
+// sshBanner connects to addr and reads the server's first line, which an
+// SSH server sends unprompted, within timeout.
+func sshBanner(ctx context.Context, addr string, timeout time.Duration) error {
+	d := net.Dialer{Timeout: timeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("reading SSH banner: %w", err)
+	}
+	if !strings.HasPrefix(line, "SSH-") {
+		return fmt.Errorf("%s did not answer with an SSH banner", addr)
+	}
+	return nil
+}
+
+// isUnreachable reports whether err says there is no route to the host.
+func isUnreachable(err error) bool {
+	return errors.Is(err, syscall.ENETUNREACH) || errors.Is(err, syscall.EHOSTUNREACH)
+}
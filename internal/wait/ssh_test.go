@@ -0,0 +1,127 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package wait_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/wait"
+)
+
+// serveBanner accepts connections on l and greets each with banner until l
+// is closed.
+func serveBanner(l net.Listener, banner string) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte(banner))
+		_ = conn.Close()
+	}
+}
+
+var _ = Describe("WaitForSSH", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	// listen opens a local listener standing in for a VM's SSH port.
+	listen := func() (net.Listener, int) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(l.Close)
+		return l, l.Addr().(*net.TCPAddr).Port
+	}
+
+	// closedPort returns a local port with nothing listening on it.
+	closedPort := func() int {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		port := l.Addr().(*net.TCPAddr).Port
+		Expect(l.Close()).To(Succeed())
+		return port
+	}
+
+	It("should return nil when the port answers with an SSH banner", func() {
+		l, port := listen()
+		go serveBanner(l, "SSH-2.0-OpenSSH_9.6\r\n")
+
+		Expect(wait.WaitForSSH(ctx, "127.0.0.1", port, "virtwork", 5*time.Second)).To(Succeed())
+	})
+
+	It("should retry until the SSH daemon starts listening", func() {
+		port := closedPort()
+
+		started := make(chan net.Listener, 1)
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+			if err != nil {
+				close(started)
+				return
+			}
+			started <- l
+			serveBanner(l, "SSH-2.0-OpenSSH_9.6\r\n")
+		}()
+		DeferCleanup(func() {
+			if l, ok := <-started; ok {
+				_ = l.Close()
+			}
+		})
+
+		Expect(wait.WaitForSSH(ctx, "127.0.0.1", port, "virtwork", 5*time.Second)).To(Succeed())
+	})
+
+	It("should fail when the port does not answer with an SSH banner", func() {
+		l, port := listen()
+		go serveBanner(l, "HTTP/1.1 400 Bad Request\r\n")
+
+		err := wait.WaitForSSH(ctx, "127.0.0.1", port, "virtwork", time.Second)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("did not answer with an SSH banner"))
+		Expect(errors.Is(err, wait.ErrSSHUnreachable)).To(BeFalse())
+	})
+
+	It("should fail, without skipping, when the connection is refused", func() {
+		port := closedPort()
+
+		err := wait.WaitForSSH(ctx, "127.0.0.1", port, "virtwork", time.Second)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("not ready after 1s"))
+		Expect(errors.Is(err, wait.ErrSSHUnreachable)).To(BeFalse())
+	})
+
+	It("should report an unreachable VM when it has no IP address", func() {
+		err := wait.WaitForSSH(ctx, "", 22, "virtwork", time.Second)
+		Expect(errors.Is(err, wait.ErrSSHUnreachable)).To(BeTrue())
+	})
+
+	It("should fail, without skipping, when a connected port stays silent", func() {
+		_, port := listen()
+
+		err := wait.WaitForSSH(ctx, "127.0.0.1", port, "virtwork", 500*time.Millisecond)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("reading SSH banner"))
+		Expect(errors.Is(err, wait.ErrSSHUnreachable)).To(BeFalse())
+	})
+
+	It("should return the context error when cancelled", func() {
+		port := closedPort()
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := wait.WaitForSSH(cctx, "127.0.0.1", port, "virtwork", 5*time.Second)
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+	})
+})
@@ -5,62 +5,184 @@ package wait
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/opdev/virtwork/internal/constants"
 )
 
+// ErrVMFailed is wrapped by the error a wait returns when the VMI reaches
+// the Failed or Unknown phase, from which it does not become ready on its own.
+var ErrVMFailed = errors.New("VMI failed")
+
+// Result is the outcome of waiting for one VM.
+type Result struct {
+	// Err is nil when the VM became ready.
+	Err error
+	// Elapsed is how long the wait took, until the VM was ready or the wait
+	// gave up.
+	Elapsed time.Duration
+	// LastPhase is the VMI phase last observed, or "" if no VMI was found.
+	LastPhase kubevirtv1.VirtualMachineInstancePhase
+}
+
 // WaitForVMReady polls the VMI phase until it reaches Running or the timeout
 // expires. It uses time.Sleep for polling intervals and respects context
-// cancellation.
+// cancellation. A VMI in the Failed or Unknown phase fails the wait at once
+// with an error wrapping ErrVMFailed.
 func WaitForVMReady(ctx context.Context, c client.Client, name, namespace string, timeout, interval time.Duration) error {
+	_, err := pollVMI(ctx, c, name, namespace, timeout, interval, nil, readinessCheck(constants.ReadinessPhase))
+	return err
+}
+
+// WaitForVMReadyWithAgent polls until the VMI is Running and its guest agent
+// has connected, which happens only once the guest has booted far enough to
+// start qemu-guest-agent. With requireReady it also waits for the VMI's Ready
+// condition. It fails on timeout or context cancellation like WaitForVMReady.
+func WaitForVMReadyWithAgent(ctx context.Context, c client.Client, name, namespace string, requireReady bool, timeout, interval time.Duration) error {
+	level := constants.ReadinessAgent
+	if requireReady {
+		level = constants.ReadinessReady
+	}
+	_, err := pollVMI(ctx, c, name, namespace, timeout, interval, nil, readinessCheck(level))
+	return err
+}
+
+// readinessCheck returns the test a VMI must pass at the given readiness
+// level. Unknown levels fall back to the Running phase.
+func readinessCheck(level string) func(*kubevirtv1.VirtualMachineInstance) bool {
+	return func(vmi *kubevirtv1.VirtualMachineInstance) bool {
+		if vmi.Status.Phase != kubevirtv1.Running {
+			return false
+		}
+		switch level {
+		case constants.ReadinessAgent:
+			return hasCondition(vmi, kubevirtv1.VirtualMachineInstanceAgentConnected)
+		case constants.ReadinessReady:
+			return hasCondition(vmi, kubevirtv1.VirtualMachineInstanceAgentConnected) &&
+				hasCondition(vmi, kubevirtv1.VirtualMachineInstanceReady)
+		}
+		return true
+	}
+}
+
+// pollVMI gets the VMI every interval until ready reports true, the VMI
+// fails, the timeout expires, or ctx is cancelled. A VMI that does not exist
+// yet is retried, and each retry is logged at debug level to logger when it
+// is non-nil. It returns the last phase observed along with the outcome.
+func pollVMI(ctx context.Context, c client.Client, name, namespace string, timeout, interval time.Duration, logger *slog.Logger, ready func(*kubevirtv1.VirtualMachineInstance) bool) (kubevirtv1.VirtualMachineInstancePhase, error) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
 	deadline := time.Now().Add(timeout)
+	var phase kubevirtv1.VirtualMachineInstancePhase
 
 	for {
 		if err := ctx.Err(); err != nil {
-			return fmt.Errorf("context cancelled waiting for VM %s/%s: %w", namespace, name, err)
+			return phase, fmt.Errorf("context cancelled waiting for VM %s/%s: %w", namespace, name, err)
 		}
 
 		if time.Now().After(deadline) {
-			return fmt.Errorf("timed out waiting for VM %s/%s to become ready", namespace, name)
+			return phase, fmt.Errorf("timed out waiting for VM %s/%s to become ready", namespace, name)
 		}
 
 		vmi := &kubevirtv1.VirtualMachineInstance{}
 		key := client.ObjectKey{Name: name, Namespace: namespace}
 		if err := c.Get(ctx, key, vmi); err != nil {
 			if !apierrors.IsNotFound(err) {
-				return fmt.Errorf("getting VMI %s/%s: %w", namespace, name, err)
+				return phase, fmt.Errorf("getting VMI %s/%s: %w", namespace, name, err)
 			}
-			fmt.Printf("VM %s: VMI not yet created, retrying...\n", name)
+			logger.Debug(fmt.Sprintf("VM %s: VMI not yet created, retrying...", name),
+				"vm_name", name, "namespace", namespace)
 			select {
 			case <-ctx.Done():
-				return fmt.Errorf("context cancelled waiting for VM %s/%s: %w", namespace, name, ctx.Err())
+				return phase, fmt.Errorf("context cancelled waiting for VM %s/%s: %w", namespace, name, ctx.Err())
 			case <-time.After(interval):
 			}
 			continue
 		}
 
-		if vmi.Status.Phase == kubevirtv1.Running {
-			return nil
+		phase = vmi.Status.Phase
+		if ready(vmi) {
+			return phase, nil
+		}
+		if phase == kubevirtv1.Failed || phase == kubevirtv1.Unknown {
+			return phase, fmt.Errorf("VM %s/%s entered phase %s: %w", namespace, name, phase, ErrVMFailed)
 		}
 
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("context cancelled waiting for VM %s/%s: %w", namespace, name, ctx.Err())
+			return phase, fmt.Errorf("context cancelled waiting for VM %s/%s: %w", namespace, name, ctx.Err())
 		case <-time.After(interval):
 		}
 	}
 }
 
+// hasCondition reports whether the VMI has the condition with status True.
+func hasCondition(vmi *kubevirtv1.VirtualMachineInstance, condType kubevirtv1.VirtualMachineInstanceConditionType) bool {
+	for _, cond := range vmi.Status.Conditions {
+		if cond.Type == condType {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 // WaitForAllVMsReady polls all named VMs concurrently using goroutines.
 // Returns a map of VM name to error (nil if ready). Each VM is polled
 // independently — a failure for one does not cancel others.
 func WaitForAllVMsReady(ctx context.Context, c client.Client, names []string, namespace string, timeout, interval time.Duration) map[string]error {
-	results := make(map[string]error, len(names))
+	results := WaitForAllVMsReadyAtLevel(ctx, c, names, namespace, constants.ReadinessPhase, timeout, interval)
+	errs := make(map[string]error, len(results))
+	for name, r := range results {
+		errs[name] = r.Err
+	}
+	return errs
+}
+
+// WaitForAllVMsReadyAtLevel is WaitForAllVMsReady with the readiness
+// criterion selected by level: constants.ReadinessPhase waits for the Running
+// phase, constants.ReadinessAgent also for the guest agent, and
+// constants.ReadinessReady also for the Ready condition. Each VM's Result
+// also records how long it took and the phase it was last seen in.
+func WaitForAllVMsReadyAtLevel(ctx context.Context, c client.Client, names []string, namespace, level string, timeout, interval time.Duration) map[string]Result {
+	return WaitForAllVMsReadyAtLevelFunc(ctx, c, names, namespace, level, timeout, interval, nil, nil)
+}
+
+// WaitForAllVMsReadyAtLevelFunc is WaitForAllVMsReadyAtLevel that also calls
+// done, when non-nil, with each VM's Result as soon as its wait ends, so a
+// caller can report progress before every VM is done. Calls to done are
+// serialized. A VMI not yet created is logged at debug level to logger,
+// which may be nil.
+func WaitForAllVMsReadyAtLevelFunc(ctx context.Context, c client.Client, names []string, namespace, level string, timeout, interval time.Duration, logger *slog.Logger, done func(name string, r Result)) map[string]Result {
+	return waitForAll(ctx, c, names, namespace, level, timeout, interval, false, logger, done)
+}
+
+// WaitForAllVMsReadyFailFast is WaitForAllVMsReadyAtLevelFunc except that the
+// first VM to fail — by entering the Failed or Unknown phase, timing out, or
+// erroring — cancels the waits for all the others, so it returns as soon as
+// the outcome is known to be a failure. Every VM still has a Result; those
+// whose wait was cut short carry an error wrapping context.Canceled.
+func WaitForAllVMsReadyFailFast(ctx context.Context, c client.Client, names []string, namespace, level string, timeout, interval time.Duration, logger *slog.Logger, done func(name string, r Result)) map[string]Result {
+	return waitForAll(ctx, c, names, namespace, level, timeout, interval, true, logger, done)
+}
+
+// waitForAll polls every named VM in its own goroutine. With failFast the
+// goroutines share a context that the first failure cancels.
+func waitForAll(ctx context.Context, c client.Client, names []string, namespace, level string, timeout, interval time.Duration, failFast bool, logger *slog.Logger, done func(name string, r Result)) map[string]Result {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ready := readinessCheck(level)
+	results := make(map[string]Result, len(names))
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
@@ -68,9 +190,75 @@ func WaitForAllVMsReady(ctx context.Context, c client.Client, names []string, na
 		wg.Add(1)
 		go func(vmName string) {
 			defer wg.Done()
-			err := WaitForVMReady(ctx, c, vmName, namespace, timeout, interval)
+			start := time.Now()
+			phase, err := pollVMI(ctx, c, vmName, namespace, timeout, interval, logger, ready)
+			r := Result{Err: err, Elapsed: time.Since(start), LastPhase: phase}
+			if err != nil && failFast {
+				cancel()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results[vmName] = r
+			if done != nil {
+				done(vmName, r)
+			}
+		}(name)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// WaitForMigration polls a VirtualMachineInstanceMigration until it succeeds,
+// fails, or the timeout expires. A failed migration is an error. It returns
+// the last phase observed along with the outcome.
+func WaitForMigration(ctx context.Context, c client.Client, name, namespace string, timeout, interval time.Duration) (kubevirtv1.VirtualMachineInstanceMigrationPhase, error) {
+	deadline := time.Now().Add(timeout)
+	var phase kubevirtv1.VirtualMachineInstanceMigrationPhase
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return phase, fmt.Errorf("context cancelled waiting for migration %s/%s: %w", namespace, name, err)
+		}
+		if time.Now().After(deadline) {
+			return phase, fmt.Errorf("timed out waiting for migration %s/%s to complete", namespace, name)
+		}
+
+		m := &kubevirtv1.VirtualMachineInstanceMigration{}
+		if err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, m); err != nil {
+			return phase, fmt.Errorf("getting migration %s/%s: %w", namespace, name, err)
+		}
+		phase = m.Status.Phase
+		switch phase {
+		case kubevirtv1.MigrationSucceeded:
+			return phase, nil
+		case kubevirtv1.MigrationFailed:
+			return phase, fmt.Errorf("migration %s/%s failed", namespace, name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return phase, fmt.Errorf("context cancelled waiting for migration %s/%s: %w", namespace, name, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// WaitForAllMigrations waits for the named migrations concurrently and
+// returns a map of migration name to error (nil if it succeeded). Each
+// migration is polled independently.
+func WaitForAllMigrations(ctx context.Context, c client.Client, names []string, namespace string, timeout, interval time.Duration) map[string]error {
+	results := make(map[string]error, len(names))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, name := range names {
+		wg.Add(1)
+		go func(migrationName string) {
+			defer wg.Done()
+			_, err := WaitForMigration(ctx, c, migrationName, namespace, timeout, interval)
 			mu.Lock()
-			results[vmName] = err
+			results[migrationName] = err
 			mu.Unlock()
 		}(name)
 	}
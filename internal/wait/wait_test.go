@@ -4,13 +4,20 @@
 package wait_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
 	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/constants"
 	"github.com/opdev/virtwork/internal/wait"
 )
 
@@ -135,6 +143,59 @@ var _ = Describe("WaitForVMReady", func() {
 		Expect(atomic.LoadInt32(&callCount)).To(BeNumerically(">=", int32(3)))
 	})
 
+	It("should fail fast when the VMI fails before it is ready", func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "failing-vm",
+				Namespace: "default",
+			},
+			Status: kubevirtv1.VirtualMachineInstanceStatus{
+				Phase: kubevirtv1.Scheduling,
+			},
+		}
+
+		var callCount int32
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(vmi).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if err := cl.Get(ctx, key, obj, opts...); err != nil {
+						return err
+					}
+					if vmiObj, ok := obj.(*kubevirtv1.VirtualMachineInstance); ok && atomic.AddInt32(&callCount, 1) >= 2 {
+						vmiObj.Status.Phase = kubevirtv1.Failed
+					}
+					return nil
+				},
+			}).
+			Build()
+
+		start := time.Now()
+		err := wait.WaitForVMReady(ctx, c, "failing-vm", "default", 5*time.Second, 10*time.Millisecond)
+		Expect(err).To(MatchError(wait.ErrVMFailed))
+		Expect(err.Error()).To(ContainSubstring("VM default/failing-vm entered phase Failed"))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(atomic.LoadInt32(&callCount)).To(Equal(int32(2)))
+	})
+
+	It("should fail fast when the VMI phase is Unknown", func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "lost-vm",
+				Namespace: "default",
+			},
+			Status: kubevirtv1.VirtualMachineInstanceStatus{
+				Phase: kubevirtv1.Unknown,
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmi).Build()
+
+		err := wait.WaitForVMReadyWithAgent(ctx, c, "lost-vm", "default", true, 5*time.Second, 10*time.Millisecond)
+		Expect(err).To(MatchError(wait.ErrVMFailed))
+		Expect(err.Error()).To(ContainSubstring("entered phase Unknown"))
+	})
+
 	It("should respect context cancellation", func() {
 		vmi := &kubevirtv1.VirtualMachineInstance{
 			ObjectMeta: metav1.ObjectMeta{
@@ -155,6 +216,123 @@ var _ = Describe("WaitForVMReady", func() {
 	})
 })
 
+var _ = Describe("WaitForVMReadyWithAgent", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	runningVMI := func(name string, conds ...kubevirtv1.VirtualMachineInstanceConditionType) *kubevirtv1.VirtualMachineInstance {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: kubevirtv1.Running},
+		}
+		for _, t := range conds {
+			vmi.Status.Conditions = append(vmi.Status.Conditions, kubevirtv1.VirtualMachineInstanceCondition{
+				Type:   t,
+				Status: corev1.ConditionTrue,
+			})
+		}
+		return vmi
+	}
+
+	// flipAfter returns a client whose Gets add conds to the VMI from the
+	// nth call on, counting calls in count.
+	flipAfter := func(vmi *kubevirtv1.VirtualMachineInstance, n int32, count *int32, conds ...kubevirtv1.VirtualMachineInstanceConditionType) client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(vmi).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if err := cl.Get(ctx, key, obj, opts...); err != nil {
+						return err
+					}
+					vmiObj, ok := obj.(*kubevirtv1.VirtualMachineInstance)
+					if ok && atomic.AddInt32(count, 1) >= n {
+						for _, t := range conds {
+							vmiObj.Status.Conditions = append(vmiObj.Status.Conditions, kubevirtv1.VirtualMachineInstanceCondition{
+								Type:   t,
+								Status: corev1.ConditionTrue,
+							})
+						}
+					}
+					return nil
+				},
+			}).
+			Build()
+	}
+
+	It("should return nil when the agent is already connected", func() {
+		vmi := runningVMI("agent-vm", kubevirtv1.VirtualMachineInstanceAgentConnected)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmi).Build()
+
+		err := wait.WaitForVMReadyWithAgent(ctx, c, "agent-vm", "default", false, 5*time.Second, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should wait for the agent to connect after the VMI is running", func() {
+		var callCount int32
+		c := flipAfter(runningVMI("slow-agent-vm"), 3, &callCount, kubevirtv1.VirtualMachineInstanceAgentConnected)
+
+		err := wait.WaitForVMReadyWithAgent(ctx, c, "slow-agent-vm", "default", false, 5*time.Second, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(atomic.LoadInt32(&callCount)).To(BeNumerically(">=", int32(3)))
+	})
+
+	It("should not accept an agent condition that is not true", func() {
+		vmi := runningVMI("false-agent-vm")
+		vmi.Status.Conditions = []kubevirtv1.VirtualMachineInstanceCondition{{
+			Type:   kubevirtv1.VirtualMachineInstanceAgentConnected,
+			Status: corev1.ConditionFalse,
+		}}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmi).Build()
+
+		err := wait.WaitForVMReadyWithAgent(ctx, c, "false-agent-vm", "default", false, 50*time.Millisecond, 10*time.Millisecond)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("timed out"))
+	})
+
+	It("should time out when the agent never connects", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(runningVMI("no-agent-vm")).Build()
+
+		err := wait.WaitForVMReadyWithAgent(ctx, c, "no-agent-vm", "default", false, 50*time.Millisecond, 10*time.Millisecond)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("timed out"))
+	})
+
+	It("should also wait for the Ready condition when required", func() {
+		var callCount int32
+		vmi := runningVMI("ready-vm", kubevirtv1.VirtualMachineInstanceAgentConnected)
+		c := flipAfter(vmi, 4, &callCount, kubevirtv1.VirtualMachineInstanceReady)
+
+		err := wait.WaitForVMReadyWithAgent(ctx, c, "ready-vm", "default", true, 5*time.Second, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(atomic.LoadInt32(&callCount)).To(BeNumerically(">=", int32(4)))
+	})
+
+	It("should time out when Ready is required but never set", func() {
+		vmi := runningVMI("not-ready-vm", kubevirtv1.VirtualMachineInstanceAgentConnected)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmi).Build()
+
+		err := wait.WaitForVMReadyWithAgent(ctx, c, "not-ready-vm", "default", true, 50*time.Millisecond, 10*time.Millisecond)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("timed out"))
+	})
+
+	It("should not accept conditions before the VMI is running", func() {
+		vmi := runningVMI("scheduling-vm", kubevirtv1.VirtualMachineInstanceAgentConnected)
+		vmi.Status.Phase = kubevirtv1.Scheduling
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmi).Build()
+
+		err := wait.WaitForVMReadyWithAgent(ctx, c, "scheduling-vm", "default", false, 50*time.Millisecond, 10*time.Millisecond)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("WaitForAllVMsReady", func() {
 	var (
 		ctx    context.Context
@@ -211,10 +389,262 @@ var _ = Describe("WaitForAllVMsReady", func() {
 		Expect(results["bad-vm"]).To(HaveOccurred())
 	})
 
+	It("should record the failed phase of a VM that fails", func() {
+		vmi := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "failed-vm",
+				Namespace: "default",
+			},
+			Status: kubevirtv1.VirtualMachineInstanceStatus{
+				Phase: kubevirtv1.Failed,
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmi).Build()
+
+		results := wait.WaitForAllVMsReadyAtLevel(ctx, c, []string{"failed-vm"}, "default",
+			constants.ReadinessPhase, 5*time.Second, 10*time.Millisecond)
+		Expect(results["failed-vm"].Err).To(MatchError(wait.ErrVMFailed))
+		Expect(results["failed-vm"].LastPhase).To(Equal(kubevirtv1.Failed))
+	})
+
 	It("should handle empty names list", func() {
 		c := fake.NewClientBuilder().WithScheme(scheme).Build()
 
 		results := wait.WaitForAllVMsReady(ctx, c, []string{}, "default", 5*time.Second, 10*time.Millisecond)
 		Expect(results).To(BeEmpty())
 	})
+
+	It("should apply the readiness level to every VM", func() {
+		running := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "running-vm", Namespace: "default"},
+			Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: kubevirtv1.Running},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(running).Build()
+
+		results := wait.WaitForAllVMsReadyAtLevel(ctx, c, []string{"running-vm"}, "default",
+			constants.ReadinessPhase, 50*time.Millisecond, 10*time.Millisecond)
+		Expect(results["running-vm"].Err).NotTo(HaveOccurred())
+
+		results = wait.WaitForAllVMsReadyAtLevel(ctx, c, []string{"running-vm"}, "default",
+			constants.ReadinessAgent, 50*time.Millisecond, 10*time.Millisecond)
+		Expect(results["running-vm"].Err).To(HaveOccurred())
+	})
+
+	It("should record the last phase and elapsed time of each VM", func() {
+		running := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "running-vm", Namespace: "default"},
+			Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: kubevirtv1.Running},
+		}
+		stuck := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "stuck-vm", Namespace: "default"},
+			Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: kubevirtv1.Scheduling},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(running, stuck).Build()
+
+		results := wait.WaitForAllVMsReadyAtLevel(ctx, c, []string{"running-vm", "stuck-vm", "missing-vm"}, "default",
+			constants.ReadinessPhase, 50*time.Millisecond, 10*time.Millisecond)
+		Expect(results).To(HaveLen(3))
+
+		Expect(results["running-vm"].Err).NotTo(HaveOccurred())
+		Expect(results["running-vm"].LastPhase).To(Equal(kubevirtv1.Running))
+
+		Expect(results["stuck-vm"].Err).To(HaveOccurred())
+		Expect(results["stuck-vm"].LastPhase).To(Equal(kubevirtv1.Scheduling))
+		Expect(results["stuck-vm"].Elapsed).To(BeNumerically(">=", 50*time.Millisecond))
+
+		Expect(results["missing-vm"].Err).To(HaveOccurred())
+		Expect(results["missing-vm"].LastPhase).To(BeEmpty())
+	})
+
+	It("should call back once per VM as each wait ends", func() {
+		running := &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "running-vm", Namespace: "default"},
+			Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: kubevirtv1.Running},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(running).Build()
+
+		done := make(map[string]error)
+		results := wait.WaitForAllVMsReadyAtLevelFunc(ctx, c, []string{"running-vm", "missing-vm"}, "default",
+			constants.ReadinessPhase, 50*time.Millisecond, 10*time.Millisecond, nil,
+			func(name string, r wait.Result) { done[name] = r.Err })
+		Expect(results).To(HaveLen(2))
+		Expect(done).To(HaveLen(2))
+		Expect(done["running-vm"]).NotTo(HaveOccurred())
+		Expect(done["missing-vm"]).To(HaveOccurred())
+	})
+
+	It("should not write to stdout while a VMI is not yet created", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		r, w, err := os.Pipe()
+		Expect(err).NotTo(HaveOccurred())
+		stdout := os.Stdout
+		os.Stdout = w
+		wait.WaitForAllVMsReadyAtLevelFunc(ctx, c, []string{"missing-vm"}, "default",
+			constants.ReadinessPhase, 30*time.Millisecond, 10*time.Millisecond, nil, nil)
+		os.Stdout = stdout
+		Expect(w.Close()).To(Succeed())
+
+		out, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(BeEmpty())
+	})
+
+	It("should log a VMI not yet created to the logger with its VM name", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		wait.WaitForAllVMsReadyAtLevelFunc(ctx, c, []string{"missing-vm"}, "default",
+			constants.ReadinessPhase, 30*time.Millisecond, 10*time.Millisecond, logger, nil)
+
+		line, _, _ := bytes.Cut(buf.Bytes(), []byte("\n"))
+		var record map[string]any
+		Expect(json.Unmarshal(line, &record)).To(Succeed())
+		Expect(record).To(HaveKeyWithValue("level", "DEBUG"))
+		Expect(record).To(HaveKeyWithValue("vm_name", "missing-vm"))
+		Expect(record).To(HaveKeyWithValue("namespace", "default"))
+	})
+})
+
+var _ = Describe("WaitForAllVMsReadyFailFast", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	vmi := func(name string, phase kubevirtv1.VirtualMachineInstancePhase) *kubevirtv1.VirtualMachineInstance {
+		return &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: phase},
+		}
+	}
+
+	It("should cancel the other waits once one VM fails", func() {
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(vmi("failed-vm", kubevirtv1.Failed), vmi("pending-vm", kubevirtv1.Pending)).
+			Build()
+
+		start := time.Now()
+		results := wait.WaitForAllVMsReadyFailFast(ctx, c, []string{"failed-vm", "pending-vm", "missing-vm"}, "default",
+			constants.ReadinessPhase, 10*time.Second, 10*time.Millisecond, nil, nil)
+		Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+
+		Expect(results).To(HaveLen(3))
+		Expect(results["failed-vm"].Err).To(MatchError(wait.ErrVMFailed))
+		Expect(results["pending-vm"].Err).To(MatchError(context.Canceled))
+		Expect(results["missing-vm"].Err).To(MatchError(context.Canceled))
+	})
+
+	It("should cancel the other waits once getting a VMI errors", func() {
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(vmi("pending-vm", kubevirtv1.Pending)).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if key.Name == "broken-vm" {
+						return errors.New("connection refused")
+					}
+					return cl.Get(ctx, key, obj, opts...)
+				},
+			}).
+			Build()
+
+		start := time.Now()
+		results := wait.WaitForAllVMsReadyFailFast(ctx, c, []string{"broken-vm", "pending-vm"}, "default",
+			constants.ReadinessPhase, 10*time.Second, 10*time.Millisecond, nil, nil)
+		Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+		Expect(results["broken-vm"].Err).To(MatchError(ContainSubstring("connection refused")))
+		Expect(results["pending-vm"].Err).To(MatchError(context.Canceled))
+	})
+
+	It("should wait for every VM when none fails", func() {
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(vmi("vm-1", kubevirtv1.Running), vmi("vm-2", kubevirtv1.Running)).
+			Build()
+
+		var calls int32
+		results := wait.WaitForAllVMsReadyFailFast(ctx, c, []string{"vm-1", "vm-2"}, "default",
+			constants.ReadinessPhase, 5*time.Second, 10*time.Millisecond, nil,
+			func(string, wait.Result) { atomic.AddInt32(&calls, 1) })
+		Expect(results).To(HaveLen(2))
+		Expect(results["vm-1"].Err).NotTo(HaveOccurred())
+		Expect(results["vm-2"].Err).NotTo(HaveOccurred())
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+	})
+})
+
+var _ = Describe("WaitForMigration", func() {
+	var (
+		ctx    context.Context
+		scheme = cluster.NewScheme()
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	migration := func(name string, phase kubevirtv1.VirtualMachineInstanceMigrationPhase) *kubevirtv1.VirtualMachineInstanceMigration {
+		return &kubevirtv1.VirtualMachineInstanceMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     kubevirtv1.VirtualMachineInstanceMigrationStatus{Phase: phase},
+		}
+	}
+
+	It("should return once the migration succeeds", func() {
+		var callCount int32
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(migration("m-1", kubevirtv1.MigrationScheduling)).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if err := cl.Get(ctx, key, obj, opts...); err != nil {
+						return err
+					}
+					if m, ok := obj.(*kubevirtv1.VirtualMachineInstanceMigration); ok && atomic.AddInt32(&callCount, 1) >= 3 {
+						m.Status.Phase = kubevirtv1.MigrationSucceeded
+					}
+					return nil
+				},
+			}).
+			Build()
+
+		phase, err := wait.WaitForMigration(ctx, c, "m-1", "default", 5*time.Second, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(kubevirtv1.MigrationSucceeded))
+		Expect(atomic.LoadInt32(&callCount)).To(BeNumerically(">=", 3))
+	})
+
+	It("should fail when the migration fails", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(migration("m-1", kubevirtv1.MigrationFailed)).Build()
+
+		phase, err := wait.WaitForMigration(ctx, c, "m-1", "default", 5*time.Second, 10*time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("migration default/m-1 failed")))
+		Expect(phase).To(Equal(kubevirtv1.MigrationFailed))
+	})
+
+	It("should time out while the migration is running", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(migration("m-1", kubevirtv1.MigrationRunning)).Build()
+
+		_, err := wait.WaitForMigration(ctx, c, "m-1", "default", 50*time.Millisecond, 10*time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("timed out waiting for migration")))
+	})
+
+	It("should report each migration's outcome", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			migration("m-ok", kubevirtv1.MigrationSucceeded),
+			migration("m-bad", kubevirtv1.MigrationFailed),
+		).Build()
+
+		results := wait.WaitForAllMigrations(ctx, c, []string{"m-ok", "m-bad"}, "default", 5*time.Second, 10*time.Millisecond)
+		Expect(results).To(HaveLen(2))
+		Expect(results["m-ok"]).To(Succeed())
+		Expect(results["m-bad"]).To(HaveOccurred())
+	})
 })
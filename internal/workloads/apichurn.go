@@ -0,0 +1,133 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+// apiChurnServiceAccount names the ServiceAccount, Role, and RoleBinding
+// the api-churn workload's VMs call the API with.
+const apiChurnServiceAccount = "virtwork-api-churn"
+
+// apiChurnScriptPath is where the churn loop is installed in the guest.
+const apiChurnScriptPath = "/usr/local/bin/virtwork-api-churn.sh"
+
+// apiChurnScript mounts the ServiceAccount disk and then creates, reads,
+// lists, and deletes a ConfigMap in the VM's namespace in a loop. Each VM
+// uses one ConfigMap named after its hostname, labeled so that cleanup
+// removes it if the VM is deleted mid-cycle.
+const apiChurnScript = `#!/bin/bash
+set -uo pipefail
+
+SA_DIR="/run/virtwork-sa"
+if ! mountpoint -q "${SA_DIR}"; then
+    mkdir -p "${SA_DIR}"
+    mount -o ro /dev/disk/by-id/virtio-` + vm.ServiceAccountDiskSerial + ` "${SA_DIR}"
+fi
+
+NAMESPACE="$(cat "${SA_DIR}/namespace")"
+API="https://kubernetes.default.svc/api/v1/namespaces/${NAMESPACE}/configmaps"
+NAME="virtwork-churn-$(hostname -s)"
+LABELS='{"app.kubernetes.io/managed-by":"virtwork","app.kubernetes.io/component":"api-churn"}'
+
+api() {
+    curl -sS -o /dev/null -w '%{http_code} %{time_total}s\n' \
+        --cacert "${SA_DIR}/ca.crt" \
+        -H "Authorization: Bearer $(cat "${SA_DIR}/token")" \
+        -H "Content-Type: application/json" "$@"
+}
+
+while true; do
+    api -X POST "${API}" \
+        -d "{\"metadata\":{\"name\":\"${NAME}\",\"labels\":${LABELS}},\"data\":{\"ts\":\"$(date +%s)\"}}"
+    api "${API}/${NAME}"
+    api "${API}?labelSelector=app.kubernetes.io%2Fcomponent%3Dapi-churn"
+    api -X DELETE "${API}/${NAME}"
+    sleep 1
+done
+`
+
+// APIChurnWorkload generates cloud-init userdata for a control-plane load
+// workload. Each VM calls the Kubernetes API with a ServiceAccount token
+// attached as a disk, creating, reading, listing, and deleting a ConfigMap in
+// its namespace in a continuous loop.
+type APIChurnWorkload struct {
+	BaseWorkload
+}
+
+// NewAPIChurnWorkload creates an APIChurnWorkload with the given
+// configuration and SSH credentials.
+func NewAPIChurnWorkload(cfg config.WorkloadConfig, sshUser, sshPassword string, sshKeys []string) *APIChurnWorkload {
+	return &APIChurnWorkload{
+		BaseWorkload: BaseWorkload{
+			Config:            cfg,
+			SSHUser:           sshUser,
+			SSHPassword:       sshPassword,
+			SSHAuthorizedKeys: sshKeys,
+		},
+	}
+}
+
+// Name returns "api-churn".
+func (w *APIChurnWorkload) Name() string {
+	return "api-churn"
+}
+
+// RequiredPackages returns curl, which the guest calls the API server with.
+func (w *APIChurnWorkload) RequiredPackages() []string {
+	return []string{"curl"}
+}
+
+// CloudInitUserdata returns cloud-init YAML that installs the churn script and
+// runs it via systemd.
+func (w *APIChurnWorkload) CloudInitUserdata() (string, error) {
+	unit := serviceUnit{
+		Name:        "api-churn",
+		Description: "Virtwork Kubernetes API churn workload",
+		After:       []string{"network-online.target"},
+		ExecStart:   apiChurnScriptPath,
+		Warmup:      w.Warmup,
+		StartDelay:  w.startDelay,
+		RestartSec:  w.restartSeconds(),
+	}
+	files := []WriteFile{
+		{
+			Path:        apiChurnScriptPath,
+			Content:     apiChurnScript,
+			Permissions: "0755",
+		},
+	}
+	return w.BuildCloudConfig(CloudConfigOpts{
+		Packages:   w.RequiredPackages(),
+		WriteFiles: append(files, unit.writeFiles()...),
+		RunCmd: [][]string{
+			{"systemctl", "daemon-reload"},
+			{"systemctl", "enable", "--now", unit.serviceName()},
+		},
+	})
+}
+
+// ServiceAccountName returns the ServiceAccount the VMs call the API as.
+func (w *APIChurnWorkload) ServiceAccountName() string {
+	return apiChurnServiceAccount
+}
+
+// PolicyRules grants the ConfigMap verbs the churn loop uses.
+func (w *APIChurnWorkload) PolicyRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{{
+		APIGroups: []string{""},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"create", "get", "list", "delete"},
+	}}
+}
+
+// ReadinessProbe checks that the churn service is active.
+func (w *APIChurnWorkload) ReadinessProbe(string) *kubevirtv1.Probe {
+	return serviceUnit{Name: "api-churn"}.readinessProbe()
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+var _ = Describe("APIChurnWorkload", func() {
+	var w *workloads.APIChurnWorkload
+
+	BeforeEach(func() {
+		w = workloads.NewAPIChurnWorkload(config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 1,
+			Memory:   "1Gi",
+		}, "virtwork", "", nil)
+	})
+
+	It("should return 'api-churn' for Name", func() {
+		Expect(w.Name()).To(Equal("api-churn"))
+	})
+
+	It("should include curl in packages", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		pkgs, ok := parsed["packages"].([]interface{})
+		Expect(ok).To(BeTrue())
+		Expect(pkgs).To(ContainElement("curl"))
+	})
+
+	It("should mount the service account disk by its serial", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		script := fileContent(parseYAML(result), "/usr/local/bin/virtwork-api-churn.sh")
+		Expect(script).To(ContainSubstring("/dev/disk/by-id/virtio-virtwork-sa"))
+		Expect(script).To(ContainSubstring(`${SA_DIR}/token`))
+		Expect(script).To(ContainSubstring(`${SA_DIR}/ca.crt`))
+	})
+
+	It("should create, read, list, and delete a labeled ConfigMap in a loop", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		script := fileContent(parseYAML(result), "/usr/local/bin/virtwork-api-churn.sh")
+		Expect(script).To(ContainSubstring("https://kubernetes.default.svc/api/v1/namespaces/${NAMESPACE}/configmaps"))
+		Expect(script).To(ContainSubstring("-X POST"))
+		Expect(script).To(ContainSubstring("-X DELETE"))
+		Expect(script).To(ContainSubstring(`"app.kubernetes.io/managed-by":"virtwork"`))
+		Expect(script).To(ContainSubstring(`"app.kubernetes.io/component":"api-churn"`))
+		Expect(script).To(ContainSubstring("while true"))
+	})
+
+	It("should run the churn script from the systemd service", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		service := fileContent(parsed, "/etc/systemd/system/virtwork-api-churn.service")
+		Expect(service).To(ContainSubstring("ExecStart=/usr/local/bin/virtwork-api-churn.sh"))
+
+		runcmd := parsed["runcmd"].([]interface{})
+		Expect(runcmd).To(ContainElement([]interface{}{"systemctl", "enable", "--now", "virtwork-api-churn.service"}))
+	})
+
+	It("should request a service account limited to ConfigMaps", func() {
+		var a workloads.APIClient = w
+		Expect(a.ServiceAccountName()).To(Equal("virtwork-api-churn"))
+		Expect(a.PolicyRules()).To(Equal([]rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     []string{"create", "get", "list", "delete"},
+		}}))
+	})
+
+	It("should provide a readiness probe checking the churn service", func() {
+		var p workloads.Prober = w
+		probe := p.ReadinessProbe("")
+		Expect(probe).NotTo(BeNil())
+		Expect(probe.Exec.Command).To(Equal([]string{"systemctl", "is-active", "--quiet", "virtwork-api-churn.service"}))
+	})
+
+	It("should not require a service", func() {
+		Expect(w.RequiresService()).To(BeFalse())
+		Expect(w.ServiceSpec()).To(BeNil())
+	})
+})
@@ -4,21 +4,28 @@
 package workloads
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/opdev/virtwork/internal/config"
 )
 
-const cpuSystemdUnit = `[Unit]
-Description=Virtwork CPU stress workload
-After=network.target
+const cpuStressCommand = "/usr/bin/stress-ng --cpu 0 --cpu-method all --timeout 0"
 
-[Service]
-Type=simple
-ExecStart=/usr/bin/stress-ng --cpu 0 --cpu-method all --timeout 0
-Restart=always
-RestartSec=10
+// cpuPinnedScriptPath runs the stress-ng workers pinned to the isolated CPUs.
+const cpuPinnedScriptPath = "/usr/local/bin/virtwork-cpu-pinned.sh"
 
-[Install]
-WantedBy=multi-user.target
+// cpuPinnedScript starts one single-worker stress-ng per isolated CPU, pinned
+// with taskset. The scheduler does not balance tasks across isolcpus CPUs, so
+// a single stress-ng allowed on all of them would crowd onto one.
+const cpuPinnedScript = `#!/bin/bash
+# Run one stress-ng worker pinned to each isolated CPU.
+trap 'kill $(jobs -p) 2>/dev/null; exit' TERM INT
+for cpu in %s; do
+    /usr/bin/taskset -c "${cpu}" /usr/bin/stress-ng --cpu 1 --cpu-method all --timeout 0 &
+done
+wait
 `
 
 // CPUWorkload generates cloud-init userdata for a continuous CPU stress workload
@@ -44,21 +51,68 @@ func (w *CPUWorkload) Name() string {
 	return "cpu"
 }
 
+// RequiredPackages returns the stress-ng package.
+func (w *CPUWorkload) RequiredPackages() []string {
+	return []string{"stress-ng"}
+}
+
 // CloudInitUserdata returns cloud-init YAML that installs stress-ng and runs a
 // continuous CPU stress workload via systemd.
+//
+// With IsolatedCPUs set in the workload config, cloud-init adds isolcpus,
+// nohz_full, and rcu_nocbs for those CPUs to the guest kernel command line
+// and reboots once it is done. The service is only enabled, so it first
+// starts after the reboot, with one stress-ng worker pinned to each isolated
+// CPU.
 func (w *CPUWorkload) CloudInitUserdata() (string, error) {
-	return w.BuildCloudConfig(CloudConfigOpts{
-		Packages: []string{"stress-ng"},
-		WriteFiles: []WriteFile{
-			{
-				Path:        "/etc/systemd/system/virtwork-cpu.service",
-				Content:     cpuSystemdUnit,
-				Permissions: "0644",
+	unit := serviceUnit{
+		Name:        "cpu",
+		Description: "Virtwork CPU stress workload",
+		ExecStart:   cpuStressCommand,
+		Warmup:      w.Warmup,
+		StartDelay:  w.startDelay,
+		RestartSec:  w.restartSeconds(),
+	}
+	if w.Config.IsolatedCPUs == "" {
+		return w.BuildCloudConfig(CloudConfigOpts{
+			Packages:   w.RequiredPackages(),
+			WriteFiles: unit.writeFiles(),
+			RunCmd: [][]string{
+				{"systemctl", "daemon-reload"},
+				{"systemctl", "enable", "--now", unit.serviceName()},
 			},
-		},
+		})
+	}
+
+	cpus, err := config.ParseCPUList(w.Config.IsolatedCPUs)
+	if err != nil {
+		return "", err
+	}
+	if last := cpus[len(cpus)-1]; last >= w.Config.CPUCores {
+		return "", fmt.Errorf("isolated-cpus %s includes CPU %d, but the VM has %d cores", w.Config.IsolatedCPUs, last, w.Config.CPUCores)
+	}
+	cpuWords := make([]string, len(cpus))
+	for i, cpu := range cpus {
+		cpuWords[i] = strconv.Itoa(cpu)
+	}
+	unit.ExecStart = cpuPinnedScriptPath
+	kernelArgs := fmt.Sprintf("isolcpus=%[1]s nohz_full=%[1]s rcu_nocbs=%[1]s", w.Config.IsolatedCPUs)
+
+	return w.BuildCloudConfig(CloudConfigOpts{
+		Packages: w.RequiredPackages(),
+		WriteFiles: append([]WriteFile{{
+			Path:        cpuPinnedScriptPath,
+			Content:     fmt.Sprintf(cpuPinnedScript, strings.Join(cpuWords, " ")),
+			Permissions: "0755",
+		}}, unit.writeFiles()...),
 		RunCmd: [][]string{
+			{"grubby", "--update-kernel=ALL", "--args=" + kernelArgs},
 			{"systemctl", "daemon-reload"},
-			{"systemctl", "enable", "--now", "virtwork-cpu.service"},
+			{"systemctl", "enable", unit.serviceName()},
+		},
+		PowerState: &PowerState{
+			Mode:    "reboot",
+			Message: "Rebooting to isolate CPUs " + w.Config.IsolatedCPUs,
 		},
 	})
 }
@@ -56,6 +56,80 @@ var _ = Describe("CPUWorkload", func() {
 		Expect(content).To(ContainSubstring("--timeout 0"))
 	})
 
+	It("should write extra files from the config alongside the service unit", func() {
+		w = workloads.NewCPUWorkload(config.WorkloadConfig{
+			Enabled: true,
+			ExtraWriteFiles: []config.WriteFile{
+				{Path: "/etc/sysctl.d/99-virtwork.conf", Content: "kernel.sched_autogroup_enabled = 0\n"},
+			},
+		}, "virtwork", "", nil)
+
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(parsed["write_files"]).To(HaveLen(2))
+		Expect(fileContent(parsed, "/etc/systemd/system/virtwork-cpu.service")).To(ContainSubstring("stress-ng"))
+		Expect(fileContent(parsed, "/etc/sysctl.d/99-virtwork.conf")).To(Equal("kernel.sched_autogroup_enabled = 0\n"))
+	})
+
+	Context("with isolated CPUs", func() {
+		BeforeEach(func() {
+			w = workloads.NewCPUWorkload(config.WorkloadConfig{
+				Enabled:      true,
+				VMCount:      1,
+				CPUCores:     4,
+				Memory:       "2Gi",
+				IsolatedCPUs: "1-3",
+			}, "virtwork", "", nil)
+		})
+
+		It("should add the isolation kernel arguments and reboot", func() {
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			runcmd := parsed["runcmd"].([]interface{})
+			Expect(runcmd[0]).To(Equal([]interface{}{
+				"grubby", "--update-kernel=ALL", "--args=isolcpus=1-3 nohz_full=1-3 rcu_nocbs=1-3",
+			}))
+			Expect(parsed["power_state"]).To(HaveKeyWithValue("mode", "reboot"))
+		})
+
+		It("should start the service only after the reboot", func() {
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			runcmd := parseYAML(result)["runcmd"].([]interface{})
+			Expect(runcmd).To(ContainElement([]interface{}{"systemctl", "enable", "virtwork-cpu.service"}))
+			Expect(runcmd).NotTo(ContainElement([]interface{}{"systemctl", "enable", "--now", "virtwork-cpu.service"}))
+		})
+
+		It("should pin one stress-ng worker to each isolated CPU", func() {
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			Expect(fileContent(parsed, "/etc/systemd/system/virtwork-cpu.service")).To(
+				ContainSubstring("ExecStart=/usr/local/bin/virtwork-cpu-pinned.sh\n"))
+			script := fileContent(parsed, "/usr/local/bin/virtwork-cpu-pinned.sh")
+			Expect(script).To(ContainSubstring("for cpu in 1 2 3; do"))
+			Expect(script).To(ContainSubstring(`/usr/bin/taskset -c "${cpu}" /usr/bin/stress-ng --cpu 1`))
+		})
+
+		It("should reject CPUs the VM does not have", func() {
+			w.Config.CPUCores = 3
+			_, err := w.CloudInitUserdata()
+			Expect(err).To(MatchError("isolated-cpus 1-3 includes CPU 3, but the VM has 3 cores"))
+		})
+	})
+
+	It("should not reboot without isolated CPUs", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parseYAML(result)).NotTo(HaveKey("power_state"))
+	})
+
 	It("should produce valid YAML", func() {
 		result, err := w.CloudInitUserdata()
 		Expect(err).NotTo(HaveOccurred())
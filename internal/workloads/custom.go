@@ -0,0 +1,92 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/opdev/virtwork/internal/config"
+)
+
+// cloudConfigHeader marks userdata that is already a complete cloud-config.
+const cloudConfigHeader = "#cloud-config"
+
+// CustomWorkload runs user-supplied userdata instead of a built-in workload.
+// The source is either a path to a file or, if it spans several lines, the
+// userdata itself. A complete cloud-config is used verbatim; anything else is
+// treated as a script and run as a systemd service, with the usual SSH
+// credentials injected.
+type CustomWorkload struct {
+	BaseWorkload
+	name   string
+	source string
+}
+
+// NewCustomWorkload creates a CustomWorkload with the given name, userdata
+// source, configuration, and SSH credentials.
+func NewCustomWorkload(name, source string, cfg config.WorkloadConfig, sshUser, sshPassword string, sshKeys []string) *CustomWorkload {
+	return &CustomWorkload{
+		BaseWorkload: BaseWorkload{
+			Config:            cfg,
+			SSHUser:           sshUser,
+			SSHPassword:       sshPassword,
+			SSHAuthorizedKeys: sshKeys,
+		},
+		name:   name,
+		source: source,
+	}
+}
+
+// Name returns the name the workload was registered under.
+func (w *CustomWorkload) Name() string {
+	return w.name
+}
+
+// CloudInitUserdata returns the user's cloud-config unchanged, or cloud-init
+// YAML that installs the user's script and runs it via systemd.
+func (w *CustomWorkload) CloudInitUserdata() (string, error) {
+	content, err := w.content()
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(content, cloudConfigHeader) {
+		return content, nil
+	}
+
+	scriptPath := fmt.Sprintf("/usr/local/bin/virtwork-%s.sh", w.name)
+	unit := serviceUnit{
+		Name:        w.name,
+		Description: fmt.Sprintf("Virtwork custom workload %s", w.name),
+		ExecStart:   scriptPath,
+		Warmup:      w.Warmup,
+		StartDelay:  w.startDelay,
+		RestartSec:  w.restartSeconds(),
+	}
+	files := []WriteFile{{
+		Path:        scriptPath,
+		Content:     content,
+		Permissions: "0755",
+	}}
+	return w.BuildCloudConfig(CloudConfigOpts{
+		WriteFiles: append(files, unit.writeFiles()...),
+		RunCmd: [][]string{
+			{"systemctl", "daemon-reload"},
+			{"systemctl", "enable", "--now", unit.serviceName()},
+		},
+	})
+}
+
+// content returns the inline userdata or reads it from the source path.
+func (w *CustomWorkload) content() (string, error) {
+	if strings.Contains(w.source, "\n") {
+		return w.source, nil
+	}
+	data, err := os.ReadFile(w.source)
+	if err != nil {
+		return "", fmt.Errorf("reading custom userdata for %q: %w", w.name, err)
+	}
+	return string(data), nil
+}
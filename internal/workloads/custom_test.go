@@ -0,0 +1,134 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opdev/virtwork/internal/cluster"
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/resources"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+var _ = Describe("CustomWorkload", func() {
+	const (
+		cloudConfig = "#cloud-config\nruncmd:\n  - [sh, -c, 'echo hello > /tmp/hello']\n"
+		script      = "#!/bin/bash\nwhile true; do dd if=/dev/zero of=/dev/null bs=1M count=1024; done\n"
+	)
+
+	var (
+		dir   string
+		wlCfg config.WorkloadConfig
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		wlCfg = config.WorkloadConfig{Enabled: true, VMCount: 1, CPUCores: 4, Memory: "8Gi"}
+	})
+
+	writeUserdata := func(content string) string {
+		path := filepath.Join(dir, "userdata")
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	It("should use a cloud-config file verbatim", func() {
+		w := workloads.NewCustomWorkload("custom", writeUserdata(cloudConfig), wlCfg, "virtwork", "", nil)
+
+		userdata, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userdata).To(Equal(cloudConfig))
+	})
+
+	It("should store the cloud-config file verbatim in the cloud-init secret", func() {
+		w := workloads.NewCustomWorkload("custom", writeUserdata(cloudConfig), wlCfg, "virtwork", "", nil)
+		userdata, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := context.Background()
+		c := fake.NewClientBuilder().WithScheme(cluster.NewScheme()).Build()
+		Expect(resources.CreateCloudInitSecret(ctx, c, "virtwork-custom-0-cloudinit", "test-ns", userdata, nil)).To(Succeed())
+
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "virtwork-custom-0-cloudinit", Namespace: "test-ns"}, secret)).To(Succeed())
+		Expect(secret.StringData["userdata"]).To(Equal(cloudConfig))
+	})
+
+	It("should install a script and run it as a service with SSH credentials", func() {
+		w := workloads.NewCustomWorkload("custom", writeUserdata(script), wlCfg, "virtwork", "", []string{"ssh-ed25519 AAAA test"})
+
+		userdata, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(userdata)
+		Expect(fileContent(parsed, "/usr/local/bin/virtwork-custom.sh")).To(Equal(script))
+		Expect(fileContent(parsed, "/etc/systemd/system/virtwork-custom.service")).
+			To(ContainSubstring("ExecStart=/usr/local/bin/virtwork-custom.sh"))
+
+		users := parsed["users"].([]interface{})
+		user := users[0].(map[string]interface{})
+		Expect(user["name"]).To(Equal("virtwork"))
+		Expect(user["ssh_authorized_keys"]).To(ContainElement("ssh-ed25519 AAAA test"))
+	})
+
+	It("should accept inline userdata", func() {
+		w := workloads.NewCustomWorkload("custom", cloudConfig, wlCfg, "virtwork", "", nil)
+
+		userdata, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userdata).To(Equal(cloudConfig))
+	})
+
+	It("should return an error for a missing file", func() {
+		w := workloads.NewCustomWorkload("custom", filepath.Join(dir, "missing.sh"), wlCfg, "virtwork", "", nil)
+
+		_, err := w.CloudInitUserdata()
+		Expect(err).To(MatchError(ContainSubstring(`reading custom userdata for "custom"`)))
+	})
+
+	It("should honor the configured CPU and memory and need no service", func() {
+		w := workloads.NewCustomWorkload("custom", writeUserdata(script), wlCfg, "virtwork", "", nil)
+
+		Expect(w.Name()).To(Equal("custom"))
+		Expect(w.VMResources()).To(Equal(workloads.VMResourceSpec{CPUCores: 4, Memory: "8Gi"}))
+		Expect(w.VMCount()).To(Equal(1))
+		Expect(w.RequiresService()).To(BeFalse())
+		Expect(w.ServiceSpec()).To(BeNil())
+	})
+
+	Describe("registration", func() {
+		It("should not register a custom workload by default", func() {
+			Expect(workloads.DefaultRegistry().List()).NotTo(ContainElement(workloads.DefaultCustomName))
+		})
+
+		It("should register under the default name when given userdata", func() {
+			reg := workloads.DefaultRegistry(workloads.WithCustomUserdata(writeUserdata(script), ""))
+
+			w, err := reg.Get(workloads.DefaultCustomName, wlCfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(w).To(BeAssignableToTypeOf(&workloads.CustomWorkload{}))
+		})
+
+		It("should register under the given name", func() {
+			reg := workloads.DefaultRegistry(workloads.WithCustomUserdata(writeUserdata(script), "burn"))
+
+			w, err := reg.Get("burn", wlCfg, workloads.WithSSHCredentials("ops", "", nil))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(w.Name()).To(Equal("burn"))
+
+			userdata, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fileContent(parseYAML(userdata), "/usr/local/bin/virtwork-burn.sh")).To(Equal(script))
+		})
+	})
+})
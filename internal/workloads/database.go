@@ -4,17 +4,24 @@
 package workloads
 
 import (
+	"fmt"
+
 	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
 	"github.com/opdev/virtwork/internal/config"
 	"github.com/opdev/virtwork/internal/vm"
 )
 
-const dbSetupScript = `#!/bin/bash
+// dbSetupScriptTemplate is the one-time database setup script. cloud-init
+// has already mounted the data disk on DATA_DIR; %s creates the pgbench
+// database, see dbSetupScript.
+const dbSetupScriptTemplate = `#!/bin/bash
 set -euo pipefail
 
 DATA_DIR="/var/lib/pgsql/data"
 MARKER="${DATA_DIR}/.virtwork-initialized"
+SEED_SQL="/etc/virtwork/db-seed.sql"
 
 # Skip if already initialized
 if [ -f "${MARKER}" ]; then
@@ -22,17 +29,31 @@ if [ -f "${MARKER}" ]; then
     exit 0
 fi
 
-# Format and mount the data disk
-if ! mountpoint -q "${DATA_DIR}"; then
-    mkfs.xfs /dev/vdc
-    mount /dev/vdc "${DATA_DIR}"
-    echo '/dev/vdc /var/lib/pgsql/data xfs defaults 0 0' >> /etc/fstab
-fi
-
 # Set ownership for postgres user
 chown -R postgres:postgres "${DATA_DIR}"
 
-# Initialize PostgreSQL
+%s
+# Apply user-supplied seed SQL, if provided
+if [ -f "${SEED_SQL}" ]; then
+    sudo -u postgres psql -v ON_ERROR_STOP=1 -d pgbench -f "${SEED_SQL}"
+fi
+
+# Stop PostgreSQL (systemd will manage it)
+systemctl stop postgresql
+
+# Mark as initialized
+touch "${MARKER}"
+chown postgres:postgres "${MARKER}"
+`
+
+// dbDataDevice and dbDataDir are the data disk and where it is mounted.
+const (
+	dbDataDevice = "/dev/vdc"
+	dbDataDir    = "/var/lib/pgsql/data"
+)
+
+// dbInitStep initializes PostgreSQL and the pgbench database.
+const dbInitStep = `# Initialize PostgreSQL
 postgresql-setup --initdb
 
 # Start PostgreSQL temporarily for pgbench init
@@ -41,39 +62,48 @@ systemctl start postgresql
 # Create pgbench database with scale factor 50
 sudo -u postgres createdb pgbench
 sudo -u postgres pgbench -i -s 50 pgbench
-
-# Stop PostgreSQL (systemd will manage it)
-systemctl stop postgresql
-
-# Mark as initialized
-touch "${MARKER}"
-chown postgres:postgres "${MARKER}"
 `
 
-const dbSystemdUnit = `[Unit]
-Description=Virtwork database benchmark workload
-After=network.target local-fs.target postgresql.service
-Requires=postgresql.service
-
-[Service]
-Type=simple
-User=postgres
-ExecStartPre=/usr/local/bin/virtwork-db-setup.sh
-ExecStart=/bin/bash -c 'while true; do pgbench -c 10 -j 2 -T 300 pgbench; sleep 10; done'
-Restart=always
-RestartSec=10
-
-[Install]
-WantedBy=multi-user.target
+// dbPreseededStep starts PostgreSQL on a data directory restored from a
+// data source, which already holds the pgbench database.
+const dbPreseededStep = `# The pre-seeded data disk already holds an initialized pgbench database
+systemctl start postgresql
 `
 
+// dbSetupScript returns the setup script. A pre-seeded data disk skips initdb
+// and pgbench initialization.
+func dbSetupScript(preseeded bool) string {
+	if preseeded {
+		return fmt.Sprintf(dbSetupScriptTemplate, dbPreseededStep)
+	}
+	return fmt.Sprintf(dbSetupScriptTemplate, dbInitStep)
+}
+
+// dbSeedSQLPath is where the seed SQL is written; the setup script applies it
+// when present.
+const dbSeedSQLPath = "/etc/virtwork/db-seed.sql"
+
+// dbBenchLoopCommand is formatted with the seconds each pgbench run lasts and
+// the seconds to sleep between runs.
+const dbBenchLoopCommand = `/bin/bash -c 'while true; do pgbench -c 10 -j 2 -T %d pgbench; sleep %d; done'`
+
 // DatabaseWorkload generates cloud-init userdata for a PostgreSQL database
-// benchmark workload using pgbench. It formats a data disk, initializes
-// PostgreSQL, creates a pgbench database at scale 50, and runs continuous
-// benchmark loops.
+// benchmark workload using pgbench. cloud-init formats and mounts a data
+// disk; the workload then initializes PostgreSQL, creates a pgbench database
+// at scale 50, and runs continuous benchmark loops. When SeedSQL is set it is applied to the pgbench database
+// after initialization and before the benchmark starts. When DataSource is
+// set the data disk is restored from it instead, and must already hold an
+// initialized PostgreSQL data directory with the pgbench database. Storage
+// selects the data disk's StorageClass and access modes. When DataFSPVC is
+// set the data disk is replaced by a virtiofs filesystem backed by that PVC
+// and mounted on the PostgreSQL data directory.
 type DatabaseWorkload struct {
 	BaseWorkload
 	DataDiskSize string
+	SeedSQL      string
+	DataSource   *cdiv1beta1.DataVolumeSource
+	Storage      vm.DataVolumeOpts
+	DataFSPVC    string
 }
 
 // NewDatabaseWorkload creates a DatabaseWorkload with the given configuration,
@@ -95,41 +125,82 @@ func (w *DatabaseWorkload) Name() string {
 	return "database"
 }
 
-// CloudInitUserdata returns cloud-init YAML that installs PostgreSQL, writes
-// a setup script for one-time database initialization, and creates a systemd
-// service that runs continuous pgbench benchmarks.
+// RequiredPackages returns the PostgreSQL server package.
+func (w *DatabaseWorkload) RequiredPackages() []string {
+	return []string{"postgresql-server"}
+}
+
+// CloudInitUserdata returns cloud-init YAML that mounts the data disk,
+// installs PostgreSQL, writes a setup script for one-time database
+// initialization, and creates a systemd service that runs continuous pgbench
+// benchmarks.
 func (w *DatabaseWorkload) CloudInitUserdata() (string, error) {
-	return w.BuildCloudConfig(CloudConfigOpts{
-		Packages: []string{"postgresql-server"},
-		WriteFiles: []WriteFile{
-			{
-				Path:        "/usr/local/bin/virtwork-db-setup.sh",
-				Content:     dbSetupScript,
-				Permissions: "0755",
-			},
-			{
-				Path:        "/etc/systemd/system/virtwork-database.service",
-				Content:     dbSystemdUnit,
-				Permissions: "0644",
-			},
+	unit := serviceUnit{
+		Name:         "database",
+		Description:  "Virtwork database benchmark workload",
+		After:        []string{"network.target", "local-fs.target", "postgresql.service"},
+		Requires:     []string{"postgresql.service"},
+		User:         "postgres",
+		ExecStartPre: "/usr/local/bin/virtwork-db-setup.sh",
+		ExecStart:    fmt.Sprintf(dbBenchLoopCommand, w.runDurationSeconds(defaultRunDurationSeconds), w.loopSleepSeconds()),
+		Warmup:       w.Warmup,
+		StartDelay:   w.startDelay,
+		RestartSec:   w.restartSeconds(),
+	}
+	files := []WriteFile{
+		{
+			Path:        "/usr/local/bin/virtwork-db-setup.sh",
+			Content:     dbSetupScript(w.DataSource != nil),
+			Permissions: "0755",
 		},
+	}
+	if w.SeedSQL != "" {
+		files = append(files, WriteFile{
+			Path:        dbSeedSQLPath,
+			Content:     w.SeedSQL,
+			Permissions: "0644",
+		})
+	}
+	fsSetup, mounts := dataDiskOpts(dbDataDevice, dbDataDir, w.DataSource != nil)
+	if w.DataFSPVC != "" {
+		fsSetup, mounts = nil, [][]string{dataFSMount(dbDataDir)}
+	}
+	return w.BuildCloudConfig(CloudConfigOpts{
+		Packages:   w.RequiredPackages(),
+		WriteFiles: append(files, unit.writeFiles()...),
+		FSSetup:    fsSetup,
+		Mounts:     mounts,
 		RunCmd: [][]string{
 			{"systemctl", "daemon-reload"},
 			{"systemctl", "enable", "postgresql"},
-			{"systemctl", "enable", "--now", "virtwork-database.service"},
+			{"systemctl", "enable", "--now", unit.serviceName()},
 		},
 	})
 }
 
-// DataVolumeTemplates returns a DataVolumeTemplateSpec for the PostgreSQL data disk.
+// ReadinessProbe checks that the pgbench service is active, which it only
+// becomes once PostgreSQL is running.
+func (w *DatabaseWorkload) ReadinessProbe(string) *kubevirtv1.Probe {
+	return serviceUnit{Name: "database"}.readinessProbe()
+}
+
+// DataVolumeTemplates returns a DataVolumeTemplateSpec for the PostgreSQL data
+// disk, or nil when a data filesystem replaces it.
 func (w *DatabaseWorkload) DataVolumeTemplates() []kubevirtv1.DataVolumeTemplateSpec {
+	if w.DataFSPVC != "" {
+		return nil
+	}
 	return []kubevirtv1.DataVolumeTemplateSpec{
-		vm.BuildDataVolumeTemplate("virtwork-database-data", w.DataDiskSize),
+		dataVolumeTemplate("virtwork-database-data", w.DataDiskSize, w.DataSource, w.Storage),
 	}
 }
 
-// ExtraDisks returns the data disk definition for PostgreSQL storage.
+// ExtraDisks returns the data disk definition for PostgreSQL storage, or nil
+// when a data filesystem replaces it.
 func (w *DatabaseWorkload) ExtraDisks() []kubevirtv1.Disk {
+	if w.DataFSPVC != "" {
+		return nil
+	}
 	return []kubevirtv1.Disk{
 		{
 			Name: "datadisk",
@@ -142,8 +213,12 @@ func (w *DatabaseWorkload) ExtraDisks() []kubevirtv1.Disk {
 	}
 }
 
-// ExtraVolumes returns the data volume sourced from the DataVolume.
+// ExtraVolumes returns the data volume sourced from the DataVolume, or the
+// PVC volume backing the data filesystem.
 func (w *DatabaseWorkload) ExtraVolumes() []kubevirtv1.Volume {
+	if w.DataFSPVC != "" {
+		return dataFSVolumes(w.DataFSPVC)
+	}
 	return []kubevirtv1.Volume{
 		{
 			Name: "datadisk",
@@ -155,3 +230,12 @@ func (w *DatabaseWorkload) ExtraVolumes() []kubevirtv1.Volume {
 		},
 	}
 }
+
+// Filesystems returns the virtiofs device of the data filesystem, or nil when
+// the workload uses a data disk.
+func (w *DatabaseWorkload) Filesystems() []kubevirtv1.Filesystem {
+	if w.DataFSPVC == "" {
+		return nil
+	}
+	return dataFSFilesystems()
+}
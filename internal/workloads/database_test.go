@@ -4,10 +4,13 @@
 package workloads_test
 
 import (
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/vm"
 	"github.com/opdev/virtwork/internal/workloads"
 )
 
@@ -72,6 +75,106 @@ var _ = Describe("DatabaseWorkload", func() {
 		Expect(setupContent).To(ContainSubstring("scale"))
 	})
 
+	It("should have cloud-init format and mount the blank data disk", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(parsed["fs_setup"]).To(Equal([]interface{}{
+			map[string]interface{}{"filesystem": "xfs", "device": "/dev/vdc", "partition": "none"},
+		}))
+		Expect(parsed["mounts"]).To(Equal([]interface{}{
+			[]interface{}{"/dev/vdc", "/var/lib/pgsql/data", "xfs", "defaults", "0", "0"},
+		}))
+
+		setup := fileContent(parsed, "/usr/local/bin/virtwork-db-setup.sh")
+		Expect(setup).NotTo(ContainSubstring("mkfs"))
+		Expect(setup).NotTo(ContainSubstring("mount "))
+		Expect(setup).To(ContainSubstring("pgbench -i -s 50"))
+		Expect(w.DataVolumeTemplates()[0].Spec.Source.Blank).NotTo(BeNil())
+	})
+
+	Context("with a data source", func() {
+		BeforeEach(func() {
+			source := vm.RegistrySource("docker://quay.io/example/pgbench-data:latest")
+			w.DataSource = &source
+		})
+
+		It("should restore the data disk from the source", func() {
+			dvts := w.DataVolumeTemplates()
+			Expect(dvts).To(HaveLen(1))
+			Expect(dvts[0].Name).To(Equal("virtwork-database-data"))
+			Expect(dvts[0].Spec.Source.Blank).To(BeNil())
+			Expect(*dvts[0].Spec.Source.Registry.URL).To(Equal("docker://quay.io/example/pgbench-data:latest"))
+		})
+
+		It("should mount the disk without mkfs, initdb, or pgbench init", func() {
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			Expect(parsed).NotTo(HaveKey("fs_setup"))
+			Expect(parsed["mounts"]).To(Equal([]interface{}{
+				[]interface{}{"/dev/vdc", "/var/lib/pgsql/data", "auto", "defaults", "0", "0"},
+			}))
+
+			setup := fileContent(parsed, "/usr/local/bin/virtwork-db-setup.sh")
+			Expect(setup).To(ContainSubstring("systemctl start postgresql"))
+			Expect(setup).NotTo(ContainSubstring("mkfs"))
+			Expect(setup).NotTo(ContainSubstring("--initdb"))
+			Expect(setup).NotTo(ContainSubstring("pgbench -i"))
+			Expect(setup).NotTo(ContainSubstring("createdb"))
+		})
+
+		It("should still apply the seed SQL", func() {
+			w.SeedSQL = "CREATE TABLE extra (id int);"
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			setup := fileContent(parseYAML(result), "/usr/local/bin/virtwork-db-setup.sh")
+			Expect(setup).To(ContainSubstring(`psql -v ON_ERROR_STOP=1 -d pgbench -f "${SEED_SQL}"`))
+		})
+	})
+
+	Context("with a data filesystem", func() {
+		BeforeEach(func() {
+			w.DataFSPVC = "shared-data"
+		})
+
+		It("should mount the virtiofs tag on the data directory without formatting", func() {
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			Expect(parsed).NotTo(HaveKey("fs_setup"))
+			Expect(parsed["mounts"]).To(Equal([]interface{}{
+				[]interface{}{"datafs", "/var/lib/pgsql/data", "virtiofs", "defaults", "0", "0"},
+			}))
+
+			setup := fileContent(parsed, "/usr/local/bin/virtwork-db-setup.sh")
+			Expect(setup).To(ContainSubstring("postgresql-setup --initdb"))
+		})
+
+		It("should share the PVC as a virtiofs filesystem instead of a data disk", func() {
+			Expect(w.DataVolumeTemplates()).To(BeNil())
+			Expect(w.ExtraDisks()).To(BeNil())
+			Expect(w.Filesystems()).To(HaveLen(1))
+
+			volumes := w.ExtraVolumes()
+			Expect(volumes).To(HaveLen(1))
+			Expect(volumes[0].Name).To(Equal(w.Filesystems()[0].Name))
+			Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("shared-data"))
+		})
+	})
+
+	It("should provide a readiness probe checking the pgbench service", func() {
+		var p workloads.Prober = w
+		probe := p.ReadinessProbe("")
+		Expect(probe).NotTo(BeNil())
+		Expect(probe.Exec).NotTo(BeNil())
+		Expect(probe.Exec.Command).To(Equal([]string{"systemctl", "is-active", "--quiet", "virtwork-database.service"}))
+	})
+
 	It("should include pgbench systemd service", func() {
 		result, err := w.CloudInitUserdata()
 		Expect(err).NotTo(HaveOccurred())
@@ -108,6 +211,24 @@ var _ = Describe("DatabaseWorkload", func() {
 		Expect(serviceContent).To(ContainSubstring("-T 300"))
 	})
 
+	It("should use the configured run duration, loop sleep, and restart interval", func() {
+		w = workloads.NewDatabaseWorkload(config.WorkloadConfig{
+			Enabled:            true,
+			VMCount:            1,
+			CPUCores:           2,
+			Memory:             "4Gi",
+			RestartSeconds:     30,
+			LoopSleepSeconds:   60,
+			RunDurationSeconds: 3600,
+		}, "10Gi", "virtwork", "", nil)
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		unit := fileContent(parseYAML(result), "/etc/systemd/system/virtwork-database.service")
+		Expect(unit).To(ContainSubstring("pgbench -c 10 -j 2 -T 3600 pgbench; sleep 60; done"))
+		Expect(unit).To(ContainSubstring("RestartSec=30\n"))
+	})
+
 	It("should use ExecStartPre for setup script", func() {
 		result, err := w.CloudInitUserdata()
 		Expect(err).NotTo(HaveOccurred())
@@ -162,4 +283,41 @@ var _ = Describe("DatabaseWorkload", func() {
 		Expect(res.CPUCores).To(Equal(2))
 		Expect(res.Memory).To(Equal("4Gi"))
 	})
+
+	Context("with seed SQL", func() {
+		const seed = "CREATE TABLE orders (id serial PRIMARY KEY, total numeric);\n"
+
+		It("should embed the seed SQL as a write_files entry", func() {
+			w.SeedSQL = seed
+
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fileContent(parseYAML(result), "/etc/virtwork/db-seed.sql")).To(Equal(seed))
+		})
+
+		It("should apply the seed SQL with psql in the setup script after pgbench init", func() {
+			w.SeedSQL = seed
+
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			setup := fileContent(parseYAML(result), "/usr/local/bin/virtwork-db-setup.sh")
+			Expect(setup).To(ContainSubstring(`SEED_SQL="/etc/virtwork/db-seed.sql"`))
+			Expect(setup).To(ContainSubstring(`psql -v ON_ERROR_STOP=1 -d pgbench -f "${SEED_SQL}"`))
+			Expect(strings.Index(setup, "psql")).To(BeNumerically(">", strings.Index(setup, "pgbench -i")))
+		})
+
+		It("should not write a seed file when no seed SQL is set", func() {
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fileContent(parseYAML(result), "/etc/virtwork/db-seed.sql")).To(BeEmpty())
+		})
+
+		It("should receive the seed SQL through the registry option", func() {
+			wl, err := workloads.DefaultRegistry().Get("database", config.WorkloadConfig{VMCount: 1},
+				workloads.WithSeedSQL(seed))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(wl.(*workloads.DatabaseWorkload).SeedSQL).To(Equal(seed))
+		})
+	})
 })
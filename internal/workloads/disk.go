@@ -4,12 +4,18 @@
 package workloads
 
 import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
 	kubevirtv1 "kubevirt.io/api/core/v1"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
 	"github.com/opdev/virtwork/internal/config"
 	"github.com/opdev/virtwork/internal/vm"
 )
 
+// fioMixedRWProfile and fioSeqWriteProfile are formatted with the seconds
+// each fio job runs.
 const fioMixedRWProfile = `[global]
 ioengine=libaio
 direct=1
@@ -21,7 +27,7 @@ rw=randrw
 rwmixread=70
 bs=4k
 numjobs=4
-runtime=300
+runtime=%d
 time_based
 group_reporting
 `
@@ -36,30 +42,34 @@ size=1G
 rw=write
 bs=128k
 numjobs=2
-runtime=300
+runtime=%d
 time_based
 group_reporting
 `
 
-const diskSystemdUnit = `[Unit]
-Description=Virtwork disk I/O workload
-After=network.target local-fs.target
-
-[Service]
-Type=simple
-ExecStart=/bin/bash -c 'while true; do fio /etc/fio/mixed-rw.fio; sleep 10; fio /etc/fio/seq-write.fio; sleep 10; done'
-Restart=always
-RestartSec=10
+// diskDataDevice and diskDataDir are the data disk and the fio directory it
+// is mounted on.
+const (
+	diskDataDevice = "/dev/vdc"
+	diskDataDir    = "/mnt/data"
+)
 
-[Install]
-WantedBy=multi-user.target
-`
+// diskLoopCommand is formatted with the seconds to sleep after each fio job.
+const diskLoopCommand = `/bin/bash -c 'while true; do fio /etc/fio/mixed-rw.fio; sleep %[1]d; fio /etc/fio/seq-write.fio; sleep %[1]d; done'`
 
 // DiskWorkload generates cloud-init userdata for a disk I/O workload using fio.
 // It alternates between a 4K random read/write mix and 128K sequential writes.
+// fio runs on the data disk, which cloud-init formats and mounts on /mnt/data.
+// When DataSource is set the data disk is populated from it instead of being
+// created blank. Storage selects the data disk's StorageClass and access modes.
+// When DataFSPVC is set the data disk is replaced by a virtiofs filesystem
+// backed by that PVC and mounted on the fio directory.
 type DiskWorkload struct {
 	BaseWorkload
 	DataDiskSize string
+	DataSource   *cdiv1beta1.DataVolumeSource
+	Storage      vm.DataVolumeOpts
+	DataFSPVC    string
 }
 
 // NewDiskWorkload creates a DiskWorkload with the given configuration, disk size,
@@ -81,45 +91,132 @@ func (w *DiskWorkload) Name() string {
 	return "disk"
 }
 
+// RequiredPackages returns the fio package.
+func (w *DiskWorkload) RequiredPackages() []string {
+	return []string{"fio"}
+}
+
 // CloudInitUserdata returns cloud-init YAML that installs fio, writes two job
-// profiles, and creates a systemd service that alternates between them.
+// profiles, mounts the data disk on the fio directory, and creates a systemd
+// service that alternates between them.
 func (w *DiskWorkload) CloudInitUserdata() (string, error) {
-	return w.BuildCloudConfig(CloudConfigOpts{
-		Packages: []string{"fio"},
-		WriteFiles: []WriteFile{
-			{
-				Path:        "/etc/fio/mixed-rw.fio",
-				Content:     fioMixedRWProfile,
-				Permissions: "0644",
-			},
-			{
-				Path:        "/etc/fio/seq-write.fio",
-				Content:     fioSeqWriteProfile,
-				Permissions: "0644",
-			},
-			{
-				Path:        "/etc/systemd/system/virtwork-disk.service",
-				Content:     diskSystemdUnit,
-				Permissions: "0644",
-			},
+	runSeconds := w.runDurationSeconds(defaultRunDurationSeconds)
+	unit := serviceUnit{
+		Name:        "disk",
+		Description: "Virtwork disk I/O workload",
+		After:       []string{"network.target", "local-fs.target"},
+		ExecStart:   fmt.Sprintf(diskLoopCommand, w.loopSleepSeconds()),
+		Warmup:      w.Warmup,
+		StartDelay:  w.startDelay,
+		RestartSec:  w.restartSeconds(),
+	}
+	files := []WriteFile{
+		{
+			Path:        "/etc/fio/mixed-rw.fio",
+			Content:     fmt.Sprintf(fioMixedRWProfile, runSeconds),
+			Permissions: "0644",
 		},
+		{
+			Path:        "/etc/fio/seq-write.fio",
+			Content:     fmt.Sprintf(fioSeqWriteProfile, runSeconds),
+			Permissions: "0644",
+		},
+	}
+	fsSetup, mounts := dataDiskOpts(diskDataDevice, diskDataDir, w.DataSource != nil)
+	if w.DataFSPVC != "" {
+		fsSetup, mounts = nil, [][]string{dataFSMount(diskDataDir)}
+	}
+	return w.BuildCloudConfig(CloudConfigOpts{
+		Packages:   w.RequiredPackages(),
+		WriteFiles: append(files, unit.writeFiles()...),
+		FSSetup:    fsSetup,
+		Mounts:     mounts,
 		RunCmd: [][]string{
-			{"mkdir", "-p", "/mnt/data"},
+			{"mkdir", "-p", diskDataDir},
 			{"systemctl", "daemon-reload"},
-			{"systemctl", "enable", "--now", "virtwork-disk.service"},
+			{"systemctl", "enable", "--now", unit.serviceName()},
 		},
 	})
 }
 
-// DataVolumeTemplates returns a DataVolumeTemplateSpec for the data disk.
+// DataVolumeTemplates returns a DataVolumeTemplateSpec for the data disk, or
+// nil when a data filesystem replaces it.
 func (w *DiskWorkload) DataVolumeTemplates() []kubevirtv1.DataVolumeTemplateSpec {
+	if w.DataFSPVC != "" {
+		return nil
+	}
 	return []kubevirtv1.DataVolumeTemplateSpec{
-		vm.BuildDataVolumeTemplate("virtwork-disk-data", w.DataDiskSize),
+		dataVolumeTemplate("virtwork-disk-data", w.DataDiskSize, w.DataSource, w.Storage),
+	}
+}
+
+// dataVolumeTemplate returns the data disk template, blank unless source is
+// set.
+func dataVolumeTemplate(name, size string, source *cdiv1beta1.DataVolumeSource, storage vm.DataVolumeOpts) kubevirtv1.DataVolumeTemplateSpec {
+	if source != nil {
+		return vm.BuildDataVolumeTemplateFromSource(name, size, *source, storage)
+	}
+	return vm.BuildDataVolumeTemplate(name, size, storage)
+}
+
+// dataDiskOpts returns the cloud-init fs_setup and mounts entries that mount
+// the data disk at device on dir. A blank disk is formatted as XFS; a
+// pre-seeded one already holds a filesystem and is mounted as is.
+func dataDiskOpts(device, dir string, preseeded bool) ([]FSSetup, [][]string) {
+	if preseeded {
+		return nil, [][]string{{device, dir, "auto", "defaults", "0", "0"}}
 	}
+	return []FSSetup{{Filesystem: "xfs", Device: device, Partition: "none"}},
+		[][]string{{device, dir, "xfs", "defaults", "0", "0"}}
 }
 
-// ExtraDisks returns the data disk definition.
+// dataFSName names the data filesystem device and its volume. KubeVirt
+// exports the filesystem to the guest under this virtiofs tag.
+const dataFSName = "datafs"
+
+// dataFSFilesystems returns the virtiofs device for a data filesystem.
+func dataFSFilesystems() []kubevirtv1.Filesystem {
+	return []kubevirtv1.Filesystem{
+		{
+			Name:     dataFSName,
+			Virtiofs: &kubevirtv1.FilesystemVirtiofs{},
+		},
+	}
+}
+
+// dataFSVolumes returns the volume backing the data filesystem with pvc.
+func dataFSVolumes(pvc string) []kubevirtv1.Volume {
+	return []kubevirtv1.Volume{
+		{
+			Name: dataFSName,
+			VolumeSource: kubevirtv1.VolumeSource{
+				PersistentVolumeClaim: &kubevirtv1.PersistentVolumeClaimVolumeSource{
+					PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: pvc,
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataFSMount returns the cloud-init mounts entry that mounts the data
+// filesystem's virtiofs tag on dir.
+func dataFSMount(dir string) []string {
+	return []string{dataFSName, dir, "virtiofs", "defaults", "0", "0"}
+}
+
+// ReadinessProbe checks that the fio service is active.
+func (w *DiskWorkload) ReadinessProbe(string) *kubevirtv1.Probe {
+	return serviceUnit{Name: "disk"}.readinessProbe()
+}
+
+// ExtraDisks returns the data disk definition, or nil when a data filesystem
+// replaces it.
 func (w *DiskWorkload) ExtraDisks() []kubevirtv1.Disk {
+	if w.DataFSPVC != "" {
+		return nil
+	}
 	return []kubevirtv1.Disk{
 		{
 			Name: "datadisk",
@@ -132,8 +229,12 @@ func (w *DiskWorkload) ExtraDisks() []kubevirtv1.Disk {
 	}
 }
 
-// ExtraVolumes returns the data volume sourced from the DataVolume.
+// ExtraVolumes returns the data volume sourced from the DataVolume, or the
+// PVC volume backing the data filesystem.
 func (w *DiskWorkload) ExtraVolumes() []kubevirtv1.Volume {
+	if w.DataFSPVC != "" {
+		return dataFSVolumes(w.DataFSPVC)
+	}
 	return []kubevirtv1.Volume{
 		{
 			Name: "datadisk",
@@ -145,3 +246,12 @@ func (w *DiskWorkload) ExtraVolumes() []kubevirtv1.Volume {
 		},
 	}
 }
+
+// Filesystems returns the virtiofs device of the data filesystem, or nil when
+// the workload uses a data disk.
+func (w *DiskWorkload) Filesystems() []kubevirtv1.Filesystem {
+	if w.DataFSPVC == "" {
+		return nil
+	}
+	return dataFSFilesystems()
+}
@@ -8,6 +8,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/vm"
 	"github.com/opdev/virtwork/internal/workloads"
 )
 
@@ -56,12 +57,70 @@ var _ = Describe("DiskWorkload", func() {
 		Expect(paths).To(ContainElement("/etc/systemd/system/virtwork-disk.service"))
 	})
 
+	It("should run each fio job for 300 seconds and sleep 10 between them by default", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(fileContent(parsed, "/etc/fio/mixed-rw.fio")).To(ContainSubstring("runtime=300\n"))
+		Expect(fileContent(parsed, "/etc/fio/seq-write.fio")).To(ContainSubstring("runtime=300\n"))
+		unit := fileContent(parsed, "/etc/systemd/system/virtwork-disk.service")
+		Expect(unit).To(ContainSubstring("fio /etc/fio/mixed-rw.fio; sleep 10; fio /etc/fio/seq-write.fio; sleep 10; done"))
+		Expect(unit).To(ContainSubstring("RestartSec=10\n"))
+	})
+
+	It("should use the configured run duration, loop sleep, and restart interval", func() {
+		w = workloads.NewDiskWorkload(config.WorkloadConfig{
+			Enabled:            true,
+			VMCount:            1,
+			CPUCores:           2,
+			Memory:             "2Gi",
+			RestartSeconds:     30,
+			LoopSleepSeconds:   60,
+			RunDurationSeconds: 3600,
+		}, "10Gi", "virtwork", "", nil)
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(fileContent(parsed, "/etc/fio/mixed-rw.fio")).To(ContainSubstring("runtime=3600\n"))
+		Expect(fileContent(parsed, "/etc/fio/seq-write.fio")).To(ContainSubstring("runtime=3600\n"))
+		unit := fileContent(parsed, "/etc/systemd/system/virtwork-disk.service")
+		Expect(unit).To(ContainSubstring("fio /etc/fio/mixed-rw.fio; sleep 60; fio /etc/fio/seq-write.fio; sleep 60; done"))
+		Expect(unit).To(ContainSubstring("RestartSec=30\n"))
+	})
+
 	It("should have data volume template", func() {
 		dvts := w.DataVolumeTemplates()
 		Expect(dvts).To(HaveLen(1))
 		Expect(dvts[0].Name).To(Equal("virtwork-disk-data"))
 	})
 
+	It("should create a blank data disk by default", func() {
+		Expect(w.DataVolumeTemplates()[0].Spec.Source.Blank).NotTo(BeNil())
+	})
+
+	It("should clone the data disk from a PVC source", func() {
+		source := vm.PVCSource("golden", "fio-seed")
+		w.DataSource = &source
+
+		dvts := w.DataVolumeTemplates()
+		Expect(dvts).To(HaveLen(1))
+		Expect(dvts[0].Name).To(Equal("virtwork-disk-data"))
+		Expect(dvts[0].Spec.Source.Blank).To(BeNil())
+		Expect(dvts[0].Spec.Source.PVC.Namespace).To(Equal("golden"))
+		Expect(dvts[0].Spec.Source.PVC.Name).To(Equal("fio-seed"))
+	})
+
+	It("should provide a readiness probe checking the fio service", func() {
+		var p workloads.Prober = w
+		probe := p.ReadinessProbe("")
+		Expect(probe).NotTo(BeNil())
+		Expect(probe.Exec).NotTo(BeNil())
+		Expect(probe.Exec.Command).To(Equal([]string{"systemctl", "is-active", "--quiet", "virtwork-disk.service"}))
+		Expect(probe.PeriodSeconds).To(BeNumerically(">", 0))
+	})
+
 	It("should have extra disk for data volume", func() {
 		disks := w.ExtraDisks()
 		Expect(disks).To(HaveLen(1))
@@ -72,6 +131,68 @@ var _ = Describe("DiskWorkload", func() {
 		Expect(volumes[0].Name).To(Equal("datadisk"))
 	})
 
+	It("should format the data disk and mount it on the fio directory", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(parsed["fs_setup"]).To(Equal([]interface{}{
+			map[string]interface{}{"filesystem": "xfs", "device": "/dev/vdc", "partition": "none"},
+		}))
+		Expect(parsed["mounts"]).To(Equal([]interface{}{
+			[]interface{}{"/dev/vdc", "/mnt/data", "xfs", "defaults", "0", "0"},
+		}))
+	})
+
+	It("should mount a pre-seeded data disk without formatting it", func() {
+		source := vm.PVCSource("golden", "fio-seed")
+		w.DataSource = &source
+
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(parsed).NotTo(HaveKey("fs_setup"))
+		Expect(parsed["mounts"]).To(Equal([]interface{}{
+			[]interface{}{"/dev/vdc", "/mnt/data", "auto", "defaults", "0", "0"},
+		}))
+	})
+
+	Context("with a data filesystem", func() {
+		BeforeEach(func() {
+			w.DataFSPVC = "shared-data"
+		})
+
+		It("should mount the virtiofs tag on the fio directory", func() {
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			Expect(parsed["mounts"]).To(Equal([]interface{}{
+				[]interface{}{"datafs", "/mnt/data", "virtiofs", "defaults", "0", "0"},
+			}))
+		})
+
+		It("should share the PVC as a virtiofs filesystem instead of a data disk", func() {
+			Expect(w.DataVolumeTemplates()).To(BeNil())
+			Expect(w.ExtraDisks()).To(BeNil())
+
+			filesystems := w.Filesystems()
+			Expect(filesystems).To(HaveLen(1))
+			Expect(filesystems[0].Name).To(Equal("datafs"))
+			Expect(filesystems[0].Virtiofs).NotTo(BeNil())
+
+			volumes := w.ExtraVolumes()
+			Expect(volumes).To(HaveLen(1))
+			Expect(volumes[0].Name).To(Equal("datafs"))
+			Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("shared-data"))
+		})
+	})
+
+	It("should have no filesystems by default", func() {
+		Expect(w.Filesystems()).To(BeNil())
+	})
+
 	It("should not require service", func() {
 		Expect(w.RequiresService()).To(BeFalse())
 		Expect(w.ServiceSpec()).To(BeNil())
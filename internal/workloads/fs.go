@@ -0,0 +1,163 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads
+
+import (
+	"fmt"
+
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/vm"
+)
+
+// fsDataDevice and fsMountPoint are the data disk and where it is mounted.
+const (
+	fsDataDevice = "/dev/vdc"
+	fsMountPoint = "/mnt/fs"
+)
+
+// fsChurnScriptPath is where the file churn script is written.
+const fsChurnScriptPath = "/usr/local/bin/virtwork-fs-churn.sh"
+
+// fsChurnScript runs one round of metadata-heavy filesystem stress: it
+// creates 5000 4 KiB files across 50 directories, stats and renames each of
+// them, then deletes the whole tree.
+const fsChurnScript = `#!/bin/bash
+set -euo pipefail
+
+ROOT="/mnt/fs/churn"
+DIRS=50
+FILES_PER_DIR=100
+
+rm -rf "${ROOT}"
+mkdir -p "${ROOT}"
+
+# Create
+for d in $(seq 1 "${DIRS}"); do
+    mkdir "${ROOT}/d${d}"
+    for f in $(seq 1 "${FILES_PER_DIR}"); do
+        head -c 4096 /dev/urandom > "${ROOT}/d${d}/f${f}"
+    done
+done
+sync
+
+# Stat
+find "${ROOT}" -type f -exec stat --format=%s {} + > /dev/null
+
+# Rename
+for d in $(seq 1 "${DIRS}"); do
+    for f in $(seq 1 "${FILES_PER_DIR}"); do
+        mv "${ROOT}/d${d}/f${f}" "${ROOT}/d${d}/r${f}"
+    done
+done
+
+# Delete
+rm -rf "${ROOT}"
+sync
+`
+
+// fsLoopCommand is formatted with the seconds to sleep between churn passes.
+const fsLoopCommand = `/bin/bash -c 'while true; do /usr/local/bin/virtwork-fs-churn.sh; sleep %d; done'`
+
+// FilesystemWorkload generates cloud-init userdata for a metadata-heavy
+// filesystem workload. Where DiskWorkload drives block I/O with fio, this
+// workload repeatedly creates, stats, renames, and deletes thousands of small
+// files on an XFS filesystem that cloud-init creates on the data disk.
+// Storage selects the data disk's StorageClass and access modes.
+type FilesystemWorkload struct {
+	BaseWorkload
+	DataDiskSize string
+	Storage      vm.DataVolumeOpts
+}
+
+// NewFilesystemWorkload creates a FilesystemWorkload with the given
+// configuration, disk size, and SSH credentials.
+func NewFilesystemWorkload(cfg config.WorkloadConfig, dataDiskSize, sshUser, sshPassword string, sshKeys []string) *FilesystemWorkload {
+	return &FilesystemWorkload{
+		BaseWorkload: BaseWorkload{
+			Config:            cfg,
+			SSHUser:           sshUser,
+			SSHPassword:       sshPassword,
+			SSHAuthorizedKeys: sshKeys,
+		},
+		DataDiskSize: dataDiskSize,
+	}
+}
+
+// Name returns "fs".
+func (w *FilesystemWorkload) Name() string {
+	return "fs"
+}
+
+// CloudInitUserdata returns cloud-init YAML that formats and mounts the data
+// disk, writes the file churn script, and creates a systemd service that runs
+// it in a loop.
+func (w *FilesystemWorkload) CloudInitUserdata() (string, error) {
+	unit := serviceUnit{
+		Name:        "fs",
+		Description: "Virtwork filesystem metadata workload",
+		After:       []string{"network.target", "local-fs.target"},
+		ExecStart:   fmt.Sprintf(fsLoopCommand, w.loopSleepSeconds()),
+		Warmup:      w.Warmup,
+		StartDelay:  w.startDelay,
+		RestartSec:  w.restartSeconds(),
+	}
+	files := []WriteFile{
+		{
+			Path:        fsChurnScriptPath,
+			Content:     fsChurnScript,
+			Permissions: "0755",
+		},
+	}
+	return w.BuildCloudConfig(CloudConfigOpts{
+		WriteFiles: append(files, unit.writeFiles()...),
+		FSSetup:    []FSSetup{{Filesystem: "xfs", Device: fsDataDevice, Partition: "none"}},
+		Mounts:     [][]string{{fsDataDevice, fsMountPoint, "xfs", "defaults", "0", "0"}},
+		RunCmd: [][]string{
+			{"systemctl", "daemon-reload"},
+			{"systemctl", "enable", "--now", unit.serviceName()},
+		},
+	})
+}
+
+// ReadinessProbe checks that the file churn service is active.
+func (w *FilesystemWorkload) ReadinessProbe(string) *kubevirtv1.Probe {
+	return serviceUnit{Name: "fs"}.readinessProbe()
+}
+
+// DataVolumeTemplates returns a blank DataVolumeTemplateSpec for the data disk.
+func (w *FilesystemWorkload) DataVolumeTemplates() []kubevirtv1.DataVolumeTemplateSpec {
+	return []kubevirtv1.DataVolumeTemplateSpec{
+		dataVolumeTemplate("virtwork-fs-data", w.DataDiskSize, nil, w.Storage),
+	}
+}
+
+// ExtraDisks returns the data disk definition.
+func (w *FilesystemWorkload) ExtraDisks() []kubevirtv1.Disk {
+	return []kubevirtv1.Disk{
+		{
+			Name: "datadisk",
+			DiskDevice: kubevirtv1.DiskDevice{
+				Disk: &kubevirtv1.DiskTarget{
+					Bus: "virtio",
+				},
+			},
+		},
+	}
+}
+
+// ExtraVolumes returns the data volume sourced from the DataVolume.
+func (w *FilesystemWorkload) ExtraVolumes() []kubevirtv1.Volume {
+	return []kubevirtv1.Volume{
+		{
+			Name: "datadisk",
+			VolumeSource: kubevirtv1.VolumeSource{
+				DataVolume: &kubevirtv1.DataVolumeSource{
+					Name: "virtwork-fs-data",
+				},
+			},
+		},
+	}
+}
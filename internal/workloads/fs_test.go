@@ -0,0 +1,107 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+var _ = Describe("FilesystemWorkload", func() {
+	var w *workloads.FilesystemWorkload
+
+	BeforeEach(func() {
+		w = workloads.NewFilesystemWorkload(config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}, "10Gi", "virtwork", "", nil)
+	})
+
+	It("should return 'fs' for Name", func() {
+		Expect(w.Name()).To(Equal("fs"))
+	})
+
+	It("should format and mount the data disk", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(parsed["fs_setup"]).To(Equal([]interface{}{
+			map[string]interface{}{"filesystem": "xfs", "device": "/dev/vdc", "partition": "none"},
+		}))
+		Expect(parsed["mounts"]).To(Equal([]interface{}{
+			[]interface{}{"/dev/vdc", "/mnt/fs", "xfs", "defaults", "0", "0"},
+		}))
+	})
+
+	It("should write a churn script that creates, renames, and deletes small files", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		files := parsed["write_files"].([]interface{})
+		Expect(files).To(HaveLen(2))
+
+		script := files[0].(map[string]interface{})
+		Expect(script["path"]).To(Equal("/usr/local/bin/virtwork-fs-churn.sh"))
+		Expect(script["permissions"]).To(Equal("0755"))
+		content := script["content"].(string)
+		Expect(content).To(ContainSubstring(`ROOT="/mnt/fs/churn"`))
+		Expect(content).To(ContainSubstring("head -c 4096 /dev/urandom"))
+		Expect(content).To(ContainSubstring("mv "))
+		Expect(content).To(ContainSubstring(`rm -rf "${ROOT}"`))
+	})
+
+	It("should run the churn script in a loop from a systemd service", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		files := parsed["write_files"].([]interface{})
+		unit := files[1].(map[string]interface{})
+		Expect(unit["path"]).To(Equal("/etc/systemd/system/virtwork-fs.service"))
+		content := unit["content"].(string)
+		Expect(content).To(ContainSubstring("After=network.target local-fs.target"))
+		Expect(content).To(ContainSubstring("while true; do /usr/local/bin/virtwork-fs-churn.sh; sleep 10; done"))
+		Expect(content).To(ContainSubstring("Restart=always"))
+
+		runcmd := parsed["runcmd"].([]interface{})
+		Expect(runcmd).To(ContainElement([]interface{}{"systemctl", "enable", "--now", "virtwork-fs.service"}))
+	})
+
+	It("should have a blank data volume template", func() {
+		dvts := w.DataVolumeTemplates()
+		Expect(dvts).To(HaveLen(1))
+		Expect(dvts[0].Name).To(Equal("virtwork-fs-data"))
+		Expect(dvts[0].Spec.Source.Blank).NotTo(BeNil())
+	})
+
+	It("should attach the data volume as an extra disk", func() {
+		disks := w.ExtraDisks()
+		Expect(disks).To(HaveLen(1))
+		Expect(disks[0].Name).To(Equal("datadisk"))
+
+		volumes := w.ExtraVolumes()
+		Expect(volumes).To(HaveLen(1))
+		Expect(volumes[0].Name).To(Equal("datadisk"))
+		Expect(volumes[0].DataVolume.Name).To(Equal("virtwork-fs-data"))
+	})
+
+	It("should provide a readiness probe checking the fs service", func() {
+		var p workloads.Prober = w
+		probe := p.ReadinessProbe("")
+		Expect(probe).NotTo(BeNil())
+		Expect(probe.Exec.Command).To(Equal([]string{"systemctl", "is-active", "--quiet", "virtwork-fs.service"}))
+	})
+
+	It("should not require service", func() {
+		Expect(w.RequiresService()).To(BeFalse())
+		Expect(w.ServiceSpec()).To(BeNil())
+	})
+})
@@ -4,26 +4,29 @@
 package workloads
 
 import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	"github.com/opdev/virtwork/internal/config"
 )
 
-const memorySystemdUnit = `[Unit]
-Description=Virtwork memory stress workload
-After=network.target
+const memoryStressCommand = "/usr/bin/stress-ng --vm 1 --vm-bytes 80% --vm-method all --timeout 0"
 
-[Service]
-Type=simple
-ExecStart=/usr/bin/stress-ng --vm 1 --vm-bytes 80% --vm-method all --timeout 0
-Restart=always
-RestartSec=10
-
-[Install]
-WantedBy=multi-user.target
-`
+// memoryStressPercent is the share of guest memory the stress-ng worker
+// keeps in use.
+const memoryStressPercent = 80
 
 // MemoryWorkload generates cloud-init userdata for a continuous memory pressure
 // workload using stress-ng. It uses a single VM worker (--vm 1) targeting 80%
 // of available memory to produce sustained pressure without triggering OOM kills.
+//
+// When Config.Hugepages is set, the guest memory is backed by hugepages of
+// that size, which KubeVirt allocates in full when the VM starts. The worker
+// then targets 80% of the memory request as a fixed size and advises the
+// guest kernel to use huge pages for it, so the pressure lands on the
+// hugepage-backed memory rather than on whatever the guest reports as
+// available. The memory request must then be a multiple of the page size.
 type MemoryWorkload struct {
 	BaseWorkload
 }
@@ -45,21 +48,54 @@ func (w *MemoryWorkload) Name() string {
 	return "memory"
 }
 
+// RequiredPackages returns the stress-ng package.
+func (w *MemoryWorkload) RequiredPackages() []string {
+	return []string{"stress-ng"}
+}
+
 // CloudInitUserdata returns cloud-init YAML that installs stress-ng and runs a
-// continuous memory pressure workload via systemd.
+// continuous memory pressure workload via systemd. It fails when hugepages
+// are set and the memory request is not a multiple of the page size.
 func (w *MemoryWorkload) CloudInitUserdata() (string, error) {
+	command, err := w.stressCommand()
+	if err != nil {
+		return "", err
+	}
+	unit := serviceUnit{
+		Name:        "memory",
+		Description: "Virtwork memory stress workload",
+		ExecStart:   command,
+		Warmup:      w.Warmup,
+		StartDelay:  w.startDelay,
+		RestartSec:  w.restartSeconds(),
+	}
 	return w.BuildCloudConfig(CloudConfigOpts{
-		Packages: []string{"stress-ng"},
-		WriteFiles: []WriteFile{
-			{
-				Path:        "/etc/systemd/system/virtwork-memory.service",
-				Content:     memorySystemdUnit,
-				Permissions: "0644",
-			},
-		},
+		Packages:   w.RequiredPackages(),
+		WriteFiles: unit.writeFiles(),
 		RunCmd: [][]string{
 			{"systemctl", "daemon-reload"},
-			{"systemctl", "enable", "--now", "virtwork-memory.service"},
+			{"systemctl", "enable", "--now", unit.serviceName()},
 		},
 	})
 }
+
+// stressCommand returns the stress-ng command line, sized to the
+// hugepage-backed memory when Config.Hugepages is set.
+func (w *MemoryWorkload) stressCommand() (string, error) {
+	if w.Config.Hugepages == "" {
+		return memoryStressCommand, nil
+	}
+	pageSize, err := resource.ParseQuantity(w.Config.Hugepages)
+	if err != nil {
+		return "", fmt.Errorf("parsing hugepages size %q: %w", w.Config.Hugepages, err)
+	}
+	memory, err := resource.ParseQuantity(w.Config.Memory)
+	if err != nil {
+		return "", fmt.Errorf("parsing memory %q: %w", w.Config.Memory, err)
+	}
+	if memory.Value()%pageSize.Value() != 0 {
+		return "", fmt.Errorf("memory %s is not a multiple of the %s hugepage size", w.Config.Memory, w.Config.Hugepages)
+	}
+	mebibytes := memory.Value() * memoryStressPercent / 100 / (1 << 20)
+	return fmt.Sprintf("/usr/bin/stress-ng --vm 1 --vm-bytes %dM --vm-method all --vm-madvise hugepage --timeout 0", mebibytes), nil
+}
@@ -101,4 +101,36 @@ var _ = Describe("MemoryWorkload", func() {
 		Expect(res.CPUCores).To(Equal(2))
 		Expect(res.Memory).To(Equal("4Gi"))
 	})
+
+	Context("with 2Mi hugepages", func() {
+		newHugepagesWorkload := func(memory string) *workloads.MemoryWorkload {
+			return workloads.NewMemoryWorkload(config.WorkloadConfig{
+				Enabled:   true,
+				VMCount:   1,
+				CPUCores:  2,
+				Memory:    memory,
+				Hugepages: "2Mi",
+			}, "virtwork", "", nil)
+		}
+
+		It("should size stress-ng to the hugepage-backed memory", func() {
+			result, err := newHugepagesWorkload("4Gi").CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			content := fileContent(parseYAML(result), "/etc/systemd/system/virtwork-memory.service")
+			Expect(content).To(ContainSubstring("--vm-bytes 3276M"))
+			Expect(content).To(ContainSubstring("--vm-madvise hugepage"))
+			Expect(content).NotTo(ContainSubstring("--vm-bytes 80%"))
+		})
+
+		It("should accept memory that is a whole number of pages", func() {
+			_, err := newHugepagesWorkload("1026Mi").CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject memory that is not a multiple of the page size", func() {
+			_, err := newHugepagesWorkload("1025Mi").CloudInitUserdata()
+			Expect(err).To(MatchError("memory 1025Mi is not a multiple of the 2Mi hugepage size"))
+		})
+	})
 })
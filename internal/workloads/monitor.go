@@ -0,0 +1,169 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/constants"
+)
+
+// monitorTargetsService names the headless Service whose DNS records list
+// the VMs the monitor scrapes.
+const monitorTargetsService = "virtwork-monitor-targets"
+
+// nodeExporterPort is the port node_exporter serves metrics on.
+const nodeExporterPort = 9100
+
+// monitorConfigPath is where the Prometheus configuration is written.
+const monitorConfigPath = "/etc/virtwork/prometheus.yml"
+
+// monitorTSDBPath is where Prometheus stores its time series, on the root disk.
+const monitorTSDBPath = "/var/lib/virtwork-prometheus"
+
+// monitorConfigTemplate scrapes node_exporter on every address the targets
+// Service resolves to. %[1]s is the Service's DNS name and %[2]d the
+// node_exporter port.
+const monitorConfigTemplate = `global:
+  scrape_interval: 15s
+  evaluation_interval: 15s
+
+scrape_configs:
+  - job_name: virtwork
+    dns_sd_configs:
+      - names:
+          - %[1]s
+        type: A
+        port: %[2]d
+        refresh_interval: 30s
+`
+
+const monitorExecStart = "/usr/bin/prometheus --config.file=" + monitorConfigPath +
+	" --storage.tsdb.path=" + monitorTSDBPath + " --storage.tsdb.retention.time=1d" +
+	" --web.listen-address=:9090"
+
+// MonitorWorkload deploys a Prometheus VM that scrapes node_exporter on the
+// VMs of its run, for demos that should carry their own metrics. Its
+// Service is headless and selects the run's VMs by label, so the monitor
+// discovers them through the Service's DNS records and follows scale-ups
+// without reconfiguration. Prometheus's web UI on port 9090 is the dashboard.
+// The targets only export metrics when the registry is given WithMonitoring,
+// which also scopes the Service to RunID; without a RunID it selects every
+// virtwork VM in the namespace.
+type MonitorWorkload struct {
+	BaseWorkload
+	Namespace string
+	RunID     string
+}
+
+// NewMonitorWorkload creates a MonitorWorkload with the given configuration,
+// namespace, and SSH credentials.
+func NewMonitorWorkload(cfg config.WorkloadConfig, namespace, sshUser, sshPassword string, sshKeys []string) *MonitorWorkload {
+	return &MonitorWorkload{
+		BaseWorkload: BaseWorkload{
+			Config:            cfg,
+			SSHUser:           sshUser,
+			SSHPassword:       sshPassword,
+			SSHAuthorizedKeys: sshKeys,
+		},
+		Namespace: namespace,
+	}
+}
+
+// Name returns "monitor".
+func (w *MonitorWorkload) Name() string {
+	return "monitor"
+}
+
+// RequiredPackages returns the Prometheus server package.
+func (w *MonitorWorkload) RequiredPackages() []string {
+	return []string{"golang-github-prometheus"}
+}
+
+// CloudInitUserdata returns cloud-init YAML that installs Prometheus, writes
+// a scrape configuration pointing at the targets Service, and runs
+// Prometheus as a systemd service.
+func (w *MonitorWorkload) CloudInitUserdata() (string, error) {
+	targets := fmt.Sprintf("%s.%s.svc.cluster.local", monitorTargetsService, w.Namespace)
+	unit := serviceUnit{
+		Name:         "monitor",
+		Description:  "Virtwork fleet monitor",
+		ExecStartPre: "/usr/bin/mkdir -p " + monitorTSDBPath,
+		ExecStart:    monitorExecStart,
+		RestartSec:   w.restartSeconds(),
+	}
+	files := []WriteFile{
+		{
+			Path:        monitorConfigPath,
+			Content:     fmt.Sprintf(monitorConfigTemplate, targets, nodeExporterPort),
+			Permissions: "0644",
+		},
+	}
+	return w.BuildCloudConfig(CloudConfigOpts{
+		Packages:   w.RequiredPackages(),
+		WriteFiles: append(files, unit.writeFiles()...),
+		RunCmd: [][]string{
+			{"systemctl", "daemon-reload"},
+			{"systemctl", "enable", "--now", unit.serviceName()},
+		},
+	})
+}
+
+// RequiresService returns true; the targets Service drives discovery.
+func (w *MonitorWorkload) RequiresService() bool {
+	return true
+}
+
+// ServiceSpec returns the headless targets Service. It selects the run's VMs,
+// the monitor included, and publishes their addresses before they are ready
+// so that VMs still booting show up as down rather than missing.
+func (w *MonitorWorkload) ServiceSpec() *corev1.Service {
+	selector := map[string]string{
+		constants.LabelManagedBy: constants.ManagedByValue,
+	}
+	if w.RunID != "" {
+		selector[constants.LabelRunID] = w.RunID
+	}
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      monitorTargetsService,
+			Namespace: w.Namespace,
+			Labels: map[string]string{
+				constants.LabelAppName:   "virtwork",
+				constants.LabelManagedBy: constants.ManagedByValue,
+				constants.LabelComponent: "monitor",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Selector:                 selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "node-exporter",
+					Port:       nodeExporterPort,
+					TargetPort: intstr.FromInt32(nodeExporterPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// RootWrites returns the Prometheus storage directory, which is on the root
+// disk.
+func (w *MonitorWorkload) RootWrites() []string {
+	return []string{monitorTSDBPath}
+}
+
+// ReadinessProbe checks that Prometheus is active.
+func (w *MonitorWorkload) ReadinessProbe(string) *kubevirtv1.Probe {
+	return serviceUnit{Name: "monitor"}.readinessProbe()
+}
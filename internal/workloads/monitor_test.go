@@ -0,0 +1,86 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+var _ = Describe("MonitorWorkload", func() {
+	var w *workloads.MonitorWorkload
+
+	BeforeEach(func() {
+		w = workloads.NewMonitorWorkload(config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}, "virtwork", "virtwork", "", nil)
+	})
+
+	It("should return 'monitor' for Name", func() {
+		Expect(w.Name()).To(Equal("monitor"))
+	})
+
+	It("should scrape node_exporter on the addresses of the targets Service", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(parsed["packages"]).To(ContainElement("golang-github-prometheus"))
+		prometheusConfig := fileContent(parsed, "/etc/virtwork/prometheus.yml")
+		Expect(prometheusConfig).To(ContainSubstring("dns_sd_configs:"))
+		Expect(prometheusConfig).To(ContainSubstring("- virtwork-monitor-targets.virtwork.svc.cluster.local"))
+		Expect(prometheusConfig).To(ContainSubstring("port: 9100"))
+	})
+
+	It("should run Prometheus from a systemd service", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		unit := fileContent(parsed, "/etc/systemd/system/virtwork-monitor.service")
+		Expect(unit).To(ContainSubstring("--config.file=/etc/virtwork/prometheus.yml"))
+		Expect(unit).To(ContainSubstring("--web.listen-address=:9090"))
+
+		runcmd := parsed["runcmd"].([]interface{})
+		Expect(runcmd).To(ContainElement([]interface{}{"systemctl", "enable", "--now", "virtwork-monitor.service"}))
+	})
+
+	It("should require a headless Service on the node_exporter port", func() {
+		Expect(w.RequiresService()).To(BeTrue())
+
+		svc := w.ServiceSpec()
+		Expect(svc.Name).To(Equal("virtwork-monitor-targets"))
+		Expect(svc.Namespace).To(Equal("virtwork"))
+		Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+		Expect(svc.Spec.PublishNotReadyAddresses).To(BeTrue())
+		Expect(svc.Spec.Ports).To(HaveLen(1))
+		Expect(svc.Spec.Ports[0].Port).To(Equal(int32(9100)))
+	})
+
+	It("should select the run's VMs when the run ID is set", func() {
+		w.RunID = "run-1"
+		Expect(w.ServiceSpec().Spec.Selector).To(Equal(map[string]string{
+			"app.kubernetes.io/managed-by": "virtwork",
+			"virtwork/run-id":              "run-1",
+		}))
+	})
+
+	It("should select every virtwork VM without a run ID", func() {
+		Expect(w.ServiceSpec().Spec.Selector).To(Equal(map[string]string{
+			"app.kubernetes.io/managed-by": "virtwork",
+		}))
+	})
+
+	It("should provide a readiness probe checking the Prometheus service", func() {
+		var p workloads.Prober = w
+		Expect(p.ReadinessProbe("").Exec.Command).To(Equal([]string{"systemctl", "is-active", "--quiet", "virtwork-monitor.service"}))
+	})
+})
@@ -5,35 +5,46 @@ package workloads
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	kubevirtv1 "kubevirt.io/api/core/v1"
 
 	"github.com/opdev/virtwork/internal/config"
 )
 
-const iperf3ServerSystemdUnit = `[Unit]
-Description=Virtwork iperf3 server
-After=network.target
-
-[Service]
-Type=simple
-ExecStart=/usr/bin/iperf3 -s
-Restart=always
-RestartSec=10
-
-[Install]
-WantedBy=multi-user.target
-`
+const (
+	iperf3ServerCommand = "/usr/bin/iperf3 -s"
+	// iperf3BasePort is the default first port the server listens on. With
+	// more than one client per server, listener i uses the base port plus i.
+	iperf3BasePort = 5201
+	// iperf3DefaultStreams is the default number of parallel client streams.
+	iperf3DefaultStreams = 4
+	// iperf3DurationSeconds is the default length of each client test.
+	iperf3DurationSeconds = 60
+	// iperf3DefaultUDPBandwidth is the UDP target bitrate used when none is
+	// configured. iperf3 otherwise sends UDP at only 1 Mbit/s.
+	iperf3DefaultUDPBandwidth = "1G"
+)
 
 // NetworkWorkload generates cloud-init userdata for an iperf3 network benchmark.
-// It creates two VMs: a server running iperf3 in listen mode, and a client that
-// runs bidirectional tests against the server via DNS. A K8s Service routes
-// traffic to the server VM.
+// It creates server VMs running iperf3 in listen mode, and client VMs that
+// run tests against the servers via DNS. A K8s Service routes traffic to the
+// server VMs. The protocol, port, parallel streams, direction, and bandwidth
+// come from the workload config.
 type NetworkWorkload struct {
 	BaseWorkload
 	Namespace string
+	// ClientsPerServer is the number of client VMs created for each server.
+	// Values below 1 are treated as 1 (one client per server).
+	ClientsPerServer int
+	// NetworkPolicy confines ingress to the server VMs to the client VMs on
+	// the iperf3 ports; see NetworkPolicySpec.
+	NetworkPolicy bool
 }
 
 // NewNetworkWorkload creates a NetworkWorkload with the given configuration,
@@ -55,14 +66,84 @@ func (w *NetworkWorkload) Name() string {
 	return "network"
 }
 
-// VMCount returns the total VM count — one server and one client per
-// configured vm-count.
+// RequiredPackages returns iperf3, used by both the server and client roles.
+func (w *NetworkWorkload) RequiredPackages() []string {
+	return []string{"iperf3"}
+}
+
+// VMCount returns the total VM count — one server per configured vm-count,
+// plus ClientsPerServer clients for each server.
 func (w *NetworkWorkload) VMCount() int {
-	count := w.Config.VMCount
-	if count < 1 {
-		count = 1
+	return w.servers() * (1 + w.clientsPerServer())
+}
+
+// RoleCounts returns the number of server and client VMs.
+func (w *NetworkWorkload) RoleCounts() []RoleCount {
+	return []RoleCount{
+		{Role: "server", Count: w.servers()},
+		{Role: "client", Count: w.servers() * w.clientsPerServer()},
+	}
+}
+
+// servers returns the configured number of server VMs (at least one).
+func (w *NetworkWorkload) servers() int {
+	if w.Config.VMCount < 1 {
+		return 1
+	}
+	return w.Config.VMCount
+}
+
+// clientsPerServer returns ClientsPerServer, defaulting to one.
+func (w *NetworkWorkload) clientsPerServer() int {
+	if w.ClientsPerServer < 1 {
+		return 1
+	}
+	return w.ClientsPerServer
+}
+
+// ports returns the ports the server listens on. iperf3 serves one test at a
+// time, so each client of a server needs its own listener.
+func (w *NetworkWorkload) ports() []int {
+	ports := make([]int, w.clientsPerServer())
+	for i := range ports {
+		ports[i] = w.basePort() + i
+	}
+	return ports
+}
+
+// basePort returns the configured port, defaulting to iperf3BasePort.
+func (w *NetworkWorkload) basePort() int {
+	if w.Config.Port < 1 {
+		return iperf3BasePort
+	}
+	return w.Config.Port
+}
+
+// udp reports whether the workload is configured for UDP tests.
+func (w *NetworkWorkload) udp() bool {
+	return strings.EqualFold(w.Config.Protocol, "udp")
+}
+
+// streams returns the configured parallel streams, defaulting to four.
+func (w *NetworkWorkload) streams() int {
+	if w.Config.ParallelStreams < 1 {
+		return iperf3DefaultStreams
+	}
+	return w.Config.ParallelStreams
+}
+
+// bidir reports whether tests run in both directions, which is the default.
+func (w *NetworkWorkload) bidir() bool {
+	return w.Config.Bidir == nil || *w.Config.Bidir
+}
+
+// bandwidth returns the target bitrate for -b. UDP always gets one, since
+// iperf3's UDP default is too low to load the network.
+func (w *NetworkWorkload) bandwidth() string {
+	if w.Config.Bandwidth == "" && w.udp() {
+		return iperf3DefaultUDPBandwidth
 	}
-	return count * 2
+	return w.Config.Bandwidth
 }
 
 // RequiresService returns true — the client needs a ClusterIP Service to reach
@@ -71,8 +152,9 @@ func (w *NetworkWorkload) RequiresService() bool {
 	return true
 }
 
-// ServiceSpec returns a ClusterIP Service on port 5201 targeting the server VM
-// by the virtwork/role: server label.
+// ServiceSpec returns a ClusterIP Service on the configured port (plus one
+// port per additional client per server) targeting the network server VMs by
+// their component and virtwork/role: server labels.
 func (w *NetworkWorkload) ServiceSpec() *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -86,17 +168,96 @@ func (w *NetworkWorkload) ServiceSpec() *corev1.Service {
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: map[string]string{
-				"virtwork/role": "server",
+				"app.kubernetes.io/component": "network",
+				"virtwork/role":               "server",
 			},
-			Ports: []corev1.ServicePort{
-				{
-					Name:       "iperf3",
-					Port:       5201,
-					TargetPort: intstr.FromInt32(5201),
-					Protocol:   corev1.ProtocolTCP,
-				},
+			Ports: w.servicePorts(),
+		},
+	}
+}
+
+// servicePorts returns a Service port for each server listener. The first is
+// named "iperf3" and the rest "iperf3-<n>". iperf3 always uses TCP for its
+// control connection, so UDP tests add a UDP port alongside each TCP one,
+// named with a "-udp" suffix.
+func (w *NetworkWorkload) servicePorts() []corev1.ServicePort {
+	var ports []corev1.ServicePort
+	for i, port := range w.ports() {
+		name := "iperf3"
+		if i > 0 {
+			name = fmt.Sprintf("iperf3-%d", i)
+		}
+		ports = append(ports, corev1.ServicePort{
+			Name:       name,
+			Port:       int32(port),
+			TargetPort: intstr.FromInt32(int32(port)),
+			Protocol:   corev1.ProtocolTCP,
+		})
+		if w.udp() {
+			ports = append(ports, corev1.ServicePort{
+				Name:       name + "-udp",
+				Port:       int32(port),
+				TargetPort: intstr.FromInt32(int32(port)),
+				Protocol:   corev1.ProtocolUDP,
+			})
+		}
+	}
+	return ports
+}
+
+// NetworkPolicySpec returns, when NetworkPolicy is set, a NetworkPolicy that
+// only admits traffic to the server VMs from the client VMs on the ports of
+// ServiceSpec. With NodeExporter set, the monitor VMs may also scrape the
+// servers' node_exporter. It returns nil when NetworkPolicy is not set.
+func (w *NetworkWorkload) NetworkPolicySpec() *networkingv1.NetworkPolicy {
+	if !w.NetworkPolicy {
+		return nil
+	}
+	var ports []networkingv1.NetworkPolicyPort
+	for _, sp := range w.servicePorts() {
+		port := intstr.FromInt32(sp.Port)
+		protocol := sp.Protocol
+		ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+	}
+	ingress := []networkingv1.NetworkPolicyIngressRule{{
+		From: []networkingv1.NetworkPolicyPeer{{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				"app.kubernetes.io/component": "network",
+				"virtwork/role":               "client",
+			}},
+		}},
+		Ports: ports,
+	}}
+	if w.NodeExporter {
+		port := intstr.FromInt32(nodeExporterPort)
+		protocol := corev1.ProtocolTCP
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+					"app.kubernetes.io/component": "monitor",
+				}},
+			}},
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &port}},
+		})
+	}
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "virtwork-iperf3-server",
+			Namespace: w.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       "virtwork",
+				"app.kubernetes.io/managed-by": "virtwork",
+				"app.kubernetes.io/component":  "network",
 			},
 		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{
+				"app.kubernetes.io/component": "network",
+				"virtwork/role":               "server",
+			}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     ingress,
+		},
 	}
 }
 
@@ -105,9 +266,16 @@ func (w *NetworkWorkload) CloudInitUserdata() (string, error) {
 	return w.UserdataForRole("server", w.Namespace)
 }
 
+// ReadinessProbe checks that the iperf3 service is active on both servers and
+// clients. Servers are not probed over TCP, since iperf3 serves one test at a
+// time and a probe connection could make a client find the server busy.
+func (w *NetworkWorkload) ReadinessProbe(string) *kubevirtv1.Probe {
+	return serviceUnit{Name: "network"}.readinessProbe()
+}
+
 // UserdataForRole returns cloud-init YAML for the given role ("server" or "client").
-// The server runs iperf3 in listen mode. The client runs bidirectional tests
-// against the server's DNS name.
+// The server runs iperf3 in listen mode. The client runs tests against the
+// server's DNS name.
 func (w *NetworkWorkload) UserdataForRole(role string, namespace string) (string, error) {
 	switch role {
 	case "server":
@@ -119,51 +287,86 @@ func (w *NetworkWorkload) UserdataForRole(role string, namespace string) (string
 	}
 }
 
+// buildServerUserdata renders the iperf3 listener. The server is passive, so
+// no warmup stage is applied to it; clients warm up instead. With several
+// clients per server, one listener runs per client port.
 func (w *NetworkWorkload) buildServerUserdata() (string, error) {
-	return w.BuildCloudConfig(CloudConfigOpts{
-		Packages: []string{"iperf3"},
-		WriteFiles: []WriteFile{
-			{
-				Path:        "/etc/systemd/system/virtwork-network.service",
-				Content:     iperf3ServerSystemdUnit,
-				Permissions: "0644",
-			},
-		},
-		RunCmd: [][]string{
-			{"systemctl", "daemon-reload"},
-			{"systemctl", "enable", "--now", "virtwork-network.service"},
-		},
-	})
+	execStart := iperf3ServerCommand
+	if w.basePort() != iperf3BasePort {
+		execStart = fmt.Sprintf("%s -p %d", iperf3ServerCommand, w.basePort())
+	}
+	if ports := w.ports(); len(ports) > 1 {
+		listeners := make([]string, len(ports))
+		for i, port := range ports {
+			listeners[i] = fmt.Sprintf("%s -p %d &", iperf3ServerCommand, port)
+		}
+		execStart = fmt.Sprintf("/bin/bash -c '%s wait'", strings.Join(listeners, " "))
+	}
+	unit := serviceUnit{
+		Name:        "network",
+		Description: "Virtwork iperf3 server",
+		ExecStart:   execStart,
+		RestartSec:  w.restartSeconds(),
+	}
+	return w.buildUserdata(unit)
 }
 
+// buildClientUserdata renders the iperf3 client loop. With several clients
+// per server, each test tries the server's listeners in turn; a listener
+// already serving another client refuses immediately, so the client moves
+// on to the next free one.
 func (w *NetworkWorkload) buildClientUserdata(namespace string) (string, error) {
 	dnsName := fmt.Sprintf("virtwork-iperf3-server.%s.svc.cluster.local", namespace)
-	clientUnit := fmt.Sprintf(`[Unit]
-Description=Virtwork iperf3 client
-After=network.target
-
-[Service]
-Type=simple
-ExecStart=/bin/bash -c 'while true; do iperf3 -c %s -t 60 -P 4 --bidir; sleep 10; done'
-Restart=always
-RestartSec=10
+	test := w.clientCommand(dnsName, 0)
+	if w.basePort() != iperf3BasePort {
+		test = w.clientCommand(dnsName, w.basePort())
+	}
+	if ports := w.ports(); len(ports) > 1 {
+		attempts := make([]string, len(ports))
+		for i, port := range ports {
+			attempts[i] = w.clientCommand(dnsName, port)
+		}
+		test = strings.Join(attempts, " || ")
+	}
+	unit := serviceUnit{
+		Name:        "network",
+		Description: "Virtwork iperf3 client",
+		ExecStart:   fmt.Sprintf("/bin/bash -c 'while true; do %s; sleep %d; done'", test, w.loopSleepSeconds()),
+		Warmup:      w.Warmup,
+		StartDelay:  w.startDelay,
+		RestartSec:  w.restartSeconds(),
+	}
+	return w.buildUserdata(unit)
+}
 
-[Install]
-WantedBy=multi-user.target
-`, dnsName)
+// clientCommand returns one iperf3 client invocation against host. A port of
+// zero leaves iperf3 on its default port.
+func (w *NetworkWorkload) clientCommand(host string, port int) string {
+	args := []string{"iperf3", "-c", host}
+	if port > 0 {
+		args = append(args, "-p", strconv.Itoa(port))
+	}
+	args = append(args, "-t", strconv.Itoa(w.runDurationSeconds(iperf3DurationSeconds)))
+	if w.udp() {
+		args = append(args, "-u")
+	}
+	if bw := w.bandwidth(); bw != "" {
+		args = append(args, "-b", bw)
+	}
+	args = append(args, "-P", strconv.Itoa(w.streams()))
+	if w.bidir() {
+		args = append(args, "--bidir")
+	}
+	return strings.Join(args, " ")
+}
 
+func (w *NetworkWorkload) buildUserdata(unit serviceUnit) (string, error) {
 	return w.BuildCloudConfig(CloudConfigOpts{
-		Packages: []string{"iperf3"},
-		WriteFiles: []WriteFile{
-			{
-				Path:        "/etc/systemd/system/virtwork-network.service",
-				Content:     clientUnit,
-				Permissions: "0644",
-			},
-		},
+		Packages:   w.RequiredPackages(),
+		WriteFiles: unit.writeFiles(),
 		RunCmd: [][]string{
 			{"systemctl", "daemon-reload"},
-			{"systemctl", "enable", "--now", "virtwork-network.service"},
+			{"systemctl", "enable", "--now", unit.serviceName()},
 		},
 	})
 }
@@ -6,6 +6,8 @@ package workloads_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 
 	"github.com/opdev/virtwork/internal/config"
 	"github.com/opdev/virtwork/internal/workloads"
@@ -130,6 +132,7 @@ var _ = Describe("NetworkWorkload", func() {
 		svc := w.ServiceSpec()
 		Expect(svc).NotTo(BeNil())
 		Expect(svc.Spec.Selector).To(HaveKeyWithValue("virtwork/role", "server"))
+		Expect(svc.Spec.Selector).To(HaveKeyWithValue("app.kubernetes.io/component", "network"))
 	})
 
 	It("should have service spec with correct name", func() {
@@ -188,4 +191,199 @@ var _ = Describe("NetworkWorkload", func() {
 	It("should implement MultiVMWorkload interface", func() {
 		var _ workloads.MultiVMWorkload = w
 	})
+
+	It("should provide a readiness probe checking the iperf3 service for both roles", func() {
+		var p workloads.Prober = w
+		for _, role := range []string{"server", "client"} {
+			probe := p.ReadinessProbe(role)
+			Expect(probe).NotTo(BeNil(), role)
+			Expect(probe.Exec).NotTo(BeNil(), role)
+			Expect(probe.TCPSocket).To(BeNil(), role)
+			Expect(probe.Exec.Command).To(Equal([]string{"systemctl", "is-active", "--quiet", "virtwork-network.service"}), role)
+		}
+	})
+
+	It("should report one server and one client per vm-count by default", func() {
+		Expect(w.RoleCounts()).To(Equal([]workloads.RoleCount{
+			{Role: "server", Count: 2},
+			{Role: "client", Count: 2},
+		}))
+	})
+
+	Context("with multiple clients per server", func() {
+		const unitPath = "/etc/systemd/system/virtwork-network.service"
+
+		BeforeEach(func() {
+			w.ClientsPerServer = 4
+		})
+
+		It("should size VMCount and RoleCounts by the ratio", func() {
+			Expect(w.VMCount()).To(Equal(10)) // 2 servers + 8 clients
+			Expect(w.RoleCounts()).To(Equal([]workloads.RoleCount{
+				{Role: "server", Count: 2},
+				{Role: "client", Count: 8},
+			}))
+		})
+
+		It("should keep role counts consistent with VMCount for a single server", func() {
+			w.Config.VMCount = 1
+			w.ClientsPerServer = 3
+
+			total := 0
+			for _, rc := range w.RoleCounts() {
+				total += rc.Count
+			}
+			Expect(total).To(Equal(w.VMCount()))
+			Expect(w.RoleCounts()[0].Count).To(Equal(1))
+			Expect(w.RoleCounts()[1].Count).To(Equal(3))
+		})
+
+		It("should run one server listener per client", func() {
+			result, err := w.UserdataForRole("server", "virtwork")
+			Expect(err).NotTo(HaveOccurred())
+
+			unit := fileContent(parseYAML(result), unitPath)
+			for _, port := range []string{"5201", "5202", "5203", "5204"} {
+				Expect(unit).To(ContainSubstring("iperf3 -s -p " + port + " &"))
+			}
+			Expect(unit).NotTo(ContainSubstring("5205"))
+		})
+
+		It("should have clients fall through to the next free listener", func() {
+			result, err := w.UserdataForRole("client", "virtwork")
+			Expect(err).NotTo(HaveOccurred())
+
+			unit := fileContent(parseYAML(result), unitPath)
+			Expect(unit).To(ContainSubstring(
+				"-p 5201 -t 60 -P 4 --bidir || iperf3 -c virtwork-iperf3-server.virtwork.svc.cluster.local -p 5202"))
+			Expect(unit).To(ContainSubstring("-p 5204 -t 60 -P 4 --bidir; sleep 10"))
+		})
+
+		It("should expose every listener port on the service", func() {
+			ports := w.ServiceSpec().Spec.Ports
+			Expect(ports).To(HaveLen(4))
+			Expect(ports[0].Name).To(Equal("iperf3"))
+			Expect(ports[0].Port).To(Equal(int32(5201)))
+			Expect(ports[3].Name).To(Equal("iperf3-3"))
+			Expect(ports[3].Port).To(Equal(int32(5204)))
+			Expect(ports[3].TargetPort.IntValue()).To(Equal(5204))
+		})
+	})
+
+	Context("with protocol and port options", func() {
+		const unitPath = "/etc/systemd/system/virtwork-network.service"
+
+		unitFor := func(role string) string {
+			result, err := w.UserdataForRole(role, "virtwork")
+			Expect(err).NotTo(HaveOccurred())
+			return fileContent(parseYAML(result), unitPath)
+		}
+
+		It("should keep the TCP defaults when no options are set", func() {
+			Expect(unitFor("server")).To(ContainSubstring("ExecStart=/usr/bin/iperf3 -s\n"))
+			Expect(unitFor("client")).To(ContainSubstring(
+				"iperf3 -c virtwork-iperf3-server.virtwork.svc.cluster.local -t 60 -P 4 --bidir;"))
+			Expect(w.ServiceSpec().Spec.Ports).To(HaveLen(1))
+			Expect(w.ServiceSpec().Spec.Ports[0].Protocol).To(Equal(corev1.ProtocolTCP))
+		})
+
+		It("should run UDP tests with a default bandwidth target", func() {
+			w.Config.Protocol = "udp"
+
+			client := unitFor("client")
+			Expect(client).To(ContainSubstring("-t 60 -u -b 1G -P 4 --bidir"))
+			Expect(unitFor("server")).To(ContainSubstring("ExecStart=/usr/bin/iperf3 -s\n"))
+		})
+
+		It("should use the configured UDP bandwidth", func() {
+			w.Config.Protocol = "UDP"
+			w.Config.Bandwidth = "500M"
+
+			Expect(unitFor("client")).To(ContainSubstring("-u -b 500M"))
+		})
+
+		It("should open TCP and UDP service ports for UDP tests", func() {
+			w.Config.Protocol = "udp"
+
+			ports := w.ServiceSpec().Spec.Ports
+			Expect(ports).To(HaveLen(2))
+			Expect(ports[0].Name).To(Equal("iperf3"))
+			Expect(ports[0].Protocol).To(Equal(corev1.ProtocolTCP))
+			Expect(ports[1].Name).To(Equal("iperf3-udp"))
+			Expect(ports[1].Protocol).To(Equal(corev1.ProtocolUDP))
+			Expect(ports[1].Port).To(Equal(int32(5201)))
+		})
+
+		It("should listen and connect on a custom port", func() {
+			w.Config.Port = 6000
+
+			Expect(unitFor("server")).To(ContainSubstring("ExecStart=/usr/bin/iperf3 -s -p 6000\n"))
+			Expect(unitFor("client")).To(ContainSubstring("svc.cluster.local -p 6000 -t 60"))
+			Expect(w.ServiceSpec().Spec.Ports[0].Port).To(Equal(int32(6000)))
+			Expect(w.ServiceSpec().Spec.Ports[0].TargetPort.IntValue()).To(Equal(6000))
+		})
+
+		It("should offset listener ports from a custom port", func() {
+			w.Config.Port = 6000
+			w.ClientsPerServer = 2
+
+			Expect(unitFor("server")).To(ContainSubstring("iperf3 -s -p 6001 &"))
+			Expect(unitFor("client")).To(ContainSubstring("-p 6000 -t 60 -P 4 --bidir || iperf3 -c"))
+			Expect(w.ServiceSpec().Spec.Ports[1].Port).To(Equal(int32(6001)))
+		})
+
+		It("should set parallel streams and allow one-way tests", func() {
+			bidir := false
+			w.Config.ParallelStreams = 8
+			w.Config.Bidir = &bidir
+
+			client := unitFor("client")
+			Expect(client).To(ContainSubstring("-t 60 -P 8;"))
+			Expect(client).NotTo(ContainSubstring("--bidir"))
+		})
+	})
+
+	Context("network policy", func() {
+		It("should have no network policy unless enabled", func() {
+			Expect(w.NetworkPolicySpec()).To(BeNil())
+		})
+
+		It("should allow ingress to servers only from clients on the iperf3 ports", func() {
+			w.NetworkPolicy = true
+
+			np := w.NetworkPolicySpec()
+			Expect(np).NotTo(BeNil())
+			Expect(np.Name).To(Equal("virtwork-iperf3-server"))
+			Expect(np.Namespace).To(Equal("virtwork"))
+			Expect(np.Spec.PodSelector.MatchLabels).To(HaveKeyWithValue("virtwork/role", "server"))
+			Expect(np.Spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}))
+			Expect(np.Spec.Ingress).To(HaveLen(1))
+
+			rule := np.Spec.Ingress[0]
+			Expect(rule.From).To(HaveLen(1))
+			Expect(rule.From[0].PodSelector.MatchLabels).To(HaveKeyWithValue("virtwork/role", "client"))
+			Expect(rule.Ports).To(HaveLen(1))
+			Expect(*rule.Ports[0].Protocol).To(Equal(corev1.ProtocolTCP))
+			Expect(rule.Ports[0].Port.IntValue()).To(Equal(5201))
+		})
+
+		It("should allow UDP and every listener port", func() {
+			w.NetworkPolicy = true
+			w.Config.Protocol = "udp"
+			w.ClientsPerServer = 2
+
+			ports := w.NetworkPolicySpec().Spec.Ingress[0].Ports
+			Expect(ports).To(HaveLen(len(w.ServiceSpec().Spec.Ports)))
+			Expect(ports).To(ContainElement(HaveField("Protocol", HaveValue(Equal(corev1.ProtocolUDP)))))
+		})
+
+		It("should let the monitor scrape node_exporter", func() {
+			w.NetworkPolicy = true
+			w.NodeExporter = true
+
+			ingress := w.NetworkPolicySpec().Spec.Ingress
+			Expect(ingress).To(HaveLen(2))
+			Expect(ingress[1].Ports[0].Port.IntValue()).To(Equal(9100))
+		})
+	})
 })
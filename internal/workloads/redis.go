@@ -0,0 +1,101 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads
+
+import (
+	"fmt"
+
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/opdev/virtwork/internal/config"
+)
+
+// redisSetupScript keeps the Redis server on localhost and turns off
+// persistence, so the benchmark exercises the in-memory cache rather than the
+// root disk. It runs once from runcmd, before Redis is first started.
+const redisSetupScript = `#!/bin/bash
+set -euo pipefail
+
+CONF="/etc/redis/redis.conf"
+
+# Listen on localhost only; the benchmark runs in the same VM
+sed -i -e 's/^bind .*/bind 127.0.0.1 -::1/' -e 's/^protected-mode .*/protected-mode yes/' "${CONF}"
+
+# Disable RDB snapshots and the append-only file
+sed -i -e '/^save /d' -e 's/^appendonly .*/appendonly no/' "${CONF}"
+echo 'save ""' >> "${CONF}"
+`
+
+// redisBenchLoopCommand is formatted with the seconds each memtier_benchmark
+// run lasts and the seconds to sleep between runs.
+const redisBenchLoopCommand = `/bin/bash -c 'while true; do memtier_benchmark -s 127.0.0.1 -p 6379 --protocol=redis -t 2 -c 25 --ratio=1:10 --test-time=%d --hide-histogram; sleep %d; done'`
+
+// RedisWorkload generates cloud-init userdata for a Redis cache benchmark
+// workload using memtier_benchmark. Redis listens on localhost only and keeps
+// no persistence, and a systemd service runs continuous memtier_benchmark
+// loops against it.
+type RedisWorkload struct {
+	BaseWorkload
+}
+
+// NewRedisWorkload creates a RedisWorkload with the given configuration and SSH credentials.
+func NewRedisWorkload(cfg config.WorkloadConfig, sshUser, sshPassword string, sshKeys []string) *RedisWorkload {
+	return &RedisWorkload{
+		BaseWorkload: BaseWorkload{
+			Config:            cfg,
+			SSHUser:           sshUser,
+			SSHPassword:       sshPassword,
+			SSHAuthorizedKeys: sshKeys,
+		},
+	}
+}
+
+// Name returns "redis".
+func (w *RedisWorkload) Name() string {
+	return "redis"
+}
+
+// RequiredPackages returns Redis and memtier_benchmark.
+func (w *RedisWorkload) RequiredPackages() []string {
+	return []string{"redis", "memtier-benchmark"}
+}
+
+// CloudInitUserdata returns cloud-init YAML that installs Redis and
+// memtier_benchmark, configures Redis for a local in-memory benchmark, and
+// creates a systemd service that runs continuous memtier_benchmark loops.
+func (w *RedisWorkload) CloudInitUserdata() (string, error) {
+	unit := serviceUnit{
+		Name:        "redis",
+		Description: "Virtwork Redis cache benchmark workload",
+		After:       []string{"network.target", "redis.service"},
+		Requires:    []string{"redis.service"},
+		ExecStart:   fmt.Sprintf(redisBenchLoopCommand, w.runDurationSeconds(defaultRunDurationSeconds), w.loopSleepSeconds()),
+		Warmup:      w.Warmup,
+		StartDelay:  w.startDelay,
+		RestartSec:  w.restartSeconds(),
+	}
+	files := []WriteFile{
+		{
+			Path:        "/usr/local/bin/virtwork-redis-setup.sh",
+			Content:     redisSetupScript,
+			Permissions: "0755",
+		},
+	}
+	return w.BuildCloudConfig(CloudConfigOpts{
+		Packages:   w.RequiredPackages(),
+		WriteFiles: append(files, unit.writeFiles()...),
+		RunCmd: [][]string{
+			{"/usr/local/bin/virtwork-redis-setup.sh"},
+			{"systemctl", "daemon-reload"},
+			{"systemctl", "enable", "--now", "redis"},
+			{"systemctl", "enable", "--now", unit.serviceName()},
+		},
+	})
+}
+
+// ReadinessProbe checks that the memtier_benchmark service is active, which it
+// only becomes once Redis is running.
+func (w *RedisWorkload) ReadinessProbe(string) *kubevirtv1.Probe {
+	return serviceUnit{Name: "redis"}.readinessProbe()
+}
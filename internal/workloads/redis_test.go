@@ -0,0 +1,107 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+var _ = Describe("RedisWorkload", func() {
+	var w *workloads.RedisWorkload
+
+	BeforeEach(func() {
+		w = workloads.NewRedisWorkload(config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "4Gi",
+		}, "virtwork", "", nil)
+	})
+
+	It("should return 'redis' for Name", func() {
+		Expect(w.Name()).To(Equal("redis"))
+	})
+
+	It("should include redis and memtier-benchmark in packages", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		pkgs, ok := parsed["packages"].([]interface{})
+		Expect(ok).To(BeTrue())
+		Expect(pkgs).To(ContainElements("redis", "memtier-benchmark"))
+	})
+
+	It("should include a setup script that keeps Redis local and in memory", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		setup := fileContent(parseYAML(result), "/usr/local/bin/virtwork-redis-setup.sh")
+		Expect(setup).NotTo(BeEmpty())
+		Expect(setup).To(ContainSubstring("bind 127.0.0.1"))
+		Expect(setup).To(ContainSubstring("appendonly no"))
+		Expect(setup).To(ContainSubstring(`save ""`))
+	})
+
+	It("should run the setup script before starting Redis", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		runcmd := parsed["runcmd"].([]interface{})
+		Expect(runcmd[0]).To(Equal([]interface{}{"/usr/local/bin/virtwork-redis-setup.sh"}))
+		Expect(runcmd).To(ContainElement([]interface{}{"systemctl", "enable", "--now", "redis"}))
+		Expect(runcmd).To(ContainElement([]interface{}{"systemctl", "enable", "--now", "virtwork-redis.service"}))
+	})
+
+	It("should include a memtier_benchmark loop against localhost in the systemd service", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		service := fileContent(parseYAML(result), "/etc/systemd/system/virtwork-redis.service")
+		Expect(service).NotTo(BeEmpty())
+		Expect(service).To(ContainSubstring("memtier_benchmark"))
+		Expect(service).To(ContainSubstring("-s 127.0.0.1 -p 6379"))
+		Expect(service).To(ContainSubstring("--test-time=300"))
+		Expect(service).To(ContainSubstring("Requires=redis.service"))
+	})
+
+	It("should provide a readiness probe checking the memtier service", func() {
+		var p workloads.Prober = w
+		probe := p.ReadinessProbe("")
+		Expect(probe).NotTo(BeNil())
+		Expect(probe.Exec).NotTo(BeNil())
+		Expect(probe.Exec.Command).To(Equal([]string{"systemctl", "is-active", "--quiet", "virtwork-redis.service"}))
+	})
+
+	It("should produce valid YAML", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(HavePrefix("#cloud-config\n"))
+
+		parsed := parseYAML(result)
+		Expect(parsed).NotTo(BeNil())
+	})
+
+	It("should not require service", func() {
+		Expect(w.RequiresService()).To(BeFalse())
+		Expect(w.ServiceSpec()).To(BeNil())
+	})
+
+	It("should have no data volumes", func() {
+		Expect(w.DataVolumeTemplates()).To(BeEmpty())
+		Expect(w.ExtraDisks()).To(BeEmpty())
+		Expect(w.ExtraVolumes()).To(BeEmpty())
+	})
+
+	It("should reflect config in VMResources", func() {
+		res := w.VMResources()
+		Expect(res.CPUCores).To(Equal(2))
+		Expect(res.Memory).To(Equal("4Gi"))
+	})
+})
@@ -7,9 +7,14 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
 	"github.com/opdev/virtwork/internal/config"
 	"github.com/opdev/virtwork/internal/constants"
+	"github.com/opdev/virtwork/internal/vm"
 )
 
 // RegistryOpts holds optional parameters for workload construction.
@@ -20,6 +25,24 @@ type RegistryOpts struct {
 	SSHUser           string
 	SSHPassword       string
 	SSHAuthorizedKeys []string
+	Warmup            time.Duration
+	Stagger           time.Duration
+	SeedSQL           string
+	ClientsPerServer  int
+	CustomUserdata    string
+	CustomName        string
+	DataSourceURL     string
+	DataSourcePVC     string
+	DataFSPVC         string
+	StorageClass      string
+	AccessMode        string
+	SyslogServer      string
+	RunID             string
+	NodeExporter      bool
+	ReadOnlyRoot      bool
+	NetworkPolicy     bool
+	Overlay           *CloudConfigOpts
+	OverlayOverride   bool
 }
 
 // Option is a functional option for workload construction.
@@ -44,18 +67,159 @@ func WithDataDiskSize(size string) Option {
 	return func(o *RegistryOpts) { o.DataDiskSize = size }
 }
 
+// WithWarmup sets how long each workload runs before its measured loop starts.
+func WithWarmup(d time.Duration) Option {
+	return func(o *RegistryOpts) { o.Warmup = d }
+}
+
+// WithStagger sets the window over which the workload's VMs start it.
+func WithStagger(d time.Duration) Option {
+	return func(o *RegistryOpts) { o.Stagger = d }
+}
+
+// WithSeedSQL sets the SQL the database workload applies before benchmarking.
+func WithSeedSQL(sql string) Option {
+	return func(o *RegistryOpts) { o.SeedSQL = sql }
+}
+
+// WithDataSource populates the data disk of the disk and database workloads
+// from a registry image URL or an existing PVC ("name" or "namespace/name",
+// defaulting to the workload namespace) instead of creating it blank. At most
+// one of url and pvc should be set.
+func WithDataSource(url, pvc string) Option {
+	return func(o *RegistryOpts) {
+		o.DataSourceURL = url
+		o.DataSourcePVC = pvc
+	}
+}
+
+// dataSource returns the data disk source selected by WithDataSource, or nil
+// for a blank disk.
+func (o *RegistryOpts) dataSource() *cdiv1beta1.DataVolumeSource {
+	switch {
+	case o.DataSourceURL != "":
+		source := vm.RegistrySource(o.DataSourceURL)
+		return &source
+	case o.DataSourcePVC != "":
+		namespace, name, found := strings.Cut(o.DataSourcePVC, "/")
+		if !found {
+			namespace, name = o.Namespace, o.DataSourcePVC
+		}
+		source := vm.PVCSource(namespace, name)
+		return &source
+	}
+	return nil
+}
+
+// WithDataFilesystem replaces the data disk of the disk and database
+// workloads with a virtiofs filesystem backed by the PVC named pvc in the
+// workload namespace. Empty keeps the data disk.
+func WithDataFilesystem(pvc string) Option {
+	return func(o *RegistryOpts) { o.DataFSPVC = pvc }
+}
+
+// WithStorageClass sets the StorageClass and access mode of the data disks
+// created by the disk, database, and fs workloads. Empty values leave the choice
+// to the cluster's default StorageClass.
+func WithStorageClass(storageClass, accessMode string) Option {
+	return func(o *RegistryOpts) {
+		o.StorageClass = storageClass
+		o.AccessMode = accessMode
+	}
+}
+
+// storage returns the data disk storage settings selected by WithStorageClass.
+func (o *RegistryOpts) storage() vm.DataVolumeOpts {
+	var opts vm.DataVolumeOpts
+	if o.StorageClass != "" {
+		storageClass := o.StorageClass
+		opts.StorageClassName = &storageClass
+	}
+	if o.AccessMode != "" {
+		opts.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.PersistentVolumeAccessMode(o.AccessMode)}
+	}
+	return opts
+}
+
+// WithClientsPerServer sets how many network clients are created per server.
+func WithClientsPerServer(n int) Option {
+	return func(o *RegistryOpts) { o.ClientsPerServer = n }
+}
+
+// WithCustomUserdata registers a CustomWorkload under name (DefaultCustomName
+// when empty) that runs the userdata at path. It only takes effect when
+// passed to DefaultRegistry.
+func WithCustomUserdata(path, name string) Option {
+	return func(o *RegistryOpts) {
+		o.CustomUserdata = path
+		o.CustomName = name
+	}
+}
+
+// WithSyslogServer forwards the guest logs of every workload to the syslog
+// server at addr (host:port). An empty addr leaves forwarding off.
+func WithSyslogServer(addr string) Option {
+	return func(o *RegistryOpts) { o.SyslogServer = addr }
+}
+
+// WithMonitoring prepares a run that includes the monitor workload: every
+// workload's VMs run node_exporter, and the monitor scrapes the VMs labeled
+// with runID.
+func WithMonitoring(runID string) Option {
+	return func(o *RegistryOpts) {
+		o.RunID = runID
+		o.NodeExporter = true
+	}
+}
+
+// WithReadOnlyRoot boots every workload's guests with a read-only root
+// filesystem under an ephemeral overlay.
+func WithReadOnlyRoot(enabled bool) Option {
+	return func(o *RegistryOpts) { o.ReadOnlyRoot = enabled }
+}
+
+// WithNetworkPolicy has the network workload confine ingress to its server
+// VMs to its client VMs with a NetworkPolicy.
+func WithNetworkPolicy(enabled bool) Option {
+	return func(o *RegistryOpts) { o.NetworkPolicy = enabled }
+}
+
+// WithCloudInitOverlay merges overlay into every workload's cloud-config,
+// keeping the workload's scalar values unless override is set. A nil overlay
+// leaves the cloud-config unchanged.
+func WithCloudInitOverlay(overlay *CloudConfigOpts, override bool) Option {
+	return func(o *RegistryOpts) {
+		o.Overlay = overlay
+		o.OverlayOverride = override
+	}
+}
+
 // WorkloadFactory creates a Workload from a WorkloadConfig and resolved options.
 type WorkloadFactory func(config.WorkloadConfig, *RegistryOpts) Workload
 
 // Registry maps workload names to their factory functions.
 type Registry map[string]WorkloadFactory
 
-// AllWorkloadNames is a sorted list of all built-in workload names.
-var AllWorkloadNames = []string{"cpu", "database", "disk", "memory", "network"}
+// AllWorkloadNames is a sorted list of the built-in workloads deployed by
+// default. The api-churn workload loads the cluster's control plane rather
+// than its nodes, and the monitor workload observes the others rather than
+// loading anything, so both are registered but left out; select them with
+// --workloads.
+var AllWorkloadNames = []string{"cpu", "database", "disk", "fs", "memory", "network", "redis", "web"}
+
+// DefaultCustomName is the name a CustomWorkload is registered under when
+// WithCustomUserdata is given no name.
+const DefaultCustomName = "custom"
 
-// DefaultRegistry returns a Registry pre-populated with all built-in workloads.
-func DefaultRegistry() Registry {
-	return Registry{
+// DefaultRegistry returns a Registry pre-populated with all built-in workloads,
+// plus a CustomWorkload if WithCustomUserdata is among opts.
+func DefaultRegistry(opts ...Option) Registry {
+	resolved := &RegistryOpts{}
+	for _, opt := range opts {
+		opt(resolved)
+	}
+
+	r := Registry{
 		"cpu": func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
 			return NewCPUWorkload(cfg, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
 		},
@@ -63,15 +227,58 @@ func DefaultRegistry() Registry {
 			return NewMemoryWorkload(cfg, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
 		},
 		"disk": func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
-			return NewDiskWorkload(cfg, opts.DataDiskSize, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+			w := NewDiskWorkload(cfg, opts.DataDiskSize, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+			w.DataSource = opts.dataSource()
+			w.Storage = opts.storage()
+			w.DataFSPVC = opts.DataFSPVC
+			return w
 		},
 		"database": func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
-			return NewDatabaseWorkload(cfg, opts.DataDiskSize, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+			w := NewDatabaseWorkload(cfg, opts.DataDiskSize, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+			w.SeedSQL = opts.SeedSQL
+			w.DataSource = opts.dataSource()
+			w.Storage = opts.storage()
+			w.DataFSPVC = opts.DataFSPVC
+			return w
+		},
+		"fs": func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
+			w := NewFilesystemWorkload(cfg, opts.DataDiskSize, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+			w.Storage = opts.storage()
+			return w
 		},
 		"network": func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
-			return NewNetworkWorkload(cfg, opts.Namespace, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+			w := NewNetworkWorkload(cfg, opts.Namespace, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+			w.ClientsPerServer = opts.ClientsPerServer
+			w.NetworkPolicy = opts.NetworkPolicy
+			return w
+		},
+		"redis": func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
+			return NewRedisWorkload(cfg, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+		},
+		"web": func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
+			return NewWebWorkload(cfg, opts.Namespace, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+		},
+		"api-churn": func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
+			return NewAPIChurnWorkload(cfg, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
 		},
+		"monitor": func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
+			w := NewMonitorWorkload(cfg, opts.Namespace, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+			w.RunID = opts.RunID
+			return w
+		},
+	}
+
+	if resolved.CustomUserdata != "" {
+		name := resolved.CustomName
+		if name == "" {
+			name = DefaultCustomName
+		}
+		source := resolved.CustomUserdata
+		r[name] = func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
+			return NewCustomWorkload(name, source, cfg, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+		}
 	}
+	return r
 }
 
 // Get retrieves a workload by name, constructing it with the given config and options.
@@ -89,7 +296,33 @@ func (r Registry) Get(name string, cfg config.WorkloadConfig, opts ...Option) (W
 		opt(resolved)
 	}
 
-	return factory(cfg, resolved), nil
+	w := factory(cfg, resolved)
+	if b, ok := w.(interface{ base() *BaseWorkload }); ok {
+		b.base().Warmup = resolved.Warmup
+		b.base().Stagger = resolved.Stagger
+		b.base().SyslogServer = resolved.SyslogServer
+		b.base().NodeExporter = resolved.NodeExporter
+		b.base().ReadOnlyRoot = resolved.ReadOnlyRoot
+		b.base().Overlay = resolved.Overlay
+		b.base().OverlayOverride = resolved.OverlayOverride
+	}
+	return w, nil
+}
+
+// RegisterTemplates adds a factory for each config-defined workload template.
+// Templates are rendered against values when their userdata is generated.
+// A template may not shadow an already-registered workload.
+func (r Registry) RegisterTemplates(templates map[string]config.WorkloadTemplate, values map[string]interface{}) error {
+	for name, tmpl := range templates {
+		if _, exists := r[name]; exists {
+			return fmt.Errorf("custom workload %q conflicts with an existing workload", name)
+		}
+		name, tmpl := name, tmpl
+		r[name] = func(cfg config.WorkloadConfig, opts *RegistryOpts) Workload {
+			return NewTemplateWorkload(name, cfg, tmpl, values, opts.SSHUser, opts.SSHPassword, opts.SSHAuthorizedKeys)
+		}
+	}
+	return nil
 }
 
 // List returns all registered workload names in sorted order.
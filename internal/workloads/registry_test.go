@@ -7,6 +7,9 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/opdev/virtwork/internal/cloudinit"
 	"github.com/opdev/virtwork/internal/config"
 	"github.com/opdev/virtwork/internal/workloads"
 )
@@ -18,10 +21,69 @@ var _ = Describe("Registry", func() {
 		reg = workloads.DefaultRegistry()
 	})
 
-	It("should have 5 entries registered", func() {
-		Expect(reg.List()).To(HaveLen(5))
+	It("should have 10 entries registered", func() {
+		Expect(reg.List()).To(HaveLen(10))
 	})
 
+	// userdataPackages returns the packages installed by the userdata of
+	// every role of w.
+	userdataPackages := func(w workloads.Workload) []interface{} {
+		var userdata []string
+		if multiVM, ok := w.(workloads.MultiVMWorkload); ok {
+			for _, rc := range multiVM.RoleCounts() {
+				ud, err := multiVM.UserdataForRole(rc.Role, "virtwork")
+				Expect(err).NotTo(HaveOccurred())
+				userdata = append(userdata, ud)
+			}
+		} else {
+			ud, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+			userdata = append(userdata, ud)
+		}
+		var pkgs []interface{}
+		for _, ud := range userdata {
+			if p, ok := parseYAML(ud)["packages"].([]interface{}); ok {
+				pkgs = append(pkgs, p...)
+			}
+		}
+		return pkgs
+	}
+
+	DescribeTable("should report the packages each workload installs",
+		func(name string, expected ...string) {
+			w, err := reg.Get(name, config.WorkloadConfig{
+				Enabled:  true,
+				VMCount:  1,
+				CPUCores: 2,
+				Memory:   "2Gi",
+			}, workloads.WithNamespace("virtwork"))
+			Expect(err).NotTo(HaveOccurred())
+
+			if len(expected) == 0 {
+				Expect(w.RequiredPackages()).To(BeEmpty())
+			} else {
+				Expect(w.RequiredPackages()).To(Equal(expected))
+			}
+			pkgs := userdataPackages(w)
+			for _, pkg := range expected {
+				Expect(pkgs).To(ContainElement(pkg))
+			}
+			for _, pkg := range pkgs {
+				Expect(expected).To(ContainElement(pkg))
+			}
+		},
+		Entry("cpu", "cpu", "stress-ng"),
+		Entry("memory", "memory", "stress-ng"),
+		Entry("disk", "disk", "fio"),
+		Entry("fs", "fs"),
+		Entry("database", "database", "postgresql-server"),
+		Entry("network", "network", "iperf3"),
+		Entry("redis", "redis", "redis", "memtier-benchmark"),
+		Entry("web", "web", "nginx", "wrk"),
+		Entry("api-churn", "api-churn", "curl"),
+		Entry("monitor", "monitor", "golang-github-prometheus"),
+	)
+
 	It("should return CPU workload by name", func() {
 		w, err := reg.Get("cpu", config.WorkloadConfig{
 			Enabled:  true,
@@ -77,6 +139,41 @@ var _ = Describe("Registry", func() {
 		Expect(w.Name()).To(Equal("disk"))
 	})
 
+	It("should return redis workload by name", func() {
+		w, err := reg.Get("redis", config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Name()).To(Equal("redis"))
+	})
+
+	It("should return web workload by name", func() {
+		w, err := reg.Get("web", config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}, workloads.WithNamespace("virtwork"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Name()).To(Equal("web"))
+		Expect(w.ServiceSpec().Namespace).To(Equal("virtwork"))
+	})
+
+	It("should return api-churn workload by name", func() {
+		w, err := reg.Get("api-churn", config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Name()).To(Equal("api-churn"))
+		Expect(w).To(BeAssignableToTypeOf(&workloads.APIChurnWorkload{}))
+	})
+
 	It("should return error for unknown name with available names", func() {
 		_, err := reg.Get("unknown", config.WorkloadConfig{})
 		Expect(err).To(HaveOccurred())
@@ -86,11 +183,14 @@ var _ = Describe("Registry", func() {
 		Expect(err.Error()).To(ContainSubstring("disk"))
 		Expect(err.Error()).To(ContainSubstring("memory"))
 		Expect(err.Error()).To(ContainSubstring("network"))
+		Expect(err.Error()).To(ContainSubstring("redis"))
+		Expect(err.Error()).To(ContainSubstring("web"))
+		Expect(err.Error()).To(ContainSubstring("api-churn"))
 	})
 
 	It("should list all names sorted alphabetically", func() {
 		names := reg.List()
-		Expect(names).To(Equal([]string{"cpu", "database", "disk", "memory", "network"}))
+		Expect(names).To(Equal([]string{"api-churn", "cpu", "database", "disk", "fs", "memory", "monitor", "network", "redis", "web"}))
 	})
 
 	It("should create workloads with provided config", func() {
@@ -154,10 +254,255 @@ var _ = Describe("Registry", func() {
 		dvts := w.DataVolumeTemplates()
 		Expect(dvts).NotTo(BeEmpty())
 	})
+	It("should pass the syslog server via options", func() {
+		w, err := reg.Get("memory", config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}, workloads.WithSyslogServer("logs.example.com:514"))
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		conf := fileContent(parseYAML(result), "/etc/rsyslog.d/90-virtwork-forward.conf")
+		Expect(conf).To(ContainSubstring(`target="logs.example.com" port="514"`))
+	})
+
+	It("should pass a registry data source to the database workload", func() {
+		w, err := reg.Get("database", config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "4Gi",
+		}, workloads.WithDataSource("docker://quay.io/example/pgbench-data:latest", ""))
+		Expect(err).NotTo(HaveOccurred())
+
+		source := w.DataVolumeTemplates()[0].Spec.Source
+		Expect(source.Registry).NotTo(BeNil())
+		Expect(*source.Registry.URL).To(Equal("docker://quay.io/example/pgbench-data:latest"))
+	})
+
+	It("should default a data source PVC to the workload namespace", func() {
+		w, err := reg.Get("disk", config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}, workloads.WithNamespace("virtwork"), workloads.WithDataSource("", "fio-seed"))
+		Expect(err).NotTo(HaveOccurred())
+
+		pvc := w.DataVolumeTemplates()[0].Spec.Source.PVC
+		Expect(pvc).NotTo(BeNil())
+		Expect(pvc.Namespace).To(Equal("virtwork"))
+		Expect(pvc.Name).To(Equal("fio-seed"))
+	})
+
+	It("should accept a namespace-qualified data source PVC", func() {
+		w, err := reg.Get("disk", config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}, workloads.WithNamespace("virtwork"), workloads.WithDataSource("", "golden/fio-seed"))
+		Expect(err).NotTo(HaveOccurred())
+
+		pvc := w.DataVolumeTemplates()[0].Spec.Source.PVC
+		Expect(pvc.Namespace).To(Equal("golden"))
+		Expect(pvc.Name).To(Equal("fio-seed"))
+	})
+
+	It("should pass the data filesystem PVC to the disk and database workloads", func() {
+		for _, name := range []string{"disk", "database"} {
+			w, err := reg.Get(name, config.WorkloadConfig{
+				Enabled:  true,
+				VMCount:  1,
+				CPUCores: 2,
+				Memory:   "4Gi",
+			}, workloads.WithDataFilesystem("shared-data"))
+			Expect(err).NotTo(HaveOccurred())
+
+			fsUser, ok := w.(workloads.FilesystemUser)
+			Expect(ok).To(BeTrue(), name)
+			Expect(fsUser.Filesystems()).To(HaveLen(1), name)
+			Expect(w.ExtraVolumes()[0].PersistentVolumeClaim.ClaimName).To(Equal("shared-data"), name)
+			Expect(w.DataVolumeTemplates()).To(BeEmpty(), name)
+		}
+	})
+
+	It("should provide readiness probes only for the service-backed workloads", func() {
+		for _, name := range workloads.AllWorkloadNames {
+			w, err := reg.Get(name, config.WorkloadConfig{
+				Enabled:  true,
+				VMCount:  1,
+				CPUCores: 2,
+				Memory:   "2Gi",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, isProber := w.(workloads.Prober)
+			switch name {
+			case "database", "disk", "fs", "network", "redis", "web":
+				Expect(isProber).To(BeTrue(), name)
+			default:
+				Expect(isProber).To(BeFalse(), name)
+			}
+		}
+	})
+
+	It("should leave data disk storage class unset by default", func() {
+		w, err := reg.Get("disk", config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		storage := w.DataVolumeTemplates()[0].Spec.Storage
+		Expect(storage.StorageClassName).To(BeNil())
+		Expect(storage.AccessModes).To(BeNil())
+	})
+
+	It("should pass storage class and access mode to the data disk workloads", func() {
+		for _, name := range []string{"disk", "database", "fs"} {
+			w, err := reg.Get(name, config.WorkloadConfig{
+				Enabled:  true,
+				VMCount:  1,
+				CPUCores: 2,
+				Memory:   "4Gi",
+			}, workloads.WithStorageClass("lvms-vg1", "ReadWriteOnce"))
+			Expect(err).NotTo(HaveOccurred())
+
+			storage := w.DataVolumeTemplates()[0].Spec.Storage
+			Expect(storage.StorageClassName).NotTo(BeNil())
+			Expect(*storage.StorageClassName).To(Equal("lvms-vg1"))
+			Expect(storage.AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+		}
+	})
+
+	It("should install node_exporter on every workload only with monitoring", func() {
+		cfg := config.WorkloadConfig{Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi"}
+
+		w, err := reg.Get("cpu", cfg)
+		Expect(err).NotTo(HaveOccurred())
+		userdata, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userdata).NotTo(ContainSubstring("prometheus-node-exporter"))
+
+		for _, name := range []string{"cpu", "database", "monitor"} {
+			w, err := reg.Get(name, cfg, workloads.WithMonitoring("run-1"))
+			Expect(err).NotTo(HaveOccurred())
+			userdata, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+			parsed := parseYAML(userdata)
+			Expect(parsed["packages"]).To(ContainElement("golang-github-prometheus-node-exporter"), name)
+			Expect(parsed["runcmd"]).To(ContainElement([]interface{}{"systemctl", "enable", "--now", "prometheus-node-exporter.service"}), name)
+		}
+	})
+
+	It("should boot every workload with a read-only root only when set", func() {
+		cfg := config.WorkloadConfig{Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi"}
+
+		w, err := reg.Get("cpu", cfg)
+		Expect(err).NotTo(HaveOccurred())
+		userdata, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userdata).NotTo(ContainSubstring("systemd.volatile"))
+
+		for _, name := range []string{"cpu", "disk", "monitor"} {
+			w, err := reg.Get(name, cfg, workloads.WithReadOnlyRoot(true))
+			Expect(err).NotTo(HaveOccurred())
+			userdata, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parseYAML(userdata)["runcmd"]).To(ContainElement(
+				[]interface{}{"grubby", "--update-kernel=ALL", "--args=systemd.volatile=overlay"}), name)
+		}
+	})
+
+	It("should merge a cloud-init overlay into every workload", func() {
+		cfg := config.WorkloadConfig{Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi"}
+		overlay := &workloads.CloudConfigOpts{
+			Packages: []string{"htop"},
+			RunCmd:   [][]string{{"touch", "/run/overlay"}},
+		}
+
+		for _, name := range []string{"cpu", "disk", "web"} {
+			w, err := reg.Get(name, cfg, workloads.WithCloudInitOverlay(overlay, false))
+			Expect(err).NotTo(HaveOccurred())
+			userdata, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+			parsed := parseYAML(userdata)
+			Expect(parsed["packages"]).To(ContainElement("htop"), name)
+			runcmd := parsed["runcmd"].([]interface{})
+			Expect(runcmd[len(runcmd)-1]).To(Equal([]interface{}{"touch", "/run/overlay"}), name)
+			Expect(parsed["final_message"]).To(Equal(cloudinit.CompletionSentinel), name)
+		}
+	})
+
+	It("should give the network workload a network policy only when set", func() {
+		cfg := config.WorkloadConfig{Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi"}
+
+		w, err := reg.Get("network", cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.(workloads.NetworkPolicyProvider).NetworkPolicySpec()).To(BeNil())
+
+		w, err = reg.Get("network", cfg, workloads.WithNetworkPolicy(true))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.(workloads.NetworkPolicyProvider).NetworkPolicySpec()).NotTo(BeNil())
+	})
+
+	It("should keep the data of disk-backed workloads on their data disk", func() {
+		cfg := config.WorkloadConfig{Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi"}
+		dataDirs := map[string]string{"database": "/var/lib/pgsql/data", "disk": "/mnt/data", "fs": "/mnt/fs"}
+		for name, dir := range dataDirs {
+			w, err := reg.Get(name, cfg, workloads.WithReadOnlyRoot(true))
+			Expect(err).NotTo(HaveOccurred())
+			_, writesRoot := w.(workloads.RootWriter)
+			Expect(writesRoot).To(BeFalse(), name)
+			userdata, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parseYAML(userdata)["mounts"]).To(ContainElement(
+				[]interface{}{"/dev/vdc", dir, "xfs", "defaults", "0", "0"}), name)
+		}
+
+		w, err := reg.Get("monitor", cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.(workloads.RootWriter).RootWrites()).To(Equal([]string{"/var/lib/virtwork-prometheus"}))
+	})
+
+	It("should pass the run ID to the monitor workload", func() {
+		w, err := reg.Get("monitor", config.WorkloadConfig{Enabled: true, VMCount: 1, CPUCores: 2, Memory: "2Gi"},
+			workloads.WithMonitoring("run-1"))
+		Expect(err).NotTo(HaveOccurred())
+
+		svc := w.(*workloads.MonitorWorkload).ServiceSpec()
+		Expect(svc.Spec.Selector).To(HaveKeyWithValue("virtwork/run-id", "run-1"))
+	})
+
+	It("should pass clients per server to network workload", func() {
+		w, err := reg.Get("network", config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}, workloads.WithClientsPerServer(4))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.VMCount()).To(Equal(5))
+	})
 })
 
 var _ = Describe("AllWorkloadNames", func() {
 	It("should contain all five workload names sorted", func() {
-		Expect(workloads.AllWorkloadNames).To(Equal([]string{"cpu", "database", "disk", "memory", "network"}))
+		Expect(workloads.AllWorkloadNames).To(Equal([]string{"cpu", "database", "disk", "fs", "memory", "network", "redis", "web"}))
+	})
+
+	It("should leave the opt-in api-churn workload out", func() {
+		Expect(workloads.AllWorkloadNames).NotTo(ContainElement("api-churn"))
+	})
+
+	It("should leave the opt-in monitor workload out", func() {
+		Expect(workloads.AllWorkloadNames).NotTo(ContainElement("monitor"))
 	})
 })
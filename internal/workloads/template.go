@@ -0,0 +1,122 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/opdev/virtwork/internal/config"
+)
+
+// TemplateWorkload is a workload defined in the config file rather than in
+// code. Its packages, files, and commands are Go templates rendered against
+// user-supplied values, so one definition can be reused with different
+// parameters.
+type TemplateWorkload struct {
+	BaseWorkload
+	name     string
+	template config.WorkloadTemplate
+	values   map[string]interface{}
+}
+
+// NewTemplateWorkload creates a TemplateWorkload with the given name,
+// definition, template values, and SSH credentials.
+func NewTemplateWorkload(name string, cfg config.WorkloadConfig, tmpl config.WorkloadTemplate, values map[string]interface{}, sshUser, sshPassword string, sshKeys []string) *TemplateWorkload {
+	return &TemplateWorkload{
+		BaseWorkload: BaseWorkload{
+			Config:            cfg,
+			SSHUser:           sshUser,
+			SSHPassword:       sshPassword,
+			SSHAuthorizedKeys: sshKeys,
+		},
+		name:     name,
+		template: tmpl,
+		values:   values,
+	}
+}
+
+// Name returns the name the workload was defined under in the config file.
+func (w *TemplateWorkload) Name() string {
+	return w.name
+}
+
+// RequiredPackages returns the definition's packages rendered against the
+// values. If any fails to render, the unrendered packages are returned; the
+// error is reported by CloudInitUserdata.
+func (w *TemplateWorkload) RequiredPackages() []string {
+	packages, err := w.packages()
+	if err != nil {
+		return w.template.Packages
+	}
+	return packages
+}
+
+// packages renders the definition's packages against the values.
+func (w *TemplateWorkload) packages() ([]string, error) {
+	var packages []string
+	for i, pkg := range w.template.Packages {
+		rendered, err := w.render(fmt.Sprintf("packages[%d]", i), pkg)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, rendered)
+	}
+	return packages, nil
+}
+
+// CloudInitUserdata renders every templated field against the values and
+// returns the resulting cloud-init YAML. Commands run via /bin/sh in runcmd.
+// A reference to a value that was not supplied is an error.
+func (w *TemplateWorkload) CloudInitUserdata() (string, error) {
+	packages, err := w.packages()
+	if err != nil {
+		return "", err
+	}
+	opts := CloudConfigOpts{Packages: packages}
+
+	for i, f := range w.template.WriteFiles {
+		path, err := w.render(fmt.Sprintf("write-files[%d].path", i), f.Path)
+		if err != nil {
+			return "", err
+		}
+		content, err := w.render(fmt.Sprintf("write-files[%d].content", i), f.Content)
+		if err != nil {
+			return "", err
+		}
+		perms := f.Permissions
+		if perms == "" {
+			perms = "0644"
+		}
+		opts.WriteFiles = append(opts.WriteFiles, WriteFile{
+			Path:        path,
+			Content:     content,
+			Permissions: perms,
+		})
+	}
+
+	for i, command := range w.template.Commands {
+		rendered, err := w.render(fmt.Sprintf("commands[%d]", i), command)
+		if err != nil {
+			return "", err
+		}
+		opts.RunCmd = append(opts.RunCmd, []string{"/bin/sh", "-c", rendered})
+	}
+
+	return w.BuildCloudConfig(opts)
+}
+
+// render executes a single templated field against the workload's values.
+func (w *TemplateWorkload) render(field, text string) (string, error) {
+	tmpl, err := template.New(field).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s of custom workload %q: %w", field, w.name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, w.values); err != nil {
+		return "", fmt.Errorf("rendering %s of custom workload %q: %w", field, w.name, err)
+	}
+	return buf.String(), nil
+}
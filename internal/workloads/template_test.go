@@ -0,0 +1,130 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+var _ = Describe("TemplateWorkload", func() {
+	var (
+		wlCfg config.WorkloadConfig
+		tmpl  config.WorkloadTemplate
+	)
+
+	BeforeEach(func() {
+		wlCfg = config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}
+		tmpl = config.WorkloadTemplate{
+			Packages: []string{"{{ .server }}"},
+			WriteFiles: []config.TemplateFile{
+				{Path: "/etc/virtwork/{{ .server }}.conf", Content: "port={{ .port }}\n"},
+			},
+			Commands: []string{"systemctl enable --now {{ .server }}"},
+		}
+	})
+
+	It("should return the name it was defined under", func() {
+		w := workloads.NewTemplateWorkload("http", wlCfg, tmpl, nil, "", "", nil)
+		Expect(w.Name()).To(Equal("http"))
+	})
+
+	It("should substitute template values into packages, files, and commands", func() {
+		values := map[string]interface{}{"server": "nginx", "port": 8080}
+		w := workloads.NewTemplateWorkload("http", wlCfg, tmpl, values, "", "", nil)
+
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(parsed["packages"]).To(ConsistOf("nginx"))
+		Expect(fileContent(parsed, "/etc/virtwork/nginx.conf")).To(Equal("port=8080\n"))
+
+		runcmd := parsed["runcmd"].([]interface{})
+		Expect(runcmd).To(ContainElement(
+			[]interface{}{"/bin/sh", "-c", "systemctl enable --now nginx"},
+		))
+	})
+
+	It("should report its rendered packages as required", func() {
+		values := map[string]interface{}{"server": "nginx", "port": 8080}
+		w := workloads.NewTemplateWorkload("http", wlCfg, tmpl, values, "", "", nil)
+		Expect(w.RequiredPackages()).To(Equal([]string{"nginx"}))
+	})
+
+	It("should report unrendered packages when a value is missing", func() {
+		w := workloads.NewTemplateWorkload("http", wlCfg, tmpl, nil, "", "", nil)
+		Expect(w.RequiredPackages()).To(Equal([]string{"{{ .server }}"}))
+	})
+
+	It("should default file permissions to 0644", func() {
+		values := map[string]interface{}{"server": "nginx", "port": 80}
+		w := workloads.NewTemplateWorkload("http", wlCfg, tmpl, values, "", "", nil)
+
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		files := parseYAML(result)["write_files"].([]interface{})
+		Expect(files[0].(map[string]interface{})["permissions"]).To(Equal("0644"))
+	})
+
+	It("should return an error when a referenced value is missing", func() {
+		values := map[string]interface{}{"server": "nginx"}
+		w := workloads.NewTemplateWorkload("http", wlCfg, tmpl, values, "", "", nil)
+
+		_, err := w.CloudInitUserdata()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("write-files[0].content"))
+		Expect(err.Error()).To(ContainSubstring(`"http"`))
+	})
+
+	It("should return an error for malformed template syntax", func() {
+		tmpl.Commands = []string{"echo {{ .server"}
+		values := map[string]interface{}{"server": "nginx", "port": 80}
+		w := workloads.NewTemplateWorkload("http", wlCfg, tmpl, values, "", "", nil)
+
+		_, err := w.CloudInitUserdata()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("parsing commands[0]"))
+	})
+})
+
+var _ = Describe("Registry.RegisterTemplates", func() {
+	var reg workloads.Registry
+
+	BeforeEach(func() {
+		reg = workloads.DefaultRegistry()
+	})
+
+	It("should register config-defined workloads by name", func() {
+		templates := map[string]config.WorkloadTemplate{
+			"http": {Commands: []string{"echo {{ .greeting }}"}},
+		}
+		Expect(reg.RegisterTemplates(templates, map[string]interface{}{"greeting": "hi"})).To(Succeed())
+		Expect(reg.List()).To(ContainElement("http"))
+
+		w, err := reg.Get("http", config.WorkloadConfig{VMCount: 1}, workloads.WithSSHCredentials("user", "", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Name()).To(Equal("http"))
+
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(ContainSubstring("echo hi"))
+	})
+
+	It("should reject a template that shadows a built-in workload", func() {
+		templates := map[string]config.WorkloadTemplate{"cpu": {}}
+		err := reg.RegisterTemplates(templates, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`"cpu"`))
+	})
+})
@@ -0,0 +1,162 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+)
+
+// Workload readiness probe timing. The probe fails until cloud-init has
+// installed and started the workload, which takes minutes, so a short period
+// only costs a cheap guest-agent call.
+const (
+	probePeriodSeconds  = 10
+	probeTimeoutSeconds = 5
+)
+
+// defaultRestartSeconds is how long systemd waits before restarting a
+// workload service that exited.
+const defaultRestartSeconds = 10
+
+// serviceUnit describes the systemd service that drives a workload inside the
+// guest. Workloads describe their unit with this struct rather than a literal
+// unit file so that cross-cutting behaviour (such as warmup) is rendered the
+// same way for every workload.
+type serviceUnit struct {
+	// Name is the workload name; the unit is installed as virtwork-<Name>.service.
+	Name         string
+	Description  string
+	After        []string
+	Requires     []string
+	User         string
+	ExecStartPre string
+	ExecStart    string
+	// Warmup, when positive, runs ExecStart for this long with its output
+	// discarded before the measured loop starts.
+	Warmup time.Duration
+	// StartDelay, when positive, sleeps in an ExecStartPre before the
+	// workload starts. The start timeout is disabled so a delay longer than
+	// systemd's default of 90s does not fail the unit.
+	StartDelay time.Duration
+	// RestartSec is how long systemd waits before restarting the service.
+	// Zero uses defaultRestartSeconds.
+	RestartSec int
+}
+
+// unitPath returns the path the systemd unit file is written to.
+func (u serviceUnit) unitPath() string {
+	return fmt.Sprintf("/etc/systemd/system/virtwork-%s.service", u.Name)
+}
+
+// driverPath returns the path of the driver script used when a warmup is set.
+func (u serviceUnit) driverPath() string {
+	return fmt.Sprintf("/usr/local/bin/virtwork-%s-driver.sh", u.Name)
+}
+
+// serviceName returns the systemd service name for use in runcmd entries.
+func (u serviceUnit) serviceName() string {
+	return fmt.Sprintf("virtwork-%s.service", u.Name)
+}
+
+// readinessProbe returns a probe that succeeds once the unit is active. It runs
+// systemctl in the guest through the QEMU guest agent, so the unit only counts
+// as started after any stagger delay and ExecStartPre steps have finished.
+func (u serviceUnit) readinessProbe() *kubevirtv1.Probe {
+	return &kubevirtv1.Probe{
+		Handler: kubevirtv1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"systemctl", "is-active", "--quiet", u.serviceName()},
+			},
+		},
+		PeriodSeconds:  probePeriodSeconds,
+		TimeoutSeconds: probeTimeoutSeconds,
+	}
+}
+
+// writeFiles returns the cloud-init write_files entries for the unit and,
+// when a warmup is configured, the driver script it executes.
+func (u serviceUnit) writeFiles() []WriteFile {
+	files := []WriteFile{}
+	if u.Warmup > 0 {
+		files = append(files, WriteFile{
+			Path:        u.driverPath(),
+			Content:     u.driverScript(),
+			Permissions: "0755",
+		})
+	}
+	files = append(files, WriteFile{
+		Path:        u.unitPath(),
+		Content:     u.render(),
+		Permissions: "0644",
+	})
+	return files
+}
+
+// render produces the systemd unit file text.
+func (u serviceUnit) render() string {
+	after := u.After
+	if len(after) == 0 {
+		after = []string{"network.target"}
+	}
+
+	execStart := u.ExecStart
+	if u.Warmup > 0 {
+		execStart = u.driverPath()
+	}
+
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", u.Description)
+	fmt.Fprintf(&b, "After=%s\n", strings.Join(after, " "))
+	if len(u.Requires) > 0 {
+		fmt.Fprintf(&b, "Requires=%s\n", strings.Join(u.Requires, " "))
+	}
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=simple\n")
+	if u.User != "" {
+		fmt.Fprintf(&b, "User=%s\n", u.User)
+	}
+	if u.StartDelay > 0 {
+		fmt.Fprintf(&b, "ExecStartPre=/bin/sleep %d\n", int(math.Ceil(u.StartDelay.Seconds())))
+		b.WriteString("TimeoutStartSec=infinity\n")
+	}
+	if u.ExecStartPre != "" {
+		fmt.Fprintf(&b, "ExecStartPre=%s\n", u.ExecStartPre)
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", execStart)
+	b.WriteString("Restart=always\n")
+	restartSec := u.RestartSec
+	if restartSec <= 0 {
+		restartSec = defaultRestartSeconds
+	}
+	fmt.Fprintf(&b, "RestartSec=%d\n", restartSec)
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=multi-user.target\n")
+	return b.String()
+}
+
+// driverScript renders the bash script that runs the warmup stage once per
+// boot and then execs the measured command. The marker lives in /tmp so a
+// reboot warms up again but a service restart does not.
+func (u serviceUnit) driverScript() string {
+	seconds := int(math.Ceil(u.Warmup.Seconds()))
+	return fmt.Sprintf(`#!/bin/bash
+# Warmup: run the workload for %[1]ds with its output discarded so that
+# measurement only starts once the guest has reached steady state.
+MARKER="/tmp/virtwork-%[2]s.warmup-done"
+if [ ! -f "${MARKER}" ]; then
+    timeout %[1]d %[3]s >/dev/null 2>&1 || true
+    touch "${MARKER}"
+fi
+
+# Measured loop
+exec %[3]s
+`, seconds, u.Name, u.ExecStart)
+}
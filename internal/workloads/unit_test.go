@@ -0,0 +1,234 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+// fileContent returns the content of the write_files entry at path, or "" if absent.
+func fileContent(parsed map[string]interface{}, path string) string {
+	files, _ := parsed["write_files"].([]interface{})
+	for _, f := range files {
+		file := f.(map[string]interface{})
+		if file["path"].(string) == path {
+			return file["content"].(string)
+		}
+	}
+	return ""
+}
+
+var _ = Describe("Workload warmup", func() {
+	var (
+		reg   workloads.Registry
+		wlCfg config.WorkloadConfig
+	)
+
+	BeforeEach(func() {
+		reg = workloads.DefaultRegistry()
+		wlCfg = config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  1,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}
+	})
+
+	Context("when no warmup is configured", func() {
+		It("should run the workload command directly from the unit", func() {
+			w, err := reg.Get("cpu", wlCfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			unit := fileContent(parsed, "/etc/systemd/system/virtwork-cpu.service")
+			Expect(unit).To(ContainSubstring("ExecStart=/usr/bin/stress-ng --cpu 0"))
+			Expect(fileContent(parsed, "/usr/local/bin/virtwork-cpu-driver.sh")).To(BeEmpty())
+		})
+	})
+
+	Context("when a warmup is configured", func() {
+		It("should write a driver script with a warmup stage of the configured length", func() {
+			w, err := reg.Get("cpu", wlCfg, workloads.WithWarmup(5*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			driver := fileContent(parsed, "/usr/local/bin/virtwork-cpu-driver.sh")
+			Expect(driver).To(HavePrefix("#!/bin/bash\n"))
+			Expect(driver).To(ContainSubstring("timeout 300 /usr/bin/stress-ng --cpu 0"))
+			Expect(driver).To(ContainSubstring(">/dev/null 2>&1"))
+		})
+
+		It("should place the warmup stage before the measured loop", func() {
+			w, err := reg.Get("cpu", wlCfg, workloads.WithWarmup(90*time.Second))
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			driver := fileContent(parseYAML(result), "/usr/local/bin/virtwork-cpu-driver.sh")
+			warmupIdx := strings.Index(driver, "timeout 90 ")
+			execIdx := strings.Index(driver, "exec /usr/bin/stress-ng")
+			Expect(warmupIdx).To(BeNumerically(">=", 0))
+			Expect(execIdx).To(BeNumerically(">", warmupIdx))
+		})
+
+		It("should point the systemd unit at the driver script", func() {
+			w, err := reg.Get("memory", wlCfg, workloads.WithWarmup(time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			unit := fileContent(parseYAML(result), "/etc/systemd/system/virtwork-memory.service")
+			Expect(unit).To(ContainSubstring("ExecStart=/usr/local/bin/virtwork-memory-driver.sh"))
+		})
+
+		It("should round sub-second warmups up to whole seconds", func() {
+			w, err := reg.Get("cpu", wlCfg, workloads.WithWarmup(1500*time.Millisecond))
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			driver := fileContent(parseYAML(result), "/usr/local/bin/virtwork-cpu-driver.sh")
+			Expect(driver).To(ContainSubstring("timeout 2 "))
+		})
+
+		It("should warm up the pgbench loop while keeping the setup step", func() {
+			w, err := reg.Get("database", wlCfg, workloads.WithWarmup(2*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := w.CloudInitUserdata()
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			unit := fileContent(parsed, "/etc/systemd/system/virtwork-database.service")
+			Expect(unit).To(ContainSubstring("ExecStartPre=/usr/local/bin/virtwork-db-setup.sh"))
+			Expect(unit).To(ContainSubstring("ExecStart=/usr/local/bin/virtwork-database-driver.sh"))
+
+			driver := fileContent(parsed, "/usr/local/bin/virtwork-database-driver.sh")
+			Expect(driver).To(ContainSubstring("timeout 120 /bin/bash -c 'while true; do pgbench"))
+		})
+
+		It("should warm up network clients but not servers", func() {
+			w, err := reg.Get("network", wlCfg,
+				workloads.WithNamespace("virtwork"),
+				workloads.WithWarmup(time.Minute),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			multiVM := w.(workloads.MultiVMWorkload)
+
+			server, err := multiVM.UserdataForRole("server", "virtwork")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fileContent(parseYAML(server), "/usr/local/bin/virtwork-network-driver.sh")).To(BeEmpty())
+
+			client, err := multiVM.UserdataForRole("client", "virtwork")
+			Expect(err).NotTo(HaveOccurred())
+			driver := fileContent(parseYAML(client), "/usr/local/bin/virtwork-network-driver.sh")
+			Expect(driver).To(ContainSubstring("timeout 60 /bin/bash -c 'while true; do iperf3 -c"))
+		})
+	})
+})
+
+var _ = Describe("Workload stagger", func() {
+	var (
+		reg   workloads.Registry
+		wlCfg config.WorkloadConfig
+	)
+
+	BeforeEach(func() {
+		reg = workloads.DefaultRegistry()
+		wlCfg = config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  4,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}
+	})
+
+	// unitFor returns the cpu unit file generated for the VM at index.
+	unitFor := func(w workloads.Workload, index int) string {
+		workloads.SetVMIndex(w, index)
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+		return fileContent(parseYAML(result), "/etc/systemd/system/virtwork-cpu.service")
+	}
+
+	It("should not delay the start when no stagger is configured", func() {
+		w, err := reg.Get("cpu", wlCfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		unit := unitFor(w, 3)
+		Expect(unit).NotTo(ContainSubstring("ExecStartPre="))
+		Expect(unit).NotTo(ContainSubstring("TimeoutStartSec"))
+	})
+
+	It("should scale the delay with the VM index across the window", func() {
+		w, err := reg.Get("cpu", wlCfg, workloads.WithStagger(2*time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(unitFor(w, 0)).NotTo(ContainSubstring("ExecStartPre="))
+		Expect(unitFor(w, 1)).To(ContainSubstring("ExecStartPre=/bin/sleep 30\n"))
+		Expect(unitFor(w, 2)).To(ContainSubstring("ExecStartPre=/bin/sleep 60\n"))
+		Expect(unitFor(w, 3)).To(ContainSubstring("ExecStartPre=/bin/sleep 90\n"))
+	})
+
+	It("should disable the start timeout so long delays do not fail the unit", func() {
+		w, err := reg.Get("cpu", wlCfg, workloads.WithStagger(10*time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(unitFor(w, 3)).To(ContainSubstring("TimeoutStartSec=infinity\n"))
+	})
+
+	It("should sleep before the workload's own ExecStartPre", func() {
+		w, err := reg.Get("database", wlCfg, workloads.WithStagger(time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+
+		workloads.SetVMIndex(w, 2)
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+
+		unit := fileContent(parseYAML(result), "/etc/systemd/system/virtwork-database.service")
+		sleepIdx := strings.Index(unit, "ExecStartPre=/bin/sleep 30\n")
+		setupIdx := strings.Index(unit, "ExecStartPre=/usr/local/bin/virtwork-db-setup.sh")
+		Expect(sleepIdx).To(BeNumerically(">=", 0))
+		Expect(setupIdx).To(BeNumerically(">", sleepIdx))
+	})
+
+	It("should stagger network clients but never delay servers", func() {
+		wlCfg.VMCount = 1
+		w, err := reg.Get("network", wlCfg,
+			workloads.WithNamespace("virtwork"),
+			workloads.WithClientsPerServer(3),
+			workloads.WithStagger(time.Minute),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		multiVM := w.(workloads.MultiVMWorkload)
+
+		workloads.SetVMIndex(w, 0)
+		server, err := multiVM.UserdataForRole("server", "virtwork")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fileContent(parseYAML(server), "/etc/systemd/system/virtwork-network.service")).
+			NotTo(ContainSubstring("/bin/sleep"))
+
+		workloads.SetVMIndex(w, 3)
+		client, err := multiVM.UserdataForRole("client", "virtwork")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fileContent(parseYAML(client), "/etc/systemd/system/virtwork-network.service")).
+			To(ContainSubstring("ExecStartPre=/bin/sleep 45\n"))
+	})
+})
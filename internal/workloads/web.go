@@ -0,0 +1,169 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/opdev/virtwork/internal/config"
+)
+
+const (
+	// webPort is the port nginx listens on and the Service exposes.
+	webPort = 80
+	// webServerCommand runs nginx in the foreground so that the workload unit,
+	// rather than nginx.service, owns the server process.
+	webServerCommand = `/usr/sbin/nginx -g 'daemon off;'`
+	// webServerPackage and webClientPackage are the packages the server and
+	// client roles install.
+	webServerPackage = "nginx"
+	webClientPackage = "wrk"
+)
+
+// WebWorkload generates cloud-init userdata for an HTTP load benchmark. It
+// creates server VMs running nginx and client VMs that run continuous wrk
+// load tests against the servers via DNS. A K8s Service on port 80 routes
+// traffic to the server VMs.
+type WebWorkload struct {
+	BaseWorkload
+	Namespace string
+}
+
+// NewWebWorkload creates a WebWorkload with the given configuration,
+// namespace, and SSH credentials.
+func NewWebWorkload(cfg config.WorkloadConfig, namespace, sshUser, sshPassword string, sshKeys []string) *WebWorkload {
+	return &WebWorkload{
+		BaseWorkload: BaseWorkload{
+			Config:            cfg,
+			SSHUser:           sshUser,
+			SSHPassword:       sshPassword,
+			SSHAuthorizedKeys: sshKeys,
+		},
+		Namespace: namespace,
+	}
+}
+
+// Name returns "web".
+func (w *WebWorkload) Name() string {
+	return "web"
+}
+
+// RequiredPackages returns nginx for the server role and wrk for the client
+// role.
+func (w *WebWorkload) RequiredPackages() []string {
+	return []string{webServerPackage, webClientPackage}
+}
+
+// VMCount returns the total VM count — one server and one client per
+// configured vm-count.
+func (w *WebWorkload) VMCount() int {
+	return 2 * w.servers()
+}
+
+// RoleCounts returns the number of server and client VMs.
+func (w *WebWorkload) RoleCounts() []RoleCount {
+	return []RoleCount{
+		{Role: "server", Count: w.servers()},
+		{Role: "client", Count: w.servers()},
+	}
+}
+
+// servers returns the configured number of server VMs (at least one).
+func (w *WebWorkload) servers() int {
+	if w.Config.VMCount < 1 {
+		return 1
+	}
+	return w.Config.VMCount
+}
+
+// RequiresService returns true — the client needs a ClusterIP Service to reach
+// the server by DNS.
+func (w *WebWorkload) RequiresService() bool {
+	return true
+}
+
+// ServiceSpec returns a ClusterIP Service on port 80 targeting the web server
+// VMs by their component and virtwork/role: server labels.
+func (w *WebWorkload) ServiceSpec() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "virtwork-web-server",
+			Namespace: w.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       "virtwork",
+				"app.kubernetes.io/managed-by": "virtwork",
+				"app.kubernetes.io/component":  "web",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"app.kubernetes.io/component": "web",
+				"virtwork/role":               "server",
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       webPort,
+					TargetPort: intstr.FromInt32(webPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// CloudInitUserdata returns the server role userdata as the default.
+func (w *WebWorkload) CloudInitUserdata() (string, error) {
+	return w.UserdataForRole("server", w.Namespace)
+}
+
+// ReadinessProbe checks that the nginx or wrk service is active.
+func (w *WebWorkload) ReadinessProbe(string) *kubevirtv1.Probe {
+	return serviceUnit{Name: "web"}.readinessProbe()
+}
+
+// UserdataForRole returns cloud-init YAML for the given role ("server" or "client").
+// The server runs nginx. The client runs wrk against the server's DNS name.
+func (w *WebWorkload) UserdataForRole(role string, namespace string) (string, error) {
+	switch role {
+	case "server":
+		unit := serviceUnit{
+			Name:        "web",
+			Description: "Virtwork nginx server",
+			ExecStart:   webServerCommand,
+			RestartSec:  w.restartSeconds(),
+		}
+		return w.buildUserdata(webServerPackage, unit)
+	case "client":
+		url := fmt.Sprintf("http://virtwork-web-server.%s.svc.cluster.local:%d/", namespace, webPort)
+		unit := serviceUnit{
+			Name:        "web",
+			Description: "Virtwork wrk HTTP load client",
+			ExecStart: fmt.Sprintf("/bin/bash -c 'while true; do wrk -t 2 -c 50 -d %ds --latency %s; sleep %d; done'",
+				w.runDurationSeconds(defaultRunDurationSeconds), url, w.loopSleepSeconds()),
+			Warmup:     w.Warmup,
+			StartDelay: w.startDelay,
+			RestartSec: w.restartSeconds(),
+		}
+		return w.buildUserdata(webClientPackage, unit)
+	default:
+		return "", fmt.Errorf("unknown web workload role: %q (expected \"server\" or \"client\")", role)
+	}
+}
+
+func (w *WebWorkload) buildUserdata(pkg string, unit serviceUnit) (string, error) {
+	return w.BuildCloudConfig(CloudConfigOpts{
+		Packages:   []string{pkg},
+		WriteFiles: unit.writeFiles(),
+		RunCmd: [][]string{
+			{"systemctl", "daemon-reload"},
+			{"systemctl", "enable", "--now", unit.serviceName()},
+		},
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Red Hat
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/opdev/virtwork/internal/config"
+	"github.com/opdev/virtwork/internal/workloads"
+)
+
+var _ = Describe("WebWorkload", func() {
+	var w *workloads.WebWorkload
+
+	BeforeEach(func() {
+		w = workloads.NewWebWorkload(config.WorkloadConfig{
+			Enabled:  true,
+			VMCount:  2,
+			CPUCores: 2,
+			Memory:   "2Gi",
+		}, "virtwork", "virtwork", "", nil)
+	})
+
+	It("should return 'web' for Name", func() {
+		Expect(w.Name()).To(Equal("web"))
+	})
+
+	It("should create a server and a client per vm-count", func() {
+		Expect(w.VMCount()).To(Equal(4))
+		Expect(w.RoleCounts()).To(Equal([]workloads.RoleCount{
+			{Role: "server", Count: 2},
+			{Role: "client", Count: 2},
+		}))
+	})
+
+	It("should require service", func() {
+		Expect(w.RequiresService()).To(BeTrue())
+	})
+
+	It("should expose port 80 rather than the iperf3 port", func() {
+		svc := w.ServiceSpec()
+		Expect(svc).NotTo(BeNil())
+		Expect(svc.Spec.Ports).To(HaveLen(1))
+		Expect(svc.Spec.Ports[0].Port).To(Equal(int32(80)))
+		Expect(svc.Spec.Ports[0].Port).NotTo(Equal(int32(5201)))
+		Expect(svc.Spec.Ports[0].TargetPort.IntValue()).To(Equal(80))
+		Expect(svc.Spec.Ports[0].Protocol).To(Equal(corev1.ProtocolTCP))
+	})
+
+	It("should select only the web server VMs", func() {
+		svc := w.ServiceSpec()
+		Expect(svc.Name).To(Equal("virtwork-web-server"))
+		Expect(svc.Namespace).To(Equal("virtwork"))
+		Expect(svc.Spec.Selector).To(Equal(map[string]string{
+			"app.kubernetes.io/component": "web",
+			"virtwork/role":               "server",
+		}))
+	})
+
+	It("should run nginx in the foreground on servers", func() {
+		result, err := w.UserdataForRole("server", "virtwork")
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(parsed["packages"]).To(ConsistOf("nginx"))
+		service := fileContent(parsed, "/etc/systemd/system/virtwork-web.service")
+		Expect(service).To(ContainSubstring("ExecStart=/usr/sbin/nginx -g 'daemon off;'"))
+		Expect(service).NotTo(ContainSubstring("wrk"))
+	})
+
+	It("should loop wrk against the server's DNS name on clients", func() {
+		result, err := w.UserdataForRole("client", "virtwork")
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := parseYAML(result)
+		Expect(parsed["packages"]).To(ConsistOf("wrk"))
+		service := fileContent(parsed, "/etc/systemd/system/virtwork-web.service")
+		Expect(service).To(ContainSubstring("while true; do wrk "))
+		Expect(service).To(ContainSubstring("http://virtwork-web-server.virtwork.svc.cluster.local:80/"))
+		Expect(service).NotTo(ContainSubstring("nginx"))
+	})
+
+	It("should use the given namespace in the client's DNS name", func() {
+		result, err := w.UserdataForRole("client", "custom-ns")
+		Expect(err).NotTo(HaveOccurred())
+
+		service := fileContent(parseYAML(result), "/etc/systemd/system/virtwork-web.service")
+		Expect(service).To(ContainSubstring("virtwork-web-server.custom-ns.svc.cluster.local"))
+	})
+
+	It("should return an error for an unknown role", func() {
+		_, err := w.UserdataForRole("proxy", "virtwork")
+		Expect(err).To(MatchError(ContainSubstring(`unknown web workload role: "proxy"`)))
+	})
+
+	It("should default CloudInitUserdata to the server role", func() {
+		result, err := w.CloudInitUserdata()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parseYAML(result)["packages"]).To(ConsistOf("nginx"))
+	})
+
+	It("should provide a readiness probe checking the workload service", func() {
+		var p workloads.Prober = w
+		for _, role := range []string{"server", "client"} {
+			probe := p.ReadinessProbe(role)
+			Expect(probe.Exec.Command).To(Equal([]string{"systemctl", "is-active", "--quiet", "virtwork-web.service"}))
+		}
+	})
+
+	It("should produce valid YAML for both roles", func() {
+		for _, role := range []string{"server", "client"} {
+			result, err := w.UserdataForRole(role, "virtwork")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HavePrefix("#cloud-config\n"))
+			Expect(parseYAML(result)).NotTo(BeNil())
+		}
+	})
+})
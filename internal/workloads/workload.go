@@ -4,7 +4,14 @@
 package workloads
 
 import (
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 
 	"github.com/opdev/virtwork/internal/cloudinit"
@@ -17,6 +24,12 @@ type CloudConfigOpts = cloudinit.CloudConfigOpts
 // WriteFile is re-exported from cloudinit for convenience.
 type WriteFile = cloudinit.WriteFile
 
+// FSSetup is re-exported from cloudinit for convenience.
+type FSSetup = cloudinit.FSSetup
+
+// PowerState is re-exported from cloudinit for convenience.
+type PowerState = cloudinit.PowerState
+
 // Workload defines the contract for all workload types.
 // Implementations are pure data producers — no I/O, no goroutines.
 type Workload interface {
@@ -26,6 +39,10 @@ type Workload interface {
 	// CloudInitUserdata returns the cloud-init YAML for this workload.
 	CloudInitUserdata() (string, error)
 
+	// RequiredPackages returns the guest packages the workload's cloud-init
+	// installs, across all of its roles. Returns nil if none needed.
+	RequiredPackages() []string
+
 	// VMResources returns the CPU and memory requirements for each VM.
 	VMResources() VMResourceSpec
 
@@ -52,11 +69,63 @@ type Workload interface {
 }
 
 // MultiVMWorkload extends Workload for workloads that need per-role userdata.
-// The orchestration layer checks VMCount() > 1 and type-asserts to this
-// interface to call UserdataForRole() for each VM role.
+// The orchestration layer type-asserts to this interface, creates
+// RoleCounts() VMs for each role, and calls UserdataForRole() for each.
 type MultiVMWorkload interface {
 	Workload
 	UserdataForRole(role string, namespace string) (string, error)
+	// RoleCounts returns how many VMs to create for each role, in creation
+	// order. The counts sum to VMCount().
+	RoleCounts() []RoleCount
+}
+
+// Prober is implemented by workloads that can tell when their workload has
+// started inside a VM. The orchestration layer type-asserts to this interface
+// and, when workload probes are enabled, sets the returned probe as the VMI's
+// readiness probe so that its Ready condition means the workload is running.
+// role is the MultiVMWorkload role of the VM, or "" for single-role workloads.
+type Prober interface {
+	ReadinessProbe(role string) *kubevirtv1.Probe
+}
+
+// APIClient is implemented by workloads whose guests call the Kubernetes API.
+// The orchestration layer type-asserts to this interface, creates a
+// ServiceAccount of that name bound to a Role with the returned rules, and
+// attaches the account's token to each of the workload's VMs as a disk.
+type APIClient interface {
+	ServiceAccountName() string
+	PolicyRules() []rbacv1.PolicyRule
+}
+
+// NetworkPolicyProvider is implemented by workloads that can confine the
+// traffic their VMs accept. The orchestration layer type-asserts to this
+// interface and creates the returned NetworkPolicy alongside the workload's
+// Service. A nil policy means none is wanted.
+type NetworkPolicyProvider interface {
+	NetworkPolicySpec() *networkingv1.NetworkPolicy
+}
+
+// FilesystemUser is implemented by workloads that can share a filesystem,
+// such as a virtiofs-exported PVC, with their VMs. The orchestration layer
+// type-asserts to this interface and adds the returned devices to each VM's
+// spec; the volumes backing them are returned by ExtraVolumes.
+type FilesystemUser interface {
+	Filesystems() []kubevirtv1.Filesystem
+}
+
+// RootWriter is implemented by workloads that keep data on the guest's root
+// disk rather than on a data disk. The orchestration layer type-asserts to
+// this interface and refuses to run them with a read-only root, under which
+// their data would only reach the in-memory overlay and be lost on reboot.
+type RootWriter interface {
+	// RootWrites returns the root disk paths the workload writes data to.
+	RootWrites() []string
+}
+
+// RoleCount is the number of VMs a MultiVMWorkload needs in one role.
+type RoleCount struct {
+	Role  string
+	Count int
 }
 
 // VMResourceSpec holds CPU and memory requirements for a VM.
@@ -72,6 +141,84 @@ type BaseWorkload struct {
 	SSHUser           string
 	SSHPassword       string
 	SSHAuthorizedKeys []string
+	// Warmup is how long each workload driver runs, with results discarded,
+	// before its measured loop starts. Zero disables the warmup stage.
+	Warmup time.Duration
+	// Stagger spreads the start of the workload over this window across its
+	// VMs. Zero starts every VM's workload as soon as it boots.
+	Stagger time.Duration
+	// SyslogServer is the host:port of a syslog server the guest forwards
+	// its logs to over TCP. Empty leaves forwarding off.
+	SyslogServer string
+	// NodeExporter installs and starts node_exporter so that a monitor VM
+	// can scrape the guest's metrics.
+	NodeExporter bool
+	// ReadOnlyRoot boots the guest with its root filesystem read-only under
+	// an ephemeral overlay, so that only data disks keep what is written.
+	ReadOnlyRoot bool
+	// Overlay is an operator-supplied cloud-config merged into the
+	// workload's with cloudinit.MergeOverlay; nil leaves it unchanged.
+	Overlay *CloudConfigOpts
+	// OverlayOverride lets Overlay's scalar values replace the workload's.
+	OverlayOverride bool
+	// startDelay is the stagger delay of the VM whose userdata is generated
+	// next; see SetVMIndex.
+	startDelay time.Duration
+}
+
+// base returns the embedded BaseWorkload so the registry can apply options
+// shared by every workload after construction.
+func (b *BaseWorkload) base() *BaseWorkload {
+	return b
+}
+
+// SetVMIndex selects which of w's VMs, numbered from zero across all roles,
+// the next generated userdata is for. With a stagger set, VM index i of n
+// delays its workload start by Stagger*i/n, so starts are spread evenly over
+// the window. Workloads that do not embed BaseWorkload are unaffected.
+func SetVMIndex(w Workload, index int) {
+	b, ok := w.(interface{ base() *BaseWorkload })
+	if !ok {
+		return
+	}
+	base := b.base()
+	base.startDelay = 0
+	if count := w.VMCount(); base.Stagger > 0 && count > 0 {
+		base.startDelay = base.Stagger * time.Duration(index) / time.Duration(count)
+	}
+}
+
+// Benchmark loop timing used when the workload config leaves it unset.
+const (
+	defaultLoopSleepSeconds   = 10
+	defaultRunDurationSeconds = 300
+)
+
+// restartSeconds returns how long systemd waits before restarting the
+// workload's service.
+func (b *BaseWorkload) restartSeconds() int {
+	if b.Config.RestartSeconds > 0 {
+		return b.Config.RestartSeconds
+	}
+	return defaultRestartSeconds
+}
+
+// loopSleepSeconds returns the pause between iterations of the workload's
+// benchmark loop.
+func (b *BaseWorkload) loopSleepSeconds() int {
+	if b.Config.LoopSleepSeconds > 0 {
+		return b.Config.LoopSleepSeconds
+	}
+	return defaultLoopSleepSeconds
+}
+
+// runDurationSeconds returns how long each iteration of the workload's
+// benchmark loop runs, or def when the config leaves it unset.
+func (b *BaseWorkload) runDurationSeconds(def int) int {
+	if b.Config.RunDurationSeconds > 0 {
+		return b.Config.RunDurationSeconds
+	}
+	return def
 }
 
 // VMResources returns the CPU and memory spec from the workload config.
@@ -82,6 +229,11 @@ func (b *BaseWorkload) VMResources() VMResourceSpec {
 	}
 }
 
+// RequiredPackages returns nil — no guest packages by default.
+func (b *BaseWorkload) RequiredPackages() []string {
+	return nil
+}
+
 // ExtraVolumes returns nil — no additional volumes by default.
 func (b *BaseWorkload) ExtraVolumes() []kubevirtv1.Volume {
 	return nil
@@ -115,12 +267,112 @@ func (b *BaseWorkload) VMCount() int {
 	return b.Config.VMCount
 }
 
+// syslogForwardPath is the rsyslog drop-in that forwards guest logs when a
+// syslog server is set.
+const syslogForwardPath = "/etc/rsyslog.d/90-virtwork-forward.conf"
+
+// syslogForwardConfig returns an rsyslog drop-in forwarding every message,
+// including the journal that rsyslog reads on Fedora, to addr over TCP.
+// Messages are queued in memory while the server is unreachable.
+func syslogForwardConfig(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("parsing syslog server %q: %w", addr, err)
+	}
+	return fmt.Sprintf(`# Managed by virtwork: forward all guest logs to %s.
+*.* action(type="omfwd" target="%s" port="%s" protocol="tcp"
+           queue.type="LinkedList" queue.size="10000"
+           action.resumeRetryCount="-1")
+`, addr, host, port), nil
+}
+
+// nodeExporterPackage and nodeExporterService are the Fedora package and
+// systemd unit of node_exporter.
+const (
+	nodeExporterPackage = "golang-github-prometheus-node-exporter"
+	nodeExporterService = "prometheus-node-exporter.service"
+)
+
+// readOnlyRootKernelArg mounts the root filesystem read-only and layers a
+// tmpfs over it, so writes to root succeed but are discarded at shutdown.
+const readOnlyRootKernelArg = "systemd.volatile=overlay"
+
 // BuildCloudConfig injects SSH credentials into the given options and delegates
 // to cloudinit.BuildCloudConfig. Workloads should call this instead of the
-// package-level function to ensure consistent SSH credential handling.
+// package-level function to ensure consistent SSH credential handling. An empty
+// FinalMessage defaults to cloudinit.CompletionSentinel so completion can be
+// detected the same way for every workload. With a SyslogServer set, rsyslog is
+// installed and configured to forward the guest's logs before the workload's
+// commands run. With NodeExporter set, node_exporter is installed and started
+// the same way. With ReadOnlyRoot set, the kernel is given
+// readOnlyRootKernelArg and the guest reboots once cloud-init is done, unless
+// the workload already reboots for arguments of its own. The config's
+// ExtraWriteFiles are appended after the workload's own files; one that would
+// replace a workload file is an error. An Overlay is merged in last, after the
+// SSH credentials and final message are set.
 func (b *BaseWorkload) BuildCloudConfig(opts CloudConfigOpts) (string, error) {
+	if b.SyslogServer != "" {
+		conf, err := syslogForwardConfig(b.SyslogServer)
+		if err != nil {
+			return "", err
+		}
+		if !slices.Contains(opts.Packages, "rsyslog") {
+			opts.Packages = append(slices.Clone(opts.Packages), "rsyslog")
+		}
+		opts.WriteFiles = append(slices.Clone(opts.WriteFiles), WriteFile{
+			Path:        syslogForwardPath,
+			Content:     conf,
+			Permissions: "0644",
+		})
+		opts.RunCmd = append([][]string{
+			{"systemctl", "enable", "rsyslog"},
+			{"systemctl", "restart", "rsyslog"},
+		}, opts.RunCmd...)
+	}
+	if b.NodeExporter {
+		if !slices.Contains(opts.Packages, nodeExporterPackage) {
+			opts.Packages = append(slices.Clone(opts.Packages), nodeExporterPackage)
+		}
+		opts.RunCmd = append([][]string{
+			{"systemctl", "enable", "--now", nodeExporterService},
+		}, opts.RunCmd...)
+	}
+	if b.ReadOnlyRoot {
+		opts.RunCmd = append(slices.Clone(opts.RunCmd),
+			[]string{"grubby", "--update-kernel=ALL", "--args=" + readOnlyRootKernelArg})
+		if opts.PowerState == nil {
+			opts.PowerState = &PowerState{
+				Mode:    "reboot",
+				Message: "Rebooting to mount the root filesystem read-only",
+			}
+		}
+	}
+	if len(b.Config.ExtraWriteFiles) > 0 {
+		own := make(map[string]bool, len(opts.WriteFiles))
+		for _, f := range opts.WriteFiles {
+			own[f.Path] = true
+		}
+		files := slices.Clone(opts.WriteFiles)
+		for _, f := range b.Config.ExtraWriteFiles {
+			if own[f.Path] {
+				return "", fmt.Errorf("extra write file %s would overwrite a file the workload writes", f.Path)
+			}
+			perms := f.Permissions
+			if perms == "" {
+				perms = "0644"
+			}
+			files = append(files, WriteFile{Path: f.Path, Content: f.Content, Permissions: perms})
+		}
+		opts.WriteFiles = files
+	}
 	opts.SSHUser = b.SSHUser
 	opts.SSHPassword = b.SSHPassword
 	opts.SSHAuthorizedKeys = b.SSHAuthorizedKeys
+	if opts.FinalMessage == "" {
+		opts.FinalMessage = cloudinit.CompletionSentinel
+	}
+	if b.Overlay != nil {
+		opts = cloudinit.MergeOverlay(opts, *b.Overlay, b.OverlayOverride)
+	}
 	return cloudinit.BuildCloudConfig(opts)
 }
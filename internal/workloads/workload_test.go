@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/opdev/virtwork/internal/cloudinit"
 	"github.com/opdev/virtwork/internal/config"
 	"github.com/opdev/virtwork/internal/workloads"
 )
@@ -25,6 +26,10 @@ var _ = Describe("BaseWorkload", func() {
 		}
 	})
 
+	It("should return nil for RequiredPackages", func() {
+		Expect(base.RequiredPackages()).To(BeNil())
+	})
+
 	It("should return nil for ExtraVolumes", func() {
 		Expect(base.ExtraVolumes()).To(BeNil())
 	})
@@ -83,5 +88,146 @@ var _ = Describe("BaseWorkload", func() {
 			Expect(parsed).NotTo(HaveKey("users"))
 			Expect(parsed).To(HaveKey("packages"))
 		})
+
+		It("should default final_message to the completion sentinel", func() {
+			result, err := base.BuildCloudConfig(workloads.CloudConfigOpts{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parseYAML(result)["final_message"]).To(Equal(cloudinit.CompletionSentinel))
+			Expect(cloudinit.HasCompleted(result)).To(BeTrue())
+		})
+
+		It("should keep an explicit final_message", func() {
+			result, err := base.BuildCloudConfig(workloads.CloudConfigOpts{FinalMessage: "done"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parseYAML(result)["final_message"]).To(Equal("done"))
+		})
+
+		It("should append extra write files after the workload's own files", func() {
+			base.Config.ExtraWriteFiles = []config.WriteFile{
+				{Path: "/etc/motd", Content: "load test VM"},
+				{Path: "/etc/sysctl.d/99-virtwork.conf", Content: "vm.swappiness = 10\n", Permissions: "0600"},
+			}
+
+			result, err := base.BuildCloudConfig(workloads.CloudConfigOpts{
+				WriteFiles: []workloads.WriteFile{
+					{Path: "/usr/local/bin/run.sh", Content: "#!/bin/bash\n", Permissions: "0755"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			files := parsed["write_files"].([]interface{})
+			Expect(files).To(HaveLen(3))
+			Expect(files[0].(map[string]interface{})["path"]).To(Equal("/usr/local/bin/run.sh"))
+			Expect(fileContent(parsed, "/usr/local/bin/run.sh")).To(Equal("#!/bin/bash\n"))
+			Expect(fileContent(parsed, "/etc/motd")).To(Equal("load test VM"))
+			Expect(files[1].(map[string]interface{})["permissions"]).To(Equal("0644"))
+			Expect(files[2].(map[string]interface{})["permissions"]).To(Equal("0600"))
+		})
+
+		It("should reject an extra write file that replaces a workload file", func() {
+			base.Config.ExtraWriteFiles = []config.WriteFile{
+				{Path: "/usr/local/bin/run.sh", Content: "exit 0\n"},
+			}
+
+			_, err := base.BuildCloudConfig(workloads.CloudConfigOpts{
+				WriteFiles: []workloads.WriteFile{
+					{Path: "/usr/local/bin/run.sh", Content: "#!/bin/bash\n", Permissions: "0755"},
+				},
+			})
+			Expect(err).To(MatchError(ContainSubstring("extra write file /usr/local/bin/run.sh would overwrite")))
+		})
+
+		It("should not configure log forwarding by default", func() {
+			result, err := base.BuildCloudConfig(workloads.CloudConfigOpts{
+				Packages: []string{"stress-ng"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			Expect(parsed["packages"]).NotTo(ContainElement("rsyslog"))
+			Expect(parsed).NotTo(HaveKey("write_files"))
+			Expect(parsed).NotTo(HaveKey("runcmd"))
+		})
+
+		It("should forward guest logs to the syslog server when set", func() {
+			base.SyslogServer = "logs.example.com:6514"
+
+			result, err := base.BuildCloudConfig(workloads.CloudConfigOpts{
+				Packages: []string{"stress-ng"},
+				RunCmd:   [][]string{{"systemctl", "enable", "--now", "virtwork-cpu.service"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			Expect(parsed["packages"]).To(Equal([]interface{}{"stress-ng", "rsyslog"}))
+
+			conf := fileContent(parsed, "/etc/rsyslog.d/90-virtwork-forward.conf")
+			Expect(conf).To(ContainSubstring(`*.* action(type="omfwd" target="logs.example.com" port="6514" protocol="tcp"`))
+
+			Expect(parsed["runcmd"]).To(Equal([]interface{}{
+				[]interface{}{"systemctl", "enable", "rsyslog"},
+				[]interface{}{"systemctl", "restart", "rsyslog"},
+				[]interface{}{"systemctl", "enable", "--now", "virtwork-cpu.service"},
+			}))
+		})
+
+		It("should forward to an IPv6 syslog server", func() {
+			base.SyslogServer = "[fd00::10]:514"
+
+			result, err := base.BuildCloudConfig(workloads.CloudConfigOpts{})
+			Expect(err).NotTo(HaveOccurred())
+
+			conf := fileContent(parseYAML(result), "/etc/rsyslog.d/90-virtwork-forward.conf")
+			Expect(conf).To(ContainSubstring(`target="fd00::10" port="514"`))
+		})
+
+		It("should not list rsyslog twice when the workload installs it", func() {
+			base.SyslogServer = "logs.example.com:514"
+
+			result, err := base.BuildCloudConfig(workloads.CloudConfigOpts{
+				Packages: []string{"rsyslog"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parseYAML(result)["packages"]).To(Equal([]interface{}{"rsyslog"}))
+		})
+
+		It("should boot with a read-only root when set", func() {
+			base.ReadOnlyRoot = true
+
+			result, err := base.BuildCloudConfig(workloads.CloudConfigOpts{
+				RunCmd: [][]string{{"systemctl", "enable", "--now", "virtwork-cpu.service"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed := parseYAML(result)
+			Expect(parsed["runcmd"]).To(Equal([]interface{}{
+				[]interface{}{"systemctl", "enable", "--now", "virtwork-cpu.service"},
+				[]interface{}{"grubby", "--update-kernel=ALL", "--args=systemd.volatile=overlay"},
+			}))
+			Expect(parsed["power_state"]).To(HaveKeyWithValue("mode", "reboot"))
+		})
+
+		It("should keep the workload's own reboot with a read-only root", func() {
+			base.ReadOnlyRoot = true
+
+			result, err := base.BuildCloudConfig(workloads.CloudConfigOpts{
+				PowerState: &workloads.PowerState{Mode: "reboot", Message: "Rebooting to isolate CPUs 1"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parseYAML(result)["power_state"]).To(HaveKeyWithValue("message", "Rebooting to isolate CPUs 1"))
+		})
+
+		It("should reject an extra write file that replaces the forwarding config", func() {
+			base.SyslogServer = "logs.example.com:514"
+			base.Config.ExtraWriteFiles = []config.WriteFile{
+				{Path: "/etc/rsyslog.d/90-virtwork-forward.conf", Content: ""},
+			}
+
+			_, err := base.BuildCloudConfig(workloads.CloudConfigOpts{})
+			Expect(err).To(MatchError(ContainSubstring("would overwrite")))
+		})
 	})
 })